@@ -20,6 +20,14 @@ type MultipleBackendResponse struct {
 	Items []storage.BackendExternal `json:"items"`
 }
 
+type MultipleBackendVolumeReportResponse struct {
+	Items []storage.BackendVolumeReport `json:"items"`
+}
+
+type MultipleOperationResponse struct {
+	Items []storage.OperationExternal `json:"items"`
+}
+
 type StorageClass struct {
 	Config struct {
 		Version         string              `json:"version"`
@@ -0,0 +1,90 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var deleteOrphanConfirm bool
+
+func init() {
+	deleteCmd.AddCommand(deleteOrphanCmd)
+	deleteOrphanCmd.Flags().BoolVar(&deleteOrphanConfirm, "confirm", false,
+		"Confirm that the orphaned volumes and their data should be destroyed")
+}
+
+var deleteOrphanCmd = &cobra.Command{
+	Use:   "orphan <backend> <internal volume name>...",
+	Short: "Delete one or more orphaned volumes from a backend",
+	Long: `Delete one or more orphaned volumes from a backend.
+
+An orphaned volume is a volume on a backend that Trident does not know about, as reported
+by 'tridentctl get drift'.  Volumes are named by their names on the backend, and are only
+deleted if a new drift check finds them all to be orphaned.  Since the volumes' data is
+destroyed, the deletion must be confirmed with --confirm.`,
+	Aliases: []string{"orphans"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"delete", "orphan"}
+			if deleteOrphanConfirm {
+				command = append(command, "--confirm")
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return orphanDelete(args, deleteOrphanConfirm)
+		}
+	},
+}
+
+func orphanDelete(args []string, confirm bool) error {
+
+	switch len(args) {
+	case 0:
+		return errors.New("backend name not specified")
+	case 1:
+		return errors.New("orphaned volume names not specified")
+	}
+	backendName, volumeNames := args[0], args[1:]
+
+	if !confirm {
+		return fmt.Errorf("deleting orphaned volumes %s from backend %s destroys their data; "+
+			"use --confirm to delete them", strings.Join(volumeNames, ", "), backendName)
+	}
+
+	url := BaseURL() + "/drift/" + backendName + "/cleanup"
+
+	requestBytes, err := json.Marshal(storage.OrphanCleanupRequest{Volumes: volumeNames, Confirm: confirm})
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not delete orphaned volumes from backend %s: %v", backendName,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var deleteResponse rest.DeleteOrphanedVolumesResponse
+	if err = json.Unmarshal(responseBody, &deleteResponse); err != nil {
+		return err
+	}
+	if deleteResponse.Report != nil {
+		WriteDriftReport(deleteResponse.Report)
+	}
+
+	return nil
+}
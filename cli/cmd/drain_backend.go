@@ -0,0 +1,89 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var backendDrain storage.BackendDrainRequest
+
+func init() {
+	drainCmd.AddCommand(drainBackendCmd)
+	drainBackendCmd.Flags().IntVarP(&backendDrain.Concurrency, "concurrency", "", 0,
+		fmt.Sprintf("Maximum number of volumes relocated at once (default %d)",
+			storage.DefaultBackendDrainConcurrency))
+}
+
+var drainBackendCmd = &cobra.Command{
+	Use:   "backend <name>",
+	Short: "Relocate the volumes on a backend in maintenance to other backends",
+	Long: `Relocate the volumes on a backend in maintenance to other backends.
+
+Each volume is relocated to another backend that matches its storage class, as with
+'tridentctl relocate volume'.  The volumes are relocated in the background; use
+'tridentctl get backend <name> --volumes' to follow the progress of the drain.`,
+	Aliases: []string{"b"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"drain", "backend"}
+			if backendDrain.Concurrency != 0 {
+				command = append(command, "--concurrency", strconv.Itoa(backendDrain.Concurrency))
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return backendDrainVolumes(args, &backendDrain)
+		}
+	},
+}
+
+func backendDrainVolumes(backendNames []string, drain *storage.BackendDrainRequest) error {
+
+	switch len(backendNames) {
+	case 0:
+		return errors.New("backend name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple backend names specified")
+	}
+
+	// Send the drain request to Trident
+	url := BaseURL() + "/backend/" + backendNames[0] + "/drain"
+
+	requestBytes, err := json.Marshal(drain)
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not drain backend %s: %v", backendNames[0],
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var drainBackendResponse rest.DrainBackendResponse
+	if err = json.Unmarshal(responseBody, &drainBackendResponse); err != nil {
+		return err
+	}
+	if drainBackendResponse.Report == nil {
+		return fmt.Errorf("could not drain backend %s: no report returned", backendNames[0])
+	}
+
+	WriteBackendVolumeReports([]storage.BackendVolumeReport{*drainBackendResponse.Report})
+
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/netapp/trident/cli/api"
 	"github.com/netapp/trident/frontend/rest"
@@ -16,12 +17,17 @@ import (
 	"github.com/netapp/trident/utils"
 	drivers "github.com/netapp/trident/storage_drivers"
 
+	"github.com/dustin/go-humanize"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
+var getBackendVolumes bool
+
 func init() {
 	getCmd.AddCommand(getBackendCmd)
+	getBackendCmd.Flags().BoolVar(&getBackendVolumes, "volumes", false,
+		"List the volumes that reside on each backend")
 }
 
 var getBackendCmd = &cobra.Command{
@@ -31,8 +37,13 @@ var getBackendCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if OperatingMode == ModeTunnel {
 			command := []string{"get", "backend"}
+			if getBackendVolumes {
+				command = append(command, "--volumes")
+			}
 			TunnelCommand(append(command, args...))
 			return nil
+		} else if getBackendVolumes {
+			return backendVolumeReportList(args)
 		} else {
 			return backendList(args)
 		}
@@ -73,6 +84,39 @@ func backendList(backendNames []string) error {
 	return nil
 }
 
+func backendVolumeReportList(backendNames []string) error {
+
+	var err error
+
+	// If no backends were specified, we'll get all of them
+	getAll := false
+	if len(backendNames) == 0 {
+		getAll = true
+		backendNames, err = GetBackends()
+		if err != nil {
+			return err
+		}
+	}
+
+	reports := make([]storage.BackendVolumeReport, 0, 10)
+
+	for _, backendName := range backendNames {
+
+		report, err := GetBackendVolumeReport(backendName)
+		if err != nil {
+			if getAll && utils.IsNotFoundError(err) {
+				continue
+			}
+			return err
+		}
+		reports = append(reports, *report)
+	}
+
+	WriteBackendVolumeReports(reports)
+
+	return nil
+}
+
 func GetBackends() ([]string, error) {
 
 	url := BaseURL() + "/backend"
@@ -142,6 +186,48 @@ func GetBackendByBackendUUID(backendUUID string) (storage.BackendExternal, error
 	return getBackendResponse.Backend, nil
 }
 
+func GetBackendVolumeReport(backendName string) (*storage.BackendVolumeReport, error) {
+
+	url := BaseURL() + "/backend/" + backendName + "/volumes"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		errorMessage := fmt.Sprintf("could not get volumes for backend %s: %v", backendName,
+			GetErrorFromHTTPResponse(response, responseBody))
+		switch response.StatusCode {
+		case http.StatusNotFound:
+			return nil, utils.NotFoundError(errorMessage)
+		default:
+			return nil, errors.New(errorMessage)
+		}
+	}
+
+	var reportResponse rest.GetBackendVolumeReportResponse
+	if err = json.Unmarshal(responseBody, &reportResponse); err != nil {
+		return nil, err
+	}
+	if reportResponse.Report == nil {
+		return nil, fmt.Errorf("could not get volumes for backend %s: no report returned", backendName)
+	}
+
+	return reportResponse.Report, nil
+}
+
+func WriteBackendVolumeReports(reports []storage.BackendVolumeReport) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultipleBackendVolumeReportResponse{Items: reports})
+	case FormatYAML:
+		WriteYAML(api.MultipleBackendVolumeReportResponse{Items: reports})
+	case FormatName:
+		writeBackendVolumeReportNames(reports)
+	default:
+		writeBackendVolumeReportTable(reports)
+	}
+}
+
 func WriteBackends(backends []storage.BackendExternal) {
 	switch OutputFormat {
 	case FormatJSON:
@@ -242,3 +328,51 @@ func writeBackendNames(backends []storage.BackendExternal) {
 		fmt.Println(b.Name)
 	}
 }
+
+func writeBackendVolumeReportTable(reports []storage.BackendVolumeReport) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Backend", "State", "Draining", "Volume", "Pool", "Size", "Storage Class",
+		"Volume State", "Published Nodes", "Snapshots"})
+
+	for _, report := range reports {
+
+		// A backend without volumes still gets a row, so that an emptied backend is apparent
+		if len(report.Volumes) == 0 {
+			table.Append([]string{
+				report.Backend,
+				report.State.String(),
+				strconv.FormatBool(report.Draining),
+				"", "", "", "", "", "", "",
+			})
+			continue
+		}
+
+		for _, volume := range report.Volumes {
+			volumeSize, _ := strconv.ParseUint(volume.Size, 10, 64)
+			table.Append([]string{
+				report.Backend,
+				report.State.String(),
+				strconv.FormatBool(report.Draining),
+				volume.Name,
+				volume.Pool,
+				humanize.IBytes(volumeSize),
+				volume.StorageClass,
+				string(volume.State),
+				strings.Join(volume.PublishedNodes, ","),
+				strconv.Itoa(volume.Snapshots),
+			})
+		}
+	}
+
+	table.Render()
+}
+
+func writeBackendVolumeReportNames(reports []storage.BackendVolumeReport) {
+
+	for _, report := range reports {
+		for _, volume := range report.Volumes {
+			fmt.Println(volume.Name)
+		}
+	}
+}
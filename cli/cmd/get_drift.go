@@ -0,0 +1,164 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var getDriftRefresh bool
+
+func init() {
+	getCmd.AddCommand(getDriftCmd)
+	getDriftCmd.Flags().BoolVar(&getDriftRefresh, "refresh", false,
+		"Check for drift now instead of reporting the last periodic check")
+}
+
+var getDriftCmd = &cobra.Command{
+	Use:   "drift [<backend>...]",
+	Short: "Get the differences between Trident's state and the storage on its backends",
+	Long: `Get the differences between Trident's state and the storage on its backends.
+
+Trident periodically compares its volumes and snapshots with the storage on each backend,
+and reports volumes it does not know about (orphaned volumes), volumes and snapshots that
+are missing from their backends, and volumes whose size or attributes have changed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "drift"}
+			if getDriftRefresh {
+				command = append(command, "--refresh")
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return driftList(args)
+		}
+	},
+}
+
+func driftList(backendNames []string) error {
+
+	report, err := GetDriftReport(getDriftRefresh)
+	if err != nil {
+		return err
+	}
+
+	// Limit the report to the specified backends
+	if len(backendNames) > 0 {
+		backends := make(map[string]bool)
+		for _, backendName := range backendNames {
+			backends[backendName] = true
+		}
+		items := make([]*storage.DriftItem, 0)
+		for _, item := range report.Items {
+			if backends[item.Backend] {
+				items = append(items, item)
+			}
+		}
+		report.Items = items
+		for backendName := range report.Errors {
+			if !backends[backendName] {
+				delete(report.Errors, backendName)
+			}
+		}
+	}
+
+	WriteDriftReport(report)
+
+	return nil
+}
+
+func GetDriftReport(refresh bool) (*storage.DriftReport, error) {
+
+	url := BaseURL() + "/drift"
+	if refresh {
+		url += "?refresh=true"
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get drift report: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var getDriftReportResponse rest.GetDriftReportResponse
+	if err = json.Unmarshal(responseBody, &getDriftReportResponse); err != nil {
+		return nil, err
+	}
+	if getDriftReportResponse.Report == nil {
+		return nil, fmt.Errorf("could not get drift report: no report returned")
+	}
+
+	return getDriftReportResponse.Report, nil
+}
+
+func WriteDriftReport(report *storage.DriftReport) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(report)
+	case FormatYAML:
+		WriteYAML(report)
+	case FormatName:
+		writeDriftItemNames(report)
+	default:
+		writeDriftReportTable(report)
+	}
+}
+
+func writeDriftReportTable(report *storage.DriftReport) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Type", "Backend", "Volume", "Internal Name", "Detail"})
+
+	for _, item := range report.Items {
+		table.Append([]string{
+			item.Type,
+			item.Backend,
+			item.Volume,
+			item.InternalName,
+			driftItemDetail(item),
+		})
+	}
+
+	table.Render()
+
+	backendNames := make([]string, 0, len(report.Errors))
+	for backendName := range report.Errors {
+		backendNames = append(backendNames, backendName)
+	}
+	sort.Strings(backendNames)
+	for _, backendName := range backendNames {
+		fmt.Printf("Could not check backend %s: %s\n", backendName, report.Errors[backendName])
+	}
+}
+
+func driftItemDetail(item *storage.DriftItem) string {
+	switch item.Type {
+	case storage.DriftTypeMissingSnapshot:
+		return "snapshot " + item.Snapshot
+	case storage.DriftTypeSize:
+		return fmt.Sprintf("size %s, expected %s", item.Actual, item.Expected)
+	case storage.DriftTypeAttribute:
+		return fmt.Sprintf("%s %s, expected %s", item.Attribute, item.Actual, item.Expected)
+	default:
+		return ""
+	}
+}
+
+func writeDriftItemNames(report *storage.DriftReport) {
+	for _, item := range report.Items {
+		fmt.Println(item.InternalName)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	getCmd.AddCommand(getOperationCmd)
+}
+
+var getOperationCmd = &cobra.Command{
+	Use:   "operation [<volume name>...]",
+	Short: "Get the operations in progress in Trident",
+	Long: `Get the operations in progress in Trident.
+
+Resizes, snapshot creations, and volume and snapshot deletions record their progress in
+Trident's operation journal.  If Trident restarts or a step fails, such an operation is
+resumed from the step it reached, and the step is retried with a backoff.`,
+	Aliases: []string{"op", "ops", "operations"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "operation"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return operationList(args)
+		}
+	},
+}
+
+func operationList(volumeNames []string) error {
+
+	operations, err := GetOperations()
+	if err != nil {
+		return err
+	}
+
+	// Limit the list to the specified volumes
+	if len(volumeNames) > 0 {
+		volumes := make(map[string]bool)
+		for _, volumeName := range volumeNames {
+			volumes[volumeName] = true
+		}
+		filtered := make([]storage.OperationExternal, 0)
+		for _, operation := range operations {
+			if volumes[operation.Volume] {
+				filtered = append(filtered, operation)
+			}
+		}
+		operations = filtered
+	}
+
+	WriteOperations(operations)
+
+	return nil
+}
+
+func GetOperations() ([]storage.OperationExternal, error) {
+
+	url := BaseURL() + "/operation"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get operations: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var listOperationsResponse rest.ListOperationsResponse
+	if err = json.Unmarshal(responseBody, &listOperationsResponse); err != nil {
+		return nil, err
+	}
+
+	operations := make([]storage.OperationExternal, 0, len(listOperationsResponse.Operations))
+	for _, operation := range listOperationsResponse.Operations {
+		operations = append(operations, *operation)
+	}
+
+	return operations, nil
+}
+
+func WriteOperations(operations []storage.OperationExternal) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultipleOperationResponse{Items: operations})
+	case FormatYAML:
+		WriteYAML(api.MultipleOperationResponse{Items: operations})
+	case FormatName:
+		writeOperationNames(operations)
+	case FormatWide:
+		writeWideOperationTable(operations)
+	default:
+		writeOperationTable(operations)
+	}
+}
+
+func writeOperationTable(operations []storage.OperationExternal) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Operation", "Volume", "Snapshot", "Phase", "State", "Started"})
+
+	for _, operation := range operations {
+		table.Append([]string{
+			string(operation.Op),
+			operation.Volume,
+			operation.Snapshot,
+			operation.Phase,
+			string(operation.State),
+			formatOperationTime(operation.StartTime),
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideOperationTable(operations []storage.OperationExternal) {
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{
+		"Operation",
+		"Volume",
+		"Snapshot",
+		"Phase",
+		"State",
+		"Started",
+		"Attempts",
+		"Next Attempt",
+		"Last Error",
+	})
+
+	for _, operation := range operations {
+		table.Append([]string{
+			string(operation.Op),
+			operation.Volume,
+			operation.Snapshot,
+			operation.Phase,
+			string(operation.State),
+			formatOperationTime(operation.StartTime),
+			strconv.Itoa(operation.Attempts),
+			formatOperationTime(operation.NextAttempt),
+			operation.LastError,
+		})
+	}
+
+	table.Render()
+}
+
+func formatOperationTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func writeOperationNames(operations []storage.OperationExternal) {
+	for _, operation := range operations {
+		fmt.Println(operation.Name)
+	}
+}
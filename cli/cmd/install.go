@@ -38,14 +38,16 @@ const (
 	DefaultPVName      = tridentconfig.OrchestratorName
 
 	// CRD names
-	BackendCRDName       = "tridentbackends.trident.netapp.io"
-	BackendConfigCRDName = "tridentbackendconfigs.trident.netapp.io"
-	NodeCRDName          = "tridentnodes.trident.netapp.io"
-	StorageClassCRDName  = "tridentstorageclasses.trident.netapp.io"
-	TransactionCRDName   = "tridenttransactions.trident.netapp.io"
-	VersionCRDName       = "tridentversions.trident.netapp.io"
-	VolumeCRDName        = "tridentvolumes.trident.netapp.io"
-	SnapshotCRDName      = "tridentsnapshots.trident.netapp.io"
+	BackendCRDName            = "tridentbackends.trident.netapp.io"
+	BackendConfigCRDName      = "tridentbackendconfigs.trident.netapp.io"
+	MirrorRelationshipCRDName = "tridentmirrorrelationships.trident.netapp.io"
+	NodeCRDName               = "tridentnodes.trident.netapp.io"
+	StorageClassCRDName       = "tridentstorageclasses.trident.netapp.io"
+	TransactionCRDName        = "tridenttransactions.trident.netapp.io"
+	VersionCRDName            = "tridentversions.trident.netapp.io"
+	VolumeCRDName             = "tridentvolumes.trident.netapp.io"
+	SnapshotCRDName           = "tridentsnapshots.trident.netapp.io"
+	SnapshotPolicyCRDName     = "tridentsnapshotpolicies.trident.netapp.io"
 
 	NamespaceFilename          = "trident-namespace.yaml"
 	ServiceAccountFilename     = "trident-serviceaccount.yaml"
@@ -115,12 +117,14 @@ var (
 	CRDnames = []string{
 		BackendCRDName,
 		BackendConfigCRDName,
+		MirrorRelationshipCRDName,
 		NodeCRDName,
 		StorageClassCRDName,
 		TransactionCRDName,
 		VersionCRDName,
 		VolumeCRDName,
 		SnapshotCRDName,
+		SnapshotPolicyCRDName,
 	}
 
 	useCRDv1 bool
@@ -97,6 +97,14 @@ func deleteCRs() error {
 		return err
 	}
 
+	if err := deleteMirrorRelationships(); err != nil {
+		return err
+	}
+
+	if err := deleteSnapshotPolicies(); err != nil {
+		return err
+	}
+
 	// deleting backend config before backends is desirable, do not want backend deletion without
 	// the backendconfig deletion to trigger another backend creation
 	if err := deleteBackendConfigs(); err != nil {
@@ -244,6 +252,64 @@ func deleteBackends() error {
 	return nil
 }
 
+func deleteMirrorRelationships() error {
+
+	crd := "tridentmirrorrelationships.trident.netapp.io"
+	logFields := log.Fields{"CRD": crd}
+
+	// See if CRD exists
+	exists, err := kubeClient.CheckCRDExists(crd)
+	if err != nil {
+		return err
+	} else if !exists {
+		log.WithFields(logFields).Debug("CRD not present.")
+		return nil
+	}
+
+	relationships, err := crdClientset.TridentV1().TridentMirrorRelationships(resetNamespace).List(ctx(), listOpts)
+	if err != nil {
+		return err
+	} else if len(relationships.Items) == 0 {
+		log.WithFields(logFields).Info("Resources not present.")
+		return nil
+	}
+
+	for _, relationship := range relationships.Items {
+		if relationship.DeletionTimestamp.IsZero() {
+			_ = crdClientset.TridentV1().TridentMirrorRelationships(resetNamespace).Delete(ctx(), relationship.Name,
+				deleteOpts)
+		}
+	}
+
+	relationships, err = crdClientset.TridentV1().TridentMirrorRelationships(resetNamespace).List(ctx(), listOpts)
+	if err != nil {
+		return err
+	}
+
+	for _, relationship := range relationships.Items {
+		if relationship.HasTridentFinalizers() {
+			crCopy := relationship.DeepCopy()
+			crCopy.RemoveTridentFinalizers()
+			_, err := crdClientset.TridentV1().TridentMirrorRelationships(resetNamespace).Update(ctx(), crCopy, updateOpts)
+			if isNotFoundError(err) {
+				continue
+			} else if err != nil {
+				log.Errorf("Problem removing finalizers: %v", err)
+				return err
+			}
+		}
+
+		deleteFunc := crdClientset.TridentV1().TridentMirrorRelationships(resetNamespace).Delete
+		if err := deleteWithRetry(deleteFunc, ctx(), relationship.Name, nil); err != nil {
+			log.Errorf("Problem deleting resource: %v", err)
+			return err
+		}
+	}
+
+	log.WithFields(logFields).Info("Resources deleted.")
+	return nil
+}
+
 func deleteBackendConfigs() error {
 
 	crd := "tridentbackendconfigs.trident.netapp.io"
@@ -587,17 +653,54 @@ func deleteSnapshots() error {
 	return nil
 }
 
+func deleteSnapshotPolicies() error {
+
+	crd := "tridentsnapshotpolicies.trident.netapp.io"
+	logFields := log.Fields{"CRD": crd}
+
+	// See if CRD exists
+	exists, err := kubeClient.CheckCRDExists(crd)
+	if err != nil {
+		return err
+	} else if !exists {
+		log.WithFields(logFields).Debug("CRD not present.")
+		return nil
+	}
+
+	policies, err := crdClientset.TridentV1().TridentSnapshotPolicies(resetNamespace).List(ctx(), listOpts)
+	if err != nil {
+		return err
+	} else if len(policies.Items) == 0 {
+		log.WithFields(logFields).Info("Resources not present.")
+		return nil
+	}
+
+	// Snapshot policies have no finalizers, since their snapshots outlive them
+	for _, policy := range policies.Items {
+		deleteFunc := crdClientset.TridentV1().TridentSnapshotPolicies(resetNamespace).Delete
+		if err := deleteWithRetry(deleteFunc, ctx(), policy.Name, nil); err != nil {
+			log.Errorf("Problem deleting resource: %v", err)
+			return err
+		}
+	}
+
+	log.WithFields(logFields).Info("Resources deleted.")
+	return nil
+}
+
 func deleteCRDs() error {
 
 	crdNames := []string{
 		"tridentversions.trident.netapp.io",
 		"tridentbackendconfigs.trident.netapp.io",
 		"tridentbackends.trident.netapp.io",
+		"tridentmirrorrelationships.trident.netapp.io",
 		"tridentstorageclasses.trident.netapp.io",
 		"tridentvolumes.trident.netapp.io",
 		"tridentnodes.trident.netapp.io",
 		"tridenttransactions.trident.netapp.io",
 		"tridentsnapshots.trident.netapp.io",
+		"tridentsnapshotpolicies.trident.netapp.io",
 	}
 
 	for _, crdName := range crdNames {
@@ -0,0 +1,18 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+func init() {
+	RootCmd.AddCommand(relocateCmd)
+}
+
+var relocateCmd = &cobra.Command{
+	Use:   "relocate",
+	Short: "Relocate a resource in Trident",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := discoverOperatingMode(cmd)
+		return err
+	},
+}
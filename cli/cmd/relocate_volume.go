@@ -0,0 +1,88 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var volumeRelocation storage.VolumeRelocationRequest
+
+func init() {
+	relocateCmd.AddCommand(relocateVolumeCmd)
+	relocateVolumeCmd.Flags().StringVarP(&volumeRelocation.Backend, "backend", "", "",
+		"Name of the backend to which the volume should be relocated")
+	relocateVolumeCmd.Flags().StringVarP(&volumeRelocation.Pool, "pool", "", "",
+		"Name of the storage pool to which the volume should be relocated")
+}
+
+var relocateVolumeCmd = &cobra.Command{
+	Use:     "volume <name>",
+	Short:   "Relocate a volume to another storage pool that matches its storage class",
+	Aliases: []string{"v"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"relocate", "volume"}
+			if volumeRelocation.Backend != "" {
+				command = append(command, "--backend", volumeRelocation.Backend)
+			}
+			if volumeRelocation.Pool != "" {
+				command = append(command, "--pool", volumeRelocation.Pool)
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeRelocate(args, &volumeRelocation)
+		}
+	},
+}
+
+func volumeRelocate(volumeNames []string, relocation *storage.VolumeRelocationRequest) error {
+
+	switch len(volumeNames) {
+	case 0:
+		return errors.New("volume name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple volume names specified")
+	}
+
+	// Send the relocation request to Trident
+	url := BaseURL() + "/volume/" + volumeNames[0] + "/relocate"
+
+	requestBytes, err := json.Marshal(relocation)
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not relocate volume %s: %v", volumeNames[0],
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var relocateVolumeResponse rest.RelocateVolumeResponse
+	if err = json.Unmarshal(responseBody, &relocateVolumeResponse); err != nil {
+		return err
+	}
+	if relocateVolumeResponse.Volume == nil {
+		return fmt.Errorf("could not relocate volume %s: no volume returned", volumeNames[0])
+	}
+
+	volumes := []storage.VolumeExternal{*relocateVolumeResponse.Volume}
+	WriteVolumes(volumes)
+
+	return nil
+}
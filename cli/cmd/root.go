@@ -4,6 +4,7 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -312,6 +313,17 @@ func getTridentPod(namespace, appLabel string) (string, error) {
 		return "", err
 	}
 
+	// With several controller replicas, use the elected leader
+	if len(tridentPod.Items) > 1 {
+		if leader, err := getLeaderPod(namespace); err == nil {
+			for _, pod := range tridentPod.Items {
+				if pod.ObjectMeta.Name == leader {
+					return leader, nil
+				}
+			}
+		}
+	}
+
 	if len(tridentPod.Items) != 1 {
 		return "", fmt.Errorf("could not find a Trident pod in the %s namespace. "+
 			"You may need to use the -n option to specify the correct namespace", namespace)
@@ -323,6 +335,22 @@ func getTridentPod(namespace, appLabel string) (string, error) {
 	return name, nil
 }
 
+// getLeaderPod returns the name of the Trident controller pod holding the leader election lease
+func getLeaderPod(namespace string) (string, error) {
+
+	out, err := exec.Command(
+		KubernetesCLI,
+		"get", "lease", config.LeaderElectionLeaseName,
+		"-n", namespace,
+		"-o=jsonpath={.spec.holderIdentity}",
+	).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%v; %s", err, string(out))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
 // getTridentOperatorPod returns the name and namespace of the Trident pod
 func getTridentOperatorPod(appLabel string) (string, string, error) {
 
@@ -555,6 +583,32 @@ func TunnelCommandRaw(commandArgs []string) ([]byte, error) {
 	return output, err
 }
 
+// TunnelCommandWithInput is TunnelCommandRaw for commands that read their input from stdin.
+func TunnelCommandWithInput(commandArgs []string, input []byte) ([]byte, error) {
+
+	// Build tunnel command to exec command in container, passing stdin through
+	execCommand := []string{"exec", "-i", TridentPodName, "-n", TridentPodNamespace, "-c", config.ContainerTrident, "--"}
+
+	// Build CLI command
+	cliCommand := []string{"tridentctl"}
+	cliCommand = append(cliCommand, commandArgs...)
+
+	// Combine tunnel and CLI commands
+	execCommand = append(execCommand, cliCommand...)
+
+	if Debug {
+		fmt.Printf("Invoking tunneled command: %s %v\n", KubernetesCLI, strings.Join(execCommand, " "))
+	}
+
+	// Invoke tridentctl inside the Trident pod
+	command := exec.Command(KubernetesCLI, execCommand...)
+	command.Stdin = bytes.NewReader(input)
+	output, err := command.CombinedOutput()
+
+	SetExitCodeFromError(err)
+	return output, err
+}
+
 func GetErrorFromHTTPResponse(response *http.Response, responseBody []byte) error {
 
 	var errorResponse api.ErrorResponse
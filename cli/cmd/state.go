@@ -0,0 +1,228 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	persistentstore "github.com/netapp/trident/persistent_store"
+)
+
+const statePassphraseEnvVar = "TRIDENT_STATE_PASSPHRASE"
+
+var (
+	stateFilename        string
+	statePassphrase      string
+	statePassphraseStdin bool
+	stateStdin           = bufio.NewReader(os.Stdin)
+)
+
+func init() {
+	RootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateExportCmd)
+	stateCmd.AddCommand(stateImportCmd)
+	stateCmd.PersistentFlags().StringVarP(&statePassphrase, "passphrase", "", "",
+		"Passphrase with which backend credentials are encrypted, which may also be set with the "+
+			statePassphraseEnvVar+" environment variable.  If not set, exported credentials are redacted.")
+	// Tunneled commands receive the passphrase on the first line of stdin, so it doesn't appear in the
+	// arguments of the command run in the Trident pod
+	stateCmd.PersistentFlags().BoolVarP(&statePassphraseStdin, "passphrase-stdin", "", false,
+		"Read the passphrase from the first line of stdin")
+	_ = stateCmd.PersistentFlags().MarkHidden("passphrase-stdin")
+	stateExportCmd.Flags().StringVarP(&stateFilename, "filename", "f", "",
+		"Path of the archive file to write, or stdout if not set")
+	stateImportCmd.Flags().StringVarP(&stateFilename, "filename", "f", "", "Path of the archive file to read")
+}
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Export or import the state of Trident",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := discoverOperatingMode(cmd)
+		return err
+	},
+}
+
+var stateExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write an archive of Trident's backends, volumes, and other state to a file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		passphrase, err := getStatePassphrase()
+		if err != nil {
+			return err
+		}
+
+		var archiveBytes []byte
+
+		if OperatingMode == ModeTunnel {
+			command := []string{"state", "export", "--passphrase-stdin"}
+			archiveBytes, err = TunnelCommandWithInput(command, passphraseInput(passphrase))
+			if err != nil {
+				return fmt.Errorf("%v; %s", err, string(archiveBytes))
+			}
+			// Make sure the tunneled command wrote an archive and not something else
+			if err = json.Unmarshal(archiveBytes, &persistentstore.StateArchive{}); err != nil {
+				return fmt.Errorf("could not parse state archive; %v", err)
+			}
+		} else if archiveBytes, err = stateExport(passphrase); err != nil {
+			return err
+		}
+
+		return writeStateArchive(archiveBytes)
+	},
+}
+
+var stateImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Restore Trident's backends, volumes, and other state from an archive file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		if stateFilename == "" {
+			return errors.New("no input file was specified")
+		}
+
+		// The passphrase precedes the archive on stdin, so it must be read first
+		passphrase, err := getStatePassphrase()
+		if err != nil {
+			return err
+		}
+
+		var archiveBytes []byte
+		if stateFilename == "-" {
+			archiveBytes, err = ioutil.ReadAll(stateStdin)
+		} else {
+			archiveBytes, err = ioutil.ReadFile(stateFilename)
+		}
+		if err != nil {
+			return err
+		}
+
+		archive := &persistentstore.StateArchive{}
+		if err = json.Unmarshal(archiveBytes, archive); err != nil {
+			return fmt.Errorf("could not parse state archive; %v", err)
+		}
+
+		if OperatingMode == ModeTunnel {
+			// The archive may be too large to pass as an argument, so send it via stdin after the passphrase
+			command := []string{"state", "import", "--filename", "-", "--passphrase-stdin"}
+			input := append(passphraseInput(passphrase), archiveBytes...)
+			out, err := TunnelCommandWithInput(command, input)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s", string(out))
+			} else {
+				fmt.Print(string(out))
+			}
+			return nil
+		} else {
+			return stateImport(archive, passphrase)
+		}
+	},
+}
+
+func getStatePassphrase() (string, error) {
+	if statePassphraseStdin {
+		line, err := stateStdin.ReadString('\n')
+		if err != nil {
+			return "", fmt.Errorf("could not read passphrase from stdin; %v", err)
+		}
+		return strings.TrimSuffix(line, "\n"), nil
+	}
+	if statePassphrase != "" {
+		return statePassphrase, nil
+	}
+	return os.Getenv(statePassphraseEnvVar), nil
+}
+
+// passphraseInput returns the stdin of a tunneled command that reads the passphrase with --passphrase-stdin.
+func passphraseInput(passphrase string) []byte {
+	var input bytes.Buffer
+	input.WriteString(passphrase)
+	input.WriteByte('\n')
+	return input.Bytes()
+}
+
+func writeStateArchive(archiveBytes []byte) error {
+
+	if stateFilename == "" || stateFilename == "-" {
+		_, err := os.Stdout.Write(archiveBytes)
+		return err
+	}
+
+	// The archive contains backend credentials, even if encrypted, so keep it private
+	if err := ioutil.WriteFile(stateFilename, archiveBytes, 0600); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote Trident state to %s.\n", stateFilename)
+	return nil
+}
+
+func stateExport(passphrase string) ([]byte, error) {
+
+	url := BaseURL() + "/state/export"
+
+	requestBytes, err := json.Marshal(rest.ExportStateRequest{Passphrase: passphrase})
+	if err != nil {
+		return nil, err
+	}
+
+	// The request and response contain secrets, so they are never logged
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, false)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not export state: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var exportStateResponse rest.ExportStateResponse
+	if err = json.Unmarshal(responseBody, &exportStateResponse); err != nil {
+		return nil, err
+	}
+
+	archiveBytes, err := json.MarshalIndent(exportStateResponse.Archive, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(archiveBytes, '\n'), nil
+}
+
+func stateImport(archive *persistentstore.StateArchive, passphrase string) error {
+
+	url := BaseURL() + "/state/import"
+
+	requestBytes, err := json.Marshal(rest.ImportStateRequest{Archive: archive, Passphrase: passphrase})
+	if err != nil {
+		return err
+	}
+
+	// The request contains secrets, so it is never logged
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, false)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("could not import state: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var importStateResponse rest.ImportStateResponse
+	if err = json.Unmarshal(responseBody, &importStateResponse); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d backends and %d volumes.\n", importStateResponse.Backends, importStateResponse.Volumes)
+
+	return nil
+}
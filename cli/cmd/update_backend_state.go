@@ -20,14 +20,19 @@ var backendState string
 
 func init() {
 	updateBackendCmd.AddCommand(updateBackendStateCmd)
-	updateBackendStateCmd.Flags().StringVarP(&backendState, "state", "", "", "New backend state")
+	updateBackendStateCmd.Flags().StringVarP(&backendState, "state", "", "",
+		"New backend state (maintenance, online or failed)")
 }
 
 var updateBackendStateCmd = &cobra.Command{
-	Use:     "state <name> <state>",
-	Short:   "Update a backend's state in Trident",
+	Use:   "state <name> --state <state>",
+	Short: "Update a backend's state in Trident",
+	Long: `Update a backend's state in Trident.
+
+A backend in the maintenance state is not chosen for new volumes, while its existing volumes
+may still be published, snapshotted, resized and deleted.  Setting the state of a backend in
+maintenance to online returns it to service.`,
 	Aliases: []string{"s"},
-	Hidden:  true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		newBackendState, err := getBackendState()
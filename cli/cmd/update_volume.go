@@ -0,0 +1,110 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var volumeModification storage.VolumeModification
+
+func init() {
+	updateCmd.AddCommand(updateVolumeCmd)
+	updateVolumeCmd.Flags().StringVarP(&volumeModification.SnapshotPolicy, "snapshot-policy", "", "",
+		"New snapshot policy")
+	updateVolumeCmd.Flags().StringVarP(&volumeModification.SnapshotReserve, "snapshot-reserve", "", "",
+		"New snapshot reserve percentage")
+	updateVolumeCmd.Flags().StringVarP(&volumeModification.ExportPolicy, "export-policy", "", "",
+		"New export policy")
+	updateVolumeCmd.Flags().StringVarP(&volumeModification.QosPolicy, "qos-policy", "", "",
+		"New QoS policy group")
+	updateVolumeCmd.Flags().StringVarP(&volumeModification.AdaptiveQosPolicy, "adaptive-qos-policy", "", "",
+		"New adaptive QoS policy group")
+	updateVolumeCmd.Flags().StringVarP(&volumeModification.TieringPolicy, "tiering-policy", "", "",
+		"New tiering policy")
+	updateVolumeCmd.Flags().StringVarP(&volumeModification.UnixPermissions, "unix-permissions", "", "",
+		"New UNIX permissions")
+}
+
+var updateVolumeCmd = &cobra.Command{
+	Use:     "volume <name>",
+	Short:   "Update a volume's attributes in Trident",
+	Aliases: []string{"v"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		if err := volumeModification.Validate(); err != nil {
+			return err
+		}
+
+		if OperatingMode == ModeTunnel {
+			command := []string{"update", "volume"}
+			for flag, value := range map[string]string{
+				"--snapshot-policy":     volumeModification.SnapshotPolicy,
+				"--snapshot-reserve":    volumeModification.SnapshotReserve,
+				"--export-policy":       volumeModification.ExportPolicy,
+				"--qos-policy":          volumeModification.QosPolicy,
+				"--adaptive-qos-policy": volumeModification.AdaptiveQosPolicy,
+				"--tiering-policy":      volumeModification.TieringPolicy,
+				"--unix-permissions":    volumeModification.UnixPermissions,
+			} {
+				if value != "" {
+					command = append(command, flag, value)
+				}
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return volumeUpdate(args, &volumeModification)
+		}
+	},
+}
+
+func volumeUpdate(volumeNames []string, modification *storage.VolumeModification) error {
+
+	switch len(volumeNames) {
+	case 0:
+		return errors.New("volume name not specified")
+	case 1:
+		break
+	default:
+		return errors.New("multiple volume names specified")
+	}
+
+	// Send the modification to Trident
+	url := BaseURL() + "/volume/" + volumeNames[0]
+
+	requestBytes, err := json.Marshal(modification)
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not update volume %s: %v", volumeNames[0],
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var updateVolumeResponse rest.UpdateVolumeResponse
+	if err = json.Unmarshal(responseBody, &updateVolumeResponse); err != nil {
+		return err
+	}
+	if updateVolumeResponse.Volume == nil {
+		return fmt.Errorf("could not update volume %s: no volume returned", volumeNames[0])
+	}
+
+	volumes := []storage.VolumeExternal{*updateVolumeResponse.Volume}
+	WriteVolumes(volumes)
+
+	return nil
+}
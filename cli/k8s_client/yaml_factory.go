@@ -119,7 +119,9 @@ rules:
     verbs: ["get", "list", "watch"]
   - apiGroups: ["trident.netapp.io"]
     resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes",
-"tridenttransactions", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status"]
+"tridenttransactions", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status",
+"tridentmirrorrelationships", "tridentmirrorrelationships/status", "tridentsnapshotpolicies",
+"tridentsnapshotpolicies/status"]
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
   - apiGroups: ["policy"]
     resources: ["podsecuritypolicies"]
@@ -170,13 +172,13 @@ rules:
     resources: ["volumeattachments/status"]
     verbs: ["update", "patch"]
   - apiGroups: ["snapshot.storage.k8s.io"]
-    resources: ["volumesnapshots", "volumesnapshotclasses"]
+    resources: ["volumesnapshotclasses"]
     verbs: ["get", "list", "watch", "update", "patch"]
   - apiGroups: ["snapshot.storage.k8s.io"]
     resources: ["volumesnapshots/status", "volumesnapshotcontents/status"]
     verbs: ["update", "patch"]
   - apiGroups: ["snapshot.storage.k8s.io"]
-    resources: ["volumesnapshotcontents"]
+    resources: ["volumesnapshots", "volumesnapshotcontents"]
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
   - apiGroups: ["csi.storage.k8s.io"]
     resources: ["csidrivers", "csinodeinfos"]
@@ -189,8 +191,13 @@ rules:
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
   - apiGroups: ["trident.netapp.io"]
     resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes",
-"tridenttransactions", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status"]
+"tridenttransactions", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status",
+"tridentmirrorrelationships", "tridentmirrorrelationships/status", "tridentsnapshotpolicies",
+"tridentsnapshotpolicies/status"]
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
+  - apiGroups: ["coordination.k8s.io"]
+    resources: ["leases"]
+    verbs: ["get", "list", "watch", "create", "update", "patch"]
   - apiGroups: ["policy"]
     resources: ["podsecuritypolicies"]
     verbs: ["use"]
@@ -1737,7 +1744,9 @@ rules:
     verbs: ["*"]
   - apiGroups: ["trident.netapp.io"]
     resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes",
-"tridenttransactions", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status"]
+"tridenttransactions", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status",
+"tridentmirrorrelationships", "tridentmirrorrelationships/status", "tridentsnapshotpolicies",
+"tridentsnapshotpolicies/status"]
     verbs: ["*"]
   - apiGroups: ["policy"]
     resources: ["podsecuritypolicies"]
@@ -1779,7 +1788,9 @@ rules:
     verbs: ["*"]
   - apiGroups: ["trident.netapp.io"]
     resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes",
-"tridenttransactions", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status"]
+"tridenttransactions", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status",
+"tridentmirrorrelationships", "tridentmirrorrelationships/status", "tridentsnapshotpolicies",
+"tridentsnapshotpolicies/status"]
     verbs: ["*"]
   - apiGroups: ["policy"]
     resources: ["podsecuritypolicies"]
@@ -2123,6 +2134,22 @@ func GetBackendConfigCRDYAML(useCRDv1 bool) string {
 	}
 }
 
+func GetMirrorRelationshipCRDYAML(useCRDv1 bool) string {
+	if useCRDv1 {
+		return tridentMirrorRelationshipCRDYAML_v1
+	} else {
+		return tridentMirrorRelationshipCRDYAML_v1beta1
+	}
+}
+
+func GetSnapshotPolicyCRDYAML(useCRDv1 bool) string {
+	if useCRDv1 {
+		return tridentSnapshotPolicyCRDYAML_v1
+	} else {
+		return tridentSnapshotPolicyCRDYAML_v1beta1
+	}
+}
+
 func GetStorageClassCRDYAML(useCRDv1 bool) string {
 	if useCRDv1 {
 		return tridentStorageClassCRDYAML_v1
@@ -2175,6 +2202,8 @@ func GetOrchestratorCRDYAML(useCRDv1 bool) string {
 kubectl delete crd tridentversions.trident.netapp.io --wait=false
 kubectl delete crd tridentbackends.trident.netapp.io --wait=false
 kubectl delete crd tridentbackendconfigs.trident.netapp.io --wait=false
+kubectl delete crd tridentmirrorrelationships.trident.netapp.io --wait=false
+kubectl delete crd tridentsnapshotpolicies.trident.netapp.io --wait=false
 kubectl delete crd tridentstorageclasses.trident.netapp.io --wait=false
 kubectl delete crd tridentvolumes.trident.netapp.io --wait=false
 kubectl delete crd tridentnodes.trident.netapp.io --wait=false
@@ -2184,6 +2213,8 @@ kubectl delete crd tridentsnapshots.trident.netapp.io --wait=false
 kubectl patch crd tridentversions.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridentbackends.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridentbackendconfigs.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
+kubectl patch crd tridentmirrorrelationships.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
+kubectl patch crd tridentsnapshotpolicies.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridentstorageclasses.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridentvolumes.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridentnodes.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
@@ -2193,6 +2224,8 @@ kubectl patch crd tridentsnapshots.trident.netapp.io -p '{"metadata":{"finalizer
 kubectl delete crd tridentversions.trident.netapp.io
 kubectl delete crd tridentbackends.trident.netapp.io
 kubectl delete crd tridentbackendconfigs.trident.netapp.io
+kubectl delete crd tridentmirrorrelationships.trident.netapp.io
+kubectl delete crd tridentsnapshotpolicies.trident.netapp.io
 kubectl delete crd tridentstorageclasses.trident.netapp.io
 kubectl delete crd tridentvolumes.trident.netapp.io
 kubectl delete crd tridentnodes.trident.netapp.io
@@ -2324,6 +2357,115 @@ spec:
       priority: 1
       JSONPath: .status.deletionPolicy`
 
+const tridentMirrorRelationshipCRDYAML_v1beta1 = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentmirrorrelationships.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  version: v1
+  versions:
+    - name: v1
+      served: true
+      storage: true
+  scope: Namespaced
+  subresources:
+    status: {}
+  names:
+    plural: tridentmirrorrelationships
+    singular: tridentmirrorrelationship
+    kind: TridentMirrorRelationship
+    shortNames:
+    - tmr
+    - tmirrorrelationship
+    categories:
+    - trident
+    - trident-external
+  additionalPrinterColumns:
+    - name: Source Volume
+      type: string
+      description: The source volume
+      priority: 0
+      JSONPath: .spec.sourceVolume
+    - name: Destination Volume
+      type: string
+      description: The destination volume
+      priority: 0
+      JSONPath: .spec.destinationVolume
+    - name: Desired State
+      type: string
+      description: The desired state of the relationship
+      priority: 0
+      JSONPath: .spec.state
+    - name: State
+      type: string
+      description: The current state of the relationship
+      priority: 0
+      JSONPath: .status.state
+    - name: Healthy
+      type: boolean
+      description: Whether the active mirror is healthy
+      priority: 1
+      JSONPath: .status.healthy
+    - name: Message
+      type: string
+      description: The most recent status message
+      priority: 1
+      JSONPath: .status.message`
+
+const tridentSnapshotPolicyCRDYAML_v1beta1 = `
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentsnapshotpolicies.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  version: v1
+  versions:
+    - name: v1
+      served: true
+      storage: true
+  scope: Namespaced
+  subresources:
+    status: {}
+  names:
+    plural: tridentsnapshotpolicies
+    singular: tridentsnapshotpolicy
+    kind: TridentSnapshotPolicy
+    shortNames:
+    - tsp
+    - tsnapshotpolicy
+    categories:
+    - trident
+    - trident-external
+  additionalPrinterColumns:
+    - name: Volumes
+      type: integer
+      description: The number of volumes selected by the policy
+      priority: 0
+      JSONPath: .status.volumes
+    - name: Snapshots
+      type: integer
+      description: The number of snapshots retained by the policy
+      priority: 0
+      JSONPath: .status.snapshots
+    - name: Last Schedule
+      type: string
+      description: The last time snapshots were created
+      priority: 0
+      JSONPath: .status.lastScheduleTime
+    - name: Next Schedule
+      type: string
+      description: The next time snapshots will be created
+      priority: 1
+      JSONPath: .status.nextScheduleTime
+    - name: Message
+      type: string
+      description: The most recent status message
+      priority: 1
+      JSONPath: .status.message`
+
 const tridentStorageClassCRDYAML_v1beta1 = `
 apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
@@ -2511,6 +2653,8 @@ spec:
 const customResourceDefinitionYAML_v1beta1 = tridentVersionCRDYAML_v1beta1 +
 	"\n---" + tridentBackendCRDYAML_v1beta1 +
 	"\n---" + tridentBackendConfigCRDYAML_v1beta1 +
+	"\n---" + tridentMirrorRelationshipCRDYAML_v1beta1 +
+	"\n---" + tridentSnapshotPolicyCRDYAML_v1beta1 +
 	"\n---" + tridentStorageClassCRDYAML_v1beta1 +
 	"\n---" + tridentVolumeCRDYAML_v1beta1 +
 	"\n---" + tridentNodeCRDYAML_v1beta1 +
@@ -2650,6 +2794,121 @@ spec:
     - trident-internal
     - trident-external`
 
+const tridentMirrorRelationshipCRDYAML_v1 = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentmirrorrelationships.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+          openAPIV3Schema:
+              type: object
+              x-kubernetes-preserve-unknown-fields: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+      - name: Source Volume
+        type: string
+        description: The source volume
+        priority: 0
+        jsonPath: .spec.sourceVolume
+      - name: Destination Volume
+        type: string
+        description: The destination volume
+        priority: 0
+        jsonPath: .spec.destinationVolume
+      - name: Desired State
+        type: string
+        description: The desired state of the relationship
+        priority: 0
+        jsonPath: .spec.state
+      - name: State
+        type: string
+        description: The current state of the relationship
+        priority: 0
+        jsonPath: .status.state
+      - name: Healthy
+        type: boolean
+        description: Whether the active mirror is healthy
+        priority: 1
+        jsonPath: .status.healthy
+      - name: Message
+        type: string
+        description: The most recent status message
+        priority: 1
+        jsonPath: .status.message
+  scope: Namespaced
+  names:
+    plural: tridentmirrorrelationships
+    singular: tridentmirrorrelationship
+    kind: TridentMirrorRelationship
+    shortNames:
+    - tmr
+    - tmirrorrelationship
+    categories:
+    - trident
+    - trident-external`
+
+const tridentSnapshotPolicyCRDYAML_v1 = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentsnapshotpolicies.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+          openAPIV3Schema:
+              type: object
+              x-kubernetes-preserve-unknown-fields: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+      - name: Volumes
+        type: integer
+        description: The number of volumes selected by the policy
+        priority: 0
+        jsonPath: .status.volumes
+      - name: Snapshots
+        type: integer
+        description: The number of snapshots retained by the policy
+        priority: 0
+        jsonPath: .status.snapshots
+      - name: Last Schedule
+        type: string
+        description: The last time snapshots were created
+        priority: 0
+        jsonPath: .status.lastScheduleTime
+      - name: Next Schedule
+        type: string
+        description: The next time snapshots will be created
+        priority: 1
+        jsonPath: .status.nextScheduleTime
+      - name: Message
+        type: string
+        description: The most recent status message
+        priority: 1
+        jsonPath: .status.message
+  scope: Namespaced
+  names:
+    plural: tridentsnapshotpolicies
+    singular: tridentsnapshotpolicy
+    kind: TridentSnapshotPolicy
+    shortNames:
+    - tsp
+    - tsnapshotpolicy
+    categories:
+    - trident
+    - trident-external`
+
 const tridentStorageClassCRDYAML_v1 = `
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -2855,6 +3114,8 @@ spec:
 const customResourceDefinitionYAML_v1 = tridentVersionCRDYAML_v1 +
 	"\n---" + tridentBackendCRDYAML_v1 +
 	"\n---" + tridentBackendConfigCRDYAML_v1 +
+	"\n---" + tridentMirrorRelationshipCRDYAML_v1 +
+	"\n---" + tridentSnapshotPolicyCRDYAML_v1 +
 	"\n---" + tridentStorageClassCRDYAML_v1 +
 	"\n---" + tridentVolumeCRDYAML_v1 +
 	"\n---" + tridentNodeCRDYAML_v1 +
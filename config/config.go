@@ -14,6 +14,7 @@ import (
 )
 
 type Protocol string
+type SANType string
 type AccessMode string
 type VolumeMode string
 type VolumeType string
@@ -76,6 +77,10 @@ const (
 	Block       Protocol = "block"
 	ProtocolAny Protocol = ""
 
+	/* SAN type constants. This value denotes the transport used to attach a 'block' protocol volume. */
+	ISCSI SANType = "iscsi"
+	NVMe  SANType = "nvme"
+
 	/* Access mode constants */
 	ReadWriteOnce AccessMode = "ReadWriteOnce"
 	ReadOnlyMany  AccessMode = "ReadOnlyMany"
@@ -103,8 +108,9 @@ const (
 	SANResizeDelta           = 50000000 // 50mb
 
 	/* REST frontend constants */
-	MaxRESTRequestSize = 10240
-	MinTLSVersion      = tls.VersionTLS12
+	MaxRESTRequestSize  = 10240
+	MaxStateArchiveSize = 100 * 1024 * 1024 // 100 MiB
+	MinTLSVersion       = tls.VersionTLS12
 
 	/* Docker constants */
 	DockerPluginModeEnvVariable = "DOCKER_PLUGIN_MODE" // set via contrib/docker/plugin/plugin.json
@@ -140,6 +146,9 @@ const (
 
 	TridentNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
 
+	// Name of the Lease object with which Trident controller replicas elect a leader
+	LeaderElectionLeaseName = OrchestratorName + "-controller"
+
 	/* Kubernetes operator constants */
 	OperatorContainerName = "trident-operator"
 
@@ -180,6 +189,9 @@ var (
 	StorageClassURL = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/storageclass"
 	NodeURL         = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/node"
 	SnapshotURL     = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/snapshot"
+	StateURL        = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/state"
+	DriftURL        = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/drift"
+	OperationURL    = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/operation"
 	StoreURL        = "/" + OrchestratorName + "/store"
 
 	UsingPassthroughStore bool
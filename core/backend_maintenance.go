@@ -0,0 +1,239 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// GetBackendVolumeReport lists the volumes that reside on a backend.
+func (o *TridentOrchestrator) GetBackendVolumeReport(
+	ctx context.Context, backendName string,
+) (report *storage.BackendVolumeReport, err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("backend_volume_report", &err)()
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	backend, err := o.getBackendByBackendName(backendName)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.backendVolumeReport(backend), nil
+}
+
+// backendVolumeReport lists the volumes that reside on a backend.  The caller must hold o.mutex.
+func (o *TridentOrchestrator) backendVolumeReport(backend *storage.Backend) *storage.BackendVolumeReport {
+
+	report := &storage.BackendVolumeReport{
+		Backend:     backend.Name,
+		BackendUUID: backend.BackendUUID,
+		State:       backend.State,
+		Draining:    o.drainingBackends[backend.BackendUUID],
+		Volumes:     make([]*storage.BackendVolumeSummary, 0),
+	}
+
+	for _, volume := range o.volumes {
+		if volume.BackendUUID != backend.BackendUUID {
+			continue
+		}
+		snapshots, _ := o.volumeSnapshots(volume.Config.Name)
+		report.Volumes = append(report.Volumes, &storage.BackendVolumeSummary{
+			Name:           volume.Config.Name,
+			InternalName:   volume.Config.InternalName,
+			Pool:           volume.Pool,
+			Size:           volume.Config.Size,
+			StorageClass:   volume.Config.StorageClass,
+			State:          volume.State,
+			PublishedNodes: append([]string(nil), volume.PublishedNodes...),
+			Snapshots:      len(snapshots),
+		})
+	}
+
+	sort.Slice(report.Volumes, func(i, j int) bool {
+		return report.Volumes[i].Name < report.Volumes[j].Name
+	})
+
+	return report
+}
+
+// DrainBackend starts relocating the volumes on a backend in maintenance to other backends that
+// match the volumes' storage classes, with at most the specified number of relocations in progress
+// at once.  The volumes are relocated in the background, and the drain stops early if the backend
+// leaves maintenance.  The returned report lists the volumes on the backend when the drain started.
+// Draining a backend that is already being drained only returns the report.
+func (o *TridentOrchestrator) DrainBackend(
+	ctx context.Context, backendName string, concurrency int,
+) (report *storage.BackendVolumeReport, err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("backend_drain", &err)()
+
+	if concurrency < 0 {
+		return nil, utils.InvalidInputError(fmt.Sprintf("invalid drain concurrency %d", concurrency))
+	} else if concurrency == 0 {
+		concurrency = storage.DefaultBackendDrainConcurrency
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	backend, err := o.getBackendByBackendName(backendName)
+	if err != nil {
+		return nil, err
+	}
+	if !backend.State.IsMaintenance() {
+		return nil, utils.InvalidInputError(fmt.Sprintf("backend %s is %s; only a backend in maintenance "+
+			"may be drained", backendName, backend.State))
+	}
+
+	report = o.backendVolumeReport(backend)
+	if report.Draining {
+		return report, nil
+	}
+
+	volumeNames := make([]string, 0, len(report.Volumes))
+	for _, volume := range report.Volumes {
+		volumeNames = append(volumeNames, volume.Name)
+	}
+
+	o.drainingBackends[backend.BackendUUID] = true
+	report.Draining = true
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":     backendName,
+		"volumes":     len(volumeNames),
+		"concurrency": concurrency,
+	}).Info("Orchestrator started draining the backend.")
+
+	go o.drainBackend(GenerateRequestContext(nil, "", ContextSourceInternal), backend.BackendUUID,
+		volumeNames, concurrency)
+
+	return report, nil
+}
+
+// drainBackend relocates the named volumes away from a backend, with at most the specified number
+// of relocations in progress at once.  It runs in its own goroutine.
+func (o *TridentOrchestrator) drainBackend(
+	ctx context.Context, backendUUID string, volumeNames []string, concurrency int,
+) {
+
+	defer func() {
+		o.mutex.Lock()
+		delete(o.drainingBackends, backendUUID)
+		o.mutex.Unlock()
+	}()
+
+	var (
+		wg          sync.WaitGroup
+		countsMutex sync.Mutex
+		relocated   int
+		failed      int
+	)
+	slots := make(chan struct{}, concurrency)
+
+	for _, volumeName := range volumeNames {
+
+		slots <- struct{}{}
+
+		// Stop starting relocations if the backend was returned online or has failed
+		o.mutex.RLock()
+		backend, found := o.backends[backendUUID]
+		inMaintenance := found && backend.State.IsMaintenance()
+		o.mutex.RUnlock()
+		if !inMaintenance {
+			<-slots
+			Logc(ctx).WithField("backendUUID", backendUUID).Info(
+				"Backend is no longer in maintenance, stopping the drain.")
+			break
+		}
+
+		wg.Add(1)
+		go func(volumeName string) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			err := o.drainVolume(ctx, backendUUID, volumeName)
+
+			countsMutex.Lock()
+			defer countsMutex.Unlock()
+			if err != nil {
+				Logc(ctx).WithFields(log.Fields{
+					"backendUUID": backendUUID,
+					"volume":      volumeName,
+				}).WithError(err).Warning("Unable to relocate volume from the backend being drained.")
+				failed++
+			} else {
+				relocated++
+			}
+		}(volumeName)
+	}
+
+	wg.Wait()
+
+	Logc(ctx).WithFields(log.Fields{
+		"backendUUID": backendUUID,
+		"relocated":   relocated,
+		"failed":      failed,
+	}).Info("Orchestrator finished draining the backend.")
+}
+
+// drainVolume relocates one volume away from a backend being drained and waits for the relocation
+// to finish.  A volume that has been deleted or has already left the backend is skipped.
+func (o *TridentOrchestrator) drainVolume(ctx context.Context, backendUUID, volumeName string) error {
+
+	o.mutex.RLock()
+	volume, found := o.volumes[volumeName]
+	onBackend := found && volume.BackendUUID == backendUUID
+	relocating := found && volume.State.IsRelocating()
+	o.mutex.RUnlock()
+
+	if !onBackend {
+		return nil
+	}
+
+	// A relocation started by some other means is simply awaited
+	if !relocating {
+		if _, err := o.RelocateVolume(ctx, volumeName, "", ""); err != nil {
+			return err
+		}
+	}
+
+	return pollRelocation(ctx, "drain", func() error {
+		o.mutex.RLock()
+		defer o.mutex.RUnlock()
+
+		volume, found := o.volumes[volumeName]
+		if !found {
+			return nil
+		}
+		if volume.State.IsRelocating() {
+			return fmt.Errorf("volume %s is relocating", volumeName)
+		}
+		if volume.BackendUUID == backendUUID {
+			return backoff.Permanent(fmt.Errorf("relocation of volume %s was rolled back", volumeName))
+		}
+		return nil
+	})
+}
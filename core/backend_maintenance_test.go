@@ -0,0 +1,216 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+func TestBackendMaintenance(t *testing.T) {
+	const (
+		maintenanceBackendName = "maintenanceBackend"
+		onlineBackendName      = "maintenanceOnlineBackend"
+		scName                 = "maintenanceSC"
+		volumeName             = "maintenanceVolume"
+		newVolumeName          = "maintenanceNewVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	addRelocationBackend(t, orchestrator, maintenanceBackendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	original, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+
+	backend, err := orchestrator.UpdateBackendState(ctx(), maintenanceBackendName, string(storage.Maintenance))
+	if err != nil {
+		t.Fatalf("Unable to place backend in maintenance: %v", err)
+	}
+	assert.Equal(t, storage.Maintenance, backend.State)
+	persistentBackend, err := orchestrator.storeClient.GetBackend(ctx(), maintenanceBackendName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.Maintenance, persistentBackend.State)
+	}
+
+	// The backend remains a member of the storage class, but takes no new volumes
+	sc, err := orchestrator.GetStorageClass(ctx(), scName)
+	if assert.NoError(t, err) {
+		assert.Contains(t, sc.StoragePools, maintenanceBackendName)
+	}
+	_, err = orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(newVolumeName, 1, scName, config.File))
+	assert.Error(t, err, "Volume should not be placed on a backend in maintenance")
+
+	addRelocationBackend(t, orchestrator, onlineBackendName, "primary")
+	newVolume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(newVolumeName, 1, scName,
+		config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	assert.NotEqual(t, original.BackendUUID, newVolume.BackendUUID)
+
+	// Existing volumes continue to be served
+	assert.NoError(t, orchestrator.ResizeVolume(ctx(), volumeName, "2147483648"))
+	_, err = orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+		Version:    "1",
+		Name:       "snap",
+		VolumeName: volumeName,
+	})
+	assert.NoError(t, err)
+
+	report, err := orchestrator.GetBackendVolumeReport(ctx(), maintenanceBackendName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.Maintenance, report.State)
+		assert.False(t, report.Draining)
+		if assert.Len(t, report.Volumes, 1) {
+			assert.Equal(t, volumeName, report.Volumes[0].Name)
+			assert.Equal(t, "primary", report.Volumes[0].Pool)
+			assert.Equal(t, "2147483648", report.Volumes[0].Size)
+			assert.Equal(t, 1, report.Volumes[0].Snapshots)
+		}
+	}
+	_, err = orchestrator.GetBackendVolumeReport(ctx(), "missing")
+	assert.True(t, utils.IsNotFoundError(err), "Expected a not found error")
+
+	assert.NoError(t, orchestrator.DeleteSnapshot(ctx(), volumeName, "snap"))
+
+	// The backend stays in maintenance across a restart
+	restarted := getOrchestrator()
+	restartedBackend, err := restarted.GetBackend(ctx(), maintenanceBackendName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.Maintenance, restartedBackend.State)
+	}
+	restarted.Stop()
+
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), volumeName))
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), newVolumeName))
+
+	backend, err = orchestrator.UpdateBackendState(ctx(), maintenanceBackendName, string(storage.Online))
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.Online, backend.State)
+	}
+
+	cleanup(t, orchestrator)
+}
+
+func TestUpdateBackendStateTransitions(t *testing.T) {
+	const (
+		backendName = "stateBackend"
+		scName      = "stateSC"
+	)
+
+	orchestrator := getOrchestrator()
+	addRelocationBackend(t, orchestrator, backendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	tests := []struct {
+		name          string
+		state         storage.BackendState
+		expectedState storage.BackendState
+		expectError   bool
+	}{
+		{"online to online", storage.Online, storage.Online, false},
+		{"online to maintenance", storage.Maintenance, storage.Maintenance, false},
+		{"maintenance to maintenance", storage.Maintenance, storage.Maintenance, false},
+		{"maintenance to offline", storage.Offline, storage.Maintenance, true},
+		{"maintenance to deleting", storage.Deleting, storage.Maintenance, true},
+		{"maintenance to online", storage.Online, storage.Online, false},
+		{"online to failed", storage.Failed, storage.Failed, false},
+		{"failed to maintenance", storage.Maintenance, storage.Failed, true},
+		{"failed to online", storage.Online, storage.Failed, true},
+	}
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		_, err := orchestrator.UpdateBackendState(ctx(), backendName, string(test.state))
+		if test.expectError {
+			assert.Error(t, err, "Expected an error")
+		} else {
+			assert.NoError(t, err, "Unexpected error")
+		}
+
+		backend, err := orchestrator.GetBackend(ctx(), backendName)
+		if assert.NoError(t, err) {
+			assert.Equal(t, test.expectedState, backend.State)
+		}
+	}
+
+	cleanup(t, orchestrator)
+}
+
+func TestDrainBackend(t *testing.T) {
+	const (
+		sourceBackendName = "drainSourceBackend"
+		targetBackendName = "drainTargetBackend"
+		scName            = "drainSC"
+	)
+	volumeNames := []string{"drainVolume1", "drainVolume2", "drainVolume3"}
+
+	defer shortenRelocationIntervals()()
+
+	orchestrator := getOrchestrator()
+	addRelocationBackend(t, orchestrator, sourceBackendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	for _, volumeName := range volumeNames {
+		_, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+		if err != nil {
+			t.Fatalf("Unable to add volume: %v", err)
+		}
+	}
+	addRelocationBackend(t, orchestrator, targetBackendName, "primary")
+	targetBackend, err := orchestrator.GetBackend(ctx(), targetBackendName)
+	if err != nil {
+		t.Fatalf("Unable to get backend: %v", err)
+	}
+	assert.NoError(t, orchestrator.BackfillPublishedNodes(ctx(), map[string][]string{}))
+
+	// Only a backend in maintenance may be drained
+	_, err = orchestrator.DrainBackend(ctx(), sourceBackendName, 0)
+	assert.True(t, utils.IsInvalidInputError(err), "Expected an invalid input error")
+	_, err = orchestrator.DrainBackend(ctx(), "missing", 0)
+	assert.True(t, utils.IsNotFoundError(err), "Expected a not found error")
+
+	if _, err = orchestrator.UpdateBackendState(ctx(), sourceBackendName, string(storage.Maintenance)); err != nil {
+		t.Fatalf("Unable to place backend in maintenance: %v", err)
+	}
+
+	_, err = orchestrator.DrainBackend(ctx(), sourceBackendName, -1)
+	assert.True(t, utils.IsInvalidInputError(err), "Expected an invalid input error")
+
+	report, err := orchestrator.DrainBackend(ctx(), sourceBackendName, 1)
+	if err != nil {
+		t.Fatalf("Unable to drain backend: %v", err)
+	}
+	assert.True(t, report.Draining)
+	assert.Len(t, report.Volumes, len(volumeNames))
+
+	assert.Eventually(t, func() bool {
+		report, err := orchestrator.GetBackendVolumeReport(ctx(), sourceBackendName)
+		return err == nil && !report.Draining && len(report.Volumes) == 0
+	}, 10*time.Second, 10*time.Millisecond, "Backend was not drained")
+
+	for _, volumeName := range volumeNames {
+		volume, err := orchestrator.GetVolume(ctx(), volumeName)
+		if assert.NoError(t, err) {
+			assert.Equal(t, targetBackend.BackendUUID, volume.BackendUUID)
+			assert.Equal(t, storage.VolumeStateOnline, volume.State)
+		}
+	}
+
+	// The drained backend may now be deleted
+	assert.NoError(t, orchestrator.DeleteBackend(ctx(), sourceBackendName))
+	_, err = orchestrator.GetBackend(ctx(), sourceBackendName)
+	assert.True(t, utils.IsNotFoundError(err), "Expected a not found error")
+
+	cleanup(t, orchestrator)
+}
@@ -0,0 +1,493 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/frontend/csi/helpers"
+	. "github.com/netapp/trident/logger"
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const driftMonitorPeriod = 60 * time.Minute
+
+// volumeEventRecorder is implemented by the CSI helper frontends, which record events for volumes
+// in the container orchestrator.
+type volumeEventRecorder interface {
+	RecordVolumeEvent(ctx context.Context, name, eventType, reason, message string)
+}
+
+// StartDriftMonitor starts the thread that periodically compares Trident's volumes and snapshots
+// with the storage on its backends.
+func (o *TridentOrchestrator) StartDriftMonitor(ctx context.Context, period time.Duration) {
+
+	// Create the ticker and channel before starting the thread, so that a stop or restart
+	// cannot race with the thread that uses them
+	ticker := time.NewTicker(period)
+	stopChannel := make(chan struct{})
+	o.driftMonitorTicker = ticker
+	o.driftMonitorChannel = stopChannel
+	o.driftMonitorStopped = false
+
+	go func() {
+		Logc(ctx).Debug("Drift monitor started.")
+
+		for {
+			select {
+			case tick := <-ticker.C:
+				Logc(ctx).WithField("tick", tick).Debug("Drift monitor running.")
+				if err := o.getBootstrapError(); err != nil {
+					Logc(ctx).WithField("error", err).Error("Drift monitor blocked by bootstrap error.")
+					continue
+				}
+				o.checkDrift(ctx)
+			case <-stopChannel:
+				Logc(ctx).Debugf("Drift monitor stopped.")
+				return
+			}
+		}
+	}()
+}
+
+// StopDriftMonitor stops the thread that periodically checks for drift.
+func (o *TridentOrchestrator) StopDriftMonitor() {
+	if o.driftMonitorTicker != nil {
+		o.driftMonitorTicker.Stop()
+	}
+	if o.driftMonitorChannel != nil && !o.driftMonitorStopped {
+		close(o.driftMonitorChannel)
+		o.driftMonitorStopped = true
+	}
+	log.Debug("Drift monitor stopped.")
+}
+
+// GetDriftReport returns the report of the last drift check.  A check is made first if one is
+// requested or none has yet been made.
+func (o *TridentOrchestrator) GetDriftReport(
+	ctx context.Context, refresh bool,
+) (report *storage.DriftReport, err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("drift_get", &err)()
+
+	o.driftMutex.Lock()
+	report = o.driftReport
+	o.driftMutex.Unlock()
+
+	if refresh || report == nil {
+		report = o.checkDrift(ctx)
+	}
+
+	return report, nil
+}
+
+// DeleteOrphanedVolumes deletes volumes that a drift check finds on a backend but that Trident does
+// not know about.  Since the volumes' data is lost, the deletion must be confirmed, and each volume
+// must be named by its internal name.  A report from a new drift check is returned.
+func (o *TridentOrchestrator) DeleteOrphanedVolumes(
+	ctx context.Context, backendName string, internalNames []string, confirm bool,
+) (report *storage.DriftReport, err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("drift_delete_orphans", &err)()
+
+	if len(internalNames) == 0 {
+		return nil, utils.InvalidInputError("no orphaned volumes were specified")
+	}
+	if !confirm {
+		return nil, utils.InvalidInputError("deleting orphaned volumes destroys their data and must be confirmed")
+	}
+
+	o.mutex.RLock()
+	backendUUID, err := o.getBackendUUIDByBackendName(backendName)
+	o.mutex.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only volumes found to be orphaned by a new check are deleted, and none are deleted unless all are
+	report = o.checkDrift(ctx)
+	orphans := make(map[string]bool)
+	for _, item := range report.Items {
+		if item.Type == storage.DriftTypeOrphanedVolume && item.BackendUUID == backendUUID {
+			orphans[item.InternalName] = true
+		}
+	}
+	for _, internalName := range internalNames {
+		if !orphans[internalName] {
+			return nil, utils.InvalidInputError(fmt.Sprintf("volume %s is not an orphaned volume on backend %s",
+				internalName, backendName))
+		}
+	}
+
+	backend, unlockBackend, err := o.rlockBackend(ctx, "DeleteOrphanedVolumes", backendUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, internalName := range internalNames {
+
+		// A volume that Trident has started creating since the check is no longer orphaned
+		if o.isDriftExcluded(ctx, internalName) {
+			err = fmt.Errorf("volume %s is no longer orphaned", internalName)
+			break
+		}
+
+		volConfig := &storage.VolumeConfig{Name: internalName, InternalName: internalName}
+		if err = backend.RemoveVolume(ctx, volConfig); err != nil {
+			err = fmt.Errorf("could not delete orphaned volume %s; %v", internalName, err)
+			break
+		}
+
+		Logc(ctx).WithFields(log.Fields{
+			"backend": backendName,
+			"volume":  internalName,
+		}).Info("Deleted orphaned volume.")
+	}
+	unlockBackend()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return o.checkDrift(ctx), nil
+}
+
+// checkDrift compares Trident's volumes and snapshots with the storage on each backend, saves the
+// resulting report, and records any new differences in metrics and events.
+func (o *TridentOrchestrator) checkDrift(ctx context.Context) *storage.DriftReport {
+
+	o.driftMutex.Lock()
+	defer o.driftMutex.Unlock()
+
+	report := &storage.DriftReport{
+		CheckTime: time.Now(),
+		Items:     make([]*storage.DriftItem, 0),
+		Errors:    make(map[string]string),
+	}
+
+	for _, backendUUID := range o.getBackendUUIDs() {
+		backendName, items, err := o.checkBackendDrift(ctx, backendUUID)
+		if err != nil {
+			Logc(ctx).WithField("backend", backendName).WithError(err).Warning(
+				"Could not check backend for drift.")
+			report.Errors[backendName] = err.Error()
+			continue
+		}
+		report.Items = append(report.Items, items...)
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		return report.Items[i].Key() < report.Items[j].Key()
+	})
+
+	previous := o.driftReport
+	o.driftReport = report
+	o.recordDrift(ctx, previous, report)
+
+	Logc(ctx).WithFields(log.Fields{
+		"differences": len(report.Items),
+		"errors":      len(report.Errors),
+	}).Debug("Checked for drift.")
+
+	return report
+}
+
+// checkBackendDrift compares Trident's volumes and snapshots with the storage on one backend.
+// Backends that are not online or in maintenance are not checked.
+func (o *TridentOrchestrator) checkBackendDrift(
+	ctx context.Context, backendUUID string,
+) (string, []*storage.DriftItem, error) {
+
+	backend, unlockBackend, err := o.rlockBackend(ctx, "checkDrift", backendUUID)
+	if err != nil {
+		// The backend was deleted since the check started
+		return backendUUID, nil, nil
+	}
+	defer unlockBackend()
+
+	if !backend.State.IsOnline() && !backend.State.IsMaintenance() {
+		return backend.Name, nil, nil
+	}
+
+	// Read every volume on the backend, draining the channel even if an error is returned, since a
+	// partial list would make volumes appear to be missing
+	backendVolumes := make(map[string]*storage.VolumeExternal)
+	channel := make(chan *storage.VolumeExternalWrapper)
+	go backend.Driver.GetVolumeExternalWrappers(ctx, channel)
+	var listErr error
+	for wrapper := range channel {
+		if wrapper.Error != nil {
+			listErr = wrapper.Error
+			continue
+		}
+		backendVolumes[wrapper.Volume.Config.InternalName] = wrapper.Volume
+	}
+	if listErr != nil {
+		return backend.Name, nil, fmt.Errorf("could not list volumes; %v", listErr)
+	}
+
+	// Volumes and snapshots with operations in progress are in flux, so they are not compared
+	busyVolumes, busySnapshots, err := o.getDriftExclusions(ctx)
+	if err != nil {
+		return backend.Name, nil, err
+	}
+
+	// Take Trident's view after listing the backend, so that a volume created meanwhile is not
+	// reported as orphaned.  Volumes of all backends are known, since backends may share storage.
+	o.mutex.RLock()
+	knownVolumes := make(map[string]bool)
+	volumes := make([]*storage.Volume, 0)
+	for _, volume := range o.volumes {
+		knownVolumes[volume.Config.InternalName] = true
+		if volume.BackendUUID == backendUUID {
+			volumes = append(volumes, volume)
+		}
+	}
+	volumeSnapshots := make(map[string][]*storage.SnapshotConfig)
+	for _, snapshot := range o.snapshots {
+		if !busySnapshots[snapshot.ID()] {
+			volumeSnapshots[snapshot.Config.VolumeName] = append(volumeSnapshots[snapshot.Config.VolumeName],
+				snapshot.Config)
+		}
+	}
+	o.mutex.RUnlock()
+
+	newItem := func(driftType string, volConfig *storage.VolumeConfig) *storage.DriftItem {
+		return &storage.DriftItem{
+			Type:         driftType,
+			Backend:      backend.Name,
+			BackendUUID:  backendUUID,
+			Volume:       volConfig.Name,
+			InternalName: volConfig.InternalName,
+		}
+	}
+
+	items := make([]*storage.DriftItem, 0)
+
+	for _, volume := range volumes {
+
+		if busyVolumes[volume.Config.InternalName] || volume.State.IsDeleting() {
+			continue
+		}
+
+		backendVolume, ok := backendVolumes[volume.Config.InternalName]
+		if !ok {
+			items = append(items, newItem(storage.DriftTypeMissingVolume, volume.Config))
+			continue
+		}
+
+		// The size of an unmanaged volume is not Trident's to keep
+		if !volume.Config.ImportNotManaged {
+			expected, _ := strconv.ParseUint(volume.Config.Size, 10, 64)
+			actual, _ := strconv.ParseUint(backendVolume.Config.Size, 10, 64)
+			if expected > 0 && actual > 0 && expected != actual {
+				item := newItem(storage.DriftTypeSize, volume.Config)
+				item.Expected, item.Actual = volume.Config.Size, backendVolume.Config.Size
+				items = append(items, item)
+			}
+		}
+
+		for _, attribute := range getVolumeAttributeDrift(volume.Config, backendVolume.Config) {
+			item := newItem(storage.DriftTypeAttribute, volume.Config)
+			item.Attribute, item.Expected, item.Actual = attribute[0], attribute[1], attribute[2]
+			items = append(items, item)
+		}
+
+		snapshotConfigs := volumeSnapshots[volume.Config.Name]
+		if len(snapshotConfigs) == 0 {
+			continue
+		}
+		backendSnapshots, err := backend.GetSnapshots(ctx, volume.Config)
+		if err != nil {
+			Logc(ctx).WithFields(log.Fields{
+				"backend": backend.Name,
+				"volume":  volume.Config.Name,
+			}).WithError(err).Warning("Could not list snapshots to check for drift.")
+			continue
+		}
+		backendSnapshotNames := make(map[string]bool)
+		for _, backendSnapshot := range backendSnapshots {
+			backendSnapshotNames[backendSnapshot.Config.InternalName] = true
+		}
+		for _, snapConfig := range snapshotConfigs {
+			if !backendSnapshotNames[snapConfig.InternalName] {
+				item := newItem(storage.DriftTypeMissingSnapshot, volume.Config)
+				item.Snapshot = snapConfig.Name
+				items = append(items, item)
+			}
+		}
+	}
+
+	for internalName, backendVolume := range backendVolumes {
+		if knownVolumes[internalName] || busyVolumes[internalName] {
+			continue
+		}
+		items = append(items, &storage.DriftItem{
+			Type:         storage.DriftTypeOrphanedVolume,
+			Backend:      backend.Name,
+			BackendUUID:  backendUUID,
+			InternalName: internalName,
+			Actual:       backendVolume.Config.Size,
+		})
+	}
+
+	return backend.Name, items, nil
+}
+
+// getVolumeAttributeDrift returns the name, recorded value and backend value of each volume attribute
+// whose value on the backend differs from the value Trident recorded.  Attributes for which either
+// value is unknown are not compared.
+func getVolumeAttributeDrift(expected, actual *storage.VolumeConfig) [][3]string {
+
+	normalizeBool := func(value string) string {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return strconv.FormatBool(b)
+		}
+		return value
+	}
+
+	attributes := [][3]string{
+		{"snapshotPolicy", expected.SnapshotPolicy, actual.SnapshotPolicy},
+		{"exportPolicy", expected.ExportPolicy, actual.ExportPolicy},
+		{"unixPermissions", expected.UnixPermissions, actual.UnixPermissions},
+		{"snapshotDir", normalizeBool(expected.SnapshotDir), normalizeBool(actual.SnapshotDir)},
+	}
+
+	drift := make([][3]string, 0)
+	for _, attribute := range attributes {
+		if attribute[1] != "" && attribute[2] != "" && attribute[1] != attribute[2] {
+			drift = append(drift, attribute)
+		}
+	}
+	return drift
+}
+
+// getDriftExclusions returns the internal names of volumes, and the IDs of snapshots, that have
+// transactions in progress.
+func (o *TridentOrchestrator) getDriftExclusions(ctx context.Context) (map[string]bool, map[string]bool, error) {
+
+	txns, err := o.storeClient.GetVolumeTransactions(ctx)
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, nil, fmt.Errorf("could not read transactions; %v", err)
+	}
+
+	volumes := make(map[string]bool)
+	snapshots := make(map[string]bool)
+	for _, txn := range txns {
+		if txn.Config != nil {
+			volumes[txn.Config.InternalName] = true
+		}
+		if txn.VolumeCreatingConfig != nil {
+			volumes[txn.VolumeCreatingConfig.InternalName] = true
+		}
+		if txn.VolumeRelocationConfig != nil && txn.VolumeRelocationConfig.TargetConfig != nil {
+			volumes[txn.VolumeRelocationConfig.TargetConfig.InternalName] = true
+		}
+		if txn.SnapshotConfig != nil {
+			snapshots[txn.SnapshotConfig.ID()] = true
+		}
+	}
+	return volumes, snapshots, nil
+}
+
+// isDriftExcluded returns true if Trident knows about a volume, or has an operation in progress on
+// it, so that it must not be treated as orphaned.
+func (o *TridentOrchestrator) isDriftExcluded(ctx context.Context, internalName string) bool {
+
+	busyVolumes, _, err := o.getDriftExclusions(ctx)
+	if err != nil || busyVolumes[internalName] {
+		return true
+	}
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	for _, volume := range o.volumes {
+		if volume.Config.InternalName == internalName {
+			return true
+		}
+	}
+	return false
+}
+
+// recordDrift updates the drift metrics and logs each difference that was not in the previous
+// report, recording an event for the volume concerned if there is one.  The caller must hold
+// o.driftMutex.
+func (o *TridentOrchestrator) recordDrift(ctx context.Context, previous, report *storage.DriftReport) {
+
+	driftGauge.Reset()
+	for _, item := range report.Items {
+		driftGauge.WithLabelValues(item.BackendUUID, item.Type).Inc()
+	}
+	driftCheckErrorsGauge.Set(float64(len(report.Errors)))
+
+	previousItems := make(map[string]bool)
+	if previous != nil {
+		for _, item := range previous.Items {
+			previousItems[item.Key()] = true
+		}
+	}
+
+	var recorder volumeEventRecorder
+	for _, name := range []string{helpers.KubernetesHelper, helpers.PlainCSIHelper} {
+		if r, ok := o.frontends[name].(volumeEventRecorder); ok {
+			recorder = r
+			break
+		}
+	}
+
+	for _, item := range report.Items {
+
+		if previousItems[item.Key()] {
+			continue
+		}
+
+		var reason, message string
+		switch item.Type {
+		case storage.DriftTypeOrphanedVolume:
+			message = fmt.Sprintf("volume %s on backend %s is not known to Trident", item.InternalName,
+				item.Backend)
+		case storage.DriftTypeMissingVolume:
+			reason = "VolumeMissing"
+			message = fmt.Sprintf("volume %s was not found on backend %s", item.InternalName, item.Backend)
+		case storage.DriftTypeMissingSnapshot:
+			reason = "SnapshotMissing"
+			message = fmt.Sprintf("snapshot %s of volume %s was not found on backend %s", item.Snapshot,
+				item.InternalName, item.Backend)
+		case storage.DriftTypeSize:
+			reason = "VolumeSizeDrift"
+			message = fmt.Sprintf("volume %s on backend %s is %s bytes, but %s bytes are expected",
+				item.InternalName, item.Backend, item.Actual, item.Expected)
+		case storage.DriftTypeAttribute:
+			reason = "VolumeAttributeDrift"
+			message = fmt.Sprintf("volume %s on backend %s has %s %s, but %s is expected", item.InternalName,
+				item.Backend, item.Attribute, item.Actual, item.Expected)
+		}
+
+		Logc(ctx).WithFields(log.Fields{
+			"type":     item.Type,
+			"backend":  item.Backend,
+			"volume":   item.Volume,
+			"internal": item.InternalName,
+		}).Warningf("Drift detected: %s.", message)
+
+		if recorder != nil && item.Volume != "" {
+			recorder.RecordVolumeEvent(ctx, item.Volume, helpers.EventTypeWarning, reason, message)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/frontend/csi/helpers"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage/fake"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+// driftEventRecorder is a frontend that records the volume events it is given.
+type driftEventRecorder struct {
+	mutex   sync.Mutex
+	reasons map[string][]string
+}
+
+func (r *driftEventRecorder) Activate() error   { return nil }
+func (r *driftEventRecorder) Deactivate() error { return nil }
+func (r *driftEventRecorder) GetName() string   { return helpers.PlainCSIHelper }
+func (r *driftEventRecorder) Version() string   { return "1" }
+
+func (r *driftEventRecorder) RecordVolumeEvent(_ context.Context, name, _, reason, _ string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.reasons[name] = append(r.reasons[name], reason)
+}
+
+func backendDriftItems(report *storage.DriftReport, backendUUID string) map[string]*storage.DriftItem {
+	items := make(map[string]*storage.DriftItem)
+	for _, item := range report.Items {
+		if item.BackendUUID == backendUUID {
+			items[item.Type+"/"+item.InternalName] = item
+		}
+	}
+	return items
+}
+
+func TestCheckDrift(t *testing.T) {
+	const (
+		backendName       = "driftBackend"
+		scName            = "driftSC"
+		missingVolumeName = "driftMissingVolume"
+		resizedVolumeName = "driftResizedVolume"
+		snapVolumeName    = "driftSnapVolume"
+		orphanName        = "driftOrphan"
+	)
+
+	orchestrator := getOrchestrator()
+	recorder := &driftEventRecorder{reasons: make(map[string][]string)}
+	orchestrator.AddFrontend(recorder)
+	addRelocationBackend(t, orchestrator, backendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	volumes := make(map[string]*storage.VolumeExternal)
+	for _, volumeName := range []string{missingVolumeName, resizedVolumeName, snapVolumeName} {
+		volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+		if err != nil {
+			t.Fatalf("Unable to add volume: %v", err)
+		}
+		volumes[volumeName] = volume
+	}
+	_, err := orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+		Version:    "1",
+		Name:       "snap",
+		VolumeName: snapVolumeName,
+	})
+	if err != nil {
+		t.Fatalf("Unable to create snapshot: %v", err)
+	}
+	backendUUID := volumes[snapVolumeName].BackendUUID
+
+	// Trident and the backend agree at first
+	report, err := orchestrator.GetDriftReport(ctx(), true)
+	if err != nil {
+		t.Fatalf("Unable to get drift report: %v", err)
+	}
+	assert.Empty(t, backendDriftItems(report, backendUUID))
+
+	// Change the backend behind Trident's back
+	driver := getFakeDriver(t, orchestrator, backendUUID)
+	delete(driver.Volumes, volumes[missingVolumeName].Config.InternalName)
+	resized := driver.Volumes[volumes[resizedVolumeName].Config.InternalName]
+	resized.SizeBytes *= 2
+	driver.Volumes[resized.Name] = resized
+	delete(driver.Snapshots, volumes[snapVolumeName].Config.InternalName)
+	driver.Volumes[orphanName] = fake.Volume{
+		Name:          orphanName,
+		RequestedPool: "primary",
+		PhysicalPool:  "primary",
+		SizeBytes:     1073741824,
+	}
+
+	// The last report is returned until a check is made
+	report, err = orchestrator.GetDriftReport(ctx(), false)
+	if assert.NoError(t, err) {
+		assert.Empty(t, backendDriftItems(report, backendUUID))
+	}
+
+	report, err = orchestrator.GetDriftReport(ctx(), true)
+	if err != nil {
+		t.Fatalf("Unable to get drift report: %v", err)
+	}
+	items := backendDriftItems(report, backendUUID)
+	assert.Len(t, items, 4)
+
+	missingKey := storage.DriftTypeMissingVolume + "/" + volumes[missingVolumeName].Config.InternalName
+	if item, ok := items[missingKey]; assert.True(t, ok, "Missing volume not reported") {
+		assert.Equal(t, missingVolumeName, item.Volume)
+		assert.Equal(t, backendName, item.Backend)
+	}
+	if item, ok := items[storage.DriftTypeSize+"/"+resized.Name]; assert.True(t, ok, "Size drift not reported") {
+		assert.Equal(t, "1073741824", item.Expected)
+		assert.Equal(t, "2147483648", item.Actual)
+	}
+	snapshotKey := storage.DriftTypeMissingSnapshot + "/" + volumes[snapVolumeName].Config.InternalName
+	if item, ok := items[snapshotKey]; assert.True(t, ok, "Missing snapshot not reported") {
+		assert.Equal(t, "snap", item.Snapshot)
+	}
+	orphanKey := storage.DriftTypeOrphanedVolume + "/" + orphanName
+	if item, ok := items[orphanKey]; assert.True(t, ok, "Orphaned volume not reported") {
+		assert.Empty(t, item.Volume)
+	}
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(driftGauge.WithLabelValues(backendUUID,
+		storage.DriftTypeOrphanedVolume)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(driftGauge.WithLabelValues(backendUUID,
+		storage.DriftTypeMissingVolume)))
+
+	// Events are recorded once for each new difference
+	_, err = orchestrator.GetDriftReport(ctx(), true)
+	assert.NoError(t, err)
+	recorder.mutex.Lock()
+	assert.Equal(t, []string{"VolumeMissing"}, recorder.reasons[missingVolumeName])
+	assert.Equal(t, []string{"VolumeSizeDrift"}, recorder.reasons[resizedVolumeName])
+	assert.Equal(t, []string{"SnapshotMissing"}, recorder.reasons[snapVolumeName])
+	recorder.mutex.Unlock()
+
+	// Orphaned volumes are only deleted if confirmed
+	tests := []struct {
+		name        string
+		backend     string
+		volumes     []string
+		confirm     bool
+		expectError func(error) bool
+	}{
+		{"not confirmed", backendName, []string{orphanName}, false, utils.IsInvalidInputError},
+		{"no volumes", backendName, []string{}, true, utils.IsInvalidInputError},
+		{"unknown backend", "missing", []string{orphanName}, true, utils.IsNotFoundError},
+		{"known volume", backendName, []string{orphanName, resized.Name}, true, utils.IsInvalidInputError},
+	}
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		_, err = orchestrator.DeleteOrphanedVolumes(ctx(), test.backend, test.volumes, test.confirm)
+		assert.True(t, test.expectError(err), "Unexpected error %v", err)
+		_, ok := driver.Volumes[orphanName]
+		assert.True(t, ok, "Orphaned volume should not have been deleted")
+	}
+
+	report, err = orchestrator.DeleteOrphanedVolumes(ctx(), backendName, []string{orphanName}, true)
+	if assert.NoError(t, err) {
+		_, ok := backendDriftItems(report, backendUUID)[orphanKey]
+		assert.False(t, ok, "Orphaned volume should have been deleted")
+	}
+	_, ok := driver.Volumes[orphanName]
+	assert.False(t, ok, "Orphaned volume should have been deleted")
+	assert.Equal(t, float64(0), testutil.ToFloat64(driftGauge.WithLabelValues(backendUUID,
+		storage.DriftTypeOrphanedVolume)))
+
+	for _, volumeName := range []string{missingVolumeName, resizedVolumeName, snapVolumeName} {
+		assert.NoError(t, orchestrator.DeleteVolume(ctx(), volumeName))
+	}
+
+	cleanup(t, orchestrator)
+}
@@ -0,0 +1,195 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// The orchestrator serializes work using a set of named locks rather than a single global mutex, so
+// that slow storage operations on one resource do not block unrelated requests.
+//
+//   - The backend management lock serializes adding, updating, and deleting backends.
+//   - Each storage class, volume, and snapshot has its own lock, which is held for the duration of
+//     any operation that creates, modifies, or deletes that object.
+//   - Each node has its own lock, which is held while the node is added or deleted.
+//   - Each backend has its own read/write lock.  Volume and snapshot operations hold it for reading
+//     while they invoke the backend's driver, and backend updates hold it for writing so that they
+//     may safely replace or reconfigure the backend.
+//   - o.mutex is a read/write lock that protects the orchestrator's in-memory maps and the objects
+//     stored in them.  It is only ever held briefly, and never while calling a storage driver or
+//     while waiting for another lock.
+//
+// To avoid deadlocks, locks must be acquired in the order listed above, multiple volume locks must
+// be acquired in sorted order (see lockVolumes), and no caller may hold more than one backend lock.
+
+const (
+	backendsLockID     = "core-backends"
+	backendLockPrefix  = "core-backend-"
+	volumeLockPrefix   = "core-volume-"
+	snapshotLockPrefix = "core-snapshot-"
+	scLockPrefix       = "core-storageclass-"
+	nodeLockPrefix     = "core-node-"
+)
+
+// coreLocks holds the locks of the backends, storage classes, volumes, snapshots and nodes.  Each lock
+// exists only while some caller holds it or is waiting for it.
+var coreLocks utils.NamedLocks
+
+// lockNamed exclusively acquires the named lock and returns a function that releases it.
+func lockNamed(ctx context.Context, lockContext, lockID string) func() {
+
+	Logc(ctx).WithField("lock", lockID).Debugf("Attempting to acquire lock (%s).", lockContext)
+	unlock := coreLocks.Lock(lockID)
+	Logc(ctx).WithField("lock", lockID).Debugf("Acquired lock (%s).", lockContext)
+
+	return func() {
+		unlock()
+		Logc(ctx).WithField("lock", lockID).Debugf("Released lock (%s).", lockContext)
+	}
+}
+
+// rlockNamed acquires the named lock for reading and returns a function that releases it.
+func rlockNamed(ctx context.Context, lockContext, lockID string) func() {
+
+	Logc(ctx).WithField("lock", lockID).Debugf("Attempting to acquire read lock (%s).", lockContext)
+	unlock := coreLocks.RLock(lockID)
+	Logc(ctx).WithField("lock", lockID).Debugf("Acquired read lock (%s).", lockContext)
+
+	return func() {
+		unlock()
+		Logc(ctx).WithField("lock", lockID).Debugf("Released read lock (%s).", lockContext)
+	}
+}
+
+// lockBackends acquires the backend management lock and returns a function that releases it.
+func lockBackends(ctx context.Context, lockContext string) func() {
+	return lockNamed(ctx, lockContext, backendsLockID)
+}
+
+// uniqueSortedNames returns a sorted copy of the supplied names with any empty or duplicate
+// names removed.
+func uniqueSortedNames(names []string) []string {
+
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// lockVolumes exclusively acquires the locks for one or more volumes, in a consistent order, and
+// returns a function that releases them.
+func lockVolumes(ctx context.Context, lockContext string, volumeNames ...string) func() {
+
+	names := uniqueSortedNames(volumeNames)
+	unlocks := make([]func(), 0, len(names))
+	for _, name := range names {
+		unlocks = append(unlocks, lockNamed(ctx, lockContext, volumeLockPrefix+name))
+	}
+	return func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+}
+
+// rlockVolume acquires a shared lock on a volume, which permits other operations that only read the
+// volume, such as creating a snapshot of it, to run concurrently.  It returns a function that
+// releases the lock.
+func rlockVolume(ctx context.Context, lockContext, volumeName string) func() {
+	return rlockNamed(ctx, lockContext, volumeLockPrefix+volumeName)
+}
+
+// lockVolumeAndSource exclusively acquires the lock for a volume that is being created from another
+// volume, such as a clone, and acquires a shared lock on the source volume.  The locks are acquired
+// in the same order as lockVolumes would use.  It returns a function that releases both locks.
+func lockVolumeAndSource(ctx context.Context, lockContext, volumeName, sourceVolumeName string) func() {
+
+	if sourceVolumeName == "" || sourceVolumeName == volumeName {
+		return lockVolumes(ctx, lockContext, volumeName)
+	}
+
+	if volumeName < sourceVolumeName {
+		unlockVolume := lockVolumes(ctx, lockContext, volumeName)
+		unlockSource := rlockVolume(ctx, lockContext, sourceVolumeName)
+		return func() {
+			unlockSource()
+			unlockVolume()
+		}
+	}
+
+	unlockSource := rlockVolume(ctx, lockContext, sourceVolumeName)
+	unlockVolume := lockVolumes(ctx, lockContext, volumeName)
+	return func() {
+		unlockVolume()
+		unlockSource()
+	}
+}
+
+// lockSnapshot acquires the lock for a snapshot and returns a function that releases it.
+func lockSnapshot(ctx context.Context, lockContext, snapshotID string) func() {
+	return lockNamed(ctx, lockContext, snapshotLockPrefix+snapshotID)
+}
+
+// lockStorageClass acquires the lock for a storage class and returns a function that releases it.
+func lockStorageClass(ctx context.Context, lockContext, scName string) func() {
+	return lockNamed(ctx, lockContext, scLockPrefix+scName)
+}
+
+// lockNode acquires the lock for a node and returns a function that releases it.
+func lockNode(ctx context.Context, lockContext, nodeName string) func() {
+	return lockNamed(ctx, lockContext, nodeLockPrefix+nodeName)
+}
+
+// lockBackend exclusively acquires the lock for a backend and returns the backend, as found after
+// the lock was acquired, along with a function that releases the lock.  If the backend no longer
+// exists, the lock is released and a NotFoundError is returned.
+func (o *TridentOrchestrator) lockBackend(
+	ctx context.Context, lockContext, backendUUID string,
+) (*storage.Backend, func(), error) {
+
+	unlock := lockNamed(ctx, lockContext, backendLockPrefix+backendUUID)
+
+	o.mutex.RLock()
+	backend, ok := o.backends[backendUUID]
+	o.mutex.RUnlock()
+
+	if !ok {
+		unlock()
+		return nil, nil, utils.NotFoundError(fmt.Sprintf("backend %v was not found", backendUUID))
+	}
+	return backend, unlock, nil
+}
+
+// rlockBackend acquires a shared lock on a backend, which permits any number of volume operations
+// to use the backend concurrently while preventing it from being updated or deleted.  It returns
+// the backend, as found after the lock was acquired, along with a function that releases the lock.
+// If the backend no longer exists, the lock is released and a NotFoundError is returned.
+func (o *TridentOrchestrator) rlockBackend(
+	ctx context.Context, lockContext, backendUUID string,
+) (*storage.Backend, func(), error) {
+
+	unlock := rlockNamed(ctx, lockContext, backendLockPrefix+backendUUID)
+
+	o.mutex.RLock()
+	backend, ok := o.backends[backendUUID]
+	o.mutex.RUnlock()
+
+	if !ok {
+		unlock()
+		return nil, nil, utils.NotFoundError(fmt.Sprintf("backend %v was not found", backendUUID))
+	}
+	return backend, unlock, nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLockVolumesOrder(t *testing.T) {
+
+	ctx := context.Background()
+
+	// Locking the same volumes in any order must not deadlock
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			lockVolumes(ctx, "test1", "b", "a", "c")()
+		}
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		lockVolumes(ctx, "test2", "c", "a", "b", "a")()
+	}
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected volume locks to be acquired in a consistent order.")
+	}
+}
@@ -85,6 +85,21 @@ var (
 		},
 		[]string{"backend_type", "backend_uuid"},
 	)
+	driftGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Name:      "drift_count",
+			Help:      "The number of differences between Trident and its backends found by the last drift check",
+		},
+		[]string{"backend_uuid", "drift_type"},
+	)
+	driftCheckErrorsGauge = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Name:      "drift_check_error_count",
+			Help:      "The number of backends that could not be checked by the last drift check",
+		},
+	)
 	operationDurationInMsSummary = promauto.NewSummaryVec(
 		prometheus.SummaryOpts{
 			Namespace:  config.OrchestratorName,
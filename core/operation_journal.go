@@ -0,0 +1,641 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/utils"
+)
+
+// journaledOperationPhases maps each operation whose progress is recorded in the operation journal
+// to the phase in which it starts.  Interrupted transactions for these operations are resumed from
+// the phase they reached, while those for other operations are rolled back or completed as before.
+// A clone is created and split from its source in phases of its own.  PV upgrades are journaled too,
+// but the Kubernetes helper carries out their phases and resumes them when it starts.
+var journaledOperationPhases = map[storage.VolumeOperation]string{
+	storage.CloneVolume:    storage.OperationPhaseCreating,
+	storage.ResizeVolume:   storage.OperationPhaseResizing,
+	storage.AddSnapshot:    storage.OperationPhaseCreating,
+	storage.DeleteVolume:   storage.OperationPhaseDeleting,
+	storage.DeleteSnapshot: storage.OperationPhaseDeleting,
+}
+
+// operationRetryPolicies control how a failed phase of each journaled operation is retried.  This is
+// a variable so that unit tests may shorten the intervals.
+var operationRetryPolicies = map[storage.VolumeOperation]storage.OperationRetryPolicy{
+	storage.CloneVolume:    {InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute, MaxAttempts: 10},
+	storage.ResizeVolume:   {InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute, MaxAttempts: 10},
+	storage.AddSnapshot:    {InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute, MaxAttempts: 10},
+	storage.DeleteVolume:   {InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute, MaxAttempts: 10},
+	storage.DeleteSnapshot: {InitialInterval: 10 * time.Second, MaxInterval: 5 * time.Minute, MaxAttempts: 10},
+}
+
+var (
+	// errOperationSuperseded is returned when a journaled operation's transaction has been deleted or
+	// replaced by another operation, so that there is nothing left to resume.
+	errOperationSuperseded = errors.New("operation is no longer in the journal")
+
+	// errOperationStopped is returned when the orchestrator stops resuming operations, or can no
+	// longer serve them, so that the operation is left in the journal as it is.
+	errOperationStopped = errors.New("operation was stopped")
+)
+
+// newOperationRecord returns a record for the journal of a new operation, or nil if the operation
+// is not journaled.
+func newOperationRecord(op storage.VolumeOperation) *storage.OperationRecord {
+	if phase, ok := journaledOperationPhases[op]; ok {
+		return storage.NewOperationRecord(phase)
+	}
+	return nil
+}
+
+// isJournaledOperation returns true if a transaction records the progress of a journaled operation.
+func isJournaledOperation(txn *storage.VolumeTransaction) bool {
+	_, ok := journaledOperationPhases[txn.Op]
+	return ok && txn.Operation != nil
+}
+
+// ListOperations returns the operations in progress, as recorded by their transactions.
+func (o *TridentOrchestrator) ListOperations(ctx context.Context) (operations []*storage.OperationExternal,
+	err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("operation_list", &err)()
+
+	operations = make([]*storage.OperationExternal, 0)
+
+	txns, err := o.storeClient.GetVolumeTransactions(ctx)
+	if err != nil {
+		if persistentstore.MatchKeyNotFoundErr(err) {
+			return operations, nil
+		}
+		return nil, err
+	}
+
+	for _, txn := range txns {
+		operations = append(operations, txn.ConstructOperationExternal())
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Op != operations[j].Op {
+			return operations[i].Op < operations[j].Op
+		}
+		return operations[i].Name < operations[j].Name
+	})
+
+	return operations, nil
+}
+
+// saveOperation records the progress of a journaled operation.  A copy of the transaction is saved,
+// so that the store never holds a record that is still being changed.
+func (o *TridentOrchestrator) saveOperation(ctx context.Context, txn *storage.VolumeTransaction) error {
+	record := *txn.Operation
+	saved := *txn
+	saved.Operation = &record
+	return o.storeClient.UpdateVolumeTransaction(ctx, &saved)
+}
+
+// continueOperation attempts the remaining phases of an interrupted journaled operation.  If a phase
+// fails, the operation is resumed in the background, where the phase is retried according to the
+// operation's retry policy.  The caller must hold the locks for the volume or snapshot named in the
+// transaction, or be bootstrapping.
+func (o *TridentOrchestrator) continueOperation(ctx context.Context, txn *storage.VolumeTransaction) {
+
+	txn = copyOperationTransaction(txn)
+
+	for txn.Operation.Phase != storage.OperationPhaseDone {
+		if err := o.attemptOperationPhase(ctx, txn); err != nil {
+			if errors.Is(err, errOperationSuperseded) {
+				return
+			}
+			Logc(ctx).WithFields(operationLogFields(txn)).WithField("phase", txn.Operation.Phase).WithError(
+				err).Warning("Unable to continue the operation, it will be retried.")
+			o.resumeOperation(ctx, txn)
+			return
+		}
+	}
+
+	Logc(ctx).WithFields(operationLogFields(txn)).Info("Orchestrator completed the interrupted operation.")
+}
+
+// resumeOperation continues a journaled operation in the background, unless it is already being
+// resumed or the orchestrator has stopped resuming operations.
+func (o *TridentOrchestrator) resumeOperation(ctx context.Context, txn *storage.VolumeTransaction) {
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if _, ok := o.resumingOperations[txn.Name()]; ok || o.operationsStopped {
+		return
+	}
+
+	operationCtx, cancel := context.WithCancel(GenerateRequestContext(nil, "", ContextSourceInternal))
+	o.resumingOperations[txn.Name()] = cancel
+
+	Logc(ctx).WithFields(operationLogFields(txn)).Debug("Resuming the operation in the background.")
+
+	go o.runOperation(operationCtx, copyOperationTransaction(txn))
+}
+
+// isOperationResuming returns true if a journaled operation is being resumed in the background.
+func (o *TridentOrchestrator) isOperationResuming(txn *storage.VolumeTransaction) bool {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	_, ok := o.resumingOperations[txn.Name()]
+	return ok
+}
+
+// stopOperations stops the journaled operations being resumed in the background, which are left in
+// the journal, and prevents others from being resumed until the transaction monitor starts again.
+func (o *TridentOrchestrator) stopOperations() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.operationsStopped = true
+	for _, cancel := range o.resumingOperations {
+		cancel()
+	}
+}
+
+// runOperation carries out the remaining phases of a journaled operation, retrying each failed
+// phase according to the operation's retry policy.  It runs in its own goroutine, taking the locks
+// for the volume or snapshot named in the transaction for each attempt, until the context is
+// cancelled.  An operation whose phase cannot be completed within its policy's attempts is rolled
+// back if possible, or else marked as failed until it is resumed again.
+func (o *TridentOrchestrator) runOperation(ctx context.Context, txn *storage.VolumeTransaction) {
+
+	defer func() {
+		o.mutex.Lock()
+		if cancel, ok := o.resumingOperations[txn.Name()]; ok {
+			cancel()
+			delete(o.resumingOperations, txn.Name())
+		}
+		o.mutex.Unlock()
+		o.updateMetrics()
+	}()
+
+	logFields := operationLogFields(txn)
+	policy := operationRetryPolicies[txn.Op]
+
+	notify := func(err error, duration time.Duration) {
+		nextAttempt := time.Now().Add(duration)
+		txn.Operation.State = storage.OperationRetrying
+		txn.Operation.LastError = err.Error()
+		txn.Operation.NextAttempt = &nextAttempt
+		if saveErr := o.saveOperation(ctx, txn); saveErr != nil {
+			Logc(ctx).WithFields(logFields).WithError(saveErr).Debug("Could not save the operation's progress.")
+		}
+		Logc(ctx).WithFields(logFields).WithFields(log.Fields{
+			"phase":     txn.Operation.Phase,
+			"attempts":  txn.Operation.Attempts,
+			"increment": duration,
+		}).WithError(err).Debug("Operation phase failed, retrying.")
+	}
+
+	for txn.Operation.Phase != storage.OperationPhaseDone {
+
+		attempt := func() error {
+			unlock := lockOperation(ctx, txn)
+			defer unlock()
+			if ctx.Err() != nil || o.getBootstrapError() != nil {
+				return backoff.Permanent(errOperationStopped)
+			}
+			return o.attemptOperationPhase(ctx, txn)
+		}
+
+		err := backoff.RetryNotify(attempt, backoff.WithContext(newOperationBackOff(policy), ctx), notify)
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, errOperationSuperseded) {
+			Logc(ctx).WithFields(logFields).Debug("Operation is no longer in the journal.")
+			return
+		}
+		if errors.Is(err, errOperationStopped) || ctx.Err() != nil {
+			Logc(ctx).WithFields(logFields).Debug("Stopped resuming the operation.")
+			return
+		}
+
+		Logc(ctx).WithFields(logFields).WithField("phase", txn.Operation.Phase).WithError(err).Error(
+			"Unable to complete the operation.")
+		o.abandonOperation(ctx, txn, err)
+		return
+	}
+
+	Logc(ctx).WithFields(logFields).Info("Orchestrator completed the resumed operation.")
+}
+
+// attemptOperationPhase makes one attempt at the current phase of a journaled operation, and records
+// the phase that follows in the journal, or deletes the transaction once the operation is done.
+func (o *TridentOrchestrator) attemptOperationPhase(ctx context.Context, txn *storage.VolumeTransaction) error {
+
+	// Stop if the operation has finished or been replaced since the last attempt
+	current, err := o.storeClient.GetExistingVolumeTransaction(ctx, txn)
+	if err != nil {
+		return err
+	}
+	if current == nil || current.Op != txn.Op || current.Operation == nil ||
+		!current.Operation.StartTime.Equal(txn.Operation.StartTime) {
+		return backoff.Permanent(errOperationSuperseded)
+	}
+
+	// An operation cannot continue once its volume's backend has been deleted
+	if err = o.checkOperationBackend(txn); err != nil {
+		return backoff.Permanent(err)
+	}
+
+	txn.Operation.Attempts++
+	txn.Operation.State = storage.OperationRunning
+	txn.Operation.NextAttempt = nil
+
+	var nextPhase string
+	switch txn.Op {
+	case storage.CloneVolume:
+		nextPhase, err = o.cloneVolumeOperationPhase(ctx, txn)
+	case storage.ResizeVolume:
+		nextPhase, err = o.resizeVolumeOperationPhase(ctx, txn)
+	case storage.AddSnapshot:
+		nextPhase, err = o.addSnapshotOperationPhase(ctx, txn)
+	case storage.DeleteVolume:
+		nextPhase, err = o.deleteVolumeOperationPhase(ctx, txn)
+	case storage.DeleteSnapshot:
+		nextPhase, err = o.deleteSnapshotOperationPhase(ctx, txn)
+	default:
+		err = backoff.Permanent(fmt.Errorf("operation %s is not journaled", txn.Op))
+	}
+	if err != nil {
+		return err
+	}
+
+	if nextPhase == storage.OperationPhaseDone {
+		if err = o.DeleteVolumeTransaction(ctx, txn); err != nil {
+			return fmt.Errorf("could not delete the transaction; %v", err)
+		}
+		txn.Operation.Phase = nextPhase
+		return nil
+	}
+
+	// Record the next phase only once it is saved, so that a failed save repeats this phase
+	record := *txn.Operation
+	record.Phase = nextPhase
+	record.Attempts = 0
+	record.LastError = ""
+	saved := *txn
+	saved.Operation = &record
+	if err = o.storeClient.UpdateVolumeTransaction(ctx, &saved); err != nil {
+		return fmt.Errorf("could not save the operation's progress; %v", err)
+	}
+	*txn.Operation = record
+
+	return nil
+}
+
+// checkOperationBackend returns a NotFoundError if the volume named in a journaled operation's
+// transaction exists but its backend does not.
+func (o *TridentOrchestrator) checkOperationBackend(txn *storage.VolumeTransaction) error {
+
+	volumeName := txn.Name()
+	if txn.SnapshotConfig != nil {
+		volumeName = txn.SnapshotConfig.VolumeName
+	}
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	volume, ok := o.volumes[volumeName]
+	if !ok {
+		return nil
+	}
+	if _, ok = o.backends[volume.BackendUUID]; !ok {
+		return utils.NotFoundError(fmt.Sprintf("backend %s for volume %s not found", volume.BackendUUID,
+			volumeName))
+	}
+	return nil
+}
+
+// abandonOperation handles a journaled operation whose phase could not be completed.  A snapshot
+// creation is rolled back, since the snapshot may be requested again, while other operations are
+// marked as failed and left in the journal, to be resumed by the transaction monitor or by the next
+// operation on the same volume or snapshot.
+func (o *TridentOrchestrator) abandonOperation(ctx context.Context, txn *storage.VolumeTransaction, err error) {
+
+	logFields := operationLogFields(txn)
+
+	if txn.Op == storage.AddSnapshot {
+		unlock := lockOperation(ctx, txn)
+		defer unlock()
+
+		if rollbackErr := o.rollBackSnapshotCreation(ctx, txn); rollbackErr != nil {
+			Logc(ctx).WithFields(logFields).WithError(rollbackErr).Error(
+				"Unable to roll back the snapshot creation.")
+		} else {
+			Logc(ctx).WithFields(logFields).Info("Rolled back the snapshot creation.")
+			return
+		}
+	}
+
+	txn.Operation.State = storage.OperationFailed
+	txn.Operation.LastError = err.Error()
+	txn.Operation.NextAttempt = nil
+	if saveErr := o.saveOperation(ctx, txn); saveErr != nil {
+		Logc(ctx).WithFields(logFields).WithError(saveErr).Debug("Could not save the operation's progress.")
+	}
+}
+
+// cloneVolumeOperationPhase creates a clone on the backend of its source volume, starts splitting
+// it from the source if called for, and then saves it.  A clone left behind by an interrupted
+// creation may be incomplete, so it is created again rather than split or saved.
+func (o *TridentOrchestrator) cloneVolumeOperationPhase(
+	ctx context.Context, txn *storage.VolumeTransaction,
+) (string, error) {
+
+	cloneConfig := txn.Config
+
+	o.mutex.RLock()
+	_, cloneExists := o.volumes[cloneConfig.Name]
+	sourceVolume, sourceExists := o.volumes[cloneConfig.CloneSourceVolume]
+	o.mutex.RUnlock()
+
+	if cloneExists {
+		return storage.OperationPhaseDone, nil
+	}
+	if !sourceExists {
+		Logc(ctx).WithField("volume", cloneConfig.CloneSourceVolume).Warning(
+			"Source volume for the clone operation wasn't found.")
+		return storage.OperationPhaseDone, nil
+	}
+
+	backend, unlockBackend, err := o.rlockBackend(ctx, "cloneVolumeOperationPhase", sourceVolume.BackendUUID)
+	if err != nil {
+		return "", err
+	}
+	defer unlockBackend()
+
+	// Place the clone in the same pool as its source, as cloneVolumeInitial does
+	pool := storage.NewStoragePool(backend, "")
+	if sourceVolume.Pool != drivers.UnsetPool {
+		if sourceVolumePool, ok := backend.Storage[sourceVolume.Pool]; ok {
+			pool = sourceVolumePool
+		}
+	}
+
+	onBackend := backend.Driver.Get(ctx, cloneConfig.InternalName) == nil
+
+	switch txn.Operation.Phase {
+	case storage.OperationPhaseCreating:
+		if onBackend {
+			if err = backend.RemoveVolume(ctx, cloneConfig); err != nil {
+				return "", fmt.Errorf("failed to remove incomplete clone %s from backend %s: %v",
+					cloneConfig.Name, backend.Name, err)
+			}
+		}
+		if _, err = createUnsplitClone(ctx, backend, cloneConfig, pool); err != nil {
+			return "", fmt.Errorf("failed to create cloned volume %s on backend %s: %v", cloneConfig.Name,
+				backend.Name, err)
+		}
+		return storage.OperationPhaseSplitting, nil
+
+	case storage.OperationPhaseSplitting:
+		if !onBackend {
+			return storage.OperationPhaseCreating, nil
+		}
+		if err = backend.SplitClone(ctx, cloneConfig, pool); err != nil {
+			return "", fmt.Errorf("failed to split cloned volume %s on backend %s: %v", cloneConfig.Name,
+				backend.Name, err)
+		}
+		return storage.OperationPhasePersisting, nil
+
+	case storage.OperationPhasePersisting:
+		if !onBackend {
+			return storage.OperationPhaseCreating, nil
+		}
+		vol := storage.NewVolume(cloneConfig, backend.BackendUUID, pool.Name, false)
+		backend.AddCachedVolume(vol)
+		if _, err = o.addVolumeFinish(ctx, txn, vol, backend, pool); err != nil {
+			return "", err
+		}
+		return storage.OperationPhaseDone, nil
+
+	default:
+		return "", backoff.Permanent(fmt.Errorf("unknown clone operation phase %s", txn.Operation.Phase))
+	}
+}
+
+// createUnsplitClone creates a clone on a backend without splitting it from its source, so that the
+// split may be journaled as a phase of its own.
+func createUnsplitClone(
+	ctx context.Context, backend *storage.Backend, cloneConfig *storage.VolumeConfig, pool *storage.Pool,
+) (*storage.Volume, error) {
+
+	splitOnClone := cloneConfig.SplitOnClone
+	cloneConfig.SplitOnClone = "false"
+	defer func() { cloneConfig.SplitOnClone = splitOnClone }()
+
+	return backend.CloneVolume(ctx, cloneConfig, pool, false)
+}
+
+// resizeVolumeOperationPhase resizes a volume on its backend and saves its new size.
+func (o *TridentOrchestrator) resizeVolumeOperationPhase(
+	ctx context.Context, txn *storage.VolumeTransaction,
+) (string, error) {
+
+	o.mutex.RLock()
+	volume, ok := o.volumes[txn.Config.Name]
+	o.mutex.RUnlock()
+	if !ok {
+		Logc(ctx).WithField("volume", txn.Config.Name).Warning("Volume for the resize operation wasn't found.")
+		return storage.OperationPhaseDone, nil
+	}
+
+	if err := o.resizeVolume(ctx, volume, txn.Config.Size); err != nil {
+		return "", err
+	}
+	return storage.OperationPhaseDone, nil
+}
+
+// addSnapshotOperationPhase creates a snapshot on its backend, unless it already exists there, and
+// then saves it.
+func (o *TridentOrchestrator) addSnapshotOperationPhase(
+	ctx context.Context, txn *storage.VolumeTransaction,
+) (string, error) {
+
+	snapConfig := txn.SnapshotConfig
+
+	o.mutex.RLock()
+	_, snapshotExists := o.snapshots[snapConfig.ID()]
+	volume, volumeExists := o.volumes[snapConfig.VolumeName]
+	o.mutex.RUnlock()
+
+	if snapshotExists {
+		return storage.OperationPhaseDone, nil
+	}
+	if !volumeExists {
+		Logc(ctx).WithField("volume", snapConfig.VolumeName).Warning(
+			"Volume for the snapshot operation wasn't found.")
+		return storage.OperationPhaseDone, nil
+	}
+
+	backend, unlockBackend, err := o.rlockBackend(ctx, "addSnapshotOperationPhase", volume.BackendUUID)
+	if err != nil {
+		return "", err
+	}
+	defer unlockBackend()
+
+	snapshot, err := findBackendSnapshot(ctx, backend, volume.Config, snapConfig)
+	if err != nil {
+		return "", err
+	}
+
+	switch txn.Operation.Phase {
+	case storage.OperationPhaseCreating:
+		if snapshot == nil {
+			if _, err = backend.CreateSnapshot(ctx, snapConfig, volume.Config); err != nil {
+				return "", fmt.Errorf("failed to create snapshot %s for volume %s on backend %s: %v",
+					snapConfig.Name, snapConfig.VolumeName, backend.Name, err)
+			}
+		}
+		return storage.OperationPhasePersisting, nil
+
+	case storage.OperationPhasePersisting:
+		if snapshot == nil {
+			return storage.OperationPhaseCreating, nil
+		}
+		if err = o.storeClient.AddSnapshot(ctx, snapshot); err != nil {
+			return "", err
+		}
+		o.mutex.Lock()
+		o.snapshots[snapConfig.ID()] = snapshot
+		o.mutex.Unlock()
+		return storage.OperationPhaseDone, nil
+
+	default:
+		return "", backoff.Permanent(fmt.Errorf("unknown snapshot operation phase %s", txn.Operation.Phase))
+	}
+}
+
+// deleteVolumeOperationPhase deletes a volume from its backend and from Trident.
+func (o *TridentOrchestrator) deleteVolumeOperationPhase(
+	ctx context.Context, txn *storage.VolumeTransaction,
+) (string, error) {
+
+	o.mutex.RLock()
+	_, ok := o.volumes[txn.Config.Name]
+	o.mutex.RUnlock()
+
+	if ok {
+		if err := o.deleteVolume(ctx, txn.Config.Name); err != nil {
+			return "", err
+		}
+	}
+	return storage.OperationPhaseDone, nil
+}
+
+// deleteSnapshotOperationPhase deletes a snapshot from its backend and from Trident.
+func (o *TridentOrchestrator) deleteSnapshotOperationPhase(
+	ctx context.Context, txn *storage.VolumeTransaction,
+) (string, error) {
+
+	if err := o.deleteSnapshot(ctx, txn.SnapshotConfig); err != nil && !utils.IsNotFoundError(err) {
+		return "", err
+	}
+	return storage.OperationPhaseDone, nil
+}
+
+// findBackendSnapshot returns a volume's snapshot from its backend, or nil if the snapshot does not
+// exist there.
+func findBackendSnapshot(
+	ctx context.Context, backend *storage.Backend, volConfig *storage.VolumeConfig,
+	snapConfig *storage.SnapshotConfig,
+) (*storage.Snapshot, error) {
+
+	internalName := snapConfig.InternalName
+	if internalName == "" {
+		internalName = snapConfig.Name
+	}
+
+	snapshots, err := backend.GetSnapshots(ctx, volConfig)
+	if err != nil {
+		return nil, err
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Config.InternalName == internalName {
+			return snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+// lockOperation takes the locks for the volume or snapshot named in a journaled operation's
+// transaction, as the operation itself would.
+func lockOperation(ctx context.Context, txn *storage.VolumeTransaction) func() {
+
+	switch txn.Op {
+	case storage.CloneVolume:
+		return lockVolumeAndSource(ctx, "runOperation", txn.Config.Name, txn.Config.CloneSourceVolume)
+	case storage.AddSnapshot:
+		unlockVolume := rlockVolume(ctx, "runOperation", txn.SnapshotConfig.VolumeName)
+		unlockSnapshot := lockSnapshot(ctx, "runOperation", txn.SnapshotConfig.ID())
+		return func() {
+			unlockSnapshot()
+			unlockVolume()
+		}
+	case storage.DeleteSnapshot:
+		unlockVolume := lockVolumes(ctx, "runOperation", txn.SnapshotConfig.VolumeName)
+		unlockSnapshot := lockSnapshot(ctx, "runOperation", txn.SnapshotConfig.ID())
+		return func() {
+			unlockSnapshot()
+			unlockVolume()
+		}
+	default:
+		return lockVolumes(ctx, "runOperation", txn.Config.Name)
+	}
+}
+
+// newOperationBackOff returns the backoff that spaces the attempts at a phase of a journaled
+// operation according to its retry policy.
+func newOperationBackOff(policy storage.OperationRetryPolicy) backoff.BackOff {
+
+	operationBackoff := backoff.NewExponentialBackOff()
+	operationBackoff.InitialInterval = policy.InitialInterval
+	operationBackoff.MaxInterval = policy.MaxInterval
+	operationBackoff.Multiplier = 2
+	operationBackoff.RandomizationFactor = 0.1
+	operationBackoff.MaxElapsedTime = 0
+
+	if policy.MaxAttempts > 0 {
+		return backoff.WithMaxRetries(operationBackoff, uint64(policy.MaxAttempts-1))
+	}
+	return operationBackoff
+}
+
+// copyOperationTransaction returns a copy of a transaction with its own operation record, so that
+// the record may be changed without changing any copy held by the store.
+func copyOperationTransaction(txn *storage.VolumeTransaction) *storage.VolumeTransaction {
+	record := *txn.Operation
+	txnCopy := *txn
+	txnCopy.Operation = &record
+	return &txnCopy
+}
+
+// operationLogFields returns the fields that identify a journaled operation in log messages.
+func operationLogFields(txn *storage.VolumeTransaction) log.Fields {
+	return log.Fields{
+		"op":   txn.Op,
+		"name": txn.Name(),
+	}
+}
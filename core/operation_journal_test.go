@@ -0,0 +1,524 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+)
+
+// shortenOperationRetryPolicies speeds up the retries of journaled operations for testing and
+// returns a function that restores the original policies.
+func shortenOperationRetryPolicies(maxAttempts int) func() {
+	policies := operationRetryPolicies
+	operationRetryPolicies = make(map[storage.VolumeOperation]storage.OperationRetryPolicy)
+	for op := range policies {
+		operationRetryPolicies[op] = storage.OperationRetryPolicy{
+			InitialInterval: 10 * time.Millisecond,
+			MaxInterval:     20 * time.Millisecond,
+			MaxAttempts:     maxAttempts,
+		}
+	}
+	return func() {
+		operationRetryPolicies = policies
+	}
+}
+
+// getJournalOrchestrator bootstraps an orchestrator on the given store.  Tests of the journal use a
+// store of their own, since the orchestrators of other tests share the in-memory client, and would
+// resume any journaled operation they found there.
+func getJournalOrchestrator(storeClient persistentstore.Client) *TridentOrchestrator {
+	o := NewTridentOrchestrator(storeClient)
+	if err := o.Bootstrap(); err != nil {
+		log.Fatal("Failure occurred during bootstrapping: ", err)
+	}
+	return o
+}
+
+// addJournaledSnapshotTransaction writes a snapshot creation to the journal, as if Trident had
+// stopped while the operation was in the given phase.
+func addJournaledSnapshotTransaction(
+	t *testing.T, o *TridentOrchestrator, volume *storage.VolumeExternal, snapshotName, phase string,
+) *storage.VolumeTransaction {
+
+	txn := &storage.VolumeTransaction{
+		Config:         volume.Config,
+		SnapshotConfig: generateSnapshotConfig(snapshotName, volume.Config.Name, volume.Config.InternalName),
+		Op:             storage.AddSnapshot,
+		Operation:      storage.NewOperationRecord(phase),
+	}
+	if err := o.storeClient.AddVolumeTransaction(ctx(), txn); err != nil {
+		t.Fatalf("Unable to create volume transaction: %v", err)
+	}
+	return txn
+}
+
+// waitForOperations waits until no operations remain in the journal.
+func waitForOperations(t *testing.T, o *TridentOrchestrator) {
+	assert.Eventually(t, func() bool {
+		operations, err := o.ListOperations(ctx())
+		return err == nil && len(operations) == 0
+	}, 10*time.Second, 10*time.Millisecond, "Operations did not finish")
+}
+
+func TestResumeResizeAfterRestart(t *testing.T) {
+	const (
+		backendName = "resumeResizeBackend"
+		scName      = "resumeResizeSC"
+		volumeName  = "resumeResizeVolume"
+		newSize     = "2147483648"
+	)
+
+	storeClient := persistentstore.NewInMemoryClient()
+	orchestrator := getJournalOrchestrator(storeClient)
+	addRelocationBackend(t, orchestrator, backendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	orchestrator.Stop()
+
+	// Journal a resize that was interrupted before the backend was called
+	volConfig := volume.Config.ConstructClone()
+	volConfig.Size = newSize
+	txn := &storage.VolumeTransaction{
+		Config:    volConfig,
+		Op:        storage.ResizeVolume,
+		Operation: storage.NewOperationRecord(storage.OperationPhaseResizing),
+	}
+	if err = orchestrator.storeClient.AddVolumeTransaction(ctx(), txn); err != nil {
+		t.Fatalf("Unable to create volume transaction: %v", err)
+	}
+
+	operations, err := orchestrator.ListOperations(ctx())
+	if assert.NoError(t, err) && assert.Len(t, operations, 1) {
+		assert.Equal(t, storage.ResizeVolume, operations[0].Op)
+		assert.Equal(t, volumeName, operations[0].Volume)
+		assert.Equal(t, storage.OperationPhaseResizing, operations[0].Phase)
+		assert.Equal(t, storage.OperationRunning, operations[0].State)
+	}
+
+	// The resize is completed rather than rolled back when Trident restarts
+	restarted := getJournalOrchestrator(storeClient)
+	resized, err := restarted.GetVolume(ctx(), volumeName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, newSize, resized.Config.Size)
+	}
+	persistentVolume, err := restarted.storeClient.GetVolume(ctx(), volumeName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, newSize, persistentVolume.Config.Size)
+	}
+	waitForOperations(t, restarted)
+	restarted.Stop()
+}
+
+func TestResumeSnapshotCreation(t *testing.T) {
+	const (
+		backendName = "resumeSnapshotBackend"
+		scName      = "resumeSnapshotSC"
+		volumeName  = "resumeSnapshotVolume"
+	)
+
+	orchestrator := getJournalOrchestrator(persistentstore.NewInMemoryClient())
+	defer orchestrator.Stop()
+	addRelocationBackend(t, orchestrator, backendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	driver := getFakeDriver(t, orchestrator, volume.BackendUUID)
+	backend, err := orchestrator.getBackendByBackendName(backendName)
+	if err != nil {
+		t.Fatalf("Unable to get backend: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		snapshotName    string
+		phase           string
+		createOnBackend bool
+	}{
+		{"interrupted before creation", "resumeSnapshotCreating", storage.OperationPhaseCreating, false},
+		{"interrupted during creation", "resumeSnapshotCreated", storage.OperationPhaseCreating, true},
+		{"interrupted before persisting", "resumeSnapshotPersisting", storage.OperationPhasePersisting, true},
+		{"persisting but not created", "resumeSnapshotMissing", storage.OperationPhasePersisting, false},
+	}
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		txn := addJournaledSnapshotTransaction(t, orchestrator, volume, test.snapshotName, test.phase)
+		if test.createOnBackend {
+			if _, err = backend.CreateSnapshot(ctx(), txn.SnapshotConfig, volume.Config); err != nil {
+				t.Fatalf("Unable to create snapshot: %v", err)
+			}
+		}
+
+		// Bootstrapping hands each transaction to handleFailedTransaction
+		assert.NoError(t, orchestrator.handleFailedTransaction(ctx(), txn))
+
+		snapshot, err := orchestrator.GetSnapshot(ctx(), volumeName, test.snapshotName)
+		if assert.NoError(t, err, "Snapshot was not created") {
+			assert.Equal(t, storage.SnapshotStateOnline, snapshot.State)
+		}
+		_, err = orchestrator.storeClient.GetSnapshot(ctx(), volumeName, test.snapshotName)
+		assert.NoError(t, err, "Snapshot was not persisted")
+		assert.Len(t, driver.Snapshots[volume.Config.InternalName], len(orchestrator.snapshots))
+		for snapshotID, destroyed := range driver.DestroyedSnapshots {
+			assert.False(t, destroyed, "Snapshot %s should not have been rolled back", snapshotID)
+		}
+
+		operations, err := orchestrator.ListOperations(ctx())
+		assert.NoError(t, err)
+		assert.Empty(t, operations, "Operation was not removed from the journal")
+	}
+}
+
+// addJournaledCloneTransaction writes a clone creation to the journal, as if Trident had stopped
+// while the operation was in the given phase.
+func addJournaledCloneTransaction(
+	t *testing.T, o *TridentOrchestrator, backend *storage.Backend, source *storage.VolumeExternal,
+	cloneName, phase string,
+) *storage.VolumeTransaction {
+
+	cloneConfig := source.Config.ConstructClone()
+	cloneConfig.Name = cloneName
+	cloneConfig.InternalName = ""
+	cloneConfig.CloneSourceVolume = source.Config.Name
+	cloneConfig.CloneSourceVolumeInternal = source.Config.InternalName
+	cloneConfig.SplitOnClone = "true"
+	backend.Driver.CreatePrepare(ctx(), cloneConfig)
+
+	txn := &storage.VolumeTransaction{
+		Config:    cloneConfig,
+		Op:        storage.CloneVolume,
+		Operation: storage.NewOperationRecord(phase),
+	}
+	if err := o.storeClient.AddVolumeTransaction(ctx(), txn); err != nil {
+		t.Fatalf("Unable to create volume transaction: %v", err)
+	}
+	return txn
+}
+
+func TestCloneVolumeSplitIsJournaled(t *testing.T) {
+	const (
+		backendName = "journalCloneBackend"
+		scName      = "journalCloneSC"
+		volumeName  = "journalCloneSource"
+		cloneName   = "journalClone"
+	)
+
+	orchestrator := getJournalOrchestrator(persistentstore.NewInMemoryClient())
+	defer orchestrator.Stop()
+	addRelocationBackend(t, orchestrator, backendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	driver := getFakeDriver(t, orchestrator, volume.BackendUUID)
+
+	cloneConfig := tu.GenerateVolumeConfig(cloneName, 1, scName, config.File)
+	cloneConfig.CloneSourceVolume = volumeName
+	cloneConfig.SplitOnClone = "true"
+	clone, err := orchestrator.CloneVolume(ctx(), cloneConfig)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The clone is split in a phase of its own, and its config still asks for the split
+	assert.True(t, driver.SplitClones[clone.Config.InternalName], "Clone was not split")
+	assert.Equal(t, "true", clone.Config.SplitOnClone)
+	persistentClone, err := orchestrator.storeClient.GetVolume(ctx(), cloneName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "true", persistentClone.Config.SplitOnClone)
+	}
+
+	operations, err := orchestrator.ListOperations(ctx())
+	assert.NoError(t, err)
+	assert.Empty(t, operations, "Operation was not removed from the journal")
+}
+
+func TestResumeCloneCreation(t *testing.T) {
+	const (
+		backendName = "resumeCloneBackend"
+		scName      = "resumeCloneSC"
+		volumeName  = "resumeCloneSource"
+	)
+
+	orchestrator := getJournalOrchestrator(persistentstore.NewInMemoryClient())
+	defer orchestrator.Stop()
+	addRelocationBackend(t, orchestrator, backendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	driver := getFakeDriver(t, orchestrator, volume.BackendUUID)
+	backend, err := orchestrator.getBackendByBackendName(backendName)
+	if err != nil {
+		t.Fatalf("Unable to get backend: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		cloneName       string
+		phase           string
+		createOnBackend bool
+	}{
+		{"interrupted before creation", "resumeCloneCreating", storage.OperationPhaseCreating, false},
+		{"interrupted during creation", "resumeCloneCreated", storage.OperationPhaseCreating, true},
+		{"interrupted before split", "resumeCloneSplitting", storage.OperationPhaseSplitting, true},
+		{"splitting but not created", "resumeCloneMissing", storage.OperationPhaseSplitting, false},
+		{"interrupted before persisting", "resumeClonePersisting", storage.OperationPhasePersisting, true},
+	}
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		txn := addJournaledCloneTransaction(t, orchestrator, backend, volume, test.cloneName, test.phase)
+		if test.createOnBackend {
+			if _, err = createUnsplitClone(ctx(), backend, txn.Config, backend.Storage[volume.Pool]); err != nil {
+				t.Fatalf("Unable to create clone: %v", err)
+			}
+			if test.phase == storage.OperationPhasePersisting {
+				if err = backend.SplitClone(ctx(), txn.Config, backend.Storage[volume.Pool]); err != nil {
+					t.Fatalf("Unable to split clone: %v", err)
+				}
+			}
+		}
+
+		// Bootstrapping hands each transaction to handleFailedTransaction
+		assert.NoError(t, orchestrator.handleFailedTransaction(ctx(), txn))
+
+		clone, err := orchestrator.GetVolume(ctx(), test.cloneName)
+		if assert.NoError(t, err, "Clone was not created") {
+			assert.Equal(t, volumeName, clone.Config.CloneSourceVolume)
+			assert.Equal(t, volume.Pool, clone.Pool)
+		}
+		_, err = orchestrator.storeClient.GetVolume(ctx(), test.cloneName)
+		assert.NoError(t, err, "Clone was not persisted")
+		assert.Contains(t, driver.Volumes, txn.Config.InternalName, "Clone was rolled back")
+		assert.True(t, driver.SplitClones[txn.Config.InternalName], "Clone was not split")
+
+		operations, err := orchestrator.ListOperations(ctx())
+		assert.NoError(t, err)
+		assert.Empty(t, operations, "Operation was not removed from the journal")
+	}
+}
+
+func TestRetryJournaledOperation(t *testing.T) {
+	const (
+		backendName  = "retryOperationBackend"
+		scName       = "retryOperationSC"
+		volumeName   = "retryOperationVolume"
+		snapshotName = "retryOperationSnapshot"
+	)
+
+	defer shortenOperationRetryPolicies(0)()
+
+	orchestrator := getJournalOrchestrator(persistentstore.NewInMemoryClient())
+	defer orchestrator.Stop()
+	addRelocationBackend(t, orchestrator, backendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	driver := getFakeDriver(t, orchestrator, volume.BackendUUID)
+
+	// Hide the volume from the driver, so that creating the snapshot fails
+	unlock := lockVolumes(ctx(), "TestRetryJournaledOperation", volumeName)
+	fakeVolume := driver.Volumes[volume.Config.InternalName]
+	delete(driver.Volumes, volume.Config.InternalName)
+	unlock()
+
+	txn := addJournaledSnapshotTransaction(t, orchestrator, volume, snapshotName, storage.OperationPhaseCreating)
+	assert.NoError(t, orchestrator.handleFailedTransaction(ctx(), txn))
+
+	assert.Eventually(t, func() bool {
+		operations, err := orchestrator.ListOperations(ctx())
+		return err == nil && len(operations) == 1 && operations[0].State == storage.OperationRetrying &&
+			operations[0].Attempts >= 1 && operations[0].LastError != "" && operations[0].NextAttempt != nil
+	}, 10*time.Second, 10*time.Millisecond, "Operation was not retried")
+
+	// Another request for the same snapshot is refused while the operation is being retried
+	_, err = orchestrator.CreateSnapshot(ctx(), txn.SnapshotConfig)
+	assert.Error(t, err, "Snapshot creation should be refused while the operation is in progress")
+
+	// Once the volume is back, the retried operation completes
+	unlock = lockVolumes(ctx(), "TestRetryJournaledOperation", volumeName)
+	driver.Volumes[volume.Config.InternalName] = fakeVolume
+	unlock()
+
+	waitForOperations(t, orchestrator)
+	_, err = orchestrator.GetSnapshot(ctx(), volumeName, snapshotName)
+	assert.NoError(t, err, "Snapshot was not created")
+}
+
+func TestAbandonJournaledOperation(t *testing.T) {
+	const (
+		backendName  = "abandonOperationBackend"
+		scName       = "abandonOperationSC"
+		volumeName   = "abandonOperationVolume"
+		snapshotName = "abandonOperationSnapshot"
+	)
+
+	defer shortenOperationRetryPolicies(3)()
+
+	orchestrator := getJournalOrchestrator(persistentstore.NewInMemoryClient())
+	defer orchestrator.Stop()
+	addRelocationBackend(t, orchestrator, backendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	driver := getFakeDriver(t, orchestrator, volume.BackendUUID)
+
+	unlock := lockVolumes(ctx(), "TestAbandonJournaledOperation", volumeName)
+	fakeVolume := driver.Volumes[volume.Config.InternalName]
+	delete(driver.Volumes, volume.Config.InternalName)
+	unlock()
+
+	// A snapshot creation that exhausts its attempts, and cannot be rolled back because the backend
+	// cannot be reached either, is left in the journal as failed
+	txn := addJournaledSnapshotTransaction(t, orchestrator, volume, snapshotName, storage.OperationPhaseCreating)
+	assert.NoError(t, orchestrator.handleFailedTransaction(ctx(), txn))
+
+	assert.Eventually(t, func() bool {
+		return !orchestrator.isOperationResuming(txn)
+	}, 10*time.Second, 10*time.Millisecond, "Operation is still being resumed")
+	operations, err := orchestrator.ListOperations(ctx())
+	if assert.NoError(t, err) && assert.Len(t, operations, 1) {
+		assert.Equal(t, storage.OperationFailed, operations[0].State)
+		// The first attempt is made when the transaction is found, and the rest by the retry policy
+		assert.Equal(t, 4, operations[0].Attempts)
+		assert.NotEmpty(t, operations[0].LastError)
+		assert.Nil(t, operations[0].NextAttempt)
+	}
+	_, err = orchestrator.GetSnapshot(ctx(), volumeName, snapshotName)
+	assert.Error(t, err, "Snapshot should not exist")
+
+	// The failed operation completes when it is resumed again, as the transaction monitor would
+	unlock = lockVolumes(ctx(), "TestAbandonJournaledOperation", volumeName)
+	driver.Volumes[volume.Config.InternalName] = fakeVolume
+	unlock()
+
+	orchestrator.resumeOperation(ctx(), txn)
+	waitForOperations(t, orchestrator)
+	_, err = orchestrator.GetSnapshot(ctx(), volumeName, snapshotName)
+	assert.NoError(t, err, "Snapshot was not created")
+}
+
+func TestStopJournaledOperations(t *testing.T) {
+	const (
+		backendName  = "stopOperationBackend"
+		scName       = "stopOperationSC"
+		volumeName   = "stopOperationVolume"
+		snapshotName = "stopOperationSnapshot"
+	)
+
+	defer shortenOperationRetryPolicies(0)()
+
+	orchestrator := getJournalOrchestrator(persistentstore.NewInMemoryClient())
+	defer orchestrator.Stop()
+	addRelocationBackend(t, orchestrator, backendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	driver := getFakeDriver(t, orchestrator, volume.BackendUUID)
+
+	unlock := lockVolumes(ctx(), "TestStopJournaledOperations", volumeName)
+	delete(driver.Volumes, volume.Config.InternalName)
+	unlock()
+
+	txn := addJournaledSnapshotTransaction(t, orchestrator, volume, snapshotName, storage.OperationPhaseCreating)
+	assert.NoError(t, orchestrator.handleFailedTransaction(ctx(), txn))
+
+	assert.Eventually(t, func() bool {
+		operations, err := orchestrator.ListOperations(ctx())
+		return err == nil && len(operations) == 1 && operations[0].State == storage.OperationRetrying
+	}, 10*time.Second, 10*time.Millisecond, "Operation was not retried")
+
+	// Stopping the orchestrator stops the retries and leaves the operation in the journal
+	orchestrator.StopTransactionMonitor()
+	assert.Eventually(t, func() bool {
+		return !orchestrator.isOperationResuming(txn)
+	}, 10*time.Second, 10*time.Millisecond, "Operation is still being resumed")
+
+	operations, err := orchestrator.ListOperations(ctx())
+	if assert.NoError(t, err) && assert.Len(t, operations, 1) {
+		assert.Equal(t, storage.OperationRetrying, operations[0].State)
+	}
+
+	// Nothing more is resumed until the transaction monitor starts again
+	orchestrator.resumeOperation(ctx(), txn)
+	assert.False(t, orchestrator.isOperationResuming(txn))
+}
+
+func TestJournaledOperationWithoutBackend(t *testing.T) {
+	const (
+		backendName = "noBackendOperationBackend"
+		scName      = "noBackendOperationSC"
+		volumeName  = "noBackendOperationVolume"
+	)
+
+	defer shortenOperationRetryPolicies(0)()
+
+	orchestrator := getJournalOrchestrator(persistentstore.NewInMemoryClient())
+	defer orchestrator.Stop()
+	addRelocationBackend(t, orchestrator, backendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	volume, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+
+	// Remove the backend as if it had been deleted, leaving the volume behind
+	orchestrator.mutex.Lock()
+	delete(orchestrator.backends, volume.BackendUUID)
+	orchestrator.mutex.Unlock()
+
+	resizeConfig := volume.Config.ConstructClone()
+	resizeConfig.Size = "2147483648"
+	txn := &storage.VolumeTransaction{
+		Config:    resizeConfig,
+		Op:        storage.ResizeVolume,
+		Operation: storage.NewOperationRecord(storage.OperationPhaseResizing),
+	}
+	if err = orchestrator.storeClient.AddVolumeTransaction(ctx(), txn); err != nil {
+		t.Fatalf("Unable to create volume transaction: %v", err)
+	}
+
+	// The operation is given up at once rather than retried
+	orchestrator.resumeOperation(ctx(), txn)
+	assert.Eventually(t, func() bool {
+		return !orchestrator.isOperationResuming(txn)
+	}, 10*time.Second, 10*time.Millisecond, "Operation is still being resumed")
+
+	operations, err := orchestrator.ListOperations(ctx())
+	if assert.NoError(t, err) && assert.Len(t, operations, 1) {
+		assert.Equal(t, storage.OperationFailed, operations[0].State)
+		assert.Equal(t, 0, operations[0].Attempts)
+		assert.Contains(t, operations[0].LastError, "not found")
+	}
+}
@@ -66,34 +66,52 @@ func recordTransactionTiming(txn *storage.VolumeTransaction, err *error) {
 }
 
 type TridentOrchestrator struct {
-	backends          map[string]*storage.Backend // key is UUID, not name
-	volumes           map[string]*storage.Volume
-	frontends         map[string]frontend.Plugin
-	mutex             *sync.Mutex
-	storageClasses    map[string]*storageclass.StorageClass
-	nodes             map[string]*utils.Node
-	snapshots         map[string]*storage.Snapshot
-	storeClient       persistentstore.Client
-	bootstrapped      bool
-	bootstrapError    error
-	txnMonitorTicker  *time.Ticker
-	txnMonitorChannel chan struct{}
-	txnMonitorStopped bool
+	backends            map[string]*storage.Backend // key is UUID, not name
+	volumes             map[string]*storage.Volume
+	frontends           map[string]frontend.Plugin
+	mutex               *sync.RWMutex // protects the maps above; see locks.go
+	storageClasses      map[string]*storageclass.StorageClass
+	nodes               map[string]*utils.Node
+	deletedNodes        map[string]*utils.Node // nodes deleted while volumes were still published to them
+	snapshots           map[string]*storage.Snapshot
+	storeClient         persistentstore.Client
+	bootstrapped        bool  // protected by bootstrapMutex
+	bootstrapStarted    bool  // protected by bootstrapMutex; a standby stops being one once it starts bootstrapping
+	bootstrapError      error // protected by bootstrapMutex; read it with getBootstrapError
+	bootstrapMutex      *sync.RWMutex
+	txnMonitorTicker    *time.Ticker
+	txnMonitorChannel   chan struct{}
+	txnMonitorStopped   bool
+	drainingBackends    map[string]bool               // key is UUID; protected by mutex
+	resumingOperations  map[string]context.CancelFunc // key is transaction name; protected by mutex
+	operationsStopped   bool                          // protected by mutex
+	driftMonitorTicker  *time.Ticker
+	driftMonitorChannel chan struct{}
+	driftMonitorStopped bool
+	driftReport         *storage.DriftReport
+	driftMutex          *sync.Mutex // protects driftReport and serializes drift checks
+
+	publishedNodesBackfilled bool // protected by mutex
 }
 
 // NewTridentOrchestrator returns a storage orchestrator instance
 func NewTridentOrchestrator(client persistentstore.Client) *TridentOrchestrator {
 	return &TridentOrchestrator{
-		backends:       make(map[string]*storage.Backend), // key is UUID, not name
-		volumes:        make(map[string]*storage.Volume),
-		frontends:      make(map[string]frontend.Plugin),
-		storageClasses: make(map[string]*storageclass.StorageClass),
-		nodes:          make(map[string]*utils.Node),
-		snapshots:      make(map[string]*storage.Snapshot), // key is ID, not name
-		mutex:          &sync.Mutex{},
-		storeClient:    client,
-		bootstrapped:   false,
-		bootstrapError: utils.NotReadyError(),
+		backends:           make(map[string]*storage.Backend), // key is UUID, not name
+		volumes:            make(map[string]*storage.Volume),
+		frontends:          make(map[string]frontend.Plugin),
+		storageClasses:     make(map[string]*storageclass.StorageClass),
+		nodes:              make(map[string]*utils.Node),
+		deletedNodes:       make(map[string]*utils.Node),
+		snapshots:          make(map[string]*storage.Snapshot), // key is ID, not name
+		drainingBackends:   make(map[string]bool),
+		resumingOperations: make(map[string]context.CancelFunc),
+		mutex:              &sync.RWMutex{},
+		driftMutex:         &sync.Mutex{},
+		bootstrapMutex:     &sync.RWMutex{},
+		storeClient:        client,
+		bootstrapped:       false,
+		bootstrapError:     utils.NotReadyError(),
 	}
 }
 
@@ -139,27 +157,66 @@ func (o *TridentOrchestrator) Bootstrap() error {
 		log.Warning("Trident is bootstrapping with no frontend.")
 	}
 
+	// A standby that was elected leader is no longer redirecting requests elsewhere
+	o.bootstrapMutex.Lock()
+	o.bootstrapStarted = true
+	if utils.IsNotLeaderError(o.bootstrapError) {
+		o.bootstrapError = utils.NotReadyError()
+	}
+	o.bootstrapMutex.Unlock()
+
 	// Transform persistent state, if necessary
 	if err = o.transformPersistentState(ctx); err != nil {
-		o.bootstrapError = utils.BootstrapError(err)
-		return o.bootstrapError
+		return o.setBootstrapError(utils.BootstrapError(err))
 	}
 
 	// Bootstrap state from persistent store
 	if err = o.bootstrap(ctx); err != nil {
-		o.bootstrapError = utils.BootstrapError(err)
-		return o.bootstrapError
+		return o.setBootstrapError(utils.BootstrapError(err))
 	}
 
+	o.bootstrapMutex.Lock()
+	o.bootstrapped = true
+	o.bootstrapError = nil
+	o.bootstrapMutex.Unlock()
+
 	// Start transaction monitor
 	o.StartTransactionMonitor(ctx, txnMonitorPeriod, txnMonitorMaxAge)
 
-	o.bootstrapped = true
-	o.bootstrapError = nil
+	// Start drift monitor, except with the passthrough store, where the backends are the only state
+	if !config.UsingPassthroughStore {
+		o.StartDriftMonitor(ctx, driftMonitorPeriod)
+	}
 	log.Infof("%s bootstrapped successfully.", strings.Title(config.OrchestratorName))
 	return nil
 }
 
+// setBootstrapError records why bootstrapping failed, and returns the error.
+func (o *TridentOrchestrator) setBootstrapError(err error) error {
+	o.bootstrapMutex.Lock()
+	defer o.bootstrapMutex.Unlock()
+	o.bootstrapError = err
+	return err
+}
+
+// getBootstrapError returns the error with which requests are rejected until the orchestrator is
+// bootstrapped, or nil once it is.
+func (o *TridentOrchestrator) getBootstrapError() error {
+	o.bootstrapMutex.RLock()
+	defer o.bootstrapMutex.RUnlock()
+	return o.bootstrapError
+}
+
+// SetStandby marks an orchestrator that has not started bootstrapping as a standby for the Trident
+// controller holding the leader lease, so that requests are rejected with an error naming the leader.
+func (o *TridentOrchestrator) SetStandby(leader string) {
+	o.bootstrapMutex.Lock()
+	defer o.bootstrapMutex.Unlock()
+	if !o.bootstrapStarted {
+		o.bootstrapError = utils.NotLeaderError(leader)
+	}
+}
+
 func (o *TridentOrchestrator) bootstrapBackends(ctx context.Context) error {
 
 	persistentBackends, err := o.storeClient.GetBackends(ctx)
@@ -183,7 +240,7 @@ func (o *TridentOrchestrator) bootstrapBackends(ctx context.Context) error {
 			return err
 		}
 
-		newBackendExternal, backendErr := o.addBackend(ctx, serializedConfig, b.BackendUUID, b.ConfigRef)
+		newBackendExternal, backendErr := o.addBackend(ctx, serializedConfig, b.BackendUUID, b.ConfigRef, true)
 		if backendErr == nil {
 			newBackendExternal.BackendUUID = b.BackendUUID
 		} else {
@@ -194,9 +251,9 @@ func (o *TridentOrchestrator) bootstrapBackends(ctx context.Context) error {
 				"backendErr":         backendErr.Error(),
 			}
 
-			// Trident for Docker supports one backend at a time, and the Docker volume plugin
-			// should not start if the backend fails to initialize, so return any error here.
-			if config.CurrentDriverContext == config.ContextDocker {
+			// The Docker volume plugin should not start if its only backend fails to initialize,
+			// so return any error here.  With multiple backends, the others remain usable.
+			if config.CurrentDriverContext == config.ContextDocker && len(persistentBackends) == 1 {
 				Logc(ctx).WithFields(errorLogFields).Error("Problem adding backend.")
 				return backendErr
 			}
@@ -233,6 +290,8 @@ func (o *TridentOrchestrator) bootstrapBackends(ctx context.Context) error {
 			} else {
 				if b.State == storage.Deleting {
 					newBackend.State = storage.Deleting
+				} else if b.State == storage.Maintenance {
+					newBackend.State = storage.Maintenance
 				}
 			}
 			Logc(ctx).WithFields(log.Fields{
@@ -284,6 +343,14 @@ func (o *TridentOrchestrator) bootstrapVolumes(ctx context.Context) error {
 		return err
 	}
 
+	o.loadVolumes(ctx, volumes)
+	return nil
+}
+
+// loadVolumes replaces the in-memory volumes with those read from the persistent store, and caches
+// each volume on its backend.  The caller must hold o.mutex for writing if the orchestrator is running.
+func (o *TridentOrchestrator) loadVolumes(ctx context.Context, volumes []*storage.VolumeExternal) {
+
 	// Remove extra volumes in list
 	volNames := make([]string, 0)
 	for _, v := range volumes {
@@ -300,6 +367,7 @@ func (o *TridentOrchestrator) bootstrapVolumes(ctx context.Context) error {
 		var backend *storage.Backend
 		var ok bool
 		vol := storage.NewVolume(v.Config, v.BackendUUID, v.Pool, v.Orphaned)
+		vol.PublishedNodes = v.PublishedNodes
 		o.volumes[vol.Config.Name] = vol
 
 		if backend, ok = o.backends[v.BackendUUID]; !ok {
@@ -309,7 +377,7 @@ func (o *TridentOrchestrator) bootstrapVolumes(ctx context.Context) error {
 			}).Warning("Couldn't find backend. Setting state to MissingBackend.")
 			vol.State = storage.VolumeStateMissingBackend
 		} else {
-			backend.Volumes[vol.Config.Name] = vol
+			backend.AddCachedVolume(vol)
 			if fakeDriver, ok := backend.Driver.(*fake.StorageDriver); ok {
 				fakeDriver.BootstrapVolume(ctx, vol)
 			}
@@ -327,7 +395,6 @@ func (o *TridentOrchestrator) bootstrapVolumes(ctx context.Context) error {
 		volCount++
 	}
 	Logc(ctx).Infof("Added %v existing volume(s)", volCount)
-	return nil
 }
 
 func (o *TridentOrchestrator) bootstrapSnapshots(ctx context.Context) error {
@@ -372,10 +439,7 @@ func (o *TridentOrchestrator) bootstrapVolTxns(ctx context.Context) error {
 		Logc(ctx).Warnf("Couldn't retrieve volume transaction logs: %s", err.Error())
 	}
 	for _, v := range volTxns {
-		o.mutex.Lock()
-		err = o.handleFailedTransaction(ctx, v)
-		o.mutex.Unlock()
-		if err != nil {
+		if err = o.handleFailedTransaction(ctx, v); err != nil {
 			return err
 		}
 	}
@@ -389,21 +453,50 @@ func (o *TridentOrchestrator) bootstrapNodes(ctx context.Context) error {
 		return nil
 	}
 
+	if err := o.loadNodes(ctx); err != nil {
+		return err
+	}
+	err := o.reconcileNodeAccessOnAllBackends(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// loadNodes adds the nodes in the persistent store to the in-memory map.
+func (o *TridentOrchestrator) loadNodes(ctx context.Context) error {
+
+	// Don't load nodes if we're not CSI
+	if config.CurrentDriverContext != config.ContextCSI {
+		return nil
+	}
+
 	nodes, err := o.storeClient.GetNodes(ctx)
 	if err != nil {
 		return err
 	}
 	for _, n := range nodes {
+		if n.Deleted {
+			// Keep a deleted node until the volumes still published to it have been unpublished
+			if !o.isNodePublished(n.Name) {
+				if err = o.storeClient.DeleteNode(ctx, n); err != nil {
+					return err
+				}
+				continue
+			}
+			Logc(ctx).WithFields(log.Fields{
+				"node":    n.Name,
+				"handler": "Bootstrap",
+			}).Info("Added a deleted node with published volumes.")
+			o.deletedNodes[n.Name] = n
+			continue
+		}
 		Logc(ctx).WithFields(log.Fields{
 			"node":    n.Name,
 			"handler": "Bootstrap",
 		}).Info("Added an existing node.")
 		o.nodes[n.Name] = n
 	}
-	err = o.reconcileNodeAccessOnAllBackends(ctx)
-	if err != nil {
-		return err
-	}
 	return nil
 }
 
@@ -455,12 +548,19 @@ func (o *TridentOrchestrator) Stop() {
 
 	// Stop transaction monitor
 	o.StopTransactionMonitor()
+
+	// Stop drift monitor
+	o.StopDriftMonitor()
 }
 
 // updateMetrics updates the metrics that track the core objects.
-// The caller should hold the orchestrator lock.
+// The caller must not hold the orchestrator mutex.
 func (o *TridentOrchestrator) updateMetrics() {
 
+	// The gauges are reset and rebuilt below, so updates must not be interleaved
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
 	tridentBuildInfo.WithLabelValues(config.BuildHash,
 		config.OrchestratorVersion.ShortString(),
 		config.BuildType).Set(float64(1))
@@ -510,10 +610,13 @@ func (o *TridentOrchestrator) updateMetrics() {
 	}
 }
 
+// handleFailedTransaction rolls back or completes an operation that was interrupted.  The caller must
+// hold the lock for the volume or snapshot named in the transaction, and must not hold any backend
+// lock, since the backends are locked here as needed.
 func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *storage.VolumeTransaction) error {
 
 	switch v.Op {
-	case storage.AddVolume, storage.DeleteVolume,
+	case storage.AddVolume, storage.CloneVolume, storage.DeleteVolume,
 		storage.ImportVolume, storage.ResizeVolume:
 		Logc(ctx).WithFields(log.Fields{
 			"volume":       v.Config.Name,
@@ -540,6 +643,21 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 			"backendUUID": v.VolumeCreatingConfig.BackendUUID,
 			"op":          v.Op,
 		}).Info("Processed volume creating transaction log.")
+	case storage.RelocateVolume:
+		Logc(ctx).WithFields(log.Fields{
+			"volume":            v.Config.Name,
+			"method":            v.VolumeRelocationConfig.Method,
+			"phase":             v.VolumeRelocationConfig.Phase,
+			"targetBackendUUID": v.VolumeRelocationConfig.TargetBackendUUID,
+			"targetPool":        v.VolumeRelocationConfig.TargetPool,
+			"op":                v.Op,
+		}).Info("Processed volume relocation transaction log.")
+	}
+
+	// Operations whose progress was journaled are resumed from the phase they reached
+	if isJournaledOperation(v) {
+		o.continueOperation(ctx, v)
+		return nil
 	}
 
 	switch v.Op {
@@ -549,7 +667,10 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 		// 1) Volume transaction created only
 		// 2) Volume created on backend
 		// 3) Volume created in the store.
-		if _, ok := o.volumes[v.Config.Name]; ok {
+		o.mutex.RLock()
+		_, ok := o.volumes[v.Config.Name]
+		o.mutex.RUnlock()
+		if ok {
 			// If the volume was added to the store, we will have loaded the
 			// volume into memory, and we can just delete it normally.
 			// Handles case 3)
@@ -564,16 +685,22 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 			// unique across backends, thanks to the StoragePrefix field,
 			// so this should be idempotent.
 			// Handles case 2)
-			for _, backend := range o.backends {
+			for _, backendUUID := range o.getBackendUUIDs() {
+				backend, unlockBackend, err := o.rlockBackend(ctx, "handleFailedTransaction", backendUUID)
+				if err != nil {
+					continue
+				}
 				// Backend offlining is serialized with volume creation,
 				// so we can safely skip offline backends.
-				if !backend.State.IsOnline() && !backend.State.IsDeleting() {
+				if !backend.State.IsOnline() && !backend.State.IsMaintenance() && !backend.State.IsDeleting() {
+					unlockBackend()
 					continue
 				}
 				// Volume deletion is an idempotent operation, so it's safe to
 				// delete an already deleted volume.
-
-				if err := backend.RemoveVolume(ctx, v.Config); err != nil {
+				err = backend.RemoveVolume(ctx, v.Config)
+				unlockBackend()
+				if err != nil {
 					return fmt.Errorf("error attempting to clean up volume %s from backend %s: %v", v.Config.Name,
 						backend.Name, err)
 				}
@@ -590,7 +717,10 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 		// it from the backend, we need to take any special measures only when
 		// the volume is still in the persistent store. In this case, the
 		// volume should have been loaded into memory when we bootstrapped.
-		if _, ok := o.volumes[v.Config.Name]; ok {
+		o.mutex.RLock()
+		_, ok := o.volumes[v.Config.Name]
+		o.mutex.RUnlock()
+		if ok {
 
 			err := o.deleteVolume(ctx, v.Config.Name)
 			if err != nil {
@@ -610,42 +740,9 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 		}
 
 	case storage.AddSnapshot:
-		// Regardless of whether the transaction succeeded or not, we need
-		// to roll it back.  There are three possible states:
-		// 1) Snapshot transaction created only
-		// 2) Snapshot created on backend
-		// 3) Snapshot created in persistent store
-		if _, ok := o.snapshots[v.SnapshotConfig.ID()]; ok {
-			// If the snapshot was added to the store, we will have loaded the
-			// snapshot into memory, and we can just delete it normally.
-			// Handles case 3)
-			if err := o.deleteSnapshot(ctx, v.SnapshotConfig); err != nil {
-				return fmt.Errorf("unable to clean up snapshot %s: %v", v.SnapshotConfig.Name, err)
-			}
-		} else {
-			// If the snapshot wasn't added into the store, we attempt to delete
-			// it at each backend, since we don't know where it might have landed.
-			// We're guaranteed that the volume name will be unique across backends,
-			// thanks to the StoragePrefix field, so this should be idempotent.
-			// Handles case 2)
-			for _, backend := range o.backends {
-				// Skip backends that aren't ready to accept a snapshot delete operation
-				if !backend.State.IsOnline() && !backend.State.IsDeleting() {
-					continue
-				}
-				// Snapshot deletion is an idempotent operation, so it's safe to
-				// delete an already deleted snapshot.
-				err := backend.DeleteSnapshot(ctx, v.SnapshotConfig, v.Config)
-				if err != nil && !utils.IsUnsupportedError(err) {
-					return fmt.Errorf("error attempting to clean up snapshot %s from backend %s: %v",
-						v.SnapshotConfig.Name, backend.Name, err)
-				}
-			}
-		}
-		// Finally, we need to clean up the snapshot transaction.  Necessary for all cases.
-		if err := o.DeleteVolumeTransaction(ctx, v); err != nil {
-			return fmt.Errorf("failed to clean up snapshot addition transaction: %v", err)
-		}
+		// A snapshot creation that wasn't journaled is rolled back, regardless
+		// of whether it succeeded or not.
+		return o.rollBackSnapshotCreation(ctx, v)
 
 	case storage.DeleteSnapshot:
 		// Because we remove the snapshot from persistent store after we remove
@@ -677,7 +774,9 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 		//    2b) Persistent store was updated, but we couldn't delete the
 		//        transaction object.
 		var err error
+		o.mutex.RLock()
 		vol, ok := o.volumes[v.Config.Name]
+		o.mutex.RUnlock()
 		if ok {
 			err = o.resizeVolume(ctx, vol, v.Config.Size)
 			if err != nil {
@@ -724,11 +823,16 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 			and in the legacy import case it is also not persisted.
 		*/
 
-		if volume, ok := o.volumes[v.Config.Name]; ok {
+		o.mutex.RLock()
+		volume, ok := o.volumes[v.Config.Name]
+		o.mutex.RUnlock()
+		if ok {
 			if err := o.deleteVolumeFromPersistentStoreIgnoreError(ctx, volume); err != nil {
 				return err
 			}
+			o.mutex.Lock()
 			delete(o.volumes, v.Config.Name)
+			o.mutex.Unlock()
 		}
 		if !v.Config.ImportNotManaged {
 			if err := o.resetImportedVolumeName(ctx, v.Config); err != nil {
@@ -741,6 +845,23 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 			return fmt.Errorf("failed to clean up volume addition transaction: %v", err)
 		}
 
+	case storage.RelocateVolume:
+		// The relocation saved its phase after each step, so resume it from there.  If the volume
+		// is gone, so is anything left to relocate.
+		o.mutex.RLock()
+		_, ok := o.volumes[v.Config.Name]
+		o.mutex.RUnlock()
+		if !ok {
+			Logc(ctx).WithFields(log.Fields{
+				"volume": v.Config.Name,
+			}).Warning("Volume for the relocation transaction wasn't found.")
+			if err := o.DeleteVolumeTransaction(ctx, v); err != nil {
+				return fmt.Errorf("failed to clean up volume relocation transaction: %v", err)
+			}
+			break
+		}
+		go o.relocateVolume(GenerateRequestContext(nil, "", ContextSourceInternal), v)
+
 	case storage.UpgradeVolume, storage.VolumeCreating:
 		// Do nothing
 	}
@@ -748,6 +869,57 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 	return nil
 }
 
+// rollBackSnapshotCreation removes any trace of an interrupted snapshot creation, along with its
+// transaction.  The caller must hold the locks for the snapshot and its volume.
+func (o *TridentOrchestrator) rollBackSnapshotCreation(ctx context.Context, v *storage.VolumeTransaction) error {
+
+	// There are three possible states:
+	// 1) Snapshot transaction created only
+	// 2) Snapshot created on backend
+	// 3) Snapshot created in persistent store
+	o.mutex.RLock()
+	_, ok := o.snapshots[v.SnapshotConfig.ID()]
+	o.mutex.RUnlock()
+	if ok {
+		// If the snapshot was added to the store, we will have loaded the
+		// snapshot into memory, and we can just delete it normally.
+		// Handles case 3)
+		if err := o.deleteSnapshot(ctx, v.SnapshotConfig); err != nil {
+			return fmt.Errorf("unable to clean up snapshot %s: %v", v.SnapshotConfig.Name, err)
+		}
+	} else {
+		// If the snapshot wasn't added into the store, we attempt to delete
+		// it at each backend, since we don't know where it might have landed.
+		// We're guaranteed that the volume name will be unique across backends,
+		// thanks to the StoragePrefix field, so this should be idempotent.
+		// Handles case 2)
+		for _, backendUUID := range o.getBackendUUIDs() {
+			backend, unlockBackend, err := o.rlockBackend(ctx, "rollBackSnapshotCreation", backendUUID)
+			if err != nil {
+				continue
+			}
+			// Skip backends that aren't ready to accept a snapshot delete operation
+			if !backend.State.IsOnline() && !backend.State.IsMaintenance() && !backend.State.IsDeleting() {
+				unlockBackend()
+				continue
+			}
+			// Snapshot deletion is an idempotent operation, so it's safe to
+			// delete an already deleted snapshot.
+			err = backend.DeleteSnapshot(ctx, v.SnapshotConfig, v.Config)
+			unlockBackend()
+			if err != nil && !utils.IsUnsupportedError(err) {
+				return fmt.Errorf("error attempting to clean up snapshot %s from backend %s: %v",
+					v.SnapshotConfig.Name, backend.Name, err)
+			}
+		}
+	}
+	// Finally, we need to clean up the snapshot transaction.  Necessary for all cases.
+	if err := o.DeleteVolumeTransaction(ctx, v); err != nil {
+		return fmt.Errorf("failed to clean up snapshot addition transaction: %v", err)
+	}
+	return nil
+}
+
 func (o *TridentOrchestrator) resetImportedVolumeName(ctx context.Context, volume *storage.VolumeConfig) error {
 
 	// The volume could be renamed (notManaged = false) without being persisted.
@@ -756,8 +928,14 @@ func (o *TridentOrchestrator) resetImportedVolumeName(ctx context.Context, volum
 	// landed.  We're guaranteed that the volume name will be
 	// unique across backends, thanks to the StoragePrefix field,
 	// so this should be idempotent.
-	for _, backend := range o.backends {
-		if err := backend.RenameVolume(ctx, volume, volume.ImportOriginalName); err == nil {
+	for _, backendUUID := range o.getBackendUUIDs() {
+		backend, unlockBackend, err := o.rlockBackend(ctx, "resetImportedVolumeName", backendUUID)
+		if err != nil {
+			continue
+		}
+		err = backend.RenameVolume(ctx, volume, volume.ImportOriginalName)
+		unlockBackend()
+		if err == nil {
 			return nil
 		}
 	}
@@ -800,32 +978,32 @@ func (o *TridentOrchestrator) validateBackendUpdate(
 }
 
 func (o *TridentOrchestrator) GetVersion(context.Context) (string, error) {
-	return config.OrchestratorVersion.String(), o.bootstrapError
+	// A standby is healthy even though it doesn't serve requests, so it must pass its liveness probe
+	bootstrapError := o.getBootstrapError()
+	if utils.IsNotLeaderError(bootstrapError) {
+		return config.OrchestratorVersion.String(), nil
+	}
+	return config.OrchestratorVersion.String(), bootstrapError
 }
 
 // AddBackend handles creation of a new storage backend
 func (o *TridentOrchestrator) AddBackend(ctx context.Context, configJSON, configRef string) (
 	backendExternal *storage.BackendExternal, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("backend_add", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockBackends(ctx, "AddBackend")()
 	defer o.updateMetrics()
 
-	backend, err := o.addBackend(ctx, configJSON, uuid.New().String(), configRef)
+	backend, err := o.addBackend(ctx, configJSON, uuid.New().String(), configRef, false)
 	if err != nil {
 		return backend, err
 	}
 
-	b, err := o.getBackendByBackendUUID(backend.BackendUUID)
-	if err != nil {
-		return backend, err
-	}
-	err = o.reconcileNodeAccessOnBackend(ctx, b)
+	err = o.reconcileNodeAccessOnBackend(ctx, backend.BackendUUID)
 	if err != nil {
 		return backend, err
 	}
@@ -833,10 +1011,11 @@ func (o *TridentOrchestrator) AddBackend(ctx context.Context, configJSON, config
 	return backend, nil
 }
 
-// addBackend creates a new storage backend. It assumes the mutex lock is
-// already held or not required (e.g., during bootstrapping).
+// addBackend creates a new storage backend. It assumes the backend management lock is
+// already held or not required (e.g., during bootstrapping).  A backend loaded from the
+// persistent store is only written back to the passthrough store, which holds the live backends.
 func (o *TridentOrchestrator) addBackend(ctx context.Context, configJSON,
-	backendUUID, configRef string) (backendExternal *storage.BackendExternal, err error) {
+	backendUUID, configRef string, loaded bool) (backendExternal *storage.BackendExternal, err error) {
 
 	var (
 		newBackend = true
@@ -871,7 +1050,9 @@ func (o *TridentOrchestrator) addBackend(ctx context.Context, configJSON,
 	}
 
 	// can we find this backend by UUID? (if so, it's an update)
+	o.mutex.RLock()
 	foundBackend := o.backends[backend.BackendUUID]
+	o.mutex.RUnlock()
 	if foundBackend != nil {
 		// Let the updateBackend method handle an existing backend
 		newBackend = false
@@ -879,7 +1060,9 @@ func (o *TridentOrchestrator) addBackend(ctx context.Context, configJSON,
 	}
 
 	// can we find this backend by name instead of UUID? (if so, it's also an update)
+	o.mutex.RLock()
 	foundBackend, _ = o.getBackendByBackendName(backend.Name)
+	o.mutex.RUnlock()
 	if foundBackend != nil {
 		// Let the updateBackend method handle an existing backend
 		newBackend = false
@@ -888,7 +1071,9 @@ func (o *TridentOrchestrator) addBackend(ctx context.Context, configJSON,
 
 	if configRef != "" {
 		// can we find this backend by configRef (if so, then something is wrong)
+		o.mutex.RLock()
 		foundBackend, _ := o.getBackendByConfigRef(configRef)
+		o.mutex.RUnlock()
 		if foundBackend != nil {
 			// IDEALLY WE SHOULD NOT BE HERE:
 			// If we are here it means that there already exists a backend with the
@@ -915,9 +1100,13 @@ func (o *TridentOrchestrator) addBackend(ctx context.Context, configJSON,
 		"backend.BackendUUID": backend.BackendUUID,
 		"backend.ConfigRef":   backend.ConfigRef,
 	}).Debug("Adding a new backend.")
-	if err = o.updateBackendOnPersistentStore(ctx, backend, true); err != nil {
-		return nil, err
+	if !loaded || config.UsingPassthroughStore {
+		if err = o.updateBackendOnPersistentStore(ctx, backend, true); err != nil {
+			return nil, err
+		}
 	}
+
+	o.mutex.Lock()
 	o.backends[backend.BackendUUID] = backend
 
 	// Update storage class information
@@ -927,6 +1116,8 @@ func (o *TridentOrchestrator) addBackend(ctx context.Context, configJSON,
 			classes = append(classes, sc.GetName())
 		}
 	}
+	o.mutex.Unlock()
+
 	if len(classes) == 0 {
 		Logc(ctx).WithFields(log.Fields{
 			"backend": backend.Name,
@@ -980,14 +1171,13 @@ func (o *TridentOrchestrator) validateAndCreateBackendFromConfig(ctx context.Con
 // UpdateBackend updates an existing backend.
 func (o *TridentOrchestrator) UpdateBackend(ctx context.Context, backendName, configJSON, configRef string) (
 	backendExternal *storage.BackendExternal, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("backend_update", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockBackends(ctx, "UpdateBackend")()
 	defer o.updateMetrics()
 
 	backend, err := o.updateBackend(ctx, backendName, configJSON, configRef)
@@ -995,11 +1185,7 @@ func (o *TridentOrchestrator) UpdateBackend(ctx context.Context, backendName, co
 		return backend, err
 	}
 
-	b, err := o.getBackendByBackendUUID(backend.BackendUUID)
-	if err != nil {
-		return backend, err
-	}
-	err = o.reconcileNodeAccessOnBackend(ctx, b)
+	err = o.reconcileNodeAccessOnBackend(ctx, backend.BackendUUID)
 	if err != nil {
 		return backend, err
 	}
@@ -1007,10 +1193,12 @@ func (o *TridentOrchestrator) UpdateBackend(ctx context.Context, backendName, co
 	return backend, nil
 }
 
-// updateBackend updates an existing backend. It assumes the mutex lock is already held.
+// updateBackend updates an existing backend. It assumes the backend management lock is already held.
 func (o *TridentOrchestrator) updateBackend(ctx context.Context, backendName, configJSON, configRef string) (
 	backendExternal *storage.BackendExternal, err error) {
+	o.mutex.RLock()
 	backendToUpdate, err := o.getBackendByBackendName(backendName)
+	o.mutex.RUnlock()
 	if err != nil {
 		return nil, err
 	}
@@ -1023,14 +1211,13 @@ func (o *TridentOrchestrator) updateBackend(ctx context.Context, backendName, co
 func (o *TridentOrchestrator) UpdateBackendByBackendUUID(
 	ctx context.Context, backendName, configJSON, backendUUID, configRef string) (backend *storage.BackendExternal,
 	err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("backend_update", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockBackends(ctx, "UpdateBackendByBackendUUID")()
 	defer o.updateMetrics()
 
 	backend, err = o.updateBackendByBackendUUID(ctx, backendName, configJSON, backendUUID, configRef)
@@ -1038,11 +1225,7 @@ func (o *TridentOrchestrator) UpdateBackendByBackendUUID(
 		return backend, err
 	}
 
-	b, err := o.getBackendByBackendUUID(backendUUID)
-	if err != nil {
-		return backend, err
-	}
-	err = o.reconcileNodeAccessOnBackend(ctx, b)
+	err = o.reconcileNodeAccessOnBackend(ctx, backendUUID)
 	if err != nil {
 		return backend, err
 	}
@@ -1051,7 +1234,8 @@ func (o *TridentOrchestrator) UpdateBackendByBackendUUID(
 }
 
 // TODO combine this one and the one above
-// updateBackendByBackendUUID updates an existing backend. It assumes the mutex lock is already held.
+// updateBackendByBackendUUID updates an existing backend. It assumes the backend management lock is
+// already held, and it acquires the backend's own lock while replacing the backend.
 func (o *TridentOrchestrator) updateBackendByBackendUUID(
 	ctx context.Context, backendName, configJSON, backendUUID, callingConfigRef string,
 ) (backendExternal *storage.BackendExternal, err error) {
@@ -1061,7 +1245,9 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 	)
 
 	// Check whether the backend exists.
+	o.mutex.RLock()
 	originalBackend, found := o.backends[backendUUID]
+	o.mutex.RUnlock()
 	if !found {
 		return nil, utils.NotFoundError(fmt.Sprintf("backend %v was not found", backendUUID))
 	}
@@ -1135,6 +1321,10 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 	if err = o.validateBackendUpdate(originalBackend, backend); err != nil {
 		return nil, err
 	}
+	// A backend stays in maintenance across updates to its config
+	if originalBackend.State.IsMaintenance() && backend.State.IsOnline() {
+		backend.State = storage.Maintenance
+	}
 	Logc(ctx).WithFields(log.Fields{
 		"originalBackend.Name":        originalBackend.Name,
 		"originalBackend.BackendUUID": originalBackend.BackendUUID,
@@ -1143,6 +1333,17 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 		"backendUUID":                 backendUUID,
 	}).Debug("Updating an existing backend.")
 
+	// Wait for any in-flight operations on the original backend to finish, and keep new ones
+	// from starting until the backend has been replaced.
+	lockedBackend, unlockBackend, err := o.lockBackend(ctx, "updateBackend", backendUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockBackend()
+	if lockedBackend != originalBackend {
+		return nil, utils.NotFoundError(fmt.Sprintf("backend %v was not found", backendUUID))
+	}
+
 	// Third, determine what type of backend update we're dealing with.
 	// Here are the major categories and their implications:
 	// 1) Backend rename
@@ -1165,7 +1366,9 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 		Logc(ctx).WithField("error", err).Error("Backend update failed.")
 		return nil, err
 	case updateCode.Contains(storage.BackendRename):
+		o.mutex.RLock()
 		checkingBackend, lookupErr := o.getBackendByBackendName(backend.Name)
+		o.mutex.RUnlock()
 		if lookupErr == nil {
 			// Don't rename if the name is already in use
 			err := fmt.Errorf("backend name %v is already in use by %v", backend.Name, checkingBackend.BackendUUID)
@@ -1198,8 +1401,6 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 		}
 	}
 
-	// Update the backend state in memory
-	delete(o.backends, originalBackend.BackendUUID)
 	// the fake driver needs these copied forward
 	if originalFakeDriver, ok := originalBackend.Driver.(*fake.StorageDriver); ok {
 		Logc(ctx).Debug("Using fake driver, going to copy volumes forward...")
@@ -1208,49 +1409,62 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 			Logc(ctx).Debug("Copied volumes forward.")
 		}
 	}
-	originalBackend.Terminate(ctx)
-	o.backends[backend.BackendUUID] = backend
 
-	// Update the volume state in memory
 	// Identify orphaned volumes (i.e., volumes that are not present on the
 	// new backend). Such a scenario can happen if a subset of volumes are
 	// replicated for DR or volumes get deleted out of band. Operations on
 	// such volumes are likely to fail, so here we just warn the users about
 	// such volumes and mark them as orphaned. This is a best effort activity,
 	// so it doesn't have to be part of the persistent store transaction.
-	for volName, vol := range o.volumes {
-		if vol.BackendUUID == originalBackend.BackendUUID {
-			vol.BackendUUID = backend.BackendUUID
-			updatePersistentStore := false
-			volumeExists := backend.Driver.Get(ctx, vol.Config.InternalName) == nil
-			if !volumeExists {
-				if !vol.Orphaned {
-					vol.Orphaned = true
-					updatePersistentStore = true
-					Logc(ctx).WithFields(log.Fields{
-						"volume":                  volName,
-						"vol.Config.InternalName": vol.Config.InternalName,
-						"backend":                 backend.Name,
-					}).Warn("Backend update resulted in an orphaned volume.")
-				}
-			} else {
-				if vol.Orphaned {
-					vol.Orphaned = false
-					updatePersistentStore = true
-					Logc(ctx).WithFields(log.Fields{
-						"volume":                  volName,
-						"vol.Config.InternalName": vol.Config.InternalName,
-						"backend":                 backend.Name,
-					}).Info("The volume is no longer orphaned as a result of the backend update.")
-				}
+	// The original backend's volume cache is used rather than the volume map,
+	// since a volume being deleted leaves the cache, while we hold the backend
+	// lock, before it leaves the map.
+	o.mutex.RLock()
+	internalNames := make(map[*storage.Volume]string)
+	for _, vol := range originalBackend.GetCachedVolumes() {
+		internalNames[vol] = vol.Config.InternalName
+	}
+	o.mutex.RUnlock()
+
+	volumeExists := make(map[*storage.Volume]bool, len(internalNames))
+	for vol, internalName := range internalNames {
+		volumeExists[vol] = backend.Driver.Get(ctx, internalName) == nil
+	}
+
+	// Update the backend and volume state in memory
+	o.mutex.Lock()
+	o.backends[backend.BackendUUID] = backend
+
+	updatedVolumes := make([]*storage.Volume, 0)
+	for vol, exists := range volumeExists {
+		volName := vol.Config.Name
+		if o.volumes[volName] != vol {
+			// The volume was deleted while the new backend was being checked
+			continue
+		}
+		vol.BackendUUID = backend.BackendUUID
+		if !exists {
+			if !vol.Orphaned {
+				vol.Orphaned = true
+				updatedVolumes = append(updatedVolumes, vol)
+				Logc(ctx).WithFields(log.Fields{
+					"volume":                  volName,
+					"vol.Config.InternalName": vol.Config.InternalName,
+					"backend":                 backend.Name,
+				}).Warn("Backend update resulted in an orphaned volume.")
 			}
-			if updatePersistentStore {
-				if err := o.updateVolumeOnPersistentStore(ctx, vol); err != nil {
-					return nil, err
-				}
+		} else {
+			if vol.Orphaned {
+				vol.Orphaned = false
+				updatedVolumes = append(updatedVolumes, vol)
+				Logc(ctx).WithFields(log.Fields{
+					"volume":                  volName,
+					"vol.Config.InternalName": vol.Config.InternalName,
+					"backend":                 backend.Name,
+				}).Info("The volume is no longer orphaned as a result of the backend update.")
 			}
-			o.backends[backend.BackendUUID].Volumes[volName] = vol
 		}
+		backend.AddCachedVolume(vol)
 	}
 
 	// Update storage class information
@@ -1261,6 +1475,16 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 			classes = append(classes, sc.GetName())
 		}
 	}
+	o.mutex.Unlock()
+
+	originalBackend.Terminate(ctx)
+
+	for _, vol := range updatedVolumes {
+		if err := o.updateVolumeOnPersistentStore(ctx, vol); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(classes) == 0 {
 		Logc(ctx).WithFields(log.Fields{
 			"backend": backend.Name,
@@ -1278,23 +1502,24 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 	return backend.ConstructExternal(ctx), nil
 }
 
-// UpdateBackend updates an existing backend.
+// UpdateBackendState changes the state of an existing backend.  A backend may be marked failed, or
+// placed in maintenance, during which its pools are not chosen for new volumes while its existing
+// volumes continue to be served.
 func (o *TridentOrchestrator) UpdateBackendState(ctx context.Context, backendName, backendState string) (
 	backendExternal *storage.BackendExternal, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("backend_update_state", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockBackends(ctx, "UpdateBackendState")()
 	defer o.updateMetrics()
 
 	return o.updateBackendState(ctx, backendName, backendState)
 }
 
-// updateBackend updates an existing backend. It assumes the mutex lock is already held.
+// updateBackend updates an existing backend. It assumes the backend management lock is already held.
 func (o *TridentOrchestrator) updateBackendState(ctx context.Context, backendName, backendState string) (
 	backendExternal *storage.BackendExternal, err error) {
 
@@ -1308,26 +1533,41 @@ func (o *TridentOrchestrator) updateBackendState(ctx context.Context, backendNam
 	}).Debug("UpdateBackendState")
 
 	// First, check whether the backend exists.
+	o.mutex.RLock()
 	backendUUID, err := o.getBackendUUIDByBackendName(backendName)
+	o.mutex.RUnlock()
 	if err != nil {
 		return nil, err
 	}
-	backend, found := o.backends[backendUUID]
-	if !found {
-		return nil, utils.NotFoundError(fmt.Sprintf("backend %v was not found", backendName))
-	}
 
 	newBackendState := storage.BackendState(backendState)
 
-	// Limit the command to Failed
-	if !newBackendState.IsFailed() {
+	// Limit the command to Failed, and to entering and leaving Maintenance
+	if !newBackendState.IsFailed() && !newBackendState.IsMaintenance() && !newBackendState.IsOnline() {
 		return nil, fmt.Errorf("unsupported backend state: %s", newBackendState)
 	}
 
-	if !newBackendState.IsOnline() {
+	backend, unlockBackend, err := o.lockBackend(ctx, "updateBackendState", backendUUID)
+	if err != nil {
+		return nil, utils.NotFoundError(fmt.Sprintf("backend %v was not found", backendName))
+	}
+	defer unlockBackend()
+
+	// Only an online backend may enter maintenance, and only a backend in maintenance may be
+	// returned online, since the state of any other backend reflects its driver
+	if newBackendState.IsMaintenance() && !backend.State.IsOnline() && !backend.State.IsMaintenance() {
+		return nil, fmt.Errorf("backend %s is %s, so it cannot enter maintenance", backendName, backend.State)
+	}
+	if newBackendState.IsOnline() && !backend.State.IsOnline() && !backend.State.IsMaintenance() {
+		return nil, fmt.Errorf("backend %s is %s, so it cannot be brought online", backendName, backend.State)
+	}
+
+	if newBackendState.IsFailed() {
 		backend.Terminate(ctx)
 	}
+	o.mutex.Lock()
 	backend.State = newBackendState
+	o.mutex.Unlock()
 
 	return backend.ConstructExternal(ctx), o.storeClient.UpdateBackend(ctx, backend)
 }
@@ -1361,6 +1601,19 @@ func (o *TridentOrchestrator) getBackendByConfigRef(configRef string) (*storage.
 	return nil, utils.NotFoundError(fmt.Sprintf("backend based on configRef '%v' was not found", configRef))
 }
 
+// getBackendUUIDs returns the UUIDs of all backends known to the orchestrator.
+func (o *TridentOrchestrator) getBackendUUIDs() []string {
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	backendUUIDs := make([]string, 0, len(o.backends))
+	for backendUUID := range o.backends {
+		backendUUIDs = append(backendUUIDs, backendUUID)
+	}
+	return backendUUIDs
+}
+
 func (o *TridentOrchestrator) getBackendByBackendUUID(backendUUID string) (*storage.Backend, error) {
 	backend := o.backends[backendUUID]
 	if backend != nil {
@@ -1372,14 +1625,14 @@ func (o *TridentOrchestrator) getBackendByBackendUUID(backendUUID string) (*stor
 func (o *TridentOrchestrator) GetBackend(ctx context.Context, backendName string) (
 	backendExternal *storage.BackendExternal, err error) {
 
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("backend_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	backendUUID, err := o.getBackendUUIDByBackendName(backendName)
 	if err != nil {
@@ -1403,14 +1656,14 @@ func (o *TridentOrchestrator) GetBackend(ctx context.Context, backendName string
 func (o *TridentOrchestrator) GetBackendByBackendUUID(ctx context.Context, backendUUID string) (
 	backendExternal *storage.BackendExternal, err error) {
 
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("backend_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	backend, err := o.getBackendByBackendUUID(backendUUID)
 	if err != nil {
@@ -1431,17 +1684,17 @@ func (o *TridentOrchestrator) GetBackendByBackendUUID(ctx context.Context, backe
 func (o *TridentOrchestrator) ListBackends(ctx context.Context) (
 	backendExternals []*storage.BackendExternal, err error) {
 
-	if o.bootstrapError != nil {
+	if err := o.getBootstrapError(); err != nil {
 		Logc(ctx).WithFields(log.Fields{
-			"bootstrapError": o.bootstrapError,
+			"bootstrapError": err,
 		}).Warn("ListBackends error")
-		return nil, o.bootstrapError
+		return nil, err
 	}
 
 	defer recordTiming("backend_list", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	Logc(ctx).Debugf("About to list backends: %v", o.backends)
 	backends := make([]*storage.BackendExternal, 0)
@@ -1453,20 +1706,21 @@ func (o *TridentOrchestrator) ListBackends(ctx context.Context) (
 
 func (o *TridentOrchestrator) DeleteBackend(ctx context.Context, backendName string) (err error) {
 
-	if o.bootstrapError != nil {
+	if err := o.getBootstrapError(); err != nil {
 		Logc(ctx).WithFields(log.Fields{
-			"bootstrapError": o.bootstrapError,
+			"bootstrapError": err,
 		}).Warn("DeleteBackend error")
-		return o.bootstrapError
+		return err
 	}
 
 	defer recordTiming("backend_delete", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockBackends(ctx, "DeleteBackend")()
 	defer o.updateMetrics()
 
+	o.mutex.RLock()
 	backendUUID, err := o.getBackendUUIDByBackendName(backendName)
+	o.mutex.RUnlock()
 	if err != nil {
 		return err
 	}
@@ -1476,22 +1730,23 @@ func (o *TridentOrchestrator) DeleteBackend(ctx context.Context, backendName str
 func (o *TridentOrchestrator) DeleteBackendByBackendUUID(ctx context.Context, backendName, backendUUID string) (
 	err error) {
 
-	if o.bootstrapError != nil {
+	if err := o.getBootstrapError(); err != nil {
 		Logc(ctx).WithFields(log.Fields{
-			"bootstrapError": o.bootstrapError,
+			"bootstrapError": err,
 		}).Warn("DeleteBackend error")
-		return o.bootstrapError
+		return err
 	}
 
 	defer recordTiming("backend_delete", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockBackends(ctx, "DeleteBackendByBackendUUID")()
 	defer o.updateMetrics()
 
 	return o.deleteBackendByBackendUUID(ctx, backendName, backendUUID)
 }
 
+// deleteBackendByBackendUUID deletes a backend, or marks it for deletion if it still has volumes.
+// It assumes the backend management lock is already held.
 func (o *TridentOrchestrator) deleteBackendByBackendUUID(ctx context.Context, backendName, backendUUID string) error {
 
 	Logc(ctx).WithFields(log.Fields{
@@ -1499,10 +1754,11 @@ func (o *TridentOrchestrator) deleteBackendByBackendUUID(ctx context.Context, ba
 		"backendUUID": backendUUID,
 	}).Debug("deleteBackendByBackendUUID")
 
-	backend, found := o.backends[backendUUID]
-	if !found {
+	backend, unlockBackend, err := o.lockBackend(ctx, "deleteBackend", backendUUID)
+	if err != nil {
 		return utils.NotFoundError(fmt.Sprintf("backend %s not found", backendName))
 	}
+	defer unlockBackend()
 
 	// Do not allow deletion of TridentBackendConfig-based backends using tridentctl
 	if backend.ConfigRef != "" {
@@ -1519,6 +1775,7 @@ func (o *TridentOrchestrator) deleteBackendByBackendUUID(ctx context.Context, ba
 		}
 	}
 
+	o.mutex.Lock()
 	backend.Online = false // TODO eventually remove
 	backend.State = storage.Deleting
 	storageClasses := make(map[string]*storageclass.StorageClass)
@@ -1531,9 +1788,14 @@ func (o *TridentOrchestrator) deleteBackendByBackendUUID(ctx context.Context, ba
 	for _, sc := range storageClasses {
 		sc.RemovePoolsForBackend(backend)
 	}
-	if !backend.HasVolumes() {
-		backend.Terminate(ctx)
+	hasVolumes := backend.HasVolumes()
+	if !hasVolumes {
 		delete(o.backends, backendUUID)
+	}
+	o.mutex.Unlock()
+
+	if !hasVolumes {
+		backend.Terminate(ctx)
 		return o.storeClient.DeleteBackend(ctx, backend)
 	}
 	Logc(ctx).WithFields(log.Fields{
@@ -1550,14 +1812,14 @@ func (o *TridentOrchestrator) deleteBackendByBackendUUID(ctx context.Context, ba
 func (o *TridentOrchestrator) RemoveBackendConfigRef(ctx context.Context, backendUUID, configRef string) (err error) {
 	defer recordTiming("backend_update", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockBackends(ctx, "RemoveBackendConfigRef")()
 	defer o.updateMetrics()
 
-	var b *storage.Backend
-	if b, err = o.getBackendByBackendUUID(backendUUID); err != nil {
+	b, unlockBackend, err := o.lockBackend(ctx, "RemoveBackendConfigRef", backendUUID)
+	if err != nil {
 		return utils.NotFoundError(fmt.Sprintf("backend with UUID '%s' not found", backendUUID))
 	}
+	defer unlockBackend()
 
 	if b.ConfigRef != "" {
 		if b.ConfigRef != configRef {
@@ -1565,7 +1827,9 @@ func (o *TridentOrchestrator) RemoveBackendConfigRef(ctx context.Context, backen
 				" with UUID '%s'", configRef, b.ConfigRef, backendUUID)
 		}
 
+		o.mutex.Lock()
 		b.ConfigRef = ""
+		o.mutex.Unlock()
 	}
 
 	return o.storeClient.UpdateBackend(ctx, b)
@@ -1574,19 +1838,21 @@ func (o *TridentOrchestrator) RemoveBackendConfigRef(ctx context.Context, backen
 func (o *TridentOrchestrator) AddVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) (
 	externalVol *storage.VolumeExternal, err error) {
 
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("volume_add", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockVolumes(ctx, "AddVolume", volumeConfig.Name)()
 	defer o.updateMetrics()
 
 	volumeConfig.Version = config.OrchestratorAPIVersion
 
-	if _, ok := o.volumes[volumeConfig.Name]; ok {
+	o.mutex.RLock()
+	_, ok := o.volumes[volumeConfig.Name]
+	o.mutex.RUnlock()
+	if ok {
 		return nil, fmt.Errorf("volume %s already exists", volumeConfig.Name)
 	}
 
@@ -1602,9 +1868,58 @@ func (o *TridentOrchestrator) AddVolume(ctx context.Context, volumeConfig *stora
 	return o.addVolumeInitial(ctx, volumeConfig)
 }
 
+// storagePoolUsage returns a function that reports the live utilization of a storage pool for use by
+// placement policies when placing a volume with the specified attributes.
+func (o *TridentOrchestrator) storagePoolUsage(volAttributes map[string]sa.Request) storageclass.PoolUsageFunc {
+	return func(ctx context.Context, pool *storage.Pool) *storageclass.PoolUsage {
+		return o.getStoragePoolUsage(ctx, pool, volAttributes)
+	}
+}
+
+// getStoragePoolUsage returns the live utilization of a storage pool for use by placement policies.
+// Capacity is summed across the physical pools reported by the backend that could host a volume with
+// the specified attributes, and the volume count includes all volumes provisioned from the pool.  The
+// caller must not hold a backend lock.
+func (o *TridentOrchestrator) getStoragePoolUsage(
+	ctx context.Context, pool *storage.Pool, volAttributes map[string]sa.Request,
+) *storageclass.PoolUsage {
+
+	backend, unlockBackend, err := o.rlockBackend(ctx, "getStoragePoolUsage", pool.Backend.BackendUUID)
+	if err != nil {
+		return nil
+	}
+	defer unlockBackend()
+	if backend != pool.Backend {
+		// The backend was replaced, so this pool is stale
+		return nil
+	}
+
+	poolCapacities, err := pool.Backend.GetPoolCapacity(ctx, pool, volAttributes)
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"backend": pool.Backend.Name,
+			"pool":    pool.Name,
+		}).WithError(err).Warning("Could not get storage pool usage.")
+		return nil
+	}
+
+	usage := &storageclass.PoolUsage{}
+	for _, poolCapacity := range poolCapacities {
+		usage.TotalBytes += poolCapacity.TotalBytes
+		usage.AvailableBytes += poolCapacity.AvailableBytes
+	}
+	for _, vol := range pool.Backend.GetCachedVolumes() {
+		if vol.Pool == pool.Name {
+			usage.VolumeCount++
+		}
+	}
+
+	return usage
+}
+
 // addVolumeInitial continues the volume creation operation.
-// This method should only be called from AddVolume, as it does not take locks or otherwise do much validation
-// of the volume config.
+// This method should only be called from AddVolume, as it expects the volume lock to be held and does not
+// do much validation of the volume config.
 func (o *TridentOrchestrator) addVolumeInitial(
 	ctx context.Context, volumeConfig *storage.VolumeConfig,
 ) (externalVol *storage.VolumeExternal, err error) {
@@ -1622,11 +1937,16 @@ func (o *TridentOrchestrator) addVolumeInitial(
 		return nil, err
 	}
 
+	o.mutex.RLock()
 	sc, ok := o.storageClasses[volumeConfig.StorageClass]
+	o.mutex.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("unknown storage class: %s", volumeConfig.StorageClass)
 	}
-	pools := sc.GetStoragePoolsForProtocolByBackend(ctx, protocol, volumeConfig.RequisiteTopologies, volumeConfig.PreferredTopologies)
+	// Backends order the physical pools behind each storage pool according to the same policy
+	volumeConfig.PlacementPolicy = sc.GetPlacementPolicy(ctx).Name()
+	pools := sc.GetStoragePoolsForProtocolByBackend(ctx, protocol, volumeConfig.RequisiteTopologies,
+		volumeConfig.PreferredTopologies, o.storagePoolUsage(sc.GetAttributes()))
 	if len(pools) == 0 {
 		return nil, fmt.Errorf("no available backends for storage class %s", volumeConfig.StorageClass)
 	}
@@ -1640,6 +1960,14 @@ func (o *TridentOrchestrator) addVolumeInitial(
 		return nil, err
 	}
 
+	// Hold the lock on the backend being tried, including while cleaning up after it
+	var unlockBackend func()
+	defer func() {
+		if unlockBackend != nil {
+			unlockBackend()
+		}
+	}()
+
 	// Recovery functions in case of error
 	defer func() {
 		err = o.addVolumeCleanup(ctx, err, backend, vol, txn, volumeConfig)
@@ -1653,16 +1981,35 @@ func (o *TridentOrchestrator) addVolumeInitial(
 	errorMessages := make([]string, 0)
 	ineligibleBackends := make(map[string]struct{})
 
-	// The pool lists are already shuffled, so just try them in order.
+	// The pool lists are already ordered by the storage class placement policy, so just try them in order.
 	// The loop terminates when creation on all matching pools has failed.
 	for _, pool = range pools {
 
-		backend = pool.Backend
+		if unlockBackend != nil {
+			unlockBackend()
+			unlockBackend = nil
+		}
 
 		// If the pool's backend cannot possibly work, skip trying
-		if _, ok := ineligibleBackends[backend.BackendUUID]; ok {
+		if _, ok := ineligibleBackends[pool.Backend.BackendUUID]; ok {
+			continue
+		}
+
+		lockedBackend, unlock, lockErr := o.rlockBackend(ctx, "addVolume", pool.Backend.BackendUUID)
+		if lockErr != nil {
 			continue
 		}
+		unlockBackend = unlock
+		if lockedBackend != pool.Backend {
+			// The backend was updated after the pools were selected, so use the equivalent pool on
+			// the updated backend, if it still satisfies the storage class
+			updatedPool, ok := lockedBackend.Storage[pool.Name]
+			if !ok || !sc.Matches(ctx, updatedPool) {
+				continue
+			}
+			pool = updatedPool
+		}
+		backend = pool.Backend
 
 		// CreatePrepare has a side effect that updates the volumeConfig with the backend-specific internal name
 		backend.Driver.CreatePrepare(ctx, volumeConfig)
@@ -1721,8 +2068,8 @@ func (o *TridentOrchestrator) addVolumeInitial(
 }
 
 // addVolumeRetry continues a volume creation operation that previously failed with a VolumeCreatingError.
-// This method should only be called from AddVolume, as it does not take locks or otherwise do much validation
-// of the volume config.
+// This method should only be called from AddVolume, as it expects the volume lock to be held and does not
+// do much validation of the volume config.
 func (o *TridentOrchestrator) addVolumeRetry(
 	ctx context.Context, txn *storage.VolumeTransaction,
 ) (externalVol *storage.VolumeExternal, err error) {
@@ -1739,14 +2086,15 @@ func (o *TridentOrchestrator) addVolumeRetry(
 
 	volumeConfig := &txn.VolumeCreatingConfig.VolumeConfig
 
-	backend, found := o.backends[txn.VolumeCreatingConfig.BackendUUID]
-	if !found {
+	backend, unlockBackend, err := o.rlockBackend(ctx, "addVolumeRetry", txn.VolumeCreatingConfig.BackendUUID)
+	if err != nil {
 		// Should never get here but just to be safe
 		return nil, utils.NotFoundError(fmt.Sprintf("backend %s for volume %s not found",
 			txn.VolumeCreatingConfig.BackendUUID, volumeConfig.Name))
 	}
+	defer unlockBackend()
 
-	pool, found = backend.Storage[txn.VolumeCreatingConfig.Pool]
+	pool, found := backend.Storage[txn.VolumeCreatingConfig.Pool]
 	if !found {
 		return nil, utils.NotFoundError(fmt.Sprintf("pool %s for backend %s not found",
 			txn.VolumeCreatingConfig.Pool, txn.VolumeCreatingConfig.BackendUUID))
@@ -1812,8 +2160,10 @@ func (o *TridentOrchestrator) addVolumeFinish(
 	}
 
 	// Update internal cache and return external form of the new volume
+	o.mutex.Lock()
 	o.volumes[vol.Config.Name] = vol
 	externalVol = vol.ConstructExternal()
+	o.mutex.Unlock()
 	return externalVol, nil
 }
 
@@ -1821,17 +2171,19 @@ func (o *TridentOrchestrator) CloneVolume(
 	ctx context.Context, volumeConfig *storage.VolumeConfig,
 ) (externalVol *storage.VolumeExternal, err error) {
 
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("volume_clone", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockVolumeAndSource(ctx, "CloneVolume", volumeConfig.Name, volumeConfig.CloneSourceVolume)()
 	defer o.updateMetrics()
 
-	if _, ok := o.volumes[volumeConfig.Name]; ok {
+	o.mutex.RLock()
+	_, ok := o.volumes[volumeConfig.Name]
+	o.mutex.RUnlock()
+	if ok {
 		return nil, fmt.Errorf("volume %s already exists", volumeConfig.Name)
 	}
 
@@ -1860,11 +2212,20 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 		txn     *storage.VolumeTransaction
 	)
 
-	// Get the source volume
+	// Get the source volume, working from a copy since a backend update may modify it
+	o.mutex.RLock()
 	sourceVolume, found := o.volumes[volumeConfig.CloneSourceVolume]
+	if found {
+		sourceVolumeCopy := *sourceVolume
+		sourceVolume = &sourceVolumeCopy
+	}
+	o.mutex.RUnlock()
 	if !found {
 		return nil, utils.NotFoundError(fmt.Sprintf("source volume not found: %s", volumeConfig.CloneSourceVolume))
 	}
+	if err = o.checkVolumeRelocation(ctx, sourceVolume, true); err != nil {
+		return nil, err
+	}
 
 	if volumeConfig.Size != "" {
 		cloneSourceVolumeSize, err := strconv.ParseInt(sourceVolume.Config.Size, 10, 64)
@@ -1904,7 +2265,8 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 	}
 
 	// Get the source backend
-	if backend, found = o.backends[sourceVolume.BackendUUID]; !found {
+	backend, unlockBackend, err := o.rlockBackend(ctx, "cloneVolumeInitial", sourceVolume.BackendUUID)
+	if err != nil {
 		// Should never get here but just to be safe
 		return nil, utils.NotFoundError(fmt.Sprintf("backend %s for the source volume not found: %s",
 			sourceVolume.BackendUUID, volumeConfig.CloneSourceVolume))
@@ -1946,25 +2308,50 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 	// Create the backend-specific internal names so they are saved in the transaction
 	backend.Driver.CreatePrepare(ctx, cloneConfig)
 
-	// Add transaction in case the operation must be rolled back later
+	// Release the backend while adding the transaction, which may need to clean up after a
+	// previous attempt on any backend
+	unlockBackend()
+
+	// Journal the clone's creation, so that an interruption leads to the clone being created, split
+	// and saved rather than rolled back
 	txn = &storage.VolumeTransaction{
-		Config: cloneConfig,
-		Op:     storage.AddVolume,
+		Config:    cloneConfig,
+		Op:        storage.CloneVolume,
+		Operation: newOperationRecord(storage.CloneVolume),
 	}
 	if err = o.AddVolumeTransaction(ctx, txn); err != nil {
 		return nil, err
 	}
 
-	// Recovery functions in case of error
-	defer func() {
-		err = o.addVolumeCleanup(ctx, err, backend, vol, txn, cloneConfig)
-	}()
-	defer func() {
-		err = o.addVolumeRetryCleanup(ctx, err, backend, pool, vol, txn, cloneConfig)
-	}()
+	lockedBackend, unlockBackend, err := o.rlockBackend(ctx, "cloneVolumeInitial", backend.BackendUUID)
+	if err != nil {
+		if txnErr := o.DeleteVolumeTransaction(ctx, txn); txnErr != nil {
+			Logc(ctx).WithError(txnErr).Warning("Could not delete volume transaction.")
+		}
+		return nil, err
+	}
+	defer unlockBackend()
 
-	// Create the clone
-	if vol, err = backend.CloneVolume(ctx, cloneConfig, pool, false); err != nil {
+	// If the backend was updated in the meantime, use the equivalent pool on the updated backend
+	if lockedBackend != backend {
+		backend = lockedBackend
+		if updatedPool, ok := backend.Storage[pool.Name]; ok {
+			pool = updatedPool
+		} else {
+			pool = storage.NewStoragePool(backend, "")
+		}
+	}
+
+	// Recovery functions in case of error
+	defer func() {
+		err = o.addVolumeCleanup(ctx, err, backend, vol, txn, cloneConfig)
+	}()
+	defer func() {
+		err = o.addVolumeRetryCleanup(ctx, err, backend, pool, vol, txn, cloneConfig)
+	}()
+
+	// Create the clone, leaving its split to the next phase
+	if vol, err = createUnsplitClone(ctx, backend, cloneConfig, pool); err != nil {
 
 		logFields := log.Fields{
 			"backend":      backend.Name,
@@ -1985,11 +2372,30 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 		return nil, fmt.Errorf("failed to create cloned volume %s on backend %s: %v",
 			cloneConfig.Name, backend.Name, err)
 	}
+	o.saveCloneOperationPhase(ctx, txn, storage.OperationPhaseSplitting)
+
+	if err = backend.SplitClone(ctx, cloneConfig, pool); err != nil {
+		return nil, fmt.Errorf("failed to split cloned volume %s on backend %s: %v",
+			cloneConfig.Name, backend.Name, err)
+	}
+	o.saveCloneOperationPhase(ctx, txn, storage.OperationPhasePersisting)
 
 	// Volume creation succeeded, so register it and return the result
 	return o.addVolumeFinish(ctx, txn, vol, backend, pool)
 }
 
+// saveCloneOperationPhase journals the phase reached by a clone's creation.  Failures are only
+// logged, since an interruption then repeats the previous phase, which is harmless.
+func (o *TridentOrchestrator) saveCloneOperationPhase(
+	ctx context.Context, txn *storage.VolumeTransaction, phase string,
+) {
+	phaseTxn := copyOperationTransaction(txn)
+	phaseTxn.Operation.Phase = phase
+	if txnErr := o.storeClient.UpdateVolumeTransaction(ctx, phaseTxn); txnErr != nil {
+		Logc(ctx).WithError(txnErr).WithField("phase", phase).Debug("Could not journal the clone creation.")
+	}
+}
+
 func (o *TridentOrchestrator) cloneVolumeRetry(
 	ctx context.Context, txn *storage.VolumeTransaction,
 ) (externalVol *storage.VolumeExternal, err error) {
@@ -2006,12 +2412,13 @@ func (o *TridentOrchestrator) cloneVolumeRetry(
 
 	cloneConfig := &txn.VolumeCreatingConfig.VolumeConfig
 
-	backend, found := o.backends[txn.VolumeCreatingConfig.BackendUUID]
-	if !found {
+	backend, unlockBackend, err := o.rlockBackend(ctx, "cloneVolumeRetry", txn.VolumeCreatingConfig.BackendUUID)
+	if err != nil {
 		// Should never get here but just to be safe
 		return nil, utils.NotFoundError(fmt.Sprintf("backend %s for volume %s not found",
 			txn.VolumeCreatingConfig.BackendUUID, cloneConfig.Name))
 	}
+	defer unlockBackend()
 
 	// Try to place the cloned volume in the same pool as the source.  This doesn't always work,
 	// as may be the case with imported volumes or virtual pools, so drivers must tolerate a nil
@@ -2066,28 +2473,28 @@ func (o *TridentOrchestrator) cloneVolumeRetry(
 func (o *TridentOrchestrator) GetVolumeExternal(ctx context.Context, volumeName string, backendName string) (
 	volExternal *storage.VolumeExternal, err error) {
 
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("volume_get_external", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
-
 	Logc(ctx).WithFields(log.Fields{
 		"originalName": volumeName,
 		"backendName":  backendName,
 	}).Debug("Orchestrator#GetVolumeExternal")
 
+	o.mutex.RLock()
 	backendUUID, err := o.getBackendUUIDByBackendName(backendName)
+	o.mutex.RUnlock()
 	if err != nil {
 		return nil, err
 	}
-	backend, ok := o.backends[backendUUID]
-	if !ok {
+	backend, unlockBackend, err := o.rlockBackend(ctx, "GetVolumeExternal", backendUUID)
+	if err != nil {
 		return nil, utils.NotFoundError(fmt.Sprintf("backend %s not found", backendName))
 	}
+	defer unlockBackend()
 
 	volExternal, err = backend.GetVolumeExternal(ctx, volumeName)
 	if err != nil {
@@ -2097,29 +2504,42 @@ func (o *TridentOrchestrator) GetVolumeExternal(ctx context.Context, volumeName
 	return volExternal, nil
 }
 
+// validateImportVolume checks whether a volume may be imported.  The caller must hold the volume lock
+// but not the lock of the backend the volume is being imported from.
 func (o *TridentOrchestrator) validateImportVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) error {
 
-	backend, err := o.getBackendByBackendUUID(volumeConfig.ImportBackendUUID)
+	backend, unlockBackend, err := o.rlockBackend(ctx, "validateImportVolume", volumeConfig.ImportBackendUUID)
 	if err != nil {
 		return fmt.Errorf("could not find backend; %v", err)
 	}
+	defer unlockBackend()
 
 	originalName := volumeConfig.ImportOriginalName
 	backendUUID := volumeConfig.ImportBackendUUID
 
-	for volumeName, volume := range o.volumes {
-		if volume.Config.InternalName == originalName && volume.BackendUUID == backendUUID {
-			return utils.FoundError(fmt.Sprintf("PV %s already exists for volume %s", originalName, volumeName))
+	// Check the request against the in-memory state
+	err = func() error {
+		o.mutex.RLock()
+		defer o.mutex.RUnlock()
+
+		for volumeName, volume := range o.volumes {
+			if volume.Config.InternalName == originalName && volume.BackendUUID == backendUUID {
+				return utils.FoundError(fmt.Sprintf("PV %s already exists for volume %s", originalName, volumeName))
+			}
 		}
-	}
 
-	sc, ok := o.storageClasses[volumeConfig.StorageClass]
-	if !ok {
-		return fmt.Errorf("unknown storage class: %s", volumeConfig.StorageClass)
-	}
+		sc, ok := o.storageClasses[volumeConfig.StorageClass]
+		if !ok {
+			return fmt.Errorf("unknown storage class: %s", volumeConfig.StorageClass)
+		}
 
-	if !sc.IsAddedToBackend(backend, volumeConfig.StorageClass) {
-		return fmt.Errorf("storageClass %s does not match any storage pools for backend %s", volumeConfig.StorageClass, backend.Name)
+		if !sc.IsAddedToBackend(backend, volumeConfig.StorageClass) {
+			return fmt.Errorf("storageClass %s does not match any storage pools for backend %s", volumeConfig.StorageClass, backend.Name)
+		}
+		return nil
+	}()
+	if err != nil {
+		return err
 	}
 
 	if backend.Driver.Get(ctx, originalName) != nil {
@@ -2162,14 +2582,13 @@ func (o *TridentOrchestrator) LegacyImportVolume(
 	createPVandPVC VolumeCallback,
 ) (externalVol *storage.VolumeExternal, err error) {
 
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("volume_import_legacy", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockVolumes(ctx, "LegacyImportVolume", volumeConfig.Name)()
 	defer o.updateMetrics()
 
 	Logc(ctx).WithFields(log.Fields{
@@ -2177,7 +2596,9 @@ func (o *TridentOrchestrator) LegacyImportVolume(
 		"backendName":  backendName,
 	}).Debug("Orchestrator#ImportVolume")
 
+	o.mutex.RLock()
 	backend, err := o.getBackendByBackendName(backendName)
+	o.mutex.RUnlock()
 	if err != nil {
 		return nil, utils.NotFoundError(fmt.Sprintf("backend %s not found: %v", backendName, err))
 	}
@@ -2196,6 +2617,15 @@ func (o *TridentOrchestrator) LegacyImportVolume(
 		return nil, fmt.Errorf("failed to add volume transaction: %v", err)
 	}
 
+	backend, unlockBackend, err := o.rlockBackend(ctx, "LegacyImportVolume", backend.BackendUUID)
+	if err != nil {
+		if txnErr := o.DeleteVolumeTransaction(ctx, volTxn); txnErr != nil {
+			Logc(ctx).WithError(txnErr).Warning("Could not delete volume transaction.")
+		}
+		return nil, utils.NotFoundError(fmt.Sprintf("backend %s not found: %v", backendName, err))
+	}
+	defer unlockBackend()
+
 	// Recover function in case or error
 	defer func() {
 		err = o.importVolumeCleanup(ctx, err, volumeConfig, volTxn)
@@ -2213,12 +2643,16 @@ func (o *TridentOrchestrator) LegacyImportVolume(
 		if err != nil {
 			return nil, fmt.Errorf("failed to persist imported volume data: %v", err)
 		}
+		o.mutex.Lock()
 		o.volumes[volumeConfig.Name] = volume
+		o.mutex.Unlock()
 	}
 
 	volExternal := volume.ConstructExternal()
 
+	o.mutex.RLock()
 	driverType, err := o.getDriverTypeForVolume(volExternal.BackendUUID)
+	o.mutex.RUnlock()
 	if err != nil {
 		return nil, fmt.Errorf("unable to determine driver type from volume %v", volExternal)
 	}
@@ -2252,8 +2686,8 @@ func (o *TridentOrchestrator) ImportVolume(
 	ctx context.Context, volumeConfig *storage.VolumeConfig,
 ) (externalVol *storage.VolumeExternal, err error) {
 
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 	if volumeConfig.ImportBackendUUID == "" {
 		return nil, fmt.Errorf("no backend specified for import")
@@ -2264,8 +2698,7 @@ func (o *TridentOrchestrator) ImportVolume(
 
 	defer recordTiming("volume_import", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockVolumes(ctx, "ImportVolume", volumeConfig.Name)()
 	defer o.updateMetrics()
 
 	Logc(ctx).WithFields(log.Fields{
@@ -2273,7 +2706,9 @@ func (o *TridentOrchestrator) ImportVolume(
 		"backendUUID":  volumeConfig.ImportBackendUUID,
 	}).Debug("Orchestrator#ImportVolume")
 
-	backend, ok := o.backends[volumeConfig.ImportBackendUUID]
+	o.mutex.RLock()
+	_, ok := o.backends[volumeConfig.ImportBackendUUID]
+	o.mutex.RUnlock()
 	if !ok {
 		return nil, utils.NotFoundError(fmt.Sprintf("backend %s not found", volumeConfig.ImportBackendUUID))
 	}
@@ -2292,6 +2727,15 @@ func (o *TridentOrchestrator) ImportVolume(
 		return nil, fmt.Errorf("failed to add volume transaction: %v", err)
 	}
 
+	backend, unlockBackend, err := o.rlockBackend(ctx, "ImportVolume", volumeConfig.ImportBackendUUID)
+	if err != nil {
+		if txnErr := o.DeleteVolumeTransaction(ctx, volTxn); txnErr != nil {
+			Logc(ctx).WithError(txnErr).Warning("Could not delete volume transaction.")
+		}
+		return nil, utils.NotFoundError(fmt.Sprintf("backend %s not found", volumeConfig.ImportBackendUUID))
+	}
+	defer unlockBackend()
+
 	// Recover function in case or error
 	defer func() {
 		err = o.importVolumeCleanup(ctx, err, volumeConfig, volTxn)
@@ -2307,11 +2751,15 @@ func (o *TridentOrchestrator) ImportVolume(
 	if err != nil {
 		return nil, fmt.Errorf("failed to persist imported volume data: %v", err)
 	}
+	o.mutex.Lock()
 	o.volumes[volumeConfig.Name] = volume
+	o.mutex.Unlock()
 
 	volExternal := volume.ConstructExternal()
 
+	o.mutex.RLock()
 	driverType, err := o.getDriverTypeForVolume(volExternal.Backend)
+	o.mutex.RUnlock()
 	if err != nil {
 		return nil, fmt.Errorf("unable to determine driver type from volume %v", volExternal)
 	}
@@ -2359,17 +2807,32 @@ func (o *TridentOrchestrator) AddVolumeTransaction(ctx context.Context, volTxn *
 		return err
 	}
 	if oldTxn != nil {
-		if oldTxn.Op != storage.UpgradeVolume && oldTxn.Op != storage.VolumeCreating {
+		if oldTxn.Op != storage.UpgradeVolume && oldTxn.Op != storage.VolumeCreating &&
+			oldTxn.Op != storage.RelocateVolume {
+
+			// A journaled operation that is being retried in the background must finish first
+			if isJournaledOperation(oldTxn) && o.isOperationResuming(oldTxn) {
+				return utils.FoundError(fmt.Sprintf("%v operation is in progress", oldTxn.Op))
+			}
+
 			err = o.handleFailedTransaction(ctx, oldTxn)
 			if err != nil {
 				return fmt.Errorf("unable to process the preexisting transaction "+
 					"for volume %s:  %v", volTxn.Config.Name, err)
 			}
+			if isJournaledOperation(oldTxn) && o.isOperationResuming(oldTxn) {
+				return utils.FoundError(fmt.Sprintf("%v operation is in progress", oldTxn.Op))
+			}
 
 			switch oldTxn.Op {
 			case storage.DeleteVolume, storage.DeleteSnapshot:
 				return fmt.Errorf("rejecting the %v transaction after successful completion "+
 					"of a preexisting %v transaction", volTxn.Op, oldTxn.Op)
+			case storage.AddSnapshot, storage.CloneVolume:
+				if isJournaledOperation(oldTxn) {
+					return fmt.Errorf("rejecting the %v transaction after successful completion "+
+						"of a preexisting %v transaction", volTxn.Op, oldTxn.Op)
+				}
 			}
 		} else {
 			return utils.FoundError("volume transaction already exists")
@@ -2403,6 +2866,12 @@ func (o *TridentOrchestrator) GetVolumeTransaction(ctx context.Context, volTxn *
 	return o.storeClient.GetExistingVolumeTransaction(ctx, volTxn)
 }
 
+// UpdateVolumeTransaction saves the progress of an operation recorded by a transaction added with
+// AddVolumeTransaction, such as the phase reached by a PV upgrade.
+func (o *TridentOrchestrator) UpdateVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error {
+	return o.storeClient.UpdateVolumeTransaction(ctx, volTxn)
+}
+
 // DeleteVolumeTransaction deletes a volume transaction created by
 // addVolumeTransaction.
 func (o *TridentOrchestrator) DeleteVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error {
@@ -2454,7 +2923,9 @@ func (o *TridentOrchestrator) addVolumeCleanup(
 	if cleanupErr != nil || txErr != nil {
 		// Remove the volume from memory, if it's there, so that the user
 		// can try to re-add.  This will trigger recovery code.
+		o.mutex.Lock()
 		delete(o.volumes, volumeConfig.Name)
+		o.mutex.Unlock()
 
 		// Report on all errors we encountered.
 		errList := make([]string, 0, 3)
@@ -2486,7 +2957,7 @@ func (o *TridentOrchestrator) addVolumeRetryCleanup(
 
 	// The volume is still creating.  There are two possible cases:
 	// 1.  The initial create failed, in which case we need to replace the
-	//     AddVolume transaction with a VolumeCreating transaction.
+	//     AddVolume or CloneVolume transaction with a VolumeCreating transaction.
 	// 2.  The create failed after one or more retries, in which case we
 	//     leave the existing VolumeCreating transaction in place.
 
@@ -2502,7 +2973,7 @@ func (o *TridentOrchestrator) addVolumeRetryCleanup(
 		return err
 	}
 
-	if existingTxn.Op == storage.AddVolume {
+	if existingTxn.Op == storage.AddVolume || existingTxn.Op == storage.CloneVolume {
 
 		creatingTxn := &storage.VolumeTransaction{
 			VolumeCreatingConfig: &storage.VolumeCreatingConfig{
@@ -2533,7 +3004,9 @@ func (o *TridentOrchestrator) importVolumeCleanup(
 		cleanupErr, txErr error
 	)
 
+	o.mutex.RLock()
 	backend, ok := o.backends[volumeConfig.ImportBackendUUID]
+	o.mutex.RUnlock()
 	if !ok {
 		return utils.NotFoundError(fmt.Sprintf("backend %s not found", volumeConfig.ImportBackendUUID))
 	}
@@ -2554,8 +3027,11 @@ func (o *TridentOrchestrator) importVolumeCleanup(
 		backend.RemoveCachedVolume(volumeConfig.Name)
 
 		// Remove volume from orchestrator cache
-		if volume, ok := o.volumes[volumeConfig.Name]; ok {
-			delete(o.volumes, volumeConfig.Name)
+		o.mutex.Lock()
+		volume, ok := o.volumes[volumeConfig.Name]
+		delete(o.volumes, volumeConfig.Name)
+		o.mutex.Unlock()
+		if ok {
 			if err = o.deleteVolumeFromPersistentStoreIgnoreError(ctx, volume); err != nil {
 				return fmt.Errorf("error occurred removing volume from persistent store; %v", err)
 			}
@@ -2584,14 +3060,14 @@ func (o *TridentOrchestrator) importVolumeCleanup(
 }
 
 func (o *TridentOrchestrator) GetVolume(ctx context.Context, volume string) (volExternal *storage.VolumeExternal, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("volume_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	vol, found := o.volumes[volume]
 	if !found {
@@ -2601,20 +3077,20 @@ func (o *TridentOrchestrator) GetVolume(ctx context.Context, volume string) (vol
 }
 
 func (o *TridentOrchestrator) GetDriverTypeForVolume(ctx context.Context, vol *storage.VolumeExternal) (string, error) {
-	if o.bootstrapError != nil {
-		return config.UnknownDriver, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return config.UnknownDriver, err
 	}
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	return o.getDriverTypeForVolume(vol.BackendUUID)
 }
 
 // getDriverTypeForVolume does the necessary work to get the driver type.  It does
 // not construct a transaction, nor does it take locks; it assumes that the
-// caller will take care of both of these.  It also assumes that the backend
-// exists in memory.
+// caller will take care of both of these, and that the caller holds the
+// orchestrator mutex at least for reading.
 func (o *TridentOrchestrator) getDriverTypeForVolume(backendUUID string) (string, error) {
 	if b, ok := o.backends[backendUUID]; ok {
 		return b.Driver.Name(), nil
@@ -2623,14 +3099,14 @@ func (o *TridentOrchestrator) getDriverTypeForVolume(backendUUID string) (string
 }
 
 func (o *TridentOrchestrator) GetVolumeType(ctx context.Context, vol *storage.VolumeExternal) (volumeType config.VolumeType, err error) {
-	if o.bootstrapError != nil {
-		return config.UnknownVolumeType, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return config.UnknownVolumeType, err
 	}
 
 	defer recordTiming("volume_get_type", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	// Since the caller has a valid VolumeExternal and we're disallowing
 	// backend deletion, we can assume that this will not hit a nil pointer.
@@ -2656,14 +3132,14 @@ func (o *TridentOrchestrator) GetVolumeType(ctx context.Context, vol *storage.Vo
 }
 
 func (o *TridentOrchestrator) ListVolumes(context.Context) (volumes []*storage.VolumeExternal, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("volume_list", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	volumes = make([]*storage.VolumeExternal, 0, len(o.volumes))
 	for _, v := range o.volumes {
@@ -2675,7 +3151,8 @@ func (o *TridentOrchestrator) ListVolumes(context.Context) (volumes []*storage.V
 	return volumes, nil
 }
 
-// volumeSnapshots returns any Snapshots for the specified volume
+// volumeSnapshots returns any Snapshots for the specified volume.  The caller must hold the
+// orchestrator mutex at least for reading.
 func (o *TridentOrchestrator) volumeSnapshots(volumeName string) ([]*storage.Snapshot, error) {
 	volume, volumeFound := o.volumes[volumeName]
 	if !volumeFound {
@@ -2692,27 +3169,31 @@ func (o *TridentOrchestrator) volumeSnapshots(volumeName string) ([]*storage.Sna
 }
 
 // deleteVolume does the necessary work to delete a volume entirely.  It does
-// not construct a transaction, nor does it take locks; it assumes that the
-// caller will take care of both of these.  It also assumes that the volume
+// not construct a transaction, nor does it take the volume lock; it assumes that
+// the caller will take care of both of these.  It also assumes that the volume
 // exists in memory.
 func (o *TridentOrchestrator) deleteVolume(ctx context.Context, volumeName string) error {
 
+	o.mutex.RLock()
 	volume := o.volumes[volumeName]
-	volumeBackend := o.backends[volume.BackendUUID]
+	backendUUID := volume.BackendUUID
 
 	// if there are any snapshots for this volume, we need to "soft" delete.
 	// only hard delete this volume when its last snapshot is deleted.
 	snapshotsForVolume, err := o.volumeSnapshots(volumeName)
+	o.mutex.RUnlock()
 	if err != nil {
 		return err
 	}
 	if len(snapshotsForVolume) > 0 {
 		Logc(ctx).WithFields(log.Fields{
 			"volume":                  volumeName,
-			"backendUUID":             volume.BackendUUID,
+			"backendUUID":             backendUUID,
 			"len(snapshotsForVolume)": len(snapshotsForVolume),
 		}).Debug("Soft deleting.")
+		o.mutex.Lock()
 		volume.State = storage.VolumeStateDeleting
+		o.mutex.Unlock()
 		if updateErr := o.updateVolumeOnPersistentStore(ctx, volume); updateErr != nil {
 			Logc(ctx).WithFields(log.Fields{
 				"volume":    volume.Config.Name,
@@ -2726,29 +3207,34 @@ func (o *TridentOrchestrator) deleteVolume(ctx context.Context, volumeName strin
 	// Note that this block will only be entered in the case that the volume
 	// is missing it's backend and the backend is nil. If the backend does not
 	// exist, delete the volume and clean up, then return.
-	if volumeBackend == nil {
+	volumeBackend, unlockBackend, err := o.rlockBackend(ctx, "deleteVolume", backendUUID)
+	if err != nil {
 		if err := o.deleteVolumeFromPersistentStoreIgnoreError(ctx, volume); err != nil {
 			return err
 		}
+		o.mutex.Lock()
 		delete(o.volumes, volumeName)
+		o.mutex.Unlock()
 		return nil
 	}
 
 	// Note that this call will only return an error if the backend actually
 	// fails to delete the volume.  If the volume does not exist on the backend,
 	// the driver will not return an error.  Thus, we're fine.
-	if err := volumeBackend.RemoveVolume(ctx, volume.Config); err != nil {
+	err = volumeBackend.RemoveVolume(ctx, volume.Config)
+	unlockBackend()
+	if err != nil {
 		if _, ok := err.(*storage.NotManagedError); !ok {
 			Logc(ctx).WithFields(log.Fields{
 				"volume":      volumeName,
-				"backendUUID": volume.BackendUUID,
+				"backendUUID": backendUUID,
 				"error":       err,
 			}).Error("Unable to delete volume from backend.")
 			return err
 		} else {
 			Logc(ctx).WithFields(log.Fields{
 				"volume":      volumeName,
-				"backendUUID": volume.BackendUUID,
+				"backendUUID": backendUUID,
 				"error":       err,
 			}).Debug("Skipping backend deletion of volume.")
 		}
@@ -2757,20 +3243,45 @@ func (o *TridentOrchestrator) deleteVolume(ctx context.Context, volumeName strin
 		return err
 	}
 
-	if volumeBackend.State.IsDeleting() && !volumeBackend.HasVolumes() {
-		if err := o.storeClient.DeleteBackend(ctx, volumeBackend); err != nil {
-			Logc(ctx).WithFields(log.Fields{
-				"backendUUID": volume.BackendUUID,
-				"volume":      volumeName,
-			}).Error("Unable to delete offline backend from the backing store" +
-				" after its last volume was deleted.  Delete the volume again" +
-				" to remove the backend.")
-			return err
-		}
-		volumeBackend.Terminate(ctx)
-		delete(o.backends, volume.BackendUUID)
+	if err := o.deleteBackendIfEmpty(ctx, backendUUID); err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"backendUUID": backendUUID,
+			"volume":      volumeName,
+		}).Error("Unable to delete offline backend from the backing store" +
+			" after its last volume was deleted.  Delete the volume again" +
+			" to remove the backend.")
+		return err
 	}
+
+	o.mutex.Lock()
 	delete(o.volumes, volumeName)
+	o.mutex.Unlock()
+	return nil
+}
+
+// deleteBackendIfEmpty removes a backend that is being deleted once its last volume is gone.
+// The caller must not hold the backend's lock.
+func (o *TridentOrchestrator) deleteBackendIfEmpty(ctx context.Context, backendUUID string) error {
+
+	backend, unlockBackend, err := o.lockBackend(ctx, "deleteBackendIfEmpty", backendUUID)
+	if err != nil {
+		// Already gone
+		return nil
+	}
+	defer unlockBackend()
+
+	if !backend.State.IsDeleting() || backend.HasVolumes() {
+		return nil
+	}
+
+	if err = o.storeClient.DeleteBackend(ctx, backend); err != nil {
+		return err
+	}
+	backend.Terminate(ctx)
+
+	o.mutex.Lock()
+	delete(o.backends, backendUUID)
+	o.mutex.Unlock()
 	return nil
 }
 
@@ -2795,30 +3306,35 @@ func (o *TridentOrchestrator) deleteVolumeFromPersistentStoreIgnoreError(
 // creating a transaction to ensure that the delete eventually completes.
 func (o *TridentOrchestrator) DeleteVolume(ctx context.Context, volumeName string) (err error) {
 
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
 	defer recordTiming("volume_delete", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockVolumes(ctx, "DeleteVolume", volumeName)()
 	defer o.updateMetrics()
 
+	o.mutex.RLock()
 	volume, ok := o.volumes[volumeName]
-	if !ok {
-		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
-	}
-	if volume.Orphaned {
+	if ok && volume.Orphaned {
 		Logc(ctx).WithFields(log.Fields{
 			"volume":      volumeName,
 			"backendUUID": volume.BackendUUID,
 		}).Warnf("Delete operation is likely to fail with an orphaned volume.")
 	}
+	o.mutex.RUnlock()
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	if err = o.checkVolumeRelocation(ctx, volume, false); err != nil {
+		return err
+	}
 
 	volTxn := &storage.VolumeTransaction{
-		Config: volume.Config,
-		Op:     storage.DeleteVolume,
+		Config:    volume.Config,
+		Op:        storage.DeleteVolume,
+		Operation: newOperationRecord(storage.DeleteVolume),
 	}
 	if err := o.AddVolumeTransaction(ctx, volTxn); err != nil {
 		return err
@@ -2849,21 +3365,21 @@ func (o *TridentOrchestrator) DeleteVolume(ctx context.Context, volumeName strin
 }
 
 func (o *TridentOrchestrator) ListVolumesByPlugin(ctx context.Context, pluginName string) (volumes []*storage.VolumeExternal, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("volume_list_by_plugin", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	volumes = make([]*storage.VolumeExternal, 0)
 	for _, backend := range o.backends {
 		if backendName := backend.GetDriverName(); pluginName != backendName {
 			continue
 		}
-		for _, vol := range backend.Volumes {
+		for _, vol := range backend.GetCachedVolumes() {
 			volumes = append(volumes, vol.ConstructExternal())
 		}
 	}
@@ -2871,20 +3387,22 @@ func (o *TridentOrchestrator) ListVolumesByPlugin(ctx context.Context, pluginNam
 }
 
 func (o *TridentOrchestrator) PublishVolume(ctx context.Context, volumeName string, publishInfo *utils.VolumePublishInfo) (err error) {
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
 	defer recordTiming("volume_publish", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockVolumes(ctx, "PublishVolume", volumeName)()
 
+	o.mutex.RLock()
 	volume, ok := o.volumes[volumeName]
 	if !ok {
+		o.mutex.RUnlock()
 		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
 	}
 	if volume.State.IsDeleting() {
+		o.mutex.RUnlock()
 		return utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
 	}
 
@@ -2892,141 +3410,391 @@ func (o *TridentOrchestrator) PublishVolume(ctx context.Context, volumeName stri
 	for _, node := range o.nodes {
 		nodes = append(nodes, node)
 	}
+	backendUUID := volume.BackendUUID
+	o.mutex.RUnlock()
+
+	if err = o.checkVolumeRelocation(ctx, volume, true); err != nil {
+		return err
+	}
+
+	backend, unlockBackend, err := o.rlockBackend(ctx, "PublishVolume", backendUUID)
+	if err != nil {
+		return err
+	}
+	defer unlockBackend()
+
 	publishInfo.Nodes = nodes
-	publishInfo.BackendUUID = volume.BackendUUID
-	return o.backends[volume.BackendUUID].PublishVolume(ctx, volume.Config, publishInfo)
-}
+	publishInfo.BackendUUID = backendUUID
+	if err = backend.PublishVolume(ctx, volume.Config, publishInfo); err != nil {
+		return err
+	}
 
-// AttachVolume mounts a volume to the local host.  This method is currently only used by Docker,
-// and it should be able to accomplish its task using only the data passed in; it should not need to
-// use the storage controller API.  It may be assumed that this method always runs on the host to
-// which the volume will be attached.
-func (o *TridentOrchestrator) AttachVolume(
-	ctx context.Context, volumeName, mountpoint string, publishInfo *utils.VolumePublishInfo,
-) (err error) {
+	// Frontends such as Docker publish to the local host without naming it
+	nodeName := publishInfo.HostName
+	if nodeName == "" && publishInfo.Localhost {
+		nodeName = localNodeName(ctx)
+	}
 
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	return o.addVolumePublishedNode(ctx, volume, nodeName)
+}
+
+// UnpublishVolume removes access to a volume from the specified node.  A volume that no longer exists
+// has nothing to unpublish, so that is not an error.
+func (o *TridentOrchestrator) UnpublishVolume(ctx context.Context, volumeName, nodeName string) (err error) {
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
-	defer recordTiming("volume_attach", &err)()
+	defer recordTiming("volume_unpublish", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockVolumes(ctx, "UnpublishVolume", volumeName)()
 
+	o.mutex.RLock()
 	volume, ok := o.volumes[volumeName]
 	if !ok {
-		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+		o.mutex.RUnlock()
+		Logc(ctx).WithField("volume", volumeName).Debug("Volume not found, nothing to unpublish.")
+		return nil
 	}
-	if volume.State.IsDeleting() {
-		return utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
+
+	publishInfo := &utils.VolumePublishInfo{
+		HostName:    nodeName,
+		BackendUUID: volume.BackendUUID,
+		Unmanaged:   volume.Config.ImportNotManaged,
 	}
 
-	hostMountpoint := mountpoint
-	isDockerPluginModeSet := false
-	if os.Getenv(config.DockerPluginModeEnvVariable) != "" {
-		isDockerPluginModeSet = true
-		hostMountpoint = filepath.Join("/host", mountpoint)
+	// A node deleted while the volume was published to it is still known by its identifiers
+	node, ok := o.nodes[nodeName]
+	if !ok {
+		node, ok = o.deletedNodes[nodeName]
+	}
+	if ok {
+		publishInfo.HostIQN = []string{node.IQN}
+		publishInfo.HostNQN = node.NQN
+		publishInfo.HostIP = node.IPs
 	}
+	_, nodeDeleted := o.deletedNodes[nodeName]
 
-	Logc(ctx).WithFields(log.Fields{
-		"volume":                volumeName,
-		"mountpoint":            mountpoint,
-		"hostMountpoint":        hostMountpoint,
-		"isDockerPluginModeSet": isDockerPluginModeSet,
-	}).Debug("Mounting volume.")
+	nodes := make([]*utils.Node, 0)
+	for _, node := range o.nodes {
+		nodes = append(nodes, node)
+	}
+	publishInfo.Nodes = nodes
+	o.mutex.RUnlock()
 
-	// Ensure mount point exists and is a directory
-	fileInfo, err := os.Lstat(hostMountpoint)
-	if os.IsNotExist(err) {
-		// Create the mount point if it doesn't exist
-		if err := os.MkdirAll(hostMountpoint, 0755); err != nil {
-			return err
-		}
-	} else if err != nil {
+	if err = o.unpublishVolumeFromBackend(ctx, volume, publishInfo); err != nil {
 		return err
-	} else if !fileInfo.IsDir() {
-		return fmt.Errorf("%v already exists and it's not a directory", hostMountpoint)
 	}
 
-	// Check if volume is already mounted
-	dfOutput, dfOuputErr := utils.GetDFOutput(ctx)
-	if dfOuputErr != nil {
-		err = fmt.Errorf("error checking if %v is already mounted: %v", mountpoint, dfOuputErr)
+	if err = o.removeVolumePublishedNode(ctx, volume, nodeName); err != nil {
 		return err
 	}
-	for _, e := range dfOutput {
-		if e.Target == mountpoint {
-			Logc(ctx).Debugf("%v is already mounted", mountpoint)
-			return nil
-		}
-	}
 
-	if publishInfo.FilesystemType == "nfs" {
-		return utils.AttachNFSVolume(ctx, volumeName, mountpoint, publishInfo)
-	} else {
-		return utils.AttachISCSIVolume(ctx, volumeName, mountpoint, publishInfo)
+	if nodeDeleted {
+		return o.removeDeletedNode(ctx, nodeName)
 	}
+	return nil
 }
 
-// DetachVolume unmounts a volume from the local host.  This method is currently only used by Docker,
-// and it should be able to accomplish its task using only the data passed in; it should not need to
-// use the storage controller API.  It may be assumed that this method always runs on the host to
-// which the volume will be attached.  It ensures the volume is already mounted, and it attempts to
-// delete the mount point.
-func (o *TridentOrchestrator) DetachVolume(ctx context.Context, volumeName, mountpoint string) (err error) {
+// unpublishVolumeFromBackend removes a node's access to a volume on the volume's backend.
+func (o *TridentOrchestrator) unpublishVolumeFromBackend(
+	ctx context.Context, volume *storage.Volume, publishInfo *utils.VolumePublishInfo,
+) error {
 
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	backend, unlockBackend, err := o.rlockBackend(ctx, "UnpublishVolume", publishInfo.BackendUUID)
+	if err != nil {
+		return utils.NotFoundError(fmt.Sprintf("backend %s not found", publishInfo.BackendUUID))
 	}
+	defer unlockBackend()
 
-	defer recordTiming("volume_detach", &err)()
-
-	volume, ok := o.volumes[volumeName]
-	if !ok {
-		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
-	}
-	if volume.State.IsDeleting() {
-		return utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
-	}
+	return backend.UnpublishVolume(ctx, volume.Config, publishInfo)
+}
 
-	Logc(ctx).WithFields(log.Fields{
-		"volume":     volumeName,
-		"mountpoint": mountpoint,
-	}).Debug("Unmounting volume.")
+// addVolumePublishedNode records that a volume is published to a node.  The volume's list of published
+// nodes is replaced rather than modified, since copies of the volume may share it.
+func (o *TridentOrchestrator) addVolumePublishedNode(ctx context.Context, volume *storage.Volume, nodeName string) error {
 
-	// Check if the mount point exists, so we know that it's attached and must be cleaned up
-	_, err = os.Stat(mountpoint)
-	if err != nil {
-		// Not attached, so nothing to do
+	o.mutex.Lock()
+	if nodeName == "" || utils.SliceContainsString(volume.PublishedNodes, nodeName) {
+		o.mutex.Unlock()
 		return nil
 	}
+	publishedNodes := make([]string, 0, len(volume.PublishedNodes)+1)
+	publishedNodes = append(publishedNodes, volume.PublishedNodes...)
+	volume.PublishedNodes = append(publishedNodes, nodeName)
+	o.mutex.Unlock()
 
-	// Unmount the volume
-	if err := utils.Umount(ctx, mountpoint); err != nil {
-		return err
+	return o.updateVolumeOnPersistentStore(ctx, volume)
+}
+
+// removeVolumePublishedNode records that a volume is no longer published to a node.
+func (o *TridentOrchestrator) removeVolumePublishedNode(
+	ctx context.Context, volume *storage.Volume, nodeName string,
+) error {
+
+	o.mutex.Lock()
+	if !utils.SliceContainsString(volume.PublishedNodes, nodeName) {
+		o.mutex.Unlock()
+		return nil
 	}
+	volume.PublishedNodes = utils.RemoveStringFromSlice(volume.PublishedNodes, nodeName)
+	o.mutex.Unlock()
 
-	// Best effort removal of the mount point
-	os.Remove(mountpoint)
-	return nil
+	return o.updateVolumeOnPersistentStore(ctx, volume)
 }
 
-// SetVolumeState sets the state of a volume to a given value
-func (o *TridentOrchestrator) SetVolumeState(
-	ctx context.Context, volumeName string, state storage.VolumeState) (err error) {
+// localNodeName returns the name under which volumes published to the local host are recorded.
+func localNodeName(ctx context.Context) string {
 
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	hostname, err := os.Hostname()
+	if err != nil {
+		Logc(ctx).WithError(err).Warning("Could not determine host name, not recording published node.")
+		return ""
 	}
+	return hostname
+}
 
-	defer recordTiming("volume_set_state", &err)()
+// BackfillPublishedNodes records the nodes to which volumes were published before Trident tracked
+// published nodes, as reported by the container orchestrator.  Nodes are only ever added here, so that
+// a volume published while the report was being gathered is not forgotten.  Once this has succeeded,
+// the published nodes of all volumes are known to be complete.
+func (o *TridentOrchestrator) BackfillPublishedNodes(
+	ctx context.Context, publishedNodes map[string][]string,
+) (err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return err
+	}
+
+	defer recordTiming("volume_backfill_published_nodes", &err)()
+
+	for volumeName, nodeNames := range publishedNodes {
+		if err = o.backfillVolumePublishedNodes(ctx, volumeName, nodeNames); err != nil {
+			return err
+		}
+	}
 
 	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.publishedNodesBackfilled = true
+	o.mutex.Unlock()
+
+	Logc(ctx).WithField("volumes", len(publishedNodes)).Info("Backfilled published nodes of volumes.")
+	return nil
+}
+
+// backfillVolumePublishedNodes records that a volume is published to each of the specified nodes.
+func (o *TridentOrchestrator) backfillVolumePublishedNodes(
+	ctx context.Context, volumeName string, nodeNames []string,
+) error {
+
+	defer lockVolumes(ctx, "BackfillPublishedNodes", volumeName)()
+
+	o.mutex.RLock()
+	volume, ok := o.volumes[volumeName]
+	o.mutex.RUnlock()
+	if !ok {
+		Logc(ctx).WithField("volume", volumeName).Debug("Volume not found, not backfilling its published nodes.")
+		return nil
+	}
+
+	for _, nodeName := range nodeNames {
+		if err := o.addVolumePublishedNode(ctx, volume, nodeName); err != nil {
+			return fmt.Errorf("could not record volume %s as published to node %s; %v", volumeName, nodeName, err)
+		}
+	}
+	return nil
+}
+
+// PublishedNodesBackfilled returns whether the published nodes of volumes published before Trident tracked
+// them have been backfilled.  Until then, a volume with no published nodes may still be in use.
+func (o *TridentOrchestrator) PublishedNodesBackfilled() bool {
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return o.publishedNodesBackfilled
+}
+
+// GetVolumeCondition returns the condition of a volume as reported by its backend.
+func (o *TridentOrchestrator) GetVolumeCondition(
+	ctx context.Context, volumeName string,
+) (condition *storage.VolumeCondition, err error) {
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("volume_condition", &err)()
+
+	defer lockVolumes(ctx, "GetVolumeCondition", volumeName)()
+
+	o.mutex.RLock()
+	volume, ok := o.volumes[volumeName]
+	if !ok {
+		o.mutex.RUnlock()
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	backendUUID := volume.BackendUUID
+	o.mutex.RUnlock()
+
+	// A volume whose backend is missing is not usable, so that is its condition rather than an error
+	backend, unlockBackend, err := o.rlockBackend(ctx, "GetVolumeCondition", backendUUID)
+	if err != nil {
+		return storage.NewAbnormalVolumeCondition("backend %s for volume %s not found", backendUUID,
+			volumeName), nil
+	}
+	defer unlockBackend()
+
+	return backend.GetVolumeCondition(ctx, volume.Config), nil
+}
+
+// AttachVolume mounts a volume to the local host.  This method is currently only used by Docker,
+// and it should be able to accomplish its task using only the data passed in; it should not need to
+// use the storage controller API.  It may be assumed that this method always runs on the host to
+// which the volume will be attached.
+func (o *TridentOrchestrator) AttachVolume(
+	ctx context.Context, volumeName, mountpoint string, publishInfo *utils.VolumePublishInfo,
+) (err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return err
+	}
+
+	defer recordTiming("volume_attach", &err)()
+
+	defer lockVolumes(ctx, "AttachVolume", volumeName)()
+
+	o.mutex.RLock()
+	volume, ok := o.volumes[volumeName]
+	o.mutex.RUnlock()
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	if volume.State.IsDeleting() {
+		return utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
+	}
+	if err = o.checkVolumeRelocation(ctx, volume, true); err != nil {
+		return err
+	}
+
+	hostMountpoint := mountpoint
+	isDockerPluginModeSet := false
+	if os.Getenv(config.DockerPluginModeEnvVariable) != "" {
+		isDockerPluginModeSet = true
+		hostMountpoint = filepath.Join("/host", mountpoint)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":                volumeName,
+		"mountpoint":            mountpoint,
+		"hostMountpoint":        hostMountpoint,
+		"isDockerPluginModeSet": isDockerPluginModeSet,
+	}).Debug("Mounting volume.")
+
+	// Ensure mount point exists and is a directory
+	fileInfo, err := os.Lstat(hostMountpoint)
+	if os.IsNotExist(err) {
+		// Create the mount point if it doesn't exist
+		if err := os.MkdirAll(hostMountpoint, 0755); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else if !fileInfo.IsDir() {
+		return fmt.Errorf("%v already exists and it's not a directory", hostMountpoint)
+	}
+
+	// Check if volume is already mounted
+	dfOutput, dfOuputErr := utils.GetDFOutput(ctx)
+	if dfOuputErr != nil {
+		err = fmt.Errorf("error checking if %v is already mounted: %v", mountpoint, dfOuputErr)
+		return err
+	}
+	for _, e := range dfOutput {
+		if e.Target == mountpoint {
+			Logc(ctx).Debugf("%v is already mounted", mountpoint)
+			return nil
+		}
+	}
+
+	if publishInfo.FilesystemType == "nfs" {
+		return utils.AttachNFSVolume(ctx, volumeName, mountpoint, publishInfo)
+	} else {
+		return utils.AttachISCSIVolume(ctx, volumeName, mountpoint, publishInfo, "")
+	}
+}
+
+// DetachVolume unmounts a volume from the local host.  This method is currently only used by Docker,
+// and it should be able to accomplish its task using only the data passed in; it should not need to
+// use the storage controller API.  It may be assumed that this method always runs on the host to
+// which the volume will be attached.  It ensures the volume is already mounted, and it attempts to
+// delete the mount point.
+func (o *TridentOrchestrator) DetachVolume(ctx context.Context, volumeName, mountpoint string) (err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return err
+	}
+
+	defer recordTiming("volume_detach", &err)()
+
+	defer lockVolumes(ctx, "DetachVolume", volumeName)()
+
+	o.mutex.RLock()
+	volume, ok := o.volumes[volumeName]
+	o.mutex.RUnlock()
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	if volume.State.IsDeleting() {
+		return utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":     volumeName,
+		"mountpoint": mountpoint,
+	}).Debug("Unmounting volume.")
+
+	// Check if the mount point exists, so we know that it's attached and must be cleaned up
+	_, err = os.Stat(mountpoint)
+	if err != nil {
+		// Not attached, so nothing to do but forget that it was published here
+		return o.removeVolumePublishedNode(ctx, volume, localNodeName(ctx))
+	}
+
+	// Unmount the volume, unless it was already unmounted while removing its devices
+	dfOutput, err := utils.GetDFOutput(ctx)
+	if err != nil {
+		return fmt.Errorf("error checking if %v is mounted: %v", mountpoint, err)
+	}
+	for _, e := range dfOutput {
+		if e.Target == mountpoint {
+			if err := utils.Umount(ctx, mountpoint); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	// Best effort removal of the mount point
+	os.Remove(mountpoint)
+
+	return o.removeVolumePublishedNode(ctx, volume, localNodeName(ctx))
+}
+
+// SetVolumeState sets the state of a volume to a given value
+func (o *TridentOrchestrator) SetVolumeState(
+	ctx context.Context, volumeName string, state storage.VolumeState) (err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return err
+	}
+
+	defer recordTiming("volume_set_state", &err)()
+
+	defer lockVolumes(ctx, "SetVolumeState", volumeName)()
 	defer o.updateMetrics()
 
+	o.mutex.RLock()
 	volume, ok := o.volumes[volumeName]
+	o.mutex.RUnlock()
 	if !ok {
 		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
 	}
@@ -3039,7 +3807,9 @@ func (o *TridentOrchestrator) SetVolumeState(
 		Logc(ctx).WithField("volume", volumeName).Errorf(msg)
 		return fmt.Errorf(msg)
 	}
+	o.mutex.Lock()
 	volume.State = state
+	o.mutex.Unlock()
 	Logc(ctx).WithField("volume", volumeName).Debugf("Volume state set to %s.", string(state))
 	return nil
 }
@@ -3056,32 +3826,42 @@ func (o *TridentOrchestrator) CreateSnapshot(
 		snapshot *storage.Snapshot
 	)
 
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("snapshot_create", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	// Snapshots of the same volume may be created concurrently, but the volume may not be
+	// modified or deleted in the meantime
+	defer rlockVolume(ctx, "CreateSnapshot", snapshotConfig.VolumeName)()
+	defer lockSnapshot(ctx, "CreateSnapshot", snapshotConfig.ID())()
 	defer o.updateMetrics()
 
+	o.mutex.RLock()
+	_, snapshotExists := o.snapshots[snapshotConfig.ID()]
+	volume, ok = o.volumes[snapshotConfig.VolumeName]
+	o.mutex.RUnlock()
+
 	// Check if the snapshot already exists
-	if _, ok := o.snapshots[snapshotConfig.ID()]; ok {
+	if snapshotExists {
 		return nil, fmt.Errorf("snapshot %s already exists", snapshotConfig.ID())
 	}
 
 	// Get the volume
-	volume, ok = o.volumes[snapshotConfig.VolumeName]
 	if !ok {
 		return nil, utils.NotFoundError(fmt.Sprintf("source volume %s not found", snapshotConfig.VolumeName))
 	}
 	if volume.State.IsDeleting() {
 		return nil, utils.VolumeDeletingError(fmt.Sprintf("source volume %s is deleting", snapshotConfig.VolumeName))
 	}
+	if err = o.checkVolumeRelocation(ctx, volume, true); err != nil {
+		return nil, err
+	}
 
 	// Get the backend
-	if backend, ok = o.backends[volume.BackendUUID]; !ok {
+	backend, unlockBackend, err := o.rlockBackend(ctx, "CreateSnapshot", volume.BackendUUID)
+	if err != nil {
 		// Should never get here but just to be safe
 		return nil, utils.NotFoundError(fmt.Sprintf("backend %s for the source volume not found: %s",
 			volume.BackendUUID, snapshotConfig.VolumeName))
@@ -3091,7 +3871,12 @@ func (o *TridentOrchestrator) CreateSnapshot(
 	snapshotConfig.VolumeInternalName = volume.Config.InternalName
 
 	// Ensure a snapshot is even possible before creating the transaction
-	if err := backend.CanSnapshot(ctx, snapshotConfig); err != nil {
+	err = backend.CanSnapshot(ctx, snapshotConfig)
+
+	// Release the backend while adding the transaction, which may need to clean up after a
+	// previous attempt on any backend
+	unlockBackend()
+	if err != nil {
 		return nil, err
 	}
 
@@ -3100,11 +3885,22 @@ func (o *TridentOrchestrator) CreateSnapshot(
 		Config:         volume.Config,
 		SnapshotConfig: snapshotConfig,
 		Op:             storage.AddSnapshot,
+		Operation:      newOperationRecord(storage.AddSnapshot),
 	}
 	if err = o.AddVolumeTransaction(ctx, txn); err != nil {
 		return nil, err
 	}
 
+	backend, unlockBackend, err = o.rlockBackend(ctx, "CreateSnapshot", volume.BackendUUID)
+	if err != nil {
+		if txnErr := o.DeleteVolumeTransaction(ctx, txn); txnErr != nil {
+			Logc(ctx).WithError(txnErr).Warning("Could not delete volume transaction.")
+		}
+		return nil, utils.NotFoundError(fmt.Sprintf("backend %s for the source volume not found: %s",
+			volume.BackendUUID, snapshotConfig.VolumeName))
+	}
+	defer unlockBackend()
+
 	// Recovery function in case of error
 	defer func() {
 		err = o.addSnapshotCleanup(ctx, err, backend, snapshot, txn, snapshotConfig)
@@ -3117,11 +3913,20 @@ func (o *TridentOrchestrator) CreateSnapshot(
 			snapshotConfig.Name, snapshotConfig.VolumeName, backend.Name, err)
 	}
 
+	// Journal the snapshot's creation, so that an interruption leads to the snapshot being saved
+	persistingTxn := copyOperationTransaction(txn)
+	persistingTxn.Operation.Phase = storage.OperationPhasePersisting
+	if txnErr := o.storeClient.UpdateVolumeTransaction(ctx, persistingTxn); txnErr != nil {
+		Logc(ctx).WithError(txnErr).Debug("Could not journal the snapshot creation.")
+	}
+
 	// Save references to new snapshot
 	if err = o.storeClient.AddSnapshot(ctx, snapshot); err != nil {
 		return nil, err
 	}
+	o.mutex.Lock()
 	o.snapshots[snapshotConfig.ID()] = snapshot
+	o.mutex.Unlock()
 
 	return snapshot.ConstructExternal(), nil
 }
@@ -3161,7 +3966,9 @@ func (o *TridentOrchestrator) addSnapshotCleanup(
 	if cleanupErr != nil || txErr != nil {
 		// Remove the snapshot from memory, if it's there, so that the user
 		// can try to re-add.  This will trigger recovery code.
+		o.mutex.Lock()
 		delete(o.snapshots, snapConfig.ID())
+		o.mutex.Unlock()
 
 		// Report on all errors we encountered.
 		errList := make([]string, 0, 3)
@@ -3181,24 +3988,28 @@ func (o *TridentOrchestrator) GetSnapshot(
 	ctx context.Context, volumeName, snapshotName string,
 ) (snapshotExternal *storage.SnapshotExternal, err error) {
 
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("snapshot_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	// The snapshot lock is needed because the snapshot may be updated
+	defer lockSnapshot(ctx, "GetSnapshot", storage.MakeSnapshotID(volumeName, snapshotName))()
 
 	return o.getSnapshot(ctx, volumeName, snapshotName, true)
 }
 
+// getSnapshot returns a snapshot, updating it from its backend if it is not yet ready.  The caller
+// must hold the snapshot lock.
 func (o *TridentOrchestrator) getSnapshot(
 	ctx context.Context, volumeName, snapshotName string, update bool,
 ) (*storage.SnapshotExternal, error) {
 
 	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+	o.mutex.RLock()
 	snapshot, found := o.snapshots[snapshotID]
+	o.mutex.RUnlock()
 	if !found {
 		return nil, utils.NotFoundError(fmt.Sprintf("snapshot %v was not found", snapshotName))
 	} else if snapshot.State != storage.SnapshotStateCreating && snapshot.State != storage.SnapshotStateUploading {
@@ -3222,21 +4033,27 @@ func (o *TridentOrchestrator) updateSnapshot(
 	}
 
 	snapshotID := snapshot.Config.ID()
+	o.mutex.RLock()
 	snapshot, ok := o.snapshots[snapshotID]
 	if !ok {
+		o.mutex.RUnlock()
 		return snapshot, utils.NotFoundError(fmt.Sprintf("snapshot %s not found on volume %s",
 			snapshot.Config.Name, snapshot.Config.VolumeName))
 	}
 
 	volume, ok := o.volumes[snapshot.Config.VolumeName]
 	if !ok {
+		o.mutex.RUnlock()
 		return snapshot, utils.NotFoundError(fmt.Sprintf("volume %s not found", snapshot.Config.VolumeName))
 	}
+	backendUUID := volume.BackendUUID
+	o.mutex.RUnlock()
 
-	backend, ok := o.backends[volume.BackendUUID]
-	if !ok {
-		return snapshot, utils.NotFoundError(fmt.Sprintf("backend %s not found", volume.BackendUUID))
+	backend, unlockBackend, err := o.rlockBackend(ctx, "updateSnapshot", backendUUID)
+	if err != nil {
+		return snapshot, utils.NotFoundError(fmt.Sprintf("backend %s not found", backendUUID))
 	}
+	defer unlockBackend()
 
 	updatedSnapshot, err := backend.GetSnapshot(ctx, snapshot.Config)
 	if err != nil {
@@ -3245,7 +4062,9 @@ func (o *TridentOrchestrator) updateSnapshot(
 
 	// If the snapshot state has changed, persist it here and in the store
 	if updatedSnapshot != nil && updatedSnapshot.State != snapshot.State {
+		o.mutex.Lock()
 		o.snapshots[snapshotID] = updatedSnapshot
+		o.mutex.Unlock()
 		if err := o.storeClient.UpdateSnapshot(ctx, updatedSnapshot); err != nil {
 			Logc(ctx).Errorf("could not update snapshot %s in persistent store; %v", snapshotID, err)
 		}
@@ -3255,31 +4074,35 @@ func (o *TridentOrchestrator) updateSnapshot(
 }
 
 // deleteSnapshot does the necessary work to delete a snapshot entirely.  It does
-// not construct a transaction, nor does it take locks; it assumes that the caller will
-// take care of both of these.
+// not construct a transaction, nor does it take the snapshot and volume locks; it
+// assumes that the caller will take care of both of these.  The volume lock must be
+// held exclusively if the volume may be hard deleted along with its last snapshot.
 func (o *TridentOrchestrator) deleteSnapshot(ctx context.Context, snapshotConfig *storage.SnapshotConfig) error {
 
 	snapshotID := snapshotConfig.ID()
-	snapshot, ok := o.snapshots[snapshotID]
-	if !ok {
+	o.mutex.RLock()
+	snapshot, snapshotFound := o.snapshots[snapshotID]
+	volume, volumeFound := o.volumes[snapshotConfig.VolumeName]
+	o.mutex.RUnlock()
+	if !snapshotFound {
 		return utils.NotFoundError(fmt.Sprintf("snapshot %s not found on volume %s",
 			snapshotConfig.Name, snapshotConfig.VolumeName))
 	}
-
-	volume, ok := o.volumes[snapshotConfig.VolumeName]
-	if !ok {
+	if !volumeFound {
 		return utils.NotFoundError(fmt.Sprintf("volume %s not found", snapshotConfig.VolumeName))
 	}
 
-	backend, ok := o.backends[volume.BackendUUID]
-	if !ok {
+	backend, unlockBackend, err := o.rlockBackend(ctx, "deleteSnapshot", volume.BackendUUID)
+	if err != nil {
 		return utils.NotFoundError(fmt.Sprintf("backend %s not found", volume.BackendUUID))
 	}
 
 	// Note that this call will only return an error if the backend actually
 	// fails to delete the snapshot.  If the snapshot does not exist on the backend,
 	// the driver will not return an error.  Thus, we're fine.
-	if err := backend.DeleteSnapshot(ctx, snapshot.Config, volume.Config); err != nil {
+	err = backend.DeleteSnapshot(ctx, snapshot.Config, volume.Config)
+	unlockBackend()
+	if err != nil {
 		Logc(ctx).WithFields(log.Fields{
 			"volume":   snapshot.Config.VolumeName,
 			"snapshot": snapshot.Config.Name,
@@ -3292,9 +4115,10 @@ func (o *TridentOrchestrator) deleteSnapshot(ctx context.Context, snapshotConfig
 		return err
 	}
 
+	o.mutex.Lock()
 	delete(o.snapshots, snapshot.ID())
-
 	snapshotsForVolume, err := o.volumeSnapshots(snapshotConfig.VolumeName)
+	o.mutex.Unlock()
 	if err != nil {
 		return err
 	}
@@ -3331,24 +4155,33 @@ func (o *TridentOrchestrator) deleteSnapshotFromPersistentStoreIgnoreError(ctx c
 // DeleteSnapshot deletes a snapshot of the given volume
 func (o *TridentOrchestrator) DeleteSnapshot(ctx context.Context, volumeName, snapshotName string) (err error) {
 
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
 	defer recordTiming("snapshot_delete", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	// The volume lock is held exclusively, since the volume is hard deleted along with its
+	// last snapshot if the volume was already soft deleted
+	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+	defer lockVolumes(ctx, "DeleteSnapshot", volumeName)()
+	defer lockSnapshot(ctx, "DeleteSnapshot", snapshotID)()
 	defer o.updateMetrics()
 
-	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+	o.mutex.RLock()
 	snapshot, ok := o.snapshots[snapshotID]
+	volume := o.volumes[volumeName]
+	var backend *storage.Backend
+	if volume != nil {
+		backend = o.backends[volume.BackendUUID]
+	}
+	o.mutex.RUnlock()
+
 	if !ok {
 		return utils.NotFoundError(fmt.Sprintf("snapshot %s not found on volume %s", snapshotName, volumeName))
 	}
 
-	volume, ok := o.volumes[volumeName]
-	if !ok {
+	if volume == nil {
 		if !snapshot.State.IsMissingVolume() {
 			return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
 		}
@@ -3361,12 +4194,13 @@ func (o *TridentOrchestrator) DeleteSnapshot(ctx context.Context, volumeName, sn
 		if err = o.deleteSnapshotFromPersistentStoreIgnoreError(ctx, snapshot); err != nil {
 			return err
 		}
+		o.mutex.Lock()
 		delete(o.snapshots, snapshot.ID())
+		o.mutex.Unlock()
 		return nil
 	}
 
-	backend, ok := o.backends[volume.BackendUUID]
-	if !ok {
+	if backend == nil {
 		if !snapshot.State.IsMissingBackend() {
 			return utils.NotFoundError(fmt.Sprintf("backend %s not found", volume.BackendUUID))
 		}
@@ -3379,7 +4213,9 @@ func (o *TridentOrchestrator) DeleteSnapshot(ctx context.Context, volumeName, sn
 		if err = o.deleteSnapshotFromPersistentStoreIgnoreError(ctx, snapshot); err != nil {
 			return err
 		}
+		o.mutex.Lock()
 		delete(o.snapshots, snapshot.ID())
+		o.mutex.Unlock()
 		return nil
 	}
 
@@ -3396,6 +4232,7 @@ func (o *TridentOrchestrator) DeleteSnapshot(ctx context.Context, volumeName, sn
 		Config:         volume.Config,
 		SnapshotConfig: snapshot.Config,
 		Op:             storage.DeleteSnapshot,
+		Operation:      newOperationRecord(storage.DeleteSnapshot),
 	}
 	if err = o.AddVolumeTransaction(ctx, volTxn); err != nil {
 		return err
@@ -3428,197 +4265,625 @@ func (o *TridentOrchestrator) DeleteSnapshot(ctx context.Context, volumeName, sn
 	return o.deleteSnapshot(ctx, snapshot.Config)
 }
 
-func (o *TridentOrchestrator) ListSnapshots(context.Context) (snapshots []*storage.SnapshotExternal, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+func (o *TridentOrchestrator) ListSnapshots(context.Context) (snapshots []*storage.SnapshotExternal, err error) {
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("snapshot_list", &err)()
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	snapshots = make([]*storage.SnapshotExternal, 0, len(o.snapshots))
+	for _, s := range o.snapshots {
+		snapshots = append(snapshots, s.ConstructExternal())
+	}
+	sort.Sort(storage.BySnapshotExternalID(snapshots))
+	return snapshots, nil
+}
+
+func (o *TridentOrchestrator) ListSnapshotsByName(ctx context.Context, snapshotName string) (snapshots []*storage.SnapshotExternal, err error) {
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("snapshot_list_by_snapshot_name", &err)()
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	snapshots = make([]*storage.SnapshotExternal, 0)
+	for _, s := range o.snapshots {
+		if s.Config.Name == snapshotName {
+			snapshots = append(snapshots, s.ConstructExternal())
+		}
+	}
+	sort.Sort(storage.BySnapshotExternalID(snapshots))
+	return snapshots, nil
+}
+
+func (o *TridentOrchestrator) ListSnapshotsForVolume(ctx context.Context, volumeName string) (snapshots []*storage.SnapshotExternal, err error) {
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("snapshot_list_by_volume_name", &err)()
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	if _, ok := o.volumes[volumeName]; !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+
+	snapshots = make([]*storage.SnapshotExternal, 0, len(o.snapshots))
+	for _, s := range o.snapshots {
+		if s.Config.VolumeName == volumeName {
+			snapshots = append(snapshots, s.ConstructExternal())
+		}
+	}
+	sort.Sort(storage.BySnapshotExternalID(snapshots))
+	return snapshots, nil
+}
+
+func (o *TridentOrchestrator) ReadSnapshotsForVolume(ctx context.Context, volumeName string) (externalSnapshots []*storage.SnapshotExternal, err error) {
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("snapshot_read_by_volume", &err)()
+
+	defer rlockVolume(ctx, "ReadSnapshotsForVolume", volumeName)()
+
+	o.mutex.RLock()
+	volume, ok := o.volumes[volumeName]
+	o.mutex.RUnlock()
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+
+	backend, unlockBackend, err := o.rlockBackend(ctx, "ReadSnapshotsForVolume", volume.BackendUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockBackend()
+
+	snapshots, err := backend.GetSnapshots(ctx, volume.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	externalSnapshots = make([]*storage.SnapshotExternal, 0)
+	for _, snapshot := range snapshots {
+		externalSnapshots = append(externalSnapshots, snapshot.ConstructExternal())
+	}
+	sort.Sort(storage.BySnapshotExternalID(externalSnapshots))
+	return externalSnapshots, nil
+}
+
+func (o *TridentOrchestrator) ReloadVolumes(ctx context.Context) (err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return err
+	}
+
+	defer recordTiming("volume_reload", &err)()
+
+	// Lock out backend changes while we reload the volumes
+	defer lockBackends(ctx, "ReloadVolumes")()
+	defer o.updateMetrics()
+
+	// Read the volumes before taking the mutex, which is never held across persistent store calls.
+	// If anything goes wrong, the original volumes remain in place.
+	volumes, err := o.storeClient.GetVolumes(ctx)
+	if err != nil {
+		Logc(ctx).Errorf("Volume reload failed, keeping original volume list: %v", err)
+		return err
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	// Clear out cached volumes in the backends and re-run the volume bootstrapping code
+	for _, backend := range o.backends {
+		backend.ClearCachedVolumes()
+	}
+	o.loadVolumes(ctx, volumes)
+
+	return nil
+}
+
+// ExportState returns an archive of everything in the persistent store.  Backend credentials are
+// encrypted with the passphrase if one is supplied, otherwise they are redacted.
+func (o *TridentOrchestrator) ExportState(
+	ctx context.Context, passphrase string,
+) (archive *persistentstore.StateArchive, err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("state_export", &err)()
+
+	// Lock out backend changes so that the archive is consistent
+	defer lockBackends(ctx, "ExportState")()
+
+	return persistentstore.ExportState(ctx, o.storeClient, passphrase)
+}
+
+// ImportState writes an archive created by ExportState to the persistent store, then loads it as
+// if Trident had just started.  This is only possible while the orchestrator has no backends,
+// volumes, or snapshots, as is the case after Trident is installed in a new cluster.
+func (o *TridentOrchestrator) ImportState(
+	ctx context.Context, archive *persistentstore.StateArchive, passphrase string,
+) (err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return err
+	}
+
+	defer recordTiming("state_import", &err)()
+
+	defer lockBackends(ctx, "ImportState")()
+	defer o.updateMetrics()
+
+	o.mutex.RLock()
+	inUse := len(o.backends) > 0 || len(o.volumes) > 0 || len(o.snapshots) > 0
+	o.mutex.RUnlock()
+	if inUse {
+		return fmt.Errorf("state may only be imported while Trident has no backends, volumes, or snapshots")
+	}
+
+	// Nodes are not imported, since the live nodes register themselves and are the only ones
+	// whose access should be reconciled
+	if err = persistentstore.ImportState(ctx, o.storeClient, archive, passphrase); err != nil {
+		return err
+	}
+
+	// Load the imported state the same way it is loaded during bootstrapping.  The backends are
+	// already in the store, so they are not persisted again as they are added.
+	if err = o.bootstrapBackends(ctx); err != nil {
+		return err
+	}
+
+	// Adding backends acquires the orchestrator mutex as needed, but the other objects are added
+	// to the in-memory maps directly, so the mutex is held for them.
+
+	o.mutex.Lock()
+	for _, f := range []func(context.Context) error{
+		o.bootstrapStorageClasses, o.bootstrapVolumes, o.bootstrapSnapshots,
+	} {
+		if err = f(ctx); err != nil {
+			break
+		}
+	}
+	o.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err = o.bootstrapVolTxns(ctx); err != nil {
+		return err
+	}
+
+	return o.reconcileNodeAccessOnAllBackends(ctx)
+}
+
+// ResizeVolume resizes a volume to the new size.
+func (o *TridentOrchestrator) ResizeVolume(ctx context.Context, volumeName, newSize string) (err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return err
+	}
+
+	defer recordTiming("volume_resize", &err)()
+
+	defer lockVolumes(ctx, "ResizeVolume", volumeName)()
+	defer o.updateMetrics()
+
+	o.mutex.RLock()
+	volume, found := o.volumes[volumeName]
+	o.mutex.RUnlock()
+	if !found {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	if volume.Orphaned {
+		Logc(ctx).WithFields(log.Fields{
+			"volume":      volumeName,
+			"backendUUID": volume.BackendUUID,
+		}).Warnf("Resize operation is likely to fail with an orphaned volume.")
+	}
+	if volume.State.IsDeleting() {
+		return utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
+	}
+	if err = o.checkVolumeRelocation(ctx, volume, false); err != nil {
+		return err
+	}
+
+	// Create a new config for the volume transaction
+	cloneConfig := volume.Config.ConstructClone()
+	cloneConfig.Size = newSize
+
+	// Add a transaction in case the operation must be retried during bootstraping.
+	volTxn := &storage.VolumeTransaction{
+		Config:    cloneConfig,
+		Op:        storage.ResizeVolume,
+		Operation: newOperationRecord(storage.ResizeVolume),
+	}
+	if err = o.AddVolumeTransaction(ctx, volTxn); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err == nil {
+			Logc(ctx).WithFields(log.Fields{
+				"volume":      volumeName,
+				"volume_size": newSize,
+			}).Info("Orchestrator resized the volume on the storage backend.")
+		}
+		err = o.resizeVolumeCleanup(ctx, err, volume, volTxn)
+	}()
+
+	// Resize the volume.
+	return o.resizeVolume(ctx, volume, newSize)
+}
+
+// ModifyVolume changes the attributes of an existing volume on its storage backend and records
+// the new attributes in the volume's config.
+func (o *TridentOrchestrator) ModifyVolume(
+	ctx context.Context, volumeName string, modification *storage.VolumeModification,
+) (volExternal *storage.VolumeExternal, err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("volume_modify", &err)()
+
+	if modification == nil {
+		return nil, utils.InvalidInputError("no volume modification specified")
+	}
+	if err = modification.Validate(); err != nil {
+		return nil, utils.InvalidInputError(err.Error())
+	}
+
+	defer lockVolumes(ctx, "ModifyVolume", volumeName)()
+
+	o.mutex.RLock()
+	volume, found := o.volumes[volumeName]
+	o.mutex.RUnlock()
+	if !found {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	if volume.State.IsDeleting() {
+		return nil, utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
+	}
+	if err = o.checkVolumeRelocation(ctx, volume, false); err != nil {
+		return nil, err
+	}
+
+	volumeBackend, unlockBackend, err := o.rlockBackend(ctx, "ModifyVolume", volume.BackendUUID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find backend %v for volume %s", volume.BackendUUID, volumeName)
+	}
+
+	// The driver works on a copy so that readers of the volume never see a partially updated config
+	volConfig := volume.Config.ConstructClone()
+	modification.Apply(volConfig)
+
+	err = volumeBackend.ModifyVolume(ctx, volConfig, modification)
+	unlockBackend()
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"volume":          volumeName,
+			"volume_internal": volume.Config.InternalName,
+			"backendUUID":     volume.BackendUUID,
+			"modification":    modification,
+			"error":           err,
+		}).Error("Unable to modify the volume.")
+		return nil, fmt.Errorf("unable to modify the volume: %v", err)
+	}
+
+	o.mutex.Lock()
+	volume.Config = volConfig
+	o.mutex.Unlock()
+
+	if err = o.updateVolumeOnPersistentStore(ctx, volume); err != nil {
+		Logc(ctx).WithField("volume", volumeName).Error("Unable to update the volume in persistent store.")
+		return nil, err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":     volumeName,
+		"attributes": modification.Attributes(),
+	}).Info("Orchestrator modified the volume on the storage backend.")
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return volume.ConstructExternal(), nil
+}
+
+// getMirrorVolumes returns the source and destination volumes of a mirror relationship, after
+// ensuring that both exist and reside on backends of the same type.
+func (o *TridentOrchestrator) getMirrorVolumes(
+	sourceVolumeName, destinationVolumeName string,
+) (*storage.Volume, *storage.Volume, error) {
+
+	if sourceVolumeName == destinationVolumeName {
+		return nil, nil, utils.InvalidInputError(fmt.Sprintf("volume %s cannot be mirrored to itself",
+			sourceVolumeName))
+	}
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	volumes := make([]*storage.Volume, 0, 2)
+	driverNames := make([]string, 0, 2)
+	for _, volumeName := range []string{sourceVolumeName, destinationVolumeName} {
+		volume, found := o.volumes[volumeName]
+		if !found {
+			return nil, nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+		}
+		if volume.State.IsDeleting() {
+			return nil, nil, utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
+		}
+		if volume.State.IsRelocating() {
+			return nil, nil, utils.VolumeRelocatingError(fmt.Sprintf("volume %s is relocating", volumeName))
+		}
+		backend, found := o.backends[volume.BackendUUID]
+		if !found {
+			return nil, nil, utils.NotFoundError(fmt.Sprintf("backend %s for volume %s not found",
+				volume.BackendUUID, volumeName))
+		}
+		volumes = append(volumes, volume)
+		driverNames = append(driverNames, backend.GetDriverName())
+	}
+
+	if driverNames[0] != driverNames[1] {
+		return nil, nil, utils.InvalidInputError(fmt.Sprintf(
+			"volume %s on a %s backend cannot be mirrored to volume %s on a %s backend",
+			sourceVolumeName, driverNames[0], destinationVolumeName, driverNames[1]))
 	}
 
-	defer recordTiming("snapshot_list", &err)()
+	return volumes[0], volumes[1], nil
+}
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+// mirrorOperation invokes a mirror operation on the backend of the local volume of a mirror
+// relationship, supplying the handle by which that backend may refer to the remote volume.  No more
+// than one backend lock is held at a time.
+func (o *TridentOrchestrator) mirrorOperation(
+	ctx context.Context, lockContext string, localVolume, remoteVolume *storage.Volume,
+	operation func(backend *storage.Backend, volConfig *storage.VolumeConfig, remoteVolumeHandle string) error,
+) error {
 
-	snapshots = make([]*storage.SnapshotExternal, 0, len(o.snapshots))
-	for _, s := range o.snapshots {
-		snapshots = append(snapshots, s.ConstructExternal())
+	remoteBackend, unlockRemoteBackend, err := o.rlockBackend(ctx, lockContext, remoteVolume.BackendUUID)
+	if err != nil {
+		return fmt.Errorf("unable to find backend %v for volume %s", remoteVolume.BackendUUID,
+			remoteVolume.Config.Name)
 	}
-	sort.Sort(storage.BySnapshotExternalID(snapshots))
-	return snapshots, nil
-}
-
-func (o *TridentOrchestrator) ListSnapshotsByName(ctx context.Context, snapshotName string) (snapshots []*storage.SnapshotExternal, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	remoteVolumeHandle, err := remoteBackend.GetMirrorVolumeHandle(ctx, remoteVolume.Config)
+	unlockRemoteBackend()
+	if err != nil {
+		return err
 	}
 
-	defer recordTiming("snapshot_list_by_snapshot_name", &err)()
-
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
-
-	snapshots = make([]*storage.SnapshotExternal, 0)
-	for _, s := range o.snapshots {
-		if s.Config.Name == snapshotName {
-			snapshots = append(snapshots, s.ConstructExternal())
-		}
+	localBackend, unlockLocalBackend, err := o.rlockBackend(ctx, lockContext, localVolume.BackendUUID)
+	if err != nil {
+		return fmt.Errorf("unable to find backend %v for volume %s", localVolume.BackendUUID,
+			localVolume.Config.Name)
 	}
-	sort.Sort(storage.BySnapshotExternalID(snapshots))
-	return snapshots, nil
+	defer unlockLocalBackend()
+
+	return operation(localBackend, localVolume.Config, remoteVolumeHandle)
 }
 
-func (o *TridentOrchestrator) ListSnapshotsForVolume(ctx context.Context, volumeName string) (snapshots []*storage.SnapshotExternal, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+// EstablishMirror starts replicating a source volume to a destination volume that was created as a
+// mirror destination.  The initial transfer continues in the background.
+func (o *TridentOrchestrator) EstablishMirror(
+	ctx context.Context, sourceVolumeName, destinationVolumeName, replicationPolicy, replicationSchedule string,
+) (err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
-	defer recordTiming("snapshot_list_by_volume_name", &err)()
+	defer recordTiming("mirror_establish", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockVolumes(ctx, "EstablishMirror", sourceVolumeName, destinationVolumeName)()
 
-	if _, ok := o.volumes[volumeName]; !ok {
-		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	source, destination, err := o.getMirrorVolumes(sourceVolumeName, destinationVolumeName)
+	if err != nil {
+		return err
+	}
+	if !destination.Config.IsMirrorDestination {
+		return utils.InvalidInputError(fmt.Sprintf("volume %s was not created as a mirror destination",
+			destinationVolumeName))
 	}
 
-	snapshots = make([]*storage.SnapshotExternal, 0, len(o.snapshots))
-	for _, s := range o.snapshots {
-		if s.Config.VolumeName == volumeName {
-			snapshots = append(snapshots, s.ConstructExternal())
-		}
+	err = o.mirrorOperation(ctx, "EstablishMirror", destination, source,
+		func(backend *storage.Backend, volConfig *storage.VolumeConfig, remoteVolumeHandle string) error {
+			return backend.EstablishMirror(ctx, volConfig, remoteVolumeHandle, replicationPolicy, replicationSchedule)
+		})
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"sourceVolume":      sourceVolumeName,
+			"destinationVolume": destinationVolumeName,
+			"error":             err,
+		}).Error("Unable to establish the mirror.")
+		return fmt.Errorf("unable to establish mirror: %v", err)
 	}
-	sort.Sort(storage.BySnapshotExternalID(snapshots))
-	return snapshots, nil
+
+	Logc(ctx).WithFields(log.Fields{
+		"sourceVolume":      sourceVolumeName,
+		"destinationVolume": destinationVolumeName,
+	}).Info("Orchestrator established the mirror.")
+
+	return nil
 }
 
-func (o *TridentOrchestrator) ReadSnapshotsForVolume(ctx context.Context, volumeName string) (externalSnapshots []*storage.SnapshotExternal, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+// ReestablishMirror resumes replicating a source volume to a destination volume after the mirror was
+// promoted, discarding any changes made to the destination volume in the meantime.  Swapping the
+// volumes reverses the direction of a promoted mirror.
+func (o *TridentOrchestrator) ReestablishMirror(
+	ctx context.Context, sourceVolumeName, destinationVolumeName, replicationPolicy, replicationSchedule string,
+) (err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
-	defer recordTiming("snapshot_read_by_volume", &err)()
+	defer recordTiming("mirror_reestablish", &err)()
 
-	volume, ok := o.volumes[volumeName]
-	if !ok {
-		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
-	}
+	defer lockVolumes(ctx, "ReestablishMirror", sourceVolumeName, destinationVolumeName)()
 
-	snapshots, err := o.backends[volume.BackendUUID].GetSnapshots(ctx, volume.Config)
+	source, destination, err := o.getMirrorVolumes(sourceVolumeName, destinationVolumeName)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	externalSnapshots = make([]*storage.SnapshotExternal, 0)
-	for _, snapshot := range snapshots {
-		externalSnapshots = append(externalSnapshots, snapshot.ConstructExternal())
+	err = o.mirrorOperation(ctx, "ReestablishMirror", destination, source,
+		func(backend *storage.Backend, volConfig *storage.VolumeConfig, remoteVolumeHandle string) error {
+			return backend.ReestablishMirror(ctx, volConfig, remoteVolumeHandle, replicationPolicy,
+				replicationSchedule)
+		})
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"sourceVolume":      sourceVolumeName,
+			"destinationVolume": destinationVolumeName,
+			"error":             err,
+		}).Error("Unable to reestablish the mirror.")
+		return fmt.Errorf("unable to reestablish mirror: %v", err)
 	}
-	sort.Sort(storage.BySnapshotExternalID(externalSnapshots))
-	return externalSnapshots, nil
+
+	Logc(ctx).WithFields(log.Fields{
+		"sourceVolume":      sourceVolumeName,
+		"destinationVolume": destinationVolumeName,
+	}).Info("Orchestrator reestablished the mirror.")
+
+	return nil
 }
 
-func (o *TridentOrchestrator) ReloadVolumes(ctx context.Context) (err error) {
+// PromoteMirror stops replicating a source volume to a destination volume and makes the destination
+// volume writable.
+func (o *TridentOrchestrator) PromoteMirror(
+	ctx context.Context, sourceVolumeName, destinationVolumeName string,
+) (err error) {
 
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
-	defer recordTiming("volume_reload", &err)()
+	defer recordTiming("mirror_promote", &err)()
 
-	// Lock out all other workflows while we reload the volumes
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
-	defer o.updateMetrics()
+	defer lockVolumes(ctx, "PromoteMirror", sourceVolumeName, destinationVolumeName)()
 
-	// Make a temporary copy of backends in case anything goes wrong
-	tempBackends := make(map[string]*storage.Backend)
-	for k, v := range o.backends {
-		tempBackends[k] = v
+	source, destination, err := o.getMirrorVolumes(sourceVolumeName, destinationVolumeName)
+	if err != nil {
+		return err
 	}
-	// Make a temporary copy of volumes in case anything goes wrong
-	tempVolumes := make(map[string]*storage.Volume)
-	for k, v := range o.volumes {
-		tempVolumes[k] = v
+
+	err = o.mirrorOperation(ctx, "PromoteMirror", destination, source,
+		func(backend *storage.Backend, volConfig *storage.VolumeConfig, remoteVolumeHandle string) error {
+			return backend.PromoteMirror(ctx, volConfig, remoteVolumeHandle)
+		})
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"sourceVolume":      sourceVolumeName,
+			"destinationVolume": destinationVolumeName,
+			"error":             err,
+		}).Error("Unable to promote the mirror.")
+		return fmt.Errorf("unable to promote mirror: %v", err)
 	}
 
-	// Clear out cached volumes in the backends
-	for _, backend := range o.backends {
-		backend.Volumes = make(map[string]*storage.Volume)
+	Logc(ctx).WithFields(log.Fields{
+		"sourceVolume":      sourceVolumeName,
+		"destinationVolume": destinationVolumeName,
+	}).Info("Orchestrator promoted the mirror.")
+
+	return nil
+}
+
+// GetMirrorStatus returns the status of the mirror from a source volume to a destination volume.
+func (o *TridentOrchestrator) GetMirrorStatus(
+	ctx context.Context, sourceVolumeName, destinationVolumeName string,
+) (status *storage.MirrorStatus, err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
-	// Re-run the volume bootstrapping code
-	err = o.bootstrapVolumes(ctx)
+	defer recordTiming("mirror_get_status", &err)()
 
-	// If anything went wrong, reinstate the original volumes
+	source, destination, err := o.getMirrorVolumes(sourceVolumeName, destinationVolumeName)
 	if err != nil {
-		Logc(ctx).Errorf("Volume reload failed, restoring original volume list: %v", err)
-		o.backends = tempBackends
-		o.volumes = tempVolumes
+		return nil, err
 	}
 
-	return err
+	err = o.mirrorOperation(ctx, "GetMirrorStatus", destination, source,
+		func(backend *storage.Backend, volConfig *storage.VolumeConfig, remoteVolumeHandle string) error {
+			status, err = backend.GetMirrorStatus(ctx, volConfig, remoteVolumeHandle)
+			return err
+		})
+
+	return status, err
 }
 
-// ResizeVolume resizes a volume to the new size.
-func (o *TridentOrchestrator) ResizeVolume(ctx context.Context, volumeName, newSize string) (err error) {
+// ReleaseMirror deletes the mirror from a source volume to a destination volume, leaving both volumes
+// in place.  A destination volume that was not promoted remains read-only.
+func (o *TridentOrchestrator) ReleaseMirror(
+	ctx context.Context, sourceVolumeName, destinationVolumeName string,
+) (err error) {
 
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
-	defer recordTiming("volume_resize", &err)()
+	defer recordTiming("mirror_release", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
-	defer o.updateMetrics()
+	defer lockVolumes(ctx, "ReleaseMirror", sourceVolumeName, destinationVolumeName)()
 
-	volume, found := o.volumes[volumeName]
-	if !found {
-		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
-	}
-	if volume.Orphaned {
-		Logc(ctx).WithFields(log.Fields{
-			"volume":      volumeName,
-			"backendUUID": volume.BackendUUID,
-		}).Warnf("Resize operation is likely to fail with an orphaned volume.")
-	}
-	if volume.State.IsDeleting() {
-		return utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
+	source, destination, err := o.getMirrorVolumes(sourceVolumeName, destinationVolumeName)
+	if err != nil {
+		return err
 	}
 
-	// Create a new config for the volume transaction
-	cloneConfig := volume.Config.ConstructClone()
-	cloneConfig.Size = newSize
-
-	// Add a transaction in case the operation must be retried during bootstraping.
-	volTxn := &storage.VolumeTransaction{
-		Config: cloneConfig,
-		Op:     storage.ResizeVolume,
+	// The relationship is deleted from the destination before it is released from the source
+	err = o.mirrorOperation(ctx, "ReleaseMirror", destination, source,
+		func(backend *storage.Backend, volConfig *storage.VolumeConfig, remoteVolumeHandle string) error {
+			return backend.DeleteMirror(ctx, volConfig, remoteVolumeHandle)
+		})
+	if err == nil {
+		err = o.mirrorOperation(ctx, "ReleaseMirror", source, destination,
+			func(backend *storage.Backend, volConfig *storage.VolumeConfig, remoteVolumeHandle string) error {
+				return backend.ReleaseMirror(ctx, volConfig, remoteVolumeHandle)
+			})
 	}
-	if err = o.AddVolumeTransaction(ctx, volTxn); err != nil {
-		return err
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"sourceVolume":      sourceVolumeName,
+			"destinationVolume": destinationVolumeName,
+			"error":             err,
+		}).Error("Unable to release the mirror.")
+		return fmt.Errorf("unable to release mirror: %v", err)
 	}
 
-	defer func() {
-		if err == nil {
-			Logc(ctx).WithFields(log.Fields{
-				"volume":      volumeName,
-				"volume_size": newSize,
-			}).Info("Orchestrator resized the volume on the storage backend.")
-		}
-		err = o.resizeVolumeCleanup(ctx, err, volume, volTxn)
-	}()
+	Logc(ctx).WithFields(log.Fields{
+		"sourceVolume":      sourceVolumeName,
+		"destinationVolume": destinationVolumeName,
+	}).Info("Orchestrator released the mirror.")
 
-	// Resize the volume.
-	return o.resizeVolume(ctx, volume, newSize)
+	return nil
 }
 
 // resizeVolume does the necessary work to resize a volume. It doesn't
-// construct a transaction, nor does it take locks; it assumes that the
+// construct a transaction, nor does it take the volume lock; it assumes that the
 // caller will take care of both of these. It also assumes that the volume
 // exists in memory.
 func (o *TridentOrchestrator) resizeVolume(ctx context.Context, volume *storage.Volume, newSize string) error {
 
-	volumeBackend, found := o.backends[volume.BackendUUID]
-	if !found {
+	volumeBackend, unlockBackend, err := o.rlockBackend(ctx, "resizeVolume", volume.BackendUUID)
+	if err != nil {
 		Logc(ctx).WithFields(log.Fields{
 			"volume":      volume.Config.Name,
 			"backendUUID": volume.BackendUUID,
@@ -3627,9 +4892,13 @@ func (o *TridentOrchestrator) resizeVolume(ctx context.Context, volume *storage.
 	}
 
 	if volume.Config.Size != newSize {
-		// If the resize is successful the driver updates the volume.Config.Size, as a side effect, with the actual
-		// byte size of the expanded volume.
-		if err := volumeBackend.ResizeVolume(ctx, volume.Config, newSize); err != nil {
+		// If the resize is successful the driver updates the volume config's size, as a side effect, with the
+		// actual byte size of the expanded volume.  The driver works on a copy so that readers of the volume
+		// never see a partially updated config.
+		volConfig := volume.Config.ConstructClone()
+		err = volumeBackend.ResizeVolume(ctx, volConfig, newSize)
+		if err != nil {
+			unlockBackend()
 			Logc(ctx).WithFields(log.Fields{
 				"volume":          volume.Config.Name,
 				"volume_internal": volume.Config.InternalName,
@@ -3640,7 +4909,11 @@ func (o *TridentOrchestrator) resizeVolume(ctx context.Context, volume *storage.
 			}).Error("Unable to resize the volume.")
 			return fmt.Errorf("unable to resize the volume: %v", err)
 		}
+		o.mutex.Lock()
+		volume.Config = volConfig
+		o.mutex.Unlock()
 	}
+	unlockBackend()
 
 	if err := o.updateVolumeOnPersistentStore(ctx, volume); err != nil {
 		// It's ok not to revert volume size as we don't clean up the
@@ -3687,6 +4960,9 @@ func (o *TridentOrchestrator) resizeVolumeCleanup(
 		// persistent store after successfully updating the volume on the
 		// backend. We leave the transaction object around so that the
 		// persistent store can be updated in the future through retries.
+		if isJournaledOperation(volTxn) {
+			o.resumeOperation(ctx, volTxn)
+		}
 	}
 	return err
 }
@@ -3766,29 +5042,38 @@ func (o *TridentOrchestrator) getProtocol(
 func (o *TridentOrchestrator) AddStorageClass(
 	ctx context.Context, scConfig *storageclass.Config) (scExternal *storageclass.External, err error) {
 
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("storageclass_add", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockStorageClass(ctx, "AddStorageClass", scConfig.Name)()
 	defer o.updateMetrics()
 
 	sc := storageclass.New(scConfig)
-	if _, ok := o.storageClasses[sc.GetName()]; ok {
+	o.mutex.RLock()
+	_, ok := o.storageClasses[sc.GetName()]
+	o.mutex.RUnlock()
+	if ok {
 		return nil, fmt.Errorf("storage class %s already exists", sc.GetName())
 	}
+	if _, err = storageclass.NewPlacementPolicy(scConfig.PlacementPolicy); err != nil {
+		return nil, fmt.Errorf("storage class %s has an invalid placement policy; %v", sc.GetName(), err)
+	}
 	err = o.storeClient.AddStorageClass(ctx, sc)
 	if err != nil {
 		return nil, err
 	}
+
+	o.mutex.Lock()
 	o.storageClasses[sc.GetName()] = sc
 	added := 0
 	for _, backend := range o.backends {
 		added += sc.CheckAndAddBackend(ctx, backend)
 	}
+	o.mutex.Unlock()
+
 	if added == 0 {
 		Logc(ctx).WithFields(log.Fields{
 			"storageClass": scConfig.Name,
@@ -3802,14 +5087,14 @@ func (o *TridentOrchestrator) AddStorageClass(
 }
 
 func (o *TridentOrchestrator) GetStorageClass(ctx context.Context, scName string) (scExternal *storageclass.External, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("storageclass_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	sc, found := o.storageClasses[scName]
 	if !found {
@@ -3820,17 +5105,98 @@ func (o *TridentOrchestrator) GetStorageClass(ctx context.Context, scName string
 	return sc.ConstructExternal(ctx), nil
 }
 
+// GetStorageClassCapacity returns the capacity available to new volumes of the specified storage class and
+// protocol that would be accessible from the specified topology.  Physical storage that backs more than one
+// pool in the storage class is only counted once.
+func (o *TridentOrchestrator) GetStorageClassCapacity(
+	ctx context.Context, scName string, protocol config.Protocol, topology map[string]string,
+) (capacity *storage.PoolCapacity, err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("storageclass_capacity", &err)()
+
+	o.mutex.RLock()
+	sc, found := o.storageClasses[scName]
+	o.mutex.RUnlock()
+	if !found {
+		return nil, utils.NotFoundError(fmt.Sprintf("storage class %v was not found", scName))
+	}
+
+	var requisiteTopologies []map[string]string
+	if len(topology) > 0 {
+		requisiteTopologies = []map[string]string{topology}
+	}
+	pools := storageclass.FilterPoolsOnTopology(ctx, sc.GetStoragePoolsForProtocol(ctx, protocol),
+		requisiteTopologies)
+
+	capacity = &storage.PoolCapacity{}
+	counted := make(map[string]bool)
+
+	for _, pool := range pools {
+		poolCapacities, poolErr := o.getPoolCapacity(ctx, pool, sc.GetAttributes())
+		if poolErr != nil {
+			Logc(ctx).WithFields(log.Fields{
+				"backend":      pool.Backend.Name,
+				"pool":         pool.Name,
+				"storageClass": scName,
+			}).WithError(poolErr).Warning("Could not get storage pool capacity.")
+			continue
+		}
+		for name, poolCapacity := range poolCapacities {
+			key := pool.Backend.BackendUUID + "/" + name
+			if counted[key] {
+				continue
+			}
+			counted[key] = true
+			capacity.TotalBytes += poolCapacity.TotalBytes
+			capacity.AvailableBytes += poolCapacity.AvailableBytes
+		}
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"storageClass":   scName,
+		"protocol":       protocol,
+		"topology":       topology,
+		"pools":          len(pools),
+		"totalBytes":     capacity.TotalBytes,
+		"availableBytes": capacity.AvailableBytes,
+	}).Debug("Computed storage class capacity.")
+
+	return capacity, nil
+}
+
+// getPoolCapacity returns the capacity of a storage pool that could host a volume with the specified
+// attributes while holding a shared lock on its backend.
+func (o *TridentOrchestrator) getPoolCapacity(
+	ctx context.Context, pool *storage.Pool, volAttributes map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+
+	backend, unlockBackend, err := o.rlockBackend(ctx, "getPoolCapacity", pool.Backend.BackendUUID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockBackend()
+
+	if backend != pool.Backend {
+		return nil, fmt.Errorf("backend %s was updated", backend.Name)
+	}
+	return backend.GetPoolCapacity(ctx, pool, volAttributes)
+}
+
 func (o *TridentOrchestrator) ListStorageClasses(ctx context.Context) (
 	scExternals []*storageclass.External, err error,
 ) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("storageclass_list", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	storageClasses := make([]*storageclass.External, 0, len(o.storageClasses))
 	for _, sc := range o.storageClasses {
@@ -3840,17 +5206,18 @@ func (o *TridentOrchestrator) ListStorageClasses(ctx context.Context) (
 }
 
 func (o *TridentOrchestrator) DeleteStorageClass(ctx context.Context, scName string) (err error) {
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
 	defer recordTiming("storageclass_delete", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockStorageClass(ctx, "DeleteStorageClass", scName)()
 	defer o.updateMetrics()
 
+	o.mutex.RLock()
 	sc, found := o.storageClasses[scName]
+	o.mutex.RUnlock()
 	if !found {
 		return utils.NotFoundError(fmt.Sprintf("storage class %s not found", scName))
 	}
@@ -3863,6 +5230,10 @@ func (o *TridentOrchestrator) DeleteStorageClass(ctx context.Context, scName str
 	if err != nil {
 		return err
 	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
 	delete(o.storageClasses, scName)
 	for _, storagePool := range sc.GetStoragePoolsForProtocol(ctx, config.ProtocolAny) {
 		storagePool.RemoveStorageClass(scName)
@@ -3877,28 +5248,37 @@ func (o *TridentOrchestrator) reconcileNodeAccessOnAllBackends(ctx context.Conte
 	}
 
 	Logc(ctx).Debug("Reconciling node access on current backends.")
-	for _, b := range o.backends {
-		err := o.reconcileNodeAccessOnBackend(ctx, b)
-		if err != nil {
+	for _, backendUUID := range o.getBackendUUIDs() {
+		err := o.reconcileNodeAccessOnBackend(ctx, backendUUID)
+		if err != nil && !utils.IsNotFoundError(err) {
 			return err
 		}
 	}
 	return nil
 }
 
-func (o *TridentOrchestrator) reconcileNodeAccessOnBackend(ctx context.Context, b *storage.Backend) error {
+// reconcileNodeAccessOnBackend updates a backend with the current set of nodes.  It acquires the
+// backend's lock, so the caller must not already hold it.
+func (o *TridentOrchestrator) reconcileNodeAccessOnBackend(ctx context.Context, backendUUID string) error {
 
 	if config.CurrentDriverContext != config.ContextCSI {
 		return nil
 	}
 
-	nodes := make([]*utils.Node, 0)
+	b, unlockBackend, err := o.lockBackend(ctx, "reconcileNodeAccess", backendUUID)
+	if err != nil {
+		return err
+	}
+	defer unlockBackend()
 
+	o.mutex.RLock()
+	nodes := make([]*utils.Node, 0, len(o.nodes))
 	for _, n := range o.nodes {
 		nodes = append(nodes, n)
 	}
+	o.mutex.RUnlock()
 
-	err := b.ReconcileNodeAccess(ctx, nodes)
+	err = b.ReconcileNodeAccess(ctx, nodes)
 	if err != nil {
 		err = fmt.Errorf("unable to reconcile node access on backend; %v", err)
 		Logc(ctx).WithField("Backend", b.Name).Error(err)
@@ -3911,19 +5291,20 @@ func (o *TridentOrchestrator) reconcileNodeAccessOnBackend(ctx context.Context,
 func (o *TridentOrchestrator) AddNode(
 	ctx context.Context, node *utils.Node, nodeEventCallback NodeEventCallback,
 ) (err error) {
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
 	defer recordTiming("node_add", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockNode(ctx, "AddNode", node.Name)()
 	defer o.updateMetrics()
 
 	if node.NodePrep != nil && node.NodePrep.Enabled {
 		// Check if node prep status has changed
+		o.mutex.RLock()
 		oldNode, found := o.nodes[node.Name]
+		o.mutex.RUnlock()
 		if found && oldNode.NodePrep != nil {
 			// NFS
 			if node.NodePrep.NFS != oldNode.NodePrep.NFS {
@@ -3943,7 +5324,10 @@ func (o *TridentOrchestrator) AddNode(
 		return err
 	}
 
+	o.mutex.Lock()
 	o.nodes[node.Name] = node
+	delete(o.deletedNodes, node.Name)
+	o.mutex.Unlock()
 
 	return o.reconcileNodeAccessOnAllBackends(ctx)
 }
@@ -3992,14 +5376,14 @@ func (o *TridentOrchestrator) handleUpdatedNodePrep(
 }
 
 func (o *TridentOrchestrator) GetNode(ctx context.Context, nName string) (node *utils.Node, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("node_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	node, found := o.nodes[nName]
 	if !found {
@@ -4012,14 +5396,14 @@ func (o *TridentOrchestrator) GetNode(ctx context.Context, nName string) (node *
 }
 
 func (o *TridentOrchestrator) ListNodes(context.Context) (nodes []*utils.Node, err error) {
-	if o.bootstrapError != nil {
-		return nil, o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
 	}
 
 	defer recordTiming("node_list", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	nodes = make([]*utils.Node, 0, len(o.nodes))
 	for _, node := range o.nodes {
@@ -4029,27 +5413,85 @@ func (o *TridentOrchestrator) ListNodes(context.Context) (nodes []*utils.Node, e
 }
 
 func (o *TridentOrchestrator) DeleteNode(ctx context.Context, nName string) (err error) {
-	if o.bootstrapError != nil {
-		return o.bootstrapError
+	if err := o.getBootstrapError(); err != nil {
+		return err
 	}
 
 	defer recordTiming("node_delete", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	defer lockNode(ctx, "DeleteNode", nName)()
 	defer o.updateMetrics()
 
+	o.mutex.RLock()
 	node, found := o.nodes[nName]
+	published := o.isNodePublished(nName)
+	o.mutex.RUnlock()
 	if !found {
 		return utils.NotFoundError(fmt.Sprintf("node %s not found", nName))
 	}
-	if err = o.storeClient.DeleteNode(ctx, node); err != nil {
-		return err
+
+	if published {
+		// Keep the node's identifiers so that its volumes may still be unpublished from it
+		deletedNode := *node
+		deletedNode.Deleted = true
+		if err = o.storeClient.AddOrUpdateNode(ctx, &deletedNode); err != nil {
+			return err
+		}
+		Logc(ctx).WithField("node", nName).Info("Node deleted while volumes are published to it.")
+
+		o.mutex.Lock()
+		o.deletedNodes[nName] = &deletedNode
+		delete(o.nodes, nName)
+		o.mutex.Unlock()
+	} else {
+		if err = o.storeClient.DeleteNode(ctx, node); err != nil {
+			return err
+		}
+
+		o.mutex.Lock()
+		delete(o.nodes, nName)
+		o.mutex.Unlock()
 	}
-	delete(o.nodes, nName)
+
 	return o.reconcileNodeAccessOnAllBackends(ctx)
 }
 
+// removeDeletedNode discards a deleted node once no volumes remain published to it.
+func (o *TridentOrchestrator) removeDeletedNode(ctx context.Context, nName string) error {
+
+	defer lockNode(ctx, "removeDeletedNode", nName)()
+
+	o.mutex.RLock()
+	node, found := o.deletedNodes[nName]
+	published := o.isNodePublished(nName)
+	o.mutex.RUnlock()
+	if !found || published {
+		return nil
+	}
+
+	if err := o.storeClient.DeleteNode(ctx, node); err != nil {
+		return err
+	}
+	Logc(ctx).WithField("node", nName).Debug("Removed deleted node.")
+
+	o.mutex.Lock()
+	delete(o.deletedNodes, nName)
+	o.mutex.Unlock()
+
+	return nil
+}
+
+// isNodePublished returns whether any volume is published to the named node.  The caller must hold
+// o.mutex.
+func (o *TridentOrchestrator) isNodePublished(nName string) bool {
+	for _, volume := range o.volumes {
+		if utils.SliceContainsString(volume.PublishedNodes, nName) {
+			return true
+		}
+	}
+	return false
+}
+
 func (o *TridentOrchestrator) updateBackendOnPersistentStore(
 	ctx context.Context, backend *storage.Backend, newBackend bool,
 ) error {
@@ -4076,14 +5518,19 @@ func (o *TridentOrchestrator) updateBackendOnPersistentStore(
 
 func (o *TridentOrchestrator) updateVolumeOnPersistentStore(ctx context.Context, vol *storage.Volume) error {
 
+	// Persist a snapshot of the volume, since a backend update may modify it concurrently
+	o.mutex.RLock()
+	volCopy := *vol
+	o.mutex.RUnlock()
+
 	// Update the volume information in persistent store
 	Logc(ctx).WithFields(log.Fields{
-		"volume":          vol.Config.Name,
-		"volume_orphaned": vol.Orphaned,
-		"volume_size":     vol.Config.Size,
-		"volumeState":     string(vol.State),
+		"volume":          volCopy.Config.Name,
+		"volume_orphaned": volCopy.Orphaned,
+		"volume_size":     volCopy.Config.Size,
+		"volumeState":     string(volCopy.State),
 	}).Debug("Updating an existing volume.")
-	return o.storeClient.UpdateVolume(ctx, vol)
+	return o.storeClient.UpdateVolume(ctx, &volCopy)
 }
 
 func (o *TridentOrchestrator) replaceBackendAndUpdateVolumesOnPersistentStore(
@@ -9,8 +9,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -2124,6 +2126,36 @@ func TestStorageClassOnlyBootstrap(t *testing.T) {
 	cleanup(t, orchestrator)
 }
 
+func TestGetStorageClassCapacity(t *testing.T) {
+	const (
+		backendName = "capacityBackend"
+		scName      = "capacitySC"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackendStorageClass(t, orchestrator, backendName, scName, config.File)
+
+	// The fake backend creates two 1 GB volumes in its single 100 GiB pool
+	capacity, err := orchestrator.GetStorageClassCapacity(ctx(), scName, config.ProtocolAny, nil)
+	if err != nil {
+		t.Fatalf("Unable to get storage class capacity: %v", err)
+	}
+	assert.Equal(t, uint64(100*1024*1024*1024), capacity.TotalBytes, "wrong total capacity")
+	assert.Equal(t, uint64(100*1024*1024*1024-2*1000000000), capacity.AvailableBytes, "wrong available capacity")
+
+	// No pools support the other protocol
+	capacity, err = orchestrator.GetStorageClassCapacity(ctx(), scName, config.Block, nil)
+	if err != nil {
+		t.Fatalf("Unable to get storage class capacity: %v", err)
+	}
+	assert.Equal(t, uint64(0), capacity.AvailableBytes, "expected no capacity")
+
+	_, err = orchestrator.GetStorageClassCapacity(ctx(), "unknown", config.ProtocolAny, nil)
+	assert.True(t, utils.IsNotFoundError(err), "expected not found error")
+
+	cleanup(t, orchestrator)
+}
+
 func TestFirstVolumeRecovery(t *testing.T) {
 	const (
 		backendName      = "firstRecoveryBackend"
@@ -2159,7 +2191,7 @@ func TestOrchestratorNotReady(t *testing.T) {
 
 	orchestrator := getOrchestrator()
 	orchestrator.bootstrapped = false
-	orchestrator.bootstrapError = utils.NotReadyError()
+	orchestrator.setBootstrapError(utils.NotReadyError())
 
 	backend, err = orchestrator.AddBackend(ctx(), "", "")
 	if backend != nil || !utils.IsNotReadyError(err) {
@@ -2282,6 +2314,68 @@ func TestOrchestratorNotReady(t *testing.T) {
 	}
 }
 
+func TestOrchestratorStandby(t *testing.T) {
+
+	orchestrator := NewTridentOrchestrator(inMemoryClient)
+	orchestrator.SetStandby("")
+
+	_, err := orchestrator.ListBackends(ctx())
+	assert.True(t, utils.IsNotLeaderError(err), "expected a not-leader error")
+
+	orchestrator.SetStandby("trident-csi-1")
+
+	_, err = orchestrator.ListVolumes(ctx())
+	assert.True(t, utils.IsNotLeaderError(err), "expected a not-leader error")
+	assert.Contains(t, err.Error(), "trident-csi-1")
+
+	// A standby must pass its liveness probe
+	version, err := orchestrator.GetVersion(ctx())
+	assert.NoError(t, err)
+	assert.Equal(t, config.OrchestratorVersion.String(), version)
+
+	// Once elected, the standby bootstraps and serves requests
+	assert.NoError(t, orchestrator.Bootstrap())
+	_, err = orchestrator.ListBackends(ctx())
+	assert.NoError(t, err)
+
+	// A bootstrapped orchestrator can't be made a standby again
+	orchestrator.SetStandby("trident-csi-1")
+	_, err = orchestrator.ListBackends(ctx())
+	assert.NoError(t, err)
+
+	orchestrator.Stop()
+}
+
+func TestOrchestratorStandbyConcurrentRequests(t *testing.T) {
+
+	orchestrator := NewTridentOrchestrator(inMemoryClient)
+	orchestrator.SetStandby("")
+
+	// Leader changes and the takeover arrive on the leader election goroutine while requests are served
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			orchestrator.SetStandby(fmt.Sprintf("trident-csi-%d", i))
+		}
+		assert.NoError(t, orchestrator.Bootstrap())
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_, _ = orchestrator.GetVersion(ctx())
+			_, _ = orchestrator.ListVolumes(ctx())
+		}
+	}()
+	wg.Wait()
+
+	_, err := orchestrator.ListVolumes(ctx())
+	assert.NoError(t, err)
+
+	orchestrator.Stop()
+}
+
 func importVolumeSetup(t *testing.T, backendName string, scName string, volumeName string, importOriginalName string,
 	backendProtocol config.Protocol) (*TridentOrchestrator, *storage.VolumeConfig) {
 	// Object setup
@@ -2462,6 +2556,241 @@ func TestImportVolume(t *testing.T) {
 	cleanup(t, orchestrator)
 }
 
+func TestUnpublishVolume(t *testing.T) {
+	const (
+		backendName     = "backend01"
+		scName          = "sc01"
+		volumeName      = "volume01"
+		originalName    = "origVolume01"
+		backendProtocol = config.Block
+	)
+
+	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName, backendProtocol)
+
+	if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	if err := orchestrator.AddNode(ctx(), &utils.Node{Name: "node1", IQN: "iqn.1993-08.org.debian:01:node1"}, nil); err != nil {
+		t.Fatal("Unable to add node: ", err)
+	}
+
+	// Unpublishing from known and unknown nodes succeeds
+	assert.NoError(t, orchestrator.UnpublishVolume(ctx(), volumeName, "node1"))
+	assert.NoError(t, orchestrator.UnpublishVolume(ctx(), volumeName, "node2"))
+
+	// A volume that doesn't exist has nothing to unpublish
+	assert.NoError(t, orchestrator.UnpublishVolume(ctx(), "volume02", "node1"))
+
+	cleanup(t, orchestrator)
+}
+
+func TestPublishVolumeTracksNodes(t *testing.T) {
+	const (
+		backendName     = "backend01"
+		scName          = "sc01"
+		volumeName      = "volume01"
+		originalName    = "origVolume01"
+		backendProtocol = config.Block
+	)
+
+	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName, backendProtocol)
+
+	if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	for _, nodeName := range []string{"node1", "node2", "node1"} {
+		assert.NoError(t, orchestrator.PublishVolume(ctx(), volumeName, &utils.VolumePublishInfo{HostName: nodeName}))
+	}
+
+	volume, err := orchestrator.GetVolume(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"node1", "node2"}, volume.PublishedNodes)
+
+	// The published nodes are persisted
+	persistentVolume, err := orchestrator.storeClient.GetVolume(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"node1", "node2"}, persistentVolume.PublishedNodes)
+
+	assert.NoError(t, orchestrator.UnpublishVolume(ctx(), volumeName, "node1"))
+	assert.NoError(t, orchestrator.UnpublishVolume(ctx(), volumeName, "node3"))
+
+	volume, err = orchestrator.GetVolume(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node2"}, volume.PublishedNodes)
+
+	cleanup(t, orchestrator)
+}
+
+func TestUnpublishVolumeFromDeletedNode(t *testing.T) {
+	const (
+		backendName     = "backend01"
+		scName          = "sc01"
+		volumeName      = "volume01"
+		originalName    = "origVolume01"
+		backendProtocol = config.Block
+	)
+
+	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName, backendProtocol)
+
+	if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	node := &utils.Node{
+		Name: "node1",
+		IQN:  "iqn.1993-08.org.debian:01:node1",
+		NQN:  "nqn.2014-08.org.nvmexpress:uuid:node1",
+		IPs:  []string{"1.1.1.1"},
+	}
+	if err := orchestrator.AddNode(ctx(), node, nil); err != nil {
+		t.Fatal("Unable to add node: ", err)
+	}
+	assert.NoError(t, orchestrator.PublishVolume(ctx(), volumeName, &utils.VolumePublishInfo{HostName: node.Name}))
+
+	// A node with published volumes is kept, though no longer listed, until they are unpublished
+	assert.NoError(t, orchestrator.DeleteNode(ctx(), node.Name))
+
+	_, err := orchestrator.GetNode(ctx(), node.Name)
+	assert.True(t, utils.IsNotFoundError(err), "expected deleted node to be gone")
+
+	persistentNode, err := orchestrator.storeClient.GetNode(ctx(), node.Name)
+	assert.NoError(t, err)
+	assert.True(t, persistentNode.Deleted)
+
+	// The backend is told how to identify the deleted node
+	assert.NoError(t, orchestrator.UnpublishVolume(ctx(), volumeName, node.Name))
+
+	volume, err := orchestrator.GetVolume(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.Empty(t, volume.PublishedNodes)
+
+	fakeDriver := orchestrator.backends[volume.BackendUUID].Driver.(*fakedriver.StorageDriver)
+	publishInfo := fakeDriver.Unpublished[node.Name]
+	if assert.NotNil(t, publishInfo) {
+		assert.Equal(t, []string{node.IQN}, publishInfo.HostIQN)
+		assert.Equal(t, node.NQN, publishInfo.HostNQN)
+		assert.Equal(t, node.IPs, publishInfo.HostIP)
+	}
+
+	// The deleted node is discarded once nothing is published to it
+	_, err = orchestrator.storeClient.GetNode(ctx(), node.Name)
+	assert.Error(t, err)
+	assert.Empty(t, orchestrator.deletedNodes)
+
+	cleanup(t, orchestrator)
+}
+
+func TestPublishVolumeTracksLocalhost(t *testing.T) {
+	const (
+		backendName     = "backend01"
+		scName          = "sc01"
+		volumeName      = "volume01"
+		originalName    = "origVolume01"
+		backendProtocol = config.File
+	)
+
+	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName, backendProtocol)
+
+	if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatal("Unable to get host name: ", err)
+	}
+
+	// Volumes published to the local host are recorded under its host name
+	assert.NoError(t, orchestrator.PublishVolume(ctx(), volumeName, &utils.VolumePublishInfo{Localhost: true}))
+
+	volume, err := orchestrator.GetVolume(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{hostname}, volume.PublishedNodes)
+
+	// Detaching the volume from the local host, even if it was never mounted, removes the record
+	mountpoint := filepath.Join(t.TempDir(), volumeName)
+	assert.NoError(t, orchestrator.DetachVolume(ctx(), volumeName, mountpoint))
+
+	volume, err = orchestrator.GetVolume(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.Empty(t, volume.PublishedNodes)
+
+	cleanup(t, orchestrator)
+}
+
+func TestBackfillPublishedNodes(t *testing.T) {
+	const (
+		backendName     = "backend01"
+		scName          = "sc01"
+		volumeName      = "volume01"
+		originalName    = "origVolume01"
+		backendProtocol = config.Block
+	)
+
+	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName, backendProtocol)
+
+	if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	assert.NoError(t, orchestrator.PublishVolume(ctx(), volumeName, &utils.VolumePublishInfo{HostName: "node1"}))
+	assert.False(t, orchestrator.PublishedNodesBackfilled())
+
+	// Backfilled nodes are added to those already recorded, and unknown volumes are ignored
+	publishedNodes := map[string][]string{
+		volumeName: {"node2"},
+		"volume02": {"node1"},
+	}
+	assert.NoError(t, orchestrator.BackfillPublishedNodes(ctx(), publishedNodes))
+	assert.True(t, orchestrator.PublishedNodesBackfilled())
+
+	volume, err := orchestrator.GetVolume(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"node1", "node2"}, volume.PublishedNodes)
+
+	persistentVolume, err := orchestrator.storeClient.GetVolume(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"node1", "node2"}, persistentVolume.PublishedNodes)
+
+	cleanup(t, orchestrator)
+}
+
+func TestGetVolumeCondition(t *testing.T) {
+	const (
+		backendName     = "backend01"
+		scName          = "sc01"
+		volumeName      = "volume01"
+		originalName    = "origVolume01"
+		backendProtocol = config.File
+	)
+
+	orchestrator, volumeConfig := importVolumeSetup(t, backendName, scName, volumeName, originalName, backendProtocol)
+
+	if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+		t.Fatal("Unable to add volume: ", err)
+	}
+
+	condition, err := orchestrator.GetVolumeCondition(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.False(t, condition.Abnormal)
+
+	// A volume that has vanished from its backend is abnormal
+	volume := orchestrator.volumes[volumeName]
+	fakeDriver := orchestrator.backends[volume.BackendUUID].Driver.(*fakedriver.StorageDriver)
+	delete(fakeDriver.Volumes, volume.Config.InternalName)
+
+	condition, err = orchestrator.GetVolumeCondition(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.True(t, condition.Abnormal)
+
+	_, err = orchestrator.GetVolumeCondition(ctx(), "volume02")
+	assert.True(t, utils.IsNotFoundError(err))
+
+	cleanup(t, orchestrator)
+}
+
 func TestValidateImportVolumeNasBackend(t *testing.T) {
 	const (
 		backendName     = "backend01"
@@ -2963,3 +3292,381 @@ func TestGetProtocol(t *testing.T) {
 		assert.True(t, tc.expected == protocolLocal, "expected both the protocols to be equal!")
 	}
 }
+
+func TestConcurrentVolumeOperations(t *testing.T) {
+	const (
+		backendName = "concurrentBackend"
+		scName      = "concurrentSC"
+		workers     = 8
+		iterations  = 5
+	)
+
+	orchestrator := getOrchestrator()
+
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
+		backendName,
+		config.File,
+		map[string]*fake.StoragePool{
+			"primary": {
+				Attrs: map[string]sa.Offer{
+					sa.Media:            sa.NewStringOffer("hdd"),
+					sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+					sa.TestingAttribute: sa.NewBoolOffer(true),
+				},
+				Bytes: 100 * 1024 * 1024 * 1024,
+			},
+		},
+		[]fake.Volume{},
+	)
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+	if _, err = orchestrator.AddBackend(ctx(), configJSON, ""); err != nil {
+		t.Fatalf("Unable to add backend: %v", err)
+	}
+	_, err = orchestrator.AddStorageClass(ctx(), &storageclass.Config{
+		Name: scName,
+		Attributes: map[string]sa.Request{
+			sa.Media:            sa.NewStringRequest("hdd"),
+			sa.ProvisioningType: sa.NewStringRequest("thick"),
+			sa.TestingAttribute: sa.NewBoolRequest(true),
+		},
+	})
+	if err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	var wg sync.WaitGroup
+
+	// Each worker runs the full lifecycle of its own volumes, so that operations on different
+	// volumes of the same backend overlap
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			for i := 0; i < iterations; i++ {
+				volumeName := fmt.Sprintf("concurrent-%d-%d", worker, i)
+				cloneName := volumeName + "-clone"
+
+				volumeConfig := tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)
+				if _, err := orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+					t.Errorf("%s: unable to add volume: %v", volumeName, err)
+					return
+				}
+
+				snapshotConfig := generateSnapshotConfig("snap", volumeName, volumeName)
+				_, err := orchestrator.CreateSnapshot(ctx(), snapshotConfig)
+				assert.NoError(t, err, "%s: unable to create snapshot", volumeName)
+
+				_, err = orchestrator.CloneVolume(ctx(), &storage.VolumeConfig{
+					Name:              cloneName,
+					StorageClass:      scName,
+					CloneSourceVolume: volumeName,
+				})
+				assert.NoError(t, err, "%s: unable to clone volume", volumeName)
+
+				err = orchestrator.ResizeVolume(ctx(), volumeName, fmt.Sprintf("%d", 2*1024*1024*1024))
+				assert.NoError(t, err, "%s: unable to resize volume", volumeName)
+
+				volume, err := orchestrator.GetVolume(ctx(), volumeName)
+				if assert.NoError(t, err, "%s: unable to get volume", volumeName) {
+					assert.Equal(t, fmt.Sprintf("%d", 2*1024*1024*1024), volume.Config.Size,
+						"%s: wrong volume size", volumeName)
+				}
+
+				_, err = orchestrator.GetSnapshot(ctx(), volumeName, "snap")
+				assert.NoError(t, err, "%s: unable to get snapshot", volumeName)
+
+				_, err = orchestrator.ListVolumes(ctx())
+				assert.NoError(t, err, "%s: unable to list volumes", volumeName)
+				_, err = orchestrator.ListSnapshotsForVolume(ctx(), volumeName)
+				assert.NoError(t, err, "%s: unable to list snapshots", volumeName)
+
+				assert.NoError(t, orchestrator.DeleteVolume(ctx(), cloneName),
+					"%s: unable to delete clone", volumeName)
+				assert.NoError(t, orchestrator.DeleteSnapshot(ctx(), volumeName, "snap"),
+					"%s: unable to delete snapshot", volumeName)
+				assert.NoError(t, orchestrator.DeleteVolume(ctx(), volumeName),
+					"%s: unable to delete volume", volumeName)
+			}
+		}(w)
+	}
+
+	// Meanwhile, repeatedly update the backend and read the backend and storage class
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < iterations; i++ {
+			_, err := orchestrator.UpdateBackend(ctx(), backendName, configJSON, "")
+			assert.NoError(t, err, "unable to update backend")
+
+			_, err = orchestrator.ListBackends(ctx())
+			assert.NoError(t, err, "unable to list backends")
+			_, err = orchestrator.GetStorageClassCapacity(ctx(), scName, config.ProtocolAny, nil)
+			assert.NoError(t, err, "unable to get storage class capacity")
+		}
+	}()
+
+	wg.Wait()
+
+	// All volumes and snapshots should be gone, both in memory and in the store
+	volumes, err := orchestrator.ListVolumes(ctx())
+	assert.NoError(t, err, "unable to list volumes")
+	assert.Empty(t, volumes, "volumes remain in memory")
+
+	snapshots, err := orchestrator.ListSnapshots(ctx())
+	assert.NoError(t, err, "unable to list snapshots")
+	assert.Empty(t, snapshots, "snapshots remain in memory")
+
+	storedVolumes, err := orchestrator.storeClient.GetVolumes(ctx())
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		t.Fatalf("Unable to read volumes from the store: %v", err)
+	}
+	assert.Empty(t, storedVolumes, "volumes remain in the store")
+
+	txns, err := orchestrator.storeClient.GetVolumeTransactions(ctx())
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		t.Fatalf("Unable to read transactions from the store: %v", err)
+	}
+	assert.Empty(t, txns, "transactions remain in the store")
+
+	backend, err := orchestrator.GetBackend(ctx(), backendName)
+	if assert.NoError(t, err, "unable to get backend") {
+		assert.Empty(t, backend.Volumes, "volumes remain on the backend")
+	}
+
+	cleanup(t, orchestrator)
+}
+
+func TestModifyVolume(t *testing.T) {
+	const (
+		backendName = "modifyBackend"
+		scName      = "modifySC"
+		volumeName  = "modifyVolume"
+	)
+
+	orchestrator := getOrchestrator()
+
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
+		backendName,
+		config.File,
+		map[string]*fake.StoragePool{
+			"primary": {
+				Attrs: map[string]sa.Offer{
+					sa.Media:            sa.NewStringOffer("hdd"),
+					sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+					sa.TestingAttribute: sa.NewBoolOffer(true),
+				},
+				Bytes: 100 * 1024 * 1024 * 1024,
+			},
+		},
+		[]fake.Volume{},
+	)
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+	if _, err = orchestrator.AddBackend(ctx(), configJSON, ""); err != nil {
+		t.Fatalf("Unable to add backend: %v", err)
+	}
+	_, err = orchestrator.AddStorageClass(ctx(), &storageclass.Config{
+		Name: scName,
+		Attributes: map[string]sa.Request{
+			sa.Media:            sa.NewStringRequest("hdd"),
+			sa.ProvisioningType: sa.NewStringRequest("thick"),
+			sa.TestingAttribute: sa.NewBoolRequest(true),
+		},
+	})
+	if err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+
+	volumeConfig := tu.GenerateVolumeConfig(volumeName, 1, scName, config.File)
+	if _, err = orchestrator.AddVolume(ctx(), volumeConfig); err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+
+	// An empty modification is rejected
+	_, err = orchestrator.ModifyVolume(ctx(), volumeName, &storage.VolumeModification{})
+	assert.Error(t, err, "Expected an error for an empty modification")
+	assert.True(t, utils.IsInvalidInputError(err), "Expected an invalid input error")
+
+	// A missing volume is reported as not found
+	_, err = orchestrator.ModifyVolume(ctx(), "missing",
+		&storage.VolumeModification{SnapshotPolicy: "default"})
+	assert.True(t, utils.IsNotFoundError(err), "Expected a not found error")
+
+	modification := &storage.VolumeModification{
+		SnapshotPolicy:  "default",
+		TieringPolicy:   "auto",
+		UnixPermissions: "0755",
+	}
+	volume, err := orchestrator.ModifyVolume(ctx(), volumeName, modification)
+	if err != nil {
+		t.Fatalf("Unable to modify volume: %v", err)
+	}
+	assert.Equal(t, "default", volume.Config.SnapshotPolicy)
+	assert.Equal(t, "auto", volume.Config.TieringPolicy)
+	assert.Equal(t, "0755", volume.Config.UnixPermissions)
+
+	// The modified attributes are persisted
+	persistentVolume, err := orchestrator.storeClient.GetVolume(ctx(), volumeName)
+	if err != nil {
+		t.Fatalf("Unable to get volume from store: %v", err)
+	}
+	assert.Equal(t, "default", persistentVolume.Config.SnapshotPolicy)
+	assert.Equal(t, "auto", persistentVolume.Config.TieringPolicy)
+	assert.Equal(t, "0755", persistentVolume.Config.UnixPermissions)
+}
+
+func TestMirrorVolumes(t *testing.T) {
+	const (
+		sourceVolume      = "sourceVolume"
+		destinationVolume = "destinationVolume"
+	)
+
+	orchestrator := getOrchestrator()
+
+	// Each backend offers a different medium, so that each volume is placed on its own backend
+	for _, media := range []string{"hdd", "ssd"} {
+		configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
+			"mirror-"+media,
+			config.File,
+			map[string]*fake.StoragePool{
+				"primary": {
+					Attrs: map[string]sa.Offer{
+						sa.Media:            sa.NewStringOffer(media),
+						sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+					},
+					Bytes: 100 * 1024 * 1024 * 1024,
+				},
+			},
+			[]fake.Volume{},
+		)
+		if err != nil {
+			t.Fatal("Unable to create mock driver config JSON: ", err)
+		}
+		if _, err = orchestrator.AddBackend(ctx(), configJSON, ""); err != nil {
+			t.Fatalf("Unable to add backend: %v", err)
+		}
+		_, err = orchestrator.AddStorageClass(ctx(), &storageclass.Config{
+			Name: media,
+			Attributes: map[string]sa.Request{
+				sa.Media:            sa.NewStringRequest(media),
+				sa.ProvisioningType: sa.NewStringRequest("thick"),
+			},
+		})
+		if err != nil {
+			t.Fatal("Unable to add storage class: ", err)
+		}
+	}
+
+	if _, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(sourceVolume, 1, "hdd",
+		config.File)); err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	destinationConfig := tu.GenerateVolumeConfig(destinationVolume, 1, "ssd", config.File)
+	destinationConfig.IsMirrorDestination = true
+	if _, err := orchestrator.AddVolume(ctx(), destinationConfig); err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+
+	// A volume cannot mirror itself, a missing volume, or to a volume that is not a mirror destination
+	err := orchestrator.EstablishMirror(ctx(), sourceVolume, sourceVolume, "", "")
+	assert.True(t, utils.IsInvalidInputError(err), "Expected an invalid input error")
+	err = orchestrator.EstablishMirror(ctx(), "missing", destinationVolume, "", "")
+	assert.True(t, utils.IsNotFoundError(err), "Expected a not found error")
+	err = orchestrator.EstablishMirror(ctx(), destinationVolume, sourceVolume, "", "")
+	assert.True(t, utils.IsInvalidInputError(err), "Expected an invalid input error")
+
+	_, err = orchestrator.GetMirrorStatus(ctx(), sourceVolume, destinationVolume)
+	assert.True(t, utils.IsNotFoundError(err), "Expected a not found error")
+
+	if err = orchestrator.EstablishMirror(ctx(), sourceVolume, destinationVolume, "", ""); err != nil {
+		t.Fatalf("Unable to establish mirror: %v", err)
+	}
+	mirrorStatus, err := orchestrator.GetMirrorStatus(ctx(), sourceVolume, destinationVolume)
+	if assert.NoError(t, err, "Unable to get mirror status") {
+		assert.Equal(t, storage.MirrorStateMirrored, mirrorStatus.State)
+	}
+
+	if err = orchestrator.PromoteMirror(ctx(), sourceVolume, destinationVolume); err != nil {
+		t.Fatalf("Unable to promote mirror: %v", err)
+	}
+	mirrorStatus, err = orchestrator.GetMirrorStatus(ctx(), sourceVolume, destinationVolume)
+	if assert.NoError(t, err, "Unable to get mirror status") {
+		assert.Equal(t, storage.MirrorStateBroken, mirrorStatus.State)
+	}
+
+	if err = orchestrator.ReestablishMirror(ctx(), sourceVolume, destinationVolume, "", ""); err != nil {
+		t.Fatalf("Unable to reestablish mirror: %v", err)
+	}
+	mirrorStatus, err = orchestrator.GetMirrorStatus(ctx(), sourceVolume, destinationVolume)
+	if assert.NoError(t, err, "Unable to get mirror status") {
+		assert.Equal(t, storage.MirrorStateMirrored, mirrorStatus.State)
+	}
+
+	if err = orchestrator.ReleaseMirror(ctx(), sourceVolume, destinationVolume); err != nil {
+		t.Fatalf("Unable to release mirror: %v", err)
+	}
+	_, err = orchestrator.GetMirrorStatus(ctx(), sourceVolume, destinationVolume)
+	assert.True(t, utils.IsNotFoundError(err), "Expected a not found error")
+
+	cleanup(t, orchestrator)
+}
+
+func TestExportImportState(t *testing.T) {
+	const (
+		backendName = "stateBackend"
+		scName      = "stateSC"
+		volumeName  = "stateVolume"
+	)
+
+	orchestrator := getOrchestrator()
+	addBackendStorageClass(t, orchestrator, backendName, scName, config.File)
+	_, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 50, scName, config.File))
+	if err != nil {
+		t.Fatal("Unable to create volume: ", err)
+	}
+	oldNode := &utils.Node{Name: "oldNode"}
+	assert.NoError(t, orchestrator.AddNode(ctx(), oldNode, nil))
+
+	archive, err := orchestrator.ExportState(ctx(), "passphrase")
+	assert.NoError(t, err)
+	assert.Len(t, archive.Backends, 1)
+	assert.Len(t, archive.Volumes, 1)
+	assert.Contains(t, archive.Nodes, oldNode)
+
+	// State may not be imported once Trident has backends and volumes
+	assert.Error(t, orchestrator.ImportState(ctx(), archive, "passphrase"))
+
+	// Import the state into an orchestrator whose store has been lost
+	cleanup(t, orchestrator)
+	assert.NoError(t, orchestrator.storeClient.DeleteNode(ctx(), oldNode))
+	newOrchestrator := getOrchestrator()
+	liveNode := &utils.Node{Name: "liveNode"}
+	assert.NoError(t, newOrchestrator.AddNode(ctx(), liveNode, nil))
+	assert.NoError(t, newOrchestrator.ImportState(ctx(), archive, "passphrase"))
+
+	backend, err := newOrchestrator.GetBackend(ctx(), backendName)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.Online, backend.State)
+	volume, err := newOrchestrator.GetVolume(ctx(), volumeName)
+	assert.NoError(t, err)
+	assert.Equal(t, backend.BackendUUID, volume.BackendUUID)
+	_, err = newOrchestrator.GetStorageClass(ctx(), scName)
+	assert.NoError(t, err)
+
+	// Only the live nodes are known
+	_, err = newOrchestrator.GetNode(ctx(), oldNode.Name)
+	assert.Error(t, err)
+	_, err = newOrchestrator.GetNode(ctx(), liveNode.Name)
+	assert.NoError(t, err)
+
+	// The imported volume is usable
+	assert.NoError(t, newOrchestrator.DeleteVolume(ctx(), volumeName))
+
+	assert.NoError(t, newOrchestrator.DeleteNode(ctx(), liveNode.Name))
+	cleanup(t, newOrchestrator)
+}
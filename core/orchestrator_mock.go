@@ -17,6 +17,7 @@ import (
 
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend"
+	persistentstore "github.com/netapp/trident/persistent_store"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/storage/factory"
 	storageclass "github.com/netapp/trident/storage_class"
@@ -60,6 +61,7 @@ type MockOrchestrator struct {
 	storageClasses     map[string]*storageclass.StorageClass
 	volumes            map[string]*storage.Volume
 	nodes              map[string]*utils.Node
+	transactions       map[string]*storage.VolumeTransaction
 	mutex              *sync.Mutex
 }
 
@@ -250,6 +252,77 @@ func (m *MockOrchestrator) UpdateBackendState(ctx context.Context, backendName,
 	return nil, fmt.Errorf("operation not currently supported")
 }
 
+// GetBackendVolumeReport lists the volumes on a backend
+func (m *MockOrchestrator) GetBackendVolumeReport(
+	ctx context.Context, backendName string,
+) (*storage.BackendVolumeReport, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	backend, err := m.getBackendByName(backendName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &storage.BackendVolumeReport{
+		Backend:     backend.Name,
+		BackendUUID: backend.BackendUUID,
+		State:       backend.State,
+		Volumes:     make([]*storage.BackendVolumeSummary, 0),
+	}
+	for _, vol := range m.volumes {
+		if vol.BackendUUID == backend.BackendUUID {
+			report.Volumes = append(report.Volumes, &storage.BackendVolumeSummary{
+				Name:         vol.Config.Name,
+				InternalName: vol.Config.InternalName,
+				Pool:         vol.Pool,
+				Size:         vol.Config.Size,
+				StorageClass: vol.Config.StorageClass,
+				State:        vol.State,
+			})
+		}
+	}
+	return report, nil
+}
+
+// DrainBackend lists the volumes on a backend, which the mock does not relocate
+func (m *MockOrchestrator) DrainBackend(
+	ctx context.Context, backendName string, concurrency int,
+) (*storage.BackendVolumeReport, error) {
+	report, err := m.GetBackendVolumeReport(ctx, backendName)
+	if err != nil {
+		return nil, err
+	}
+	if !report.State.IsMaintenance() {
+		return nil, utils.InvalidInputError(fmt.Sprintf("backend %s is not in maintenance", backendName))
+	}
+	return report, nil
+}
+
+// GetDriftReport returns an empty report, since the mock's backends cannot drift
+func (m *MockOrchestrator) GetDriftReport(ctx context.Context, refresh bool) (*storage.DriftReport, error) {
+	return &storage.DriftReport{
+		CheckTime: time.Now(),
+		Items:     make([]*storage.DriftItem, 0),
+	}, nil
+}
+
+// DeleteOrphanedVolumes fails, since the mock's backends have no orphaned volumes
+func (m *MockOrchestrator) DeleteOrphanedVolumes(
+	ctx context.Context, backendName string, internalNames []string, confirm bool,
+) (*storage.DriftReport, error) {
+	if !confirm {
+		return nil, utils.InvalidInputError("deleting orphaned volumes must be confirmed")
+	}
+	m.mutex.Lock()
+	_, err := m.getBackendByName(backendName)
+	m.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return nil, utils.InvalidInputError(fmt.Sprintf("backend %s has no orphaned volumes", backendName))
+}
+
 // RemoveBackendConfigRef sets backend configRef to empty and updates it.
 func (m *MockOrchestrator) RemoveBackendConfigRef(ctx context.Context, backendUUID,
 	configRef string) (err error) {
@@ -479,6 +552,18 @@ func (m *MockOrchestrator) GetVolume(ctx context.Context, volume string) (*stora
 	return vol.ConstructExternal(), nil
 }
 
+func (m *MockOrchestrator) GetVolumeCondition(
+	ctx context.Context, volumeName string,
+) (*storage.VolumeCondition, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, found := m.volumes[volumeName]; !found {
+		return nil, utils.NotFoundError("not found")
+	}
+	return storage.NewNormalVolumeCondition(), nil
+}
+
 func (m *MockOrchestrator) SetVolumeState(ctx context.Context, volumeName string, state storage.VolumeState) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -571,6 +656,18 @@ func (m *MockOrchestrator) PublishVolume(ctx context.Context, volumeName string,
 	return nil
 }
 
+func (m *MockOrchestrator) UnpublishVolume(ctx context.Context, volumeName, nodeName string) error {
+	return nil
+}
+
+func (m *MockOrchestrator) BackfillPublishedNodes(ctx context.Context, publishedNodes map[string][]string) error {
+	return nil
+}
+
+func (m *MockOrchestrator) PublishedNodesBackfilled() bool {
+	return true
+}
+
 func (m *MockOrchestrator) CreateSnapshot(ctx context.Context, snapshotConfig *storage.SnapshotConfig) (*storage.SnapshotExternal, error) {
 	return nil, nil
 }
@@ -607,6 +704,62 @@ func (m *MockOrchestrator) ResizeVolume(ctx context.Context, volumeName, newSize
 	return nil
 }
 
+func (m *MockOrchestrator) ModifyVolume(
+	ctx context.Context, volumeName string, modification *storage.VolumeModification,
+) (*storage.VolumeExternal, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	vol, found := m.volumes[volumeName]
+	if !found {
+		return nil, utils.NotFoundError("not found")
+	}
+	modification.Apply(vol.Config)
+	return vol.ConstructExternal(), nil
+}
+
+func (m *MockOrchestrator) RelocateVolume(
+	ctx context.Context, volumeName, backendName, poolName string,
+) (*storage.VolumeExternal, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	vol, found := m.volumes[volumeName]
+	if !found {
+		return nil, utils.NotFoundError("not found")
+	}
+	if poolName != "" {
+		vol.Pool = poolName
+	}
+	return vol.ConstructExternal(), nil
+}
+
+func (m *MockOrchestrator) EstablishMirror(
+	ctx context.Context, sourceVolume, destinationVolume, replicationPolicy, replicationSchedule string,
+) error {
+	return nil
+}
+
+func (m *MockOrchestrator) ReestablishMirror(
+	ctx context.Context, sourceVolume, destinationVolume, replicationPolicy, replicationSchedule string,
+) error {
+	return nil
+}
+
+func (m *MockOrchestrator) PromoteMirror(ctx context.Context, sourceVolume, destinationVolume string) error {
+	return nil
+}
+
+func (m *MockOrchestrator) GetMirrorStatus(
+	ctx context.Context, sourceVolume, destinationVolume string,
+) (*storage.MirrorStatus, error) {
+	return &storage.MirrorStatus{State: storage.MirrorStateMirrored, Healthy: true}, nil
+}
+
+func (m *MockOrchestrator) ReleaseMirror(ctx context.Context, sourceVolume, destinationVolume string) error {
+	return nil
+}
+
 func NewMockOrchestrator() *MockOrchestrator {
 	return &MockOrchestrator{
 		backendsByUUID:     make(map[string]*storage.Backend),
@@ -615,6 +768,7 @@ func NewMockOrchestrator() *MockOrchestrator {
 		// mockBackends:   make(map[string]*mockBackend),
 		storageClasses: make(map[string]*storageclass.StorageClass),
 		volumes:        make(map[string]*storage.Volume),
+		transactions:   make(map[string]*storage.VolumeTransaction),
 		mutex:          &sync.Mutex{},
 	}
 }
@@ -644,6 +798,18 @@ func (m *MockOrchestrator) ListStorageClasses(ctx context.Context) ([]*storagecl
 	return ret, nil
 }
 
+func (m *MockOrchestrator) GetStorageClassCapacity(
+	ctx context.Context, scName string, _ config.Protocol, _ map[string]string,
+) (*storage.PoolCapacity, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, found := m.storageClasses[scName]; !found {
+		return nil, utils.NotFoundError(fmt.Sprintf("storage class %s not found", scName))
+	}
+	return &storage.PoolCapacity{}, nil
+}
+
 func (m *MockOrchestrator) DeleteStorageClass(ctx context.Context, scName string) error {
 	if _, ok := m.storageClasses[scName]; !ok {
 		return utils.NotFoundError(fmt.Sprintf("storage class %s not found", scName))
@@ -690,13 +856,51 @@ func (m *MockOrchestrator) DeleteNode(ctx context.Context, nName string) error {
 }
 
 func (m *MockOrchestrator) AddVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.transactions[volTxn.Name()]; ok {
+		return utils.FoundError("volume transaction already exists")
+	}
+	m.transactions[volTxn.Name()] = volTxn
 	return nil
 }
 
 func (m *MockOrchestrator) GetVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) (*storage.VolumeTransaction, error) {
-	return nil, nil
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.transactions[volTxn.Name()], nil
+}
+
+func (m *MockOrchestrator) UpdateVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.transactions[volTxn.Name()] = volTxn
+	return nil
 }
 
 func (m *MockOrchestrator) DeleteVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.transactions, volTxn.Name())
+	return nil
+}
+
+func (m *MockOrchestrator) ListOperations(ctx context.Context) ([]*storage.OperationExternal, error) {
+	return make([]*storage.OperationExternal, 0), nil
+}
+
+func (m *MockOrchestrator) ExportState(
+	ctx context.Context, passphrase string,
+) (*persistentstore.StateArchive, error) {
+	return nil, nil
+}
+
+func (m *MockOrchestrator) ImportState(
+	ctx context.Context, archive *persistentstore.StateArchive, passphrase string,
+) error {
 	return nil
 }
@@ -22,19 +22,37 @@ const (
 func (o *TridentOrchestrator) StartTransactionMonitor(
 	ctx context.Context, txnPeriod time.Duration, txnMaxAge time.Duration) {
 
+	// Create the ticker and channel before starting the thread, so that a stop or restart
+	// cannot race with the thread that uses them
+	ticker := time.NewTicker(txnPeriod)
+	stopChannel := make(chan struct{})
+	o.txnMonitorTicker = ticker
+	o.txnMonitorChannel = stopChannel
+	o.txnMonitorStopped = false
+
+	o.mutex.Lock()
+	o.operationsStopped = false
+	o.mutex.Unlock()
+
 	go func() {
-		o.txnMonitorTicker = time.NewTicker(txnPeriod)
-		o.txnMonitorChannel = make(chan struct{})
 		Logc(ctx).Debug("Transaction monitor started.")
 
-		o.checkLongRunningTransactions(ctx, txnMaxAge)
+		// Skip the first check if the monitor was stopped before this thread ran, since journaled
+		// operations must not be resumed by a stopped orchestrator
+		select {
+		case <-stopChannel:
+			Logc(ctx).Debugf("Transaction monitor stopped.")
+			return
+		default:
+			o.checkLongRunningTransactions(ctx, txnMaxAge)
+		}
 
 		for {
 			select {
-			case tick := <-o.txnMonitorTicker.C:
+			case tick := <-ticker.C:
 				Logc(ctx).WithField("tick", tick).Debug("Transaction monitor running.")
 				o.checkLongRunningTransactions(ctx, txnMaxAge)
-			case <-o.txnMonitorChannel:
+			case <-stopChannel:
 				Logc(ctx).Debugf("Transaction monitor stopped.")
 				return
 			}
@@ -42,7 +60,8 @@ func (o *TridentOrchestrator) StartTransactionMonitor(
 	}()
 }
 
-// StopTransactionMonitor stops the thread that reaps abandoned long-running transactions.
+// StopTransactionMonitor stops the thread that reaps abandoned long-running transactions, along with
+// any journaled operations it is resuming in the background.
 func (o *TridentOrchestrator) StopTransactionMonitor() {
 	if o.txnMonitorTicker != nil {
 		o.txnMonitorTicker.Stop()
@@ -51,15 +70,17 @@ func (o *TridentOrchestrator) StopTransactionMonitor() {
 		close(o.txnMonitorChannel)
 		o.txnMonitorStopped = true
 	}
+	o.stopOperations()
 	log.Debug("Transaction monitor stopped.")
 }
 
 // checkLongRunningTransactions is called periodically by the transaction monitor to
-// see if any long-running transactions exist that have expired and must be reaped.
+// see if any long-running transactions exist that have expired and must be reaped, and
+// to resume any journaled operations that are not already being retried.
 func (o *TridentOrchestrator) checkLongRunningTransactions(ctx context.Context, txnMaxAge time.Duration) {
 
-	if o.bootstrapError != nil {
-		Logc(ctx).WithField("error", o.bootstrapError).Errorf("Transaction monitor blocked by bootstrap error.")
+	if err := o.getBootstrapError(); err != nil {
+		Logc(ctx).WithField("error", err).Errorf("Transaction monitor blocked by bootstrap error.")
 		return
 	}
 
@@ -80,6 +101,9 @@ func (o *TridentOrchestrator) checkLongRunningTransactions(ctx context.Context,
 		case storage.VolumeCreating:
 			txnMap[txn] = txn.VolumeCreatingConfig.StartTime
 		default:
+			if isJournaledOperation(txn) {
+				o.resumeOperation(ctx, txn)
+			}
 			continue
 		}
 	}
@@ -106,8 +130,8 @@ func (o *TridentOrchestrator) checkLongRunningTransactions(ctx context.Context,
 // storage resources associated with them are not orphaned indefinitely.
 func (o *TridentOrchestrator) reapLongRunningTransaction(ctx context.Context, txn *storage.VolumeTransaction) {
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	// Only volume creating transactions are reaped, so the volume lock suffices
+	defer lockVolumes(ctx, "reapLongRunningTransaction", txn.Name())()
 
 	Logc(ctx).WithFields(log.Fields{
 		"op":   txn.Op,
@@ -119,7 +143,10 @@ func (o *TridentOrchestrator) reapLongRunningTransaction(ctx context.Context, tx
 	case storage.VolumeCreating:
 
 		// If the volume was somehow fully created and the transaction was left around, don't delete the volume!
-		if _, found := o.volumes[txn.VolumeCreatingConfig.Name]; found {
+		o.mutex.RLock()
+		_, found := o.volumes[txn.VolumeCreatingConfig.Name]
+		o.mutex.RUnlock()
+		if found {
 
 			Logc(ctx).WithFields(log.Fields{
 				"volume": txn.VolumeCreatingConfig.Name,
@@ -128,8 +155,9 @@ func (o *TridentOrchestrator) reapLongRunningTransaction(ctx context.Context, tx
 		}
 
 		// Get the backend where this abandoned volume may still exist
-		backend, found := o.backends[txn.VolumeCreatingConfig.BackendUUID]
-		if !found {
+		backend, unlockBackend, err := o.rlockBackend(ctx, "reapLongRunningTransaction",
+			txn.VolumeCreatingConfig.BackendUUID)
+		if err != nil {
 
 			Logc(ctx).WithFields(log.Fields{
 				"backendUUID": txn.VolumeCreatingConfig.BackendUUID,
@@ -140,7 +168,9 @@ func (o *TridentOrchestrator) reapLongRunningTransaction(ctx context.Context, tx
 
 		// Delete the volume.  This should be safe since the transaction was left around and Trident doesn't
 		// know anything about the volume.
-		if err := backend.RemoveVolume(ctx, &txn.VolumeCreatingConfig.VolumeConfig); err != nil {
+		err = backend.RemoveVolume(ctx, &txn.VolumeCreatingConfig.VolumeConfig)
+		unlockBackend()
+		if err != nil {
 
 			Logc(ctx).WithFields(log.Fields{
 				"backendUUID": txn.VolumeCreatingConfig.BackendUUID,
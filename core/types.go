@@ -7,6 +7,7 @@ import (
 
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend"
+	persistentstore "github.com/netapp/trident/persistent_store"
 	"github.com/netapp/trident/storage"
 	storageclass "github.com/netapp/trident/storage_class"
 	"github.com/netapp/trident/utils"
@@ -30,6 +31,11 @@ type Orchestrator interface {
 		configRef string) (storageBackendExternal *storage.BackendExternal, err error)
 	UpdateBackendState(ctx context.Context, backendName, backendState string) (storageBackendExternal *storage.BackendExternal, err error)
 	RemoveBackendConfigRef(ctx context.Context, backendUUID, configRef string) (err error)
+	GetBackendVolumeReport(ctx context.Context, backendName string) (*storage.BackendVolumeReport, error)
+	DrainBackend(ctx context.Context, backendName string, concurrency int) (*storage.BackendVolumeReport, error)
+	GetDriftReport(ctx context.Context, refresh bool) (*storage.DriftReport, error)
+	DeleteOrphanedVolumes(ctx context.Context, backendName string, internalNames []string,
+		confirm bool) (*storage.DriftReport, error)
 
 	AddVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
 	AttachVolume(ctx context.Context, volumeName, mountpoint string, publishInfo *utils.VolumePublishInfo) error
@@ -37,6 +43,7 @@ type Orchestrator interface {
 	DetachVolume(ctx context.Context, volumeName, mountpoint string) error
 	DeleteVolume(ctx context.Context, volume string) error
 	GetVolume(ctx context.Context, volume string) (*storage.VolumeExternal, error)
+	GetVolumeCondition(ctx context.Context, volumeName string) (*storage.VolumeCondition, error)
 	GetVolumeExternal(ctx context.Context, volumeName string, backendName string) (*storage.VolumeExternal, error)
 	GetVolumeType(ctx context.Context, vol *storage.VolumeExternal) (config.VolumeType, error)
 	LegacyImportVolume(ctx context.Context, volumeConfig *storage.VolumeConfig, backendName string, notManaged bool, createPVandPVC VolumeCallback) (*storage.VolumeExternal, error)
@@ -44,7 +51,12 @@ type Orchestrator interface {
 	ListVolumes(ctx context.Context) ([]*storage.VolumeExternal, error)
 	ListVolumesByPlugin(ctx context.Context, pluginName string) ([]*storage.VolumeExternal, error)
 	PublishVolume(ctx context.Context, volumeName string, publishInfo *utils.VolumePublishInfo) error
+	UnpublishVolume(ctx context.Context, volumeName, nodeName string) error
+	BackfillPublishedNodes(ctx context.Context, publishedNodes map[string][]string) error
+	PublishedNodesBackfilled() bool
 	ResizeVolume(ctx context.Context, volumeName, newSize string) error
+	ModifyVolume(ctx context.Context, volumeName string, modification *storage.VolumeModification) (*storage.VolumeExternal, error)
+	RelocateVolume(ctx context.Context, volumeName, backendName, poolName string) (*storage.VolumeExternal, error)
 	SetVolumeState(ctx context.Context, volumeName string, state storage.VolumeState) error
 
 	CreateSnapshot(ctx context.Context, snapshotConfig *storage.SnapshotConfig) (*storage.SnapshotExternal, error)
@@ -55,6 +67,12 @@ type Orchestrator interface {
 	ReadSnapshotsForVolume(ctx context.Context, volumeName string) ([]*storage.SnapshotExternal, error)
 	DeleteSnapshot(ctx context.Context, volumeName, snapshotName string) error
 
+	EstablishMirror(ctx context.Context, sourceVolume, destinationVolume, replicationPolicy, replicationSchedule string) error
+	ReestablishMirror(ctx context.Context, sourceVolume, destinationVolume, replicationPolicy, replicationSchedule string) error
+	PromoteMirror(ctx context.Context, sourceVolume, destinationVolume string) error
+	GetMirrorStatus(ctx context.Context, sourceVolume, destinationVolume string) (*storage.MirrorStatus, error)
+	ReleaseMirror(ctx context.Context, sourceVolume, destinationVolume string) error
+
 	GetDriverTypeForVolume(ctx context.Context, vol *storage.VolumeExternal) (string, error)
 	ReloadVolumes(ctx context.Context) error
 
@@ -62,6 +80,8 @@ type Orchestrator interface {
 	DeleteStorageClass(ctx context.Context, scName string) error
 	GetStorageClass(ctx context.Context, scName string) (*storageclass.External, error)
 	ListStorageClasses(ctx context.Context) ([]*storageclass.External, error)
+	GetStorageClassCapacity(ctx context.Context, scName string, protocol config.Protocol,
+		topology map[string]string) (*storage.PoolCapacity, error)
 
 	AddNode(ctx context.Context, node *utils.Node, nodeEventCallback NodeEventCallback) error
 	GetNode(ctx context.Context, nName string) (*utils.Node, error)
@@ -70,7 +90,12 @@ type Orchestrator interface {
 
 	AddVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error
 	GetVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) (*storage.VolumeTransaction, error)
+	UpdateVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error
 	DeleteVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error
+	ListOperations(ctx context.Context) ([]*storage.OperationExternal, error)
+
+	ExportState(ctx context.Context, passphrase string) (*persistentstore.StateArchive, error)
+	ImportState(ctx context.Context, archive *persistentstore.StateArchive, passphrase string) error
 }
 
 type VolumeCallback func(*storage.VolumeExternal, string) error
@@ -0,0 +1,861 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/utils"
+)
+
+// These are variables so that unit tests may shorten them.
+var (
+	// relocationRetryInterval is the initial interval between attempts of a failed relocation step
+	relocationRetryInterval = 5 * time.Second
+	// relocationStepTimeout limits how long a failed relocation step is retried before the
+	// relocation is rolled back
+	relocationStepTimeout = 5 * time.Minute
+	// relocationPollInterval is the interval between checks on the progress of a data copy, which
+	// may take hours and so is not limited
+	relocationPollInterval = 30 * time.Second
+)
+
+// copyVolumeOnHost copies the data of a volume relocated by the copy method.  It is a variable so that
+// unit tests may replace it.
+var copyVolumeOnHost = utils.CopyVolume
+
+// RelocateVolume starts moving a volume to another storage pool that satisfies its storage class,
+// optionally limited to the named backend and pool.  A volume is moved between the pools of its
+// backend if the backend supports it.  Otherwise it is mirrored to a new volume on another backend of
+// the same type, or else copied to a new volume on another backend by the host on which Trident runs.
+// The data is copied in the background, during which the volume is in the relocating state, and the
+// volume's backend and pool are then swapped to the target.  The progress is recorded in a transaction
+// so that the relocation may be resumed if Trident restarts.
+func (o *TridentOrchestrator) RelocateVolume(
+	ctx context.Context, volumeName, backendName, poolName string,
+) (externalVol *storage.VolumeExternal, err error) {
+
+	if err := o.getBootstrapError(); err != nil {
+		return nil, err
+	}
+
+	defer recordTiming("volume_relocate", &err)()
+
+	defer lockVolumes(ctx, "RelocateVolume", volumeName)()
+
+	o.mutex.RLock()
+	volume, found := o.volumes[volumeName]
+	var (
+		sc            *storageclass.StorageClass
+		sourceBackend *storage.Backend
+		snapshots     []*storage.Snapshot
+		clones        []string
+	)
+	if found {
+		sc = o.storageClasses[volume.Config.StorageClass]
+		sourceBackend = o.backends[volume.BackendUUID]
+		snapshots, _ = o.volumeSnapshots(volumeName)
+		for _, vol := range o.volumes {
+			if vol.Config.CloneSourceVolume == volumeName {
+				clones = append(clones, vol.Config.Name)
+			}
+		}
+	}
+	o.mutex.RUnlock()
+
+	if !found {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	if volume.State.IsDeleting() {
+		return nil, utils.VolumeDeletingError(fmt.Sprintf("volume %s is deleting", volumeName))
+	}
+	if volume.State.IsRelocating() {
+		return nil, utils.VolumeRelocatingError(fmt.Sprintf("volume %s is already relocating", volumeName))
+	}
+	if volume.Orphaned {
+		return nil, utils.InvalidInputError(fmt.Sprintf("volume %s is orphaned and cannot be relocated",
+			volumeName))
+	}
+	if volume.Config.ImportNotManaged {
+		return nil, utils.InvalidInputError(fmt.Sprintf("volume %s is not managed by %s and cannot be relocated",
+			volumeName, config.OrchestratorName))
+	}
+	if volume.Config.IsMirrorDestination {
+		return nil, utils.InvalidInputError(fmt.Sprintf("volume %s is a mirror destination and cannot be "+
+			"relocated", volumeName))
+	}
+	if sc == nil {
+		return nil, utils.NotFoundError(fmt.Sprintf("storage class %s for volume %s not found",
+			volume.Config.StorageClass, volumeName))
+	}
+	if sourceBackend == nil {
+		return nil, utils.NotFoundError(fmt.Sprintf("backend %s for volume %s not found",
+			volume.BackendUUID, volumeName))
+	}
+
+	// Mirroring or copying to another backend transfers only the volume, and it requires that the
+	// volume not be written until the target takes its place, so it is limited to unpublished volumes
+	// that have no dependents.  The nodes to which a volume is published are only known once they have
+	// been backfilled from the container orchestrator, since volumes published by an older Trident have
+	// none recorded.
+	copyBlockers := make([]string, 0)
+	if !o.PublishedNodesBackfilled() {
+		copyBlockers = append(copyBlockers, "the nodes to which volumes are published are not yet known")
+	} else if len(volume.PublishedNodes) > 0 {
+		copyBlockers = append(copyBlockers, "the volume is published")
+	}
+	if len(snapshots) > 0 {
+		copyBlockers = append(copyBlockers, "the volume has snapshots")
+	}
+	if len(clones) > 0 {
+		copyBlockers = append(copyBlockers, fmt.Sprintf("the volume has clones %v", clones))
+	}
+
+	// Find a pool to which the volume may be relocated, in the order of the storage class placement policy
+	pools := sc.GetStoragePoolsForProtocolByBackend(ctx, volume.Config.Protocol, volume.Config.RequisiteTopologies,
+		volume.Config.PreferredTopologies, o.storagePoolUsage(sc.GetAttributes()))
+
+	var (
+		targetPool *storage.Pool
+		method     string
+	)
+	reasons := make([]string, 0)
+
+	for _, pool := range pools {
+
+		if backendName != "" && pool.Backend.Name != backendName {
+			continue
+		}
+		if poolName != "" && pool.Name != poolName {
+			continue
+		}
+		if pool.Backend.BackendUUID == volume.BackendUUID && pool.Name == volume.Pool {
+			continue
+		}
+
+		if !pool.Backend.State.IsOnline() {
+			reasons = append(reasons, fmt.Sprintf("backend %s is not online", pool.Backend.Name))
+			continue
+		}
+
+		if pool.Backend.BackendUUID == volume.BackendUUID {
+			if !pool.Backend.CanMoveVolume() {
+				reasons = append(reasons, fmt.Sprintf("backend %s cannot move volumes between its pools",
+					pool.Backend.Name))
+				continue
+			}
+			targetPool, method = pool, storage.RelocationMethodMove
+			break
+		}
+
+		if len(copyBlockers) > 0 {
+			reasons = append(reasons, fmt.Sprintf("volume cannot be copied to backend %s because %s",
+				pool.Backend.Name, strings.Join(copyBlockers, " and ")))
+			continue
+		}
+
+		// Only backends of the same type can mirror to each other, so the data is otherwise copied on the host
+		targetPool, method = pool, storage.RelocationMethodCopy
+		if pool.Backend.GetDriverName() == sourceBackend.GetDriverName() && pool.Backend.CanMirror() &&
+			sourceBackend.CanMirror() {
+			method = storage.RelocationMethodMirror
+		}
+		break
+	}
+
+	if targetPool == nil {
+		if len(reasons) == 0 {
+			return nil, utils.NotFoundError(fmt.Sprintf("no other storage pool for volume %s matches storage "+
+				"class %s", volumeName, volume.Config.StorageClass))
+		}
+		return nil, utils.UnsupportedError(fmt.Sprintf("volume %s cannot be relocated: %s", volumeName,
+			strings.Join(reasons, "; ")))
+	}
+
+	relocation := &storage.VolumeRelocationConfig{
+		StartTime:         time.Now(),
+		Method:            method,
+		Phase:             storage.RelocationPhaseCopying,
+		SourceBackendUUID: volume.BackendUUID,
+		SourcePool:        volume.Pool,
+		TargetBackendUUID: targetPool.Backend.BackendUUID,
+		TargetPool:        targetPool.Name,
+	}
+	if method != storage.RelocationMethodMove {
+		relocation.Phase = storage.RelocationPhaseProvisioning
+		if relocation.TargetConfig, err = o.prepareRelocationTarget(ctx, volume.Config, relocation); err != nil {
+			return nil, err
+		}
+	}
+
+	txn := &storage.VolumeTransaction{
+		Config:                 volume.Config.ConstructClone(),
+		VolumeRelocationConfig: relocation,
+		Op:                     storage.RelocateVolume,
+	}
+	if err = o.AddVolumeTransaction(ctx, txn); err != nil {
+		return nil, err
+	}
+
+	o.mutex.Lock()
+	previousState := volume.State
+	volume.State = storage.VolumeStateRelocating
+	o.mutex.Unlock()
+
+	if err = o.updateVolumeOnPersistentStore(ctx, volume); err != nil {
+		o.mutex.Lock()
+		volume.State = previousState
+		o.mutex.Unlock()
+		if txnErr := o.DeleteVolumeTransaction(ctx, txn); txnErr != nil {
+			Logc(ctx).WithField("volume", volumeName).WithError(txnErr).Error(
+				"Unable to delete volume relocation transaction.")
+		}
+		return nil, err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":        volumeName,
+		"method":        method,
+		"sourceBackend": sourceBackend.Name,
+		"sourcePool":    relocation.SourcePool,
+		"targetBackend": targetPool.Backend.Name,
+		"targetPool":    relocation.TargetPool,
+	}).Info("Orchestrator started relocating the volume.")
+
+	go o.relocateVolume(GenerateRequestContext(nil, "", ContextSourceInternal), txn)
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return volume.ConstructExternal(), nil
+}
+
+// prepareRelocationTarget returns the config of the volume to be created on the target backend of a
+// mirror or copy relocation.
+func (o *TridentOrchestrator) prepareRelocationTarget(
+	ctx context.Context, volConfig *storage.VolumeConfig, relocation *storage.VolumeRelocationConfig,
+) (*storage.VolumeConfig, error) {
+
+	targetConfig := volConfig.ConstructClone()
+	targetConfig.AccessInfo = utils.VolumeAccessInfo{}
+	targetConfig.CloneSourceVolume = ""
+	targetConfig.CloneSourceVolumeInternal = ""
+	targetConfig.CloneSourceSnapshot = ""
+	targetConfig.IsMirrorDestination = relocation.Method == storage.RelocationMethodMirror
+
+	if relocation.Method == storage.RelocationMethodCopy {
+		err := o.useRelocationBackend(ctx, relocation.TargetBackendUUID, func(backend *storage.Backend) error {
+			backend.Driver.CreatePrepare(ctx, targetConfig)
+			// Backends that share storage would otherwise find the volume itself in place of the target
+			if backend.Driver.Get(ctx, targetConfig.InternalName) == nil {
+				return utils.UnsupportedError(fmt.Sprintf("volume %s cannot be copied to backend %s, on which "+
+					"%s already exists", volConfig.Name, backend.Name, targetConfig.InternalName))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return targetConfig, nil
+	}
+
+	var sourceHandle, targetHandle string
+
+	err := o.useRelocationBackend(ctx, relocation.TargetBackendUUID, func(backend *storage.Backend) (err error) {
+		// CreatePrepare has a side effect that updates the config with the backend-specific internal name
+		backend.Driver.CreatePrepare(ctx, targetConfig)
+		targetHandle, err = backend.GetMirrorVolumeHandle(ctx, targetConfig)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = o.useRelocationBackend(ctx, relocation.SourceBackendUUID, func(backend *storage.Backend) (err error) {
+		sourceHandle, err = backend.GetMirrorVolumeHandle(ctx, volConfig)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Backends that share storage would otherwise find the volume itself in place of the target
+	if sourceHandle == targetHandle {
+		return nil, utils.UnsupportedError(fmt.Sprintf("volume %s cannot be mirrored to itself (%s)",
+			volConfig.Name, targetHandle))
+	}
+
+	return targetConfig, nil
+}
+
+// relocateVolume carries out the relocation recorded in a transaction, starting from the phase that
+// was last saved.  It runs in its own goroutine, and it rolls the relocation back if any phase fails
+// before the volume is swapped to the target.
+func (o *TridentOrchestrator) relocateVolume(ctx context.Context, txn *storage.VolumeTransaction) {
+
+	relocation := txn.VolumeRelocationConfig
+	logFields := log.Fields{
+		"volume":            txn.Config.Name,
+		"method":            relocation.Method,
+		"sourceBackendUUID": relocation.SourceBackendUUID,
+		"sourcePool":        relocation.SourcePool,
+		"targetBackendUUID": relocation.TargetBackendUUID,
+		"targetPool":        relocation.TargetPool,
+	}
+
+	var err error
+	for err == nil && relocation.Phase != storage.RelocationPhaseSwapped {
+
+		Logc(ctx).WithFields(logFields).WithField("phase", relocation.Phase).Debug("Relocating volume.")
+
+		var nextPhase string
+		switch relocation.Phase {
+		case storage.RelocationPhaseProvisioning:
+			err = o.provisionRelocationTarget(ctx, txn)
+			nextPhase = storage.RelocationPhaseCopying
+		case storage.RelocationPhaseCopying:
+			switch relocation.Method {
+			case storage.RelocationMethodMove:
+				err = o.moveRelocatedVolume(ctx, txn)
+			case storage.RelocationMethodCopy:
+				err = o.copyRelocatedVolume(ctx, txn)
+			default:
+				err = o.mirrorRelocatedVolume(ctx, txn)
+			}
+			nextPhase = storage.RelocationPhaseCutover
+		case storage.RelocationPhaseCutover:
+			err = o.cutOverRelocatedVolume(ctx, txn)
+			nextPhase = storage.RelocationPhaseSwapped
+		default:
+			err = fmt.Errorf("unknown relocation phase %s", relocation.Phase)
+		}
+
+		if err == nil {
+			relocation.Phase = nextPhase
+			err = retryRelocationStep(ctx, "save phase", func() error {
+				return o.storeClient.UpdateVolumeTransaction(ctx, txn)
+			})
+		}
+	}
+
+	if err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Unable to relocate the volume, rolling back.")
+		if err = o.rollBackVolumeRelocation(ctx, txn); err != nil {
+			Logc(ctx).WithFields(logFields).WithError(err).Errorf(
+				"Unable to roll back the volume relocation. It will be retried when %s restarts.",
+				config.OrchestratorName)
+		}
+		return
+	}
+
+	if err = o.finishVolumeRelocation(ctx, txn); err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Errorf(
+			"Unable to finish the volume relocation. It will be retried when %s restarts.",
+			config.OrchestratorName)
+		return
+	}
+
+	Logc(ctx).WithFields(logFields).Info("Orchestrator relocated the volume.")
+}
+
+// provisionRelocationTarget creates the volume on the target backend of a mirror or copy relocation.
+func (o *TridentOrchestrator) provisionRelocationTarget(ctx context.Context, txn *storage.VolumeTransaction) error {
+
+	relocation := txn.VolumeRelocationConfig
+
+	o.mutex.RLock()
+	sc, ok := o.storageClasses[txn.Config.StorageClass]
+	o.mutex.RUnlock()
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("storage class %s not found", txn.Config.StorageClass))
+	}
+
+	// The target is placed according to the storage class's current placement policy
+	relocation.TargetConfig.PlacementPolicy = sc.GetPlacementPolicy(ctx).Name()
+
+	return retryRelocationStep(ctx, "provision target", func() error {
+		return o.useRelocationBackend(ctx, relocation.TargetBackendUUID, func(backend *storage.Backend) error {
+			pool, ok := backend.Storage[relocation.TargetPool]
+			if !ok {
+				return backoff.Permanent(utils.NotFoundError(fmt.Sprintf("pool %s for backend %s not found",
+					relocation.TargetPool, backend.Name)))
+			}
+			vol, err := backend.AddVolume(ctx, relocation.TargetConfig, pool, sc.GetAttributes(), false)
+			if err != nil {
+				return err
+			}
+			relocation.TargetConfig = vol.Config
+			return nil
+		})
+	})
+}
+
+// moveRelocatedVolume moves a volume between the pools of its backend and waits for the move to finish.
+func (o *TridentOrchestrator) moveRelocatedVolume(ctx context.Context, txn *storage.VolumeTransaction) error {
+
+	relocation := txn.VolumeRelocationConfig
+
+	err := retryRelocationStep(ctx, "start move", func() error {
+		return o.useRelocationBackend(ctx, relocation.SourceBackendUUID, func(backend *storage.Backend) error {
+			if err := backend.MoveVolume(ctx, txn.Config, relocation.TargetPool); err != nil {
+				if utils.IsUnsupportedError(err) {
+					return backoff.Permanent(err)
+				}
+				return err
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return pollRelocation(ctx, "move", func() error {
+		return o.useRelocationBackend(ctx, relocation.SourceBackendUUID, func(backend *storage.Backend) error {
+			status, err := backend.GetVolumeMoveStatus(ctx, txn.Config, relocation.TargetPool)
+			if err != nil {
+				return err
+			}
+			switch status.State {
+			case storage.VolumeMoveStateComplete:
+				return nil
+			case storage.VolumeMoveStateFailed:
+				return backoff.Permanent(fmt.Errorf("move of volume %s to pool %s failed: %s",
+					txn.Config.Name, relocation.TargetPool, status.Message))
+			default:
+				return fmt.Errorf("move of volume %s is %d%% complete", txn.Config.Name, status.PercentComplete)
+			}
+		})
+	})
+}
+
+// mirrorRelocatedVolume mirrors a volume to the target volume and waits for the data to be transferred.
+func (o *TridentOrchestrator) mirrorRelocatedVolume(ctx context.Context, txn *storage.VolumeTransaction) error {
+
+	relocation := txn.VolumeRelocationConfig
+
+	var sourceHandle string
+	err := retryRelocationStep(ctx, "establish mirror", func() (err error) {
+		if sourceHandle, _, err = o.getRelocationMirrorHandles(ctx, txn); err != nil {
+			return err
+		}
+		return o.useRelocationBackend(ctx, relocation.TargetBackendUUID, func(backend *storage.Backend) error {
+			return backend.EstablishMirror(ctx, relocation.TargetConfig, sourceHandle, "", "")
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return pollRelocation(ctx, "mirror", func() error {
+		return o.useRelocationBackend(ctx, relocation.TargetBackendUUID, func(backend *storage.Backend) error {
+			status, err := backend.GetMirrorStatus(ctx, relocation.TargetConfig, sourceHandle)
+			if err != nil {
+				return err
+			}
+			if status.State != storage.MirrorStateMirrored || status.Transferring {
+				return fmt.Errorf("mirror of volume %s is %s", txn.Config.Name, status.State)
+			}
+			return nil
+		})
+	})
+}
+
+// cutOverRelocatedVolume makes the target volume of a mirror relocation writable and independent of
+// the source volume, and then swaps the volume to its target backend and pool.  The target volume of a
+// copy relocation is already independent, so it is only swapped.  Each step may be
+// repeated safely, so that a relocation interrupted here may be resumed.
+func (o *TridentOrchestrator) cutOverRelocatedVolume(ctx context.Context, txn *storage.VolumeTransaction) error {
+
+	relocation := txn.VolumeRelocationConfig
+
+	if relocation.Method == storage.RelocationMethodMirror {
+		err := retryRelocationStep(ctx, "promote mirror", func() error {
+			sourceHandle, targetHandle, err := o.getRelocationMirrorHandles(ctx, txn)
+			if err != nil {
+				return err
+			}
+			err = o.useRelocationBackend(ctx, relocation.TargetBackendUUID, func(backend *storage.Backend) error {
+				if err := backend.PromoteMirror(ctx, relocation.TargetConfig, sourceHandle); err != nil {
+					return err
+				}
+				return backend.DeleteMirror(ctx, relocation.TargetConfig, sourceHandle)
+			})
+			if err != nil {
+				return err
+			}
+			return o.useRelocationBackend(ctx, relocation.SourceBackendUUID, func(backend *storage.Backend) error {
+				return backend.ReleaseMirror(ctx, txn.Config, targetHandle)
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return o.swapRelocatedVolume(ctx, txn)
+}
+
+// swapRelocatedVolume points a volume at the target backend and pool of its relocation.
+func (o *TridentOrchestrator) swapRelocatedVolume(ctx context.Context, txn *storage.VolumeTransaction) error {
+
+	relocation := txn.VolumeRelocationConfig
+
+	defer lockVolumes(ctx, "relocateVolume", txn.Config.Name)()
+
+	o.mutex.RLock()
+	volume, ok := o.volumes[txn.Config.Name]
+	var swappedVolume storage.Volume
+	if ok {
+		swappedVolume = *volume
+	}
+	o.mutex.RUnlock()
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", txn.Config.Name))
+	}
+
+	// Persist the swapped volume before updating the one in memory, so that both agree if this fails
+	swappedVolume.BackendUUID = relocation.TargetBackendUUID
+	swappedVolume.Pool = relocation.TargetPool
+	if relocation.Method != storage.RelocationMethodMove {
+		swappedVolume.Config = relocation.TargetConfig.ConstructClone()
+		swappedVolume.Config.IsMirrorDestination = false
+	}
+	err := retryRelocationStep(ctx, "swap volume", func() error {
+		return o.storeClient.UpdateVolume(ctx, &swappedVolume)
+	})
+	if err != nil {
+		return err
+	}
+
+	o.mutex.Lock()
+	volume.BackendUUID = swappedVolume.BackendUUID
+	volume.Pool = swappedVolume.Pool
+	volume.Config = swappedVolume.Config
+	o.mutex.Unlock()
+
+	if relocation.Method != storage.RelocationMethodMove {
+		targetBackend, unlockBackend, err := o.rlockBackend(ctx, "relocateVolume", relocation.TargetBackendUUID)
+		if err == nil {
+			targetBackend.AddCachedVolume(volume)
+			unlockBackend()
+		}
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":            volume.Config.Name,
+		"targetBackendUUID": relocation.TargetBackendUUID,
+		"targetPool":        relocation.TargetPool,
+	}).Debug("Swapped volume to its relocation target.")
+
+	return nil
+}
+
+// finishVolumeRelocation removes the source volume of a mirror or copy relocation and then ends the
+// relocation.
+func (o *TridentOrchestrator) finishVolumeRelocation(ctx context.Context, txn *storage.VolumeTransaction) error {
+
+	relocation := txn.VolumeRelocationConfig
+
+	if relocation.Method != storage.RelocationMethodMove {
+		err := retryRelocationStep(ctx, "remove source", func() error {
+			return o.useRelocationBackend(ctx, relocation.SourceBackendUUID, func(backend *storage.Backend) error {
+				return backend.RemoveVolume(ctx, txn.Config)
+			})
+		})
+		if err != nil && !utils.IsNotFoundError(err) {
+			return err
+		}
+		if err = o.deleteBackendIfEmpty(ctx, relocation.SourceBackendUUID); err != nil {
+			Logc(ctx).WithField("backendUUID", relocation.SourceBackendUUID).WithError(err).Warning(
+				"Unable to delete the deleting backend after its last volume was relocated.")
+		}
+	}
+
+	return o.endVolumeRelocation(ctx, txn)
+}
+
+// rollBackVolumeRelocation undoes a relocation that failed, leaving the volume where it was.  If the
+// volume was already swapped to its target, the relocation is finished instead.
+func (o *TridentOrchestrator) rollBackVolumeRelocation(ctx context.Context, txn *storage.VolumeTransaction) error {
+
+	relocation := txn.VolumeRelocationConfig
+
+	o.mutex.RLock()
+	volume, ok := o.volumes[txn.Config.Name]
+	swapped := ok && volume.BackendUUID == relocation.TargetBackendUUID && volume.Pool == relocation.TargetPool
+	o.mutex.RUnlock()
+	if swapped {
+		return o.finishVolumeRelocation(ctx, txn)
+	}
+
+	if relocation.Method == storage.RelocationMethodMirror && relocation.TargetConfig != nil {
+		err := retryRelocationStep(ctx, "remove target", func() error {
+			sourceHandle, targetHandle, err := o.getRelocationMirrorHandles(ctx, txn)
+			if err != nil {
+				return err
+			}
+			err = o.useRelocationBackend(ctx, relocation.TargetBackendUUID, func(backend *storage.Backend) error {
+				if err := backend.DeleteMirror(ctx, relocation.TargetConfig, sourceHandle); err != nil {
+					return err
+				}
+				return backend.RemoveVolume(ctx, relocation.TargetConfig)
+			})
+			if err != nil {
+				return err
+			}
+			return o.useRelocationBackend(ctx, relocation.SourceBackendUUID, func(backend *storage.Backend) error {
+				return backend.ReleaseMirror(ctx, txn.Config, targetHandle)
+			})
+		})
+		if err != nil && !utils.IsNotFoundError(err) {
+			return err
+		}
+	}
+
+	if relocation.Method == storage.RelocationMethodCopy && relocation.TargetConfig != nil {
+		err := retryRelocationStep(ctx, "remove target", func() error {
+			return o.useRelocationBackend(ctx, relocation.TargetBackendUUID, func(backend *storage.Backend) error {
+				return backend.RemoveVolume(ctx, relocation.TargetConfig)
+			})
+		})
+		if err != nil && !utils.IsNotFoundError(err) {
+			return err
+		}
+	}
+
+	return o.endVolumeRelocation(ctx, txn)
+}
+
+// endVolumeRelocation returns a volume to the online state and deletes its relocation transaction.
+func (o *TridentOrchestrator) endVolumeRelocation(ctx context.Context, txn *storage.VolumeTransaction) error {
+
+	defer lockVolumes(ctx, "relocateVolume", txn.Config.Name)()
+
+	o.mutex.Lock()
+	volume, ok := o.volumes[txn.Config.Name]
+	if ok {
+		volume.State = storage.VolumeStateOnline
+	}
+	o.mutex.Unlock()
+
+	if ok {
+		err := retryRelocationStep(ctx, "update volume", func() error {
+			return o.updateVolumeOnPersistentStore(ctx, volume)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return retryRelocationStep(ctx, "delete transaction", func() error {
+		return o.DeleteVolumeTransaction(ctx, txn)
+	})
+}
+
+// copyRelocatedVolume publishes the source and target volumes of a copy relocation to the local host,
+// copies the data between them there, and unpublishes them again.  A copy interrupted by a restart is
+// simply repeated.
+func (o *TridentOrchestrator) copyRelocatedVolume(ctx context.Context, txn *storage.VolumeTransaction) error {
+
+	relocation := txn.VolumeRelocationConfig
+
+	sourcePublishInfo := o.getLocalRelocationPublishInfo(ctx, relocation.SourceBackendUUID)
+	err := retryRelocationStep(ctx, "publish source", func() error {
+		return o.useRelocationBackend(ctx, relocation.SourceBackendUUID, func(backend *storage.Backend) error {
+			return backend.PublishVolume(ctx, txn.Config, sourcePublishInfo)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	defer o.unpublishLocalRelocationVolume(ctx, txn.Config, relocation.SourceBackendUUID)
+
+	targetPublishInfo := o.getLocalRelocationPublishInfo(ctx, relocation.TargetBackendUUID)
+	err = retryRelocationStep(ctx, "publish target", func() error {
+		return o.useRelocationBackend(ctx, relocation.TargetBackendUUID, func(backend *storage.Backend) error {
+			return backend.PublishVolume(ctx, relocation.TargetConfig, targetPublishInfo)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	defer o.unpublishLocalRelocationVolume(ctx, relocation.TargetConfig, relocation.TargetBackendUUID)
+
+	workDir := filepath.Join(os.TempDir(), "trident-relocation-"+txn.Config.Name)
+
+	return retryRelocationStep(ctx, "copy data", func() error {
+		err := copyVolumeOnHost(ctx, workDir, txn.Config.InternalName, sourcePublishInfo,
+			relocation.TargetConfig.InternalName, targetPublishInfo)
+		if utils.IsUnsupportedError(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	})
+}
+
+// unpublishLocalRelocationVolume removes the local host's access to a volume published for a copy
+// relocation.  Failures are only logged, since the volume is no longer used by the relocation.
+func (o *TridentOrchestrator) unpublishLocalRelocationVolume(
+	ctx context.Context, volConfig *storage.VolumeConfig, backendUUID string,
+) {
+
+	publishInfo := o.getLocalRelocationPublishInfo(ctx, backendUUID)
+	err := o.useRelocationBackend(ctx, backendUUID, func(backend *storage.Backend) error {
+		return backend.UnpublishVolume(ctx, volConfig, publishInfo)
+	})
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"volume":      volConfig.Name,
+			"backendUUID": backendUUID,
+		}).WithError(err).Warning("Unable to unpublish the relocated volume from the local host.")
+	}
+}
+
+// getLocalRelocationPublishInfo returns the publish info with which the volumes of a copy relocation are
+// published to the local host.  The local host is added to the known nodes, since Trident's controller
+// need not run on a node that has registered with it.
+func (o *TridentOrchestrator) getLocalRelocationPublishInfo(
+	ctx context.Context, backendUUID string,
+) *utils.VolumePublishInfo {
+
+	localNode := &utils.Node{
+		Name:     localNodeName(ctx),
+		NodePrep: &utils.NodePrep{},
+	}
+	if iqns, err := utils.GetInitiatorIqns(ctx); err != nil {
+		Logc(ctx).WithError(err).Debug("Could not determine local initiator IQN.")
+	} else if len(iqns) > 0 {
+		localNode.IQN = iqns[0]
+	}
+	if ips, err := utils.GetIPAddresses(ctx); err != nil {
+		Logc(ctx).WithError(err).Debug("Could not determine local IP addresses.")
+	} else {
+		localNode.IPs = ips
+	}
+
+	o.mutex.RLock()
+	nodes := make([]*utils.Node, 0, len(o.nodes)+1)
+	for _, node := range o.nodes {
+		if node.Name != localNode.Name {
+			nodes = append(nodes, node)
+		}
+	}
+	o.mutex.RUnlock()
+
+	publishInfo := &utils.VolumePublishInfo{
+		HostIP:      localNode.IPs,
+		BackendUUID: backendUUID,
+		Nodes:       append(nodes, localNode),
+		HostName:    localNode.Name,
+	}
+	if localNode.IQN != "" {
+		publishInfo.HostIQN = []string{localNode.IQN}
+	}
+	return publishInfo
+}
+
+// getRelocationMirrorHandles returns the mirror handles of the source and target volumes of a
+// mirror relocation.
+func (o *TridentOrchestrator) getRelocationMirrorHandles(
+	ctx context.Context, txn *storage.VolumeTransaction,
+) (sourceHandle, targetHandle string, err error) {
+
+	relocation := txn.VolumeRelocationConfig
+
+	err = o.useRelocationBackend(ctx, relocation.SourceBackendUUID, func(backend *storage.Backend) (err error) {
+		sourceHandle, err = backend.GetMirrorVolumeHandle(ctx, txn.Config)
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+	err = o.useRelocationBackend(ctx, relocation.TargetBackendUUID, func(backend *storage.Backend) (err error) {
+		targetHandle, err = backend.GetMirrorVolumeHandle(ctx, relocation.TargetConfig)
+		return err
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return sourceHandle, targetHandle, nil
+}
+
+// useRelocationBackend invokes a function with one of the backends of a relocation while holding a
+// shared lock on the backend.  A missing backend is a permanent failure of the relocation step.
+func (o *TridentOrchestrator) useRelocationBackend(
+	ctx context.Context, backendUUID string, f func(*storage.Backend) error,
+) error {
+
+	backend, unlockBackend, err := o.rlockBackend(ctx, "relocateVolume", backendUUID)
+	if err != nil {
+		return backoff.Permanent(err)
+	}
+	defer unlockBackend()
+
+	return f(backend)
+}
+
+// checkVolumeRelocation returns a VolumeRelocatingError if a volume is being relocated, unless the
+// operation may continue while the volume is moved between the pools of its backend.  The caller
+// must hold the volume's lock.
+func (o *TridentOrchestrator) checkVolumeRelocation(
+	ctx context.Context, volume *storage.Volume, allowedDuringMove bool,
+) error {
+
+	if !volume.State.IsRelocating() {
+		return nil
+	}
+
+	if allowedDuringMove {
+		txn, err := o.storeClient.GetExistingVolumeTransaction(ctx, &storage.VolumeTransaction{
+			Config: volume.Config,
+			Op:     storage.RelocateVolume,
+		})
+		if err == nil && txn != nil && txn.Op == storage.RelocateVolume && txn.VolumeRelocationConfig != nil &&
+			txn.VolumeRelocationConfig.Method == storage.RelocationMethodMove {
+			return nil
+		}
+	}
+
+	return utils.VolumeRelocatingError(fmt.Sprintf("volume %s is relocating", volume.Config.Name))
+}
+
+// retryRelocationStep invokes a relocation step until it succeeds, it fails permanently, or
+// relocationStepTimeout elapses.
+func retryRelocationStep(ctx context.Context, step string, f func() error) error {
+
+	stepNotify := func(err error, duration time.Duration) {
+		Logc(ctx).WithFields(log.Fields{
+			"step":      step,
+			"increment": duration,
+		}).WithError(err).Debug("Relocation step failed, retrying.")
+	}
+	stepBackoff := backoff.NewExponentialBackOff()
+	stepBackoff.InitialInterval = relocationRetryInterval
+	stepBackoff.Multiplier = 2
+	stepBackoff.RandomizationFactor = 0.1
+	stepBackoff.MaxElapsedTime = relocationStepTimeout
+
+	return backoff.RetryNotify(f, stepBackoff, stepNotify)
+}
+
+// pollRelocation invokes a check on the progress of a data copy every relocationPollInterval until
+// the check succeeds or fails permanently.
+func pollRelocation(ctx context.Context, copyType string, f func() error) error {
+
+	pollNotify := func(err error, duration time.Duration) {
+		Logc(ctx).WithField("copy", copyType).WithError(err).Debug("Waiting for relocation copy.")
+	}
+
+	return backoff.RetryNotify(f, backoff.NewConstantBackOff(relocationPollInterval), pollNotify)
+}
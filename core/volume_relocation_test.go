@@ -0,0 +1,429 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage/fake"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	fakedriver "github.com/netapp/trident/storage_drivers/fake"
+	tu "github.com/netapp/trident/storage_drivers/fake/test_utils"
+	"github.com/netapp/trident/utils"
+)
+
+// shortenRelocationIntervals speeds up relocations for testing and returns a function that
+// restores the original intervals.
+func shortenRelocationIntervals() func() {
+	retryInterval, stepTimeout, pollInterval := relocationRetryInterval, relocationStepTimeout,
+		relocationPollInterval
+	relocationRetryInterval = 10 * time.Millisecond
+	relocationStepTimeout = 1 * time.Second
+	relocationPollInterval = 10 * time.Millisecond
+	return func() {
+		relocationRetryInterval, relocationStepTimeout, relocationPollInterval = retryInterval, stepTimeout,
+			pollInterval
+	}
+}
+
+// addRelocationBackend adds a fake backend with the named pools, all of which match the storage
+// class added by addRelocationStorageClass.
+func addRelocationBackend(t *testing.T, o *TridentOrchestrator, backendName string, poolNames ...string) {
+
+	pools := make(map[string]*fake.StoragePool)
+	for _, poolName := range poolNames {
+		pools[poolName] = &fake.StoragePool{
+			Attrs: map[string]sa.Offer{
+				sa.Media:            sa.NewStringOffer("hdd"),
+				sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+			},
+			Bytes: 100 * 1024 * 1024 * 1024,
+		}
+	}
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(backendName, config.File, pools, []fake.Volume{})
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+	if _, err = o.AddBackend(ctx(), configJSON, ""); err != nil {
+		t.Fatalf("Unable to add backend: %v", err)
+	}
+}
+
+func addRelocationStorageClass(t *testing.T, o *TridentOrchestrator, scName string) {
+	_, err := o.AddStorageClass(ctx(), &storageclass.Config{
+		Name: scName,
+		Attributes: map[string]sa.Request{
+			sa.Media:            sa.NewStringRequest("hdd"),
+			sa.ProvisioningType: sa.NewStringRequest("thick"),
+		},
+	})
+	if err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+}
+
+// waitForRelocation waits for a volume to leave the relocating state and returns the volume.
+func waitForRelocation(t *testing.T, o *TridentOrchestrator, volumeName string) *storage.VolumeExternal {
+
+	var volume *storage.VolumeExternal
+	assert.Eventually(t, func() bool {
+		var err error
+		volume, err = o.GetVolume(ctx(), volumeName)
+		return err == nil && !volume.State.IsRelocating()
+	}, 10*time.Second, 10*time.Millisecond, "Volume did not finish relocating")
+	return volume
+}
+
+func getFakeDriver(t *testing.T, o *TridentOrchestrator, backendUUID string) *fakedriver.StorageDriver {
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	backend, ok := o.backends[backendUUID]
+	if !ok {
+		t.Fatalf("Backend %s not found", backendUUID)
+	}
+	return backend.Driver.(*fakedriver.StorageDriver)
+}
+
+func TestRelocateVolumeBetweenPools(t *testing.T) {
+	const (
+		backendName = "relocatePoolsBackend"
+		scName      = "relocatePoolsSC"
+		volumeName  = "relocatePoolsVolume"
+	)
+
+	defer shortenRelocationIntervals()()
+
+	orchestrator := getOrchestrator()
+	addRelocationBackend(t, orchestrator, backendName, "pool-a", "pool-b")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	original, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	targetPool := "pool-a"
+	if original.Pool == targetPool {
+		targetPool = "pool-b"
+	}
+
+	// The volume cannot be relocated to the pool on which it already resides
+	_, err = orchestrator.RelocateVolume(ctx(), volumeName, backendName, original.Pool)
+	assert.True(t, utils.IsNotFoundError(err), "Expected a not found error")
+
+	_, err = orchestrator.RelocateVolume(ctx(), "missing", "", "")
+	assert.True(t, utils.IsNotFoundError(err), "Expected a not found error")
+
+	started, err := orchestrator.RelocateVolume(ctx(), volumeName, "", "")
+	if err != nil {
+		t.Fatalf("Unable to relocate volume: %v", err)
+	}
+	assert.Equal(t, original.BackendUUID, started.BackendUUID)
+
+	relocated := waitForRelocation(t, orchestrator, volumeName)
+	assert.Equal(t, storage.VolumeStateOnline, relocated.State)
+	assert.Equal(t, original.BackendUUID, relocated.BackendUUID)
+	assert.Equal(t, targetPool, relocated.Pool)
+	assert.Equal(t, original.Config.InternalName, relocated.Config.InternalName)
+
+	// The volume was moved on the backend, and the relocation is persisted and complete
+	driver := getFakeDriver(t, orchestrator, original.BackendUUID)
+	assert.Equal(t, targetPool, driver.Volumes[original.Config.InternalName].PhysicalPool)
+	persistentVolume, err := orchestrator.storeClient.GetVolume(ctx(), volumeName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, targetPool, persistentVolume.Pool)
+		assert.Equal(t, string(storage.VolumeStateOnline), string(persistentVolume.State))
+	}
+	txns, err := orchestrator.storeClient.GetVolumeTransactions(ctx())
+	assert.NoError(t, err)
+	assert.Empty(t, txns)
+
+	cleanup(t, orchestrator)
+}
+
+func TestRelocateVolumeBetweenBackends(t *testing.T) {
+	const (
+		sourceBackendName = "relocateSourceBackend"
+		targetBackendName = "relocateTargetBackend"
+		scName            = "relocateBackendsSC"
+		volumeName        = "relocateBackendsVolume"
+		snapVolumeName    = "relocateSnapVolume"
+	)
+
+	defer shortenRelocationIntervals()()
+
+	orchestrator := getOrchestrator()
+	addRelocationBackend(t, orchestrator, sourceBackendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	original, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	_, err = orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(snapVolumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	_, err = orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+		Version:    "1",
+		Name:       "snap",
+		VolumeName: snapVolumeName,
+	})
+	if err != nil {
+		t.Fatalf("Unable to create snapshot: %v", err)
+	}
+
+	addRelocationBackend(t, orchestrator, targetBackendName, "primary")
+	targetBackend, err := orchestrator.GetBackend(ctx(), targetBackendName)
+	if err != nil {
+		t.Fatalf("Unable to get backend: %v", err)
+	}
+
+	// A volume cannot be mirrored until it is known to be unpublished
+	_, err = orchestrator.RelocateVolume(ctx(), volumeName, targetBackendName, "")
+	assert.True(t, utils.IsUnsupportedError(err), "Expected an unsupported error")
+
+	assert.NoError(t, orchestrator.BackfillPublishedNodes(ctx(), map[string][]string{}))
+
+	// A volume with snapshots cannot be mirrored
+	_, err = orchestrator.RelocateVolume(ctx(), snapVolumeName, targetBackendName, "")
+	assert.True(t, utils.IsUnsupportedError(err), "Expected an unsupported error")
+
+	if _, err = orchestrator.RelocateVolume(ctx(), volumeName, targetBackendName, ""); err != nil {
+		t.Fatalf("Unable to relocate volume: %v", err)
+	}
+
+	relocated := waitForRelocation(t, orchestrator, volumeName)
+	assert.Equal(t, storage.VolumeStateOnline, relocated.State)
+	assert.Equal(t, targetBackend.BackendUUID, relocated.BackendUUID)
+	assert.Equal(t, "primary", relocated.Pool)
+	assert.False(t, relocated.Config.IsMirrorDestination)
+
+	// The volume was copied to the target backend and removed from the source backend
+	targetDriver := getFakeDriver(t, orchestrator, targetBackend.BackendUUID)
+	_, ok := targetDriver.Volumes[relocated.Config.InternalName]
+	assert.True(t, ok, "Volume not found on target backend")
+	sourceDriver := getFakeDriver(t, orchestrator, original.BackendUUID)
+	_, ok = sourceDriver.Volumes[original.Config.InternalName]
+	assert.False(t, ok, "Volume still found on source backend")
+
+	txns, err := orchestrator.storeClient.GetVolumeTransactions(ctx())
+	assert.NoError(t, err)
+	assert.Empty(t, txns)
+
+	// The relocated volume is usable
+	assert.NoError(t, orchestrator.ResizeVolume(ctx(), volumeName, "2147483648"))
+
+	cleanup(t, orchestrator)
+}
+
+// otherFakeDriver is a fake driver that reports another driver type, so that volumes are copied to it
+// rather than mirrored.
+type otherFakeDriver struct {
+	*fakedriver.StorageDriver
+}
+
+func (d *otherFakeDriver) Name() string {
+	return "other-fake"
+}
+
+// replaceCopyVolumeOnHost replaces the host-side copy of relocated volumes and returns a function that
+// restores it.
+func replaceCopyVolumeOnHost(
+	copyVolume func(context.Context, string, string, *utils.VolumePublishInfo, string, *utils.VolumePublishInfo) error,
+) func() {
+	original := copyVolumeOnHost
+	copyVolumeOnHost = copyVolume
+	return func() {
+		copyVolumeOnHost = original
+	}
+}
+
+func TestRelocateVolumeBetweenBackendTypes(t *testing.T) {
+	const (
+		sourceBackendName = "copySourceBackend"
+		targetBackendName = "copyTargetBackend"
+		scName            = "copyBackendsSC"
+		volumeName        = "copyBackendsVolume"
+	)
+
+	defer shortenRelocationIntervals()()
+
+	orchestrator := getOrchestrator()
+	addRelocationBackend(t, orchestrator, sourceBackendName, "primary")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	original, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+
+	addRelocationBackend(t, orchestrator, targetBackendName, "primary")
+	targetBackend, err := orchestrator.GetBackend(ctx(), targetBackendName)
+	if err != nil {
+		t.Fatalf("Unable to get backend: %v", err)
+	}
+	targetDriver := &otherFakeDriver{getFakeDriver(t, orchestrator, targetBackend.BackendUUID)}
+	orchestrator.mutex.Lock()
+	orchestrator.backends[targetBackend.BackendUUID].Driver = targetDriver
+	orchestrator.mutex.Unlock()
+	sourceDriver := getFakeDriver(t, orchestrator, original.BackendUUID)
+
+	assert.NoError(t, orchestrator.BackfillPublishedNodes(ctx(), map[string][]string{}))
+
+	// A failed copy is rolled back, leaving the volume on its source backend
+	defer replaceCopyVolumeOnHost(func(
+		context.Context, string, string, *utils.VolumePublishInfo, string, *utils.VolumePublishInfo,
+	) error {
+		return utils.UnsupportedError("copy not supported")
+	})()
+
+	if _, err = orchestrator.RelocateVolume(ctx(), volumeName, targetBackendName, ""); err != nil {
+		t.Fatalf("Unable to relocate volume: %v", err)
+	}
+
+	rolledBack := waitForRelocation(t, orchestrator, volumeName)
+	assert.Equal(t, storage.VolumeStateOnline, rolledBack.State)
+	assert.Equal(t, original.BackendUUID, rolledBack.BackendUUID)
+	_, ok := sourceDriver.Volumes[original.Config.InternalName]
+	assert.True(t, ok, "Volume not found on source backend")
+	assert.Empty(t, targetDriver.Volumes, "Volume left on target backend")
+
+	// A successful copy swaps the volume to the target backend
+	var copiedSource, copiedTarget string
+	replaceCopyVolumeOnHost(func(
+		_ context.Context, _, sourceName string, _ *utils.VolumePublishInfo, targetName string,
+		_ *utils.VolumePublishInfo,
+	) error {
+		copiedSource, copiedTarget = sourceName, targetName
+		return nil
+	})
+
+	if _, err = orchestrator.RelocateVolume(ctx(), volumeName, targetBackendName, ""); err != nil {
+		t.Fatalf("Unable to relocate volume: %v", err)
+	}
+
+	relocated := waitForRelocation(t, orchestrator, volumeName)
+	assert.Equal(t, storage.VolumeStateOnline, relocated.State)
+	assert.Equal(t, targetBackend.BackendUUID, relocated.BackendUUID)
+	assert.Equal(t, "primary", relocated.Pool)
+	assert.False(t, relocated.Config.IsMirrorDestination)
+	assert.Equal(t, original.Config.InternalName, copiedSource)
+	assert.Equal(t, relocated.Config.InternalName, copiedTarget)
+
+	// The data was copied to a new volume on the target backend, without mirroring, and both volumes were
+	// unpublished from the local host afterwards
+	_, ok = targetDriver.Volumes[relocated.Config.InternalName]
+	assert.True(t, ok, "Volume not found on target backend")
+	assert.Empty(t, targetDriver.Mirrors, "Volume was mirrored")
+	_, ok = sourceDriver.Volumes[original.Config.InternalName]
+	assert.False(t, ok, "Volume still found on source backend")
+	assert.Contains(t, sourceDriver.Unpublished, localNodeName(ctx()))
+	assert.Contains(t, targetDriver.Unpublished, localNodeName(ctx()))
+
+	txns, err := orchestrator.storeClient.GetVolumeTransactions(ctx())
+	assert.NoError(t, err)
+	assert.Empty(t, txns)
+
+	cleanup(t, orchestrator)
+}
+
+func TestRelocatingVolumeOperations(t *testing.T) {
+	const (
+		backendName = "relocatingBackend"
+		scName      = "relocatingSC"
+		volumeName  = "relocatingVolume"
+	)
+
+	defer shortenRelocationIntervals()()
+
+	orchestrator := getOrchestrator()
+	addRelocationBackend(t, orchestrator, backendName, "pool-a", "pool-b")
+	addRelocationStorageClass(t, orchestrator, scName)
+
+	original, err := orchestrator.AddVolume(ctx(), tu.GenerateVolumeConfig(volumeName, 1, scName, config.File))
+	if err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+	targetPool := "pool-a"
+	if original.Pool == targetPool {
+		targetPool = "pool-b"
+	}
+
+	// Simulate a move that was interrupted by a restart
+	txn := &storage.VolumeTransaction{
+		Config: original.Config,
+		VolumeRelocationConfig: &storage.VolumeRelocationConfig{
+			StartTime:         time.Now(),
+			Method:            storage.RelocationMethodMove,
+			Phase:             storage.RelocationPhaseCopying,
+			SourceBackendUUID: original.BackendUUID,
+			SourcePool:        original.Pool,
+			TargetBackendUUID: original.BackendUUID,
+			TargetPool:        targetPool,
+		},
+		Op: storage.RelocateVolume,
+	}
+	if err = orchestrator.AddVolumeTransaction(ctx(), txn); err != nil {
+		t.Fatalf("Unable to add transaction: %v", err)
+	}
+	if err = orchestrator.SetVolumeState(ctx(), volumeName, storage.VolumeStateRelocating); err != nil {
+		t.Fatalf("Unable to set volume state: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		operation func() error
+		allowed   bool
+	}{
+		{"relocate", func() error {
+			_, err := orchestrator.RelocateVolume(ctx(), volumeName, "", "")
+			return err
+		}, false},
+		{"resize", func() error {
+			return orchestrator.ResizeVolume(ctx(), volumeName, "2147483648")
+		}, false},
+		{"delete", func() error {
+			return orchestrator.DeleteVolume(ctx(), volumeName)
+		}, false},
+		{"snapshot", func() error {
+			_, err := orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+				Version:    "1",
+				Name:       "snap",
+				VolumeName: volumeName,
+			})
+			return err
+		}, true},
+	}
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		err = test.operation()
+		if test.allowed {
+			assert.NoError(t, err, "Operation should be allowed during a move")
+		} else {
+			assert.True(t, utils.IsVolumeRelocatingError(err), "Expected a volume relocating error")
+		}
+	}
+
+	// The relocation resumes from its saved phase
+	if err = orchestrator.handleFailedTransaction(ctx(), txn); err != nil {
+		t.Fatalf("Unable to resume relocation: %v", err)
+	}
+	relocated := waitForRelocation(t, orchestrator, volumeName)
+	assert.Equal(t, storage.VolumeStateOnline, relocated.State)
+	assert.Equal(t, targetPool, relocated.Pool)
+
+	assert.NoError(t, orchestrator.DeleteSnapshot(ctx(), volumeName, "snap"))
+	assert.NoError(t, orchestrator.DeleteVolume(ctx(), volumeName))
+
+	cleanup(t, orchestrator)
+}
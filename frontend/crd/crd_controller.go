@@ -15,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	goinformer "k8s.io/client-go/informers"
 	goinformerv1 "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -55,6 +56,9 @@ const (
 	ObjectTypeTridentBackend       ObjectType = "trident-backend"
 	ObjectTypeSecret               ObjectType = "secret"
 
+	ObjectTypeTridentMirrorRelationship ObjectType = "trident-mirror-relationship"
+	ObjectTypeTridentSnapshotPolicy     ObjectType = "trident-snapshot-policy"
+
 	OperationStatusSuccess string = "Success"
 	OperationStatusFailed  string = "Failed"
 
@@ -93,10 +97,17 @@ type TridentCrdController struct {
 	// crdClientset is a clientset for our own API group
 	crdClientset clientset.Interface
 
+	// dynamicClientset is a clientset for resources, such as VolumeSnapshots, without typed clients
+	dynamicClientset dynamic.Interface
+
 	crdControllerStopChan chan struct{}
 	crdInformerFactory    tridentinformers.SharedInformerFactory
 	crdInformer           tridentinformersv1.Interface
 
+	// policyInformerFactory watches TridentSnapshotPolicies in every namespace, since each policy
+	// snapshots the volumes bound to the PVCs in its own namespace
+	policyInformerFactory tridentinformers.SharedInformerFactory
+
 	kubeInformerFactory goinformer.SharedInformerFactory
 	kubeInformer        goinformerv1.Interface
 
@@ -108,6 +119,10 @@ type TridentCrdController struct {
 	backendConfigsLister listers.TridentBackendConfigLister
 	backendConfigsSynced cache.InformerSynced
 
+	// TridentMirrorRelationship CRD handling
+	mirrorRelationshipsLister listers.TridentMirrorRelationshipLister
+	mirrorRelationshipsSynced cache.InformerSynced
+
 	// TridentNode CRD handling
 	nodesLister listers.TridentNodeLister
 	nodesSynced cache.InformerSynced
@@ -132,6 +147,10 @@ type TridentCrdController struct {
 	snapshotsLister listers.TridentSnapshotLister
 	snapshotsSynced cache.InformerSynced
 
+	// TridentSnapshotPolicy CRD handling
+	snapshotPoliciesLister listers.TridentSnapshotPolicyLister
+	snapshotPoliciesSynced cache.InformerSynced
+
 	// TridentSnapshot CRD handling
 	secretsLister v1.SecretLister
 	secretsSynced cache.InformerSynced
@@ -196,22 +215,32 @@ func newTridentCrdController(
 		return nil, err
 	}
 
-	return newTridentCrdControllerImpl(orchestrator, tridentNamespace, kubeClientset, crdClientset)
+	dynamicClientset, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTridentCrdControllerImpl(orchestrator, tridentNamespace, kubeClientset, crdClientset, dynamicClientset)
 }
 
 // newTridentCrdControllerImpl returns a new Trident CRD controller frontend
 func newTridentCrdControllerImpl(
 	orchestrator core.Orchestrator, tridentNamespace string,
-	kubeClientset kubernetes.Interface, crdClientset clientset.Interface,
+	kubeClientset kubernetes.Interface, crdClientset clientset.Interface, dynamicClientset dynamic.Interface,
 ) (*TridentCrdController, error) {
 
 	log.WithFields(log.Fields{
 		"namespace": tridentNamespace,
 	}).Info("Initializing Trident CRD controller frontend.")
 
-	// Set resync to 0 sec so that reconciliation is on demand
-	crdInformerFactory := tridentinformers.NewSharedInformerFactory(crdClientset, time.Second*0)
+	// Set resync to 0 sec so that reconciliation is on demand, except for mirror relationships, whose
+	// status is refreshed periodically
+	crdInformerFactory := tridentinformers.NewSharedInformerFactoryWithOptions(crdClientset, time.Second*0,
+		tridentinformers.WithCustomResyncConfig(map[metav1.Object]time.Duration{
+			&tridentv1.TridentMirrorRelationship{}: mirrorRelationshipRefreshInterval,
+		}))
 	crdInformer := tridentinformersv1.New(crdInformerFactory, tridentNamespace, nil)
+	policyInformerFactory := tridentinformers.NewSharedInformerFactory(crdClientset, time.Second*0)
 
 	// Set resync to 0 sec so that reconciliation is on demand
 	kubeInformerFactory := goinformer.NewSharedInformerFactory(kubeClientset, time.Second*0)
@@ -219,12 +248,14 @@ func newTridentCrdControllerImpl(
 
 	backendInformer := crdInformer.TridentBackends()
 	backendConfigInformer := crdInformer.TridentBackendConfigs()
+	mirrorRelationshipInformer := crdInformer.TridentMirrorRelationships()
 	nodeInformer := crdInformer.TridentNodes()
 	storageClassInformer := crdInformer.TridentStorageClasses()
 	transactionInformer := crdInformer.TridentTransactions()
 	versionInformer := crdInformer.TridentVersions()
 	volumeInformer := crdInformer.TridentVolumes()
 	snapshotInformer := crdInformer.TridentSnapshots()
+	snapshotPolicyInformer := policyInformerFactory.Trident().V1().TridentSnapshotPolicies()
 	secretInformer := kubeInformer.Secrets()
 
 	// Create event broadcaster
@@ -236,32 +267,38 @@ func newTridentCrdControllerImpl(
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 
 	controller := &TridentCrdController{
-		orchestrator:          orchestrator,
-		kubeClientset:         kubeClientset,
-		crdClientset:          crdClientset,
-		crdControllerStopChan: make(chan struct{}),
-		crdInformerFactory:    crdInformerFactory,
-		crdInformer:           crdInformer,
-		kubeInformerFactory:   kubeInformerFactory,
-		kubeInformer:          kubeInformer,
-		backendsLister:        backendInformer.Lister(),
-		backendsSynced:        backendInformer.Informer().HasSynced,
-		backendConfigsLister:  backendConfigInformer.Lister(),
-		backendConfigsSynced:  backendConfigInformer.Informer().HasSynced,
-		nodesLister:           nodeInformer.Lister(),
-		nodesSynced:           nodeInformer.Informer().HasSynced,
-		storageClassesLister:  storageClassInformer.Lister(),
-		storageClassesSynced:  storageClassInformer.Informer().HasSynced,
-		transactionsLister:    transactionInformer.Lister(),
-		transactionsSynced:    transactionInformer.Informer().HasSynced,
-		versionsLister:        versionInformer.Lister(),
-		versionsSynced:        versionInformer.Informer().HasSynced,
-		volumesLister:         volumeInformer.Lister(),
-		volumesSynced:         volumeInformer.Informer().HasSynced,
-		snapshotsLister:       snapshotInformer.Lister(),
-		snapshotsSynced:       snapshotInformer.Informer().HasSynced,
-		secretsLister:         secretInformer.Lister(),
-		secretsSynced:         secretInformer.Informer().HasSynced,
+		orchestrator:              orchestrator,
+		kubeClientset:             kubeClientset,
+		crdClientset:              crdClientset,
+		dynamicClientset:          dynamicClientset,
+		crdControllerStopChan:     make(chan struct{}),
+		crdInformerFactory:        crdInformerFactory,
+		crdInformer:               crdInformer,
+		policyInformerFactory:     policyInformerFactory,
+		kubeInformerFactory:       kubeInformerFactory,
+		kubeInformer:              kubeInformer,
+		backendsLister:            backendInformer.Lister(),
+		backendsSynced:            backendInformer.Informer().HasSynced,
+		backendConfigsLister:      backendConfigInformer.Lister(),
+		backendConfigsSynced:      backendConfigInformer.Informer().HasSynced,
+		mirrorRelationshipsLister: mirrorRelationshipInformer.Lister(),
+		mirrorRelationshipsSynced: mirrorRelationshipInformer.Informer().HasSynced,
+		nodesLister:               nodeInformer.Lister(),
+		nodesSynced:               nodeInformer.Informer().HasSynced,
+		storageClassesLister:      storageClassInformer.Lister(),
+		storageClassesSynced:      storageClassInformer.Informer().HasSynced,
+		transactionsLister:        transactionInformer.Lister(),
+		transactionsSynced:        transactionInformer.Informer().HasSynced,
+		versionsLister:            versionInformer.Lister(),
+		versionsSynced:            versionInformer.Informer().HasSynced,
+		volumesLister:             volumeInformer.Lister(),
+		volumesSynced:             volumeInformer.Informer().HasSynced,
+		snapshotsLister:           snapshotInformer.Lister(),
+		snapshotsSynced:           snapshotInformer.Informer().HasSynced,
+		snapshotPoliciesLister:    snapshotPolicyInformer.Lister(),
+		snapshotPoliciesSynced:    snapshotPolicyInformer.Informer().HasSynced,
+		secretsLister:             secretInformer.Lister(),
+		secretsSynced:             secretInformer.Informer().HasSynced,
 		workqueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(),
 			tridentBackendConfigsQueueName),
 		recorder: recorder,
@@ -276,6 +313,16 @@ func newTridentCrdControllerImpl(
 		DeleteFunc: controller.deleteTridentBackendConfigEvent,
 	})
 
+	mirrorRelationshipInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.addTridentMirrorRelationshipEvent,
+		UpdateFunc: controller.updateTridentMirrorRelationshipEvent,
+	})
+
+	snapshotPolicyInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.addTridentSnapshotPolicyEvent,
+		UpdateFunc: controller.updateTridentSnapshotPolicyEvent,
+	})
+
 	backendInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		// Do not handle add backends here, otherwise it may results in continous
 		// reconcile loops esp. in cases where backends are created as a result
@@ -323,6 +370,7 @@ func (c *TridentCrdController) Activate() error {
 	log.Info("Activating CRD frontend.")
 	if c.crdControllerStopChan != nil {
 		c.crdInformerFactory.Start(c.crdControllerStopChan)
+		c.policyInformerFactory.Start(c.crdControllerStopChan)
 		c.kubeInformerFactory.Start(c.crdControllerStopChan)
 		go c.Run(1, c.crdControllerStopChan)
 	}
@@ -365,12 +413,14 @@ func (c *TridentCrdController) Run(threadiness int, stopCh <-chan struct{}) {
 	if ok := cache.WaitForCacheSync(stopCh,
 		c.backendsSynced,
 		c.backendConfigsSynced,
+		c.mirrorRelationshipsSynced,
 		c.nodesSynced,
 		c.storageClassesSynced,
 		c.transactionsSynced,
 		c.versionsSynced,
 		c.volumesSynced,
 		c.snapshotsSynced,
+		c.snapshotPoliciesSynced,
 		c.secretsSynced); !ok {
 		waitErr := fmt.Errorf("failed to wait for caches to sync")
 		log.Errorf("Error: %v", waitErr)
@@ -631,6 +681,14 @@ func (c *TridentCrdController) processNextWorkItem() bool {
 			if err := c.reconcileBackendConfig(&keyItem); err != nil {
 				return err
 			}
+		case ObjectTypeTridentMirrorRelationship:
+			if err := c.reconcileMirrorRelationship(&keyItem); err != nil {
+				return err
+			}
+		case ObjectTypeTridentSnapshotPolicy:
+			if err := c.reconcileSnapshotPolicy(&keyItem); err != nil {
+				return err
+			}
 		default:
 			return fmt.Errorf("unknown objectType in the workqueue: %v", keyItem.objectType)
 		}
@@ -13,6 +13,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	k8s_fake "k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
 
@@ -23,6 +24,8 @@ import (
 	crdFake "github.com/netapp/trident/persistent_store/crd/client/clientset/versioned/fake"
 	"github.com/netapp/trident/storage"
 	fakeStorage "github.com/netapp/trident/storage/fake"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
 	drivers "github.com/netapp/trident/storage_drivers"
 	"github.com/netapp/trident/storage_drivers/fake"
 	fakeDriver "github.com/netapp/trident/storage_drivers/fake"
@@ -128,6 +131,11 @@ func GetTestCrdClientset() *crdFake.Clientset {
 	return client
 }
 
+func GetTestDynamicClientset() *dynamicfake.FakeDynamicClient {
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	return client
+}
+
 func delaySeconds(n time.Duration) {
 	time.Sleep(n * time.Second)
 }
@@ -143,6 +151,51 @@ func newFakeStorageDriverConfigJSON(name string) (string, error) {
 	return fakeDriver.NewFakeStorageDriverConfigJSON(name, config.File, testutils2.GenerateFakePools(2), volumes)
 }
 
+// getFakeBackendTestController returns a CRD controller backed by a real orchestrator with a fake
+// backend and the given storage classes.
+func getFakeBackendTestController(t *testing.T, scNames ...string) (*TridentCrdController, core.Orchestrator) {
+
+	orchestrator := core.NewTridentOrchestrator(persistentstore.NewInMemoryClient())
+	if err := orchestrator.Bootstrap(); err != nil {
+		t.Fatalf("Unable to bootstrap orchestrator: %v", err)
+	}
+	t.Cleanup(orchestrator.Stop)
+
+	pools := map[string]*fakeStorage.StoragePool{
+		"primary": {
+			Attrs: map[string]sa.Offer{
+				sa.Media:            sa.NewStringOffer("hdd"),
+				sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+			},
+			Bytes: 100 * 1024 * 1024 * 1024,
+		},
+	}
+	configJSON, err := fakeDriver.NewFakeStorageDriverConfigJSON("fakeBackend", config.File, pools,
+		[]fakeStorage.Volume{})
+	if err != nil {
+		t.Fatalf("Unable to create fake driver config JSON: %v", err)
+	}
+	if _, err = orchestrator.AddBackend(ctx(), configJSON, ""); err != nil {
+		t.Fatalf("Unable to add backend: %v", err)
+	}
+	for _, scName := range scNames {
+		_, err = orchestrator.AddStorageClass(ctx(), &storageclass.Config{
+			Name:       scName,
+			Attributes: map[string]sa.Request{sa.Media: sa.NewStringRequest("hdd")},
+		})
+		if err != nil {
+			t.Fatalf("Unable to add storage class: %v", err)
+		}
+	}
+
+	crdController, err := newTridentCrdControllerImpl(orchestrator, "trident", GetTestKubernetesClientset(),
+		GetTestCrdClientset(), GetTestDynamicClientset())
+	if err != nil {
+		t.Fatalf("Unable to create Trident CRD controller frontend: %v", err)
+	}
+	return crdController, orchestrator
+}
+
 func addCrdTestReactors(crdFakeClient *crdFake.Clientset, testingCache *TestingCache) {
 
 	crdFakeClient.Fake.PrependReactor(
@@ -228,8 +281,10 @@ func TestCrdController(t *testing.T) {
 	tridentNamespace := "trident"
 	kubeClient := GetTestKubernetesClientset()
 	crdClient := GetTestCrdClientset()
+	dynamicClient := GetTestDynamicClientset()
 	addCrdTestReactors(crdClient, testingCache)
-	crdController, err := newTridentCrdControllerImpl(orchestrator, tridentNamespace, kubeClient, crdClient)
+	crdController, err := newTridentCrdControllerImpl(orchestrator, tridentNamespace, kubeClient, crdClient,
+		dynamicClient)
 	if err != nil {
 		t.Fatalf("cannot create Trident CRD controller frontend, error: %v", err.Error())
 	}
@@ -390,8 +445,10 @@ func TestCrdController2(t *testing.T) {
 	tridentNamespace := "trident"
 	kubeClient := GetTestKubernetesClientset()
 	crdClient := GetTestCrdClientset()
+	dynamicClient := GetTestDynamicClientset()
 	addCrdTestReactors(crdClient, testingCache)
-	crdController, err := newTridentCrdControllerImpl(orchestrator, tridentNamespace, kubeClient, crdClient)
+	crdController, err := newTridentCrdControllerImpl(orchestrator, tridentNamespace, kubeClient, crdClient,
+		dynamicClient)
 	if err != nil {
 		t.Fatalf("cannot create Trident CRD controller frontend, error: %v", err.Error())
 	}
@@ -0,0 +1,443 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package crd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+
+	. "github.com/netapp/trident/logger"
+	tridentv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// mirrorRelationshipPollInterval is how often a mirror relationship is reconciled while it
+	// is transitioning to its desired state
+	mirrorRelationshipPollInterval = 30 * time.Second
+	// mirrorRelationshipRefreshInterval is how often the status of every mirror relationship is refreshed
+	mirrorRelationshipRefreshInterval = 5 * time.Minute
+)
+
+// addTridentMirrorRelationshipEvent takes a TridentMirrorRelationship resource and converts it into a
+// namespace/name string which is then put onto the work queue. This method should *not* be passed
+// resources of any type other than TridentMirrorRelationship.
+func (c *TridentCrdController) addTridentMirrorRelationshipEvent(obj interface{}) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceCRD)
+	ctx = context.WithValue(ctx, CRDControllerEvent, string(EventAdd))
+
+	Logx(ctx).Debug("TridentCrdController#addTridentMirrorRelationshipEvent")
+
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		Logx(ctx).Error(err)
+		return
+	}
+
+	keyItem := KeyItem{
+		key:        key,
+		event:      EventAdd,
+		ctx:        ctx,
+		objectType: ObjectTypeTridentMirrorRelationship,
+	}
+
+	c.workqueue.Add(keyItem)
+}
+
+// updateTridentMirrorRelationshipEvent takes a TridentMirrorRelationship resource and converts it into a
+// namespace/name string which is then put onto the work queue. This method should *not* be passed
+// resources of any type other than TridentMirrorRelationship.
+func (c *TridentCrdController) updateTridentMirrorRelationshipEvent(old, new interface{}) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceCRD)
+	ctx = context.WithValue(ctx, CRDControllerEvent, string(EventUpdate))
+
+	Logx(ctx).Debug("TridentCrdController#updateTridentMirrorRelationshipEvent")
+
+	newRelationship := new.(*tridentv1.TridentMirrorRelationship)
+	oldRelationship := old.(*tridentv1.TridentMirrorRelationship)
+
+	// Ignore status only updates, but not the periodic resyncs that refresh the status or the
+	// updates that mark the relationship for deletion
+	if oldRelationship != nil && newRelationship != nil {
+		if newRelationship.ResourceVersion != oldRelationship.ResourceVersion &&
+			newRelationship.GetGeneration() == oldRelationship.GetGeneration() &&
+			newRelationship.ObjectMeta.DeletionTimestamp.IsZero() {
+			Logx(ctx).Debugf("No change in the generation, nothing to do.")
+			return
+		}
+	}
+
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(new); err != nil {
+		Logx(ctx).Error(err)
+		return
+	}
+
+	keyItem := KeyItem{
+		key:        key,
+		event:      EventUpdate,
+		ctx:        ctx,
+		objectType: ObjectTypeTridentMirrorRelationship,
+	}
+
+	c.workqueue.Add(keyItem)
+}
+
+// reconcileMirrorRelationship converges a mirror relationship to its desired state and requeues it
+// as needed.
+func (c *TridentCrdController) reconcileMirrorRelationship(keyItem *KeyItem) error {
+	Logx(keyItem.ctx).Debug("TridentCrdController#reconcileMirrorRelationship")
+
+	if err := c.handleTridentMirrorRelationship(keyItem); err != nil {
+
+		// A relationship that is still transitioning is checked again later
+		if utils.IsReconcileIncompleteError(err) {
+			c.workqueue.Forget(*keyItem)
+			c.workqueue.AddAfter(*keyItem, mirrorRelationshipPollInterval)
+			Logx(keyItem.ctx).WithField("key", keyItem.key).Debug("Mirror relationship is transitioning.")
+			return nil
+		}
+
+		c.workqueue.AddRateLimited(*keyItem)
+
+		errMessage := fmt.Sprintf("error syncing mirror relationship '%v', requeuing; %v", keyItem.key, err)
+		Logx(keyItem.ctx).Errorf(errMessage)
+		log.Info("-------------------------------------------------")
+		log.Info("-------------------------------------------------")
+
+		return fmt.Errorf(errMessage)
+	}
+
+	return nil
+}
+
+// handleTridentMirrorRelationship compares the actual state of a mirror relationship with the desired
+// state, and attempts to converge the two.  It then updates the Status block of the
+// TridentMirrorRelationship resource with the current status of the relationship.
+func (c *TridentCrdController) handleTridentMirrorRelationship(keyItem *KeyItem) error {
+
+	if keyItem == nil {
+		return fmt.Errorf("keyItem item is nil")
+	}
+
+	key := keyItem.key
+	ctx := keyItem.ctx
+
+	Logx(ctx).WithFields(log.Fields{
+		"Key":        key,
+		"eventType":  keyItem.event,
+		"objectType": keyItem.objectType,
+	}).Debug("TridentCrdController#handleTridentMirrorRelationship")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		Logx(ctx).WithField("key", key).Error("Invalid key.")
+		return nil
+	}
+
+	// Get the CR with this namespace/name
+	relationship, err := c.mirrorRelationshipsLister.TridentMirrorRelationships(namespace).Get(name)
+	if err != nil {
+		// The resource may no longer exist, in which case we stop processing.
+		if errors.IsNotFound(err) {
+			Logx(ctx).WithField("key", key).Debug("Object in work queue no longer exists.")
+			return nil
+		}
+		return err
+	}
+
+	if !relationship.ObjectMeta.DeletionTimestamp.IsZero() {
+		return c.deleteTridentMirrorRelationship(ctx, relationship)
+	}
+
+	// Ensure the relationship has a finalizer, so that the mirror is released when it is deleted
+	if !relationship.HasTridentFinalizers() {
+		relationshipCopy := relationship.DeepCopy()
+		Logx(ctx).WithField("relationship.Name", relationshipCopy.Name).Debugf("Adding finalizer.")
+		relationshipCopy.AddTridentFinalizers()
+
+		relationship, err = c.crdClientset.TridentV1().TridentMirrorRelationships(namespace).Update(
+			ctx, relationshipCopy, updateOpts)
+		if err != nil {
+			return fmt.Errorf("error setting finalizer; %v", err)
+		}
+	}
+
+	// An invalid spec cannot be fixed by retrying, so record the problem and wait for the spec to change
+	if err = relationship.Validate(); err == nil {
+		err = c.validateMirrorRelationshipVolumes(ctx, relationship)
+	}
+	if err != nil {
+		if !utils.IsInvalidInputError(err) {
+			return err
+		}
+		newStatus := tridentv1.TridentMirrorRelationshipStatus{
+			State:   tridentv1.MirrorRelationshipStateFailed,
+			Message: fmt.Sprintf("Invalid mirror relationship: %v", err),
+		}
+		return c.updateTridentMirrorRelationshipStatus(ctx, relationship, newStatus)
+	}
+
+	state, mirrorStatus, reconcileErr := c.convergeMirrorRelationship(ctx, &relationship.Spec)
+
+	newStatus := tridentv1.TridentMirrorRelationshipStatus{
+		State: state,
+	}
+	if mirrorStatus != nil {
+		newStatus.MirrorState = mirrorStatus.State
+		newStatus.Healthy = mirrorStatus.Healthy
+		newStatus.Message = mirrorStatus.Message
+	}
+	if reconcileErr != nil && !utils.IsReconcileIncompleteError(reconcileErr) {
+		newStatus.Message = reconcileErr.Error()
+	}
+
+	if err = c.updateTridentMirrorRelationshipStatus(ctx, relationship, newStatus); err != nil {
+		return err
+	}
+
+	return reconcileErr
+}
+
+// validateMirrorRelationshipVolumes verifies that both volumes of a relationship are bound to PVCs in
+// the relationship's namespace, so that a relationship cannot be used to replicate, promote or release
+// volumes belonging to other namespaces.  An InvalidInputError is returned if either volume is not
+// eligible.
+func (c *TridentCrdController) validateMirrorRelationshipVolumes(
+	ctx context.Context, relationship *tridentv1.TridentMirrorRelationship,
+) error {
+
+	for _, volumeName := range []string{relationship.Spec.SourceVolume, relationship.Spec.DestinationVolume} {
+
+		pv, err := c.kubeClientset.CoreV1().PersistentVolumes().Get(ctx, volumeName, getOpts)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return utils.InvalidInputError(fmt.Sprintf("PV for volume %s not found", volumeName))
+			}
+			return fmt.Errorf("could not get PV for volume %s; %v", volumeName, err)
+		}
+
+		if pv.Spec.ClaimRef == nil || pv.Spec.ClaimRef.Namespace != relationship.Namespace {
+			return utils.InvalidInputError(fmt.Sprintf("volume %s is not bound to a PVC in namespace %s",
+				volumeName, relationship.Namespace))
+		}
+	}
+
+	return nil
+}
+
+// convergeMirrorRelationship performs the mirror operations needed to move a relationship toward its
+// desired state.  It returns the state of the relationship and the status of the mirror that is active
+// in that state, if any.  A ReconcileIncompleteError is returned while the mirror is transferring
+// its initial data.
+func (c *TridentCrdController) convergeMirrorRelationship(
+	ctx context.Context, spec *tridentv1.TridentMirrorRelationshipSpec,
+) (string, *storage.MirrorStatus, error) {
+
+	source, destination := spec.SourceVolume, spec.DestinationVolume
+
+	switch spec.State {
+
+	case tridentv1.MirrorRelationshipStateEstablished:
+
+		// Stop any replication in the reverse direction
+		if err := c.stopMirror(ctx, destination, source); err != nil {
+			return tridentv1.MirrorRelationshipStateEstablishing, nil, err
+		}
+		if err := c.startMirror(ctx, source, destination, spec); err != nil {
+			return tridentv1.MirrorRelationshipStateEstablishing, nil, err
+		}
+		return c.getMirrorProgress(ctx, source, destination, tridentv1.MirrorRelationshipStateEstablished,
+			tridentv1.MirrorRelationshipStateEstablishing)
+
+	case tridentv1.MirrorRelationshipStatePromoted:
+
+		if err := c.stopMirror(ctx, source, destination); err != nil {
+			return tridentv1.MirrorRelationshipStatePromoting, nil, err
+		}
+		if err := c.stopMirror(ctx, destination, source); err != nil {
+			return tridentv1.MirrorRelationshipStatePromoting, nil, err
+		}
+		mirrorStatus, err := c.orchestrator.GetMirrorStatus(ctx, source, destination)
+		if err != nil && !utils.IsNotFoundError(err) {
+			return tridentv1.MirrorRelationshipStatePromoting, nil, err
+		}
+		return tridentv1.MirrorRelationshipStatePromoted, mirrorStatus, nil
+
+	case tridentv1.MirrorRelationshipStateReversed:
+
+		if err := c.stopMirror(ctx, source, destination); err != nil {
+			return tridentv1.MirrorRelationshipStateReversing, nil, err
+		}
+		if err := c.startMirror(ctx, destination, source, spec); err != nil {
+			return tridentv1.MirrorRelationshipStateReversing, nil, err
+		}
+		return c.getMirrorProgress(ctx, destination, source, tridentv1.MirrorRelationshipStateReversed,
+			tridentv1.MirrorRelationshipStateReversing)
+
+	default:
+		return tridentv1.MirrorRelationshipStateFailed, nil, fmt.Errorf("invalid state %s", spec.State)
+	}
+}
+
+// startMirror ensures that the source volume is being replicated to the destination volume, either by
+// establishing a new mirror or by reestablishing one that was promoted.
+func (c *TridentCrdController) startMirror(
+	ctx context.Context, source, destination string, spec *tridentv1.TridentMirrorRelationshipSpec,
+) error {
+
+	mirrorStatus, err := c.orchestrator.GetMirrorStatus(ctx, source, destination)
+	if err != nil && !utils.IsNotFoundError(err) {
+		return err
+	}
+
+	switch {
+	case mirrorStatus == nil && source == spec.SourceVolume:
+		return c.orchestrator.EstablishMirror(ctx, source, destination, spec.ReplicationPolicy,
+			spec.ReplicationSchedule)
+	case mirrorStatus == nil, mirrorStatus.State == storage.MirrorStateBroken:
+		return c.orchestrator.ReestablishMirror(ctx, source, destination, spec.ReplicationPolicy,
+			spec.ReplicationSchedule)
+	case mirrorStatus.State == storage.MirrorStateUninitialized:
+		return c.orchestrator.EstablishMirror(ctx, source, destination, spec.ReplicationPolicy,
+			spec.ReplicationSchedule)
+	default:
+		return nil
+	}
+}
+
+// stopMirror promotes the mirror from the source volume to the destination volume, if it exists and
+// has not already been promoted.
+func (c *TridentCrdController) stopMirror(ctx context.Context, source, destination string) error {
+
+	mirrorStatus, err := c.orchestrator.GetMirrorStatus(ctx, source, destination)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if mirrorStatus.State == storage.MirrorStateBroken {
+		return nil
+	}
+	return c.orchestrator.PromoteMirror(ctx, source, destination)
+}
+
+// getMirrorProgress returns the done state once the mirror from the source volume to the destination
+// volume has completed its initial transfer, or else the in progress state and a ReconcileIncompleteError.
+func (c *TridentCrdController) getMirrorProgress(
+	ctx context.Context, source, destination, doneState, inProgressState string,
+) (string, *storage.MirrorStatus, error) {
+
+	mirrorStatus, err := c.orchestrator.GetMirrorStatus(ctx, source, destination)
+	if err != nil {
+		return inProgressState, nil, err
+	}
+	if mirrorStatus.State != storage.MirrorStateMirrored {
+		return inProgressState, mirrorStatus, utils.ReconcileIncompleteError()
+	}
+	return doneState, mirrorStatus, nil
+}
+
+// deleteTridentMirrorRelationship releases the mirrors between the volumes of a relationship that is
+// being deleted, in both directions, and then removes the relationship's finalizers.
+func (c *TridentCrdController) deleteTridentMirrorRelationship(
+	ctx context.Context, relationship *tridentv1.TridentMirrorRelationship,
+) error {
+
+	Logx(ctx).WithFields(log.Fields{
+		"relationship.Name":                         relationship.Name,
+		"relationship.ObjectMeta.DeletionTimestamp": relationship.ObjectMeta.DeletionTimestamp,
+	}).Debug("Mirror relationship is being deleted.")
+
+	if !relationship.HasTridentFinalizers() {
+		return nil
+	}
+
+	// Only release mirrors between volumes in the relationship's namespace, since an invalid
+	// relationship may name volumes that belong to other namespaces
+	source, destination := relationship.Spec.SourceVolume, relationship.Spec.DestinationVolume
+	releaseMirrors := source != "" && destination != "" && source != destination
+	if releaseMirrors {
+		if err := c.validateMirrorRelationshipVolumes(ctx, relationship); err != nil {
+			if !utils.IsInvalidInputError(err) {
+				return err
+			}
+			Logx(ctx).WithField("relationship.Name", relationship.Name).Debugf(
+				"Not releasing mirrors of invalid relationship; %v", err)
+			releaseMirrors = false
+		}
+	}
+
+	if releaseMirrors {
+		for _, volumes := range [][]string{{source, destination}, {destination, source}} {
+			err := c.orchestrator.ReleaseMirror(ctx, volumes[0], volumes[1])
+			if err != nil && !utils.IsNotFoundError(err) {
+				return fmt.Errorf("could not release mirror from volume %s to volume %s; %v",
+					volumes[0], volumes[1], err)
+			}
+		}
+	}
+
+	relationshipCopy := relationship.DeepCopy()
+	relationshipCopy.RemoveTridentFinalizers()
+	_, err := c.crdClientset.TridentV1().TridentMirrorRelationships(relationship.Namespace).Update(
+		ctx, relationshipCopy, updateOpts)
+	if err != nil {
+		return fmt.Errorf("error removing finalizer; %v", err)
+	}
+
+	return nil
+}
+
+// updateTridentMirrorRelationshipStatus updates the Status block of a TridentMirrorRelationship CR,
+// recording an event if its state changed.
+func (c *TridentCrdController) updateTridentMirrorRelationshipStatus(
+	ctx context.Context, relationship *tridentv1.TridentMirrorRelationship,
+	newStatus tridentv1.TridentMirrorRelationshipStatus,
+) error {
+
+	logFields := log.Fields{"TridentMirrorRelationshipCR": relationship.Name}
+
+	newStatus.ObservedGeneration = relationship.Generation
+	newStatus.LastTransitionTime = relationship.Status.LastTransitionTime
+	if newStatus.State != relationship.Status.State || newStatus.LastTransitionTime == "" {
+		newStatus.LastTransitionTime = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	if reflect.DeepEqual(relationship.Status, newStatus) {
+		Logx(ctx).WithFields(logFields).Debug("New status is same as the old status, no status update needed.")
+		return nil
+	}
+
+	crClone := relationship.DeepCopy()
+	crClone.Status = newStatus
+
+	if _, err := c.crdClientset.TridentV1().TridentMirrorRelationships(relationship.Namespace).UpdateStatus(
+		ctx, crClone, updateOpts); err != nil {
+		Logx(ctx).WithFields(logFields).Errorf("Could not update status of the CR; %v", err)
+		return err
+	}
+
+	if newStatus.State != relationship.Status.State {
+		eventType := corev1.EventTypeNormal
+		if newStatus.State == tridentv1.MirrorRelationshipStateFailed {
+			eventType = corev1.EventTypeWarning
+		}
+		c.recorder.Event(relationship, eventType, newStatus.State, newStatus.Message)
+	}
+
+	return nil
+}
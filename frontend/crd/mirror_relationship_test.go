@@ -0,0 +1,116 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package crd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/config"
+	tridentv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+func TestHandleTridentMirrorRelationship(t *testing.T) {
+
+	tests := []struct {
+		name                 string
+		destinationNamespace string
+		spec                 tridentv1.TridentMirrorRelationshipSpec
+		expectedState        string
+		expectMirror         bool
+	}{
+		{"same namespace", "app", tridentv1.TridentMirrorRelationshipSpec{
+			SourceVolume: "sourceVolume", DestinationVolume: "destinationVolume",
+			State: tridentv1.MirrorRelationshipStateEstablished,
+		}, tridentv1.MirrorRelationshipStateEstablished, true},
+		{"other namespace", "other", tridentv1.TridentMirrorRelationshipSpec{
+			SourceVolume: "sourceVolume", DestinationVolume: "destinationVolume",
+			State: tridentv1.MirrorRelationshipStateEstablished,
+		}, tridentv1.MirrorRelationshipStateFailed, false},
+		{"missing destination", "app", tridentv1.TridentMirrorRelationshipSpec{
+			SourceVolume: "sourceVolume", State: tridentv1.MirrorRelationshipStateEstablished,
+		}, tridentv1.MirrorRelationshipStateFailed, false},
+		{"same volume", "app", tridentv1.TridentMirrorRelationshipSpec{
+			SourceVolume: "sourceVolume", DestinationVolume: "sourceVolume",
+			State: tridentv1.MirrorRelationshipStateEstablished,
+		}, tridentv1.MirrorRelationshipStateFailed, false},
+		{"invalid state", "app", tridentv1.TridentMirrorRelationshipSpec{
+			SourceVolume: "sourceVolume", DestinationVolume: "destinationVolume", State: "mirrored",
+		}, tridentv1.MirrorRelationshipStateFailed, false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		crdController, orchestrator := getFakeBackendTestController(t, "gold")
+
+		// Each volume is bound to a PVC in the given namespace
+		volumeNamespaces := map[string]string{"sourceVolume": "app", "destinationVolume": test.destinationNamespace}
+		for volumeName, namespace := range volumeNamespaces {
+			volConfig := &storage.VolumeConfig{
+				Version:             config.OrchestratorAPIVersion,
+				Name:                volumeName,
+				InternalName:        volumeName,
+				Size:                "1073741824",
+				Protocol:            config.File,
+				StorageClass:        "gold",
+				IsMirrorDestination: volumeName == "destinationVolume",
+			}
+			if _, err := orchestrator.AddVolume(ctx(), volConfig); err != nil {
+				t.Fatalf("Unable to add volume: %v", err)
+			}
+
+			pv := &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: volumeName},
+				Spec: corev1.PersistentVolumeSpec{
+					ClaimRef: &corev1.ObjectReference{Name: "data", Namespace: namespace},
+				},
+			}
+			if _, err := crdController.kubeClientset.CoreV1().PersistentVolumes().Create(ctx(), pv,
+				createOpts); err != nil {
+				t.Fatalf("Unable to create PV: %v", err)
+			}
+		}
+
+		relationship, err := crdController.crdClientset.TridentV1().TridentMirrorRelationships("app").Create(ctx(),
+			&tridentv1.TridentMirrorRelationship{
+				ObjectMeta: metav1.ObjectMeta{Name: "mirror", Namespace: "app"},
+				Spec:       test.spec,
+			}, createOpts)
+		if err != nil {
+			t.Fatalf("Unable to create mirror relationship: %v", err)
+		}
+		if err = crdController.crdInformer.TridentMirrorRelationships().Informer().GetIndexer().Add(
+			relationship); err != nil {
+			t.Fatalf("Unable to cache mirror relationship: %v", err)
+		}
+
+		keyItem := &KeyItem{
+			key: "app/mirror", event: EventAdd, ctx: ctx(), objectType: ObjectTypeTridentMirrorRelationship,
+		}
+		assert.NoError(t, crdController.handleTridentMirrorRelationship(keyItem), test.name)
+
+		relationship, err = crdController.crdClientset.TridentV1().TridentMirrorRelationships("app").Get(ctx(),
+			"mirror", getOpts)
+		if assert.NoError(t, err, test.name) {
+			assert.Equal(t, test.expectedState, relationship.Status.State, test.name)
+			if test.expectedState == tridentv1.MirrorRelationshipStateFailed {
+				assert.Contains(t, relationship.Status.Message, "Invalid mirror relationship", test.name)
+			}
+		}
+
+		mirrorStatus, err := orchestrator.GetMirrorStatus(ctx(), "sourceVolume", "destinationVolume")
+		if test.expectMirror {
+			if assert.NoError(t, err, test.name) {
+				assert.Equal(t, storage.MirrorStateMirrored, mirrorStatus.State, test.name)
+			}
+		} else {
+			assert.True(t, utils.IsNotFoundError(err), "%s: expected no mirror", test.name)
+		}
+	}
+}
@@ -0,0 +1,631 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package crd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/frontend/csi"
+	. "github.com/netapp/trident/logger"
+	tridentv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// snapshotPolicyTimeFormat is the format of the UTC schedule time in the names of the snapshots
+	// created by a snapshot policy
+	snapshotPolicyTimeFormat = "20060102-150405"
+
+	// snapshotPolicyLabel is set on the VolumeSnapshots created by a snapshot policy
+	snapshotPolicyLabel = "trident.netapp.io/snapshotPolicy"
+
+	// snapshotPolicyUIDLabel records the UID of the snapshot policy that owns a VolumeSnapshot, so that
+	// a policy never deletes VolumeSnapshots it did not create
+	snapshotPolicyUIDLabel = "trident.netapp.io/snapshotPolicyUID"
+)
+
+var (
+	volumeSnapshotResource = schema.GroupVersionResource{
+		Group: "snapshot.storage.k8s.io", Version: "v1beta1", Resource: "volumesnapshots"}
+	volumeSnapshotContentResource = schema.GroupVersionResource{
+		Group: "snapshot.storage.k8s.io", Version: "v1beta1", Resource: "volumesnapshotcontents"}
+
+	// snapshotPolicyScheduleCtx is the context of the work items that wake a snapshot policy at its
+	// next schedule time.  Using the same context for every such item lets the work queue coalesce
+	// them, so that each policy has at most one pending wakeup.
+	snapshotPolicyScheduleCtx = context.WithValue(
+		GenerateRequestContext(nil, "", ContextSourceCRD), CRDControllerEvent, string(EventUpdate))
+)
+
+// addTridentSnapshotPolicyEvent takes a TridentSnapshotPolicy resource and converts it into a
+// namespace/name string which is then put onto the work queue. This method should *not* be passed
+// resources of any type other than TridentSnapshotPolicy.
+func (c *TridentCrdController) addTridentSnapshotPolicyEvent(obj interface{}) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceCRD)
+	ctx = context.WithValue(ctx, CRDControllerEvent, string(EventAdd))
+
+	Logx(ctx).Debug("TridentCrdController#addTridentSnapshotPolicyEvent")
+
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		Logx(ctx).Error(err)
+		return
+	}
+
+	keyItem := KeyItem{
+		key:        key,
+		event:      EventAdd,
+		ctx:        ctx,
+		objectType: ObjectTypeTridentSnapshotPolicy,
+	}
+
+	c.workqueue.Add(keyItem)
+}
+
+// updateTridentSnapshotPolicyEvent takes a TridentSnapshotPolicy resource and converts it into a
+// namespace/name string which is then put onto the work queue. This method should *not* be passed
+// resources of any type other than TridentSnapshotPolicy.
+func (c *TridentCrdController) updateTridentSnapshotPolicyEvent(old, new interface{}) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceCRD)
+	ctx = context.WithValue(ctx, CRDControllerEvent, string(EventUpdate))
+
+	Logx(ctx).Debug("TridentCrdController#updateTridentSnapshotPolicyEvent")
+
+	newPolicy := new.(*tridentv1.TridentSnapshotPolicy)
+	oldPolicy := old.(*tridentv1.TridentSnapshotPolicy)
+
+	// Ignore status only updates, since the policy is woken at its next schedule time anyway
+	if oldPolicy != nil && newPolicy != nil {
+		if newPolicy.GetGeneration() == oldPolicy.GetGeneration() {
+			Logx(ctx).Debugf("No change in the generation, nothing to do.")
+			return
+		}
+	}
+
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(new); err != nil {
+		Logx(ctx).Error(err)
+		return
+	}
+
+	keyItem := KeyItem{
+		key:        key,
+		event:      EventUpdate,
+		ctx:        ctx,
+		objectType: ObjectTypeTridentSnapshotPolicy,
+	}
+
+	c.workqueue.Add(keyItem)
+}
+
+// reconcileSnapshotPolicy creates and prunes the snapshots of a snapshot policy and requeues it for
+// its next schedule time.
+func (c *TridentCrdController) reconcileSnapshotPolicy(keyItem *KeyItem) error {
+	Logx(keyItem.ctx).Debug("TridentCrdController#reconcileSnapshotPolicy")
+
+	nextScheduleTime, err := c.handleTridentSnapshotPolicy(keyItem)
+	if err != nil {
+
+		c.workqueue.AddRateLimited(*keyItem)
+
+		errMessage := fmt.Sprintf("error syncing snapshot policy '%v', requeuing; %v", keyItem.key, err)
+		Logx(keyItem.ctx).Errorf(errMessage)
+		log.Info("-------------------------------------------------")
+		log.Info("-------------------------------------------------")
+
+		return fmt.Errorf(errMessage)
+	}
+
+	if !nextScheduleTime.IsZero() {
+		scheduleItem := KeyItem{
+			key:        keyItem.key,
+			event:      EventUpdate,
+			ctx:        snapshotPolicyScheduleCtx,
+			objectType: ObjectTypeTridentSnapshotPolicy,
+		}
+		c.workqueue.AddAfter(scheduleItem, time.Until(nextScheduleTime))
+		Logx(keyItem.ctx).WithFields(log.Fields{
+			"key":              keyItem.key,
+			"nextScheduleTime": nextScheduleTime,
+		}).Debug("Snapshot policy is scheduled.")
+	}
+
+	return nil
+}
+
+// handleTridentSnapshotPolicy snapshots the volumes selected by a snapshot policy if one of its
+// schedules has come due, deletes the policy's snapshots that are no longer retained, and then
+// updates the Status block of the TridentSnapshotPolicy resource.  It returns the time at which the
+// policy should next be reconciled, or the zero time if its spec is invalid.
+func (c *TridentCrdController) handleTridentSnapshotPolicy(keyItem *KeyItem) (time.Time, error) {
+
+	if keyItem == nil {
+		return time.Time{}, fmt.Errorf("keyItem item is nil")
+	}
+
+	key := keyItem.key
+	ctx := keyItem.ctx
+
+	Logx(ctx).WithFields(log.Fields{
+		"Key":        key,
+		"eventType":  keyItem.event,
+		"objectType": keyItem.objectType,
+	}).Debug("TridentCrdController#handleTridentSnapshotPolicy")
+
+	// Convert the namespace/name string into a distinct namespace and name
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		Logx(ctx).WithField("key", key).Error("Invalid key.")
+		return time.Time{}, nil
+	}
+
+	// Get the CR with this namespace/name
+	policy, err := c.snapshotPoliciesLister.TridentSnapshotPolicies(namespace).Get(name)
+	if err != nil {
+		// The resource may no longer exist, in which case we stop processing.
+		if errors.IsNotFound(err) {
+			Logx(ctx).WithField("key", key).Debug("Object in work queue no longer exists.")
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	// An invalid spec cannot be fixed by retrying, so record the problem and wait for the spec to change
+	if err = policy.Validate(); err != nil {
+		newStatus := tridentv1.TridentSnapshotPolicyStatus{
+			Message: fmt.Sprintf("Invalid snapshot policy: %v", err),
+		}
+		return time.Time{}, c.updateTridentSnapshotPolicyStatus(ctx, policy, newStatus, corev1.EventTypeWarning)
+	}
+
+	now := time.Now().UTC()
+	scheduleTime, err := c.getSnapshotPolicyScheduleTime(policy, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	nextScheduleTime, err := policy.NextScheduleTime(now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	volumes, pvcs, err := c.getSnapshotPolicyVolumes(ctx, policy)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	newStatus := tridentv1.TridentSnapshotPolicyStatus{
+		Volumes:          len(volumes),
+		LastScheduleTime: policy.Status.LastScheduleTime,
+		NextScheduleTime: nextScheduleTime.Format(time.RFC3339),
+	}
+	var failures []string
+
+	if !scheduleTime.IsZero() {
+		Logx(ctx).WithFields(log.Fields{
+			"policy":       policy.Name,
+			"scheduleTime": scheduleTime,
+			"volumes":      len(volumes),
+		}).Info("Creating scheduled snapshots.")
+
+		snapshotName := getPolicySnapshotPrefix(policy) + scheduleTime.Format(snapshotPolicyTimeFormat)
+		for _, volume := range volumes {
+			if err := c.createPolicySnapshot(ctx, policy, volume, pvcs[volume.Config.Name],
+				snapshotName); err != nil {
+				Logx(ctx).WithFields(log.Fields{
+					"policy": policy.Name,
+					"volume": volume.Config.Name,
+				}).Errorf("Could not create scheduled snapshot; %v", err)
+				failures = append(failures, fmt.Sprintf("volume %s: %v", volume.Config.Name, err))
+			}
+		}
+		newStatus.LastScheduleTime = scheduleTime.Format(time.RFC3339)
+	}
+
+	for _, volume := range volumes {
+		retained, err := c.prunePolicySnapshots(ctx, policy, volume, pvcs[volume.Config.Name])
+		if err != nil {
+			Logx(ctx).WithFields(log.Fields{
+				"policy": policy.Name,
+				"volume": volume.Config.Name,
+			}).Errorf("Could not prune snapshots; %v", err)
+			failures = append(failures, fmt.Sprintf("volume %s: %v", volume.Config.Name, err))
+		}
+		newStatus.Snapshots += retained
+	}
+
+	eventType := corev1.EventTypeNormal
+	if len(failures) > 0 {
+		eventType = corev1.EventTypeWarning
+		newStatus.Message = "Failed: " + strings.Join(failures, "; ")
+	} else if !scheduleTime.IsZero() {
+		newStatus.Message = fmt.Sprintf("Created %d scheduled snapshots", len(volumes))
+	} else {
+		newStatus.Message = policy.Status.Message
+	}
+
+	if err = c.updateTridentSnapshotPolicyStatus(ctx, policy, newStatus, eventType); err != nil {
+		return time.Time{}, err
+	}
+
+	return nextScheduleTime, nil
+}
+
+// getSnapshotPolicyScheduleTime returns the most recent time, no later than now, at which a snapshot
+// policy was scheduled to create snapshots but has not yet done so, or the zero time if snapshots
+// are not due.  Snapshots missed while Trident was not running are only created once.
+func (c *TridentCrdController) getSnapshotPolicyScheduleTime(
+	policy *tridentv1.TridentSnapshotPolicy, now time.Time,
+) (time.Time, error) {
+
+	lastScheduleTime, err := time.Parse(time.RFC3339, policy.Status.LastScheduleTime)
+	if err != nil {
+		lastScheduleTime = policy.CreationTimestamp.Time
+	}
+	if lastScheduleTime.IsZero() || lastScheduleTime.After(now) {
+		lastScheduleTime = now
+	}
+
+	var scheduleTime time.Time
+	for {
+		next, err := policy.NextScheduleTime(lastScheduleTime)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if next.IsZero() || next.After(now) {
+			return scheduleTime, nil
+		}
+		scheduleTime, lastScheduleTime = next, next
+	}
+}
+
+// getSnapshotPolicyVolumes returns the volumes selected by a snapshot policy, along with the PVCs
+// bound to those volumes, keyed by volume name.  A policy only selects volumes bound to PVCs in its
+// own namespace.
+func (c *TridentCrdController) getSnapshotPolicyVolumes(
+	ctx context.Context, policy *tridentv1.TridentSnapshotPolicy,
+) ([]*storage.VolumeExternal, map[string]*corev1.PersistentVolumeClaim, error) {
+
+	var selector labels.Selector
+	if policy.Spec.PVCSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(policy.Spec.PVCSelector); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	pvcList, err := c.kubeClientset.CoreV1().PersistentVolumeClaims(policy.Namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list PVCs in namespace %s; %v", policy.Namespace, err)
+	}
+	pvcs := make(map[string]*corev1.PersistentVolumeClaim)
+	for i := range pvcList.Items {
+		if pvc := &pvcList.Items[i]; pvc.Spec.VolumeName != "" {
+			pvcs[pvc.Spec.VolumeName] = pvc
+		}
+	}
+
+	allVolumes, err := c.orchestrator.ListVolumes(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list volumes; %v", err)
+	}
+
+	volumes := make([]*storage.VolumeExternal, 0)
+	for _, volume := range allVolumes {
+		if volume.State.IsDeleting() {
+			continue
+		}
+		if len(policy.Spec.StorageClasses) > 0 &&
+			!utils.SliceContainsString(policy.Spec.StorageClasses, volume.Config.StorageClass) {
+			continue
+		}
+		pvc, ok := pvcs[volume.Config.Name]
+		if !ok {
+			continue
+		}
+		if selector != nil && !selector.Matches(labels.Set(pvc.Labels)) {
+			continue
+		}
+		volumes = append(volumes, volume)
+	}
+
+	return volumes, pvcs, nil
+}
+
+// createPolicySnapshot creates a snapshot of a volume for a snapshot policy, unless it already
+// exists, along with a matching VolumeSnapshot in the PVC's namespace if the policy calls for one.
+func (c *TridentCrdController) createPolicySnapshot(
+	ctx context.Context, policy *tridentv1.TridentSnapshotPolicy, volume *storage.VolumeExternal,
+	pvc *corev1.PersistentVolumeClaim, snapshotName string,
+) error {
+
+	volumeName := volume.Config.Name
+
+	if _, err := c.orchestrator.GetSnapshot(ctx, volumeName, snapshotName); err != nil {
+		if !utils.IsNotFoundError(err) {
+			return err
+		}
+		snapshotConfig := &storage.SnapshotConfig{
+			Version:    config.OrchestratorAPIVersion,
+			Name:       snapshotName,
+			VolumeName: volumeName,
+		}
+		if _, err = c.orchestrator.CreateSnapshot(ctx, snapshotConfig); err != nil {
+			return err
+		}
+	}
+
+	if policy.Spec.VolumeSnapshotClass == "" {
+		return nil
+	}
+
+	volumeSnapshotName, contentName := getPolicyVolumeSnapshotNames(pvc, volumeName, snapshotName)
+	snapshotLabels := map[string]interface{}{
+		snapshotPolicyLabel:    policy.Name,
+		snapshotPolicyUIDLabel: string(policy.UID),
+	}
+
+	content := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": volumeSnapshotContentResource.GroupVersion().String(),
+		"kind":       "VolumeSnapshotContent",
+		"metadata": map[string]interface{}{
+			"name":   contentName,
+			"labels": snapshotLabels,
+		},
+		"spec": map[string]interface{}{
+			"deletionPolicy":          "Retain",
+			"driver":                  csi.Provisioner,
+			"volumeSnapshotClassName": policy.Spec.VolumeSnapshotClass,
+			"source": map[string]interface{}{
+				"snapshotHandle": storage.MakeSnapshotID(volumeName, snapshotName),
+			},
+			"volumeSnapshotRef": map[string]interface{}{
+				"name":      volumeSnapshotName,
+				"namespace": pvc.Namespace,
+			},
+		},
+	}}
+	_, err := c.dynamicClientset.Resource(volumeSnapshotContentResource).Create(ctx, content, createOpts)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("could not create VolumeSnapshotContent %s; %v", contentName, err)
+	}
+
+	volumeSnapshot := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": volumeSnapshotResource.GroupVersion().String(),
+		"kind":       "VolumeSnapshot",
+		"metadata": map[string]interface{}{
+			"name":      volumeSnapshotName,
+			"namespace": pvc.Namespace,
+			"labels":    snapshotLabels,
+		},
+		"spec": map[string]interface{}{
+			"volumeSnapshotClassName": policy.Spec.VolumeSnapshotClass,
+			"source": map[string]interface{}{
+				"volumeSnapshotContentName": contentName,
+			},
+		},
+	}}
+	_, err = c.dynamicClientset.Resource(volumeSnapshotResource).Namespace(pvc.Namespace).Create(
+		ctx, volumeSnapshot, createOpts)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("could not create VolumeSnapshot %s/%s; %v", pvc.Namespace, volumeSnapshotName, err)
+	}
+
+	return nil
+}
+
+// prunePolicySnapshots deletes the snapshots of a volume created by a snapshot policy that the
+// policy no longer retains, along with the matching VolumeSnapshots the policy created.  It returns
+// the number of the policy's snapshots that remain.
+func (c *TridentCrdController) prunePolicySnapshots(
+	ctx context.Context, policy *tridentv1.TridentSnapshotPolicy, volume *storage.VolumeExternal,
+	pvc *corev1.PersistentVolumeClaim,
+) (int, error) {
+
+	volumeName := volume.Config.Name
+
+	snapshots, err := c.orchestrator.ListSnapshotsForVolume(ctx, volumeName)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshotTimes := make(map[string]time.Time)
+	for _, snapshot := range snapshots {
+		if scheduleTime, ok := parsePolicySnapshotName(policy, snapshot.Config.Name); ok {
+			snapshotTimes[snapshot.Config.Name] = scheduleTime
+		}
+	}
+
+	retained := getRetainedPolicySnapshots(snapshotTimes, policy.Spec.Retention)
+
+	for snapshotName := range snapshotTimes {
+		if retained[snapshotName] {
+			continue
+		}
+
+		Logx(ctx).WithFields(log.Fields{
+			"policy":   policy.Name,
+			"volume":   volumeName,
+			"snapshot": snapshotName,
+		}).Info("Deleting snapshot that is no longer retained.")
+
+		if err = c.deletePolicyVolumeSnapshot(ctx, policy, pvc, volumeName, snapshotName); err != nil {
+			return len(retained), err
+		}
+
+		if err = c.orchestrator.DeleteSnapshot(ctx, volumeName, snapshotName); err != nil &&
+			!utils.IsNotFoundError(err) {
+			return len(retained), err
+		}
+	}
+
+	return len(retained), nil
+}
+
+// deletePolicyVolumeSnapshot deletes the VolumeSnapshot and VolumeSnapshotContent that represent a
+// snapshot policy's snapshot of the volume bound to a PVC.  Objects of the same names that the policy
+// did not create are left alone.
+func (c *TridentCrdController) deletePolicyVolumeSnapshot(
+	ctx context.Context, policy *tridentv1.TridentSnapshotPolicy, pvc *corev1.PersistentVolumeClaim,
+	volumeName, snapshotName string,
+) error {
+
+	volumeSnapshotName, contentName := getPolicyVolumeSnapshotNames(pvc, volumeName, snapshotName)
+
+	volumeSnapshots := c.dynamicClientset.Resource(volumeSnapshotResource).Namespace(pvc.Namespace)
+	volumeSnapshot, err := volumeSnapshots.Get(ctx, volumeSnapshotName, getOpts)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("could not get VolumeSnapshot %s/%s; %v", pvc.Namespace, volumeSnapshotName, err)
+	} else if err == nil && isOwnedByPolicy(volumeSnapshot, policy) {
+		err = volumeSnapshots.Delete(ctx, volumeSnapshotName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("could not delete VolumeSnapshot %s/%s; %v", pvc.Namespace, volumeSnapshotName, err)
+		}
+	}
+
+	contents := c.dynamicClientset.Resource(volumeSnapshotContentResource)
+	content, err := contents.Get(ctx, contentName, getOpts)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("could not get VolumeSnapshotContent %s; %v", contentName, err)
+	} else if err == nil && isOwnedByPolicy(content, policy) {
+		err = contents.Delete(ctx, contentName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("could not delete VolumeSnapshotContent %s; %v", contentName, err)
+		}
+	}
+
+	return nil
+}
+
+// isOwnedByPolicy returns true if an object was created by the given snapshot policy.
+func isOwnedByPolicy(obj metav1.Object, policy *tridentv1.TridentSnapshotPolicy) bool {
+	return obj.GetLabels()[snapshotPolicyUIDLabel] == string(policy.UID)
+}
+
+// getPolicySnapshotPrefix returns the prefix of the names of the snapshots created by a snapshot
+// policy.  The prefix includes the policy's UID, so a policy never claims snapshots created by hand,
+// by another policy of the same name in a different namespace, or by a policy it replaced.
+func getPolicySnapshotPrefix(policy *tridentv1.TridentSnapshotPolicy) string {
+	return policy.Name + "-" + string(policy.UID) + "-"
+}
+
+// parsePolicySnapshotName returns the schedule time of a snapshot created by a snapshot policy, and
+// false if the snapshot was not created by that policy.
+func parsePolicySnapshotName(policy *tridentv1.TridentSnapshotPolicy, snapshotName string) (time.Time, bool) {
+
+	prefix := getPolicySnapshotPrefix(policy)
+	if !strings.HasPrefix(snapshotName, prefix) {
+		return time.Time{}, false
+	}
+
+	scheduleTime, err := time.Parse(snapshotPolicyTimeFormat, strings.TrimPrefix(snapshotName, prefix))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return scheduleTime, true
+}
+
+// getRetainedPolicySnapshots returns the names of the snapshots a retention policy keeps, given the
+// schedule time of each snapshot.  The newest snapshot in each of the most recent hours, days, and
+// ISO weeks that have snapshots is retained, up to the number of each allowed by the policy.
+func getRetainedPolicySnapshots(
+	snapshotTimes map[string]time.Time, retention tridentv1.TridentSnapshotPolicyRetention,
+) map[string]bool {
+
+	names := make([]string, 0, len(snapshotTimes))
+	for name := range snapshotTimes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return snapshotTimes[names[i]].After(snapshotTimes[names[j]])
+	})
+
+	periods := []struct {
+		count  int
+		period func(time.Time) string
+	}{
+		{retention.Hourly, func(t time.Time) string { return t.Format("2006-01-02T15") }},
+		{retention.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{retention.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+	}
+
+	retained := make(map[string]bool)
+	for _, p := range periods {
+		seen := make(map[string]bool)
+		for _, name := range names {
+			if len(seen) >= p.count {
+				break
+			}
+			if period := p.period(snapshotTimes[name]); !seen[period] {
+				seen[period] = true
+				retained[name] = true
+			}
+		}
+	}
+
+	return retained
+}
+
+// getPolicyVolumeSnapshotNames returns the names of the VolumeSnapshot and VolumeSnapshotContent that
+// represent a snapshot policy's snapshot of the volume bound to a PVC.
+func getPolicyVolumeSnapshotNames(
+	pvc *corev1.PersistentVolumeClaim, volumeName, snapshotName string,
+) (string, string) {
+	return pvc.Name + "-" + snapshotName, "snapcontent-" + volumeName + "-" + snapshotName
+}
+
+// updateTridentSnapshotPolicyStatus updates the Status block of a TridentSnapshotPolicy CR, recording
+// an event if its message changed.
+func (c *TridentCrdController) updateTridentSnapshotPolicyStatus(
+	ctx context.Context, policy *tridentv1.TridentSnapshotPolicy,
+	newStatus tridentv1.TridentSnapshotPolicyStatus, eventType string,
+) error {
+
+	logFields := log.Fields{"TridentSnapshotPolicyCR": policy.Name}
+
+	newStatus.ObservedGeneration = policy.Generation
+
+	if reflect.DeepEqual(policy.Status, newStatus) {
+		Logx(ctx).WithFields(logFields).Debug("New status is same as the old status, no status update needed.")
+		return nil
+	}
+
+	crClone := policy.DeepCopy()
+	crClone.Status = newStatus
+
+	if _, err := c.crdClientset.TridentV1().TridentSnapshotPolicies(policy.Namespace).UpdateStatus(
+		ctx, crClone, updateOpts); err != nil {
+		Logx(ctx).WithFields(logFields).Errorf("Could not update status of the CR; %v", err)
+		return err
+	}
+
+	if newStatus.Message != policy.Status.Message || newStatus.LastScheduleTime != policy.Status.LastScheduleTime {
+		reason := "Scheduled"
+		if eventType == corev1.EventTypeWarning {
+			reason = OperationStatusFailed
+		}
+		c.recorder.Event(policy, eventType, reason, newStatus.Message)
+	}
+
+	return nil
+}
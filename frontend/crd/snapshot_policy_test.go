@@ -0,0 +1,313 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package crd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/netapp/trident/config"
+	tridentv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+)
+
+func TestGetRetainedPolicySnapshots(t *testing.T) {
+
+	// Snapshots every six hours over two weeks, ending on Sunday 2021-05-09
+	snapshotTimes := make(map[string]time.Time)
+	end := time.Date(2021, 5, 9, 18, 0, 0, 0, time.UTC)
+	for scheduleTime := end.AddDate(0, 0, -14); !scheduleTime.After(end); {
+		snapshotTimes["daily-"+scheduleTime.Format(snapshotPolicyTimeFormat)] = scheduleTime
+		scheduleTime = scheduleTime.Add(6 * time.Hour)
+	}
+
+	tests := []struct {
+		name      string
+		retention tridentv1.TridentSnapshotPolicyRetention
+		expected  []string
+	}{
+		{"hourly", tridentv1.TridentSnapshotPolicyRetention{Hourly: 3}, []string{
+			"daily-20210509-180000", "daily-20210509-120000", "daily-20210509-060000"}},
+		{"daily", tridentv1.TridentSnapshotPolicyRetention{Daily: 2}, []string{
+			"daily-20210509-180000", "daily-20210508-180000"}},
+		{"weekly", tridentv1.TridentSnapshotPolicyRetention{Weekly: 3}, []string{
+			"daily-20210509-180000", "daily-20210502-180000", "daily-20210425-180000"}},
+		{"combined", tridentv1.TridentSnapshotPolicyRetention{Hourly: 2, Daily: 2, Weekly: 2}, []string{
+			"daily-20210509-180000", "daily-20210509-120000", "daily-20210508-180000", "daily-20210502-180000"}},
+		{"more than exist", tridentv1.TridentSnapshotPolicyRetention{Weekly: 10}, []string{
+			"daily-20210509-180000", "daily-20210502-180000", "daily-20210425-180000"}},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		retained := getRetainedPolicySnapshots(snapshotTimes, test.retention)
+
+		expected := make(map[string]bool)
+		for _, name := range test.expected {
+			expected[name] = true
+		}
+		assert.Equal(t, expected, retained, test.name)
+	}
+}
+
+func TestParsePolicySnapshotName(t *testing.T) {
+
+	policy := &tridentv1.TridentSnapshotPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "app", UID: "1234"},
+	}
+
+	tests := []struct {
+		name         string
+		snapshotName string
+		expected     time.Time
+		ok           bool
+	}{
+		{"policy snapshot", "nightly-1234-20210509-000000", time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC), true},
+		{"other policy", "hourly-1234-20210509-000000", time.Time{}, false},
+		{"policy with same name", "nightly-5678-20210509-000000", time.Time{}, false},
+		{"manual snapshot with policy name", "nightly-20210509-000000", time.Time{}, false},
+		{"manual snapshot", "nightly-backup", time.Time{}, false},
+		{"CSI snapshot", "snapshot-4f1c9dd5-43c6-4e2b-9a4a-3c2f8e5f4f4b", time.Time{}, false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		scheduleTime, ok := parsePolicySnapshotName(policy, test.snapshotName)
+		assert.Equal(t, test.ok, ok, test.name)
+		assert.Equal(t, test.expected, scheduleTime, test.name)
+	}
+}
+
+// addTestSnapshotPolicy creates a snapshot policy and adds it to the controller's informer cache.
+func addTestSnapshotPolicy(t *testing.T, c *TridentCrdController, policy *tridentv1.TridentSnapshotPolicy) {
+
+	policy, err := c.crdClientset.TridentV1().TridentSnapshotPolicies(policy.Namespace).Create(ctx(), policy,
+		createOpts)
+	if err != nil {
+		t.Fatalf("Unable to create snapshot policy: %v", err)
+	}
+	indexer := c.policyInformerFactory.Trident().V1().TridentSnapshotPolicies().Informer().GetIndexer()
+	if err = indexer.Add(policy); err != nil {
+		t.Fatalf("Unable to cache snapshot policy: %v", err)
+	}
+}
+
+func TestHandleTridentSnapshotPolicy(t *testing.T) {
+
+	crdController, orchestrator := getFakeBackendTestController(t, "gold", "silver")
+
+	volumes := map[string]string{"goldVolume": "gold", "silverVolume": "silver"}
+	for volumeName, scName := range volumes {
+		volConfig := &storage.VolumeConfig{
+			Version:      config.OrchestratorAPIVersion,
+			Name:         volumeName,
+			InternalName: volumeName,
+			Size:         "1073741824",
+			Protocol:     config.File,
+			StorageClass: scName,
+		}
+		if _, err := orchestrator.AddVolume(ctx(), volConfig); err != nil {
+			t.Fatalf("Unable to add volume: %v", err)
+		}
+	}
+
+	// An older snapshot created by the policy, which is no longer retained, and manual snapshots
+	oldSnapshotName := "nightly-1234-20210101-000000"
+	for _, snapshotName := range []string{oldSnapshotName, "nightly-20210101-000000", "manual"} {
+		_, err := orchestrator.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+			Version: config.OrchestratorAPIVersion, Name: snapshotName, VolumeName: "goldVolume"})
+		if err != nil {
+			t.Fatalf("Unable to create snapshot: %v", err)
+		}
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "app"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "goldVolume"},
+	}
+	if _, err := crdController.kubeClientset.CoreV1().PersistentVolumeClaims("app").Create(ctx(), pvc,
+		createOpts); err != nil {
+		t.Fatalf("Unable to create PVC: %v", err)
+	}
+
+	// A VolumeSnapshot the policy did not create, named like the one for its older snapshot
+	otherVolumeSnapshotName, _ := getPolicyVolumeSnapshotNames(pvc, "goldVolume", oldSnapshotName)
+	otherVolumeSnapshot := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": volumeSnapshotResource.GroupVersion().String(),
+		"kind":       "VolumeSnapshot",
+		"metadata":   map[string]interface{}{"name": otherVolumeSnapshotName, "namespace": "app"},
+	}}
+	if _, err := crdController.dynamicClientset.Resource(volumeSnapshotResource).Namespace("app").Create(ctx(),
+		otherVolumeSnapshot, createOpts); err != nil {
+		t.Fatalf("Unable to create VolumeSnapshot: %v", err)
+	}
+
+	lastScheduleTime := time.Now().UTC().Add(-5 * time.Minute)
+	addTestSnapshotPolicy(t, crdController, &tridentv1.TridentSnapshotPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "app", UID: "1234"},
+		Spec: tridentv1.TridentSnapshotPolicySpec{
+			StorageClasses:      []string{"gold", "silver"},
+			Schedules:           []string{"* * * * *"},
+			Retention:           tridentv1.TridentSnapshotPolicyRetention{Daily: 1},
+			VolumeSnapshotClass: "csi-snapclass",
+		},
+		Status: tridentv1.TridentSnapshotPolicyStatus{
+			LastScheduleTime: lastScheduleTime.Format(time.RFC3339),
+		},
+	})
+
+	keyItem := &KeyItem{
+		key: "app/nightly", event: EventAdd, ctx: ctx(), objectType: ObjectTypeTridentSnapshotPolicy,
+	}
+	nextScheduleTime, err := crdController.handleTridentSnapshotPolicy(keyItem)
+	assert.NoError(t, err)
+	assert.True(t, nextScheduleTime.After(time.Now()), "next schedule time should be in the future")
+	assert.True(t, nextScheduleTime.Before(time.Now().Add(time.Minute)), "next schedule time should be soon")
+
+	policy, err := crdController.crdClientset.TridentV1().TridentSnapshotPolicies("app").Get(ctx(),
+		"nightly", getOpts)
+	assert.NoError(t, err)
+
+	// The newest schedule time has a snapshot, and the older policy snapshot was pruned
+	snapshots, err := orchestrator.ListSnapshotsForVolume(ctx(), "goldVolume")
+	assert.NoError(t, err)
+	snapshotNames := make([]string, 0)
+	var policySnapshotName string
+	for _, snapshot := range snapshots {
+		snapshotNames = append(snapshotNames, snapshot.Config.Name)
+		if scheduleTime, ok := parsePolicySnapshotName(policy, snapshot.Config.Name); ok {
+			policySnapshotName = snapshot.Config.Name
+			assert.True(t, scheduleTime.After(lastScheduleTime), "snapshot should be for a new schedule time")
+		}
+	}
+	assert.ElementsMatch(t, []string{"manual", "nightly-20210101-000000", policySnapshotName}, snapshotNames)
+
+	// Volumes not bound to a PVC in the policy's namespace are not snapshotted
+	snapshots, err = orchestrator.ListSnapshotsForVolume(ctx(), "silverVolume")
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	// The VolumeSnapshot the policy did not create was not pruned
+	_, err = crdController.dynamicClientset.Resource(volumeSnapshotResource).Namespace("app").Get(ctx(),
+		otherVolumeSnapshotName, getOpts)
+	assert.NoError(t, err, "VolumeSnapshot not created by the policy should remain")
+
+	// The snapshot is represented by a VolumeSnapshot in the PVC's namespace
+	volumeSnapshotName, contentName := getPolicyVolumeSnapshotNames(pvc, "goldVolume", policySnapshotName)
+	volumeSnapshot, err := crdController.dynamicClientset.Resource(volumeSnapshotResource).Namespace("app").Get(
+		ctx(), volumeSnapshotName, getOpts)
+	if assert.NoError(t, err, "VolumeSnapshot should exist") {
+		assert.True(t, isOwnedByPolicy(volumeSnapshot, policy), "VolumeSnapshot should be owned by the policy")
+	}
+	content, err := crdController.dynamicClientset.Resource(volumeSnapshotContentResource).Get(ctx(),
+		contentName, getOpts)
+	if assert.NoError(t, err, "VolumeSnapshotContent should exist") {
+		handle, _, _ := unstructured.NestedString(content.Object, "spec", "source", "snapshotHandle")
+		assert.Equal(t, "goldVolume/"+policySnapshotName, handle)
+	}
+
+	policy, err = crdController.crdClientset.TridentV1().TridentSnapshotPolicies("app").Get(ctx(),
+		"nightly", getOpts)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, policy.Status.Volumes)
+	assert.Equal(t, 1, policy.Status.Snapshots)
+	assert.Equal(t, nextScheduleTime.Format(time.RFC3339), policy.Status.NextScheduleTime)
+	assert.NotEqual(t, lastScheduleTime.Format(time.RFC3339), policy.Status.LastScheduleTime)
+}
+
+func TestSnapshotPolicyIgnoresOtherNamespaces(t *testing.T) {
+
+	crdController, orchestrator := getFakeBackendTestController(t, "gold")
+
+	volConfig := &storage.VolumeConfig{
+		Version:      config.OrchestratorAPIVersion,
+		Name:         "goldVolume",
+		InternalName: "goldVolume",
+		Size:         "1073741824",
+		Protocol:     config.File,
+		StorageClass: "gold",
+	}
+	if _, err := orchestrator.AddVolume(ctx(), volConfig); err != nil {
+		t.Fatalf("Unable to add volume: %v", err)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "app"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "goldVolume"},
+	}
+	if _, err := crdController.kubeClientset.CoreV1().PersistentVolumeClaims("app").Create(ctx(), pvc,
+		createOpts); err != nil {
+		t.Fatalf("Unable to create PVC: %v", err)
+	}
+
+	addTestSnapshotPolicy(t, crdController, &tridentv1.TridentSnapshotPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "trident", UID: "1234"},
+		Spec: tridentv1.TridentSnapshotPolicySpec{
+			StorageClasses:      []string{"gold"},
+			Schedules:           []string{"* * * * *"},
+			Retention:           tridentv1.TridentSnapshotPolicyRetention{Daily: 1},
+			VolumeSnapshotClass: "csi-snapclass",
+		},
+		Status: tridentv1.TridentSnapshotPolicyStatus{
+			LastScheduleTime: time.Now().UTC().Add(-5 * time.Minute).Format(time.RFC3339),
+		},
+	})
+
+	keyItem := &KeyItem{
+		key: "trident/nightly", event: EventAdd, ctx: ctx(), objectType: ObjectTypeTridentSnapshotPolicy,
+	}
+	_, err := crdController.handleTridentSnapshotPolicy(keyItem)
+	assert.NoError(t, err)
+
+	policy, err := crdController.crdClientset.TridentV1().TridentSnapshotPolicies("trident").Get(ctx(),
+		"nightly", getOpts)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, policy.Status.Volumes)
+
+	// The volume of the PVC in another namespace is neither snapshotted nor given a VolumeSnapshot
+	snapshots, err := orchestrator.ListSnapshotsForVolume(ctx(), "goldVolume")
+	assert.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	scheduleTime, err := time.Parse(time.RFC3339, policy.Status.LastScheduleTime)
+	assert.NoError(t, err)
+	snapshotName := getPolicySnapshotPrefix(policy) + scheduleTime.Format(snapshotPolicyTimeFormat)
+	volumeSnapshotName, _ := getPolicyVolumeSnapshotNames(pvc, "goldVolume", snapshotName)
+	_, err = crdController.dynamicClientset.Resource(volumeSnapshotResource).Namespace("app").Get(ctx(),
+		volumeSnapshotName, getOpts)
+	assert.True(t, errors.IsNotFound(err), "VolumeSnapshot should not exist")
+}
+
+func TestHandleInvalidTridentSnapshotPolicy(t *testing.T) {
+
+	crdController, _ := getFakeBackendTestController(t)
+
+	addTestSnapshotPolicy(t, crdController, &tridentv1.TridentSnapshotPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "trident"},
+		Spec: tridentv1.TridentSnapshotPolicySpec{
+			StorageClasses: []string{"gold"},
+			Schedules:      []string{"every night"},
+			Retention:      tridentv1.TridentSnapshotPolicyRetention{Daily: 7},
+		},
+	})
+
+	keyItem := &KeyItem{
+		key: "trident/invalid", event: EventAdd, ctx: ctx(), objectType: ObjectTypeTridentSnapshotPolicy,
+	}
+	nextScheduleTime, err := crdController.handleTridentSnapshotPolicy(keyItem)
+	assert.NoError(t, err)
+	assert.True(t, nextScheduleTime.IsZero(), "invalid policy should not be scheduled")
+
+	policy, err := crdController.crdClientset.TridentV1().TridentSnapshotPolicies("trident").Get(ctx(),
+		"invalid", getOpts)
+	assert.NoError(t, err)
+	assert.Contains(t, policy.Status.Message, "Invalid snapshot policy")
+}
@@ -12,4 +12,7 @@ const (
 	// CSI supported features
 	CSIBlockVolumes  helpers.Feature = "CSI_BLOCK_VOLUMES"
 	ExpandCSIVolumes helpers.Feature = "EXPAND_CSI_VOLUMES"
+
+	// Key of the LUKS passphrase in the node stage secret
+	LUKSPassphraseSecretKey = "luks-passphrase"
 )
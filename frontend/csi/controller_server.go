@@ -352,6 +352,7 @@ func (p *Plugin) ControllerPublishVolume(
 	volumePublishInfo := &utils.VolumePublishInfo{
 		Localhost: false,
 		HostIQN:   []string{nodeInfo.IQN},
+		HostNQN:   nodeInfo.NQN,
 		HostIP:    nodeInfo.IPs,
 		HostName:  nodeInfo.Name,
 		Unmanaged: volume.Config.ImportNotManaged,
@@ -380,13 +381,29 @@ func (p *Plugin) ControllerPublishVolume(
 	if volume.Config.Protocol == tridentconfig.File {
 		publishInfo["nfsServerIp"] = volume.Config.AccessInfo.NfsServerIP
 		publishInfo["nfsPath"] = volume.Config.AccessInfo.NfsPath
+	} else if volume.Config.Protocol == tridentconfig.Block && volumePublishInfo.SANType == string(tridentconfig.NVMe) {
+		publishInfo["sanType"] = volumePublishInfo.SANType
+		publishInfo["nvmeSubsystemNqn"] = volumePublishInfo.NVMeSubsystemNQN
+		publishInfo["nvmeNamespaceUuid"] = volumePublishInfo.NVMeNamespaceUUID
+		publishInfo["nvmeTargetIps"] = strings.Join(volumePublishInfo.NVMeTargetIPs, ",")
+		publishInfo["filesystemType"] = volumePublishInfo.FilesystemType
 	} else if volume.Config.Protocol == tridentconfig.Block {
+		// LUNs mapped to each node as they are published may have a different LUN number on every node,
+		// so prefer the values returned by the driver over those recorded when the volume was created.
+		iscsiAccessInfo := volume.Config.AccessInfo.IscsiAccessInfo
+		if volumePublishInfo.IscsiTargetIQN != "" {
+			iscsiAccessInfo.IscsiTargetIQN = volumePublishInfo.IscsiTargetIQN
+			iscsiAccessInfo.IscsiLunNumber = volumePublishInfo.IscsiLunNumber
+			if volumePublishInfo.IscsiIgroup != "" {
+				iscsiAccessInfo.IscsiIgroup = volumePublishInfo.IscsiIgroup
+			}
+		}
 		stashIscsiTargetPortals(publishInfo, volumePublishInfo)
-		publishInfo["iscsiTargetIqn"] = volume.Config.AccessInfo.IscsiTargetIQN
-		publishInfo["iscsiLunNumber"] = strconv.Itoa(int(volume.Config.AccessInfo.IscsiLunNumber))
-		publishInfo["iscsiInterface"] = volume.Config.AccessInfo.IscsiInterface
-		publishInfo["iscsiLunSerial"] = volume.Config.AccessInfo.IscsiLunSerial
-		publishInfo["iscsiIgroup"] = volume.Config.AccessInfo.IscsiIgroup
+		publishInfo["iscsiTargetIqn"] = iscsiAccessInfo.IscsiTargetIQN
+		publishInfo["iscsiLunNumber"] = strconv.Itoa(int(iscsiAccessInfo.IscsiLunNumber))
+		publishInfo["iscsiInterface"] = iscsiAccessInfo.IscsiInterface
+		publishInfo["iscsiLunSerial"] = iscsiAccessInfo.IscsiLunSerial
+		publishInfo["iscsiIgroup"] = iscsiAccessInfo.IscsiIgroup
 		// Encrypt and add CHAP credentials if they're needed
 		if volumePublishInfo.UseCHAP {
 			if p.aesKey != nil {
@@ -400,6 +417,7 @@ func (p *Plugin) ControllerPublishVolume(
 			}
 		}
 		publishInfo["filesystemType"] = volumePublishInfo.FilesystemType
+		publishInfo["luksEncryption"] = volume.Config.LUKSEncryption
 		publishInfo["useCHAP"] = strconv.FormatBool(volumePublishInfo.UseCHAP)
 		publishInfo["sharedTarget"] = strconv.FormatBool(volumePublishInfo.SharedTarget)
 	}
@@ -421,11 +439,34 @@ func (p *Plugin) ControllerUnpublishVolume(
 	}
 
 	// Check if volume exists.  If not, return success.
-	if _, err := p.orchestrator.GetVolume(ctx, volumeID); err != nil && !utils.IsNotFoundError(err) {
+	volume, err := p.orchestrator.GetVolume(ctx, volumeID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return &csi.ControllerUnpublishVolumeResponse{}, nil
+		}
 		return nil, p.getCSIErrorForOrchestratorError(err)
 	}
 
-	// Apart from validation, Trident has nothing to do for this entry point
+	// Without a node, the volume is unpublished from every node to which it is published
+	nodeIDs := []string{req.GetNodeId()}
+	if req.GetNodeId() == "" {
+		nodeIDs = volume.PublishedNodes
+		Logc(ctx).WithFields(log.Fields{
+			"volume": volumeID,
+			"nodes":  nodeIDs,
+		}).Debug("No node ID provided, unpublishing volume from all nodes.")
+	}
+
+	// Remove each node's access to the volume, such as its igroup map, access group or host mapping
+	for _, nodeID := range nodeIDs {
+		if err = p.orchestrator.UnpublishVolume(ctx, volumeID, nodeID); err != nil {
+			if utils.IsNotFoundError(err) {
+				continue
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
 	return &csi.ControllerUnpublishVolumeResponse{}, nil
 }
 
@@ -524,7 +565,13 @@ func (p *Plugin) ListVolumes(
 
 		if len(entries) < maxPageEntries {
 			if csiVolume, err := p.getCSIVolumeFromTridentVolume(ctx, volume); err == nil {
-				entries = append(entries, &csi.ListVolumesResponse_Entry{Volume: csiVolume})
+				entries = append(entries, &csi.ListVolumesResponse_Entry{
+					Volume: csiVolume,
+					Status: &csi.ListVolumesResponse_VolumeStatus{
+						PublishedNodeIds: volume.PublishedNodes,
+						VolumeCondition:  p.getCachedCSIVolumeCondition(volume.Config.Name),
+					},
+				})
 			}
 		} else {
 			nextToken = volume.Config.Name
@@ -535,10 +582,70 @@ func (p *Plugin) ListVolumes(
 	return &csi.ListVolumesResponse{Entries: entries, NextToken: nextToken}, nil
 }
 
-func (p *Plugin) GetCapacity(_ context.Context, _ *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
+func (p *Plugin) GetCapacity(
+	ctx context.Context, req *csi.GetCapacityRequest,
+) (*csi.GetCapacityResponse, error) {
+
+	fields := log.Fields{"Method": "GetCapacity", "Type": "CSI_Controller"}
+	Logc(ctx).WithFields(fields).Debug(">>>> GetCapacity")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< GetCapacity")
+
+	// Determine the protocol implied by the volume capabilities, if any were specified
+	volumeMode := tridentconfig.Filesystem
+	for _, capability := range req.GetVolumeCapabilities() {
+		if block := capability.GetBlock(); block != nil {
+			volumeMode = tridentconfig.RawBlock
+		}
+	}
+
+	protocol := tridentconfig.ProtocolAny
+	for _, capability := range req.GetVolumeCapabilities() {
+		protocolLocal := p.getProtocolForCSIAccessMode(capability.GetAccessMode().GetMode(), volumeMode)
+		if protocolLocal == tridentconfig.ProtocolAny {
+			continue
+		}
+		if protocol != tridentconfig.ProtocolAny && protocol != protocolLocal {
+			return nil, status.Error(codes.InvalidArgument, "volume capabilities translate to both file and block protocols")
+		}
+		protocol = protocolLocal
+	}
+
+	// Find the storage class described by the request parameters
+	scName, err := p.helper.GetStorageClassForParameters(ctx, req.GetParameters())
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			// No matching storage class means no capacity is available
+			return &csi.GetCapacityResponse{AvailableCapacity: 0}, nil
+		}
+		return nil, p.getCSIErrorForOrchestratorError(err)
+	}
+
+	topology := make(map[string]string)
+	for k, v := range req.GetAccessibleTopology().GetSegments() {
+		topology[k] = v
+	}
+
+	capacity, err := p.orchestrator.GetStorageClassCapacity(ctx, scName, protocol, topology)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return &csi.GetCapacityResponse{AvailableCapacity: 0}, nil
+		}
+		return nil, p.getCSIErrorForOrchestratorError(err)
+	}
+
+	availableCapacity := int64(math.MaxInt64)
+	if capacity.AvailableBytes < math.MaxInt64 {
+		availableCapacity = int64(capacity.AvailableBytes)
+	}
 
-	// Trident doesn't report pool capacities
-	return nil, status.Error(codes.Unimplemented, "")
+	Logc(ctx).WithFields(log.Fields{
+		"storageClass":      scName,
+		"protocol":          protocol,
+		"topology":          topology,
+		"availableCapacity": availableCapacity,
+	}).Debug("Reporting storage class capacity.")
+
+	return &csi.GetCapacityResponse{AvailableCapacity: availableCapacity}, nil
 }
 
 func (p *Plugin) ControllerGetCapabilities(
@@ -857,11 +964,55 @@ func (p *Plugin) ControllerExpandVolume(
 }
 
 func (p *Plugin) ControllerGetVolume(
-	_ context.Context, _ *csi.ControllerGetVolumeRequest,
+	ctx context.Context, req *csi.ControllerGetVolumeRequest,
 ) (*csi.ControllerGetVolumeResponse, error) {
 
-	// Trident doesn't support ControllerGetVolume
-	return nil, status.Error(codes.Unimplemented, "")
+	fields := log.Fields{"Method": "ControllerGetVolume", "Type": "CSI_Controller"}
+	Logc(ctx).WithFields(fields).Debug(">>>> ControllerGetVolume")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< ControllerGetVolume")
+
+	volumeID := req.GetVolumeId()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "no volume ID provided")
+	}
+
+	volume, err := p.orchestrator.GetVolume(ctx, volumeID)
+	if err != nil {
+		return nil, p.getCSIErrorForOrchestratorError(err)
+	}
+
+	csiVolume, err := p.getCSIVolumeFromTridentVolume(ctx, volume)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// A single volume's condition is cheap enough to get from its backend
+	condition := p.getCSIVolumeCondition(ctx, volumeID)
+	p.volumeConditions.Store(volumeID, condition)
+
+	return &csi.ControllerGetVolumeResponse{
+		Volume: csiVolume,
+		Status: &csi.ControllerGetVolumeResponse_VolumeStatus{
+			PublishedNodeIds: volume.PublishedNodes,
+			VolumeCondition:  condition,
+		},
+	}, nil
+}
+
+// getCSIVolumeCondition returns the condition of a volume as reported by its backend.  Errors are reported
+// as an abnormal condition, since a volume whose condition cannot be determined may well be unusable.
+func (p *Plugin) getCSIVolumeCondition(ctx context.Context, volumeName string) *csi.VolumeCondition {
+
+	condition, err := p.orchestrator.GetVolumeCondition(ctx, volumeName)
+	if err != nil {
+		Logc(ctx).WithField("volume", volumeName).WithError(err).Warning("Could not get volume condition.")
+		return &csi.VolumeCondition{
+			Abnormal: true,
+			Message:  fmt.Sprintf("could not get volume condition; %v", err),
+		}
+	}
+
+	return &csi.VolumeCondition{Abnormal: condition.Abnormal, Message: condition.Message}
 }
 
 func (p *Plugin) getCSIVolumeFromTridentVolume(
@@ -0,0 +1,85 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+
+	. "github.com/netapp/trident/logger"
+)
+
+const volumeConditionRefreshInterval = 5 * time.Minute
+
+// startVolumeConditionThread periodically asks the backends for the condition of every volume and caches
+// the results, so that listing volumes does not wait on a backend call for each volume listed.  The
+// thread runs until the stop channel is closed.
+func (p *Plugin) startVolumeConditionThread(ctx context.Context, stop <-chan struct{}) {
+
+	Logc(ctx).WithField("interval", volumeConditionRefreshInterval).Info("Starting volume condition thread.")
+
+	ticker := time.NewTicker(volumeConditionRefreshInterval)
+
+	go func() {
+		defer ticker.Stop()
+		p.refreshVolumeConditions(ctx)
+		for {
+			select {
+			case <-stop:
+				Logc(ctx).Info("Stopped volume condition thread.")
+				return
+			case <-ticker.C:
+				p.refreshVolumeConditions(ctx)
+			}
+		}
+	}()
+}
+
+// stopVolumeConditionThread stops the volume condition thread, if it was started.
+func (p *Plugin) stopVolumeConditionThread(ctx context.Context) {
+
+	if p.stopVolumeConditionRefresh != nil {
+		Logc(ctx).Info("Stopping volume condition thread.")
+		close(p.stopVolumeConditionRefresh)
+		p.stopVolumeConditionRefresh = nil
+	}
+}
+
+// refreshVolumeConditions caches the current condition of each volume and forgets the conditions of
+// volumes that no longer exist.
+func (p *Plugin) refreshVolumeConditions(ctx context.Context) {
+
+	Logc(ctx).Debug(">>>> refreshVolumeConditions")
+	defer Logc(ctx).Debug("<<<< refreshVolumeConditions")
+
+	volumes, err := p.orchestrator.ListVolumes(ctx)
+	if err != nil {
+		Logc(ctx).WithError(err).Debug("Could not list volumes to refresh their conditions.")
+		return
+	}
+
+	volumeNames := make(map[string]bool)
+	for _, volume := range volumes {
+		volumeNames[volume.Config.Name] = true
+		p.volumeConditions.Store(volume.Config.Name, p.getCSIVolumeCondition(ctx, volume.Config.Name))
+	}
+
+	p.volumeConditions.Range(func(key, _ interface{}) bool {
+		if !volumeNames[key.(string)] {
+			p.volumeConditions.Delete(key)
+		}
+		return true
+	})
+}
+
+// getCachedCSIVolumeCondition returns the last known condition of a volume, or nil if it has not yet
+// been determined.
+func (p *Plugin) getCachedCSIVolumeCondition(volumeName string) *csi.VolumeCondition {
+
+	if condition, ok := p.volumeConditions.Load(volumeName); ok {
+		return condition.(*csi.VolumeCondition)
+	}
+	return nil
+}
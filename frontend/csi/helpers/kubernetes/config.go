@@ -22,13 +22,19 @@ const (
 	PodDeleteWaitPeriod     = 60 * time.Second
 	ImportPVCacheWaitPeriod = 180 * time.Second
 
+	PublishedNodesBackfillMaxElapsedTime = 10 * time.Minute
+
 	CacheBackoffInitialInterval     = 1 * time.Second
 	CacheBackoffRandomizationFactor = 0.1
 	CacheBackoffMultiplier          = 1.414
 	CacheBackoffMaxInterval         = 5 * time.Second
 
 	// Kubernetes-defined storage class parameters
-	K8sFsType = "fsType"
+	K8sFsType             = "fsType"
+	K8sCSIParameterPrefix = "csi.storage.k8s.io/"
+
+	// Orchestrator-defined storage class parameters that are volume options rather than storage attributes
+	SCParameterLUKSEncryption = "luksEncryption"
 
 	// Kubernetes-defined annotations
 	// (Based on kubernetes/pkg/controller/volume/persistentvolume/controller.go)
@@ -48,6 +54,9 @@ const (
 	AnnSnapshotDir        = annPrefix + "/snapshotDirectory"
 	AnnUnixPermissions    = annPrefix + "/unixPermissions"
 	AnnExportPolicy       = annPrefix + "/exportPolicy"
+	AnnQosPolicy          = annPrefix + "/qosPolicy"
+	AnnAdaptiveQosPolicy  = annPrefix + "/adaptiveQosPolicy"
+	AnnTieringPolicy      = annPrefix + "/tieringPolicy"
 	AnnBlockSize          = annPrefix + "/blockSize"
 	AnnFileSystem         = annPrefix + "/fileSystem"
 	AnnCloneFromPVC       = annPrefix + "/cloneFromPVC"
@@ -55,6 +64,8 @@ const (
 	AnnNotManaged         = annPrefix + "/notManaged"
 	AnnImportOriginalName = annPrefix + "/importOriginalName"
 	AnnImportBackendUUID  = annPrefix + "/importBackendUUID"
+	AnnMirrorDestination  = annPrefix + "/mirrorDestination"
+	AnnLUKSEncryption     = annPrefix + "/luksEncryption"
 )
 
 var features = map[helpers.Feature]*utils.Version{
@@ -4,6 +4,8 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -18,6 +20,7 @@ import (
 	"github.com/netapp/trident/frontend/csi/helpers"
 	. "github.com/netapp/trident/logger"
 	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
 )
 
 /////////////////////////////////////////////////////////////////////////////
@@ -85,8 +88,11 @@ func (p *Plugin) GetVolumeConfig(
 	}
 
 	// Create the volume config
-	volumeConfig := getVolumeConfig(ctx, pvc.Spec.AccessModes, pvc.Spec.VolumeMode, pvName, pvcSize,
+	volumeConfig, err := getVolumeConfig(ctx, pvc.Spec.AccessModes, pvc.Spec.VolumeMode, pvName, pvcSize,
 		processPVCAnnotations(pvc, fsType), sc, requisiteTopology, preferredTopology)
+	if err != nil {
+		return nil, fmt.Errorf("invalid volume attributes for PVC %s; %v", pvc.Name, err)
+	}
 
 	// Check if we're cloning a PVC, and if so, do some further validation
 	if cloneSourcePVName, err := p.getCloneSourceInfo(ctx, pvc); err != nil {
@@ -238,6 +244,55 @@ func (p *Plugin) GetSnapshotConfig(volumeName, snapshotName string) (*storage.Sn
 	}, nil
 }
 
+// GetStorageClassForParameters accepts the parameters of a storage class as supplied by the
+// CSI provisioner and returns the name of the cached Trident-provisioned storage class with the
+// same parameters.  Trident storage classes are named after their Kubernetes counterparts.  If
+// several storage classes have the same parameters, the first one by name is returned so that
+// the result does not depend on the order of the cache.
+func (p *Plugin) GetStorageClassForParameters(ctx context.Context, parameters map[string]string) (string, error) {
+
+	matches := make([]string, 0)
+	for _, item := range p.scIndexer.List() {
+		sc, ok := item.(*k8sstoragev1.StorageClass)
+		if !ok || sc.Provisioner != csi.Provisioner {
+			continue
+		}
+		if storageClassParametersMatch(sc.Parameters, parameters) {
+			matches = append(matches, sc.Name)
+		}
+	}
+
+	if len(matches) == 0 {
+		return "", utils.NotFoundError("no storage class matches the specified parameters")
+	}
+
+	sort.Strings(matches)
+
+	Logc(ctx).WithFields(log.Fields{
+		"name":    matches[0],
+		"matches": matches,
+	}).Debug("Found storage class matching parameters.")
+
+	return matches[0], nil
+}
+
+// storageClassParametersMatch compares two sets of storage class parameters, ignoring any
+// parameters reserved for the Kubernetes CSI sidecars.
+func storageClassParametersMatch(scParameters, parameters map[string]string) bool {
+
+	filter := func(in map[string]string) map[string]string {
+		out := make(map[string]string)
+		for k, v := range in {
+			if !strings.HasPrefix(k, K8sCSIParameterPrefix) {
+				out[k] = v
+			}
+		}
+		return out
+	}
+
+	return reflect.DeepEqual(filter(scParameters), filter(parameters))
+}
+
 // RecordVolumeEvent accepts the name of a CSI volume (i.e. a PV name), finds the associated
 // PVC, and posts and event message on the PVC object with the K8S API server.
 func (p *Plugin) RecordVolumeEvent(ctx context.Context, name, eventType, reason, message string) {
@@ -294,7 +349,7 @@ func getVolumeConfig(
 	ctx context.Context, pvcAccessModes []v1.PersistentVolumeAccessMode, volumeMode *v1.PersistentVolumeMode,
 	name string, size resource.Quantity, annotations map[string]string, storageClass *k8sstoragev1.StorageClass,
 	requisiteTopology, preferredTopology []map[string]string,
-) *storage.VolumeConfig {
+) (*storage.VolumeConfig, error) {
 
 	var accessModes []config.AccessMode
 
@@ -321,6 +376,19 @@ func getVolumeConfig(
 		Logc(ctx).Warnf("unable to parse notManaged annotation into bool; %v", err)
 	}
 
+	mirrorDestination := false
+	if getAnnotation(annotations, AnnMirrorDestination) != "" {
+		if mirrorDestination, err = strconv.ParseBool(getAnnotation(annotations, AnnMirrorDestination)); err != nil {
+			Logc(ctx).Warnf("unable to parse mirrorDestination annotation into bool; %v", err)
+		}
+	}
+
+	luksEncryption, err := getLUKSEncryption(storageClass.Parameters[SCParameterLUKSEncryption],
+		getAnnotation(annotations, AnnLUKSEncryption))
+	if err != nil {
+		return nil, err
+	}
+
 	return &storage.VolumeConfig{
 		Name:                name,
 		Size:                fmt.Sprintf("%d", size.Value()),
@@ -330,6 +398,9 @@ func getVolumeConfig(
 		SnapshotDir:         getAnnotation(annotations, AnnSnapshotDir),
 		ExportPolicy:        getAnnotation(annotations, AnnExportPolicy),
 		UnixPermissions:     getAnnotation(annotations, AnnUnixPermissions),
+		QosPolicy:           getAnnotation(annotations, AnnQosPolicy),
+		AdaptiveQosPolicy:   getAnnotation(annotations, AnnAdaptiveQosPolicy),
+		TieringPolicy:       getAnnotation(annotations, AnnTieringPolicy),
 		StorageClass:        storageClass.Name,
 		BlockSize:           getAnnotation(annotations, AnnBlockSize),
 		FileSystem:          getAnnotation(annotations, AnnFileSystem),
@@ -339,10 +410,42 @@ func getVolumeConfig(
 		ImportOriginalName:  getAnnotation(annotations, AnnImportOriginalName),
 		ImportBackendUUID:   getAnnotation(annotations, AnnImportBackendUUID),
 		ImportNotManaged:    notManaged,
+		IsMirrorDestination: mirrorDestination,
 		MountOptions:        strings.Join(storageClass.MountOptions, ","),
+		LUKSEncryption:      luksEncryption,
 		RequisiteTopologies: requisiteTopology,
 		PreferredTopologies: preferredTopology,
+	}, nil
+}
+
+// getLUKSEncryption combines the luksEncryption storage class parameter with the PVC annotation of the same
+// name.  The annotation may request encryption that the storage class does not, but it may not disable
+// encryption that the storage class requires.
+func getLUKSEncryption(scEncryption, pvcEncryption string) (string, error) {
+
+	scEncrypt := false
+	if scEncryption != "" {
+		var err error
+		if scEncrypt, err = strconv.ParseBool(scEncryption); err != nil {
+			return "", fmt.Errorf("invalid value '%s' for storage class parameter %s", scEncryption,
+				SCParameterLUKSEncryption)
+		}
 	}
+
+	if pvcEncryption == "" {
+		return scEncryption, nil
+	}
+
+	pvcEncrypt, err := strconv.ParseBool(pvcEncryption)
+	if err != nil {
+		return "", fmt.Errorf("invalid value '%s' for annotation %s", pvcEncryption, AnnLUKSEncryption)
+	}
+	if scEncrypt && !pvcEncrypt {
+		return "", fmt.Errorf("annotation %s may not disable the LUKS encryption required by the storage class",
+			AnnLUKSEncryption)
+	}
+
+	return strconv.FormatBool(scEncrypt || pvcEncrypt), nil
 }
 
 // getAnnotation returns an annotation from a map, or an empty string if not found.
@@ -0,0 +1,97 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sstoragev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/netapp/trident/frontend/csi"
+	"github.com/netapp/trident/utils"
+)
+
+func TestGetStorageClassForParameters(t *testing.T) {
+
+	newStorageClass := func(name, provisioner string, parameters map[string]string) *k8sstoragev1.StorageClass {
+		return &k8sstoragev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: name},
+			Provisioner: provisioner,
+			Parameters:  parameters,
+		}
+	}
+
+	gold := map[string]string{"backendType": "ontap-nas", "media": "ssd"}
+	silver := map[string]string{"backendType": "ontap-nas", "media": "hdd"}
+
+	p := &Plugin{scIndexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})}
+	for _, sc := range []*k8sstoragev1.StorageClass{
+		newStorageClass("gold-b", csi.Provisioner, gold),
+		newStorageClass("gold-c", csi.Provisioner, gold),
+		newStorageClass("gold-a", csi.Provisioner, gold),
+		newStorageClass("aaa", "kubernetes.io/no-provisioner", gold),
+		newStorageClass("silver", csi.Provisioner, silver),
+	} {
+		assert.NoError(t, p.scIndexer.Add(sc))
+	}
+
+	tests := []struct {
+		name       string
+		parameters map[string]string
+		expected   string
+		notFound   bool
+	}{
+		{"single match", silver, "silver", false},
+		{"sidecar parameters ignored", map[string]string{
+			"backendType": "ontap-nas", "media": "hdd", K8sCSIParameterPrefix + "fstype": "ext4"}, "silver", false},
+		{"several matches", gold, "gold-a", false},
+		{"no match", map[string]string{"backendType": "ontap-san"}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		for i := 0; i < 5; i++ {
+			name, err := p.GetStorageClassForParameters(context.Background(), test.parameters)
+			assert.Equal(t, test.expected, name, test.name)
+			assert.Equal(t, test.notFound, utils.IsNotFoundError(err), test.name)
+		}
+	}
+}
+
+func TestGetLUKSEncryption(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		scEncryption  string
+		pvcEncryption string
+		expected      string
+		expectError   bool
+	}{
+		{"neither set", "", "", "", false},
+		{"storage class only", "true", "", "true", false},
+		{"annotation enables", "false", "true", "true", false},
+		{"annotation enables without storage class", "", "true", "true", false},
+		{"annotation agrees", "true", "true", "true", false},
+		{"annotation declines", "", "false", "false", false},
+		{"annotation disables", "true", "false", "", true},
+		{"invalid annotation", "", "yes please", "", true},
+		{"invalid storage class parameter", "maybe", "", "", true},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		luksEncryption, err := getLUKSEncryption(test.scEncryption, test.pvcEncryption)
+		if test.expectError {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, luksEncryption, test.name)
+	}
+}
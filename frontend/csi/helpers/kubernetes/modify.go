@@ -0,0 +1,103 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/netapp/trident/frontend/csi"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the event handlers that modify the attributes of CSI
+// Trident volumes when their PVC annotations change.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// modifiableAnnotations maps each PVC annotation that may be changed after its volume is created
+// to a function that sets the corresponding attribute of a volume modification.
+var modifiableAnnotations = map[string]func(*storage.VolumeModification, string){
+	AnnSnapshotPolicy:    func(m *storage.VolumeModification, value string) { m.SnapshotPolicy = value },
+	AnnSnapshotReserve:   func(m *storage.VolumeModification, value string) { m.SnapshotReserve = value },
+	AnnExportPolicy:      func(m *storage.VolumeModification, value string) { m.ExportPolicy = value },
+	AnnQosPolicy:         func(m *storage.VolumeModification, value string) { m.QosPolicy = value },
+	AnnAdaptiveQosPolicy: func(m *storage.VolumeModification, value string) { m.AdaptiveQosPolicy = value },
+	AnnTieringPolicy:     func(m *storage.VolumeModification, value string) { m.TieringPolicy = value },
+	AnnUnixPermissions:   func(m *storage.VolumeModification, value string) { m.UnixPermissions = value },
+}
+
+// getVolumeModification compares two sets of PVC annotations and returns a volume modification
+// containing any modifiable attributes that were set or changed, or nil if there are none.  Removing
+// an annotation does not change the corresponding volume attribute.
+func getVolumeModification(oldAnnotations, newAnnotations map[string]string) *storage.VolumeModification {
+
+	modification := &storage.VolumeModification{}
+	for annotation, setAttribute := range modifiableAnnotations {
+		value := getAnnotation(newAnnotations, annotation)
+		if value != "" && value != getAnnotation(oldAnnotations, annotation) {
+			setAttribute(modification, value)
+		}
+	}
+
+	if *modification == (storage.VolumeModification{}) {
+		return nil
+	}
+	return modification
+}
+
+// updatePVCModify is the update handler for the PVC watcher whose job is to detect changes
+// to the annotations of bound PVCs and apply them to the underlying volumes.
+func (p *Plugin) updatePVCModify(oldObj, newObj interface{}) {
+
+	ctx := GenerateRequestContext(nil, "", ContextSourceK8S)
+
+	// Ensure we got PVC objects
+	oldPVC, ok := oldObj.(*v1.PersistentVolumeClaim)
+	if !ok {
+		Logc(ctx).Errorf("K8S helper expected PVC; got %v", oldObj)
+		return
+	}
+	newPVC, ok := newObj.(*v1.PersistentVolumeClaim)
+	if !ok {
+		Logc(ctx).Errorf("K8S helper expected PVC; got %v", newObj)
+		return
+	}
+
+	// Verify there is work to be done
+	modification := getVolumeModification(oldPVC.Annotations, newPVC.Annotations)
+	if modification == nil {
+		return
+	}
+
+	// Verify the PVC is Bound
+	if newPVC.Status.Phase != v1.ClaimBound {
+		return
+	}
+
+	// Verify the PVC is managed by Trident (include legacy volumes)
+	pvcProvisioner := getPVCProvisioner(newPVC)
+	if pvcProvisioner != csi.Provisioner && pvcProvisioner != csi.LegacyProvisioner {
+		return
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"PVC":          newPVC.Name,
+		"PV":           newPVC.Spec.VolumeName,
+		"modification": modification,
+	}).Debug("K8S helper detected a PVC whose volume attributes changed.")
+
+	if _, err := p.orchestrator.ModifyVolume(ctx, newPVC.Spec.VolumeName, modification); err != nil {
+		message := fmt.Sprintf("failed to modify the volume: %v", err)
+		p.eventRecorder.Event(newPVC, v1.EventTypeWarning, "ModifyFailed", message)
+		Logc(ctx).WithFields(log.Fields{"PVC": newPVC.Name}).Errorf("K8S helper %v", message)
+		return
+	}
+
+	p.eventRecorder.Event(newPVC, v1.EventTypeNormal, "ModifySuccess", "modified the volume.")
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/storage"
+)
+
+func TestGetVolumeModification(t *testing.T) {
+
+	tests := map[string]struct {
+		oldAnnotations map[string]string
+		newAnnotations map[string]string
+		expected       *storage.VolumeModification
+	}{
+		"No annotations": {
+			oldAnnotations: nil,
+			newAnnotations: nil,
+			expected:       nil,
+		},
+		"Unchanged annotations": {
+			oldAnnotations: map[string]string{AnnSnapshotPolicy: "default"},
+			newAnnotations: map[string]string{AnnSnapshotPolicy: "default"},
+			expected:       nil,
+		},
+		"Unmodifiable annotation changed": {
+			oldAnnotations: map[string]string{AnnFileSystem: "ext4"},
+			newAnnotations: map[string]string{AnnFileSystem: "xfs"},
+			expected:       nil,
+		},
+		"Removed annotation": {
+			oldAnnotations: map[string]string{AnnExportPolicy: "default"},
+			newAnnotations: map[string]string{},
+			expected:       nil,
+		},
+		"Changed annotations": {
+			oldAnnotations: map[string]string{AnnSnapshotPolicy: "none", AnnExportPolicy: "default"},
+			newAnnotations: map[string]string{
+				AnnSnapshotPolicy: "default",
+				AnnExportPolicy:   "default",
+				AnnQosPolicy:      "gold",
+				AnnTieringPolicy:  "auto",
+			},
+			expected: &storage.VolumeModification{
+				SnapshotPolicy: "default",
+				QosPolicy:      "gold",
+				TieringPolicy:  "auto",
+			},
+		},
+	}
+	for testName, test := range tests {
+		t.Logf("Running test case '%s'", testName)
+
+		assert.Equal(t, test.expected, getVolumeModification(test.oldAnnotations, test.newAnnotations))
+	}
+}
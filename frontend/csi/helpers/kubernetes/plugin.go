@@ -207,6 +207,13 @@ func newKubernetesPlugin(
 		},
 	)
 
+	// Add handler for modifying volumes when PVC annotations change
+	p.pvcController.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: p.updatePVCModify,
+		},
+	)
+
 	if !p.SupportsFeature(ctx, csi.ExpandCSIVolumes) {
 		p.pvcController.AddEventHandlerWithResyncPeriod(
 			cache.ResourceEventHandlerFuncs{
@@ -359,6 +366,7 @@ func (p *Plugin) Activate() error {
 	go p.scController.Run(p.scControllerStopChan)
 	go p.nodeController.Run(p.nodeControllerStopChan)
 	go p.reconcileNodes(ctx)
+	go p.backfillPublishedNodes(ctx)
 
 	// Configure telemetry
 	config.OrchestratorTelemetry.Platform = string(config.PlatformKubernetes)
@@ -439,6 +447,90 @@ func (p *Plugin) reconcileNodes(ctx context.Context) {
 	Logc(ctx).Debug("Node reconciliation complete.")
 }
 
+// backfillPublishedNodes records the nodes to which Trident volumes are attached, so that the published
+// nodes of volumes attached before Trident tracked them are known.  It is retried for a while, since the
+// orchestrator may not be able to accept the update yet.
+func (p *Plugin) backfillPublishedNodes(ctx context.Context) {
+
+	backfill := func() error {
+		publishedNodes, err := p.getAttachedNodes(ctx)
+		if err != nil {
+			return err
+		}
+		return p.orchestrator.BackfillPublishedNodes(ctx, publishedNodes)
+	}
+	backfillNotify := func(err error, duration time.Duration) {
+		Logc(ctx).WithFields(log.Fields{
+			"increment": duration,
+			"error":     err,
+		}).Debug("Could not backfill published nodes, waiting.")
+	}
+	backfillBackoff := backoff.NewExponentialBackOff()
+	backfillBackoff.MaxElapsedTime = PublishedNodesBackfillMaxElapsedTime
+
+	if err := backoff.RetryNotify(backfill, backfillBackoff, backfillNotify); err != nil {
+		Logc(ctx).WithError(err).Error("Could not backfill published nodes of volumes.")
+	}
+}
+
+// getAttachedNodes returns the names of the nodes to which each Trident volume is attached, as recorded
+// in the cluster's VolumeAttachments.  Attachments that are still being made or removed are included,
+// since the volume may be in use on those nodes.
+func (p *Plugin) getAttachedNodes(ctx context.Context) (map[string][]string, error) {
+
+	attachments, err := p.listVolumeAttachments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attachedNodes := make(map[string][]string)
+	for _, attachment := range attachments {
+		pvName := *attachment.Spec.Source.PersistentVolumeName
+		if !utils.SliceContainsString(attachedNodes[pvName], attachment.Spec.NodeName) {
+			attachedNodes[pvName] = append(attachedNodes[pvName], attachment.Spec.NodeName)
+		}
+	}
+	return attachedNodes, nil
+}
+
+// GetNodeAttachedVolumes returns the names of the Trident volumes attached to a node, as recorded in
+// the cluster's VolumeAttachments.  Attachments that are still being made or removed are included,
+// since the volume may be in use on the node.
+func (p *Plugin) GetNodeAttachedVolumes(ctx context.Context, nodeName string) ([]string, error) {
+
+	attachments, err := p.listVolumeAttachments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeNames := make([]string, 0)
+	for _, attachment := range attachments {
+		pvName := *attachment.Spec.Source.PersistentVolumeName
+		if attachment.Spec.NodeName == nodeName && !utils.SliceContainsString(volumeNames, pvName) {
+			volumeNames = append(volumeNames, pvName)
+		}
+	}
+	return volumeNames, nil
+}
+
+// listVolumeAttachments returns the cluster's VolumeAttachments of persistent volumes provisioned by Trident.
+func (p *Plugin) listVolumeAttachments(ctx context.Context) ([]k8sstoragev1.VolumeAttachment, error) {
+
+	attachments, err := p.kubeClient.StorageV1().VolumeAttachments().List(ctx, listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error reading volume attachments; %v", err)
+	}
+
+	tridentAttachments := make([]k8sstoragev1.VolumeAttachment, 0)
+	for _, attachment := range attachments.Items {
+		pvName := attachment.Spec.Source.PersistentVolumeName
+		if attachment.Spec.Attacher == csi.Provisioner && pvName != nil && *pvName != "" {
+			tridentAttachments = append(tridentAttachments, attachment)
+		}
+	}
+	return tridentAttachments, nil
+}
+
 // addPVC is the add handler for the PVC watcher.
 func (p *Plugin) addPVC(obj interface{}) {
 	ctx := GenerateRequestContext(nil, "", ContextSourceK8S)
@@ -776,10 +868,19 @@ func (p *Plugin) processAddedStorageClass(ctx context.Context, sc *k8sstoragev1.
 
 	// Populate storage class config attributes and backend storage pools
 	for k, v := range sc.Parameters {
+
+		// Ignore Kubernetes-defined storage class parameters handled by CSI
+		if strings.HasPrefix(k, K8sCSIParameterPrefix) {
+			continue
+		}
+
 		switch k {
 		case K8sFsType:
 			// Ignore Kubernetes-defined storage class parameters handled by CSI
 
+		case SCParameterLUKSEncryption:
+			// Ignore volume options, which are read from the storage class when each volume is created
+
 		case storageattribute.RequiredStorage, storageattribute.AdditionalStoragePools:
 			// format:  additionalStoragePools: "backend1:pool1,pool2;backend2:pool1"
 			additionalPools, err := storageattribute.CreateBackendStoragePoolsMapFromEncodedString(v)
@@ -819,6 +920,10 @@ func (p *Plugin) processAddedStorageClass(ctx context.Context, sc *k8sstoragev1.
 			}
 			scConfig.Pools = pools
 
+		case storageattribute.PlacementPolicy, storageattribute.PlacementPackThreshold,
+			storageattribute.PlacementLabelWeights:
+			// Handled below, since these parameters must be considered together
+
 		default:
 			// format:  attribute: "value"
 			req, err := storageattribute.CreateAttributeRequestFromAttributeValue(k, v)
@@ -835,6 +940,19 @@ func (p *Plugin) processAddedStorageClass(ctx context.Context, sc *k8sstoragev1.
 		}
 	}
 
+	// format:  placementPolicy: "pack", placementPackThreshold: "85"
+	placementPolicy, err := storageclass.CreatePlacementPolicyConfigFromParameters(sc.Parameters)
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"name":        sc.Name,
+			"provisioner": sc.Provisioner,
+			"parameters":  sc.Parameters,
+			"error":       err,
+		}).Error("K8S helper could not process the storage class placement policy")
+		return
+	}
+	scConfig.PlacementPolicy = placementPolicy
+
 	// Add the storage class
 	if _, err := p.orchestrator.AddStorageClass(ctx, scConfig); err != nil {
 		Logc(ctx).WithFields(log.Fields{
@@ -0,0 +1,52 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	k8sstoragev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/netapp/trident/frontend/csi"
+)
+
+func TestVolumeAttachments(t *testing.T) {
+
+	newAttachment := func(name, attacher, nodeName string, pvName *string) *k8sstoragev1.VolumeAttachment {
+		return &k8sstoragev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: k8sstoragev1.VolumeAttachmentSpec{
+				Attacher: attacher,
+				NodeName: nodeName,
+				Source:   k8sstoragev1.VolumeAttachmentSource{PersistentVolumeName: pvName},
+			},
+		}
+	}
+
+	pv1, pv2, pv3 := "pv1", "pv2", "pv3"
+
+	kubeClient := fake.NewSimpleClientset(
+		newAttachment("va1", csi.Provisioner, "node1", &pv1),
+		newAttachment("va2", csi.Provisioner, "node2", &pv1),
+		newAttachment("va3", csi.Provisioner, "node1", &pv2),
+		newAttachment("va4", "other.csi.example.com", "node1", &pv3),
+		newAttachment("va5", csi.Provisioner, "node1", nil),
+	)
+	p := &Plugin{kubeClient: kubeClient}
+
+	attachedNodes, err := p.getAttachedNodes(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, attachedNodes, 2)
+	assert.ElementsMatch(t, []string{"node1", "node2"}, attachedNodes["pv1"])
+	assert.ElementsMatch(t, []string{"node1"}, attachedNodes["pv2"])
+
+	volumeNames, err := p.GetNodeAttachedVolumes(context.Background(), "node1")
+
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"pv1", "pv2"}, volumeNames)
+}
@@ -193,10 +193,12 @@ func (p *Plugin) UpgradeVolume(
 		OwnedPodsForPVC: ownedPodsForPVC,
 	}
 
+	// Journal the upgrade's progress, so that an interrupted upgrade is resumed rather than rolled back
 	volTxn := &storage.VolumeTransaction{
 		Config:          volume.Config,
 		PVUpgradeConfig: upgradeConfig,
 		Op:              storage.UpgradeVolume,
+		Operation:       storage.NewOperationRecord(storage.OperationPhaseDeletingPV),
 	}
 	txnErr := p.orchestrator.AddVolumeTransaction(ctx, volTxn)
 	if utils.IsFoundError(txnErr) {
@@ -249,56 +251,160 @@ func (p *Plugin) UpgradeVolume(
 			"failure-e57c5f01-87c1-46d7-a09b-366527d31599")
 	}
 
-	// Delete the PV along with any finalizers
-	if err = p.deletePVForUpgrade(ctx, pv); err != nil {
-		message := "PV upgrade: could not delete the PV"
+	if err = p.runPVUpgradePhases(ctx, volTxn, volume); err != nil {
+		return nil, err
+	}
+
+	// Return volume to caller
+	return volume, nil
+}
+
+// runPVUpgradePhases carries out the remaining phases of a PV upgrade, journaling each phase in the
+// upgrade's transaction once it is reached, so that an interrupted upgrade may be resumed from there.
+// The transaction is deleted once the upgrade is done.
+func (p *Plugin) runPVUpgradePhases(
+	ctx context.Context, volTxn *storage.VolumeTransaction, volume *storage.VolumeExternal,
+) error {
+
+	// Work on a copy, so that the orchestrator never holds a record that is still being changed
+	record := *volTxn.Operation
+	txn := *volTxn
+	txn.Operation = &record
+
+	for {
+		var nextPhase string
+		var err error
+
+		switch txn.Operation.Phase {
+		case storage.OperationPhaseDeletingPV:
+			nextPhase, err = p.deleteLegacyPVPhase(ctx, &txn)
+		case storage.OperationPhaseDeletingPods:
+			nextPhase, err = p.deleteOwnedPodsPhase(ctx, &txn)
+		case storage.OperationPhaseCreatingPV:
+			nextPhase, err = p.createCSIPVPhase(ctx, &txn, volume)
+		default:
+			err = fmt.Errorf("PV upgrade: unknown upgrade phase %s", txn.Operation.Phase)
+		}
+		if err != nil {
+			return err
+		}
+		if nextPhase == storage.OperationPhaseDone {
+			break
+		}
+
+		saved := *txn.Operation
+		saved.Phase = nextPhase
+		savedTxn := txn
+		savedTxn.Operation = &saved
+		if err = p.orchestrator.UpdateVolumeTransaction(ctx, &savedTxn); err != nil {
+			return fmt.Errorf("PV upgrade: unable to journal upgrade phase %s; %v", nextPhase, err)
+		}
+		txn.Operation.Phase = nextPhase
+
 		Logc(ctx).WithFields(log.Fields{
-			"PV":    pv.Name,
+			"volume": txn.Config.Name,
+			"phase":  nextPhase,
+		}).Debug("PV upgrade: journaled upgrade phase.")
+	}
+
+	if err := p.orchestrator.DeleteVolumeTransaction(ctx, &txn); err != nil {
+		return fmt.Errorf("PV upgrade: unable to delete upgrade transaction; %v", err)
+	}
+	return nil
+}
+
+// deleteLegacyPVPhase deletes the legacy PV being upgraded, unless it was deleted before the upgrade
+// was interrupted, and waits for its PVC to become Lost or Pending.
+func (p *Plugin) deleteLegacyPVPhase(ctx context.Context, volTxn *storage.VolumeTransaction) (string, error) {
+
+	pvName := volTxn.PVUpgradeConfig.PVConfig.Name
+	pvc := volTxn.PVUpgradeConfig.PVCConfig
+	pvcDisplayName := pvc.Namespace + "/" + pvc.Name
+
+	pvExists, err := p.isPVInCache(ctx, pvName)
+	if err != nil {
+		message := "PV upgrade: could not check cache for the PV"
+		Logc(ctx).WithFields(log.Fields{
+			"PV":    pvName,
 			"error": err,
 		}).Errorf("%s.", message)
-		return nil, fmt.Errorf("%s: %v", message, err)
+		return "", fmt.Errorf("%s: %v", message, err)
+	}
+
+	if pvExists {
+		pv, err := p.getCachedPVByName(ctx, pvName)
+		if err != nil {
+			message := "PV upgrade: could not find the PV to upgrade"
+			Logc(ctx).WithFields(log.Fields{
+				"PV":    pvName,
+				"error": err,
+			}).Errorf("%s.", message)
+			return "", fmt.Errorf("%s: %v", message, err)
+		}
+
+		// Delete the PV along with any finalizers
+		if err = p.deletePVForUpgrade(ctx, pv); err != nil {
+			message := "PV upgrade: could not delete the PV"
+			Logc(ctx).WithFields(log.Fields{
+				"PV":    pvName,
+				"error": err,
+			}).Errorf("%s.", message)
+			return "", fmt.Errorf("%s: %v", message, err)
+		}
+		Logc(ctx).WithField("PV", pvName).Infof("PV upgrade: PV deleted.")
 	}
-	Logc(ctx).WithField("PV", pv.Name).Infof("PV upgrade: PV deleted.")
 
 	// Wait for PVC to become Lost or Pending
 	lostOrPending := []v1.PersistentVolumeClaimPhase{v1.ClaimLost, v1.ClaimPending}
-	lostPVC, err := p.waitForPVCPhase(ctx, pvc, lostOrPending, PVDeleteWaitPeriod)
-	if err != nil {
+	if _, err = p.waitForPVCPhase(ctx, pvc, lostOrPending, PVDeleteWaitPeriod); err != nil {
 		message := "PV upgrade: PVC did not reach the Lost or Pending state"
 		Logc(ctx).WithFields(log.Fields{
-			"PV":    pv.Name,
+			"PV":    pvName,
 			"PVC":   pvcDisplayName,
 			"error": err,
 		}).Errorf("%s.", message)
-		return nil, fmt.Errorf("%s: %v", message, err)
+		return "", fmt.Errorf("%s: %v", message, err)
 	}
 	Logc(ctx).WithFields(log.Fields{
-		"PV":  pv.Name,
+		"PV":  pvName,
 		"PVC": pvcDisplayName,
 	}).Infof("PV upgrade: PVC reached the Lost or Pending state.")
 
 	// Trigger failure based on PVC name for testing rollback
 	if pvc.Name == "failure-65ca482e-d013-454f-bb4f-5c340fb835ac" {
-		return nil, fmt.Errorf("PV upgrade: post-pv delete error triggered by PVC name " +
+		return "", fmt.Errorf("PV upgrade: post-pv delete error triggered by PVC name " +
 			"failure-65ca482e-d013-454f-bb4f-5c340fb835ac")
 	}
 
+	return storage.OperationPhaseDeletingPods, nil
+}
+
+// deleteOwnedPodsPhase deletes the owned pods that were using the PV being upgraded, and waits for
+// them to disappear or reappear in a non-Running state.  Pods already gone are skipped.
+func (p *Plugin) deleteOwnedPodsPhase(ctx context.Context, volTxn *storage.VolumeTransaction) (string, error) {
+
+	pvName := volTxn.PVUpgradeConfig.PVConfig.Name
+	pvc := volTxn.PVUpgradeConfig.PVCConfig
+	namespace := pvc.Namespace
+	pvcDisplayName := namespace + "/" + pvc.Name
+
 	// Delete all owned pods that were using the PV
-	for _, podName := range ownedPodsForPVC {
+	for _, podName := range volTxn.PVUpgradeConfig.OwnedPodsForPVC {
 
 		// Delete pod
-		if err = p.kubeClient.CoreV1().Pods(namespace).Delete(ctx, podName, deleteOpts); err != nil {
+		err := p.kubeClient.CoreV1().Pods(namespace).Delete(ctx, podName, deleteOpts)
+		if err != nil && !apierrors.IsNotFound(err) {
 			message := "PV upgrade: could not delete a pod using the PV"
 			Logc(ctx).WithFields(log.Fields{
-				"PV":    pv.Name,
+				"PV":    pvName,
 				"PVC":   pvcDisplayName,
 				"pod":   podName,
 				"error": err,
 			}).Errorf("%s.", message)
-			return nil, fmt.Errorf("%s: %v", message, err)
+			return "", fmt.Errorf("%s: %v", message, err)
 		} else {
 			Logc(ctx).WithFields(log.Fields{
-				"PV":  pv.Name,
+				"PV":  pvName,
 				"PVC": pvcDisplayName,
 				"pod": podName,
 			}).Infof("PV upgrade: Owned pod deleted.")
@@ -306,21 +412,21 @@ func (p *Plugin) UpgradeVolume(
 	}
 
 	// Wait for all deleted pods to disappear (or reappear in a non-Running state)
-	for _, podName := range ownedPodsForPVC {
+	for _, podName := range volTxn.PVUpgradeConfig.OwnedPodsForPVC {
 
 		// Wait for pod to disappear or become pending
-		if _, err = p.waitForDeletedOrNonRunningPod(ctx, podName, namespace, PodDeleteWaitPeriod); err != nil {
+		if _, err := p.waitForDeletedOrNonRunningPod(ctx, podName, namespace, PodDeleteWaitPeriod); err != nil {
 			message := "PV upgrade: unexpected pod status"
 			Logc(ctx).WithFields(log.Fields{
-				"PV":    pv.Name,
+				"PV":    pvName,
 				"PVC":   pvcDisplayName,
 				"pod":   podName,
 				"error": err,
 			}).Errorf("%s.", message)
-			return nil, fmt.Errorf("%s: %v", message, err)
+			return "", fmt.Errorf("%s: %v", message, err)
 		} else {
 			Logc(ctx).WithFields(log.Fields{
-				"PV":  pv.Name,
+				"PV":  pvName,
 				"PVC": pvcDisplayName,
 				"pod": podName,
 			}).Info("PV upgrade: Pod deleted or non-Running.")
@@ -329,45 +435,82 @@ func (p *Plugin) UpgradeVolume(
 
 	// Trigger failure based on PVC name for testing rollback
 	if pvc.Name == "failure-f900bd0f-fd81-453d-97bc-03148e8a4178" {
-		return nil, fmt.Errorf("PV upgrade: post-pod delete error triggered by PVC name " +
+		return "", fmt.Errorf("PV upgrade: post-pod delete error triggered by PVC name " +
 			"failure-f900bd0f-fd81-453d-97bc-03148e8a4178")
 	}
 
+	return storage.OperationPhaseCreatingPV, nil
+}
+
+// createCSIPVPhase creates the CSI version of the PV being upgraded, unless it was created before
+// the upgrade was interrupted, and waits for its PVC to become Bound.
+func (p *Plugin) createCSIPVPhase(
+	ctx context.Context, volTxn *storage.VolumeTransaction, volume *storage.VolumeExternal,
+) (string, error) {
+
+	pv := volTxn.PVUpgradeConfig.PVConfig
+	namespace := volTxn.PVUpgradeConfig.PVCConfig.Namespace
+	pvcDisplayName := namespace + "/" + volTxn.PVUpgradeConfig.PVCConfig.Name
+
 	// TODO: Do controller stuff (igroups, etc.) (?)
 
-	// Remove bind-completed annotation from PVC
-	unboundLostPVC, err := p.removePVCBindCompletedAnnotation(ctx, lostPVC)
+	pvc, err := p.getCachedPVCByName(ctx, volTxn.PVUpgradeConfig.PVCConfig.Name, namespace)
 	if err != nil {
-		message := "PV upgrade: could not remove bind-completed annotation from PVC"
+		message := "PV upgrade: could not find the PVC bound to the PV"
 		Logc(ctx).WithFields(log.Fields{
+			"PV":    pv.Name,
 			"PVC":   pvcDisplayName,
 			"error": err,
 		}).Errorf("%s.", message)
-		return nil, fmt.Errorf("%s: %v", message, err)
-	}
-	Logc(ctx).WithField("PVC", pvc.Name).Info("PV upgrade: removed bind-completed annotation from PVC.")
-
-	// Trigger failure based on PVC name for testing rollback
-	if pvc.Name == "failure-e41d3d81-1771-47b4-bec3-2a949d0049bd" {
-		return nil, fmt.Errorf("PV upgrade: post-pvc update error triggered by PVC name " +
-			"failure-e41d3d81-1771-47b4-bec3-2a949d0049bd")
+		return "", fmt.Errorf("%s: %v", message, err)
 	}
 
-	// Create new PV
-	csiPV, err := p.createCSIPVFromPV(ctx, pv, volume)
+	csiPVExists, err := p.isPVInCache(ctx, pv.Name)
 	if err != nil {
-		message := "PV upgrade: could not create the CSI version of PV being upgraded"
+		message := "PV upgrade: could not check cache for the CSI version of PV being upgraded"
 		Logc(ctx).WithFields(log.Fields{
 			"PV":    pv.Name,
 			"error": err,
-		}).Errorf("PV upgrade: %s.", message)
-		return nil, fmt.Errorf("%s: %v", message, err)
+		}).Errorf("%s.", message)
+		return "", fmt.Errorf("%s: %v", message, err)
+	}
+
+	if csiPVExists {
+		Logc(ctx).WithField("PV", pv.Name).Info("PV upgrade: CSI version of PV already created.")
+	} else {
+		// Remove bind-completed annotation from PVC
+		if pvc, err = p.removePVCBindCompletedAnnotation(ctx, pvc); err != nil {
+			message := "PV upgrade: could not remove bind-completed annotation from PVC"
+			Logc(ctx).WithFields(log.Fields{
+				"PVC":   pvcDisplayName,
+				"error": err,
+			}).Errorf("%s.", message)
+			return "", fmt.Errorf("%s: %v", message, err)
+		}
+		Logc(ctx).WithField("PVC", pvc.Name).Info("PV upgrade: removed bind-completed annotation from PVC.")
+
+		// Trigger failure based on PVC name for testing rollback
+		if pvc.Name == "failure-e41d3d81-1771-47b4-bec3-2a949d0049bd" {
+			return "", fmt.Errorf("PV upgrade: post-pvc update error triggered by PVC name " +
+				"failure-e41d3d81-1771-47b4-bec3-2a949d0049bd")
+		}
+
+		// Create new PV
+		csiPV, err := p.createCSIPVFromPV(ctx, pv, volume)
+		if err != nil {
+			message := "PV upgrade: could not create the CSI version of PV being upgraded"
+			Logc(ctx).WithFields(log.Fields{
+				"PV":    pv.Name,
+				"error": err,
+			}).Errorf("PV upgrade: %s.", message)
+			return "", fmt.Errorf("%s: %v", message, err)
+		}
+		Logc(ctx).WithField("PV", csiPV.Name).Info("PV upgrade: created CSI version of PV.")
 	}
-	Logc(ctx).WithField("PV", csiPV.Name).Info("PV upgrade: created CSI version of PV.")
 
 	// Wait for PVC to become Bound
 	bound := []v1.PersistentVolumeClaimPhase{v1.ClaimBound}
-	boundPVC, err := p.waitForPVCPhase(ctx, unboundLostPVC, bound, PVDeleteWaitPeriod)
+	boundPVC, err := p.waitForPVCPhase(ctx, pvc, bound, PVDeleteWaitPeriod)
 	if err != nil {
 		message := "PV upgrade: PVC did not reach the Bound state"
 		Logc(ctx).WithFields(log.Fields{
@@ -375,26 +518,46 @@ func (p *Plugin) UpgradeVolume(
 			"PVC":   pvcDisplayName,
 			"error": err,
 		}).Errorf("%s.", message)
-		return nil, fmt.Errorf("%s: %v", message, err)
+		return "", fmt.Errorf("%s: %v", message, err)
 	} else if boundPVC != nil {
 		Logc(ctx).WithFields(log.Fields{
-			"PV":  csiPV.Name,
+			"PV":  pv.Name,
 			"PVC": pvcDisplayName,
 		}).Infof("PV upgrade: PVC bound.")
 	}
 
 	// Trigger failure based on PVC name for testing rollback
 	if pvc.Name == "failure-f26f23e5-4895-41cc-b983-6fe99d105841" {
-		return nil, fmt.Errorf("PV upgrade: post-csi pv create error triggered by PVC name " +
+		return "", fmt.Errorf("PV upgrade: post-csi pv create error triggered by PVC name " +
 			"failure-f26f23e5-4895-41cc-b983-6fe99d105841")
 	}
 
-	if err = p.orchestrator.DeleteVolumeTransaction(ctx, volTxn); err != nil {
-		return nil, fmt.Errorf("PV upgrade: unable to delete upgrade transaction; %v", err)
+	return storage.OperationPhaseDone, nil
+}
+
+// resumePVUpgrade carries out the remaining phases of a PV upgrade that was interrupted, and then
+// returns its volume to the online state.
+func (p *Plugin) resumePVUpgrade(ctx context.Context, volTxn *storage.VolumeTransaction) error {
+
+	volume, err := p.orchestrator.GetVolume(ctx, volTxn.Config.Name)
+	if err != nil {
+		return fmt.Errorf("PV upgrade: could not find the volume to upgrade; %v", err)
 	}
 
-	// Return volume to caller
-	return volume, nil
+	Logc(ctx).WithFields(log.Fields{
+		"volume": volume.Config.Name,
+		"phase":  volTxn.Operation.Phase,
+	}).Info("PV upgrade: resuming interrupted upgrade.")
+
+	if err = p.runPVUpgradePhases(ctx, volTxn, volume); err != nil {
+		return err
+	}
+
+	if err = p.orchestrator.SetVolumeState(ctx, volume.Config.Name, storage.VolumeStateOnline); err != nil {
+		return fmt.Errorf("PV upgrade: error setting volume to online state; %v", err)
+	}
+	Logc(ctx).WithField("volume", volume.Config.Name).Info("PV upgrade: resumed upgrade completed.")
+	return nil
 }
 
 func (p *Plugin) rollBackPVUpgrade(ctx context.Context, volTxn *storage.VolumeTransaction) error {
@@ -952,7 +1115,7 @@ func (p *Plugin) handleFailedPVUpgrades(ctx context.Context) error {
 		return fmt.Errorf("could not list known volumes; %v", err)
 	}
 	for _, volume := range volumes {
-		// If the volume is upgrading, we need to clean up the transaction
+		// If the volume is upgrading, we need to finish or clean up the transaction
 		if volume.State == storage.VolumeStateUpgrading {
 			volTxn := &storage.VolumeTransaction{
 				Config: volume.Config,
@@ -961,7 +1124,23 @@ func (p *Plugin) handleFailedPVUpgrades(ctx context.Context) error {
 			if err != nil {
 				return fmt.Errorf("could not get volume upgrade transaction; %v", err)
 			}
-			if volTxn.Op == storage.UpgradeVolume {
+			if volTxn != nil && volTxn.Op == storage.UpgradeVolume {
+
+				// Upgrades that journaled their progress are resumed from the phase they reached,
+				// and rolled back only if they cannot be completed
+				if volTxn.Operation != nil {
+					if err = p.resumePVUpgrade(ctx, volTxn); err == nil {
+						continue
+					}
+					Logc(ctx).WithField("volume", volume.Config.Name).WithError(err).Warning(
+						"PV upgrade: could not resume the upgrade; rolling back changes.")
+					if volTxn, err = p.orchestrator.GetVolumeTransaction(ctx, volTxn); err != nil {
+						return fmt.Errorf("could not get volume upgrade transaction; %v", err)
+					} else if volTxn == nil {
+						continue
+					}
+				}
+
 				if err = p.rollBackPVUpgrade(ctx, volTxn); err != nil {
 					return fmt.Errorf("error rolling back PV upgrade; %v", err)
 				}
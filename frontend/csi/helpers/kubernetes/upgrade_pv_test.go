@@ -0,0 +1,123 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
+	"github.com/netapp/trident/frontend/csi"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+)
+
+func TestResumePVUpgrade(t *testing.T) {
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		phase       string
+		csiPVCached bool
+	}{
+		{"DeletingPods", storage.OperationPhaseDeletingPods, false},
+		{"CreatingPVNotCreated", storage.OperationPhaseCreatingPV, false},
+		{"CreatingPVAlreadyCreated", storage.OperationPhaseCreatingPV, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			orchestrator := core.NewMockOrchestrator()
+			orchestrator.AddMockONTAPNFSBackend(ctx, "nfs", "127.0.0.1")
+			_, err := orchestrator.AddStorageClass(ctx, &storageclass.Config{Name: "gold"})
+			assert.NoError(t, err)
+
+			volConfig := &storage.VolumeConfig{
+				Name:         "pvc-1234",
+				Size:         "1Gi",
+				Protocol:     config.File,
+				StorageClass: "gold",
+			}
+			_, err = orchestrator.AddVolume(ctx, volConfig)
+			assert.NoError(t, err)
+			assert.NoError(t, orchestrator.SetVolumeState(ctx, volConfig.Name, storage.VolumeStateUpgrading))
+
+			pvc := &v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "claim1",
+					UID:         "claim1-uid",
+					Namespace:   "default",
+					Annotations: map[string]string{AnnBindCompleted: "yes"},
+				},
+				Spec:   v1.PersistentVolumeClaimSpec{VolumeName: volConfig.Name},
+				Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+			}
+			legacyPV := &v1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: volConfig.Name, UID: "pv-uid"},
+				Spec: v1.PersistentVolumeSpec{
+					PersistentVolumeSource: v1.PersistentVolumeSource{
+						NFS: &v1.NFSVolumeSource{Server: "127.0.0.1", Path: "/pvc_1234"},
+					},
+				},
+			}
+
+			p := &Plugin{
+				orchestrator: orchestrator,
+				kubeClient:   fake.NewSimpleClientset(pvc),
+				pvIndexer:    cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{uidIndex: MetaUIDKeyFunc}),
+				pvcIndexer:   cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{uidIndex: MetaUIDKeyFunc}),
+			}
+			assert.NoError(t, p.pvcIndexer.Add(pvc))
+			if test.csiPVCached {
+				csiPV := legacyPV.DeepCopy()
+				csiPV.Spec.NFS = nil
+				csiPV.Spec.CSI = &v1.CSIPersistentVolumeSource{Driver: csi.Provisioner, VolumeHandle: volConfig.Name}
+				assert.NoError(t, p.pvIndexer.Add(csiPV))
+			}
+
+			volTxn := &storage.VolumeTransaction{
+				Config: volConfig,
+				PVUpgradeConfig: &storage.PVUpgradeConfig{
+					PVCConfig:       pvc,
+					PVConfig:        legacyPV,
+					OwnedPodsForPVC: []string{"pod1"},
+				},
+				Op:        storage.UpgradeVolume,
+				Operation: storage.NewOperationRecord(test.phase),
+			}
+			assert.NoError(t, orchestrator.AddVolumeTransaction(ctx, volTxn))
+
+			assert.NoError(t, p.handleFailedPVUpgrades(ctx))
+
+			// The upgrade is complete, so its transaction is gone and its volume is back online
+			remainingTxn, err := orchestrator.GetVolumeTransaction(ctx, volTxn)
+			assert.NoError(t, err)
+			assert.Nil(t, remainingTxn)
+
+			volume, err := orchestrator.GetVolume(ctx, volConfig.Name)
+			if assert.NoError(t, err) {
+				assert.Equal(t, storage.VolumeStateOnline, volume.State)
+			}
+
+			// The CSI version of the PV is only created if it wasn't before the upgrade was interrupted
+			pv, err := p.kubeClient.CoreV1().PersistentVolumes().Get(ctx, volConfig.Name, getOpts)
+			if test.csiPVCached {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) {
+				assert.Nil(t, pv.Spec.NFS)
+				if assert.NotNil(t, pv.Spec.CSI) {
+					assert.Equal(t, csi.Provisioner, pv.Spec.CSI.Driver)
+				}
+			}
+		})
+	}
+}
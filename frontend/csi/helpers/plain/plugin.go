@@ -15,6 +15,7 @@ import (
 	"github.com/netapp/trident/frontend/csi/helpers"
 	. "github.com/netapp/trident/logger"
 	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
 )
 
 type Plugin struct {
@@ -98,6 +99,19 @@ func (p *Plugin) GetSnapshotConfig(volumeName, snapshotName string) (*storage.Sn
 	}, nil
 }
 
+// GetStorageClassForParameters accepts the parameters of a storage class as supplied by the
+// CSI provisioner and returns the name of the matching storage class, registering a new one
+// if necessary.
+func (p *Plugin) GetStorageClassForParameters(ctx context.Context, parameters map[string]string) (string, error) {
+
+	scConfig, err := frontendcommon.GetStorageClass(ctx, parameters, p.orchestrator)
+	if err != nil {
+		return "", err
+	}
+
+	return scConfig.Name, nil
+}
+
 func (p *Plugin) GetNodeTopologyLabels(ctx context.Context, nodeName string) (map[string]string, error) {
 	return map[string]string{}, nil
 }
@@ -126,6 +140,12 @@ func (p *Plugin) RecordNodeEvent(ctx context.Context, name, eventType, reason, m
 	}).Debug("Node event.")
 }
 
+// GetNodeAttachedVolumes returns an unsupported error, since plain CSI has no record of
+// which volumes are attached to which nodes.
+func (p *Plugin) GetNodeAttachedVolumes(_ context.Context, _ string) ([]string, error) {
+	return nil, utils.UnsupportedError("volume attachments are not recorded by plain CSI")
+}
+
 // SupportsFeature accepts a CSI feature and returns true if the
 // feature exists and is supported.
 func (p *Plugin) SupportsFeature(_ context.Context, feature helpers.Feature) bool {
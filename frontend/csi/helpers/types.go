@@ -38,6 +38,11 @@ type HybridPlugin interface {
 	// a SnapshotConfig structure as needed by Trident to create a new snapshot.
 	GetSnapshotConfig(volumeName, snapshotName string) (*storage.SnapshotConfig, error)
 
+	// GetStorageClassForParameters accepts the parameters of a storage class as supplied by the
+	// CSI provisioner (i.e. in a GetCapacity request) and returns the name of the corresponding
+	// Trident storage class.
+	GetStorageClassForParameters(ctx context.Context, parameters map[string]string) (string, error)
+
 	// GetNodeTopologyLabels returns topology labels for a given node
 	// Example: map[string]string{"topology.kubernetes.io/region": "us-east1"}
 	GetNodeTopologyLabels(ctx context.Context, nodeName string) (map[string]string, error)
@@ -50,6 +55,11 @@ type HybridPlugin interface {
 	// event message in a manner appropriate to the container orchestrator.
 	RecordNodeEvent(ctx context.Context, name, eventType, reason, message string)
 
+	// GetNodeAttachedVolumes returns the names of the CSI volumes attached to a node, as recorded
+	// by the container orchestrator.  An unsupported error is returned if the container orchestrator
+	// does not record volume attachments.
+	GetNodeAttachedVolumes(ctx context.Context, nodeName string) ([]string, error)
+
 	// SupportsFeature accepts a CSI feature and returns true if the feature is supported.
 	SupportsFeature(ctx context.Context, feature Feature) bool
 
@@ -0,0 +1,46 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/netapp/trident/config"
+)
+
+var (
+	iSCSIStagedVolumes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "node",
+			Name:      "iscsi_staged_volumes",
+			Help:      "The number of iSCSI volumes staged on this node at the last self-healing check",
+		},
+	)
+	iSCSIDegradedVolumes = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "node",
+			Name:      "iscsi_degraded_volumes",
+			Help:      "The number of staged iSCSI volumes with fewer running paths than expected after the last self-healing check",
+		},
+	)
+	iSCSISelfHealingRepairsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "node",
+			Name:      "iscsi_self_healing_repairs_total",
+			Help:      "The total number of iSCSI sessions restored and multipath devices reloaded by self-healing",
+		},
+		[]string{"repair"},
+	)
+	iSCSISelfHealingErrorsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "node",
+			Name:      "iscsi_self_healing_errors_total",
+			Help:      "The total number of staged iSCSI volumes that self-healing failed to check or repair",
+		},
+	)
+)
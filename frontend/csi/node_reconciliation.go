@@ -0,0 +1,282 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+const DefaultNodeReconcileInterval = 15 * time.Minute
+
+// NodeReconciliationReport describes the volumes and devices found on a node that the controller no
+// longer has published to it, along with what was done to clean them up.  Nothing is cleaned up unless
+// the controller's list of the volumes published to the node is authoritative.
+type NodeReconciliationReport struct {
+	DryRun           bool                    `json:"dryRun"`
+	Authoritative    bool                    `json:"authoritative"`
+	PublishedVolumes []string                `json:"publishedVolumes"`
+	StaleVolumes     []*StaleVolumeReport    `json:"staleVolumes"`
+	OrphanedDevices  []*OrphanedDeviceReport `json:"orphanedDevices"`
+	LoggedOutTargets []string                `json:"loggedOutTargets"`
+}
+
+// StaleVolumeReport describes a volume that is staged on a node but no longer published to it.
+// A stale volume that is still mounted is in use, so it is left alone.
+type StaleVolumeReport struct {
+	VolumeID          string `json:"volumeID"`
+	StagingTargetPath string `json:"stagingTargetPath,omitempty"`
+	InUse             bool   `json:"inUse"`
+	Cleaned           bool   `json:"cleaned"`
+	Error             string `json:"error,omitempty"`
+}
+
+// OrphanedDeviceReport describes an iSCSI device attached from one of Trident's targets whose LUN
+// belongs to a Trident volume that is not staged on the node, and that is not mounted.
+type OrphanedDeviceReport struct {
+	TargetIQN       string   `json:"targetIQN"`
+	LUN             string   `json:"lun"`
+	MultipathDevice string   `json:"multipathDevice,omitempty"`
+	Devices         []string `json:"devices"`
+	Removed         bool     `json:"removed"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// startNodeReconciliationThread reconciles the volumes and devices on this node with the volumes the
+// controller has published to it, once at startup and then periodically, so that anything left behind
+// by a reboot or a force-deleted pod is cleaned up.
+func (p *Plugin) startNodeReconciliationThread(ctx context.Context, stop <-chan struct{}) {
+
+	if p.nodeReconcileInterval <= 0 {
+		Logc(ctx).Info("Node reconciliation is disabled.")
+		return
+	}
+
+	Logc(ctx).WithField("interval", p.nodeReconcileInterval).Info("Starting node reconciliation thread.")
+
+	ticker := time.NewTicker(p.nodeReconcileInterval)
+
+	go func() {
+		defer ticker.Stop()
+		p.performNodeReconciliation(ctx)
+		for {
+			select {
+			case <-stop:
+				Logc(ctx).Info("Stopped node reconciliation thread.")
+				return
+			case <-ticker.C:
+				p.performNodeReconciliation(ctx)
+			}
+		}
+	}()
+}
+
+// stopNodeReconciliationThread stops the node reconciliation thread, if it was started.
+func (p *Plugin) stopNodeReconciliationThread(ctx context.Context) {
+
+	if p.stopNodeReconciliation != nil {
+		Logc(ctx).Info("Stopping node reconciliation thread.")
+		close(p.stopNodeReconciliation)
+		p.stopNodeReconciliation = nil
+	}
+}
+
+func (p *Plugin) performNodeReconciliation(ctx context.Context) {
+
+	report, err := p.ReconcileNodeVolumes(ctx, false)
+	if err != nil {
+		Logc(ctx).WithError(err).Error("Node reconciliation failed.")
+		return
+	}
+
+	for _, volume := range report.StaleVolumes {
+		fields := log.Fields{"volumeID": volume.VolumeID, "stagingTargetPath": volume.StagingTargetPath}
+		if volume.Error != "" {
+			Logc(ctx).WithFields(fields).Errorf("Could not clean up stale volume; %s", volume.Error)
+		} else if volume.InUse {
+			Logc(ctx).WithFields(fields).Warning("Volume is not published to this node but is still mounted.")
+		} else {
+			Logc(ctx).WithFields(fields).Info("Cleaned up stale volume.")
+		}
+	}
+	for _, device := range report.OrphanedDevices {
+		fields := log.Fields{"targetIQN": device.TargetIQN, "lun": device.LUN, "devices": device.Devices}
+		if device.Error != "" {
+			Logc(ctx).WithFields(fields).Errorf("Could not remove orphaned device; %s", device.Error)
+		} else {
+			Logc(ctx).WithFields(fields).Info("Removed orphaned device.")
+		}
+	}
+	for _, targetIQN := range report.LoggedOutTargets {
+		Logc(ctx).WithField("targetIQN", targetIQN).Info("Logged out of unused iSCSI target.")
+	}
+}
+
+// ReconcileNodeVolumes compares the volumes staged on this node, as recorded by their tracking files,
+// and the iSCSI devices attached from Trident's targets against the volumes the controller has published
+// to this node.  Staged volumes that are no longer published are unstaged, and devices whose LUNs belong
+// to Trident volumes that are not staged are flushed and removed, logging out of any target left without
+// devices.  Nothing that is mounted is ever cleaned up.  In dry-run mode, which is forced until the
+// controller knows every volume published to this node, the report describes what would be cleaned up.
+func (p *Plugin) ReconcileNodeVolumes(ctx context.Context, dryRun bool) (*NodeReconciliationReport, error) {
+
+	fields := log.Fields{"dryRun": dryRun}
+	Logc(ctx).WithFields(fields).Debug(">>>> ReconcileNodeVolumes")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< ReconcileNodeVolumes")
+
+	// Hold the node server lock throughout, so that no volume can be staged between listing the volumes
+	// published to this node and looking for the ones that aren't
+	lockContext := "ReconcileNodeVolumes"
+	utils.Lock(ctx, lockContext, lockID)
+	defer utils.Unlock(ctx, lockContext, lockID)
+
+	nodeVolumes, err := p.restClient.GetNodeVolumes(ctx, p.nodeName)
+	if err != nil {
+		return nil, err
+	}
+	publishedVolumes := nodeVolumes.Volumes
+
+	// Until the controller knows every volume published to this node, anything could still be in use
+	if !nodeVolumes.Authoritative && !dryRun {
+		Logc(ctx).Warning("The controller does not yet know all volumes published to this node; " +
+			"reporting stale volumes and devices without cleaning them up.")
+		dryRun = true
+	}
+
+	volumeIds, err := p.listStagedVolumeIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NodeReconciliationReport{
+		DryRun:           dryRun,
+		Authoritative:    nodeVolumes.Authoritative,
+		PublishedVolumes: publishedVolumes,
+		StaleVolumes:     make([]*StaleVolumeReport, 0),
+		OrphanedDevices:  make([]*OrphanedDeviceReport, 0),
+		LoggedOutTargets: make([]string, 0),
+	}
+
+	targetIQNs := make([]string, 0)
+	stagedVolumes := make([]*utils.VolumePublishInfo, 0)
+
+	for _, volumeId := range volumeIds {
+
+		stagingTargetPath, err := p.readStagedTrackingFile(ctx, volumeId)
+		if err != nil {
+			continue
+		}
+
+		published := utils.SliceContainsString(publishedVolumes, volumeId)
+
+		publishInfo, err := p.readStagedDeviceInfo(ctx, stagingTargetPath)
+		if err != nil {
+			if !published {
+				// Without its device info, all that remains of the volume is its tracking file
+				volumeReport := &StaleVolumeReport{VolumeID: volumeId, StagingTargetPath: stagingTargetPath}
+				if !dryRun {
+					if err = p.clearStagedTrackingFile(ctx, volumeId); err != nil {
+						volumeReport.Error = err.Error()
+					} else {
+						volumeReport.Cleaned = true
+					}
+				}
+				report.StaleVolumes = append(report.StaleVolumes, volumeReport)
+			}
+			continue
+		}
+
+		if publishInfo.IscsiTargetIQN != "" && !utils.SliceContainsString(targetIQNs, publishInfo.IscsiTargetIQN) {
+			targetIQNs = append(targetIQNs, publishInfo.IscsiTargetIQN)
+		}
+
+		if published {
+			stagedVolumes = append(stagedVolumes, publishInfo)
+			continue
+		}
+
+		volumeReport := &StaleVolumeReport{VolumeID: volumeId, StagingTargetPath: stagingTargetPath}
+		report.StaleVolumes = append(report.StaleVolumes, volumeReport)
+
+		if volumeReport.InUse, err = utils.IsStagedVolumeMounted(ctx, publishInfo); err != nil {
+			volumeReport.Error = err.Error()
+		} else if !volumeReport.InUse && !dryRun {
+			volumeReport.Cleaned, volumeReport.Error = p.unstageStaleVolume(ctx, volumeId, stagingTargetPath)
+		}
+
+		// A stale volume that wasn't cleaned up still owns its device
+		if !volumeReport.Cleaned {
+			stagedVolumes = append(stagedVolumes, publishInfo)
+		}
+	}
+
+	if len(targetIQNs) == 0 {
+		return report, nil
+	}
+
+	orphanedDevices, err := utils.FindOrphanedISCSIDevices(ctx, targetIQNs, stagedVolumes, nodeVolumes.LUNSerials)
+	if err != nil {
+		return nil, err
+	}
+
+	unusedTargetIQNs := make([]string, 0)
+	for _, device := range orphanedDevices {
+
+		deviceReport := &OrphanedDeviceReport{
+			TargetIQN:       device.IQN,
+			LUN:             device.LUN,
+			MultipathDevice: device.MultipathDevice,
+			Devices:         device.Devices,
+		}
+		report.OrphanedDevices = append(report.OrphanedDevices, deviceReport)
+
+		if dryRun {
+			continue
+		}
+		if err = utils.RemoveOrphanedISCSIDevice(ctx, device); err != nil {
+			deviceReport.Error = err.Error()
+			continue
+		}
+		deviceReport.Removed = true
+
+		if !utils.SliceContainsString(unusedTargetIQNs, device.IQN) {
+			unusedTargetIQNs = append(unusedTargetIQNs, device.IQN)
+		}
+	}
+
+	for _, targetIQN := range unusedTargetIQNs {
+		loggedOut, err := utils.LogoutISCSITargetIfUnused(ctx, targetIQN)
+		if err != nil {
+			Logc(ctx).WithField("targetIQN", targetIQN).WithError(err).Error("Could not log out of iSCSI target.")
+		}
+		if loggedOut {
+			report.LoggedOutTargets = append(report.LoggedOutTargets, targetIQN)
+		}
+	}
+
+	return report, nil
+}
+
+// unstageStaleVolume unstages a volume that is no longer published to this node.  The caller holds the
+// node server lock, so the volume's lock is only tried, and a volume that is busy is left for the next
+// reconciliation.
+func (p *Plugin) unstageStaleVolume(ctx context.Context, volumeId, stagingTargetPath string) (bool, string) {
+
+	unlock := p.volumeLocks.TryLock(volumeId)
+	if unlock == nil {
+		return false, "volume is busy"
+	}
+	defer unlock()
+
+	req := &csi.NodeUnstageVolumeRequest{VolumeId: volumeId, StagingTargetPath: stagingTargetPath}
+	if _, err := p.nodeUnstageVolume(ctx, req); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
@@ -0,0 +1,240 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/frontend/csi/helpers"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	DefaultISCSISelfHealingInterval = 5 * time.Minute
+
+	// iSCSISelfHealingEventInterval is the minimum time between repeated warning events about one volume
+	iSCSISelfHealingEventInterval = 1 * time.Hour
+
+	iSCSIPathsRestoredEventReason     = "ISCSIPathsRestored"
+	iSCSIPathsDegradedEventReason     = "ISCSIPathsDegraded"
+	iSCSISelfHealingFailedEventReason = "ISCSISelfHealingFailed"
+)
+
+// startISCSISelfHealingThread periodically repairs the iSCSI sessions and multipath devices of the volumes
+// staged on this node, so that paths lost when a LIF fails over or a portal goes away are restored without
+// waiting for the volume to be staged again.
+func (p *Plugin) startISCSISelfHealingThread(ctx context.Context, stop <-chan struct{}) {
+
+	if p.iSCSISelfHealingInterval <= 0 {
+		Logc(ctx).Info("iSCSI self-healing is disabled.")
+		return
+	}
+
+	Logc(ctx).WithField("interval", p.iSCSISelfHealingInterval).Info("Starting iSCSI self-healing thread.")
+
+	ticker := time.NewTicker(p.iSCSISelfHealingInterval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				Logc(ctx).Info("Stopped iSCSI self-healing thread.")
+				return
+			case <-ticker.C:
+				p.performISCSISelfHealing(ctx)
+			}
+		}
+	}()
+}
+
+// stopISCSISelfHealingThread stops the iSCSI self-healing thread, if it was started.
+func (p *Plugin) stopISCSISelfHealingThread(ctx context.Context) {
+
+	if p.stopISCSISelfHealing != nil {
+		Logc(ctx).Info("Stopping iSCSI self-healing thread.")
+		close(p.stopISCSISelfHealing)
+		p.stopISCSISelfHealing = nil
+	}
+}
+
+// performISCSISelfHealing checks each iSCSI volume staged on this node, using the device info saved
+// when it was staged, and repairs any missing sessions or paths.  The results are published as metrics,
+// and any repairs or remaining degradation are reported as events on this node.
+func (p *Plugin) performISCSISelfHealing(ctx context.Context) {
+
+	Logc(ctx).Debug(">>>> performISCSISelfHealing")
+	defer Logc(ctx).Debug("<<<< performISCSISelfHealing")
+
+	volumeIds, err := p.listStagedVolumeIds(ctx)
+	if err != nil {
+		Logc(ctx).WithError(err).Error("Could not list staged volumes for iSCSI self-healing.")
+		return
+	}
+
+	staged, degraded := 0, 0
+
+	// Forget the warnings about volumes that are no longer staged
+	for warning := range p.iSCSISelfHealingWarnings {
+		if !utils.SliceContainsString(volumeIds, warning.volumeId) {
+			delete(p.iSCSISelfHealingWarnings, warning)
+		}
+	}
+
+	for _, volumeId := range volumeIds {
+
+		result, err := p.healISCSIVolume(ctx, volumeId)
+		if err != nil {
+			iSCSISelfHealingErrorsTotal.Inc()
+			Logc(ctx).WithField("volumeId", volumeId).WithError(err).Error("iSCSI self-healing failed.")
+			p.recordISCSISelfHealingWarning(ctx, volumeId, iSCSISelfHealingFailedEventReason,
+				fmt.Sprintf("could not repair iSCSI paths of volume %s; %v", volumeId, err))
+			continue
+		}
+		p.clearISCSISelfHealingWarning(volumeId, iSCSISelfHealingFailedEventReason)
+		if result == nil {
+			continue
+		}
+
+		staged++
+
+		if len(result.RestoredPortals) > 0 {
+			iSCSISelfHealingRepairsTotal.WithLabelValues("session").Add(float64(len(result.RestoredPortals)))
+		}
+		if result.MultipathReloaded {
+			iSCSISelfHealingRepairsTotal.WithLabelValues("multipath").Inc()
+		}
+
+		fields := log.Fields{
+			"volumeId":          volumeId,
+			"missingPortals":    result.MissingPortals,
+			"restoredPortals":   result.RestoredPortals,
+			"runningPaths":      result.RunningPaths,
+			"expectedPaths":     result.ExpectedPaths,
+			"multipathReloaded": result.MultipathReloaded,
+		}
+
+		if result.Degraded() {
+			degraded++
+			Logc(ctx).WithFields(fields).Warning("iSCSI volume is degraded.")
+			p.recordISCSISelfHealingWarning(ctx, volumeId, iSCSIPathsDegradedEventReason,
+				fmt.Sprintf("volume %s has %d of %d iSCSI paths running", volumeId,
+					result.RunningPaths, result.ExpectedPaths))
+			continue
+		}
+
+		p.clearISCSISelfHealingWarning(volumeId, iSCSIPathsDegradedEventReason)
+		if result.Repaired() {
+			Logc(ctx).WithFields(fields).Info("Restored iSCSI paths.")
+			p.recordNodeEvent(ctx, helpers.EventTypeNormal, iSCSIPathsRestoredEventReason,
+				fmt.Sprintf("restored iSCSI paths of volume %s; %d of %d paths running", volumeId,
+					result.RunningPaths, result.ExpectedPaths))
+		} else {
+			Logc(ctx).WithFields(fields).Debug("iSCSI volume is healthy.")
+		}
+	}
+
+	iSCSIStagedVolumes.Set(float64(staged))
+	iSCSIDegradedVolumes.Set(float64(degraded))
+}
+
+// healISCSIVolume repairs the sessions and paths of a staged volume while holding the volume's lock, so
+// that the volume cannot be staged or unstaged at the same time.  The node server lock is not held, since
+// logging in to a portal that is down can take a long time, and other volumes must not wait for that.
+// A nil result is returned if the volume is no longer staged or is not an iSCSI volume.
+func (p *Plugin) healISCSIVolume(ctx context.Context, volumeId string) (*utils.ISCSISelfHealingResult, error) {
+
+	defer p.volumeLocks.Lock(volumeId)()
+
+	stagingTargetPath, err := p.readStagedTrackingFile(ctx, volumeId)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	publishInfo, err := p.readStagedDeviceInfo(ctx, stagingTargetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if publishInfo.IscsiTargetIQN == "" {
+		return nil, nil
+	}
+
+	return utils.HealISCSIVolume(ctx, publishInfo)
+}
+
+// iSCSISelfHealingWarning identifies a warning event recorded about a volume.
+type iSCSISelfHealingWarning struct {
+	volumeId string
+	reason   string
+}
+
+// recordISCSISelfHealingWarning records a warning event about a volume, unless the same warning was
+// recorded recently, so that a volume that stays degraded does not raise an event on every check.
+func (p *Plugin) recordISCSISelfHealingWarning(ctx context.Context, volumeId, reason, message string) {
+
+	warning := iSCSISelfHealingWarning{volumeId: volumeId, reason: reason}
+	if recorded, ok := p.iSCSISelfHealingWarnings[warning]; ok && time.Since(recorded) < iSCSISelfHealingEventInterval {
+		Logc(ctx).WithFields(log.Fields{
+			"volumeId": volumeId,
+			"reason":   reason,
+		}).Debug("Warning event recorded recently, not recording it again.")
+		return
+	}
+
+	if p.iSCSISelfHealingWarnings == nil {
+		p.iSCSISelfHealingWarnings = make(map[iSCSISelfHealingWarning]time.Time)
+	}
+	p.iSCSISelfHealingWarnings[warning] = time.Now()
+
+	p.recordNodeEvent(ctx, helpers.EventTypeWarning, reason, message)
+}
+
+// clearISCSISelfHealingWarning forgets a warning about a volume once its cause has gone away, so that
+// the warning is recorded at once if the cause returns.
+func (p *Plugin) clearISCSISelfHealingWarning(volumeId, reason string) {
+	delete(p.iSCSISelfHealingWarnings, iSCSISelfHealingWarning{volumeId: volumeId, reason: reason})
+}
+
+// listStagedVolumeIds returns the IDs of the volumes with tracking files, which are written when
+// a volume is staged and removed when it is unstaged.
+func (p *Plugin) listStagedVolumeIds(ctx context.Context) ([]string, error) {
+
+	files, err := ioutil.ReadDir(tridentDeviceInfoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeIds := make([]string, 0)
+	for _, file := range files {
+		if file.IsDir() || file.Name() == nodePrepBreadcrumbFilename || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		volumeIds = append(volumeIds, strings.TrimSuffix(file.Name(), ".json"))
+	}
+	return volumeIds, nil
+}
+
+// recordNodeEvent asks the controller to record an event against this node.  Failures are only logged,
+// since the event is informational.
+func (p *Plugin) recordNodeEvent(ctx context.Context, eventType, reason, message string) {
+
+	event := &utils.NodeEvent{Type: eventType, Reason: reason, Message: message}
+	if err := p.restClient.CreateNodeEvent(ctx, p.nodeName, event); err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"reason":  reason,
+			"message": message,
+		}).WithError(err).Warning("Could not record node event.")
+	}
+}
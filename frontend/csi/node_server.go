@@ -40,6 +40,8 @@ func (p *Plugin) NodeStageVolume(
 	ctx context.Context, req *csi.NodeStageVolumeRequest,
 ) (*csi.NodeStageVolumeResponse, error) {
 
+	defer p.volumeLocks.Lock(req.GetVolumeId())()
+
 	lockContext := "NodeStageVolume-" + req.GetVolumeId()
 	utils.Lock(ctx, lockContext, lockID)
 	defer utils.Unlock(ctx, lockContext, lockID)
@@ -52,6 +54,9 @@ func (p *Plugin) NodeStageVolume(
 	case string(tridentconfig.File):
 		return p.nodeStageNFSVolume(ctx, req)
 	case string(tridentconfig.Block):
+		if req.PublishContext["sanType"] == string(tridentconfig.NVMe) {
+			return p.nodeStageNVMeVolume(ctx, req)
+		}
 		return p.nodeStageISCSIVolume(ctx, req)
 	default:
 		return nil, status.Error(codes.InvalidArgument, "unknown protocol")
@@ -62,6 +67,8 @@ func (p *Plugin) NodeUnstageVolume(
 	ctx context.Context, req *csi.NodeUnstageVolumeRequest,
 ) (*csi.NodeUnstageVolumeResponse, error) {
 
+	defer p.volumeLocks.Lock(req.GetVolumeId())()
+
 	lockContext := "NodeUnstageVolume-" + req.GetVolumeId()
 	utils.Lock(ctx, lockContext, lockID)
 	defer utils.Unlock(ctx, lockContext, lockID)
@@ -70,6 +77,15 @@ func (p *Plugin) NodeUnstageVolume(
 	Logc(ctx).WithFields(fields).Debug(">>>> NodeUnstageVolume")
 	defer Logc(ctx).WithFields(fields).Debug("<<<< NodeUnstageVolume")
 
+	return p.nodeUnstageVolume(ctx, req)
+}
+
+// nodeUnstageVolume unstages a volume using the device info saved when it was staged.  The caller
+// must hold the volume's lock and the node server lock.
+func (p *Plugin) nodeUnstageVolume(
+	ctx context.Context, req *csi.NodeUnstageVolumeRequest,
+) (*csi.NodeUnstageVolumeResponse, error) {
+
 	_, stagingTargetPath, err := p.getVolumeIdAndStagingPath(req)
 	if err != nil {
 		return nil, err
@@ -89,6 +105,9 @@ func (p *Plugin) NodeUnstageVolume(
 	case tridentconfig.File:
 		return p.nodeUnstageNFSVolume(ctx, req)
 	case tridentconfig.Block:
+		if publishInfo.SANType == string(tridentconfig.NVMe) {
+			return p.nodeUnstageNVMeVolume(ctx, req, publishInfo)
+		}
 		return p.nodeUnstageISCSIVolume(ctx, req, publishInfo)
 	default:
 		return nil, status.Error(codes.InvalidArgument, "unknown protocol")
@@ -206,23 +225,34 @@ func (p *Plugin) NodeGetVolumeStats(
 	// Ensure volume is published at path
 	_, err := os.Stat(req.GetVolumePath())
 	if err != nil {
+		// An NFS volume whose export has gone away is still mounted, but unusable
+		if utils.IsStaleFileHandleError(err) {
+			return &csi.NodeGetVolumeStatsResponse{
+				VolumeCondition: &csi.VolumeCondition{
+					Abnormal: true,
+					Message:  fmt.Sprintf("stale NFS file handle at path %s", req.GetVolumePath()),
+				},
+			}, nil
+		}
 		return nil, status.Error(codes.NotFound,
 			fmt.Sprintf("could not find volume mount at path: %s; %v ", req.GetVolumePath(), err))
 	}
 
 	// If raw block volume, dont return usage
 	isRawBlock := false
+	var publishInfo *utils.VolumePublishInfo
 	if req.StagingTargetPath != "" {
-		publishInfo, err := p.readStagedDeviceInfo(ctx, req.StagingTargetPath)
+		publishInfo, err = p.readStagedDeviceInfo(ctx, req.StagingTargetPath)
 		if err != nil {
 			return nil, status.Error(codes.Internal, err.Error())
 		}
 
 		isRawBlock = publishInfo.FilesystemType == fsRaw
 	}
+	volumeCondition := p.getNodeVolumeCondition(ctx, req.GetVolumePath(), publishInfo, isRawBlock)
 	if isRawBlock {
 		// Return no capacity info for raw block volumes, we cannot reliably determine the capacity
-		return &csi.NodeGetVolumeStatsResponse{}, nil
+		return &csi.NodeGetVolumeStatsResponse{VolumeCondition: volumeCondition}, nil
 	} else {
 		// If filesystem, return usage reported by FS
 		available, capacity, usage, inodes, inodesFree, inodesUsed, err := utils.GetFilesystemStats(
@@ -246,10 +276,55 @@ func (p *Plugin) NodeGetVolumeStats(
 					Used:      inodesUsed,
 				},
 			},
+			VolumeCondition: volumeCondition,
 		}, nil
 	}
 }
 
+// getNodeVolumeCondition checks a published volume for the problems that are only visible from the node,
+// namely missing paths to a SAN volume and a file system that has been remounted read-only.
+func (p *Plugin) getNodeVolumeCondition(
+	ctx context.Context, volumePath string, publishInfo *utils.VolumePublishInfo, isRawBlock bool,
+) *csi.VolumeCondition {
+
+	if publishInfo != nil && publishInfo.SANType == string(tridentconfig.NVMe) {
+		live, expected, err := utils.GetNVMePathCounts(ctx, publishInfo)
+		if err != nil {
+			Logc(ctx).WithField("subsystem", publishInfo.NVMeSubsystemNQN).WithError(err).Warning(
+				"Could not count NVMe paths.")
+		} else if live < expected {
+			return &csi.VolumeCondition{
+				Abnormal: true,
+				Message: fmt.Sprintf("%d of %d paths to NVMe subsystem %s are live", live, expected,
+					publishInfo.NVMeSubsystemNQN),
+			}
+		}
+	} else if publishInfo != nil && publishInfo.IscsiTargetIQN != "" {
+		running, expected := utils.GetISCSIPathCounts(ctx, publishInfo)
+		if running < expected {
+			return &csi.VolumeCondition{
+				Abnormal: true,
+				Message: fmt.Sprintf("%d of %d paths to LUN %d on iSCSI target %s are running", running,
+					expected, publishInfo.IscsiLunNumber, publishInfo.IscsiTargetIQN),
+			}
+		}
+	}
+
+	if !isRawBlock {
+		readOnly, err := utils.IsRemountedReadOnly(ctx, volumePath)
+		if err != nil {
+			Logc(ctx).WithField("path", volumePath).WithError(err).Warning("Could not read mount options.")
+		} else if readOnly {
+			return &csi.VolumeCondition{
+				Abnormal: true,
+				Message:  fmt.Sprintf("file system at path %s has been remounted read-only", volumePath),
+			}
+		}
+	}
+
+	return &csi.VolumeCondition{Abnormal: false, Message: "volume is healthy"}
+}
+
 // The CO only calls NodeExpandVolume for the Block protocol as the filesystem has to be mounted to perform
 // the resize. This is enforced in our ControllerExpandVolume method where we return true for nodeExpansionRequired
 // when the protocol is Block and return false when the protocol is file.
@@ -317,50 +392,43 @@ func (p *Plugin) NodeExpandVolume(
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
-	lunID := int(publishInfo.IscsiLunNumber)
-
-	Logc(ctx).WithFields(log.Fields{
-		"targetIQN":      publishInfo.IscsiTargetIQN,
-		"lunID":          lunID,
-		"devicePath":     publishInfo.DevicePath,
-		"mountOptions":   publishInfo.MountOptions,
-		"filesystemType": publishInfo.FilesystemType,
-	}).Debug("PublishInfo for device to expand.")
-
-	// Make sure device is ready
-	if utils.IsAlreadyAttached(ctx, lunID, publishInfo.IscsiTargetIQN) {
+	// Make sure device is ready, and rescan it to detect increased size
+	if publishInfo.SANType == string(tridentconfig.NVMe) {
+		err = p.nodeRescanNVMeDevice(ctx, publishInfo, requiredBytes)
+	} else {
+		err = p.nodeRescanISCSIDevice(ctx, publishInfo, requiredBytes)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		// Rescan device to detect increased size
-		if err = utils.ISCSIRescanDevices(
-			ctx, publishInfo.IscsiTargetIQN, publishInfo.IscsiLunNumber, requiredBytes); err != nil {
+	// Grow the LUKS device, if any, to fill the rescanned device
+	if utils.IsLUKSDevicePath(publishInfo.DevicePath) {
+		if err = utils.ResizeLUKSDevice(ctx, publishInfo.DevicePath); err != nil {
 			Logc(ctx).WithFields(log.Fields{
 				"device": publishInfo.DevicePath,
 				"error":  err,
-			}).Error("Unable to scan device.")
+			}).Error("Unable to resize LUKS device.")
 			return nil, status.Error(codes.Internal, err.Error())
 		}
+	}
 
-		// Expand filesystem
-		if publishInfo.FilesystemType != fsRaw {
-			filesystemSize, err := utils.ExpandISCSIFilesystem(ctx, publishInfo, stagingTargetPath)
-			if err != nil {
-				Logc(ctx).WithFields(log.Fields{
-					"device":         publishInfo.DevicePath,
-					"filesystemType": publishInfo.FilesystemType,
-					"error":          err,
-				}).Error("Unable to expand filesystem.")
-				return nil, status.Error(codes.Internal, err.Error())
-			}
+	// Expand filesystem
+	if publishInfo.FilesystemType != fsRaw {
+		filesystemSize, err := utils.ExpandISCSIFilesystem(ctx, publishInfo, stagingTargetPath)
+		if err != nil {
 			Logc(ctx).WithFields(log.Fields{
-				"filesystemSize": filesystemSize,
-				"requiredBytes":  requiredBytes,
-				"limitBytes":     limitBytes,
-			}).Debug("Filesystem size after expand.")
+				"device":         publishInfo.DevicePath,
+				"filesystemType": publishInfo.FilesystemType,
+				"error":          err,
+			}).Error("Unable to expand filesystem.")
+			return nil, status.Error(codes.Internal, err.Error())
 		}
-	} else {
-		Logc(ctx).WithField("devicePath", publishInfo.DevicePath).Error("Unable to expand volume as device is not attached.")
-		err = fmt.Errorf("device %s to expand is not attached", publishInfo.DevicePath)
-		return nil, status.Error(codes.Internal, err.Error())
+		Logc(ctx).WithFields(log.Fields{
+			"filesystemSize": filesystemSize,
+			"requiredBytes":  requiredBytes,
+			"limitBytes":     limitBytes,
+		}).Debug("Filesystem size after expand.")
 	}
 
 	Logc(ctx).WithFields(log.Fields{
@@ -370,6 +438,70 @@ func (p *Plugin) NodeExpandVolume(
 	return &csi.NodeExpandVolumeResponse{}, nil
 }
 
+// nodeRescanISCSIDevice verifies that the iSCSI LUN of a volume being expanded is attached, and rescans its
+// devices so that the host detects the LUN's increased size.
+func (p *Plugin) nodeRescanISCSIDevice(
+	ctx context.Context, publishInfo *utils.VolumePublishInfo, requiredBytes int64,
+) error {
+
+	lunID := int(publishInfo.IscsiLunNumber)
+
+	Logc(ctx).WithFields(log.Fields{
+		"targetIQN":      publishInfo.IscsiTargetIQN,
+		"lunID":          lunID,
+		"devicePath":     publishInfo.DevicePath,
+		"mountOptions":   publishInfo.MountOptions,
+		"filesystemType": publishInfo.FilesystemType,
+	}).Debug("PublishInfo for device to expand.")
+
+	if !utils.IsAlreadyAttached(ctx, lunID, publishInfo.IscsiTargetIQN) {
+		Logc(ctx).WithField("devicePath", publishInfo.DevicePath).Error("Unable to expand volume as device is not attached.")
+		err := fmt.Errorf("device %s to expand is not attached", publishInfo.DevicePath)
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	if err := utils.ISCSIRescanDevices(
+		ctx, publishInfo.IscsiTargetIQN, publishInfo.IscsiLunNumber, requiredBytes); err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"device": publishInfo.DevicePath,
+			"error":  err,
+		}).Error("Unable to scan device.")
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
+// nodeRescanNVMeDevice rescans the NVMe namespace of a volume being expanded so that the host detects the
+// namespace's increased size.  The rescan fails if the host is not connected to the namespace's subsystem.
+func (p *Plugin) nodeRescanNVMeDevice(
+	ctx context.Context, publishInfo *utils.VolumePublishInfo, requiredBytes int64,
+) error {
+
+	Logc(ctx).WithFields(log.Fields{
+		"subsystemNQN":   publishInfo.NVMeSubsystemNQN,
+		"namespaceUUID":  publishInfo.NVMeNamespaceUUID,
+		"devicePath":     publishInfo.DevicePath,
+		"mountOptions":   publishInfo.MountOptions,
+		"filesystemType": publishInfo.FilesystemType,
+	}).Debug("PublishInfo for device to expand.")
+
+	if publishInfo.NVMeSubsystemNQN == "" || publishInfo.NVMeNamespaceUUID == "" {
+		return status.Error(codes.Internal, "no NVMe subsystem or namespace found in volume publish info")
+	}
+
+	if err := utils.NVMeRescanNamespace(
+		ctx, publishInfo.NVMeSubsystemNQN, publishInfo.NVMeNamespaceUUID, requiredBytes); err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"device": publishInfo.DevicePath,
+			"error":  err,
+		}).Error("Unable to scan device.")
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return nil
+}
+
 func (p *Plugin) NodeGetCapabilities(
 	ctx context.Context, _ *csi.NodeGetCapabilitiesRequest,
 ) (*csi.NodeGetCapabilitiesResponse, error) {
@@ -430,6 +562,18 @@ func (p *Plugin) nodeGetInfo(ctx context.Context) *utils.Node {
 		Logc(ctx).WithField("IQN", iscsiWWN).Info("Discovered iSCSI initiator name.")
 	}
 
+	nvmeNQN := ""
+	if utils.NVMeSupported(ctx) {
+		if nvmeNQN, err = utils.GetHostNQN(ctx); err != nil {
+			Logc(ctx).WithField("error", err).Warn("Problem getting NVMe host NQN.")
+		} else {
+			Logc(ctx).WithFields(log.Fields{
+				"NQN":             nvmeNQN,
+				"nativeMultipath": utils.NVMeNativeMultipathEnabled(ctx),
+			}).Info("Discovered NVMe host NQN.")
+		}
+	}
+
 	ips, err := utils.GetIPAddresses(ctx)
 	if err != nil {
 		Logc(ctx).WithField("error", err).Error("Could not get IP addresses.")
@@ -442,6 +586,7 @@ func (p *Plugin) nodeGetInfo(ctx context.Context) *utils.Node {
 	node := &utils.Node{
 		Name:     p.nodeName,
 		IQN:      iscsiWWN,
+		NQN:      nvmeNQN,
 		IPs:      ips,
 		NodePrep: p.nodePrep,
 		HostInfo: p.hostInfo,
@@ -906,6 +1051,7 @@ func (p *Plugin) nodeStageISCSIVolume(
 	publishInfo.IscsiLunSerial = req.PublishContext["iscsiLunSerial"]
 	publishInfo.IscsiInterface = req.PublishContext["iscsiInterface"]
 	publishInfo.IscsiIgroup = req.PublishContext["iscsiIgroup"]
+	publishInfo.LUKSEncryption = req.PublishContext["luksEncryption"]
 
 	if useCHAP {
 		publishInfo.IscsiUsername = req.PublishContext["iscsiUsername"]
@@ -927,8 +1073,9 @@ func (p *Plugin) nodeStageISCSIVolume(
 		}
 	}
 
-	// Perform the login/rescan/discovery/(optionally)format, mount & get the device back in the publish info
-	if err := utils.AttachISCSIVolume(ctx, req.VolumeContext["internalName"], "", publishInfo); err != nil {
+	// Perform the login/rescan/discovery/(optionally)encrypt/format, mount & get the device back in the publish info
+	if err := utils.AttachISCSIVolume(ctx, req.VolumeContext["internalName"], "", publishInfo,
+		req.GetSecrets()[LUKSPassphraseSecretKey]); err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -949,6 +1096,14 @@ func (p *Plugin) nodeUnstageISCSIVolume(
 	ctx context.Context, req *csi.NodeUnstageVolumeRequest, publishInfo *utils.VolumePublishInfo,
 ) (*csi.NodeUnstageVolumeResponse, error) {
 
+	// Close the LUKS device, if any, before removing the device beneath it
+	if utils.IsLUKSDevicePath(publishInfo.DevicePath) {
+		err := utils.CloseLUKSDevice(ctx, publishInfo.DevicePath)
+		if nil != err && !p.unsafeDetach {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
 	// Delete the device from the host
 	err := utils.PrepareDeviceForRemoval(ctx, int(publishInfo.IscsiLunNumber), publishInfo.IscsiTargetIQN,
 		p.unsafeDetach)
@@ -1012,6 +1167,103 @@ func (p *Plugin) nodeUnstageISCSIVolume(
 	return &csi.NodeUnstageVolumeResponse{}, nil
 }
 
+func (p *Plugin) nodeStageNVMeVolume(
+	ctx context.Context, req *csi.NodeStageVolumeRequest,
+) (*csi.NodeStageVolumeResponse, error) {
+
+	var fstype string
+
+	mountCapability := req.GetVolumeCapability().GetMount()
+	blockCapability := req.GetVolumeCapability().GetBlock()
+
+	if mountCapability == nil && blockCapability == nil {
+		return nil, status.Error(codes.InvalidArgument, "mount or block capability required")
+	} else if mountCapability != nil && blockCapability != nil {
+		return nil, status.Error(codes.InvalidArgument, "mixed block and mount capabilities")
+	}
+
+	if mountCapability != nil && mountCapability.GetFsType() != "" {
+		fstype = mountCapability.GetFsType()
+	}
+
+	if fstype == "" {
+		fstype = req.PublishContext["filesystemType"]
+	}
+
+	if fstype == fsRaw && mountCapability != nil {
+		return nil, status.Error(codes.InvalidArgument, "mount capability requested with raw blocks")
+	} else if fstype != fsRaw && blockCapability != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("block capability requested with %s", fstype))
+	}
+
+	if req.PublishContext["nvmeTargetIps"] == "" {
+		return nil, status.Error(codes.InvalidArgument, "no NVMe target IPs provided")
+	}
+
+	publishInfo := &utils.VolumePublishInfo{
+		Localhost:      true,
+		FilesystemType: fstype,
+		SANType:        req.PublishContext["sanType"],
+	}
+	publishInfo.MountOptions = req.PublishContext["mountOptions"]
+	publishInfo.NVMeSubsystemNQN = req.PublishContext["nvmeSubsystemNqn"]
+	publishInfo.NVMeNamespaceUUID = req.PublishContext["nvmeNamespaceUuid"]
+	publishInfo.NVMeTargetIPs = strings.Split(req.PublishContext["nvmeTargetIps"], ",")
+
+	// Perform the connect/discovery/(optionally)format, mount & get the device back in the publish info
+	if err := utils.AttachNVMeVolume(ctx, req.VolumeContext["internalName"], "", publishInfo); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	volumeId, stagingTargetPath, err := p.getVolumeIdAndStagingPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save the device info to the staging path for use in the publish & unstage calls
+	if err := p.writeStagedDeviceInfo(ctx, stagingTargetPath, publishInfo, volumeId); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &csi.NodeStageVolumeResponse{}, nil
+}
+
+func (p *Plugin) nodeUnstageNVMeVolume(
+	ctx context.Context, req *csi.NodeUnstageVolumeRequest, publishInfo *utils.VolumePublishInfo,
+) (*csi.NodeUnstageVolumeResponse, error) {
+
+	// Flush the device before its paths go away
+	err := utils.PrepareNVMeDeviceForRemoval(ctx, publishInfo.DevicePath, p.unsafeDetach)
+	if nil != err && !p.unsafeDetach {
+		return nil, err
+	}
+
+	// Each volume has its own subsystem, so the host may always disconnect from it
+	if err := utils.NVMeDisconnect(ctx, publishInfo.NVMeSubsystemNQN); err != nil {
+		Logc(ctx).Error(err)
+	}
+
+	volumeId, stagingTargetPath, err := p.getVolumeIdAndStagingPath(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delete the device info we saved to the staging path so unstage can succeed
+	if err := p.clearStagedDeviceInfo(ctx, stagingTargetPath, volumeId); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	// Ensure that the temporary mount point created during a filesystem expand operation is removed.
+	if err := utils.UmountAndRemoveTemporaryMountPoint(ctx, stagingTargetPath); err != nil {
+		Logc(ctx).WithField("stagingTargetPath", stagingTargetPath).Errorf(
+			"Failed to remove directory in staging target path; %s", err)
+		return nil, fmt.Errorf("failed to remove temporary directory in staging target path %s; %s",
+			stagingTargetPath, err)
+	}
+
+	return &csi.NodeUnstageVolumeResponse{}, nil
+}
+
 func (p *Plugin) nodePublishISCSIVolume(
 	ctx context.Context, req *csi.NodePublishVolumeRequest,
 ) (*csi.NodePublishVolumeResponse, error) {
@@ -1209,6 +1461,8 @@ func (p *Plugin) getVolumeProtocolFromPublishInfo(publishInfo *utils.VolumePubli
 		return tridentconfig.File, nil
 	} else if publishInfo.VolumeAccessInfo.IscsiTargetIQN != "" && publishInfo.VolumeAccessInfo.NfsServerIP == "" {
 		return tridentconfig.Block, nil
+	} else if publishInfo.VolumeAccessInfo.NVMeSubsystemNQN != "" && publishInfo.VolumeAccessInfo.NfsServerIP == "" {
+		return tridentconfig.Block, nil
 	} else {
 		return "", fmt.Errorf("unable to infer volume protocol")
 	}
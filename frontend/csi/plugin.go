@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	log "github.com/sirupsen/logrus"
@@ -54,7 +55,22 @@ type Plugin struct {
 
 	opCache sync.Map
 
+	// volumeLocks serializes the work done on each volume on this node, so that slow work on one volume,
+	// such as logging in to an unreachable iSCSI portal, need not hold up the others.  A caller that also
+	// needs the node server lock must acquire the volume's lock first, or else only try to acquire it.
+	volumeLocks utils.NamedLocks
+
 	nodeIsRegistered bool
+
+	iSCSISelfHealingInterval time.Duration
+	stopISCSISelfHealing     chan struct{}
+	iSCSISelfHealingWarnings map[iSCSISelfHealingWarning]time.Time // only used by the self-healing thread
+
+	nodeReconcileInterval  time.Duration
+	stopNodeReconciliation chan struct{}
+
+	volumeConditions           sync.Map // key is volume name, value is *csi.VolumeCondition
+	stopVolumeConditionRefresh chan struct{}
 }
 
 func NewControllerPlugin(
@@ -89,6 +105,10 @@ func NewControllerPlugin(
 		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+		csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
 	})
 
 	// Define volume capabilities
@@ -105,7 +125,7 @@ func NewControllerPlugin(
 
 func NewNodePlugin(
 	nodeName, endpoint, caCert, clientCert, clientKey, aesKeyFile string, orchestrator core.Orchestrator,
-	unsafeDetach, nodePrep bool,
+	unsafeDetach, nodePrep bool, iSCSISelfHealingInterval, nodeReconcileInterval time.Duration,
 ) (*Plugin, error) {
 
 	ctx := GenerateRequestContext(context.Background(), "", ContextSourceInternal)
@@ -120,6 +140,9 @@ func NewNodePlugin(
 		unsafeDetach: unsafeDetach,
 		opCache:      sync.Map{},
 		nodePrep:     &utils.NodePrep{Enabled: nodePrep},
+
+		iSCSISelfHealingInterval: iSCSISelfHealingInterval,
+		nodeReconcileInterval:    nodeReconcileInterval,
 	}
 
 	// Initialize node prep statuses
@@ -130,6 +153,7 @@ func NewNodePlugin(
 			csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
 			csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
 			csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+			csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
 		},
 	)
 
@@ -174,7 +198,7 @@ func NewNodePlugin(
 func NewAllInOnePlugin(
 	nodeName, endpoint, caCert, clientCert, clientKey, aesKeyFile string,
 	orchestrator core.Orchestrator, helper *helpers.HybridPlugin,
-	unsafeDetach, nodePrep bool,
+	unsafeDetach, nodePrep bool, iSCSISelfHealingInterval, nodeReconcileInterval time.Duration,
 ) (*Plugin, error) {
 
 	ctx := GenerateRequestContext(context.Background(), "", ContextSourceInternal)
@@ -190,6 +214,9 @@ func NewAllInOnePlugin(
 		helper:       *helper,
 		opCache:      sync.Map{},
 		nodePrep:     &utils.NodePrep{Enabled: nodePrep},
+
+		iSCSISelfHealingInterval: iSCSISelfHealingInterval,
+		nodeReconcileInterval:    nodeReconcileInterval,
 	}
 
 	// Initialize node prep statuses
@@ -204,12 +231,17 @@ func NewAllInOnePlugin(
 		csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
+		csi.ControllerServiceCapability_RPC_GET_CAPACITY,
+		csi.ControllerServiceCapability_RPC_GET_VOLUME,
+		csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csi.ControllerServiceCapability_RPC_VOLUME_CONDITION,
 	})
 
 	p.addNodeServiceCapabilities([]csi.NodeServiceCapability_RPC_Type{
 		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
 		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
 		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+		csi.NodeServiceCapability_RPC_VOLUME_CONDITION,
 	})
 	port := "34571"
 	for _, envVar := range os.Environ() {
@@ -244,6 +276,21 @@ func NewAllInOnePlugin(
 }
 
 func (p *Plugin) Activate() error {
+
+	// The background threads' stop channels are created here rather than where the threads start, so
+	// that a Deactivate arriving before the threads have started still stops them
+	var stopISCSISelfHealing, stopNodeReconciliation, stopVolumeConditionRefresh chan struct{}
+	if p.role == CSINode || p.role == CSIAllInOne {
+		stopISCSISelfHealing = make(chan struct{})
+		stopNodeReconciliation = make(chan struct{})
+		p.stopISCSISelfHealing = stopISCSISelfHealing
+		p.stopNodeReconciliation = stopNodeReconciliation
+	}
+	if p.role == CSIController || p.role == CSIAllInOne {
+		stopVolumeConditionRefresh = make(chan struct{})
+		p.stopVolumeConditionRefresh = stopVolumeConditionRefresh
+	}
+
 	go func() {
 		ctx := GenerateRequestContext(context.Background(), "", ContextSourceInternal)
 		p.grpc = NewNonBlockingGRPCServer()
@@ -251,6 +298,11 @@ func (p *Plugin) Activate() error {
 		Logc(ctx).Info("Activating CSI frontend.")
 		if p.role == CSINode || p.role == CSIAllInOne {
 			p.nodeRegisterWithController(ctx, 0) // Retry indefinitely
+			p.startISCSISelfHealingThread(ctx, stopISCSISelfHealing)
+			p.startNodeReconciliationThread(ctx, stopNodeReconciliation)
+		}
+		if p.role == CSIController || p.role == CSIAllInOne {
+			p.startVolumeConditionThread(ctx, stopVolumeConditionRefresh)
 		}
 		p.grpc.Start(p.endpoint, p, p, p)
 	}()
@@ -261,6 +313,9 @@ func (p *Plugin) Deactivate() error {
 	ctx := GenerateRequestContext(context.Background(), "", ContextSourceInternal)
 
 	Logc(ctx).Info("Deactivating CSI frontend.")
+	p.stopISCSISelfHealingThread(ctx)
+	p.stopNodeReconciliationThread(ctx)
+	p.stopVolumeConditionThread(ctx)
 	p.grpc.GracefulStop()
 	return nil
 }
@@ -310,7 +365,7 @@ func (p *Plugin) addVolumeCapabilityAccessModes(vc []csi.VolumeCapability_Access
 }
 
 func (p *Plugin) getCSIErrorForOrchestratorError(err error) error {
-	if utils.IsNotReadyError(err) {
+	if utils.IsNotReadyError(err) || utils.IsNotLeaderError(err) {
 		return status.Error(codes.Unavailable, err.Error())
 	} else if utils.IsBootstrapError(err) {
 		return status.Error(codes.FailedPrecondition, err.Error())
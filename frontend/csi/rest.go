@@ -161,6 +161,36 @@ func (c *RestClient) GetNodes(ctx context.Context) ([]string, error) {
 	return respData.Nodes, nil
 }
 
+// ListNodeVolumesResponse lists the volumes the controller has published to a node, along with the LUN
+// serial numbers of all of Trident's iSCSI volumes.  Volumes and devices may only be cleaned up based on
+// a list that is authoritative.
+type ListNodeVolumesResponse struct {
+	Volumes       []string `json:"volumes"`
+	Authoritative bool     `json:"authoritative"`
+	LUNSerials    []string `json:"lunSerials"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// GetNodeVolumes returns the volumes the controller has published to the node
+func (c *RestClient) GetNodeVolumes(ctx context.Context, name string) (*ListNodeVolumesResponse, error) {
+	resp, respBody, err := c.InvokeAPI(ctx, nil, "GET", config.NodeURL+"/"+name+"/volume")
+	if err != nil {
+		return nil, fmt.Errorf("could not log into the Trident CSI Controller: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not list the volumes published to node %s", name)
+	}
+
+	// Parse JSON data
+	respData := ListNodeVolumesResponse{}
+	if err := json.Unmarshal(respBody, &respData); err != nil {
+		return nil, fmt.Errorf("could not parse volume list: %s; %v", string(respBody), err)
+	}
+
+	return &respData, nil
+}
+
 // DeleteNode deregisters the node with the CSI controller server
 func (c *RestClient) DeleteNode(ctx context.Context, name string) error {
 	resp, _, err := c.InvokeAPI(ctx, nil, "DELETE", config.NodeURL+"/"+name)
@@ -180,3 +210,20 @@ func (c *RestClient) DeleteNode(ctx context.Context, name string) error {
 	}
 	return nil
 }
+
+// CreateNodeEvent asks the CSI controller server to record an event against the node
+func (c *RestClient) CreateNodeEvent(ctx context.Context, name string, event *utils.NodeEvent) error {
+	eventData, err := json.MarshalIndent(event, "", " ")
+	if err != nil {
+		return fmt.Errorf("error parsing create node event request; %v", err)
+	}
+	resp, _, err := c.InvokeAPI(ctx, eventData, "POST", config.NodeURL+"/"+name+"/event")
+	if err != nil {
+		return fmt.Errorf("could not log into the Trident CSI Controller: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not create node event")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+const volumeMountsDirName = ".mounts"
+
+// volumeMounts records the Docker mount requests that reference a volume, so that a volume shared by
+// several containers is only detached when the last of them unmounts it.  It also keeps the publish info
+// needed to clean up the volume's devices at that time.  The records are kept in the volume path, so
+// they persist on the host across plugin restarts.  A volume that was already mounted when its mounts
+// began to be recorded may be in use by containers that are not recorded, so it is never detached
+// while it remains mounted.
+type volumeMounts struct {
+	MountIDs        []string                 `json:"mountIDs"`
+	UntrackedMounts bool                     `json:"untrackedMounts,omitempty"`
+	PublishInfo     *utils.VolumePublishInfo `json:"publishInfo,omitempty"`
+}
+
+func (p *Plugin) volumeMountsDir() string {
+	return filepath.Join(p.volumePath, volumeMountsDirName)
+}
+
+func (p *Plugin) volumeMountsFile(name string) string {
+	return filepath.Join(p.volumeMountsDir(), name+".json")
+}
+
+// readVolumeMounts returns the mount record for a volume, and whether there was one.  The record is
+// empty if there was none.
+func (p *Plugin) readVolumeMounts(ctx context.Context, name string) (*volumeMounts, bool, error) {
+
+	mounts := &volumeMounts{MountIDs: make([]string, 0)}
+
+	mountsBytes, err := ioutil.ReadFile(p.volumeMountsFile(name))
+	if os.IsNotExist(err) {
+		return mounts, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	if err = json.Unmarshal(mountsBytes, mounts); err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"volume": name,
+			"error":  err,
+		}).Error("Could not parse volume mount record.")
+		return nil, false, err
+	}
+
+	return mounts, true, nil
+}
+
+// writeVolumeMounts saves the mount record for a volume.
+func (p *Plugin) writeVolumeMounts(ctx context.Context, name string, mounts *volumeMounts) error {
+
+	mountsBytes, err := json.Marshal(mounts)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(p.volumeMountsDir(), 0700); err != nil {
+		return err
+	}
+
+	if err = ioutil.WriteFile(p.volumeMountsFile(name), mountsBytes, 0600); err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"volume": name,
+			"error":  err,
+		}).Error("Could not write volume mount record.")
+		return err
+	}
+
+	return nil
+}
+
+// deleteVolumeMounts removes the mount record for a volume once it is no longer mounted.
+func (p *Plugin) deleteVolumeMounts(ctx context.Context, name string) error {
+
+	if err := os.Remove(p.volumeMountsFile(name)); err != nil && !os.IsNotExist(err) {
+		Logc(ctx).WithFields(log.Fields{
+			"volume": name,
+			"error":  err,
+		}).Error("Could not remove volume mount record.")
+		return err
+	}
+	return nil
+}
+
+// iSCSITargetInUse returns true if any mounted volume other than the one specified was attached from the
+// specified iSCSI target, in which case the sessions to the target must be left in place.
+func (p *Plugin) iSCSITargetInUse(ctx context.Context, targetIQN, excludedName string) (bool, error) {
+
+	files, err := ioutil.ReadDir(p.volumeMountsDir())
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, file := range files {
+
+		name := strings.TrimSuffix(file.Name(), ".json")
+		if file.IsDir() || name == file.Name() || name == excludedName {
+			continue
+		}
+
+		mounts, _, err := p.readVolumeMounts(ctx, name)
+		if err != nil {
+			return false, err
+		}
+		inUse := len(mounts.MountIDs) > 0 || mounts.UntrackedMounts
+		if inUse && mounts.PublishInfo != nil && mounts.PublishInfo.IscsiTargetIQN == targetIQN {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// isMounted returns true if a file system is mounted at the mount point.
+func (p *Plugin) isMounted(ctx context.Context, mountpoint string) (bool, error) {
+
+	dfOutput, err := utils.GetDFOutput(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range dfOutput {
+		if e.Target == mountpoint {
+			return true, nil
+		}
+	}
+	return false, nil
+}
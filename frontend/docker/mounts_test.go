@@ -0,0 +1,116 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package docker
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/utils"
+)
+
+func iSCSIPublishInfo(targetIQN string) *utils.VolumePublishInfo {
+	return &utils.VolumePublishInfo{
+		VolumeAccessInfo: utils.VolumeAccessInfo{
+			IscsiAccessInfo: utils.IscsiAccessInfo{IscsiTargetIQN: targetIQN},
+		},
+	}
+}
+
+func TestVolumeMounts(t *testing.T) {
+
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "docker-volumes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := &Plugin{volumePath: dir}
+
+	// A volume that was never mounted has an empty record
+	mounts, recorded, err := p.readVolumeMounts(ctx, "vol1")
+	assert.NoError(t, err)
+	assert.False(t, recorded)
+	assert.Empty(t, mounts.MountIDs)
+	assert.Nil(t, mounts.PublishInfo)
+
+	mounts.MountIDs = append(mounts.MountIDs, "id1", "id2")
+	mounts.PublishInfo = iSCSIPublishInfo("iqn.1992-08.com.netapp:sn.1:vs.3")
+	assert.NoError(t, p.writeVolumeMounts(ctx, "vol1", mounts))
+
+	mounts, recorded, err = p.readVolumeMounts(ctx, "vol1")
+	assert.NoError(t, err)
+	assert.True(t, recorded)
+	assert.Equal(t, []string{"id1", "id2"}, mounts.MountIDs)
+	assert.False(t, mounts.UntrackedMounts)
+	assert.Equal(t, "iqn.1992-08.com.netapp:sn.1:vs.3", mounts.PublishInfo.IscsiTargetIQN)
+
+	// Untracked mounts are remembered even after every recorded mount is gone
+	mounts.MountIDs = []string{}
+	mounts.UntrackedMounts = true
+	assert.NoError(t, p.writeVolumeMounts(ctx, "vol1", mounts))
+
+	mounts, recorded, err = p.readVolumeMounts(ctx, "vol1")
+	assert.NoError(t, err)
+	assert.True(t, recorded)
+	assert.Empty(t, mounts.MountIDs)
+	assert.True(t, mounts.UntrackedMounts)
+
+	assert.NoError(t, p.deleteVolumeMounts(ctx, "vol1"))
+	assert.NoError(t, p.deleteVolumeMounts(ctx, "vol1"))
+
+	mounts, recorded, err = p.readVolumeMounts(ctx, "vol1")
+	assert.NoError(t, err)
+	assert.False(t, recorded)
+	assert.Empty(t, mounts.MountIDs)
+}
+
+func TestISCSITargetInUse(t *testing.T) {
+
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "docker-volumes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	p := &Plugin{volumePath: dir}
+
+	// No volumes have been mounted yet
+	inUse, err := p.iSCSITargetInUse(ctx, "iqn.1", "vol1")
+	assert.NoError(t, err)
+	assert.False(t, inUse)
+
+	assert.NoError(t, p.writeVolumeMounts(ctx, "vol1",
+		&volumeMounts{MountIDs: []string{"id1"}, PublishInfo: iSCSIPublishInfo("iqn.1")}))
+	assert.NoError(t, p.writeVolumeMounts(ctx, "vol2",
+		&volumeMounts{MountIDs: []string{"id2"}, PublishInfo: iSCSIPublishInfo("iqn.2")}))
+	assert.NoError(t, p.writeVolumeMounts(ctx, "vol3",
+		&volumeMounts{MountIDs: []string{}, PublishInfo: iSCSIPublishInfo("iqn.3")}))
+	assert.NoError(t, p.writeVolumeMounts(ctx, "vol4",
+		&volumeMounts{MountIDs: []string{}, UntrackedMounts: true, PublishInfo: iSCSIPublishInfo("iqn.5")}))
+
+	tests := []struct {
+		name         string
+		targetIQN    string
+		excludedName string
+		expected     bool
+	}{
+		{"otherVolume", "iqn.1", "vol2", true},
+		{"onlyExcludedVolume", "iqn.1", "vol1", false},
+		{"noMounts", "iqn.3", "vol1", false},
+		{"unknownTarget", "iqn.4", "vol1", false},
+		{"untrackedMounts", "iqn.5", "vol1", true},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		inUse, err := p.iSCSITargetInUse(ctx, test.targetIQN, test.excludedName)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, inUse)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -23,11 +24,17 @@ import (
 	frontendcommon "github.com/netapp/trident/frontend/common"
 	. "github.com/netapp/trident/logger"
 	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
 	"github.com/netapp/trident/utils"
 )
 
 const (
 	startupTimeout = 50 * time.Second
+
+	// Volume creation options that choose where a volume is created
+	storageClassOption = "storageClass"
+	backendOption      = "backend"
 )
 
 // Plugin implements the frontendcommon Plugin interface
@@ -37,7 +44,8 @@ type Plugin struct {
 	driverPort         string
 	volumePath         string
 	version            *Version
-	mutex              *sync.Mutex
+	volumeLocks        utils.NamedLocks // serializes mounts and unmounts of each volume
+	iSCSITargetMutex   *sync.RWMutex    // held for reading while attaching, for writing while logging out
 	isDockerPluginMode bool
 	hostVolumePath     string
 }
@@ -60,7 +68,7 @@ func NewPlugin(driverName, driverPort string, orchestrator core.Orchestrator) (*
 		driverName:         driverName,
 		driverPort:         driverPort,
 		volumePath:         filepath.Join(volume.DefaultDockerRootDirectory, driverName),
-		mutex:              &sync.Mutex{},
+		iSCSITargetMutex:   &sync.RWMutex{},
 		isDockerPluginMode: isDockerPluginMode,
 		hostVolumePath:     "",
 	}
@@ -221,8 +229,8 @@ func (p *Plugin) Create(request *volume.CreateRequest) error {
 		"options": request.Options,
 	}).Debug("Docker frontend method is invoked.")
 
-	// Find a matching storage class, or register a new one
-	scConfig, err := frontendcommon.GetStorageClass(ctx, request.Options, p.orchestrator)
+	// Find the requested or a matching storage class, or register a new one
+	scConfig, err := p.getStorageClass(ctx, request.Options)
 	if err != nil {
 		return p.dockerError(ctx, err)
 	}
@@ -249,6 +257,42 @@ func (p *Plugin) Create(request *volume.CreateRequest) error {
 	return p.dockerError(ctx, err)
 }
 
+// getStorageClass returns the storage class for a new volume.  A storage class that already exists, such
+// as one defined in the config files, may be chosen with the storageClass option.  Otherwise a storage class
+// is matched or created from the volume options, and the backend option limits it to that backend's pools.
+func (p *Plugin) getStorageClass(ctx context.Context, options map[string]string) (*storageclass.Config, error) {
+
+	scName, scOK := options[storageClassOption]
+	backendName, backendOK := options[backendOption]
+	delete(options, storageClassOption)
+	delete(options, backendOption)
+
+	if scOK && backendOK {
+		return nil, fmt.Errorf("the %s and %s options may not be used together", storageClassOption, backendOption)
+	}
+
+	if scOK {
+		sc, err := p.orchestrator.GetStorageClass(ctx, scName)
+		if err != nil {
+			return nil, err
+		}
+		Logc(ctx).WithField("storageClass", scName).Debug("Using requested storage class.")
+		return sc.Config, nil
+	}
+
+	if backendOK {
+		if _, ok := options[sa.StoragePools]; ok {
+			return nil, fmt.Errorf("the %s and %s options may not be used together", backendOption, sa.StoragePools)
+		}
+		if _, err := p.orchestrator.GetBackend(ctx, backendName); err != nil {
+			return nil, err
+		}
+		options[sa.StoragePools] = regexp.QuoteMeta(backendName) + ":.*"
+	}
+
+	return frontendcommon.GetStorageClass(ctx, options, p.orchestrator)
+}
+
 func (p *Plugin) List() (*volume.ListResponse, error) {
 
 	ctx := GenerateRequestContext(nil, "", ContextSourceDocker)
@@ -315,6 +359,11 @@ func (p *Plugin) Get(request *volume.GetRequest) (*volume.GetResponse, error) {
 		"Snapshots": dockerSnapshots,
 	}
 
+	// Report the backend, since volumes may be on any of several backends
+	if backend, err := p.orchestrator.GetBackendByBackendUUID(ctx, tridentVol.BackendUUID); err == nil {
+		status["Backend"] = backend.Name
+	}
+
 	// Get the mountpoint, if this volume is mounted
 	mountpoint, _ := p.getPath(ctx, tridentVol)
 
@@ -378,11 +427,46 @@ func (p *Plugin) Mount(request *volume.MountRequest) (*volume.MountResponse, err
 		"id":     request.ID,
 	}).Debug("Docker frontend method is invoked.")
 
+	// Serialize mounts and unmounts of the volume so that its mount record stays consistent
+	defer p.volumeLocks.Lock(request.Name)()
+
 	tridentVol, err := p.orchestrator.GetVolume(ctx, request.Name)
 	if err != nil {
 		return &volume.MountResponse{}, p.dockerError(ctx, err)
 	}
 
+	// if this is binary mode, then hostMountpoint and mountpoint will be the same
+	hostMountpoint := p.hostMountpoint(tridentVol.Config.InternalName)
+
+	mounts, recorded, err := p.readVolumeMounts(ctx, request.Name)
+	if err != nil {
+		return &volume.MountResponse{}, p.dockerError(ctx, err)
+	}
+
+	mounted, err := p.isMounted(ctx, hostMountpoint)
+	if err != nil {
+		return &volume.MountResponse{}, p.dockerError(ctx, err)
+	}
+
+	if !mounted && (len(mounts.MountIDs) > 0 || mounts.UntrackedMounts) {
+		// Mounts recorded before the host rebooted no longer refer to anything
+		Logc(ctx).WithFields(log.Fields{
+			"volume":   request.Name,
+			"mountIDs": mounts.MountIDs,
+		}).Warning("Discarding mounts of a volume that is no longer mounted.")
+		mounts.MountIDs = make([]string, 0)
+		mounts.UntrackedMounts = false
+	} else if mounted && !recorded {
+		// The volume was mounted before its mounts were recorded, so other containers may be using it
+		Logc(ctx).WithField("volume", request.Name).Warning(
+			"Volume is already mounted without a mount record; it will not be detached by later unmounts.")
+		mounts.UntrackedMounts = true
+	}
+
+	// Attaching logs in to iSCSI targets, which must not be logged out of at the same time
+	p.iSCSITargetMutex.RLock()
+	defer p.iSCSITargetMutex.RUnlock()
+
 	// First call PublishVolume to make the volume available to the node
 	publishInfo := &utils.VolumePublishInfo{Localhost: true}
 	if err = p.orchestrator.PublishVolume(ctx, request.Name, publishInfo); err != nil {
@@ -391,9 +475,6 @@ func (p *Plugin) Mount(request *volume.MountRequest) (*volume.MountResponse, err
 		return &volume.MountResponse{}, p.dockerError(ctx, err)
 	}
 
-	// if this is binary mode, then hostMountpoint and mountpoint will be the same
-	hostMountpoint := p.hostMountpoint(tridentVol.Config.InternalName)
-
 	// Then call AttachVolume to discover/format/mount the volume on the node
 	if err = p.orchestrator.AttachVolume(ctx, request.Name, hostMountpoint, publishInfo); err != nil {
 		err = fmt.Errorf("error attaching volume %v, hostMountpoint %v, error: %v", request.Name, hostMountpoint, err)
@@ -401,6 +482,15 @@ func (p *Plugin) Mount(request *volume.MountRequest) (*volume.MountResponse, err
 		return &volume.MountResponse{}, p.dockerError(ctx, err)
 	}
 
+	// Record the mount, along with what is needed to clean up after the last unmount
+	if !utils.SliceContainsString(mounts.MountIDs, request.ID) {
+		mounts.MountIDs = append(mounts.MountIDs, request.ID)
+	}
+	mounts.PublishInfo = publishInfo
+	if err = p.writeVolumeMounts(ctx, request.Name, mounts); err != nil {
+		return &volume.MountResponse{}, p.dockerError(ctx, err)
+	}
+
 	// if this is binary mode, then hostMountpoint and mountpoint will be the same
 	mountpoint := p.mountpoint(tridentVol.Config.InternalName)
 	return &volume.MountResponse{Mountpoint: mountpoint}, nil
@@ -416,6 +506,8 @@ func (p *Plugin) Unmount(request *volume.UnmountRequest) error {
 		"id":     request.ID,
 	}).Debug("Docker frontend method is invoked.")
 
+	defer p.volumeLocks.Lock(request.Name)()
+
 	tridentVol, err := p.orchestrator.GetVolume(ctx, request.Name)
 	if err != nil {
 		return p.dockerError(ctx, err)
@@ -424,14 +516,72 @@ func (p *Plugin) Unmount(request *volume.UnmountRequest) error {
 	// if this is binary mode, then hostMountpoint and mountpoint will be the same
 	hostMountpoint := p.hostMountpoint(tridentVol.Config.InternalName)
 
+	mounts, recorded, err := p.readVolumeMounts(ctx, request.Name)
+	if err != nil {
+		return p.dockerError(ctx, err)
+	}
+
+	// A volume without a mount record was mounted before mounts were recorded, or not by this plugin,
+	// so whether anything else is still using it is unknown
+	if !recorded {
+		Logc(ctx).WithField("volume", request.Name).Warning(
+			"Volume has no mount record and may still be in use, not detaching.")
+		return nil
+	}
+
+	// Leave the volume attached while other containers (or 'docker cp') still have it mounted.
+	// See https://github.com/moby/moby/issues/34665
+	mounts.MountIDs = utils.RemoveStringFromSlice(mounts.MountIDs, request.ID)
+	if len(mounts.MountIDs) > 0 || mounts.UntrackedMounts {
+		Logc(ctx).WithFields(log.Fields{
+			"volume":          request.Name,
+			"mountIDs":        mounts.MountIDs,
+			"untrackedMounts": mounts.UntrackedMounts,
+		}).Debug("Volume is still mounted by other requests, not detaching.")
+		return p.dockerError(ctx, p.writeVolumeMounts(ctx, request.Name, mounts))
+	}
+
+	// Unmount the volume and remove its SCSI devices, which can only be found from the mount
+	publishInfo := mounts.PublishInfo
+	isISCSI := publishInfo != nil && publishInfo.IscsiTargetIQN != ""
+	if isISCSI {
+		if mounted, err := p.isMounted(ctx, hostMountpoint); err != nil {
+			return p.dockerError(ctx, err)
+		} else if mounted {
+			if err = utils.PrepareDeviceAtMountPathForRemoval(ctx, hostMountpoint, true, false); err != nil {
+				err = fmt.Errorf("error removing devices of volume %v, hostMountpoint %v, error: %v",
+					request.Name, hostMountpoint, err)
+				Logc(ctx).Error(err)
+				return p.dockerError(ctx, err)
+			}
+		}
+	}
+
 	if err = p.orchestrator.DetachVolume(ctx, request.Name, hostMountpoint); err != nil {
 		err = fmt.Errorf("error detaching volume %v, hostMountpoint %v, error: %v", request.Name, hostMountpoint, err)
 		Logc(ctx).Error(err)
 		return p.dockerError(ctx, err)
 	}
 
-	// No longer detaching and removing iSCSI session here because it was causing issues with 'docker cp'.
-	// See https://github.com/moby/moby/issues/34665
+	if err = p.deleteVolumeMounts(ctx, request.Name); err != nil {
+		return p.dockerError(ctx, err)
+	}
+
+	// Log out of the iSCSI target once no other volume is using it
+	if isISCSI {
+		p.iSCSITargetMutex.Lock()
+		defer p.iSCSITargetMutex.Unlock()
+
+		inUse, err := p.iSCSITargetInUse(ctx, publishInfo.IscsiTargetIQN, request.Name)
+		if err != nil {
+			Logc(ctx).WithError(err).Warning("Could not determine if iSCSI target is in use, not logging out.")
+		} else if !inUse {
+			if _, err = utils.LogoutISCSITargetIfUnused(ctx, publishInfo.IscsiTargetIQN); err != nil {
+				Logc(ctx).WithField("targetIQN", publishInfo.IscsiTargetIQN).WithError(err).Warning(
+					"Could not log out of iSCSI target.")
+			}
+		}
+	}
 
 	return nil
 }
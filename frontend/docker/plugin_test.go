@@ -0,0 +1,62 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/core"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+)
+
+func TestGetStorageClass(t *testing.T) {
+
+	ctx := context.Background()
+
+	orchestrator := core.NewMockOrchestrator()
+	orchestrator.AddMockFakeNASBackend(ctx, "nas.backend-1")
+	_, err := orchestrator.AddStorageClass(ctx, &storageclass.Config{Name: "gold"})
+	assert.NoError(t, err)
+
+	p := &Plugin{orchestrator: orchestrator}
+
+	tests := []struct {
+		name          string
+		options       map[string]string
+		expectedName  string
+		expectedPools map[string][]string
+		expectedError bool
+	}{
+		{"storageClass", map[string]string{"storageClass": "gold", "size": "1G"}, "gold", nil, false},
+		{"missingStorageClass", map[string]string{"storageClass": "silver"}, "", nil, true},
+		{"backend", map[string]string{"backend": "nas.backend-1"}, "",
+			map[string][]string{`nas\.backend-1`: {".*"}}, false},
+		{"missingBackend", map[string]string{"backend": "san"}, "", nil, true},
+		{"storageClassAndBackend", map[string]string{"storageClass": "gold", "backend": "nas.backend-1"}, "",
+			nil, true},
+		{"backendAndStoragePools", map[string]string{"backend": "nas.backend-1", sa.StoragePools: "san:.*"}, "",
+			nil, true},
+		{"options", map[string]string{"media": "ssd"}, "", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		scConfig, err := p.getStorageClass(ctx, test.options)
+		if test.expectedError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		if test.expectedName != "" {
+			assert.Equal(t, test.expectedName, scConfig.Name)
+		}
+		assert.Equal(t, test.expectedPools, scConfig.Pools)
+		assert.NotContains(t, test.options, storageClassOption)
+		assert.NotContains(t, test.options, backendOption)
+	}
+}
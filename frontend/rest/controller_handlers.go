@@ -16,11 +16,11 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netapp/trident/config"
-	"github.com/netapp/trident/frontend"
 	"github.com/netapp/trident/frontend/csi/helpers"
 	k8shelper "github.com/netapp/trident/frontend/csi/helpers/kubernetes"
 	"github.com/netapp/trident/frontend/kubernetes"
 	. "github.com/netapp/trident/logger"
+	persistentstore "github.com/netapp/trident/persistent_store"
 	"github.com/netapp/trident/storage"
 	storageclass "github.com/netapp/trident/storage_class"
 	"github.com/netapp/trident/utils"
@@ -33,7 +33,7 @@ type listResponse interface {
 func httpStatusCodeForAdd(err error) int {
 	if err == nil {
 		return http.StatusCreated
-	} else if utils.IsNotReadyError(err) {
+	} else if utils.IsNotReadyError(err) || utils.IsNotLeaderError(err) {
 		return http.StatusServiceUnavailable
 	} else if utils.IsBootstrapError(err) {
 		return http.StatusInternalServerError
@@ -45,7 +45,7 @@ func httpStatusCodeForAdd(err error) int {
 func httpStatusCodeForGetUpdateList(err error) int {
 	if err == nil {
 		return http.StatusOK
-	} else if utils.IsNotReadyError(err) {
+	} else if utils.IsNotReadyError(err) || utils.IsNotLeaderError(err) {
 		return http.StatusServiceUnavailable
 	} else if utils.IsBootstrapError(err) {
 		return http.StatusInternalServerError
@@ -59,7 +59,7 @@ func httpStatusCodeForGetUpdateList(err error) int {
 func httpStatusCodeForDelete(err error) int {
 	if err == nil {
 		return http.StatusOK
-	} else if utils.IsNotReadyError(err) {
+	} else if utils.IsNotReadyError(err) || utils.IsNotLeaderError(err) {
 		return http.StatusServiceUnavailable
 	} else if utils.IsBootstrapError(err) {
 		return http.StatusInternalServerError
@@ -177,6 +177,17 @@ func AddGeneric(
 	r *http.Request,
 	response httpResponse,
 	adder func([]byte) int,
+) {
+	addGenericWithLimit(w, r, response, adder, config.MaxRESTRequestSize)
+}
+
+// addGenericWithLimit is AddGeneric for requests whose bodies may be larger than usual.
+func addGenericWithLimit(
+	w http.ResponseWriter,
+	r *http.Request,
+	response httpResponse,
+	adder func([]byte) int,
+	maxRequestSize int64,
 ) {
 	var err error
 	var httpStatusCode int
@@ -193,7 +204,7 @@ func AddGeneric(
 		writeHTTPResponse(r.Context(), w, response, httpStatusCode)
 	}()
 
-	body, err := ioutil.ReadAll(io.LimitReader(r.Body, config.MaxRESTRequestSize))
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestSize))
 	if err != nil {
 		response.setError(err)
 		httpStatusCode = httpStatusCodeForAdd(err)
@@ -428,6 +439,78 @@ func UpdateBackendState(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+type GetBackendVolumeReportResponse struct {
+	Report *storage.BackendVolumeReport `json:"report"`
+	Error  string                       `json:"error,omitempty"`
+}
+
+func GetBackendVolumeReport(w http.ResponseWriter, r *http.Request) {
+	response := &GetBackendVolumeReportResponse{}
+	GetGeneric(w, r, "backend", response,
+		func(backendName string) int {
+			report, err := orchestrator.GetBackendVolumeReport(r.Context(), backendName)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Report = report
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type DrainBackendResponse struct {
+	Report *storage.BackendVolumeReport `json:"report"`
+	Error  string                       `json:"error,omitempty"`
+}
+
+func (d *DrainBackendResponse) setError(err error) {
+	d.Error = err.Error()
+}
+
+func (d *DrainBackendResponse) isError() bool {
+	return d.Error != ""
+}
+
+func (d *DrainBackendResponse) logSuccess(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "DrainBackend",
+		"backend": d.Report.Backend,
+		"volumes": len(d.Report.Volumes),
+	}).Info("Started draining a backend.")
+}
+
+func (d *DrainBackendResponse) logFailure(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "DrainBackend",
+	}).Error(d.Error)
+}
+
+func DrainBackend(w http.ResponseWriter, r *http.Request) {
+	response := &DrainBackendResponse{}
+	UpdateGeneric(w, r, "backend", response,
+		func(backendName string, body []byte) int {
+			drainRequest := new(storage.BackendDrainRequest)
+			if len(body) > 0 {
+				if err := json.Unmarshal(body, drainRequest); err != nil {
+					response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+					return httpStatusCodeForGetUpdateList(err)
+				}
+			}
+			report, err := orchestrator.DrainBackend(r.Context(), backendName, drainRequest.Concurrency)
+			if err != nil {
+				response.Error = err.Error()
+			}
+			if report != nil {
+				response.Report = report
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
 type ListBackendsResponse struct {
 	Backends []string `json:"backends"`
 	Error    string   `json:"error,omitempty"`
@@ -694,6 +777,55 @@ func ImportVolume(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+type UpdateVolumeResponse struct {
+	Volume *storage.VolumeExternal `json:"volume"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+func (i *UpdateVolumeResponse) setError(err error) {
+	i.Error = err.Error()
+}
+
+func (i *UpdateVolumeResponse) isError() bool {
+	return i.Error != ""
+}
+
+func (i *UpdateVolumeResponse) logSuccess(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "UpdateVolume",
+		"volume":  i.Volume.Config.Name,
+	}).Info("Modified an existing volume.")
+}
+func (i *UpdateVolumeResponse) logFailure(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "UpdateVolume",
+	}).Error(i.Error)
+}
+
+func UpdateVolume(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateVolumeResponse{}
+	UpdateGeneric(w, r, "volume", response,
+		func(volumeName string, body []byte) int {
+			modification := new(storage.VolumeModification)
+			err := json.Unmarshal(body, modification)
+			if err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			volume, err := orchestrator.ModifyVolume(r.Context(), volumeName, modification)
+			if err != nil {
+				response.Error = err.Error()
+			}
+			if volume != nil {
+				response.Volume = volume
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
 type UpgradeVolumeResponse struct {
 	Volume *storage.VolumeExternal `json:"volume"`
 	Error  string                  `json:"error,omitempty"`
@@ -759,6 +891,57 @@ func UpgradeVolume(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+type RelocateVolumeResponse struct {
+	Volume *storage.VolumeExternal `json:"volume"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+func (i *RelocateVolumeResponse) setError(err error) {
+	i.Error = err.Error()
+}
+
+func (i *RelocateVolumeResponse) isError() bool {
+	return i.Error != ""
+}
+
+func (i *RelocateVolumeResponse) logSuccess(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "RelocateVolume",
+		"volume":  i.Volume.Config.Name,
+	}).Info("Started relocating an existing volume.")
+}
+func (i *RelocateVolumeResponse) logFailure(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "RelocateVolume",
+	}).Error(i.Error)
+}
+
+func RelocateVolume(w http.ResponseWriter, r *http.Request) {
+	response := &RelocateVolumeResponse{}
+	UpdateGeneric(w, r, "volume", response,
+		func(volumeName string, body []byte) int {
+			relocationRequest := new(storage.VolumeRelocationRequest)
+			if len(body) > 0 {
+				if err := json.Unmarshal(body, relocationRequest); err != nil {
+					response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+					return httpStatusCodeForGetUpdateList(err)
+				}
+			}
+			volume, err := orchestrator.RelocateVolume(r.Context(), volumeName, relocationRequest.Backend,
+				relocationRequest.Pool)
+			if err != nil {
+				response.Error = err.Error()
+			}
+			if volume != nil {
+				response.Volume = volume
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
 type AddStorageClassResponse struct {
 	StorageClassID string `json:"storageClass"`
 	Error          string `json:"error,omitempty"`
@@ -913,20 +1096,8 @@ func AddNode(w http.ResponseWriter, r *http.Request) {
 				return httpStatusCodeForAdd(err)
 			}
 
-			var csiFrontend frontend.Plugin
-			csiFrontend, err = orchestrator.GetFrontend(r.Context(), helpers.KubernetesHelper)
-			if err != nil {
-				csiFrontend, err = orchestrator.GetFrontend(r.Context(), helpers.PlainCSIHelper)
-			}
+			helper, err := getCSIHelperFrontend(r.Context())
 			if err != nil {
-				err = fmt.Errorf("could not get CSI helper frontend")
-				response.setError(err)
-				return httpStatusCodeForAdd(err)
-			}
-
-			helper, ok := csiFrontend.(helpers.HybridPlugin)
-			if !ok {
-				err = fmt.Errorf("could not get CSI hybrid frontend")
 				response.setError(err)
 				return httpStatusCodeForAdd(err)
 			}
@@ -1007,6 +1178,141 @@ func DeleteNode(w http.ResponseWriter, r *http.Request) {
 	DeleteGeneric(w, r, orchestrator.DeleteNode, "node")
 }
 
+// ListNodeVolumesResponse lists the volumes published to a node.  A node may only clean up volumes and
+// devices that are not in the list if the list is authoritative.  The LUN serial numbers of all iSCSI
+// volumes known to Trident identify which devices on the node belong to Trident.
+type ListNodeVolumesResponse struct {
+	Volumes       []string `json:"volumes"`
+	Authoritative bool     `json:"authoritative"`
+	LUNSerials    []string `json:"lunSerials"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// ListNodeVolumes returns the names of the volumes published to a node, which the node uses to find
+// volumes and devices left behind after they were unpublished.  The container orchestrator's record
+// of volume attachments is used if there is one.  Otherwise Trident's own record is used, which is only
+// authoritative once the published nodes of volumes published before Trident tracked them are known.
+func ListNodeVolumes(w http.ResponseWriter, r *http.Request) {
+	response := &ListNodeVolumesResponse{
+		Volumes:    make([]string, 0),
+		LUNSerials: make([]string, 0),
+	}
+	GetGeneric(w, r, "node", response,
+		func(nodeName string) int {
+			volumes, err := orchestrator.ListVolumes(r.Context())
+			if err != nil {
+				response.Error = err.Error()
+				return httpStatusCodeForGetUpdateList(err)
+			}
+
+			for _, volume := range volumes {
+				if volume.Config.AccessInfo.IscsiLunSerial != "" {
+					response.LUNSerials = append(response.LUNSerials, volume.Config.AccessInfo.IscsiLunSerial)
+				}
+			}
+
+			if helper, err := getCSIHelperFrontend(r.Context()); err == nil {
+				if attachedVolumes, err := helper.GetNodeAttachedVolumes(r.Context(), nodeName); err == nil {
+					response.Volumes = attachedVolumes
+					response.Authoritative = true
+					return http.StatusOK
+				} else if !utils.IsUnsupportedError(err) {
+					response.Error = err.Error()
+					return http.StatusInternalServerError
+				}
+			}
+
+			for _, volume := range volumes {
+				if utils.SliceContainsString(volume.PublishedNodes, nodeName) {
+					response.Volumes = append(response.Volumes, volume.Config.Name)
+				}
+			}
+			response.Authoritative = orchestrator.PublishedNodesBackfilled()
+			return http.StatusOK
+		},
+	)
+}
+
+// getCSIHelperFrontend returns the CSI helper frontend, which records events and
+// determines node topology for the container orchestrator in use.
+func getCSIHelperFrontend(ctx context.Context) (helpers.HybridPlugin, error) {
+
+	csiFrontend, err := orchestrator.GetFrontend(ctx, helpers.KubernetesHelper)
+	if err != nil {
+		csiFrontend, err = orchestrator.GetFrontend(ctx, helpers.PlainCSIHelper)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get CSI helper frontend")
+	}
+
+	helper, ok := csiFrontend.(helpers.HybridPlugin)
+	if !ok {
+		return nil, fmt.Errorf("could not get CSI hybrid frontend")
+	}
+	return helper, nil
+}
+
+type AddNodeEventResponse struct {
+	Node  string `json:"node"`
+	Error string `json:"error,omitempty"`
+}
+
+func (a *AddNodeEventResponse) setError(err error) {
+	a.Error = err.Error()
+}
+
+func (a *AddNodeEventResponse) isError() bool {
+	return a.Error != ""
+}
+
+func (a *AddNodeEventResponse) logSuccess(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "AddNodeEvent",
+		"node":    a.Node,
+	}).Debug("Recorded node event.")
+}
+
+func (a *AddNodeEventResponse) logFailure(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "AddNodeEvent",
+		"node":    a.Node,
+	}).Error(a.Error)
+}
+
+// AddNodeEvent records an event reported by a CSI node against that node, since
+// the node plugins have no access to the container orchestrator.
+func AddNodeEvent(w http.ResponseWriter, r *http.Request) {
+	response := &AddNodeEventResponse{}
+	UpdateGeneric(w, r, "node", response,
+		func(name string, body []byte) int {
+			response.Node = name
+
+			event := new(utils.NodeEvent)
+			err := json.Unmarshal(body, event)
+			if err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+
+			if _, err = orchestrator.GetNode(r.Context(), name); err != nil {
+				response.setError(err)
+				return httpStatusCodeForGetUpdateList(err)
+			}
+
+			helper, err := getCSIHelperFrontend(r.Context())
+			if err != nil {
+				response.setError(err)
+				return httpStatusCodeForAdd(err)
+			}
+
+			helper.RecordNodeEvent(r.Context(), name, event.Type, event.Reason, event.Message)
+			return httpStatusCodeForAdd(nil)
+		},
+	)
+}
+
 type GetSnapshotResponse struct {
 	Snapshot *storage.SnapshotExternal `json:"snapshot"`
 	Error    string                    `json:"error,omitempty"`
@@ -1133,3 +1439,214 @@ func AddSnapshot(w http.ResponseWriter, r *http.Request) {
 func DeleteSnapshot(w http.ResponseWriter, r *http.Request) {
 	DeleteGenericTwoArg(w, r, orchestrator.DeleteSnapshot, "volume", "snapshot")
 }
+
+type ExportStateRequest struct {
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+type ExportStateResponse struct {
+	Archive *persistentstore.StateArchive `json:"archive,omitempty"`
+	Error   string                        `json:"error,omitempty"`
+}
+
+func (r *ExportStateResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *ExportStateResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *ExportStateResponse) logSuccess(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"secrets": r.Archive.Secrets,
+		"handler": "ExportState",
+	}).Info("Exported the orchestrator state.")
+}
+
+func (r *ExportStateResponse) logFailure(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "ExportState",
+	}).Error(r.Error)
+}
+
+func ExportState(w http.ResponseWriter, r *http.Request) {
+	response := &ExportStateResponse{}
+	AddGeneric(w, r, response,
+		func(body []byte) int {
+			request := new(ExportStateRequest)
+			if len(body) > 0 {
+				if err := json.Unmarshal(body, request); err != nil {
+					response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+					return httpStatusCodeForGetUpdateList(err)
+				}
+			}
+			archive, err := orchestrator.ExportState(r.Context(), request.Passphrase)
+			if err != nil {
+				response.setError(err)
+			} else {
+				response.Archive = archive
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ImportStateRequest struct {
+	Passphrase string                        `json:"passphrase,omitempty"`
+	Archive    *persistentstore.StateArchive `json:"archive"`
+}
+
+type ImportStateResponse struct {
+	Backends int    `json:"backends"`
+	Volumes  int    `json:"volumes"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (r *ImportStateResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *ImportStateResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *ImportStateResponse) logSuccess(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"backends": r.Backends,
+		"volumes":  r.Volumes,
+		"handler":  "ImportState",
+	}).Info("Imported the orchestrator state.")
+}
+
+func (r *ImportStateResponse) logFailure(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "ImportState",
+	}).Error(r.Error)
+}
+
+func ImportState(w http.ResponseWriter, r *http.Request) {
+	response := &ImportStateResponse{}
+	addGenericWithLimit(w, r, response,
+		func(body []byte) int {
+			request := new(ImportStateRequest)
+			if err := json.Unmarshal(body, request); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForAdd(err)
+			}
+			if request.Archive == nil {
+				err := fmt.Errorf("no state archive was supplied")
+				response.setError(err)
+				return httpStatusCodeForAdd(err)
+			}
+			err := orchestrator.ImportState(r.Context(), request.Archive, request.Passphrase)
+			if err != nil {
+				response.setError(err)
+			} else {
+				response.Backends = len(request.Archive.Backends)
+				response.Volumes = len(request.Archive.Volumes)
+			}
+			return httpStatusCodeForAdd(err)
+		},
+		config.MaxStateArchiveSize,
+	)
+}
+
+type GetDriftReportResponse struct {
+	Report *storage.DriftReport `json:"report"`
+	Error  string               `json:"error,omitempty"`
+}
+
+func GetDriftReport(w http.ResponseWriter, r *http.Request) {
+	response := &GetDriftReportResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			refresh := r.URL.Query().Get("refresh") == "true"
+			report, err := orchestrator.GetDriftReport(r.Context(), refresh)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Report = report
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type DeleteOrphanedVolumesResponse struct {
+	Backend string               `json:"backend"`
+	Volumes []string             `json:"volumes"`
+	Report  *storage.DriftReport `json:"report"`
+	Error   string               `json:"error,omitempty"`
+}
+
+func (d *DeleteOrphanedVolumesResponse) setError(err error) {
+	d.Error = err.Error()
+}
+
+func (d *DeleteOrphanedVolumesResponse) isError() bool {
+	return d.Error != ""
+}
+
+func (d *DeleteOrphanedVolumesResponse) logSuccess(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "DeleteOrphanedVolumes",
+		"backend": d.Backend,
+		"volumes": d.Volumes,
+	}).Info("Deleted orphaned volumes.")
+}
+
+func (d *DeleteOrphanedVolumesResponse) logFailure(ctx context.Context) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "DeleteOrphanedVolumes",
+	}).Error(d.Error)
+}
+
+func DeleteOrphanedVolumes(w http.ResponseWriter, r *http.Request) {
+	response := &DeleteOrphanedVolumesResponse{}
+	UpdateGeneric(w, r, "backend", response,
+		func(backendName string, body []byte) int {
+			request := new(storage.OrphanCleanupRequest)
+			if err := json.Unmarshal(body, request); err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			response.Backend = backendName
+			response.Volumes = request.Volumes
+			report, err := orchestrator.DeleteOrphanedVolumes(r.Context(), backendName, request.Volumes,
+				request.Confirm)
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Report = report
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ListOperationsResponse struct {
+	Operations []*storage.OperationExternal `json:"operations"`
+	Error      string                       `json:"error,omitempty"`
+}
+
+func ListOperations(w http.ResponseWriter, r *http.Request) {
+	response := &ListOperationsResponse{}
+	GetGenericNoArg(w, r, response,
+		func() int {
+			operations, err := orchestrator.ListOperations(r.Context())
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Operations = operations
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
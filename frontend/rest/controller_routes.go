@@ -49,6 +49,18 @@ var controllerRoutes = Routes{
 		config.BackendURL + "/{backend}" + "/state",
 		UpdateBackendState,
 	},
+	Route{
+		"GetBackendVolumeReport",
+		"GET",
+		config.BackendURL + "/{backend}" + "/volumes",
+		GetBackendVolumeReport,
+	},
+	Route{
+		"DrainBackend",
+		"POST",
+		config.BackendURL + "/{backend}" + "/drain",
+		DrainBackend,
+	},
 	Route{
 		"GetBackend",
 		"GET",
@@ -97,12 +109,24 @@ var controllerRoutes = Routes{
 		config.VolumeURL + "/import",
 		ImportVolume,
 	},
+	Route{
+		"UpdateVolume",
+		"POST",
+		config.VolumeURL + "/{volume}",
+		UpdateVolume,
+	},
 	Route{
 		"UpgradeVolume",
 		"POST",
 		config.VolumeURL + "/{volume}/upgrade",
 		UpgradeVolume,
 	},
+	Route{
+		"RelocateVolume",
+		"POST",
+		config.VolumeURL + "/{volume}/relocate",
+		RelocateVolume,
+	},
 	Route{
 		"AddStorageClass",
 		"POST",
@@ -151,6 +175,18 @@ var controllerRoutes = Routes{
 		config.NodeURL + "/{node}",
 		DeleteNode,
 	},
+	Route{
+		"ListNodeVolumes",
+		"GET",
+		config.NodeURL + "/{node}/volume",
+		ListNodeVolumes,
+	},
+	Route{
+		"AddNodeEvent",
+		"POST",
+		config.NodeURL + "/{node}/event",
+		AddNodeEvent,
+	},
 	Route{
 		"ListSnapshots",
 		"GET",
@@ -181,4 +217,34 @@ var controllerRoutes = Routes{
 		config.SnapshotURL + "/{volume}/{snapshot}",
 		DeleteSnapshot,
 	},
+	Route{
+		"ExportState",
+		"POST",
+		config.StateURL + "/export",
+		ExportState,
+	},
+	Route{
+		"ImportState",
+		"POST",
+		config.StateURL + "/import",
+		ImportState,
+	},
+	Route{
+		"GetDriftReport",
+		"GET",
+		config.DriftURL,
+		GetDriftReport,
+	},
+	Route{
+		"DeleteOrphanedVolumes",
+		"POST",
+		config.DriftURL + "/{backend}" + "/cleanup",
+		DeleteOrphanedVolumes,
+	},
+	Route{
+		"ListOperations",
+		"GET",
+		config.OperationURL,
+		ListOperations,
+	},
 }
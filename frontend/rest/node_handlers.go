@@ -27,3 +27,29 @@ func NodeReadinessCheck(plugin *csi.Plugin) http.HandlerFunc {
 		}
 	}
 }
+
+type NodeReconciliationReportResponse struct {
+	Report *csi.NodeReconciliationReport `json:"report,omitempty"`
+	Error  string                        `json:"error,omitempty"`
+}
+
+// Node endpoint reporting the stale volumes and orphaned devices that reconciliation would clean up.
+// The reconciliation is always a dry run here; the cleanup itself happens only in the background.
+func NodeReconciliationReport(plugin *csi.Plugin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+
+		response := &NodeReconciliationReportResponse{}
+		httpStatusCode := http.StatusOK
+
+		report, err := plugin.ReconcileNodeVolumes(r.Context(), true)
+		if err != nil {
+			response.Error = err.Error()
+			httpStatusCode = http.StatusInternalServerError
+		} else {
+			response.Report = report
+		}
+
+		writeHTTPResponse(r.Context(), w, response, httpStatusCode)
+	}
+}
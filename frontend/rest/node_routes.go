@@ -20,5 +20,11 @@ func nodeRoutes(plugin *csi.Plugin) []Route {
 			"/readiness",
 			NodeReadinessCheck(plugin),
 		},
+		Route{
+			"NodeReconciliationReport",
+			"GET",
+			"/reconciliation",
+			NodeReconciliationReport(plugin),
+		},
 	}
 }
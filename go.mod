@@ -33,6 +33,7 @@ require (
 	github.com/stretchr/testify v1.7.0
 	github.com/vishvananda/netlink v1.1.0
 	github.com/zcalusic/sysinfo v0.0.0-20210226105846-b810d137e525
+	go.etcd.io/bbolt v1.3.5
 	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2 // github.com/golang/crypto
 	golang.org/x/oauth2 v0.0.0-20210413134643-5e61552d6c78 // github.com/golang/oauth2
 	golang.org/x/sys v0.0.0-20210412220455-f1c623a9e750 // github.com/golang/sys
@@ -0,0 +1,96 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package leaderelection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	k8sleaderelection "k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/netapp/trident/config"
+)
+
+const (
+	DefaultLeaseDuration = 15 * time.Second
+	DefaultRenewDeadline = 10 * time.Second
+	DefaultRetryPeriod   = 2 * time.Second
+)
+
+// Callbacks are invoked as leadership of the Trident controller changes hands.  OnStartedLeading is
+// called in its own goroutine with a context that is cancelled when leadership is lost.
+// OnStoppedLeading is called when Run returns, whether or not this instance was ever the leader.
+// OnNewLeader is called with the identity of each new leader, including this instance.
+type Callbacks struct {
+	OnStartedLeading func(ctx context.Context)
+	OnStoppedLeading func()
+	OnNewLeader      func(identity string)
+}
+
+// Elector elects one leader among several Trident controller replicas using a Lease object in
+// Trident's namespace.  Changes of leadership are only reported through the callbacks.
+type Elector struct {
+	elector *k8sleaderelection.LeaderElector
+}
+
+// NewElector returns an Elector that campaigns for the Trident controller lease as the specified
+// identity, which must be unique among the replicas.
+func NewElector(
+	kubeClient kubernetes.Interface, namespace, identity string, leaseDuration, renewDeadline,
+	retryPeriod time.Duration, callbacks Callbacks,
+) (*Elector, error) {
+
+	if identity == "" {
+		return nil, fmt.Errorf("leader election identity must not be empty")
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      config.LeaderElectionLeaseName,
+			Namespace: namespace,
+		},
+		Client: kubeClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	elector, err := k8sleaderelection.NewLeaderElector(k8sleaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: leaseDuration,
+		RenewDeadline: renewDeadline,
+		RetryPeriod:   retryPeriod,
+		Callbacks: k8sleaderelection.LeaderCallbacks{
+			OnStartedLeading: callbacks.OnStartedLeading,
+			OnStoppedLeading: callbacks.OnStoppedLeading,
+			OnNewLeader:      callbacks.OnNewLeader,
+		},
+		// Give up the lease on shutdown so that a standby can take over without waiting for it to expire
+		ReleaseOnCancel: true,
+		Name:            config.LeaderElectionLeaseName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.WithFields(log.Fields{
+		"lease":         config.LeaderElectionLeaseName,
+		"namespace":     namespace,
+		"identity":      identity,
+		"leaseDuration": leaseDuration,
+		"renewDeadline": renewDeadline,
+		"retryPeriod":   retryPeriod,
+	}).Info("Initialized leader election.")
+
+	return &Elector{elector: elector}, nil
+}
+
+// Run campaigns for the lease and blocks until the context is cancelled or leadership is lost.
+func (e *Elector) Run(ctx context.Context) {
+	e.elector.Run(ctx)
+}
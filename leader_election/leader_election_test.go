@@ -0,0 +1,88 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package leaderelection
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	testLeaseDuration = 2 * time.Second
+	testRenewDeadline = 1 * time.Second
+	testRetryPeriod   = 100 * time.Millisecond
+)
+
+func newTestElector(
+	t *testing.T, kubeClient *fake.Clientset, identity string, leading chan<- string, leader *atomic.Value,
+) *Elector {
+
+	elector, err := NewElector(kubeClient, "trident", identity, testLeaseDuration, testRenewDeadline,
+		testRetryPeriod, Callbacks{
+			OnStartedLeading: func(ctx context.Context) { leading <- identity },
+			OnStoppedLeading: func() {},
+			OnNewLeader:      func(identity string) { leader.Store(identity) },
+		})
+	assert.NoError(t, err)
+	return elector
+}
+
+func TestNewElector_NoIdentity(t *testing.T) {
+
+	_, err := NewElector(fake.NewSimpleClientset(), "trident", "", testLeaseDuration, testRenewDeadline,
+		testRetryPeriod, Callbacks{})
+	assert.Error(t, err)
+}
+
+func TestElector_Failover(t *testing.T) {
+
+	kubeClient := fake.NewSimpleClientset()
+	leading := make(chan string, 2)
+
+	var firstLeader, secondLeader atomic.Value
+
+	first := newTestElector(t, kubeClient, "trident-csi-1", leading, &firstLeader)
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		first.Run(firstCtx)
+		close(firstDone)
+	}()
+
+	select {
+	case identity := <-leading:
+		assert.Equal(t, "trident-csi-1", identity)
+	case <-time.After(10 * time.Second):
+		t.Fatal("First elector was not elected.")
+	}
+	assert.Eventually(t, func() bool { return firstLeader.Load() == "trident-csi-1" },
+		10*time.Second, 10*time.Millisecond)
+
+	second := newTestElector(t, kubeClient, "trident-csi-2", leading, &secondLeader)
+	secondCtx, cancelSecond := context.WithCancel(context.Background())
+	defer cancelSecond()
+	go second.Run(secondCtx)
+
+	// The standby observes the leader but does not take over while the lease is renewed
+	assert.Eventually(t, func() bool { return secondLeader.Load() == "trident-csi-1" },
+		10*time.Second, 10*time.Millisecond)
+	assert.Len(t, leading, 0)
+
+	// The leader releases the lease on shutdown, and the standby takes over
+	cancelFirst()
+	<-firstDone
+
+	select {
+	case identity := <-leading:
+		assert.Equal(t, "trident-csi-2", identity)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Standby elector was not elected.")
+	}
+	assert.Eventually(t, func() bool { return secondLeader.Load() == "trident-csi-2" },
+		10*time.Second, 10*time.Millisecond)
+}
@@ -3,19 +3,24 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	kubeclient "k8s.io/client-go/kubernetes"
+	k8srest "k8s.io/client-go/rest"
 
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/core"
@@ -29,6 +34,7 @@ import (
 	"github.com/netapp/trident/frontend/kubernetes"
 	"github.com/netapp/trident/frontend/metrics"
 	"github.com/netapp/trident/frontend/rest"
+	leaderelection "github.com/netapp/trident/leader_election"
 	"github.com/netapp/trident/logging"
 	persistentstore "github.com/netapp/trident/persistent_store"
 )
@@ -63,12 +69,31 @@ var (
 
 	nodePrep = flag.Bool("node_prep", true, "Attempt to install required packages on nodes.")
 
+	iSCSISelfHealingInterval = flag.Duration("iscsi_self_healing_interval", csi.DefaultISCSISelfHealingInterval,
+		"Interval at which staged iSCSI volumes are checked for missing sessions and paths (0 to disable)")
+	nodeReconcileInterval = flag.Duration("node_reconcile_interval", csi.DefaultNodeReconcileInterval,
+		"Interval at which volumes and devices no longer published to the node are cleaned up (0 to disable)")
+
+	// Leader election
+	enableLeaderElection = flag.Bool("enable_leader_election", false, "Elect a leader among several CSI "+
+		"controller replicas, with the others standing by to take over from it")
+	leaderElectionLeaseDuration = flag.Duration("leader_election_lease_duration",
+		leaderelection.DefaultLeaseDuration, "Duration for which standbys wait before taking over from a "+
+			"leader that stopped renewing its lease")
+	leaderElectionRenewDeadline = flag.Duration("leader_election_renew_deadline",
+		leaderelection.DefaultRenewDeadline, "Duration for which the leader tries to renew its lease "+
+			"before giving up leadership")
+	leaderElectionRetryPeriod = flag.Duration("leader_election_retry_period",
+		leaderelection.DefaultRetryPeriod, "Interval between attempts to acquire or renew the lease")
+
 	// Persistence
 	useInMemory = flag.Bool("no_persistence", false, "Does not persist "+
 		"any metadata.  WILL LOSE TRACK OF VOLUMES ON REBOOT/CRASH.")
 	usePassthrough = flag.Bool("passthrough", false, "Uses the storage backends "+
 		"as the source of truth.  No data is stored anywhere else.")
 	useCRD = flag.Bool("crd_persistence", false, "Uses CRDs for persisting orchestrator state.")
+	boltDB = flag.String("bolt_persistence", "", "Uses an embedded database at this path for "+
+		"persisting orchestrator state.")
 
 	// HTTP REST interface
 	address    = flag.String("address", "127.0.0.1", "Storage orchestrator HTTP API address")
@@ -144,6 +169,9 @@ func processCmdLineArgs() {
 	if *useCRD {
 		storeCount++
 	}
+	if *boltDB != "" {
+		storeCount++
+	}
 	// Infer persistent store type if not explicitly specified
 	if storeCount == 0 && enableDocker {
 		log.Debug("Inferred passthrough persistent store.")
@@ -176,9 +204,193 @@ func processCmdLineArgs() {
 		if err != nil {
 			log.Fatalf("Unable to create the Kubernetes store client. %v", err)
 		}
+
+	case *boltDB != "":
+		log.Debug("Trident is configured with a bolt store client.")
+
+		// Opening the store updates its modification time, so it is compared with the config first
+		if *configPath != "" {
+			warnIfConfigNewerThanStore(*configPath, *boltDB)
+		}
+
+		storeClient, err = persistentstore.NewBoltClient(*boltDB)
+		if err != nil {
+			log.Fatalf("Unable to create the bolt store client. %v", err)
+		}
+
+		// Deployments that used the passthrough store, which reads the config path, move from it the
+		// first time the bolt store is used, whatever their frontend.  The config is import-only after that.
+		if *configPath != "" {
+			passthroughClient, err := persistentstore.NewPassthroughClient(*configPath)
+			if err != nil {
+				log.Fatalf("Unable to create the passthrough store client. %v", err)
+			}
+			if err = persistentstore.NewDataMigrator(passthroughClient, storeClient, false).Run(); err != nil {
+				log.Fatalf("Unable to migrate from the passthrough store. %v", err)
+			}
+		}
 	}
 
 	config.UsingPassthroughStore = storeClient.GetType() == persistentstore.PassthroughStore
+
+	if *enableLeaderElection && (!enableCSI || *csiRole != csi.CSIController || !*useCRD || !*k8sPod) {
+		log.Fatal("Leader election is only supported by the CSI controller with CRD persistence in a pod.")
+	}
+}
+
+// warnIfConfigNewerThanStore logs a warning if any file at the config path was modified after the
+// bolt store was last written, since the config is only imported the first time the store is used.
+func warnIfConfigNewerThanStore(configPath, storePath string) {
+
+	storeInfo, err := os.Stat(storePath)
+	if err != nil {
+		// A new store imports the config, so there is nothing to ignore
+		return
+	}
+
+	configInfo, err := os.Stat(configPath)
+	if err != nil {
+		return
+	}
+	configFiles := []os.FileInfo{configInfo}
+	if configInfo.IsDir() {
+		if configFiles, err = ioutil.ReadDir(configPath); err != nil {
+			return
+		}
+	}
+
+	for _, configFile := range configFiles {
+		if configFile.Mode().IsRegular() && configFile.ModTime().After(storeInfo.ModTime()) {
+			log.WithFields(log.Fields{
+				"configPath": configPath,
+				"store":      storePath,
+			}).Warning("The config was modified after the bolt store was last written. The config is only " +
+				"imported the first time the store is used, so the changes are ignored. Update the backends " +
+				"and storage classes with tridentctl or the REST API instead.")
+			return
+		}
+	}
+}
+
+// startLeaderElection campaigns for leadership of the Trident controller replicas.  Until it is
+// elected, this instance keeps a warm cache of the persistent state and rejects requests with an
+// error naming the leader.  Once elected, it bootstraps the orchestrator from that cache and
+// activates the frontends that start after bootstrapping.  Losing the lease ends the process, so
+// that a former leader never writes alongside a new one; Kubernetes restarts it as a standby.
+//
+// The first returned function ensures the frontends will not be activated, and reports whether they
+// were.  The second one gives up the lease.
+func startLeaderElection(
+	orchestrator *core.TridentOrchestrator, postBootstrapFrontends []frontend.Plugin,
+) (func() bool, func()) {
+
+	kubeConfig, err := k8srest.InClusterConfig()
+	if err != nil {
+		log.Fatalf("Unable to get the Kubernetes configuration for leader election. %v", err)
+	}
+	kubeClient, err := kubeclient.NewForConfig(kubeConfig)
+	if err != nil {
+		log.Fatalf("Unable to create the Kubernetes client for leader election. %v", err)
+	}
+	namespaceBytes, err := ioutil.ReadFile(config.TridentNamespaceFile)
+	if err != nil {
+		log.Fatalf("Unable to get Trident's namespace for leader election. %v", err)
+	}
+	// The hostname of a container is the name of its pod, which is unique among the replicas
+	identity, err := os.Hostname()
+	if err != nil {
+		log.Fatalf("Unable to get the identity for leader election. %v", err)
+	}
+
+	orchestrator.SetStandby("")
+
+	// Follow changes to the persistent state, so that bootstrapping after being elected needn't read
+	// it all from the API server.  The cache can't be behind a former leader's final writes, since
+	// a leader gives up before its renew deadline, and a standby waits out the longer lease duration.
+	// A leader that shuts down releases its lease only once it has stopped writing, and a standby
+	// takes over no sooner than its next retry, which leaves the watches time to deliver those writes.
+	crdClient, _ := storeClient.(*persistentstore.CRDClientV1)
+	if crdClient != nil {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), *leaderElectionLeaseDuration*10)
+		if err = crdClient.StartWatchCache(cacheCtx); err != nil {
+			log.Warningf("Unable to start the CRD watch cache; the persistent state will be read from "+
+				"the API server when this instance is elected. %v", err)
+		}
+		cancel()
+	}
+
+	var mutex sync.Mutex
+	activated := false
+	halted := false
+
+	electionCtx, cancelElection := context.WithCancel(context.Background())
+	electionDone := make(chan struct{})
+
+	callbacks := leaderelection.Callbacks{
+		OnStartedLeading: func(ctx context.Context) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if halted {
+				return
+			}
+
+			log.WithField("identity", identity).Info("Elected leader of the Trident controller replicas.")
+
+			if err := orchestrator.Bootstrap(); err != nil {
+				log.Error(err.Error())
+			}
+			// The leader's own writes must be read back from the API server from now on
+			if crdClient != nil {
+				crdClient.StopWatchCache(ctx)
+			}
+			for _, f := range postBootstrapFrontends {
+				if err := f.Activate(); err != nil {
+					log.Error(err)
+				}
+			}
+			activated = true
+		},
+		OnStoppedLeading: func() {
+			if electionCtx.Err() != nil {
+				// Shutting down
+				return
+			}
+			log.Fatal("Lost leadership of the Trident controller replicas, exiting.")
+		},
+		OnNewLeader: func(leader string) {
+			if leader == identity {
+				return
+			}
+			log.WithField("leader", leader).Info("Standing by for the leader of the Trident controller replicas.")
+			orchestrator.SetStandby(leader)
+		},
+	}
+
+	elector, err := leaderelection.NewElector(kubeClient, string(namespaceBytes), identity,
+		*leaderElectionLeaseDuration, *leaderElectionRenewDeadline, *leaderElectionRetryPeriod, callbacks)
+	if err != nil {
+		log.Fatalf("Unable to start leader election. %v", err)
+	}
+
+	go func() {
+		elector.Run(electionCtx)
+		close(electionDone)
+	}()
+
+	halt := func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		halted = true
+		return activated
+	}
+
+	release := func() {
+		cancelElection()
+		<-electionDone
+	}
+
+	return halt, release
 }
 
 // getenvAsPointerToBool returns the key's value and defaults to false if not set
@@ -227,6 +439,13 @@ func processDockerPluginArgs() error {
 		}
 		configPath = &configFile
 	}
+	if storeEnv := os.Getenv("store"); storeEnv != "" {
+		storeFile := storeEnv
+		if !strings.HasPrefix(storeFile, config.DockerPluginConfigLocation) {
+			storeFile = filepath.Join(config.DockerPluginConfigLocation, storeEnv)
+		}
+		boltDB = &storeFile
+	}
 	return nil
 }
 
@@ -382,12 +601,14 @@ func main() {
 			csiFrontend, err = csi.NewControllerPlugin(*csiNodeName, *csiEndpoint, *aesKey, orchestrator, &hybridPlugin)
 		case csi.CSINode:
 			csiFrontend, err = csi.NewNodePlugin(*csiNodeName, *csiEndpoint, *httpsCACert, *httpsClientCert,
-				*httpsClientKey, *aesKey, orchestrator, *csiUnsafeNodeDetach, *nodePrep)
+				*httpsClientKey, *aesKey, orchestrator, *csiUnsafeNodeDetach, *nodePrep, *iSCSISelfHealingInterval,
+				*nodeReconcileInterval)
 			enableMutualTLS = false
 			handler = rest.NewNodeRouter(csiFrontend)
 		case csi.CSIAllInOne:
 			csiFrontend, err = csi.NewAllInOnePlugin(*csiNodeName, *csiEndpoint, *httpsCACert, *httpsClientCert,
-				*httpsClientKey, *aesKey, orchestrator, &hybridPlugin, *csiUnsafeNodeDetach, *nodePrep)
+				*httpsClientKey, *aesKey, orchestrator, &hybridPlugin, *csiUnsafeNodeDetach, *nodePrep,
+				*iSCSISelfHealingInterval, *nodeReconcileInterval)
 		}
 		if err != nil {
 			log.Fatalf("Unable to start the CSI frontend. %v", err)
@@ -440,18 +661,24 @@ func main() {
 	// Bootstrap the orchestrator and start its frontends.  Some frontends, notably REST and Docker, must
 	// start before the core so that the external interfaces are minimally responding while the core is
 	// still initializing.  Other frontends such as legacy Kubernetes and CSI benefit from starting after
-	// the core is ready.
+	// the core is ready.  With leader election, only the elected leader bootstraps and starts the latter.
 	for _, f := range preBootstrapFrontends {
 		if err := f.Activate(); err != nil {
 			log.Error(err)
 		}
 	}
-	if err = orchestrator.Bootstrap(); err != nil {
-		log.Error(err.Error())
-	}
-	for _, f := range postBootstrapFrontends {
-		if err := f.Activate(); err != nil {
-			log.Error(err)
+	var haltLeaderElection func() bool
+	var releaseLease func()
+	if *enableLeaderElection {
+		haltLeaderElection, releaseLease = startLeaderElection(orchestrator, postBootstrapFrontends)
+	} else {
+		if err = orchestrator.Bootstrap(); err != nil {
+			log.Error(err.Error())
+		}
+		for _, f := range postBootstrapFrontends {
+			if err := f.Activate(); err != nil {
+				log.Error(err)
+			}
 		}
 	}
 
@@ -460,9 +687,11 @@ func main() {
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	<-c
 	log.Info("Shutting down.")
-	for _, f := range postBootstrapFrontends {
-		if err := f.Deactivate(); err != nil {
-			log.Error(err)
+	if haltLeaderElection == nil || haltLeaderElection() {
+		for _, f := range postBootstrapFrontends {
+			if err := f.Deactivate(); err != nil {
+				log.Error(err)
+			}
 		}
 	}
 	orchestrator.Stop()
@@ -471,6 +700,10 @@ func main() {
 			log.Error(err)
 		}
 	}
+	// Give up the lease only after this instance has stopped writing
+	if releaseLease != nil {
+		releaseLease()
+	}
 	if err = storeClient.Stop(); err != nil {
 		log.Error(err)
 	}
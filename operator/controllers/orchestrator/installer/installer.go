@@ -30,14 +30,16 @@ import (
 
 const (
 	// CRD names
-	BackendCRDName       = "tridentbackends.trident.netapp.io"
-	BackendConfigCRDName = "tridentbackendconfigs.trident.netapp.io"
-	NodeCRDName          = "tridentnodes.trident.netapp.io"
-	StorageClassCRDName  = "tridentstorageclasses.trident.netapp.io"
-	TransactionCRDName   = "tridenttransactions.trident.netapp.io"
-	VersionCRDName       = "tridentversions.trident.netapp.io"
-	VolumeCRDName        = "tridentvolumes.trident.netapp.io"
-	SnapshotCRDName      = "tridentsnapshots.trident.netapp.io"
+	BackendCRDName            = "tridentbackends.trident.netapp.io"
+	BackendConfigCRDName      = "tridentbackendconfigs.trident.netapp.io"
+	MirrorRelationshipCRDName = "tridentmirrorrelationships.trident.netapp.io"
+	NodeCRDName               = "tridentnodes.trident.netapp.io"
+	StorageClassCRDName       = "tridentstorageclasses.trident.netapp.io"
+	TransactionCRDName        = "tridenttransactions.trident.netapp.io"
+	VersionCRDName            = "tridentversions.trident.netapp.io"
+	VolumeCRDName             = "tridentvolumes.trident.netapp.io"
+	SnapshotCRDName           = "tridentsnapshots.trident.netapp.io"
+	SnapshotPolicyCRDName     = "tridentsnapshotpolicies.trident.netapp.io"
 
 	VolumeSnapshotCRDName        = "volumesnapshots.snapshot.storage.k8s.io"
 	VolumeSnapshotClassCRDName   = "volumesnapshotclasses.snapshot.storage.k8s.io"
@@ -82,12 +84,14 @@ var (
 	CRDnames = []string{
 		BackendCRDName,
 		BackendConfigCRDName,
+		MirrorRelationshipCRDName,
 		NodeCRDName,
 		StorageClassCRDName,
 		TransactionCRDName,
 		VersionCRDName,
 		VolumeCRDName,
 		SnapshotCRDName,
+		SnapshotPolicyCRDName,
 	}
 
 	AlphaCRDNames = []string{
@@ -593,6 +597,9 @@ func (i *Installer) createCRDs() error {
 	if err = i.CreateCRD(BackendConfigCRDName, k8sclient.GetBackendConfigCRDYAML(useCRDv1)); err != nil {
 		return err
 	}
+	if err = i.CreateCRD(MirrorRelationshipCRDName, k8sclient.GetMirrorRelationshipCRDYAML(useCRDv1)); err != nil {
+		return err
+	}
 	if err = i.CreateCRD(StorageClassCRDName, k8sclient.GetStorageClassCRDYAML(useCRDv1)); err != nil {
 		return err
 	}
@@ -608,6 +615,9 @@ func (i *Installer) createCRDs() error {
 	if err = i.CreateCRD(SnapshotCRDName, k8sclient.GetSnapshotCRDYAML(useCRDv1)); err != nil {
 		return err
 	}
+	if err = i.CreateCRD(SnapshotPolicyCRDName, k8sclient.GetSnapshotPolicyCRDYAML(useCRDv1)); err != nil {
+		return err
+	}
 
 	return err
 }
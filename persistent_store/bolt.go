@@ -0,0 +1,546 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/netapp/trident/config"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	sc "github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// boltOpenTimeout limits how long to wait for the database file lock, which is held by
+	// any other process that has the same database open.
+	boltOpenTimeout = 10 * time.Second
+
+	boltVersionKey = "version"
+)
+
+var (
+	boltMetadataBucket           = []byte("metadata")
+	boltBackendsBucket           = []byte("backends")
+	boltVolumesBucket            = []byte("volumes")
+	boltVolumeTransactionsBucket = []byte("volumeTransactions")
+	boltStorageClassesBucket     = []byte("storageClasses")
+	boltNodesBucket              = []byte("nodes")
+	boltSnapshotsBucket          = []byte("snapshots")
+
+	boltBuckets = [][]byte{
+		boltMetadataBucket, boltBackendsBucket, boltVolumesBucket, boltVolumeTransactionsBucket,
+		boltStorageClassesBucket, boltNodesBucket, boltSnapshotsBucket,
+	}
+)
+
+// BoltClient is a persistent store client that keeps the orchestrator's state in a bbolt
+// database file on the local host.  It is intended for standalone deployments, such as Docker
+// or plain CSI, that have no Kubernetes API server in which to store CRDs.  Each object is
+// stored as JSON in a bucket for its type, keyed by the same name used by the other stores.
+type BoltClient struct {
+	db *bolt.DB
+}
+
+// NewBoltClient opens the bbolt database at the specified path, creating it if necessary.
+func NewBoltClient(path string) (*BoltClient, error) {
+
+	ctx := GenerateRequestContext(nil, "", ContextSourceInternal)
+
+	if path == "" {
+		return nil, fmt.Errorf("bolt store initialization failed, database path must be specified")
+	}
+
+	// Backends are stored with their credentials, so keep the database private
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("could not create directory for bolt store; %v", err)
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("could not open bolt store at %s; %v", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range boltBuckets {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not initialize bolt store at %s; %v", path, err)
+	}
+
+	Logc(ctx).WithField("path", path).Debug("Opened bolt store.")
+
+	return &BoltClient{db: db}, nil
+}
+
+// get reads a single object from a bucket, returning a not found error if the key does not exist.
+func (c *BoltClient) get(bucket []byte, key string, object interface{}) error {
+	return c.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucket).Get([]byte(key))
+		if value == nil {
+			return NewPersistentStoreError(KeyNotFoundErr, key)
+		}
+		return json.Unmarshal(value, object)
+	})
+}
+
+// put writes a single object to a bucket.  If mustExist is set, the key must already exist, and if
+// mustNotExist is set, it must not.
+func (c *BoltClient) put(bucket []byte, key string, object interface{}, mustExist, mustNotExist bool) error {
+
+	value, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		exists := b.Get([]byte(key)) != nil
+		if mustExist && !exists {
+			return NewPersistentStoreError(KeyNotFoundErr, key)
+		} else if mustNotExist && exists {
+			return fmt.Errorf("%s already exists in %s", key, bucket)
+		}
+		return b.Put([]byte(key), value)
+	})
+}
+
+// delete removes a single object from a bucket.  If mustExist is set, the key must already exist.
+func (c *BoltClient) delete(bucket []byte, key string, mustExist bool) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if mustExist && b.Get([]byte(key)) == nil {
+			return NewPersistentStoreError(KeyNotFoundErr, key)
+		}
+		return b.Delete([]byte(key))
+	})
+}
+
+// list calls the supplied function with each value in a bucket.
+func (c *BoltClient) list(bucket []byte, f func(value []byte) error) error {
+	return c.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(_, value []byte) error {
+			return f(value)
+		})
+	})
+}
+
+// deleteAll removes every object from a bucket.
+func (c *BoltClient) deleteAll(bucket []byte) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(bucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucket(bucket)
+		return err
+	})
+}
+
+func (c *BoltClient) GetType() StoreType {
+	return BoltStore
+}
+
+func (c *BoltClient) Stop() error {
+	return c.db.Close()
+}
+
+func (c *BoltClient) GetConfig() *ClientConfig {
+	return &ClientConfig{}
+}
+
+func (c *BoltClient) GetVersion(context.Context) (*config.PersistentStateVersion, error) {
+
+	version := &config.PersistentStateVersion{}
+	if err := c.get(boltMetadataBucket, boltVersionKey, version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func (c *BoltClient) SetVersion(ctx context.Context, version *config.PersistentStateVersion) error {
+
+	if err := c.put(boltMetadataBucket, boltVersionKey, version, false, false); err != nil {
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"PersistentStoreVersion": version.PersistentStoreVersion,
+		"OrchestratorAPIVersion": version.OrchestratorAPIVersion,
+	}).Debug("Set persistent state version.")
+
+	return nil
+}
+
+func (c *BoltClient) AddBackend(ctx context.Context, b *storage.Backend) error {
+	return c.AddBackendPersistent(ctx, b.ConstructPersistent(ctx))
+}
+
+func (c *BoltClient) AddBackendPersistent(_ context.Context, backend *storage.BackendPersistent) error {
+	return c.put(boltBackendsBucket, backend.Name, backend, false, true)
+}
+
+func (c *BoltClient) GetBackend(_ context.Context, backendName string) (*storage.BackendPersistent, error) {
+
+	backend := &storage.BackendPersistent{}
+	if err := c.get(boltBackendsBucket, backendName, backend); err != nil {
+		return nil, err
+	}
+	return backend, nil
+}
+
+func (c *BoltClient) GetBackendSecret(_ context.Context, _ string) (map[string]string, error) {
+	return nil, nil
+}
+
+func (c *BoltClient) UpdateBackend(ctx context.Context, b *storage.Backend) error {
+	return c.UpdateBackendPersistent(ctx, b.ConstructPersistent(ctx))
+}
+
+// UpdateBackendPersistent updates a backend's persistent state
+func (c *BoltClient) UpdateBackendPersistent(_ context.Context, update *storage.BackendPersistent) error {
+	return c.put(boltBackendsBucket, update.Name, update, true, false)
+}
+
+func (c *BoltClient) DeleteBackend(_ context.Context, b *storage.Backend) error {
+	return c.delete(boltBackendsBucket, b.Name, true)
+}
+
+func (c *BoltClient) IsBackendDeleting(context.Context, *storage.Backend) bool {
+	return false
+}
+
+// ReplaceBackendAndUpdateVolumes renames a backend and updates all volumes to
+// reflect the new backend UUID.  All changes are made in a single database
+// transaction, so either all or none of them are persisted.
+func (c *BoltClient) ReplaceBackendAndUpdateVolumes(
+	ctx context.Context, origBackend, newBackend *storage.Backend,
+) error {
+
+	Logc(ctx).WithFields(log.Fields{
+		"origBackend.Name":        origBackend.Name,
+		"origBackend.BackendUUID": origBackend.BackendUUID,
+		"newBackend.Name":         newBackend.Name,
+		"newBackend.BackendUUID":  newBackend.BackendUUID,
+	}).Debug("ReplaceBackendAndUpdateVolumes.")
+
+	newBackendJSON, err := json.Marshal(newBackend.ConstructPersistent(ctx))
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+
+		backends := tx.Bucket(boltBackendsBucket)
+		if backends.Get([]byte(origBackend.Name)) == nil {
+			return NewPersistentStoreError(KeyNotFoundErr, origBackend.Name)
+		}
+		if err := backends.Delete([]byte(origBackend.Name)); err != nil {
+			return err
+		}
+		if err := backends.Put([]byte(newBackend.Name), newBackendJSON); err != nil {
+			return err
+		}
+
+		if origBackend.BackendUUID == newBackend.BackendUUID {
+			return nil
+		}
+
+		volumes := tx.Bucket(boltVolumesBucket)
+		updates := make(map[string][]byte)
+		err := volumes.ForEach(func(key, value []byte) error {
+			volume := &storage.VolumeExternal{}
+			if err := json.Unmarshal(value, volume); err != nil {
+				return err
+			}
+			if volume.BackendUUID != origBackend.BackendUUID {
+				return nil
+			}
+			volume.BackendUUID = newBackend.BackendUUID
+			volumeJSON, err := json.Marshal(volume)
+			if err != nil {
+				return err
+			}
+			updates[string(key)] = volumeJSON
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Buckets may not be modified while iterating over them
+		for key, value := range updates {
+			if err := volumes.Put([]byte(key), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *BoltClient) GetBackends(context.Context) ([]*storage.BackendPersistent, error) {
+
+	backendList := make([]*storage.BackendPersistent, 0)
+	err := c.list(boltBackendsBucket, func(value []byte) error {
+		backend := &storage.BackendPersistent{}
+		if err := json.Unmarshal(value, backend); err != nil {
+			return err
+		}
+		backendList = append(backendList, backend)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backendList, nil
+}
+
+func (c *BoltClient) DeleteBackends(context.Context) error {
+	return c.deleteAll(boltBackendsBucket)
+}
+
+func (c *BoltClient) AddVolume(ctx context.Context, vol *storage.Volume) error {
+	return c.AddVolumePersistent(ctx, vol.ConstructExternal())
+}
+
+// AddVolumePersistent saves a volume's persistent state to the persistent store
+func (c *BoltClient) AddVolumePersistent(_ context.Context, volume *storage.VolumeExternal) error {
+	return c.put(boltVolumesBucket, volume.Config.Name, volume, false, true)
+}
+
+func (c *BoltClient) GetVolume(_ context.Context, volName string) (*storage.VolumeExternal, error) {
+
+	volume := &storage.VolumeExternal{}
+	if err := c.get(boltVolumesBucket, volName, volume); err != nil {
+		return nil, err
+	}
+	return volume, nil
+}
+
+func (c *BoltClient) UpdateVolume(ctx context.Context, vol *storage.Volume) error {
+	return c.UpdateVolumePersistent(ctx, vol.ConstructExternal())
+}
+
+// UpdateVolumePersistent updates a volume's persistent state
+func (c *BoltClient) UpdateVolumePersistent(_ context.Context, volume *storage.VolumeExternal) error {
+	return c.put(boltVolumesBucket, volume.Config.Name, volume, true, false)
+}
+
+func (c *BoltClient) DeleteVolume(_ context.Context, vol *storage.Volume) error {
+	return c.delete(boltVolumesBucket, vol.Config.Name, true)
+}
+
+func (c *BoltClient) DeleteVolumeIgnoreNotFound(_ context.Context, vol *storage.Volume) error {
+	return c.delete(boltVolumesBucket, vol.Config.Name, false)
+}
+
+func (c *BoltClient) GetVolumes(context.Context) ([]*storage.VolumeExternal, error) {
+
+	volumes := make([]*storage.VolumeExternal, 0)
+	err := c.list(boltVolumesBucket, func(value []byte) error {
+		volume := &storage.VolumeExternal{}
+		if err := json.Unmarshal(value, volume); err != nil {
+			return err
+		}
+		volumes = append(volumes, volume)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return volumes, nil
+}
+
+func (c *BoltClient) DeleteVolumes(context.Context) error {
+	return c.deleteAll(boltVolumesBucket)
+}
+
+// AddVolumeTransaction overwrites existing keys, unlike the other methods
+func (c *BoltClient) AddVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error {
+
+	Logc(ctx).WithFields(log.Fields{
+		"op":   volTxn.Op,
+		"name": volTxn.Name(),
+	}).Debug("AddVolumeTransaction")
+
+	return c.put(boltVolumeTransactionsBucket, volTxn.Name(), volTxn, false, false)
+}
+
+func (c *BoltClient) GetVolumeTransactions(context.Context) ([]*storage.VolumeTransaction, error) {
+
+	volTxns := make([]*storage.VolumeTransaction, 0)
+	err := c.list(boltVolumeTransactionsBucket, func(value []byte) error {
+		volTxn := &storage.VolumeTransaction{}
+		if err := json.Unmarshal(value, volTxn); err != nil {
+			return err
+		}
+		volTxns = append(volTxns, volTxn)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return volTxns, nil
+}
+
+func (c *BoltClient) UpdateVolumeTransaction(_ context.Context, volTxn *storage.VolumeTransaction) error {
+	return c.put(boltVolumeTransactionsBucket, volTxn.Name(), volTxn, true, false)
+}
+
+func (c *BoltClient) GetExistingVolumeTransaction(
+	_ context.Context, volTxn *storage.VolumeTransaction,
+) (*storage.VolumeTransaction, error) {
+
+	existingTxn := &storage.VolumeTransaction{}
+	if err := c.get(boltVolumeTransactionsBucket, volTxn.Name(), existingTxn); err != nil {
+		if MatchKeyNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error getting volume transaction; %v", err)
+	}
+	return existingTxn, nil
+}
+
+func (c *BoltClient) DeleteVolumeTransaction(_ context.Context, volTxn *storage.VolumeTransaction) error {
+	return c.delete(boltVolumeTransactionsBucket, volTxn.Name(), true)
+}
+
+func (c *BoltClient) AddStorageClass(_ context.Context, s *sc.StorageClass) error {
+	storageClass := s.ConstructPersistent()
+	return c.put(boltStorageClassesBucket, storageClass.GetName(), storageClass, false, true)
+}
+
+func (c *BoltClient) GetStorageClass(_ context.Context, scName string) (*sc.Persistent, error) {
+
+	storageClass := &sc.Persistent{}
+	if err := c.get(boltStorageClassesBucket, scName, storageClass); err != nil {
+		return nil, err
+	}
+	return storageClass, nil
+}
+
+func (c *BoltClient) GetStorageClasses(context.Context) ([]*sc.Persistent, error) {
+
+	storageClasses := make([]*sc.Persistent, 0)
+	err := c.list(boltStorageClassesBucket, func(value []byte) error {
+		storageClass := &sc.Persistent{}
+		if err := json.Unmarshal(value, storageClass); err != nil {
+			return err
+		}
+		storageClasses = append(storageClasses, storageClass)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return storageClasses, nil
+}
+
+func (c *BoltClient) DeleteStorageClass(_ context.Context, s *sc.StorageClass) error {
+	return c.delete(boltStorageClassesBucket, s.GetName(), true)
+}
+
+func (c *BoltClient) AddOrUpdateNode(_ context.Context, n *utils.Node) error {
+	return c.put(boltNodesBucket, n.Name, n, false, false)
+}
+
+func (c *BoltClient) GetNode(_ context.Context, nName string) (*utils.Node, error) {
+
+	node := &utils.Node{}
+	if err := c.get(boltNodesBucket, nName, node); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (c *BoltClient) GetNodes(context.Context) ([]*utils.Node, error) {
+
+	nodes := make([]*utils.Node, 0)
+	err := c.list(boltNodesBucket, func(value []byte) error {
+		node := &utils.Node{}
+		if err := json.Unmarshal(value, node); err != nil {
+			return err
+		}
+		nodes = append(nodes, node)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func (c *BoltClient) DeleteNode(_ context.Context, n *utils.Node) error {
+	return c.delete(boltNodesBucket, n.Name, true)
+}
+
+func (c *BoltClient) AddSnapshot(_ context.Context, snapshot *storage.Snapshot) error {
+	return c.put(boltSnapshotsBucket, snapshot.ID(), snapshot.ConstructPersistent(), false, false)
+}
+
+// GetSnapshot retrieves a snapshot state from the persistent store
+func (c *BoltClient) GetSnapshot(_ context.Context, volumeName, snapshotName string) (
+	*storage.SnapshotPersistent, error,
+) {
+
+	snapshot := &storage.SnapshotPersistent{}
+	if err := c.get(boltSnapshotsBucket, storage.MakeSnapshotID(volumeName, snapshotName), snapshot); err != nil {
+		if MatchKeyNotFoundErr(err) {
+			return nil, NewPersistentStoreError(KeyNotFoundErr, snapshotName)
+		}
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// GetSnapshots retrieves all snapshots for all volumes
+func (c *BoltClient) GetSnapshots(context.Context) ([]*storage.SnapshotPersistent, error) {
+
+	snapshots := make([]*storage.SnapshotPersistent, 0)
+	err := c.list(boltSnapshotsBucket, func(value []byte) error {
+		snapshot := &storage.SnapshotPersistent{}
+		if err := json.Unmarshal(value, snapshot); err != nil {
+			return err
+		}
+		snapshots = append(snapshots, snapshot)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (c *BoltClient) UpdateSnapshot(_ context.Context, snapshot *storage.Snapshot) error {
+	return c.put(boltSnapshotsBucket, snapshot.ID(), snapshot.ConstructPersistent(), true, false)
+}
+
+// DeleteSnapshot deletes a snapshot from the persistent store
+func (c *BoltClient) DeleteSnapshot(_ context.Context, snapshot *storage.Snapshot) error {
+	return c.delete(boltSnapshotsBucket, snapshot.ID(), true)
+}
+
+// DeleteSnapshotIgnoreNotFound deletes a snapshot from the persistent store,
+// returning no error if the record does not exist.
+func (c *BoltClient) DeleteSnapshotIgnoreNotFound(_ context.Context, snapshot *storage.Snapshot) error {
+	return c.delete(boltSnapshotsBucket, snapshot.ID(), false)
+}
+
+// DeleteSnapshots deletes all snapshots
+func (c *BoltClient) DeleteSnapshots(context.Context) error {
+	return c.deleteAll(boltSnapshotsBucket)
+}
@@ -0,0 +1,245 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+)
+
+func newTestBoltClient(t *testing.T) (*BoltClient, string) {
+
+	dir, err := ioutil.TempDir("", "bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "trident.db")
+	client, err := NewBoltClient(path)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return client, path
+}
+
+func TestBoltClient_Version(t *testing.T) {
+
+	client, path := newTestBoltClient(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	_, err := client.GetVersion(ctx())
+	assert.True(t, MatchKeyNotFoundErr(err))
+
+	version := &config.PersistentStateVersion{
+		PersistentStoreVersion: string(BoltStore),
+		OrchestratorAPIVersion: config.OrchestratorAPIVersion,
+	}
+	assert.NoError(t, client.SetVersion(ctx(), version))
+	assert.NoError(t, client.Stop())
+
+	// The version must survive reopening the database
+	client, err = NewBoltClient(path)
+	assert.NoError(t, err)
+	defer client.Stop()
+
+	result, err := client.GetVersion(ctx())
+	assert.NoError(t, err)
+	assert.Equal(t, version, result)
+	assert.Equal(t, BoltStore, client.GetType())
+}
+
+func TestBoltClient_Backends(t *testing.T) {
+
+	client, path := newTestBoltClient(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	defer client.Stop()
+
+	backend := getFakeBackend()
+	backend.BackendUUID = "1f5a3e8d-0b44-4a5c-b3c9-6b6d1b0a1d2e"
+
+	_, err := client.GetBackend(ctx(), backend.Name)
+	assert.True(t, MatchKeyNotFoundErr(err))
+	assert.True(t, MatchKeyNotFoundErr(client.UpdateBackend(ctx(), backend)))
+
+	assert.NoError(t, client.AddBackend(ctx(), backend))
+	assert.Error(t, client.AddBackend(ctx(), backend), "backend should already exist")
+
+	result, err := client.GetBackend(ctx(), backend.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, backend.ConstructPersistent(ctx()), result)
+
+	backend.Online = false
+	assert.NoError(t, client.UpdateBackend(ctx(), backend))
+	result, err = client.GetBackend(ctx(), backend.Name)
+	assert.NoError(t, err)
+	assert.False(t, result.Online)
+
+	backends, err := client.GetBackends(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, backends, 1)
+
+	assert.NoError(t, client.DeleteBackend(ctx(), backend))
+	assert.True(t, MatchKeyNotFoundErr(client.DeleteBackend(ctx(), backend)))
+
+	assert.NoError(t, client.AddBackend(ctx(), backend))
+	assert.NoError(t, client.DeleteBackends(ctx()))
+	backends, err = client.GetBackends(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, backends, 0)
+}
+
+func TestBoltClient_ReplaceBackendAndUpdateVolumes(t *testing.T) {
+
+	client, path := newTestBoltClient(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	defer client.Stop()
+
+	origBackend := getFakeBackendWithName("orig")
+	origBackend.BackendUUID = "1f5a3e8d-0b44-4a5c-b3c9-6b6d1b0a1d2e"
+	otherBackend := getFakeBackendWithName("other")
+	otherBackend.BackendUUID = "7d0c2b4e-5a61-4f0e-9b8e-2c3d4e5f6a7b"
+	assert.NoError(t, client.AddBackend(ctx(), origBackend))
+	assert.NoError(t, client.AddBackend(ctx(), otherBackend))
+	assert.NoError(t, client.AddVolume(ctx(), getFakeVolumeWithName("vol1", origBackend)))
+	assert.NoError(t, client.AddVolume(ctx(), getFakeVolumeWithName("vol2", otherBackend)))
+
+	newBackend := getFakeBackendWithName("new")
+	newBackend.BackendUUID = "3e6f9a1b-2c4d-4e8f-a0b1-c2d3e4f5a6b7"
+	assert.NoError(t, client.ReplaceBackendAndUpdateVolumes(ctx(), origBackend, newBackend))
+
+	_, err := client.GetBackend(ctx(), origBackend.Name)
+	assert.True(t, MatchKeyNotFoundErr(err))
+	_, err = client.GetBackend(ctx(), newBackend.Name)
+	assert.NoError(t, err)
+
+	vol1, err := client.GetVolume(ctx(), "vol1")
+	assert.NoError(t, err)
+	assert.Equal(t, newBackend.BackendUUID, vol1.BackendUUID)
+	vol2, err := client.GetVolume(ctx(), "vol2")
+	assert.NoError(t, err)
+	assert.Equal(t, otherBackend.BackendUUID, vol2.BackendUUID)
+
+	// Replacing a backend that doesn't exist changes nothing
+	assert.True(t, MatchKeyNotFoundErr(client.ReplaceBackendAndUpdateVolumes(ctx(), origBackend, newBackend)))
+}
+
+func TestBoltClient_Volumes(t *testing.T) {
+
+	client, path := newTestBoltClient(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	defer client.Stop()
+
+	volume := getFakeVolume(getFakeBackend())
+
+	assert.True(t, MatchKeyNotFoundErr(client.UpdateVolume(ctx(), volume)))
+	assert.NoError(t, client.AddVolume(ctx(), volume))
+	assert.Error(t, client.AddVolume(ctx(), volume), "volume should already exist")
+
+	volume.Config.Size = "1Gi"
+	assert.NoError(t, client.UpdateVolume(ctx(), volume))
+	result, err := client.GetVolume(ctx(), volume.Config.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, volume.ConstructExternal(), result)
+
+	volumes, err := client.GetVolumes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, volumes, 1)
+
+	assert.NoError(t, client.DeleteVolume(ctx(), volume))
+	assert.True(t, MatchKeyNotFoundErr(client.DeleteVolume(ctx(), volume)))
+	assert.NoError(t, client.DeleteVolumeIgnoreNotFound(ctx(), volume))
+
+	_, err = client.GetVolume(ctx(), volume.Config.Name)
+	assert.True(t, MatchKeyNotFoundErr(err))
+}
+
+func TestBoltClient_VolumeTransactions(t *testing.T) {
+
+	client, path := newTestBoltClient(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	defer client.Stop()
+
+	volTxn := getFakeVolumeTransaction()
+
+	volTxns, err := client.GetVolumeTransactions(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, volTxns, 0)
+
+	existing, err := client.GetExistingVolumeTransaction(ctx(), volTxn)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	assert.NoError(t, client.AddVolumeTransaction(ctx(), volTxn))
+	assert.NoError(t, client.AddVolumeTransaction(ctx(), volTxn), "transactions should be overwritten")
+
+	volTxn.Op = storage.DeleteVolume
+	assert.NoError(t, client.UpdateVolumeTransaction(ctx(), volTxn))
+
+	existing, err = client.GetExistingVolumeTransaction(ctx(), volTxn)
+	assert.NoError(t, err)
+	assert.Equal(t, volTxn, existing)
+
+	volTxns, err = client.GetVolumeTransactions(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, volTxns, 1)
+
+	assert.NoError(t, client.DeleteVolumeTransaction(ctx(), volTxn))
+	assert.True(t, MatchKeyNotFoundErr(client.DeleteVolumeTransaction(ctx(), volTxn)))
+	assert.True(t, MatchKeyNotFoundErr(client.UpdateVolumeTransaction(ctx(), volTxn)))
+}
+
+func TestBoltClient_StorageClassesNodesAndSnapshots(t *testing.T) {
+
+	client, path := newTestBoltClient(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	defer client.Stop()
+
+	storageClass := getFakeStorageClass()
+	assert.NoError(t, client.AddStorageClass(ctx(), storageClass))
+	assert.Error(t, client.AddStorageClass(ctx(), storageClass), "storage class should already exist")
+	scResult, err := client.GetStorageClass(ctx(), storageClass.GetName())
+	assert.NoError(t, err)
+	assert.Equal(t, storageClass.ConstructPersistent(), scResult)
+	storageClasses, err := client.GetStorageClasses(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, storageClasses, 1)
+	assert.NoError(t, client.DeleteStorageClass(ctx(), storageClass))
+	_, err = client.GetStorageClass(ctx(), storageClass.GetName())
+	assert.True(t, MatchKeyNotFoundErr(err))
+
+	node := getFakeNode()
+	assert.NoError(t, client.AddOrUpdateNode(ctx(), node))
+	node.IQN = "newIQN"
+	assert.NoError(t, client.AddOrUpdateNode(ctx(), node))
+	nodeResult, err := client.GetNode(ctx(), node.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, node, nodeResult)
+	nodes, err := client.GetNodes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	assert.NoError(t, client.DeleteNode(ctx(), node))
+	assert.True(t, MatchKeyNotFoundErr(client.DeleteNode(ctx(), node)))
+
+	snapshot := getFakeSnapshot()
+	assert.True(t, MatchKeyNotFoundErr(client.UpdateSnapshot(ctx(), snapshot)))
+	assert.NoError(t, client.AddSnapshot(ctx(), snapshot))
+	snapshot.SizeBytes = 2000000000
+	assert.NoError(t, client.UpdateSnapshot(ctx(), snapshot))
+	snapResult, err := client.GetSnapshot(ctx(), snapshot.Config.VolumeName, snapshot.Config.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot.ConstructPersistent(), snapResult)
+	snapshots, err := client.GetSnapshots(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+	assert.NoError(t, client.DeleteSnapshot(ctx(), snapshot))
+	assert.True(t, MatchKeyNotFoundErr(client.DeleteSnapshot(ctx(), snapshot)))
+	assert.NoError(t, client.DeleteSnapshotIgnoreNotFound(ctx(), snapshot))
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package v1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// Desired states of a TridentMirrorRelationship, which are also reported once they are reached
+	MirrorRelationshipStateEstablished = "established"
+	MirrorRelationshipStatePromoted    = "promoted"
+	MirrorRelationshipStateReversed    = "reversed"
+
+	// Transitional states of a TridentMirrorRelationship, which are only reported
+	MirrorRelationshipStateEstablishing = "establishing"
+	MirrorRelationshipStatePromoting    = "promoting"
+	MirrorRelationshipStateReversing    = "reversing"
+	MirrorRelationshipStateFailed       = "failed"
+)
+
+func (in *TridentMirrorRelationship) GetObjectMeta() metav1.ObjectMeta {
+	return in.ObjectMeta
+}
+
+func (in *TridentMirrorRelationship) GetFinalizers() []string {
+	if in.ObjectMeta.Finalizers != nil {
+		return in.ObjectMeta.Finalizers
+	}
+	return []string{}
+}
+
+func (in *TridentMirrorRelationship) HasTridentFinalizers() bool {
+	for _, finalizerName := range GetTridentFinalizers() {
+		if utils.SliceContainsString(in.ObjectMeta.Finalizers, finalizerName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (in *TridentMirrorRelationship) AddTridentFinalizers() {
+	for _, finalizerName := range GetTridentFinalizers() {
+		if !utils.SliceContainsString(in.ObjectMeta.Finalizers, finalizerName) {
+			in.ObjectMeta.Finalizers = append(in.ObjectMeta.Finalizers, finalizerName)
+		}
+	}
+}
+
+func (in *TridentMirrorRelationship) RemoveTridentFinalizers() {
+	for _, finalizerName := range GetTridentFinalizers() {
+		in.ObjectMeta.Finalizers = utils.RemoveStringFromSlice(in.ObjectMeta.Finalizers, finalizerName)
+	}
+}
+
+// Validate function validates the TridentMirrorRelationship.  An InvalidInputError is returned if the
+// spec is invalid, since retrying cannot fix it.
+func (in *TridentMirrorRelationship) Validate() error {
+
+	if in.Spec.SourceVolume == "" || in.Spec.DestinationVolume == "" {
+		return utils.InvalidInputError("sourceVolume and destinationVolume must both be specified")
+	}
+	if in.Spec.SourceVolume == in.Spec.DestinationVolume {
+		return utils.InvalidInputError("sourceVolume and destinationVolume must be different")
+	}
+
+	allowedStates := []string{
+		MirrorRelationshipStateEstablished, MirrorRelationshipStatePromoted, MirrorRelationshipStateReversed,
+	}
+	if !utils.SliceContainsString(allowedStates, in.Spec.State) {
+		return utils.InvalidInputError(fmt.Sprintf("invalid state %s; must be one of %v", in.Spec.State,
+			allowedStates))
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/utils"
+)
+
+func TestTridentMirrorRelationship_Validate(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		spec  TridentMirrorRelationshipSpec
+		valid bool
+	}{
+		{"established", TridentMirrorRelationshipSpec{
+			SourceVolume: "src", DestinationVolume: "dst", State: MirrorRelationshipStateEstablished}, true},
+		{"promoted", TridentMirrorRelationshipSpec{
+			SourceVolume: "src", DestinationVolume: "dst", State: MirrorRelationshipStatePromoted}, true},
+		{"reversed", TridentMirrorRelationshipSpec{
+			SourceVolume: "src", DestinationVolume: "dst", State: MirrorRelationshipStateReversed}, true},
+		{"missing source", TridentMirrorRelationshipSpec{
+			DestinationVolume: "dst", State: MirrorRelationshipStateEstablished}, false},
+		{"missing destination", TridentMirrorRelationshipSpec{
+			SourceVolume: "src", State: MirrorRelationshipStateEstablished}, false},
+		{"same volume", TridentMirrorRelationshipSpec{
+			SourceVolume: "src", DestinationVolume: "src", State: MirrorRelationshipStateEstablished}, false},
+		{"transitional state", TridentMirrorRelationshipSpec{
+			SourceVolume: "src", DestinationVolume: "dst", State: MirrorRelationshipStateEstablishing}, false},
+		{"empty state", TridentMirrorRelationshipSpec{SourceVolume: "src", DestinationVolume: "dst"}, false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		relationship := &TridentMirrorRelationship{Spec: test.spec}
+		err := relationship.Validate()
+		if test.valid {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.True(t, utils.IsInvalidInputError(err), "%s: expected an InvalidInputError", test.name)
+		}
+	}
+}
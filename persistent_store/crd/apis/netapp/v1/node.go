@@ -40,7 +40,9 @@ func (in *TridentNode) Apply(persistent *utils.Node) error {
 
 	in.Name = persistent.Name
 	in.IQN = persistent.IQN
+	in.NQN = persistent.NQN
 	in.IPs = persistent.IPs
+	in.Deleted = persistent.Deleted
 
 	nodePrep, err := json.Marshal(persistent.NodePrep)
 	if err != nil {
@@ -62,9 +64,11 @@ func (in *TridentNode) Persistent() (*utils.Node, error) {
 	persistent := &utils.Node{
 		Name:     in.Name,
 		IQN:      in.IQN,
+		NQN:      in.NQN,
 		IPs:      in.IPs,
 		NodePrep: &utils.NodePrep{},
 		HostInfo: &utils.HostSystem{},
+		Deleted:  in.Deleted,
 	}
 
 	if string(in.NodePrep.Raw) != "" {
@@ -36,6 +36,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&TridentBackendList{},
 		&TridentBackendConfig{},
 		&TridentBackendConfigList{},
+		&TridentMirrorRelationship{},
+		&TridentMirrorRelationshipList{},
 		&TridentVolume{},
 		&TridentVolumeList{},
 		&TridentStorageClass{},
@@ -48,6 +50,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&TridentVersionList{},
 		&TridentSnapshot{},
 		&TridentSnapshotList{},
+		&TridentSnapshotPolicy{},
+		&TridentSnapshotPolicyList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,81 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package v1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/utils"
+)
+
+func (in *TridentSnapshotPolicy) GetObjectMeta() metav1.ObjectMeta {
+	return in.ObjectMeta
+}
+
+// Validate function validates the TridentSnapshotPolicy
+func (in *TridentSnapshotPolicy) Validate() error {
+
+	if len(in.Spec.StorageClasses) == 0 && in.Spec.PVCSelector == nil {
+		return fmt.Errorf("storageClasses or pvcSelector must be specified")
+	}
+	if in.Spec.PVCSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(in.Spec.PVCSelector); err != nil {
+			return fmt.Errorf("invalid pvcSelector; %v", err)
+		}
+	}
+
+	if _, err := in.GetSchedules(); err != nil {
+		return err
+	}
+
+	retention := in.Spec.Retention
+	if retention.Hourly < 0 || retention.Daily < 0 || retention.Weekly < 0 {
+		return fmt.Errorf("retention counts must not be negative")
+	}
+	if retention.Hourly == 0 && retention.Daily == 0 && retention.Weekly == 0 {
+		return fmt.Errorf("at least one of the hourly, daily, or weekly retention counts must be specified")
+	}
+
+	return nil
+}
+
+// GetSchedules parses the cron schedules of the TridentSnapshotPolicy.
+func (in *TridentSnapshotPolicy) GetSchedules() ([]*utils.CronSchedule, error) {
+
+	if len(in.Spec.Schedules) == 0 {
+		return nil, fmt.Errorf("at least one schedule must be specified")
+	}
+
+	schedules := make([]*utils.CronSchedule, 0, len(in.Spec.Schedules))
+	for _, spec := range in.Spec.Schedules {
+		schedule, err := utils.ParseCronSchedule(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule; %v", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	return schedules, nil
+}
+
+// NextScheduleTime returns the first time after t, in UTC, at which any of the TridentSnapshotPolicy's
+// schedules calls for snapshots.
+func (in *TridentSnapshotPolicy) NextScheduleTime(t time.Time) (time.Time, error) {
+
+	schedules, err := in.GetSchedules()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var next time.Time
+	for _, schedule := range schedules {
+		if scheduleNext := schedule.Next(t.UTC()); next.IsZero() || scheduleNext.Before(next) {
+			next = scheduleNext
+		}
+	}
+
+	return next, nil
+}
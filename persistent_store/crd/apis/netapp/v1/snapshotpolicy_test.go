@@ -0,0 +1,71 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTridentSnapshotPolicy_Validate(t *testing.T) {
+
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"backup": "true"}}
+	badSelector := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "backup", Operator: "Sometimes"}}}
+	retention := TridentSnapshotPolicyRetention{Hourly: 24, Daily: 7}
+
+	tests := []struct {
+		name  string
+		spec  TridentSnapshotPolicySpec
+		valid bool
+	}{
+		{"storage class", TridentSnapshotPolicySpec{
+			StorageClasses: []string{"gold"}, Schedules: []string{"@hourly"}, Retention: retention}, true},
+		{"pvc selector", TridentSnapshotPolicySpec{
+			PVCSelector: selector, Schedules: []string{"0 * * * *"}, Retention: retention}, true},
+		{"several schedules", TridentSnapshotPolicySpec{
+			StorageClasses: []string{"gold"}, Schedules: []string{"0 8-18 * * 1-5", "0 0 * * *"},
+			Retention: TridentSnapshotPolicyRetention{Weekly: 4}}, true},
+		{"no selection", TridentSnapshotPolicySpec{Schedules: []string{"@hourly"}, Retention: retention}, false},
+		{"invalid pvc selector", TridentSnapshotPolicySpec{
+			PVCSelector: badSelector, Schedules: []string{"@hourly"}, Retention: retention}, false},
+		{"no schedules", TridentSnapshotPolicySpec{StorageClasses: []string{"gold"}, Retention: retention}, false},
+		{"invalid schedule", TridentSnapshotPolicySpec{
+			StorageClasses: []string{"gold"}, Schedules: []string{"0 25 * * *"}, Retention: retention}, false},
+		{"no retention", TridentSnapshotPolicySpec{
+			StorageClasses: []string{"gold"}, Schedules: []string{"@hourly"}}, false},
+		{"negative retention", TridentSnapshotPolicySpec{
+			StorageClasses: []string{"gold"}, Schedules: []string{"@hourly"},
+			Retention: TridentSnapshotPolicyRetention{Hourly: 24, Daily: -1}}, false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		policy := &TridentSnapshotPolicy{Spec: test.spec}
+		err := policy.Validate()
+		if test.valid {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
+
+func TestTridentSnapshotPolicy_NextScheduleTime(t *testing.T) {
+
+	policy := &TridentSnapshotPolicy{Spec: TridentSnapshotPolicySpec{
+		Schedules: []string{"0 0 * * *", "30 */6 * * *"},
+	}}
+
+	next, err := policy.NextScheduleTime(time.Date(2021, 4, 30, 19, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), next)
+
+	next, err = policy.NextScheduleTime(time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 5, 1, 0, 30, 0, 0, time.UTC), next)
+}
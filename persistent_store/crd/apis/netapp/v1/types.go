@@ -69,6 +69,112 @@ type TridentBackendConfigBackendInfo struct {
 	BackendUUID string `json:"backendUUID"`
 }
 
+// TridentMirrorRelationship defines a mirror relationship between two Trident volumes.
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentMirrorRelationship struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TridentMirrorRelationshipSpec   `json:"spec"`
+	Status TridentMirrorRelationshipStatus `json:"status"`
+}
+
+// TridentMirrorRelationshipList is a list of TridentMirrorRelationship objects.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentMirrorRelationshipList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of TridentMirrorRelationship objects
+	Items []*TridentMirrorRelationship `json:"items"`
+}
+
+// TridentMirrorRelationshipSpec defines the desired state of TridentMirrorRelationship
+type TridentMirrorRelationshipSpec struct {
+	// SourceVolume is the name of the Trident volume that is replicated
+	SourceVolume string `json:"sourceVolume"`
+	// DestinationVolume is the name of the Trident volume that receives the replicated data
+	DestinationVolume string `json:"destinationVolume"`
+	// State is the desired state of the relationship: established, promoted, or reversed
+	State string `json:"state"`
+	// ReplicationPolicy is the name of the storage replication policy to use, if any
+	ReplicationPolicy string `json:"replicationPolicy,omitempty"`
+	// ReplicationSchedule is the name of the storage replication schedule to use, if any
+	ReplicationSchedule string `json:"replicationSchedule,omitempty"`
+}
+
+// TridentMirrorRelationshipStatus defines the observed state of TridentMirrorRelationship
+type TridentMirrorRelationshipStatus struct {
+	State              string `json:"state"`
+	MirrorState        string `json:"mirrorState,omitempty"`
+	Healthy            bool   `json:"healthy"`
+	Message            string `json:"message"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+	ObservedGeneration int64  `json:"observedGeneration"`
+}
+
+// TridentSnapshotPolicy defines a schedule for snapshots of the Trident volumes bound to PVCs in its
+// namespace, and how many of those snapshots are retained.
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentSnapshotPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TridentSnapshotPolicySpec   `json:"spec"`
+	Status TridentSnapshotPolicyStatus `json:"status"`
+}
+
+// TridentSnapshotPolicyList is a list of TridentSnapshotPolicy objects.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentSnapshotPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	// List of TridentSnapshotPolicy objects
+	Items []*TridentSnapshotPolicy `json:"items"`
+}
+
+// TridentSnapshotPolicySpec defines the desired state of TridentSnapshotPolicy
+type TridentSnapshotPolicySpec struct {
+	// StorageClasses selects the volumes provisioned from any of these storage classes
+	StorageClasses []string `json:"storageClasses,omitempty"`
+	// PVCSelector selects the volumes bound to PVCs with matching labels
+	PVCSelector *metav1.LabelSelector `json:"pvcSelector,omitempty"`
+	// Schedules are the cron schedules, in UTC, on which snapshots are created
+	Schedules []string `json:"schedules"`
+	// Retention is the number of snapshots kept for each period
+	Retention TridentSnapshotPolicyRetention `json:"retention"`
+	// VolumeSnapshotClass is the class of the Kubernetes VolumeSnapshot objects created for each
+	// snapshot, if any
+	VolumeSnapshotClass string `json:"volumeSnapshotClass,omitempty"`
+}
+
+// TridentSnapshotPolicyRetention defines how many snapshots of each volume a TridentSnapshotPolicy
+// keeps.  The newest snapshot in each of the most recent hours, days, and weeks is retained.
+type TridentSnapshotPolicyRetention struct {
+	Hourly int `json:"hourly,omitempty"`
+	Daily  int `json:"daily,omitempty"`
+	Weekly int `json:"weekly,omitempty"`
+}
+
+// TridentSnapshotPolicyStatus defines the observed state of TridentSnapshotPolicy
+type TridentSnapshotPolicyStatus struct {
+	Volumes            int    `json:"volumes"`
+	Snapshots          int    `json:"snapshots"`
+	LastScheduleTime   string `json:"lastScheduleTime,omitempty"`
+	NextScheduleTime   string `json:"nextScheduleTime,omitempty"`
+	Message            string `json:"message"`
+	ObservedGeneration int64  `json:"observedGeneration"`
+}
+
 // TridentBackend defines a Trident backend.
 // +genclient
 // +k8s:openapi-gen=true
@@ -121,6 +227,8 @@ type TridentVolume struct {
 	Orphaned bool `json:"orphaned"`
 	// State records the TridentVolume's state
 	State string `json:"state"`
+	// PublishedNodes lists the nodes to which the TridentVolume is currently published
+	PublishedNodes []string `json:"publishedNodes,omitempty"`
 }
 
 // TridentVolumeList is a list of TridentVolume objects.
@@ -189,12 +297,16 @@ type TridentNode struct {
 	NodeName string `json:"name"`
 	// IQN is the iqn of the node
 	IQN string `json:"iqn,omitempty"`
+	// NQN is the NVMe host nqn of the node
+	NQN string `json:"nqn,omitempty"`
 	// IPs is a list of IP addresses for the TridentNode
 	IPs []string `json:"ips,omitempty"`
 	// NodePrep is the current status of node preparation for this node
 	NodePrep runtime.RawExtension `json:"nodePrep,omitempty"`
 	// HostInfo contains information about the node's host machine
 	HostInfo runtime.RawExtension `json:"hostInfo,omitempty"`
+	// Deleted indicates that the node is gone but volumes are still published to it
+	Deleted bool `json:"deleted,omitempty"`
 }
 
 // TridentNodeList is a list of TridentNode objects.
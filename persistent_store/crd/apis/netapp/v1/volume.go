@@ -69,6 +69,7 @@ func (in *TridentVolume) Apply(ctx context.Context, persistent *storage.VolumeEx
 	in.Orphaned = persistent.Orphaned
 	in.Pool = persistent.Pool
 	in.State = string(persistent.State)
+	in.PublishedNodes = persistent.PublishedNodes
 
 	return nil
 }
@@ -77,11 +78,12 @@ func (in *TridentVolume) Apply(ctx context.Context, persistent *storage.VolumeEx
 // storage.VolumeExternal equivalent
 func (in *TridentVolume) Persistent() (*storage.VolumeExternal, error) {
 	persistent := &storage.VolumeExternal{
-		BackendUUID: in.BackendUUID,
-		Orphaned:    in.Orphaned,
-		Pool:        in.Pool,
-		Config:      &storage.VolumeConfig{},
-		State:       storage.VolumeState(in.State),
+		BackendUUID:    in.BackendUUID,
+		Orphaned:       in.Orphaned,
+		Pool:           in.Pool,
+		Config:         &storage.VolumeConfig{},
+		State:          storage.VolumeState(in.State),
+		PublishedNodes: in.PublishedNodes,
 	}
 
 	return persistent, json.Unmarshal(in.Config.Raw, persistent.Config)
@@ -7,6 +7,7 @@
 package v1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -172,6 +173,103 @@ func (in *TridentBackendList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TridentMirrorRelationship) DeepCopyInto(out *TridentMirrorRelationship) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TridentMirrorRelationship.
+func (in *TridentMirrorRelationship) DeepCopy() *TridentMirrorRelationship {
+	if in == nil {
+		return nil
+	}
+	out := new(TridentMirrorRelationship)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TridentMirrorRelationship) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TridentMirrorRelationshipList) DeepCopyInto(out *TridentMirrorRelationshipList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]*TridentMirrorRelationship, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(TridentMirrorRelationship)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TridentMirrorRelationshipList.
+func (in *TridentMirrorRelationshipList) DeepCopy() *TridentMirrorRelationshipList {
+	if in == nil {
+		return nil
+	}
+	out := new(TridentMirrorRelationshipList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TridentMirrorRelationshipList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TridentMirrorRelationshipSpec) DeepCopyInto(out *TridentMirrorRelationshipSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TridentMirrorRelationshipSpec.
+func (in *TridentMirrorRelationshipSpec) DeepCopy() *TridentMirrorRelationshipSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TridentMirrorRelationshipSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TridentMirrorRelationshipStatus) DeepCopyInto(out *TridentMirrorRelationshipStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TridentMirrorRelationshipStatus.
+func (in *TridentMirrorRelationshipStatus) DeepCopy() *TridentMirrorRelationshipStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TridentMirrorRelationshipStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TridentNode) DeepCopyInto(out *TridentNode) {
 	*out = *in
@@ -306,6 +404,135 @@ func (in *TridentSnapshotList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TridentSnapshotPolicy) DeepCopyInto(out *TridentSnapshotPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TridentSnapshotPolicy.
+func (in *TridentSnapshotPolicy) DeepCopy() *TridentSnapshotPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TridentSnapshotPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TridentSnapshotPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TridentSnapshotPolicyList) DeepCopyInto(out *TridentSnapshotPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]*TridentSnapshotPolicy, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(TridentSnapshotPolicy)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TridentSnapshotPolicyList.
+func (in *TridentSnapshotPolicyList) DeepCopy() *TridentSnapshotPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(TridentSnapshotPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TridentSnapshotPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TridentSnapshotPolicyRetention) DeepCopyInto(out *TridentSnapshotPolicyRetention) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TridentSnapshotPolicyRetention.
+func (in *TridentSnapshotPolicyRetention) DeepCopy() *TridentSnapshotPolicyRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(TridentSnapshotPolicyRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TridentSnapshotPolicySpec) DeepCopyInto(out *TridentSnapshotPolicySpec) {
+	*out = *in
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PVCSelector != nil {
+		in, out := &in.PVCSelector, &out.PVCSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Retention = in.Retention
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TridentSnapshotPolicySpec.
+func (in *TridentSnapshotPolicySpec) DeepCopy() *TridentSnapshotPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TridentSnapshotPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TridentSnapshotPolicyStatus) DeepCopyInto(out *TridentSnapshotPolicyStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TridentSnapshotPolicyStatus.
+func (in *TridentSnapshotPolicyStatus) DeepCopy() *TridentSnapshotPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TridentSnapshotPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TridentStorageClass) DeepCopyInto(out *TridentStorageClass) {
 	*out = *in
@@ -503,6 +730,11 @@ func (in *TridentVolume) DeepCopyInto(out *TridentVolume) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Config.DeepCopyInto(&out.Config)
+	if in.PublishedNodes != nil {
+		in, out := &in.PublishedNodes, &out.PublishedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -22,6 +22,10 @@ func (c *FakeTridentV1) TridentBackendConfigs(namespace string) v1.TridentBacken
 	return &FakeTridentBackendConfigs{c, namespace}
 }
 
+func (c *FakeTridentV1) TridentMirrorRelationships(namespace string) v1.TridentMirrorRelationshipInterface {
+	return &FakeTridentMirrorRelationships{c, namespace}
+}
+
 func (c *FakeTridentV1) TridentNodes(namespace string) v1.TridentNodeInterface {
 	return &FakeTridentNodes{c, namespace}
 }
@@ -30,6 +34,10 @@ func (c *FakeTridentV1) TridentSnapshots(namespace string) v1.TridentSnapshotInt
 	return &FakeTridentSnapshots{c, namespace}
 }
 
+func (c *FakeTridentV1) TridentSnapshotPolicies(namespace string) v1.TridentSnapshotPolicyInterface {
+	return &FakeTridentSnapshotPolicies{c, namespace}
+}
+
 func (c *FakeTridentV1) TridentStorageClasses(namespace string) v1.TridentStorageClassInterface {
 	return &FakeTridentStorageClasses{c, namespace}
 }
@@ -8,10 +8,14 @@ type TridentBackendExpansion interface{}
 
 type TridentBackendConfigExpansion interface{}
 
+type TridentMirrorRelationshipExpansion interface{}
+
 type TridentNodeExpansion interface{}
 
 type TridentSnapshotExpansion interface{}
 
+type TridentSnapshotPolicyExpansion interface{}
+
 type TridentStorageClassExpansion interface{}
 
 type TridentTransactionExpansion interface{}
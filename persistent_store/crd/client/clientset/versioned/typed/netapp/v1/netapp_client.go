@@ -14,8 +14,10 @@ type TridentV1Interface interface {
 	RESTClient() rest.Interface
 	TridentBackendsGetter
 	TridentBackendConfigsGetter
+	TridentMirrorRelationshipsGetter
 	TridentNodesGetter
 	TridentSnapshotsGetter
+	TridentSnapshotPoliciesGetter
 	TridentStorageClassesGetter
 	TridentTransactionsGetter
 	TridentVersionsGetter
@@ -35,6 +37,10 @@ func (c *TridentV1Client) TridentBackendConfigs(namespace string) TridentBackend
 	return newTridentBackendConfigs(c, namespace)
 }
 
+func (c *TridentV1Client) TridentMirrorRelationships(namespace string) TridentMirrorRelationshipInterface {
+	return newTridentMirrorRelationships(c, namespace)
+}
+
 func (c *TridentV1Client) TridentNodes(namespace string) TridentNodeInterface {
 	return newTridentNodes(c, namespace)
 }
@@ -43,6 +49,10 @@ func (c *TridentV1Client) TridentSnapshots(namespace string) TridentSnapshotInte
 	return newTridentSnapshots(c, namespace)
 }
 
+func (c *TridentV1Client) TridentSnapshotPolicies(namespace string) TridentSnapshotPolicyInterface {
+	return newTridentSnapshotPolicies(c, namespace)
+}
+
 func (c *TridentV1Client) TridentStorageClasses(namespace string) TridentStorageClassInterface {
 	return newTridentStorageClasses(c, namespace)
 }
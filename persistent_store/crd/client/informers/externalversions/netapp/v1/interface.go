@@ -14,10 +14,14 @@ type Interface interface {
 	TridentBackends() TridentBackendInformer
 	// TridentBackendConfigs returns a TridentBackendConfigInformer.
 	TridentBackendConfigs() TridentBackendConfigInformer
+	// TridentMirrorRelationships returns a TridentMirrorRelationshipInformer.
+	TridentMirrorRelationships() TridentMirrorRelationshipInformer
 	// TridentNodes returns a TridentNodeInformer.
 	TridentNodes() TridentNodeInformer
 	// TridentSnapshots returns a TridentSnapshotInformer.
 	TridentSnapshots() TridentSnapshotInformer
+	// TridentSnapshotPolicies returns a TridentSnapshotPolicyInformer.
+	TridentSnapshotPolicies() TridentSnapshotPolicyInformer
 	// TridentStorageClasses returns a TridentStorageClassInformer.
 	TridentStorageClasses() TridentStorageClassInformer
 	// TridentTransactions returns a TridentTransactionInformer.
@@ -49,6 +53,11 @@ func (v *version) TridentBackendConfigs() TridentBackendConfigInformer {
 	return &tridentBackendConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// TridentMirrorRelationships returns a TridentMirrorRelationshipInformer.
+func (v *version) TridentMirrorRelationships() TridentMirrorRelationshipInformer {
+	return &tridentMirrorRelationshipInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TridentNodes returns a TridentNodeInformer.
 func (v *version) TridentNodes() TridentNodeInformer {
 	return &tridentNodeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -59,6 +68,11 @@ func (v *version) TridentSnapshots() TridentSnapshotInformer {
 	return &tridentSnapshotInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// TridentSnapshotPolicies returns a TridentSnapshotPolicyInformer.
+func (v *version) TridentSnapshotPolicies() TridentSnapshotPolicyInformer {
+	return &tridentSnapshotPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TridentStorageClasses returns a TridentStorageClassInformer.
 func (v *version) TridentStorageClasses() TridentStorageClassInformer {
 	return &tridentStorageClassInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
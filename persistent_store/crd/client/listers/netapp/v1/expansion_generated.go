@@ -20,6 +20,14 @@ type TridentBackendConfigListerExpansion interface{}
 // TridentBackendConfigNamespaceLister.
 type TridentBackendConfigNamespaceListerExpansion interface{}
 
+// TridentMirrorRelationshipListerExpansion allows custom methods to be added to
+// TridentMirrorRelationshipLister.
+type TridentMirrorRelationshipListerExpansion interface{}
+
+// TridentMirrorRelationshipNamespaceListerExpansion allows custom methods to be added to
+// TridentMirrorRelationshipNamespaceLister.
+type TridentMirrorRelationshipNamespaceListerExpansion interface{}
+
 // TridentNodeListerExpansion allows custom methods to be added to
 // TridentNodeLister.
 type TridentNodeListerExpansion interface{}
@@ -36,6 +44,14 @@ type TridentSnapshotListerExpansion interface{}
 // TridentSnapshotNamespaceLister.
 type TridentSnapshotNamespaceListerExpansion interface{}
 
+// TridentSnapshotPolicyListerExpansion allows custom methods to be added to
+// TridentSnapshotPolicyLister.
+type TridentSnapshotPolicyListerExpansion interface{}
+
+// TridentSnapshotPolicyNamespaceListerExpansion allows custom methods to be added to
+// TridentSnapshotPolicyNamespaceLister.
+type TridentSnapshotPolicyNamespaceListerExpansion interface{}
+
 // TridentStorageClassListerExpansion allows custom methods to be added to
 // TridentStorageClassLister.
 type TridentStorageClassListerExpansion interface{}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -43,10 +44,12 @@ var (
 
 // CRDClientV1 stores persistent state in CRD objects in Kubernetes
 type CRDClientV1 struct {
-	crdClient versioned.Interface
-	k8sClient k8sclient.Interface
-	version   *config.PersistentStateVersion
-	namespace string
+	crdClient      versioned.Interface
+	k8sClient      k8sclient.Interface
+	version        *config.PersistentStateVersion
+	namespace      string
+	watchCache     *crdWatchCache
+	watchCacheLock sync.RWMutex
 }
 
 func NewCRDClientV1(apiServerIP, kubeConfigPath string) (*CRDClientV1, error) {
@@ -121,17 +124,17 @@ func newCRDClientV1(ctx context.Context, kubeConfig *rest.Config, k8sClient k8sc
 
 func (k *CRDClientV1) GetVersion(ctx context.Context) (*config.PersistentStateVersion, error) {
 
-	versionList, err := k.crdClient.TridentV1().TridentVersions(k.namespace).List(ctx, listOpts)
+	versions, err := k.listVersions(ctx)
 	if err != nil {
 		if strings.Contains(err.Error(), "the server could not find the requested resource") {
 			return nil, NewPersistentStoreError(KeyNotFoundErr, v1.PersistentStateVersionName)
 		}
 		return nil, err
-	} else if versionList == nil || versionList.Items == nil || len(versionList.Items) == 0 {
+	} else if len(versions) == 0 {
 		return nil, NewPersistentStoreError(KeyNotFoundErr, v1.PersistentStateVersionName)
 	}
 
-	persistentVersion, err := versionList.Items[0].Persistent()
+	persistentVersion, err := versions[0].Persistent()
 	if err != nil {
 		return nil, err
 	}
@@ -196,6 +199,7 @@ func (k *CRDClientV1) GetType() StoreType {
 }
 
 func (k *CRDClientV1) Stop() error {
+	k.StopWatchCache(GenerateRequestContext(nil, "", ContextSourceInternal))
 	return nil
 }
 
@@ -782,14 +786,14 @@ func (k *CRDClientV1) IsBackendDeleting(ctx context.Context, b *storage.Backend)
 func (k *CRDClientV1) GetBackends(ctx context.Context) ([]*storage.BackendPersistent, error) {
 
 	// Get the backend resources
-	backendList, err := k.crdClient.TridentV1().TridentBackends(k.namespace).List(ctx, listOpts)
+	backends, err := k.listBackends(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*storage.BackendPersistent, 0)
 
-	for _, backend := range backendList.Items {
+	for _, backend := range backends {
 
 		// Convert backend resource into BackendPersistent object
 		backendPersistent, err := backend.Persistent()
@@ -1079,14 +1083,14 @@ func (k *CRDClientV1) DeleteVolumeIgnoreNotFound(ctx context.Context, volume *st
 
 func (k *CRDClientV1) GetVolumes(ctx context.Context) ([]*storage.VolumeExternal, error) {
 
-	volumeList, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).List(ctx, listOpts)
+	volumes, err := k.listVolumes(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*storage.VolumeExternal, 0)
 
-	for _, item := range volumeList.Items {
+	for _, item := range volumes {
 		if !item.ObjectMeta.DeletionTimestamp.IsZero() {
 			Logc(ctx).WithFields(log.Fields{
 				"Name":              item.Name,
@@ -1157,14 +1161,14 @@ func (k *CRDClientV1) HasVolumeTransactions(ctx context.Context) (bool, error) {
 
 func (k *CRDClientV1) GetVolumeTransactions(ctx context.Context) ([]*storage.VolumeTransaction, error) {
 
-	txnList, err := k.crdClient.TridentV1().TridentTransactions(k.namespace).List(ctx, listOpts)
+	txns, err := k.listTransactions(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*storage.VolumeTransaction, 0)
 
-	for _, item := range txnList.Items {
+	for _, item := range txns {
 		if !item.ObjectMeta.DeletionTimestamp.IsZero() {
 			Logc(ctx).WithFields(log.Fields{
 				"Name":              item.Name,
@@ -1287,14 +1291,14 @@ func (k *CRDClientV1) GetStorageClass(ctx context.Context, scName string) (*stor
 
 func (k *CRDClientV1) GetStorageClasses(ctx context.Context) ([]*storageclass.Persistent, error) {
 
-	scList, err := k.crdClient.TridentV1().TridentStorageClasses(k.namespace).List(ctx, listOpts)
+	storageClasses, err := k.listStorageClasses(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*storageclass.Persistent, 0)
 
-	for _, item := range scList.Items {
+	for _, item := range storageClasses {
 		if !item.ObjectMeta.DeletionTimestamp.IsZero() {
 			Logc(ctx).WithFields(log.Fields{
 				"Name":              item.Name,
@@ -1372,14 +1376,14 @@ func (k *CRDClientV1) GetNode(ctx context.Context, nName string) (*utils.Node, e
 
 func (k *CRDClientV1) GetNodes(ctx context.Context) ([]*utils.Node, error) {
 
-	nodeList, err := k.crdClient.TridentV1().TridentNodes(k.namespace).List(ctx, listOpts)
+	nodes, err := k.listNodes(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*utils.Node, 0)
 
-	for _, item := range nodeList.Items {
+	for _, item := range nodes {
 		if !item.ObjectMeta.DeletionTimestamp.IsZero() {
 			Logc(ctx).WithFields(log.Fields{
 				"Name":              item.Name,
@@ -1447,14 +1451,14 @@ func (k *CRDClientV1) GetSnapshot(ctx context.Context, volumeName, snapshotName
 
 func (k *CRDClientV1) GetSnapshots(ctx context.Context) ([]*storage.SnapshotPersistent, error) {
 
-	snapshotList, err := k.crdClient.TridentV1().TridentSnapshots(k.namespace).List(ctx, listOpts)
+	snapshots, err := k.listSnapshots(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	results := make([]*storage.SnapshotPersistent, 0)
 
-	for _, item := range snapshotList.Items {
+	for _, item := range snapshots {
 		if !item.ObjectMeta.DeletionTimestamp.IsZero() {
 			Logc(ctx).WithFields(log.Fields{
 				"Name":              item.Name,
@@ -0,0 +1,278 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	. "github.com/netapp/trident/logger"
+	v1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	tridentinformers "github.com/netapp/trident/persistent_store/crd/client/informers/externalversions"
+	tridentinformersv1 "github.com/netapp/trident/persistent_store/crd/client/informers/externalversions/netapp/v1"
+	listers "github.com/netapp/trident/persistent_store/crd/client/listers/netapp/v1"
+)
+
+// crdWatchCache holds informers on Trident's custom resources.  A standby Trident controller keeps
+// one running, so that it already has a current copy of the persistent state when it becomes leader.
+type crdWatchCache struct {
+	stopChan       chan struct{}
+	backends       listers.TridentBackendLister
+	nodes          listers.TridentNodeLister
+	snapshots      listers.TridentSnapshotLister
+	storageClasses listers.TridentStorageClassLister
+	transactions   listers.TridentTransactionLister
+	versions       listers.TridentVersionLister
+	volumes        listers.TridentVolumeLister
+}
+
+// StartWatchCache starts informers on Trident's custom resources and waits for them to sync.  While
+// the cache is running, the methods that read all objects of a type (as bootstrapping does) are
+// served from it instead of from the API server.  Everything else, including all writes, always
+// goes to the API server.
+func (k *CRDClientV1) StartWatchCache(ctx context.Context) error {
+
+	k.watchCacheLock.Lock()
+	defer k.watchCacheLock.Unlock()
+
+	if k.watchCache != nil {
+		return nil
+	}
+
+	Logc(ctx).Debug("Starting CRD watch cache.")
+
+	// Set resync to 0 sec, since the cache only needs to follow changes made by the leader
+	informerFactory := tridentinformers.NewSharedInformerFactory(k.crdClient, 0)
+	crdInformer := tridentinformersv1.New(informerFactory, k.namespace, nil)
+
+	backendInformer := crdInformer.TridentBackends()
+	nodeInformer := crdInformer.TridentNodes()
+	snapshotInformer := crdInformer.TridentSnapshots()
+	storageClassInformer := crdInformer.TridentStorageClasses()
+	transactionInformer := crdInformer.TridentTransactions()
+	versionInformer := crdInformer.TridentVersions()
+	volumeInformer := crdInformer.TridentVolumes()
+
+	watchCache := &crdWatchCache{
+		stopChan:       make(chan struct{}),
+		backends:       backendInformer.Lister(),
+		nodes:          nodeInformer.Lister(),
+		snapshots:      snapshotInformer.Lister(),
+		storageClasses: storageClassInformer.Lister(),
+		transactions:   transactionInformer.Lister(),
+		versions:       versionInformer.Lister(),
+		volumes:        volumeInformer.Lister(),
+	}
+
+	informerFactory.Start(watchCache.stopChan)
+
+	if ok := cache.WaitForCacheSync(ctx.Done(),
+		backendInformer.Informer().HasSynced,
+		nodeInformer.Informer().HasSynced,
+		snapshotInformer.Informer().HasSynced,
+		storageClassInformer.Informer().HasSynced,
+		transactionInformer.Informer().HasSynced,
+		versionInformer.Informer().HasSynced,
+		volumeInformer.Informer().HasSynced,
+	); !ok {
+		close(watchCache.stopChan)
+		return fmt.Errorf("CRD watch cache did not sync")
+	}
+
+	k.watchCache = watchCache
+
+	Logc(ctx).Info("CRD watch cache synced.")
+
+	return nil
+}
+
+// StopWatchCache stops the informers started by StartWatchCache, after which all reads go to the
+// API server again.
+func (k *CRDClientV1) StopWatchCache(ctx context.Context) {
+
+	k.watchCacheLock.Lock()
+	defer k.watchCacheLock.Unlock()
+
+	if k.watchCache == nil {
+		return
+	}
+
+	close(k.watchCache.stopChan)
+	k.watchCache = nil
+
+	Logc(ctx).Debug("Stopped CRD watch cache.")
+}
+
+// The list methods below return all custom resources of one type, either from the watch cache if it
+// is running or from the API server.  Objects from the cache are copied, since the cache owns them.
+
+func (k *CRDClientV1) listBackends(ctx context.Context) ([]*v1.TridentBackend, error) {
+
+	k.watchCacheLock.RLock()
+	defer k.watchCacheLock.RUnlock()
+
+	if k.watchCache == nil {
+		list, err := k.crdClient.TridentV1().TridentBackends(k.namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+
+	cached, err := k.watchCache.backends.TridentBackends(k.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*v1.TridentBackend, 0, len(cached))
+	for _, item := range cached {
+		results = append(results, item.DeepCopy())
+	}
+	return results, nil
+}
+
+func (k *CRDClientV1) listNodes(ctx context.Context) ([]*v1.TridentNode, error) {
+
+	k.watchCacheLock.RLock()
+	defer k.watchCacheLock.RUnlock()
+
+	if k.watchCache == nil {
+		list, err := k.crdClient.TridentV1().TridentNodes(k.namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+
+	cached, err := k.watchCache.nodes.TridentNodes(k.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*v1.TridentNode, 0, len(cached))
+	for _, item := range cached {
+		results = append(results, item.DeepCopy())
+	}
+	return results, nil
+}
+
+func (k *CRDClientV1) listSnapshots(ctx context.Context) ([]*v1.TridentSnapshot, error) {
+
+	k.watchCacheLock.RLock()
+	defer k.watchCacheLock.RUnlock()
+
+	if k.watchCache == nil {
+		list, err := k.crdClient.TridentV1().TridentSnapshots(k.namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+
+	cached, err := k.watchCache.snapshots.TridentSnapshots(k.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*v1.TridentSnapshot, 0, len(cached))
+	for _, item := range cached {
+		results = append(results, item.DeepCopy())
+	}
+	return results, nil
+}
+
+func (k *CRDClientV1) listStorageClasses(ctx context.Context) ([]*v1.TridentStorageClass, error) {
+
+	k.watchCacheLock.RLock()
+	defer k.watchCacheLock.RUnlock()
+
+	if k.watchCache == nil {
+		list, err := k.crdClient.TridentV1().TridentStorageClasses(k.namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+
+	cached, err := k.watchCache.storageClasses.TridentStorageClasses(k.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*v1.TridentStorageClass, 0, len(cached))
+	for _, item := range cached {
+		results = append(results, item.DeepCopy())
+	}
+	return results, nil
+}
+
+func (k *CRDClientV1) listTransactions(ctx context.Context) ([]*v1.TridentTransaction, error) {
+
+	k.watchCacheLock.RLock()
+	defer k.watchCacheLock.RUnlock()
+
+	if k.watchCache == nil {
+		list, err := k.crdClient.TridentV1().TridentTransactions(k.namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+
+	cached, err := k.watchCache.transactions.TridentTransactions(k.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*v1.TridentTransaction, 0, len(cached))
+	for _, item := range cached {
+		results = append(results, item.DeepCopy())
+	}
+	return results, nil
+}
+
+func (k *CRDClientV1) listVersions(ctx context.Context) ([]*v1.TridentVersion, error) {
+
+	k.watchCacheLock.RLock()
+	defer k.watchCacheLock.RUnlock()
+
+	if k.watchCache == nil {
+		list, err := k.crdClient.TridentV1().TridentVersions(k.namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+
+	cached, err := k.watchCache.versions.TridentVersions(k.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*v1.TridentVersion, 0, len(cached))
+	for _, item := range cached {
+		results = append(results, item.DeepCopy())
+	}
+	return results, nil
+}
+
+func (k *CRDClientV1) listVolumes(ctx context.Context) ([]*v1.TridentVolume, error) {
+
+	k.watchCacheLock.RLock()
+	defer k.watchCacheLock.RUnlock()
+
+	if k.watchCache == nil {
+		list, err := k.crdClient.TridentV1().TridentVolumes(k.namespace).List(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+
+	cached, err := k.watchCache.volumes.TridentVolumes(k.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*v1.TridentVolume, 0, len(cached))
+	for _, item := range cached {
+		results = append(results, item.DeepCopy())
+	}
+	return results, nil
+}
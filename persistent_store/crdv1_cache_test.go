@@ -0,0 +1,100 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/persistent_store/crd/client/clientset/versioned/fake"
+	"github.com/netapp/trident/storage"
+)
+
+func TestKubernetesWatchCache(t *testing.T) {
+
+	p, _ := GetTestKubernetesClient()
+
+	assert.NoError(t, p.SetVersion(ctx(), &config.PersistentStateVersion{
+		PersistentStoreVersion: string(CRDV1Store),
+		OrchestratorAPIVersion: config.OrchestratorAPIVersion,
+	}))
+	assert.NoError(t, p.AddVolume(ctx(), getFakeVolume(getFakeBackend())))
+	assert.NoError(t, p.AddStorageClass(ctx(), getFakeStorageClass()))
+	assert.NoError(t, p.AddSnapshot(ctx(), getFakeSnapshot()))
+	assert.NoError(t, p.AddOrUpdateNode(ctx(), getFakeNode()))
+
+	syncCtx, cancel := context.WithTimeout(ctx(), 30*time.Second)
+	defer cancel()
+	assert.NoError(t, p.StartWatchCache(syncCtx))
+	assert.NotNil(t, p.watchCache)
+
+	// Starting the cache again is harmless
+	assert.NoError(t, p.StartWatchCache(syncCtx))
+
+	// Bootstrapping from the cache doesn't list anything from the API server
+	listActions := func() int {
+		count := 0
+		for _, action := range p.crdClient.(*fake.Clientset).Actions() {
+			if action.GetVerb() == "list" {
+				count++
+			}
+		}
+		return count
+	}
+	listsBeforeBootstrap := listActions()
+
+	version, err := p.GetVersion(ctx())
+	assert.NoError(t, err)
+	assert.Equal(t, config.OrchestratorAPIVersion, version.OrchestratorAPIVersion)
+	volumes, err := p.GetVolumes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, volumes, 1)
+	storageClasses, err := p.GetStorageClasses(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, storageClasses, 1)
+	snapshots, err := p.GetSnapshots(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+	nodes, err := p.GetNodes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	backends, err := p.GetBackends(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, backends, 0)
+	txns, err := p.GetVolumeTransactions(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, txns, 0)
+	assert.Equal(t, listsBeforeBootstrap, listActions())
+
+	// The cache follows changes made through the API server
+	assert.NoError(t, p.AddVolume(ctx(), getFakeVolumeWithName("cachedVolume", getFakeBackend())))
+	assert.Eventually(t, func() bool {
+		volumes, err := p.GetVolumes(ctx())
+		return err == nil && len(volumes) == 2
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// Objects listed from the cache may be modified without affecting it
+	cached, err := p.listVolumes(ctx())
+	assert.NoError(t, err)
+	for _, cachedVolume := range cached {
+		cachedVolume.State = string(storage.VolumeStateDeleting)
+	}
+	cached, err = p.listVolumes(ctx())
+	assert.NoError(t, err)
+	for _, cachedVolume := range cached {
+		assert.NotEqual(t, string(storage.VolumeStateDeleting), cachedVolume.State)
+	}
+
+	p.StopWatchCache(ctx())
+	assert.Nil(t, p.watchCache)
+
+	// Reads go to the API server again
+	volumes, err = p.GetVolumes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, volumes, 2)
+	assert.Greater(t, listActions(), listsBeforeBootstrap)
+}
@@ -1,9 +1,18 @@
-// Copyright 2019 NetApp, Inc. All Rights Reserved.
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
 
 package persistentstore
 
 import (
+	"context"
+	"fmt"
+
 	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	sc "github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/utils"
 )
 
 type DataMigrator struct {
@@ -12,6 +21,17 @@ type DataMigrator struct {
 	dryRun       bool
 }
 
+// migrationData holds everything read from the source store, so that nothing is written to the
+// destination store unless all of it could be read.
+type migrationData struct {
+	backends       []*storage.BackendPersistent
+	storageClasses []*sc.Persistent
+	volumes        []*storage.VolumeExternal
+	snapshots      []*storage.SnapshotPersistent
+	volumeTxns     []*storage.VolumeTransaction
+	nodes          []*utils.Node
+}
+
 func NewDataMigrator(SourceClient, DestClient Client, dryRun bool) *DataMigrator {
 	return &DataMigrator{
 		SourceClient: SourceClient,
@@ -20,10 +40,199 @@ func NewDataMigrator(SourceClient, DestClient Client, dryRun bool) *DataMigrator
 	}
 }
 
+// Run copies the orchestrator state from the source store to the destination store.  The only
+// supported migrations are from the passthrough and in-memory stores to the bolt store, and only
+// into a bolt store that has never been used, which is known by it having no persistent state
+// version.  The version is written last, so an interrupted migration is started over.
 func (m *DataMigrator) Run() error {
 
+	ctx := GenerateRequestContext(nil, "", ContextSourceInternal)
+
+	sourceType := m.SourceClient.GetType()
+	destType := m.DestClient.GetType()
+	fields := log.Fields{"source": sourceType, "destination": destType, "dryRun": m.dryRun}
+
 	// Determine if this is a supported data migration.
-	log.Debug("DataMigrator does not currently support any migrations.")
+	if destType != BoltStore || (sourceType != PassthroughStore && sourceType != MemoryStore) {
+		Logc(ctx).WithFields(fields).Debug("DataMigrator does not support this migration.")
+		return nil
+	}
+
+	if _, err := m.DestClient.GetVersion(ctx); err == nil {
+		Logc(ctx).WithFields(fields).Debug("Destination store is in use, no data migration needed.")
+		return nil
+	} else if !MatchKeyNotFoundErr(err) {
+		return fmt.Errorf("could not read persistent state version of %s store; %v", destType, err)
+	}
+
+	Logc(ctx).WithFields(fields).Info("Migrating data between persistent stores.")
+
+	data, err := m.read(ctx)
+	if err != nil {
+		return fmt.Errorf("could not read data from %s store; %v", sourceType, err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backends":       len(data.backends),
+		"storageClasses": len(data.storageClasses),
+		"volumes":        len(data.volumes),
+		"snapshots":      len(data.snapshots),
+		"transactions":   len(data.volumeTxns),
+		"nodes":          len(data.nodes),
+		"dryRun":         m.dryRun,
+	}).Info("Read data to migrate.")
+
+	if m.dryRun {
+		return nil
+	}
+
+	if err = m.write(ctx, data); err != nil {
+		return fmt.Errorf("could not write data to %s store; %v", destType, err)
+	}
+
+	Logc(ctx).WithFields(fields).Info("Data migration complete.")
+
+	return nil
+}
+
+// read gets everything to be migrated from the source store.  The passthrough store only knows
+// about volumes once its backends have been started, so they are started here.
+func (m *DataMigrator) read(ctx context.Context) (*migrationData, error) {
+
+	passthroughClient, ok := m.SourceClient.(*PassthroughClient)
+	if !ok {
+		return readData(ctx, m.SourceClient)
+	}
+
+	defer passthroughClient.stopBackends(ctx)
+	backends, err := passthroughClient.startBackends(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readData(ctx, m.SourceClient)
+	if err != nil {
+		return nil, err
+	}
+	data.backends = backends
+
+	return data, nil
+}
+
+// readData gets everything stored by a client.
+func readData(ctx context.Context, client Client) (*migrationData, error) {
+
+	data := &migrationData{}
+	var err error
+
+	if data.backends, err = client.GetBackends(ctx); err != nil {
+		return nil, err
+	}
+	if data.storageClasses, err = client.GetStorageClasses(ctx); err != nil {
+		return nil, err
+	}
+	if data.volumes, err = client.GetVolumes(ctx); err != nil {
+		return nil, err
+	}
+	if data.snapshots, err = client.GetSnapshots(ctx); err != nil {
+		return nil, err
+	}
+	if data.volumeTxns, err = client.GetVolumeTransactions(ctx); err != nil && !MatchKeyNotFoundErr(err) {
+		return nil, err
+	}
+	if data.nodes, err = client.GetNodes(ctx); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// write replaces the contents of the destination store with the migrated data, then sets its
+// persistent state version.
+func (m *DataMigrator) write(ctx context.Context, data *migrationData) error {
+
+	// Remove anything left by an interrupted migration
+	for _, deleteAll := range []func(context.Context) error{
+		m.DestClient.DeleteBackends, m.DestClient.DeleteVolumes, m.DestClient.DeleteSnapshots,
+	} {
+		if err := deleteAll(ctx); err != nil && !MatchKeyNotFoundErr(err) {
+			return err
+		}
+	}
+	if err := m.deleteStorageClassesTransactionsAndNodes(ctx); err != nil {
+		return err
+	}
+
+	for _, backend := range data.backends {
+		if err := m.DestClient.AddBackendPersistent(ctx, backend); err != nil {
+			return err
+		}
+	}
+	for _, storageClass := range data.storageClasses {
+		if err := m.DestClient.AddStorageClass(ctx, sc.NewFromPersistent(storageClass)); err != nil {
+			return err
+		}
+	}
+	for _, volume := range data.volumes {
+		if err := m.DestClient.AddVolumePersistent(ctx, volume); err != nil {
+			return err
+		}
+	}
+	for _, snapshot := range data.snapshots {
+		if err := m.DestClient.AddSnapshot(ctx, &snapshot.Snapshot); err != nil {
+			return err
+		}
+	}
+	for _, volTxn := range data.volumeTxns {
+		if err := m.DestClient.AddVolumeTransaction(ctx, volTxn); err != nil {
+			return err
+		}
+	}
+	for _, node := range data.nodes {
+		if err := m.DestClient.AddOrUpdateNode(ctx, node); err != nil {
+			return err
+		}
+	}
+
+	return m.DestClient.SetVersion(ctx, &config.PersistentStateVersion{
+		PersistentStoreVersion: string(m.DestClient.GetType()),
+		OrchestratorAPIVersion: config.OrchestratorAPIVersion,
+	})
+}
+
+// deleteStorageClassesTransactionsAndNodes removes the objects for which the Client interface has
+// no method to delete all of them at once.
+func (m *DataMigrator) deleteStorageClassesTransactionsAndNodes(ctx context.Context) error {
+
+	storageClasses, err := m.DestClient.GetStorageClasses(ctx)
+	if err != nil {
+		return err
+	}
+	for _, storageClass := range storageClasses {
+		if err = m.DestClient.DeleteStorageClass(ctx, sc.NewFromPersistent(storageClass)); err != nil {
+			return err
+		}
+	}
+
+	volTxns, err := m.DestClient.GetVolumeTransactions(ctx)
+	if err != nil && !MatchKeyNotFoundErr(err) {
+		return err
+	}
+	for _, volTxn := range volTxns {
+		if err = m.DestClient.DeleteVolumeTransaction(ctx, volTxn); err != nil {
+			return err
+		}
+	}
+
+	nodes, err := m.DestClient.GetNodes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err = m.DestClient.DeleteNode(ctx, node); err != nil {
+			return err
+		}
+	}
 
-	return nil //crdDataMigrator.Run()
+	return nil
 }
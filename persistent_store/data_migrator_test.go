@@ -0,0 +1,117 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataMigrator_MemoryToBolt(t *testing.T) {
+
+	source := NewInMemoryClient()
+	backend := getFakeBackend()
+	backend.BackendUUID = "1f5a3e8d-0b44-4a5c-b3c9-6b6d1b0a1d2e"
+	assert.NoError(t, source.AddBackend(ctx(), backend))
+	assert.NoError(t, source.AddVolume(ctx(), getFakeVolume(backend)))
+	assert.NoError(t, source.AddStorageClass(ctx(), getFakeStorageClass()))
+	assert.NoError(t, source.AddSnapshot(ctx(), getFakeSnapshot()))
+	assert.NoError(t, source.AddVolumeTransaction(ctx(), getFakeVolumeTransaction()))
+	assert.NoError(t, source.AddOrUpdateNode(ctx(), getFakeNode()))
+
+	dest, path := newTestBoltClient(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	defer dest.Stop()
+
+	// A dry run reads the source but writes nothing
+	assert.NoError(t, NewDataMigrator(source, dest, true).Run())
+	_, err := dest.GetVersion(ctx())
+	assert.True(t, MatchKeyNotFoundErr(err))
+
+	assert.NoError(t, NewDataMigrator(source, dest, false).Run())
+
+	version, err := dest.GetVersion(ctx())
+	assert.NoError(t, err)
+	assert.Equal(t, string(BoltStore), version.PersistentStoreVersion)
+
+	backends, err := dest.GetBackends(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, backends, 1)
+	volumes, err := dest.GetVolumes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, volumes, 1)
+	storageClasses, err := dest.GetStorageClasses(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, storageClasses, 1)
+	snapshots, err := dest.GetSnapshots(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+	volTxns, err := dest.GetVolumeTransactions(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, volTxns, 1)
+	nodes, err := dest.GetNodes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+
+	// Once the destination is in use, nothing more is migrated
+	assert.NoError(t, source.AddVolume(ctx(), getFakeVolumeWithName("vol2", backend)))
+	assert.NoError(t, NewDataMigrator(source, dest, false).Run())
+	volumes, err = dest.GetVolumes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, volumes, 1)
+}
+
+func TestDataMigrator_PassthroughToBolt(t *testing.T) {
+
+	configPath, err := ioutil.TempDir("", "passthrough")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(configPath)
+
+	for _, name := range []string{"backend1", "backend2"} {
+		backendJSON, _ := getFakeBackendWithName(name).ConstructPersistent(ctx()).MarshalConfig()
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(configPath, name), []byte(backendJSON), 0644))
+	}
+	storageClassesJSON := `{"storageClasses": [{"name": "gold"}]}`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configPath, "sc"), []byte(storageClassesJSON), 0644))
+
+	source, err := NewPassthroughClient(configPath)
+	assert.NoError(t, err)
+
+	dest, path := newTestBoltClient(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	defer dest.Stop()
+
+	assert.NoError(t, NewDataMigrator(source, dest, false).Run())
+
+	backends, err := dest.GetBackends(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, backends, 2)
+	for _, backend := range backends {
+		assert.NotEmpty(t, backend.Name)
+		assert.NotEmpty(t, backend.BackendUUID)
+	}
+
+	_, err = dest.GetStorageClass(ctx(), "gold")
+	assert.NoError(t, err)
+
+	// The backends started to read volumes are stopped afterward
+	assert.Len(t, source.liveBackends, 0)
+}
+
+func TestDataMigrator_Unsupported(t *testing.T) {
+
+	dest, path := newTestBoltClient(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	defer dest.Stop()
+
+	// Only migrations to the bolt store are supported
+	assert.NoError(t, NewDataMigrator(dest, NewInMemoryClient(), false).Run())
+	_, err := dest.GetVersion(ctx())
+	assert.True(t, MatchKeyNotFoundErr(err))
+}
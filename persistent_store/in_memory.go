@@ -5,6 +5,7 @@ package persistentstore
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/storage"
@@ -26,6 +27,7 @@ type InMemoryClient struct {
 	nodesAdded          int
 	snapshots           map[string]*storage.SnapshotPersistent
 	snapshotsAdded      int
+	mutex               sync.Mutex
 }
 
 func NewInMemoryClient() *InMemoryClient {
@@ -47,6 +49,9 @@ func (c *InMemoryClient) GetType() StoreType {
 }
 
 func (c *InMemoryClient) Stop() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	c.backendsAdded = 0
 	c.volumesAdded = 0
 	c.storageClassesAdded = 0
@@ -61,6 +66,9 @@ func (c *InMemoryClient) GetConfig() *ClientConfig {
 }
 
 func (c *InMemoryClient) GetVersion(context.Context) (*config.PersistentStateVersion, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	return c.version, nil
 }
 
@@ -69,6 +77,9 @@ func (c *InMemoryClient) SetVersion(context.Context, *config.PersistentStateVers
 }
 
 func (c *InMemoryClient) AddBackend(ctx context.Context, b *storage.Backend) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	backend := b.ConstructPersistent(ctx)
 	if _, ok := c.backends[backend.Name]; ok {
 		return fmt.Errorf("backend %s already exists", backend.Name)
@@ -79,6 +90,9 @@ func (c *InMemoryClient) AddBackend(ctx context.Context, b *storage.Backend) err
 }
 
 func (c *InMemoryClient) AddBackendPersistent(_ context.Context, backend *storage.BackendPersistent) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.backends[backend.Name]; ok {
 		return fmt.Errorf("backend %s already exists", backend.Name)
 	}
@@ -88,6 +102,9 @@ func (c *InMemoryClient) AddBackendPersistent(_ context.Context, backend *storag
 }
 
 func (c *InMemoryClient) GetBackend(_ context.Context, backendName string) (*storage.BackendPersistent, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret, ok := c.backends[backendName]
 	if !ok {
 		return nil, NewPersistentStoreError(KeyNotFoundErr, backendName)
@@ -100,6 +117,9 @@ func (c *InMemoryClient) GetBackendSecret(_ context.Context, _ string) (map[stri
 }
 
 func (c *InMemoryClient) UpdateBackend(ctx context.Context, b *storage.Backend) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	// UpdateBackend requires the backend to already exist.
 	if _, ok := c.backends[b.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, b.Name)
@@ -110,6 +130,9 @@ func (c *InMemoryClient) UpdateBackend(ctx context.Context, b *storage.Backend)
 
 // UpdateBackendPersistent updates a backend's persistent state
 func (c *InMemoryClient) UpdateBackendPersistent(_ context.Context, update *storage.BackendPersistent) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	// UpdateBackend requires the backend to already exist.
 	if _, ok := c.backends[update.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, update.Name)
@@ -119,6 +142,9 @@ func (c *InMemoryClient) UpdateBackendPersistent(_ context.Context, update *stor
 }
 
 func (c *InMemoryClient) DeleteBackend(_ context.Context, b *storage.Backend) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.backends[b.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, b.Name)
 	}
@@ -140,6 +166,9 @@ func (c *InMemoryClient) ReplaceBackendAndUpdateVolumes(
 }
 
 func (c *InMemoryClient) GetBackends(context.Context) ([]*storage.BackendPersistent, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	backendList := make([]*storage.BackendPersistent, 0)
 	if c.backendsAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
@@ -152,6 +181,9 @@ func (c *InMemoryClient) GetBackends(context.Context) ([]*storage.BackendPersist
 }
 
 func (c *InMemoryClient) DeleteBackends(context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if c.backendsAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
 		return NewPersistentStoreError(KeyNotFoundErr, "Backends")
@@ -161,6 +193,9 @@ func (c *InMemoryClient) DeleteBackends(context.Context) error {
 }
 
 func (c *InMemoryClient) AddVolume(_ context.Context, vol *storage.Volume) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	volume := vol.ConstructExternal()
 	if _, ok := c.volumes[volume.Config.Name]; ok {
 		return fmt.Errorf("volume %s already exists", volume.Config.Name)
@@ -172,6 +207,9 @@ func (c *InMemoryClient) AddVolume(_ context.Context, vol *storage.Volume) error
 
 // AddVolumePersistent saves a volume's persistent state to the persistent store
 func (c *InMemoryClient) AddVolumePersistent(_ context.Context, volume *storage.VolumeExternal) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.volumes[volume.Config.Name]; ok {
 		return fmt.Errorf("volume %s already exists", volume.Config.Name)
 	}
@@ -182,11 +220,17 @@ func (c *InMemoryClient) AddVolumePersistent(_ context.Context, volume *storage.
 
 // UpdateVolumePersistent updates a volume's persistent state
 func (c *InMemoryClient) UpdateVolumePersistent(_ context.Context, volume *storage.VolumeExternal) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	c.volumes[volume.Config.Name] = volume
 	return nil
 }
 
 func (c *InMemoryClient) GetVolume(_ context.Context, volumeName string) (*storage.VolumeExternal, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret, ok := c.volumes[volumeName]
 	if !ok {
 		return nil, NewPersistentStoreError(KeyNotFoundErr, volumeName)
@@ -195,6 +239,9 @@ func (c *InMemoryClient) GetVolume(_ context.Context, volumeName string) (*stora
 }
 
 func (c *InMemoryClient) UpdateVolume(_ context.Context, vol *storage.Volume) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	// UpdateVolume requires the volume to already exist.
 	if _, ok := c.volumes[vol.Config.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, vol.Config.Name)
@@ -204,6 +251,9 @@ func (c *InMemoryClient) UpdateVolume(_ context.Context, vol *storage.Volume) er
 }
 
 func (c *InMemoryClient) DeleteVolume(_ context.Context, vol *storage.Volume) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.volumes[vol.Config.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, vol.Config.Name)
 	}
@@ -212,11 +262,17 @@ func (c *InMemoryClient) DeleteVolume(_ context.Context, vol *storage.Volume) er
 }
 
 func (c *InMemoryClient) DeleteVolumeIgnoreNotFound(_ context.Context, vol *storage.Volume) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	delete(c.volumes, vol.Config.Name)
 	return nil
 }
 
 func (c *InMemoryClient) GetVolumes(context.Context) ([]*storage.VolumeExternal, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret := make([]*storage.VolumeExternal, 0, len(c.volumes))
 	if c.volumesAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
@@ -229,6 +285,9 @@ func (c *InMemoryClient) GetVolumes(context.Context) ([]*storage.VolumeExternal,
 }
 
 func (c *InMemoryClient) DeleteVolumes(context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if c.volumesAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
 		return NewPersistentStoreError(KeyNotFoundErr, "Volumes")
@@ -238,6 +297,9 @@ func (c *InMemoryClient) DeleteVolumes(context.Context) error {
 }
 
 func (c *InMemoryClient) AddVolumeTransaction(_ context.Context, volTxn *storage.VolumeTransaction) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	// AddVolumeTransaction overwrites existing keys, unlike the other methods
 	c.volumeTxns[volTxn.Name()] = volTxn
 	c.volumeTxnsAdded++
@@ -245,6 +307,9 @@ func (c *InMemoryClient) AddVolumeTransaction(_ context.Context, volTxn *storage
 }
 
 func (c *InMemoryClient) GetVolumeTransactions(context.Context) ([]*storage.VolumeTransaction, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if c.volumeTxnsAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
 		return nil, NewPersistentStoreError(KeyNotFoundErr, "VolumesTransactions")
@@ -257,6 +322,9 @@ func (c *InMemoryClient) GetVolumeTransactions(context.Context) ([]*storage.Volu
 }
 
 func (c *InMemoryClient) UpdateVolumeTransaction(_ context.Context, volTxn *storage.VolumeTransaction) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	c.volumeTxns[volTxn.Name()] = volTxn
 	return nil
 }
@@ -264,6 +332,9 @@ func (c *InMemoryClient) UpdateVolumeTransaction(_ context.Context, volTxn *stor
 func (c *InMemoryClient) GetExistingVolumeTransaction(
 	_ context.Context, volTxn *storage.VolumeTransaction,
 ) (*storage.VolumeTransaction, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	vt, ok := c.volumeTxns[volTxn.Name()]
 	if !ok {
 		return nil, nil
@@ -272,6 +343,9 @@ func (c *InMemoryClient) GetExistingVolumeTransaction(
 }
 
 func (c *InMemoryClient) DeleteVolumeTransaction(_ context.Context, volTxn *storage.VolumeTransaction) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.volumeTxns[volTxn.Name()]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, "VolumesTransactions")
 	}
@@ -280,6 +354,9 @@ func (c *InMemoryClient) DeleteVolumeTransaction(_ context.Context, volTxn *stor
 }
 
 func (c *InMemoryClient) AddStorageClass(_ context.Context, s *sc.StorageClass) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	storageClass := s.ConstructPersistent()
 	if _, ok := c.storageClasses[storageClass.GetName()]; ok {
 		return fmt.Errorf("storage class %s already exists", storageClass.GetName())
@@ -290,6 +367,9 @@ func (c *InMemoryClient) AddStorageClass(_ context.Context, s *sc.StorageClass)
 }
 
 func (c *InMemoryClient) GetStorageClass(_ context.Context, scName string) (*sc.Persistent, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret, ok := c.storageClasses[scName]
 	if !ok {
 		return nil, NewPersistentStoreError(KeyNotFoundErr, scName)
@@ -298,6 +378,9 @@ func (c *InMemoryClient) GetStorageClass(_ context.Context, scName string) (*sc.
 }
 
 func (c *InMemoryClient) GetStorageClasses(context.Context) ([]*sc.Persistent, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret := make([]*sc.Persistent, 0, len(c.storageClasses))
 	if c.storageClassesAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
@@ -310,6 +393,9 @@ func (c *InMemoryClient) GetStorageClasses(context.Context) ([]*sc.Persistent, e
 }
 
 func (c *InMemoryClient) DeleteStorageClass(_ context.Context, s *sc.StorageClass) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.storageClasses[s.GetName()]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, s.GetName())
 	}
@@ -318,6 +404,9 @@ func (c *InMemoryClient) DeleteStorageClass(_ context.Context, s *sc.StorageClas
 }
 
 func (c *InMemoryClient) AddOrUpdateNode(_ context.Context, n *utils.Node) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	exists := false
 	if _, ok := c.nodes[n.Name]; ok {
 		exists = true
@@ -330,6 +419,9 @@ func (c *InMemoryClient) AddOrUpdateNode(_ context.Context, n *utils.Node) error
 }
 
 func (c *InMemoryClient) GetNode(_ context.Context, nName string) (*utils.Node, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret, ok := c.nodes[nName]
 	if !ok {
 		return nil, NewPersistentStoreError(KeyNotFoundErr, nName)
@@ -338,6 +430,9 @@ func (c *InMemoryClient) GetNode(_ context.Context, nName string) (*utils.Node,
 }
 
 func (c *InMemoryClient) GetNodes(context.Context) ([]*utils.Node, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret := make([]*utils.Node, 0, len(c.nodes))
 	if c.nodesAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
@@ -350,6 +445,9 @@ func (c *InMemoryClient) GetNodes(context.Context) ([]*utils.Node, error) {
 }
 
 func (c *InMemoryClient) DeleteNode(_ context.Context, n *utils.Node) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.nodes[n.Name]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, n.Name)
 	}
@@ -358,6 +456,9 @@ func (c *InMemoryClient) DeleteNode(_ context.Context, n *utils.Node) error {
 }
 
 func (c *InMemoryClient) AddSnapshot(_ context.Context, snapshot *storage.Snapshot) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	snapPersistent := snapshot.ConstructPersistent()
 	c.snapshots[snapshot.ID()] = snapPersistent
 	c.snapshotsAdded++
@@ -368,6 +469,9 @@ func (c *InMemoryClient) AddSnapshot(_ context.Context, snapshot *storage.Snapsh
 func (c *InMemoryClient) GetSnapshot(_ context.Context, volumeName, snapshotName string) (
 	*storage.SnapshotPersistent, error,
 ) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret, ok := c.snapshots[storage.MakeSnapshotID(volumeName, snapshotName)]
 	if !ok {
 		return nil, NewPersistentStoreError(KeyNotFoundErr, snapshotName)
@@ -377,6 +481,9 @@ func (c *InMemoryClient) GetSnapshot(_ context.Context, volumeName, snapshotName
 
 // GetSnapshots retrieves all snapshots for all volumes
 func (c *InMemoryClient) GetSnapshots(context.Context) ([]*storage.SnapshotPersistent, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	ret := make([]*storage.SnapshotPersistent, 0, len(c.snapshots))
 	if c.snapshotsAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
@@ -389,6 +496,9 @@ func (c *InMemoryClient) GetSnapshots(context.Context) ([]*storage.SnapshotPersi
 }
 
 func (c *InMemoryClient) UpdateSnapshot(_ context.Context, snapshot *storage.Snapshot) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	// UpdateSnapshot requires the snapshot to already exist.
 	if _, ok := c.snapshots[snapshot.ID()]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, snapshot.Config.Name)
@@ -399,6 +509,9 @@ func (c *InMemoryClient) UpdateSnapshot(_ context.Context, snapshot *storage.Sna
 
 // DeleteSnapshot deletes a snapshot from the persistent store
 func (c *InMemoryClient) DeleteSnapshot(_ context.Context, snapshot *storage.Snapshot) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if _, ok := c.snapshots[snapshot.ID()]; !ok {
 		return NewPersistentStoreError(KeyNotFoundErr, snapshot.Config.Name)
 	}
@@ -415,6 +528,9 @@ func (c *InMemoryClient) DeleteSnapshotIgnoreNotFound(ctx context.Context, snaps
 
 // DeleteSnapshots deletes all snapshots
 func (c *InMemoryClient) DeleteSnapshots(context.Context) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	if c.snapshotsAdded == 0 {
 		// Try to match etcd semantics as closely as possible.
 		return NewPersistentStoreError(KeyNotFoundErr, "Snapshots")
@@ -14,20 +14,26 @@ import (
 	"sync"
 
 	"github.com/ghodss/yaml"
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netapp/trident/config"
 	. "github.com/netapp/trident/logger"
 	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage/factory"
 	sc "github.com/netapp/trident/storage_class"
 	drivers "github.com/netapp/trident/storage_drivers"
 	"github.com/netapp/trident/utils"
 )
 
+// storageClassesConfigKey is the config file section in which storage classes may be defined
+const storageClassesConfigKey = "storageClasses"
+
 type PassthroughClient struct {
-	liveBackends map[string]*storage.Backend
-	bootBackends []*storage.BackendPersistent
-	version      *config.PersistentStateVersion
+	liveBackends       map[string]*storage.Backend
+	bootBackends       []*storage.BackendPersistent
+	bootStorageClasses []*sc.Persistent
+	version            *config.PersistentStateVersion
 }
 
 // NewPassthroughClient returns a client that satisfies the
@@ -37,17 +43,19 @@ type PassthroughClient struct {
 // the store is pre-populated with backend objects from one or more backend
 // config files prior to bootstrapping.  The volume info is then read
 // directly from the storage controllers during the bootstrapping process.
-// The passthrough store does not need to persist any objects, including
-// transactions and storage classes, once the orchestrator has started.
-// The passthrough store is primarily useful for the Docker Volume Plugin
-// use case, which doesn't easily support a separate persistence layer
-// and has no support for storage classes.
+// Storage classes may also be defined in the config files, in which case
+// they are returned during bootstrapping as well.  The passthrough store
+// does not need to persist any objects, including transactions and storage
+// classes, once the orchestrator has started.  The passthrough store is
+// primarily useful for the Docker Volume Plugin use case, which doesn't
+// easily support a separate persistence layer.
 func NewPassthroughClient(configPath string) (*PassthroughClient, error) {
 
 	ctx := GenerateRequestContext(nil, "", ContextSourceInternal)
 	client := &PassthroughClient{
-		liveBackends: make(map[string]*storage.Backend),
-		bootBackends: make([]*storage.BackendPersistent, 0),
+		liveBackends:       make(map[string]*storage.Backend),
+		bootBackends:       make([]*storage.BackendPersistent, 0),
+		bootStorageClasses: make([]*sc.Persistent, 0),
 		version: &config.PersistentStateVersion{
 			PersistentStoreVersion: "passthrough",
 			OrchestratorAPIVersion: config.OrchestratorAPIVersion,
@@ -111,7 +119,8 @@ func (c *PassthroughClient) initialize(ctx context.Context, configPath string) e
 	}
 }
 
-// loadBackend loads a single driver config file from the specified path
+// loadBackend loads a single driver config file from the specified path.  The file may also
+// contain storage class definitions, or it may contain only storage class definitions.
 func (c *PassthroughClient) loadBackend(ctx context.Context, configPath string) error {
 
 	Logc(ctx).WithField("configPath", configPath).Debug("Passthrough store loading config file.")
@@ -125,8 +134,22 @@ func (c *PassthroughClient) loadBackend(ctx context.Context, configPath string)
 		}).Fatal("Passthrough store could not read configuration file.")
 	}
 
+	// Convert config (JSON or YAML) to JSON
+	configJSON, err := yaml.YAMLToJSON(fileContents)
+	if err != nil {
+		return err
+	}
+
+	// Load any storage classes, leaving just the backend config
+	configJSON, hasBackend, err := c.loadStorageClasses(ctx, configJSON)
+	if err != nil {
+		return fmt.Errorf("could not load storage classes from %s; %v", configPath, err)
+	} else if !hasBackend {
+		return nil
+	}
+
 	// Convert config file to persistent backend JSON
-	backendJSON, err := c.unmarshalConfig(ctx, fileContents)
+	backendJSON, err := c.unmarshalConfig(ctx, configJSON)
 	if err != nil {
 		return err
 	}
@@ -137,10 +160,59 @@ func (c *PassthroughClient) loadBackend(ctx context.Context, configPath string)
 		return err
 	}
 
+	// Each backend needs a UUID so that the orchestrator can tell the backends apart
+	backend.BackendUUID = uuid.New().String()
+
 	c.bootBackends = append(c.bootBackends, &backend)
 	return nil
 }
 
+// loadStorageClasses removes the storageClasses section, if any, from a JSON config and adds the storage
+// classes defined there to those returned during bootstrapping.  It returns the remaining config, along
+// with whether that config defines a backend.  A config with only a storageClasses section does not.
+func (c *PassthroughClient) loadStorageClasses(ctx context.Context, configJSON []byte) ([]byte, bool, error) {
+
+	var configMap map[string]json.RawMessage
+	if err := json.Unmarshal(configJSON, &configMap); err != nil {
+		return nil, false, err
+	}
+
+	storageClassesJSON, ok := configMap[storageClassesConfigKey]
+	if !ok {
+		return configJSON, true, nil
+	}
+
+	var scConfigs []*sc.Config
+	if err := json.Unmarshal(storageClassesJSON, &scConfigs); err != nil {
+		return nil, false, err
+	}
+
+	for _, scConfig := range scConfigs {
+		if scConfig == nil || scConfig.Name == "" {
+			return nil, false, errors.New("storage class name must be specified")
+		}
+		for _, existing := range c.bootStorageClasses {
+			if existing.GetName() == scConfig.Name {
+				return nil, false, fmt.Errorf("storage class %s is defined more than once", scConfig.Name)
+			}
+		}
+
+		Logc(ctx).WithField("storageClass", scConfig.Name).Debug("Passthrough store loaded storage class.")
+		c.bootStorageClasses = append(c.bootStorageClasses, sc.New(scConfig).ConstructPersistent())
+	}
+
+	delete(configMap, storageClassesConfigKey)
+	if len(configMap) == 0 {
+		return nil, false, nil
+	}
+
+	configJSON, err := json.Marshal(configMap)
+	if err != nil {
+		return nil, false, err
+	}
+	return configJSON, true, nil
+}
+
 // unmarshalConfig accepts a driver JSON/YAML config and converts it to a persistent backend
 // JSON config as needed by the bootstrapping process.
 func (c *PassthroughClient) unmarshalConfig(ctx context.Context, fileContents []byte) (string, error) {
@@ -195,6 +267,49 @@ func (c *PassthroughClient) unmarshalConfig(ctx context.Context, fileContents []
 	return strings.Replace(string(persistentBackendJSON), oldConfig, newConfig, 1), nil
 }
 
+// startBackends initializes a live backend for each backend read from the config files, so that
+// volumes may be read from the storage without the orchestrator.  It returns the persistent form of
+// each live backend, which unlike the backends returned by GetBackends includes its name.
+func (c *PassthroughClient) startBackends(ctx context.Context) ([]*storage.BackendPersistent, error) {
+
+	backends := make([]*storage.BackendPersistent, 0)
+
+	for _, bootBackend := range c.bootBackends {
+
+		configJSON, err := bootBackend.MarshalConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		commonConfig, err := drivers.ValidateCommonSettings(ctx, configJSON)
+		if err != nil {
+			return nil, fmt.Errorf("input failed validation: %v", err)
+		}
+
+		backend, err := factory.NewStorageBackendForConfig(ctx, configJSON, bootBackend.BackendUUID, commonConfig, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not initialize backend; %v", err)
+		}
+		backend.BackendUUID = bootBackend.BackendUUID
+
+		if err = c.AddBackend(ctx, backend); err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend.ConstructPersistent(ctx))
+	}
+
+	return backends, nil
+}
+
+// stopBackends terminates any live backends started by startBackends.
+func (c *PassthroughClient) stopBackends(ctx context.Context) {
+
+	for _, backend := range c.liveBackends {
+		backend.Terminate(ctx)
+	}
+	c.liveBackends = make(map[string]*storage.Backend)
+}
+
 func (c *PassthroughClient) GetType() StoreType {
 	return PassthroughStore
 }
@@ -359,12 +474,21 @@ func (c *PassthroughClient) GetVolumes(ctx context.Context) ([]*storage.VolumeEx
 		close(volumeChannel)
 	}()
 
-	// Read the volumes as they come in from the goroutines
+	// Read the volumes as they come in from the goroutines.  Volumes are known by name, so if
+	// two backends report volumes with the same name, only the first one can be managed.
 	volumes := make([]*storage.VolumeExternal, 0)
+	volumeBackends := make(map[string]string)
 	for wrapper := range volumeChannel {
 		if wrapper.Error != nil {
 			Logc(ctx).Error(wrapper.Error)
+		} else if backendUUID, ok := volumeBackends[wrapper.Volume.Config.Name]; ok {
+			Logc(ctx).WithFields(log.Fields{
+				"volume":              wrapper.Volume.Config.Name,
+				"backendUUID":         wrapper.Volume.BackendUUID,
+				"existingBackendUUID": backendUUID,
+			}).Warning("Passthrough store found a volume with the same name on multiple backends, ignoring it.")
 		} else {
+			volumeBackends[wrapper.Volume.Config.Name] = wrapper.Volume.BackendUUID
 			volumes = append(volumes, wrapper.Volume)
 		}
 	}
@@ -424,12 +548,26 @@ func (c *PassthroughClient) AddStorageClass(context.Context, *sc.StorageClass) e
 	return nil
 }
 
+// GetStorageClass returns a storage class that was read from the config files.
 func (c *PassthroughClient) GetStorageClass(_ context.Context, scName string) (*sc.Persistent, error) {
+
+	for _, storageClass := range c.bootStorageClasses {
+		if storageClass.GetName() == scName {
+			return storageClass, nil
+		}
+	}
 	return nil, NewPersistentStoreError(KeyNotFoundErr, scName)
 }
 
+// GetStorageClasses is called by the orchestrator during bootstrapping, so the
+// passthrough store returns the storage classes it read from config files.
 func (c *PassthroughClient) GetStorageClasses(context.Context) ([]*sc.Persistent, error) {
-	return make([]*sc.Persistent, 0), nil
+
+	storageClassList := make([]*sc.Persistent, 0)
+
+	storageClassList = append(storageClassList, c.bootStorageClasses...)
+
+	return storageClassList, nil
 }
 
 func (c *PassthroughClient) DeleteStorageClass(context.Context, *sc.StorageClass) error {
@@ -5,6 +5,7 @@ package persistentstore
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -185,6 +186,93 @@ func TestPassthroughClient_NewPassthroughClientDirectory(t *testing.T) {
 	os.Remove(configPath)
 }
 
+func TestPassthroughClient_NewPassthroughClientStorageClasses(t *testing.T) {
+
+	configPath, err := ioutil.TempDir("", "passthrough")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(configPath)
+
+	backendJSON, _ := getFakeBackend().ConstructPersistent(ctx()).MarshalConfig()
+	backendJSON = strings.Replace(backendJSON, "{",
+		`{"storageClasses":[{"name":"gold","attributes":{"IOPS":"6000"}}],`, 1)
+	err = ioutil.WriteFile(configPath+"/backend", []byte(backendJSON), 0644)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	storageClassesYAML := `storageClasses:
+- name: silver
+  storagePools:
+    fake_backend: [".*"]
+`
+	err = ioutil.WriteFile(configPath+"/storage_classes.yaml", []byte(storageClassesYAML), 0644)
+	if err != nil {
+		t.Error(err.Error())
+	}
+
+	p, err := NewPassthroughClient(configPath)
+
+	if p == nil || err != nil {
+		t.Fatalf("Failed to create a working passthrough client! %v", err)
+	}
+	if len(p.bootBackends) != 1 {
+		t.Error("Passthrough client failed to initialize one backend!")
+	}
+	if bootConfig, _ := p.bootBackends[0].MarshalConfig(); strings.Contains(bootConfig, "storageClasses") {
+		t.Error("Passthrough client left storage classes in the backend config!")
+	}
+	storageClasses, err := p.GetStorageClasses(ctx())
+	if err != nil || len(storageClasses) != 2 {
+		t.Errorf("Passthrough client failed to initialize two storage classes! %v", err)
+	}
+	gold, err := p.GetStorageClass(ctx(), "gold")
+	if err != nil || len(gold.Config.Attributes) != 1 {
+		t.Errorf("Passthrough client failed to load storage class attributes! %v", err)
+	}
+	silver, err := p.GetStorageClass(ctx(), "silver")
+	if err != nil || len(silver.Config.Pools["fake_backend"]) != 1 {
+		t.Errorf("Passthrough client failed to load storage class pools! %v", err)
+	}
+
+	// A storage class may only be defined once
+	err = ioutil.WriteFile(configPath+"/more_storage_classes.yaml", []byte(storageClassesYAML), 0644)
+	if err != nil {
+		t.Error(err.Error())
+	}
+	if _, err = NewPassthroughClient(configPath); err == nil {
+		t.Error("Expected an error for a duplicate storage class!")
+	}
+}
+
+func TestPassthroughClient_NewPassthroughClientBackendUUIDs(t *testing.T) {
+
+	configPath, err := ioutil.TempDir("", "passthrough")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(configPath)
+
+	for _, name := range []string{"backend1", "backend2"} {
+		backendJSON, _ := getFakeBackendWithName(name).ConstructPersistent(ctx()).MarshalConfig()
+		if err = ioutil.WriteFile(configPath+"/"+name, []byte(backendJSON), 0644); err != nil {
+			t.Error(err.Error())
+		}
+	}
+
+	p, err := NewPassthroughClient(configPath)
+
+	if p == nil || err != nil {
+		t.Fatalf("Failed to create a working passthrough client! %v", err)
+	}
+	if len(p.bootBackends) != 2 {
+		t.Fatal("Passthrough client failed to initialize two backends!")
+	}
+	if p.bootBackends[0].BackendUUID == "" || p.bootBackends[0].BackendUUID == p.bootBackends[1].BackendUUID {
+		t.Error("Passthrough client failed to assign each backend a unique UUID!")
+	}
+}
+
 func TestPassthroughClient_GetType(t *testing.T) {
 	p := newPassthroughClient()
 
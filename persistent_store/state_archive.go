@@ -0,0 +1,385 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/netapp/trident/config"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	sc "github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// StateArchiveVersion is the version of the state archive format, which must be incremented
+	// whenever a change to it would prevent an older Trident from importing it correctly.
+	StateArchiveVersion = "1"
+
+	// SecretsRedacted means the backend credentials were removed from the archive.
+	SecretsRedacted = "redacted"
+	// SecretsEncrypted means the backend credentials were encrypted with a key derived from a passphrase.
+	SecretsEncrypted = "encrypted"
+
+	// redactedSecretName replaces the value of every credential removed from an archived backend.
+	redactedSecretName = "redacted"
+
+	// scrypt parameters for deriving the secrets encryption key, as recommended for interactive use
+	scryptN      = 32768
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 32
+)
+
+// StateArchive is a versioned copy of everything the orchestrator keeps in its persistent store,
+// from which a Trident instance that has lost its store may be rebuilt.
+type StateArchive struct {
+	ArchiveVersion         string                         `json:"archiveVersion"`
+	TridentVersion         string                         `json:"tridentVersion"`
+	Created                string                         `json:"created"`
+	PersistentStateVersion *config.PersistentStateVersion `json:"persistentStateVersion"`
+	Secrets                string                         `json:"secrets"`
+	Salt                   []byte                         `json:"salt,omitempty"`
+	Backends               []*ArchivedBackend             `json:"backends"`
+	StorageClasses         []*sc.Persistent               `json:"storageClasses"`
+	Volumes                []*storage.VolumeExternal      `json:"volumes"`
+	Snapshots              []*storage.SnapshotPersistent  `json:"snapshots"`
+	VolumeTransactions     []*storage.VolumeTransaction   `json:"volumeTransactions"`
+	Nodes                  []*utils.Node                  `json:"nodes"`
+}
+
+// ArchivedBackend is a backend with its credentials removed from its config.  If the archive's
+// secrets are encrypted, the credentials are kept alongside it in encrypted form.
+type ArchivedBackend struct {
+	Backend          *storage.BackendPersistent `json:"backend"`
+	EncryptedSecrets []byte                     `json:"encryptedSecrets,omitempty"`
+}
+
+// ExportState reads everything from a persistent store into a state archive.  If a passphrase is
+// supplied, the backend credentials are encrypted with it, otherwise they are left out.  Backends
+// that refer to their credentials via the credentials field never contain any, so their secrets
+// must simply exist wherever the archive is imported.
+func ExportState(ctx context.Context, client Client, passphrase string) (*StateArchive, error) {
+
+	version, err := client.GetVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not read persistent state version; %v", err)
+	}
+
+	data, err := readData(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &StateArchive{
+		ArchiveVersion:         StateArchiveVersion,
+		TridentVersion:         config.OrchestratorVersion.String(),
+		Created:                time.Now().UTC().Format(time.RFC3339),
+		PersistentStateVersion: version,
+		Secrets:                SecretsRedacted,
+		Backends:               make([]*ArchivedBackend, 0, len(data.backends)),
+		StorageClasses:         data.storageClasses,
+		Volumes:                data.volumes,
+		Snapshots:              data.snapshots,
+		VolumeTransactions:     data.volumeTxns,
+		Nodes:                  data.nodes,
+	}
+
+	var gcm cipher.AEAD
+	if passphrase != "" {
+		archive.Secrets = SecretsEncrypted
+		archive.Salt = make([]byte, saltLen)
+		if _, err = io.ReadFull(rand.Reader, archive.Salt); err != nil {
+			return nil, fmt.Errorf("could not generate salt; %v", err)
+		}
+		if gcm, err = newSecretsCipher(passphrase, archive.Salt); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, backend := range data.backends {
+		redactedBackend, secretMap, _, err := backend.ExtractBackendSecrets(redactedSecretName)
+		if err != nil {
+			return nil, fmt.Errorf("could not extract secrets of backend %s; %v", backend.Name, err)
+		}
+
+		archivedBackend := &ArchivedBackend{Backend: redactedBackend}
+		if gcm != nil && secretMap != nil {
+			if archivedBackend.EncryptedSecrets, err = encryptSecrets(gcm, secretMap); err != nil {
+				return nil, fmt.Errorf("could not encrypt secrets of backend %s; %v", backend.Name, err)
+			}
+		}
+		archive.Backends = append(archive.Backends, archivedBackend)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backends":       len(archive.Backends),
+		"storageClasses": len(archive.StorageClasses),
+		"volumes":        len(archive.Volumes),
+		"snapshots":      len(archive.Snapshots),
+		"transactions":   len(archive.VolumeTransactions),
+		"nodes":          len(archive.Nodes),
+		"secrets":        archive.Secrets,
+	}).Info("Exported orchestrator state.")
+
+	return archive, nil
+}
+
+// ImportState writes the contents of a state archive to a persistent store.  Because the archive
+// replaces the orchestrator's view of its storage, the store must not already contain any backends,
+// volumes, snapshots, or transactions.  Storage classes may already exist, as they will once a new
+// cluster's frontends have started, but only if they match the archived ones.  Nodes are never
+// imported, since the live nodes register themselves and archived ones may no longer exist.  If
+// anything cannot be written, the backends, volumes, and snapshots that were are removed again.
+func ImportState(ctx context.Context, client Client, archive *StateArchive, passphrase string) error {
+
+	if client.GetType() == PassthroughStore {
+		return fmt.Errorf("state cannot be imported into the %s store", PassthroughStore)
+	}
+
+	backends, err := validateStateArchive(archive, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if err = ensureStoreEmpty(ctx, client, archive); err != nil {
+		return err
+	}
+
+	if err = writeStateArchive(ctx, client, archive, backends); err != nil {
+		for _, deleteAll := range []func(context.Context) error{
+			client.DeleteSnapshots, client.DeleteVolumes, client.DeleteBackends,
+		} {
+			if deleteErr := deleteAll(ctx); deleteErr != nil && !MatchKeyNotFoundErr(deleteErr) {
+				Logc(ctx).Errorf("Could not remove partially imported state; %v", deleteErr)
+			}
+		}
+		return fmt.Errorf("could not import state; %v", err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backends":       len(backends),
+		"storageClasses": len(archive.StorageClasses),
+		"volumes":        len(archive.Volumes),
+		"snapshots":      len(archive.Snapshots),
+		"transactions":   len(archive.VolumeTransactions),
+		"created":        archive.Created,
+	}).Info("Imported orchestrator state.")
+
+	return nil
+}
+
+// validateStateArchive checks that an archive may be imported by this version of Trident, and
+// returns its backends with any encrypted credentials restored.
+func validateStateArchive(archive *StateArchive, passphrase string) ([]*storage.BackendPersistent, error) {
+
+	if archive.ArchiveVersion != StateArchiveVersion {
+		return nil, fmt.Errorf("unsupported state archive version %s; expected %s",
+			archive.ArchiveVersion, StateArchiveVersion)
+	}
+	if archive.PersistentStateVersion == nil {
+		return nil, fmt.Errorf("state archive has no persistent state version")
+	}
+	if archive.PersistentStateVersion.OrchestratorAPIVersion != config.OrchestratorAPIVersion {
+		return nil, fmt.Errorf("state archive has orchestrator API version %s; expected %s",
+			archive.PersistentStateVersion.OrchestratorAPIVersion, config.OrchestratorAPIVersion)
+	}
+
+	var gcm cipher.AEAD
+	var err error
+	switch archive.Secrets {
+	case SecretsRedacted:
+	case SecretsEncrypted:
+		if passphrase == "" {
+			return nil, fmt.Errorf("a passphrase is required to import a state archive with encrypted secrets")
+		}
+		if gcm, err = newSecretsCipher(passphrase, archive.Salt); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("state archive has invalid secrets mode '%s'", archive.Secrets)
+	}
+
+	backends := make([]*storage.BackendPersistent, 0, len(archive.Backends))
+	for _, archivedBackend := range archive.Backends {
+		backend := archivedBackend.Backend
+		if backend == nil || backend.Name == "" || backend.BackendUUID == "" {
+			return nil, fmt.Errorf("state archive contains a backend without a name or UUID")
+		}
+		if gcm != nil && archivedBackend.EncryptedSecrets != nil {
+			secretMap, err := decryptSecrets(gcm, archivedBackend.EncryptedSecrets)
+			if err != nil {
+				return nil, fmt.Errorf("could not decrypt secrets of backend %s, the passphrase may be "+
+					"incorrect; %v", backend.Name, err)
+			}
+			if err = backend.InjectBackendSecrets(secretMap); err != nil {
+				return nil, err
+			}
+		}
+		backends = append(backends, backend)
+	}
+
+	return backends, nil
+}
+
+// ensureStoreEmpty returns an error if a persistent store already knows about any storage, or has
+// a storage class that differs from the archived one of the same name.
+func ensureStoreEmpty(ctx context.Context, client Client, archive *StateArchive) error {
+
+	backends, err := client.GetBackends(ctx)
+	if err != nil && !MatchKeyNotFoundErr(err) {
+		return err
+	}
+	volumes, err := client.GetVolumes(ctx)
+	if err != nil && !MatchKeyNotFoundErr(err) {
+		return err
+	}
+	snapshots, err := client.GetSnapshots(ctx)
+	if err != nil && !MatchKeyNotFoundErr(err) {
+		return err
+	}
+	volTxns, err := client.GetVolumeTransactions(ctx)
+	if err != nil && !MatchKeyNotFoundErr(err) {
+		return err
+	}
+
+	if len(backends) > 0 || len(volumes) > 0 || len(snapshots) > 0 || len(volTxns) > 0 {
+		return fmt.Errorf("state may only be imported into an empty store; found %d backends, %d volumes, "+
+			"%d snapshots, and %d transactions", len(backends), len(volumes), len(snapshots), len(volTxns))
+	}
+
+	for _, storageClass := range archive.StorageClasses {
+		existing, err := client.GetStorageClass(ctx, storageClass.GetName())
+		if err != nil {
+			if MatchKeyNotFoundErr(err) {
+				continue
+			}
+			return err
+		}
+		if !reflect.DeepEqual(existing.Config, storageClass.Config) {
+			return fmt.Errorf("storage class %s already exists with a different configuration",
+				storageClass.GetName())
+		}
+	}
+
+	return nil
+}
+
+// writeStateArchive adds the contents of an archive to a persistent store.
+func writeStateArchive(
+	ctx context.Context, client Client, archive *StateArchive, backends []*storage.BackendPersistent,
+) error {
+
+	for _, backend := range backends {
+		if err := client.AddBackendPersistent(ctx, backend); err != nil {
+			return err
+		}
+	}
+	for _, storageClass := range archive.StorageClasses {
+		if _, err := client.GetStorageClass(ctx, storageClass.GetName()); err == nil {
+			Logc(ctx).WithField("storageClass", storageClass.GetName()).Info(
+				"Storage class already exists, not importing it.")
+			continue
+		} else if !MatchKeyNotFoundErr(err) {
+			return err
+		}
+		if err := client.AddStorageClass(ctx, sc.NewFromPersistent(storageClass)); err != nil {
+			return err
+		}
+	}
+	for _, volume := range archive.Volumes {
+		if err := client.AddVolumePersistent(ctx, volume); err != nil {
+			return err
+		}
+	}
+	for _, snapshot := range archive.Snapshots {
+		if err := client.AddSnapshot(ctx, &snapshot.Snapshot); err != nil {
+			return err
+		}
+	}
+	for _, volTxn := range archive.VolumeTransactions {
+		if err := client.AddVolumeTransaction(ctx, volTxn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newSecretsCipher returns an AES-GCM cipher keyed with the passphrase and salt.
+func newSecretsCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+
+	if len(salt) != saltLen {
+		return nil, fmt.Errorf("invalid salt length %d", len(salt))
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive encryption key; %v", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// encryptSecrets seals a backend's secrets, prefixed by the random nonce used to do so.  The keys
+// are lower-cased, as they are when read from a backend secret, so that they may be injected
+// into the backend on import.
+func encryptSecrets(gcm cipher.AEAD, secretMap map[string]string) ([]byte, error) {
+
+	lowerSecretMap := make(map[string]string, len(secretMap))
+	for key, value := range secretMap {
+		lowerSecretMap[strings.ToLower(key)] = value
+	}
+
+	plaintext, err := json.Marshal(lowerSecretMap)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// decryptSecrets reverses encryptSecrets.
+func decryptSecrets(gcm cipher.AEAD, ciphertext []byte) (map[string]string, error) {
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted secrets are too short")
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	secretMap := make(map[string]string)
+	if err = json.Unmarshal(plaintext, &secretMap); err != nil {
+		return nil, err
+	}
+
+	return secretMap, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package persistentstore
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	drivers "github.com/netapp/trident/storage_drivers"
+)
+
+func getFakeOntapBackendPersistent() *storage.BackendPersistent {
+	return &storage.BackendPersistent{
+		Version: config.OrchestratorAPIVersion,
+		Config: storage.PersistentStorageBackendConfig{
+			OntapConfig: &drivers.OntapStorageDriverConfig{
+				CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{
+					Version:           drivers.ConfigVersion,
+					StorageDriverName: drivers.OntapNASStorageDriverName,
+				},
+				ManagementLIF: "10.0.0.1",
+				Username:      "admin",
+				Password:      "Netapp123",
+			},
+		},
+		Name:        "ontap",
+		BackendUUID: "1f5a3e8d-0b44-4a5c-b3c9-6b6d1b0a1d2e",
+		Online:      true,
+		State:       storage.Online,
+	}
+}
+
+func newFakeStateSource(t *testing.T) *InMemoryClient {
+
+	source := NewInMemoryClient()
+	backend := getFakeOntapBackendPersistent()
+	assert.NoError(t, source.AddBackendPersistent(ctx(), backend))
+	volume := getFakeVolume(getFakeBackend())
+	volume.BackendUUID = backend.BackendUUID
+	assert.NoError(t, source.AddVolume(ctx(), volume))
+	assert.NoError(t, source.AddStorageClass(ctx(), getFakeStorageClass()))
+	assert.NoError(t, source.AddSnapshot(ctx(), getFakeSnapshot()))
+	assert.NoError(t, source.AddOrUpdateNode(ctx(), getFakeNode()))
+	return source
+}
+
+// roundTrip serializes an archive the way tridentctl writes it to a file and reads it back.
+func roundTrip(t *testing.T, archive *StateArchive) *StateArchive {
+
+	archiveBytes, err := json.Marshal(archive)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(archiveBytes), "Netapp123", "archive should not contain credentials")
+
+	result := &StateArchive{}
+	assert.NoError(t, json.Unmarshal(archiveBytes, result))
+	return result
+}
+
+func TestStateArchive_EncryptedSecrets(t *testing.T) {
+
+	archive, err := ExportState(ctx(), newFakeStateSource(t), "passphrase")
+	assert.NoError(t, err)
+	assert.Equal(t, StateArchiveVersion, archive.ArchiveVersion)
+	assert.Equal(t, SecretsEncrypted, archive.Secrets)
+	assert.Len(t, archive.Backends, 1)
+	assert.NotEmpty(t, archive.Backends[0].EncryptedSecrets)
+	assert.Len(t, archive.Volumes, 1)
+	assert.Len(t, archive.StorageClasses, 1)
+	assert.Len(t, archive.Snapshots, 1)
+	assert.Len(t, archive.Nodes, 1)
+
+	archive = roundTrip(t, archive)
+
+	// The wrong passphrase is detected before anything is written
+	dest := NewInMemoryClient()
+	assert.Error(t, ImportState(ctx(), dest, archive, "wrong"))
+	assert.Error(t, ImportState(ctx(), dest, archive, ""))
+	backends, err := dest.GetBackends(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, backends, 0)
+
+	assert.NoError(t, ImportState(ctx(), dest, archive, "passphrase"))
+
+	backend, err := dest.GetBackend(ctx(), "ontap")
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", backend.Config.OntapConfig.Username)
+	assert.Equal(t, "Netapp123", backend.Config.OntapConfig.Password)
+	assert.Equal(t, "10.0.0.1", backend.Config.OntapConfig.ManagementLIF)
+	volumes, err := dest.GetVolumes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, volumes, 1)
+	snapshots, err := dest.GetSnapshots(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, snapshots, 1)
+
+	// Nothing may be imported into a store that is in use
+	assert.Error(t, ImportState(ctx(), dest, archive, "passphrase"))
+}
+
+func TestStateArchive_RedactedSecrets(t *testing.T) {
+
+	archive, err := ExportState(ctx(), newFakeStateSource(t), "")
+	assert.NoError(t, err)
+	assert.Equal(t, SecretsRedacted, archive.Secrets)
+	assert.Empty(t, archive.Backends[0].EncryptedSecrets)
+
+	archive = roundTrip(t, archive)
+
+	// A storage class that differs from the archived one prevents the import
+	dest := NewInMemoryClient()
+	assert.NoError(t, dest.AddStorageClass(ctx(), getFakeStorageClassWithName("fake_sc", 50, true, "thin")))
+	assert.Error(t, ImportState(ctx(), dest, archive, ""))
+
+	// Matching storage classes and live nodes are kept, and archived nodes are not imported
+	dest = NewInMemoryClient()
+	assert.NoError(t, dest.AddStorageClass(ctx(), getFakeStorageClass()))
+	node := getFakeNode()
+	node.Name = "liveNode"
+	assert.NoError(t, dest.AddOrUpdateNode(ctx(), node))
+
+	assert.NoError(t, ImportState(ctx(), dest, archive, ""))
+
+	backend, err := dest.GetBackend(ctx(), "ontap")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret:redacted", backend.Config.OntapConfig.Password)
+	nodes, err := dest.GetNodes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	assert.Equal(t, "liveNode", nodes[0].Name)
+}
+
+func TestStateArchive_Validation(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		modify func(*StateArchive)
+	}{
+		{"archiveVersion", func(a *StateArchive) { a.ArchiveVersion = "0" }},
+		{"noStateVersion", func(a *StateArchive) { a.PersistentStateVersion = nil }},
+		{"apiVersion", func(a *StateArchive) { a.PersistentStateVersion.OrchestratorAPIVersion = "0" }},
+		{"secretsMode", func(a *StateArchive) { a.Secrets = "plaintext" }},
+		{"salt", func(a *StateArchive) { a.Salt = a.Salt[1:] }},
+		{"backendUUID", func(a *StateArchive) { a.Backends[0].Backend.BackendUUID = "" }},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		archive, err := ExportState(ctx(), newFakeStateSource(t), "passphrase")
+		assert.NoError(t, err)
+		test.modify(archive)
+
+		dest := NewInMemoryClient()
+		assert.Error(t, ImportState(ctx(), dest, archive, "passphrase"))
+		backends, err := dest.GetBackends(ctx())
+		assert.NoError(t, err)
+		assert.Len(t, backends, 0)
+	}
+}
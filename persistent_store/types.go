@@ -18,6 +18,7 @@ const (
 	MemoryStore      StoreType = "memory"
 	PassthroughStore StoreType = "passthrough"
 	CRDV1Store       StoreType = "crdv1"
+	BoltStore        StoreType = "bolt"
 )
 
 type ClientConfig struct {
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/RoaringBitmap/roaring"
@@ -45,12 +46,23 @@ type Driver interface {
 	Destroy(ctx context.Context, name string) error
 	Rename(ctx context.Context, name, newName string) error
 	Resize(ctx context.Context, volConfig *VolumeConfig, sizeBytes uint64) error
+	// Modify changes the attributes of an existing volume as specified by the modification.
+	// Drivers return an UnsupportedError for any attribute they cannot change.
+	Modify(ctx context.Context, volConfig *VolumeConfig, modification *VolumeModification) error
 	Get(ctx context.Context, name string) error
 	GetInternalVolumeName(ctx context.Context, name string) string
 	GetStorageBackendSpecs(ctx context.Context, backend *Backend) error
 	GetStorageBackendPhysicalPoolNames(ctx context.Context) []string
+	// GetCapacity returns the capacity of each physical storage resource that could host
+	// a volume with the specified attributes in the specified pool, keyed by a name that is
+	// unique to the backend.  Virtual pools report only the physical resources that match
+	// the attributes, or all of them if the attributes are empty.
+	GetCapacity(
+		ctx context.Context, storagePool *Pool, volAttributes map[string]sa.Request,
+	) (map[string]*PoolCapacity, error)
 	GetProtocol(ctx context.Context) tridentconfig.Protocol
 	Publish(ctx context.Context, volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo) error
+	Unpublish(ctx context.Context, volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo) error
 	CanSnapshot(ctx context.Context, snapConfig *SnapshotConfig) error
 	GetSnapshot(ctx context.Context, snapConfig *SnapshotConfig) (*Snapshot, error)
 	GetSnapshots(ctx context.Context, volConfig *VolumeConfig) ([]*Snapshot, error)
@@ -65,8 +77,8 @@ type Driver interface {
 	// object.  This method is only available if using the passthrough store (i.e. Docker).
 	GetVolumeExternal(ctx context.Context, name string) (*VolumeExternal, error)
 	// GetVolumeExternalWrappers reads all volumes owned by this driver from the storage backend and
-	// writes them to the supplied channel as VolumeExternalWrapper objects.  This method is only
-	// available if using the passthrough store (i.e. Docker).
+	// writes them to the supplied channel as VolumeExternalWrapper objects.  It is used by the
+	// passthrough store (i.e. Docker) and by the drift monitor.
 	GetVolumeExternalWrappers(context.Context, chan *VolumeExternalWrapper)
 	GetUpdateType(ctx context.Context, driver Driver) *roaring.Bitmap
 	ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node, backendUUID string) error
@@ -82,6 +94,9 @@ type Backend struct {
 	Storage     map[string]*Pool
 	Volumes     map[string]*Volume
 	ConfigRef   string
+
+	// volumesMutex protects Volumes, which may be updated by concurrent operations on this backend
+	volumesMutex sync.RWMutex
 }
 
 type UpdateBackendStateRequest struct {
@@ -104,11 +119,13 @@ const (
 	Offline  = BackendState("offline")
 	Deleting = BackendState("deleting")
 	Failed   = BackendState("failed")
+	// Maintenance backends serve their existing volumes but are not chosen for new ones
+	Maintenance = BackendState("maintenance")
 )
 
 func (s BackendState) String() string {
 	switch s {
-	case Unknown, Online, Offline, Deleting, Failed:
+	case Unknown, Online, Offline, Deleting, Failed, Maintenance:
 		return string(s)
 	default:
 		return "unknown"
@@ -117,7 +134,7 @@ func (s BackendState) String() string {
 
 func (s BackendState) IsUnknown() bool {
 	switch s {
-	case Online, Offline, Deleting, Failed:
+	case Online, Offline, Deleting, Failed, Maintenance:
 		return false
 	case Unknown:
 		return true
@@ -142,6 +159,10 @@ func (s BackendState) IsFailed() bool {
 	return s == Failed
 }
 
+func (s BackendState) IsMaintenance() bool {
+	return s == Maintenance
+}
+
 func NewStorageBackend(ctx context.Context, driver Driver) (*Backend, error) {
 	backend := Backend{
 		Driver:  driver,
@@ -284,7 +305,7 @@ func (b *Backend) AddVolume(
 	}
 
 	vol := NewVolume(volConfig, b.BackendUUID, storagePool.Name, false)
-	b.Volumes[vol.Config.Name] = vol
+	b.AddCachedVolume(vol)
 	return vol, nil
 }
 
@@ -420,10 +441,46 @@ func (b *Backend) CloneVolume(
 	}
 
 	vol := NewVolume(volConfig, b.BackendUUID, poolName, false)
-	b.Volumes[vol.Config.Name] = vol
+	b.AddCachedVolume(vol)
 	return vol, nil
 }
 
+// CloneSplitter is implemented by drivers that split clones from their source volumes, so that the
+// split of a clone whose creation was interrupted may be started again.
+type CloneSplitter interface {
+	// SplitClone starts splitting a clone from its source volume if the clone's configuration or
+	// storage pool calls for a split.  Splitting a volume that is no longer a clone succeeds without
+	// doing anything.
+	SplitClone(ctx context.Context, volConfig *VolumeConfig, storagePool *Pool) error
+}
+
+// SplitClone starts splitting a clone from its source volume, as CloneVolume would have when it
+// created the clone.  Drivers that do not implement CloneSplitter never split clones, so nothing is
+// done for them.
+func (b *Backend) SplitClone(ctx context.Context, volConfig *VolumeConfig, storagePool *Pool) error {
+
+	splitter, ok := b.Driver.(CloneSplitter)
+	if !ok {
+		return nil
+	}
+
+	// Ensure volume is managed
+	if volConfig.ImportNotManaged {
+		return &NotManagedError{volConfig.InternalName}
+	}
+
+	// Ensure backend is ready
+	if err := b.ensureOnline(ctx); err != nil {
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":      b.Name,
+		"clone_volume": volConfig.InternalName,
+	}).Debug("Attempting clone split.")
+	return splitter.SplitClone(ctx, volConfig, storagePool)
+}
+
 func (b *Backend) PublishVolume(
 	ctx context.Context, volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo,
 ) error {
@@ -436,17 +493,51 @@ func (b *Backend) PublishVolume(
 	}).Debug("Attempting volume publish.")
 
 	// Ensure backend is ready
-	if err := b.ensureOnlineOrDeleting(ctx); err != nil {
+	if err := b.ensureOnlineMaintenanceOrDeleting(ctx); err != nil {
 		return err
 	}
 
 	return b.Driver.Publish(ctx, volConfig, publishInfo)
 }
 
+func (b *Backend) UnpublishVolume(
+	ctx context.Context, volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":        b.Name,
+		"backendUUID":    b.BackendUUID,
+		"volume":         volConfig.Name,
+		"volumeInternal": volConfig.InternalName,
+		"node":           publishInfo.HostName,
+	}).Debug("Attempting volume unpublish.")
+
+	// Ensure backend is ready
+	if err := b.ensureOnlineMaintenanceOrDeleting(ctx); err != nil {
+		return err
+	}
+
+	return b.Driver.Unpublish(ctx, volConfig, publishInfo)
+}
+
+// GetPoolCapacity returns the capacity of the physical resources backing the specified pool that
+// could host a volume with the specified attributes.
+func (b *Backend) GetPoolCapacity(
+	ctx context.Context, pool *Pool, volAttributes map[string]sa.Request,
+) (map[string]*PoolCapacity, error) {
+
+	// Ensure backend is ready
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
+		return nil, err
+	}
+
+	return b.Driver.GetCapacity(ctx, pool, volAttributes)
+}
+
 func (b *Backend) GetVolumeExternal(ctx context.Context, volumeName string) (*VolumeExternal, error) {
 
 	// Ensure backend is ready
-	if err := b.ensureOnline(ctx); err != nil {
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
 		return nil, err
 	}
 
@@ -495,7 +586,7 @@ func (b *Backend) ImportVolume(ctx context.Context, volConfig *VolumeConfig) (*V
 	}
 
 	volume := NewVolume(volConfig, b.BackendUUID, drivers.UnsetPool, false)
-	b.Volumes[volume.Config.Name] = volume
+	b.AddCachedVolume(volume)
 	return volume, nil
 }
 
@@ -507,7 +598,7 @@ func (b *Backend) ResizeVolume(ctx context.Context, volConfig *VolumeConfig, new
 	}
 
 	// Ensure backend is ready
-	if err := b.ensureOnline(ctx); err != nil {
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
 		return err
 	}
 
@@ -529,6 +620,26 @@ func (b *Backend) ResizeVolume(ctx context.Context, volConfig *VolumeConfig, new
 	return b.Driver.Resize(ctx, volConfig, newSizeBytes)
 }
 
+func (b *Backend) ModifyVolume(ctx context.Context, volConfig *VolumeConfig, modification *VolumeModification) error {
+
+	// Ensure volume is managed
+	if volConfig.ImportNotManaged {
+		return &NotManagedError{volConfig.InternalName}
+	}
+
+	// Ensure backend is ready
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":      b.Name,
+		"volume":       volConfig.InternalName,
+		"modification": modification,
+	}).Debug("Attempting volume modification.")
+	return b.Driver.Modify(ctx, volConfig, modification)
+}
+
 func (b *Backend) RenameVolume(ctx context.Context, volConfig *VolumeConfig, newName string) error {
 
 	oldName := volConfig.InternalName
@@ -570,7 +681,7 @@ func (b *Backend) RemoveVolume(ctx context.Context, volConfig *VolumeConfig) err
 	}
 
 	// Ensure backend is ready
-	if err := b.ensureOnlineOrDeleting(ctx); err != nil {
+	if err := b.ensureOnlineMaintenanceOrDeleting(ctx); err != nil {
 		return err
 	}
 
@@ -583,10 +694,37 @@ func (b *Backend) RemoveVolume(ctx context.Context, volConfig *VolumeConfig) err
 	return nil
 }
 
+// AddCachedVolume adds a volume to the backend's in-memory volume cache.
+func (b *Backend) AddCachedVolume(volume *Volume) {
+	b.volumesMutex.Lock()
+	defer b.volumesMutex.Unlock()
+	b.Volumes[volume.Config.Name] = volume
+}
+
 func (b *Backend) RemoveCachedVolume(volumeName string) {
+	b.volumesMutex.Lock()
+	defer b.volumesMutex.Unlock()
 	delete(b.Volumes, volumeName)
 }
 
+// GetCachedVolumes returns a snapshot of the volumes in the backend's in-memory volume cache.
+func (b *Backend) GetCachedVolumes() []*Volume {
+	b.volumesMutex.RLock()
+	defer b.volumesMutex.RUnlock()
+	volumes := make([]*Volume, 0, len(b.Volumes))
+	for _, volume := range b.Volumes {
+		volumes = append(volumes, volume)
+	}
+	return volumes
+}
+
+// ClearCachedVolumes empties the backend's in-memory volume cache.
+func (b *Backend) ClearCachedVolumes() {
+	b.volumesMutex.Lock()
+	defer b.volumesMutex.Unlock()
+	b.Volumes = make(map[string]*Volume)
+}
+
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
 func (b *Backend) CanSnapshot(ctx context.Context, snapConfig *SnapshotConfig) error {
 	return b.Driver.CanSnapshot(ctx, snapConfig)
@@ -602,7 +740,7 @@ func (b *Backend) GetSnapshot(ctx context.Context, snapConfig *SnapshotConfig) (
 	}).Debug("GetSnapshot.")
 
 	// Ensure backend is ready
-	if err := b.ensureOnline(ctx); err != nil {
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
 		return nil, err
 	}
 
@@ -626,7 +764,7 @@ func (b *Backend) GetSnapshots(ctx context.Context, volConfig *VolumeConfig) ([]
 	}).Debug("GetSnapshots.")
 
 	// Ensure backend is ready
-	if err := b.ensureOnline(ctx); err != nil {
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
 		return nil, err
 	}
 
@@ -650,7 +788,7 @@ func (b *Backend) CreateSnapshot(
 	}
 
 	// Ensure backend is ready
-	if err := b.ensureOnline(ctx); err != nil {
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
 		return nil, err
 	}
 
@@ -695,7 +833,7 @@ func (b *Backend) RestoreSnapshot(ctx context.Context, snapConfig *SnapshotConfi
 	}
 
 	// Ensure backend is ready
-	if err := b.ensureOnline(ctx); err != nil {
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
 		return err
 	}
 
@@ -718,7 +856,7 @@ func (b *Backend) DeleteSnapshot(ctx context.Context, snapConfig *SnapshotConfig
 	}
 
 	// Ensure backend is ready
-	if err := b.ensureOnlineOrDeleting(ctx); err != nil {
+	if err := b.ensureOnlineMaintenanceOrDeleting(ctx); err != nil {
 		return err
 	}
 
@@ -765,6 +903,8 @@ func (b *Backend) GetUpdateType(ctx context.Context, origBackend *Backend) *roar
 // HasVolumes returns true if the Backend has one or more volumes
 // provisioned on it.
 func (b *Backend) HasVolumes() bool {
+	b.volumesMutex.RLock()
+	defer b.volumesMutex.RUnlock()
 	return len(b.Volumes) > 0
 }
 
@@ -792,7 +932,7 @@ func (b *Backend) Terminate(ctx context.Context) {
 // to its volumes from active nodes in the k8s cluster. This is usually
 // handled via export policies or initiators
 func (b *Backend) ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node) error {
-	if b.State == Online || b.State == Deleting {
+	if b.State == Online || b.State == Maintenance || b.State == Deleting {
 		return b.Driver.ReconcileNodeAccess(ctx, nodes, b.BackendUUID)
 	}
 	return nil
@@ -810,14 +950,28 @@ func (b *Backend) ensureOnline(ctx context.Context) error {
 	return nil
 }
 
-func (b *Backend) ensureOnlineOrDeleting(ctx context.Context) error {
+// ensureOnlineOrMaintenance is used by operations on existing volumes, which a backend in maintenance
+// continues to serve.
+func (b *Backend) ensureOnlineOrMaintenance(ctx context.Context) error {
+
+	if b.State != Online && b.State != Maintenance {
+		Logc(ctx).WithFields(log.Fields{
+			"state":         b.State,
+			"expectedState": string(Online) + "/" + string(Maintenance),
+		}).Error("Invalid backend state.")
+		return fmt.Errorf("backend %s is not Online or in Maintenance", b.Name)
+	}
+	return nil
+}
+
+func (b *Backend) ensureOnlineMaintenanceOrDeleting(ctx context.Context) error {
 
-	if b.State != Online && b.State != Deleting {
+	if b.State != Online && b.State != Maintenance && b.State != Deleting {
 		Logc(ctx).WithFields(log.Fields{
 			"state":         b.State,
-			"expectedState": string(Online) + "/" + string(Deleting),
+			"expectedState": string(Online) + "/" + string(Maintenance) + "/" + string(Deleting),
 		}).Error("Invalid backend state.")
-		return fmt.Errorf("backend %s is not Online or Deleting", b.Name)
+		return fmt.Errorf("backend %s is not Online, in Maintenance or Deleting", b.Name)
 	}
 	return nil
 }
@@ -850,8 +1004,8 @@ func (b *Backend) ConstructExternal(ctx context.Context) *BackendExternal {
 	for name, pool := range b.Storage {
 		backendExternal.Storage[name] = pool.ConstructExternal()
 	}
-	for volName := range b.Volumes {
-		backendExternal.Volumes = append(backendExternal.Volumes, volName)
+	for _, volume := range b.GetCachedVolumes() {
+		backendExternal.Volumes = append(backendExternal.Volumes, volume.Config.Name)
 	}
 	return &backendExternal
 }
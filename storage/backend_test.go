@@ -57,6 +57,13 @@ func TestBackendState(t *testing.T) {
 				return input.IsFailed()
 			},
 		},
+		"Maintenance state": {
+			input:  Maintenance,
+			output: "maintenance",
+			predicate: func(input BackendState) bool {
+				return input.IsMaintenance() && !input.IsUnknown()
+			},
+		},
 	}
 	for testName, test := range tests {
 		t.Logf("Running test case '%s'", testName)
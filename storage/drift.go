@@ -0,0 +1,54 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import "time"
+
+const (
+	// DriftTypeOrphanedVolume is a volume on a backend that Trident does not know about
+	DriftTypeOrphanedVolume = "orphanedVolume"
+	// DriftTypeMissingVolume is a Trident volume that is not found on its backend
+	DriftTypeMissingVolume = "missingVolume"
+	// DriftTypeMissingSnapshot is a Trident snapshot that is not found on its backend
+	DriftTypeMissingSnapshot = "missingSnapshot"
+	// DriftTypeSize is a volume whose size on its backend differs from the size Trident recorded
+	DriftTypeSize = "size"
+	// DriftTypeAttribute is a volume whose attribute on its backend differs from the value Trident recorded
+	DriftTypeAttribute = "attribute"
+)
+
+// DriftReport describes the differences found between Trident's state and the storage on its
+// backends by a drift check.
+type DriftReport struct {
+	CheckTime time.Time    `json:"checkTime"`
+	Items     []*DriftItem `json:"items"`
+	// Errors maps the names of backends that could not be checked to the reason
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// DriftItem describes one difference between Trident's state and the storage on a backend.
+type DriftItem struct {
+	Type        string `json:"type"`
+	Backend     string `json:"backend"`
+	BackendUUID string `json:"backendUUID"`
+	// Volume is the name of the Trident volume, which orphaned volumes do not have
+	Volume       string `json:"volume,omitempty"`
+	InternalName string `json:"internalName"`
+	Snapshot     string `json:"snapshot,omitempty"`
+	Attribute    string `json:"attribute,omitempty"`
+	Expected     string `json:"expected,omitempty"`
+	Actual       string `json:"actual,omitempty"`
+}
+
+// Key identifies the difference described by a DriftItem, so that the same difference may be
+// recognized in successive reports.
+func (i *DriftItem) Key() string {
+	return i.Type + "/" + i.BackendUUID + "/" + i.InternalName + "/" + i.Snapshot + "/" + i.Attribute
+}
+
+// OrphanCleanupRequest asks that orphaned volumes, identified by their internal names, be deleted
+// from a backend.  The deletion must be confirmed, since the volumes' data is lost.
+type OrphanCleanupRequest struct {
+	Volumes []string `json:"volumes"`
+	Confirm bool     `json:"confirm"`
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+// VolumeFullThresholdPercent is the used capacity, as a percentage of a volume's size, at or above which
+// a volume is reported as abnormal.
+const VolumeFullThresholdPercent = 95
+
+// VolumeCondition describes the health of a volume as seen by its storage backend.
+type VolumeCondition struct {
+	Abnormal bool   `json:"abnormal"`
+	Message  string `json:"message"`
+}
+
+// NewNormalVolumeCondition returns a condition for a healthy volume.
+func NewNormalVolumeCondition() *VolumeCondition {
+	return &VolumeCondition{Abnormal: false, Message: "volume is healthy"}
+}
+
+// NewAbnormalVolumeCondition returns a condition for an unhealthy volume with the supplied message.
+func NewAbnormalVolumeCondition(format string, args ...interface{}) *VolumeCondition {
+	return &VolumeCondition{Abnormal: true, Message: fmt.Sprintf(format, args...)}
+}
+
+// VolumeHealthChecker is implemented by drivers that can report more about a volume's health than
+// whether it exists, such as whether it is online and has free space.
+type VolumeHealthChecker interface {
+	// GetVolumeCondition returns the condition of the volume.  An error is returned only if the
+	// condition could not be determined.
+	GetVolumeCondition(ctx context.Context, volConfig *VolumeConfig) (*VolumeCondition, error)
+}
+
+// GetVolumeCondition returns the condition of a volume on this backend.  Drivers that do not implement
+// VolumeHealthChecker are only asked whether the volume exists.  Failures to reach the storage are
+// themselves reported as an abnormal condition, since the volume is then unlikely to be usable.
+func (b *Backend) GetVolumeCondition(ctx context.Context, volConfig *VolumeConfig) *VolumeCondition {
+
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
+		return NewAbnormalVolumeCondition("%v", err)
+	}
+
+	checker, ok := b.Driver.(VolumeHealthChecker)
+	if !ok {
+		if err := b.Driver.Get(ctx, volConfig.InternalName); err != nil {
+			return NewAbnormalVolumeCondition("volume %s not found on backend %s; %v",
+				volConfig.InternalName, b.Name, err)
+		}
+		return NewNormalVolumeCondition()
+	}
+
+	condition, err := checker.GetVolumeCondition(ctx, volConfig)
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"backend": b.Name,
+			"volume":  volConfig.InternalName,
+		}).WithError(err).Warning("Could not determine volume condition.")
+		return NewAbnormalVolumeCondition("could not determine condition of volume %s on backend %s; %v",
+			volConfig.InternalName, b.Name, err)
+	}
+
+	return condition
+}
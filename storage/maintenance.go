@@ -0,0 +1,36 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+// DefaultBackendDrainConcurrency is the number of volumes relocated at once by a backend drain that
+// does not specify its own limit.
+const DefaultBackendDrainConcurrency = 2
+
+// BackendVolumeReport lists the volumes that still reside on a backend, such as one in maintenance
+// that is being drained.
+type BackendVolumeReport struct {
+	Backend     string                  `json:"backend"`
+	BackendUUID string                  `json:"backendUUID"`
+	State       BackendState            `json:"state"`
+	Draining    bool                    `json:"draining"`
+	Volumes     []*BackendVolumeSummary `json:"volumes"`
+}
+
+// BackendVolumeSummary describes one volume in a BackendVolumeReport.
+type BackendVolumeSummary struct {
+	Name           string      `json:"name"`
+	InternalName   string      `json:"internalName"`
+	Pool           string      `json:"pool"`
+	Size           string      `json:"size"`
+	StorageClass   string      `json:"storageClass"`
+	State          VolumeState `json:"state"`
+	PublishedNodes []string    `json:"publishedNodes,omitempty"`
+	Snapshots      int         `json:"snapshots"`
+}
+
+// BackendDrainRequest asks that the volumes on a backend in maintenance be relocated to other
+// backends, with at most Concurrency relocations in progress at once.  A Concurrency of zero
+// selects DefaultBackendDrainConcurrency.
+type BackendDrainRequest struct {
+	Concurrency int `json:"concurrency,omitempty"`
+}
@@ -0,0 +1,185 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// Mirror states, as seen from the destination volume of a mirror relationship
+	MirrorStateUninitialized = "uninitialized"
+	MirrorStateMirrored      = "mirrored"
+	MirrorStateBroken        = "broken"
+)
+
+// MirrorStatus describes a mirror relationship from a remote source volume to a local destination volume.
+type MirrorStatus struct {
+	State string `json:"state"`
+	// Transferring is true while data is being replicated from the source volume
+	Transferring bool   `json:"transferring"`
+	Healthy      bool   `json:"healthy"`
+	Message      string `json:"message,omitempty"`
+}
+
+// Mirrorer is implemented by drivers that can replicate volumes between backends of the same type.
+// Each mirror operation is invoked on the backend that hosts the local volume, and the volume on the
+// peer backend is identified by the handle returned by that backend's GetMirrorVolumeHandle.
+type Mirrorer interface {
+	// GetMirrorVolumeHandle returns a string by which a peer backend may refer to the named volume.
+	GetMirrorVolumeHandle(ctx context.Context, name string) (string, error)
+	// EstablishMirror creates and initializes, if necessary, a mirror relationship that replicates
+	// the remote volume to the local volume.
+	EstablishMirror(ctx context.Context, name, remoteVolumeHandle, replicationPolicy, replicationSchedule string) error
+	// ReestablishMirror resynchronizes a broken or missing mirror relationship so that the local volume
+	// once again replicates the remote volume, discarding any changes made to the local volume since
+	// the latest common snapshot.
+	ReestablishMirror(
+		ctx context.Context, name, remoteVolumeHandle, replicationPolicy, replicationSchedule string,
+	) error
+	// PromoteMirror stops replication to the local volume and makes it writable.
+	PromoteMirror(ctx context.Context, name, remoteVolumeHandle string) error
+	// GetMirrorStatus returns the status of the mirror relationship to the local volume.
+	GetMirrorStatus(ctx context.Context, name, remoteVolumeHandle string) (*MirrorStatus, error)
+	// DeleteMirror removes the mirror relationship to the local volume.
+	DeleteMirror(ctx context.Context, name, remoteVolumeHandle string) error
+	// ReleaseMirror removes any information about a deleted mirror relationship from the local
+	// volume, which was its source.
+	ReleaseMirror(ctx context.Context, name, remoteVolumeHandle string) error
+}
+
+// getMirrorer returns the backend's driver as a Mirrorer, after ensuring that the volume is managed
+// and the backend is online.
+func (b *Backend) getMirrorer(ctx context.Context, volConfig *VolumeConfig) (Mirrorer, error) {
+
+	mirrorer, ok := b.Driver.(Mirrorer)
+	if !ok {
+		return nil, utils.UnsupportedError(fmt.Sprintf("mirroring is not supported by backend type %s",
+			b.GetDriverName()))
+	}
+
+	// Ensure volume is managed
+	if volConfig.ImportNotManaged {
+		return nil, &NotManagedError{volConfig.InternalName}
+	}
+
+	// Ensure backend is ready
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
+		return nil, err
+	}
+
+	return mirrorer, nil
+}
+
+// CanMirror returns true if the backend's driver supports volume mirroring.
+func (b *Backend) CanMirror() bool {
+	_, ok := b.Driver.(Mirrorer)
+	return ok
+}
+
+func (b *Backend) GetMirrorVolumeHandle(ctx context.Context, volConfig *VolumeConfig) (string, error) {
+
+	mirrorer, err := b.getMirrorer(ctx, volConfig)
+	if err != nil {
+		return "", err
+	}
+	return mirrorer.GetMirrorVolumeHandle(ctx, volConfig.InternalName)
+}
+
+func (b *Backend) EstablishMirror(
+	ctx context.Context, volConfig *VolumeConfig, remoteVolumeHandle, replicationPolicy, replicationSchedule string,
+) error {
+
+	mirrorer, err := b.getMirrorer(ctx, volConfig)
+	if err != nil {
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":      b.Name,
+		"volume":       volConfig.InternalName,
+		"remoteVolume": remoteVolumeHandle,
+	}).Debug("Attempting to establish mirror.")
+	return mirrorer.EstablishMirror(ctx, volConfig.InternalName, remoteVolumeHandle, replicationPolicy,
+		replicationSchedule)
+}
+
+func (b *Backend) ReestablishMirror(
+	ctx context.Context, volConfig *VolumeConfig, remoteVolumeHandle, replicationPolicy, replicationSchedule string,
+) error {
+
+	mirrorer, err := b.getMirrorer(ctx, volConfig)
+	if err != nil {
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":      b.Name,
+		"volume":       volConfig.InternalName,
+		"remoteVolume": remoteVolumeHandle,
+	}).Debug("Attempting to reestablish mirror.")
+	return mirrorer.ReestablishMirror(ctx, volConfig.InternalName, remoteVolumeHandle, replicationPolicy,
+		replicationSchedule)
+}
+
+func (b *Backend) PromoteMirror(ctx context.Context, volConfig *VolumeConfig, remoteVolumeHandle string) error {
+
+	mirrorer, err := b.getMirrorer(ctx, volConfig)
+	if err != nil {
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":      b.Name,
+		"volume":       volConfig.InternalName,
+		"remoteVolume": remoteVolumeHandle,
+	}).Debug("Attempting to promote mirror.")
+	return mirrorer.PromoteMirror(ctx, volConfig.InternalName, remoteVolumeHandle)
+}
+
+func (b *Backend) GetMirrorStatus(
+	ctx context.Context, volConfig *VolumeConfig, remoteVolumeHandle string,
+) (*MirrorStatus, error) {
+
+	mirrorer, err := b.getMirrorer(ctx, volConfig)
+	if err != nil {
+		return nil, err
+	}
+	return mirrorer.GetMirrorStatus(ctx, volConfig.InternalName, remoteVolumeHandle)
+}
+
+func (b *Backend) DeleteMirror(ctx context.Context, volConfig *VolumeConfig, remoteVolumeHandle string) error {
+
+	mirrorer, err := b.getMirrorer(ctx, volConfig)
+	if err != nil {
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":      b.Name,
+		"volume":       volConfig.InternalName,
+		"remoteVolume": remoteVolumeHandle,
+	}).Debug("Attempting to delete mirror.")
+	return mirrorer.DeleteMirror(ctx, volConfig.InternalName, remoteVolumeHandle)
+}
+
+func (b *Backend) ReleaseMirror(ctx context.Context, volConfig *VolumeConfig, remoteVolumeHandle string) error {
+
+	mirrorer, err := b.getMirrorer(ctx, volConfig)
+	if err != nil {
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":      b.Name,
+		"volume":       volConfig.InternalName,
+		"remoteVolume": remoteVolumeHandle,
+	}).Debug("Attempting to release mirror.")
+	return mirrorer.ReleaseMirror(ctx, volConfig.InternalName, remoteVolumeHandle)
+}
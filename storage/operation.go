@@ -0,0 +1,123 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import "time"
+
+// OperationState is the state of an operation recorded in the operation journal.
+type OperationState string
+
+const (
+	// OperationRunning is an operation whose current phase is being attempted
+	OperationRunning = OperationState("running")
+	// OperationRetrying is an operation whose current phase failed and is waiting to be retried
+	OperationRetrying = OperationState("retrying")
+	// OperationFailed is an operation that was given up until it is resumed again
+	OperationFailed = OperationState("failed")
+)
+
+// The phases of the operations recorded in the operation journal.  Each operation starts in one
+// phase and moves through the others until it is done, when its transaction is deleted.
+const (
+	OperationPhaseResizing   = "resizing"
+	OperationPhaseCreating   = "creating"
+	OperationPhaseSplitting  = "splitting"
+	OperationPhasePersisting = "persisting"
+	OperationPhaseDeleting   = "deleting"
+	OperationPhaseDone       = "done"
+)
+
+// The phases of a PV upgrade, which the Kubernetes helper records in the operation journal as it
+// replaces a legacy PV with a CSI PV.
+const (
+	OperationPhaseDeletingPV   = "deletingPV"
+	OperationPhaseDeletingPods = "deletingPods"
+	OperationPhaseCreatingPV   = "creatingPV"
+)
+
+// OperationRecord records the progress of an operation in its transaction, which serves as the
+// operation's entry in the operation journal, so that an operation interrupted by a restart may be
+// resumed from the phase it reached rather than rolled back.
+type OperationRecord struct {
+	StartTime time.Time      `json:"startTime"`
+	Phase     string         `json:"phase"`
+	State     OperationState `json:"state"`
+	// Attempts counts the attempts at the current phase
+	Attempts    int        `json:"attempts"`
+	NextAttempt *time.Time `json:"nextAttempt,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+}
+
+// NewOperationRecord returns the record of an operation that starts now in the specified phase.
+func NewOperationRecord(phase string) *OperationRecord {
+	return &OperationRecord{
+		StartTime: time.Now(),
+		Phase:     phase,
+		State:     OperationRunning,
+	}
+}
+
+// OperationRetryPolicy controls how a failed phase of a journaled operation is retried.  The
+// interval between attempts starts at InitialInterval and doubles up to MaxInterval.  MaxAttempts
+// limits the attempts at each phase; zero allows the phase to be retried until it succeeds.
+type OperationRetryPolicy struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	MaxAttempts     int
+}
+
+// OperationExternal describes an operation in progress, as recorded by its transaction.
+type OperationExternal struct {
+	Name        string          `json:"name"`
+	Op          VolumeOperation `json:"op"`
+	Volume      string          `json:"volume,omitempty"`
+	Snapshot    string          `json:"snapshot,omitempty"`
+	StartTime   *time.Time      `json:"startTime,omitempty"`
+	Phase       string          `json:"phase,omitempty"`
+	State       OperationState  `json:"state"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt *time.Time      `json:"nextAttempt,omitempty"`
+	LastError   string          `json:"lastError,omitempty"`
+}
+
+// ConstructOperationExternal describes the operation recorded by a transaction.
+func (t *VolumeTransaction) ConstructOperationExternal() *OperationExternal {
+
+	external := &OperationExternal{
+		Name:  t.Name(),
+		Op:    t.Op,
+		State: OperationRunning,
+	}
+
+	switch t.Op {
+	case AddSnapshot, DeleteSnapshot:
+		external.Volume = t.SnapshotConfig.VolumeName
+		external.Snapshot = t.SnapshotConfig.Name
+	case VolumeCreating:
+		external.Volume = t.VolumeCreatingConfig.Name
+		startTime := t.VolumeCreatingConfig.StartTime
+		external.StartTime = &startTime
+	default:
+		if t.Config != nil {
+			external.Volume = t.Config.Name
+		}
+	}
+
+	if t.VolumeRelocationConfig != nil {
+		startTime := t.VolumeRelocationConfig.StartTime
+		external.StartTime = &startTime
+		external.Phase = t.VolumeRelocationConfig.Phase
+	}
+
+	if t.Operation != nil {
+		startTime := t.Operation.StartTime
+		external.StartTime = &startTime
+		external.Phase = t.Operation.Phase
+		external.State = t.Operation.State
+		external.Attempts = t.Operation.Attempts
+		external.NextAttempt = t.Operation.NextAttempt
+		external.LastError = t.Operation.LastError
+	}
+
+	return external
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// RelocationMethodMove moves a volume between the pools of one backend, using the VolumeMover
+	// implemented by the backend's driver
+	RelocationMethodMove = "move"
+	// RelocationMethodMirror copies a volume to a new volume on another backend of the same type,
+	// using the Mirrorer implemented by the backends' driver
+	RelocationMethodMirror = "mirror"
+	// RelocationMethodCopy copies a volume to a new volume on another backend, of any type, by attaching
+	// both volumes to the host on which Trident runs and copying the data there
+	RelocationMethodCopy = "copy"
+
+	// Relocation phases, in the order in which they occur.  The provisioning phase is not used by the
+	// move method.
+	RelocationPhaseProvisioning = "provisioning"
+	RelocationPhaseCopying      = "copying"
+	RelocationPhaseCutover      = "cutover"
+	RelocationPhaseSwapped      = "swapped"
+
+	// Volume move states
+	VolumeMoveStateInProgress = "in_progress"
+	VolumeMoveStateComplete   = "complete"
+	VolumeMoveStateFailed     = "failed"
+)
+
+// VolumeRelocationConfig records the progress of a volume relocation in its transaction, so that a
+// relocation that is interrupted may be resumed.
+type VolumeRelocationConfig struct {
+	StartTime         time.Time `json:"startTime"`
+	Method            string    `json:"method"`
+	Phase             string    `json:"phase"`
+	SourceBackendUUID string    `json:"sourceBackendUUID"`
+	SourcePool        string    `json:"sourcePool"`
+	TargetBackendUUID string    `json:"targetBackendUUID"`
+	TargetPool        string    `json:"targetPool"`
+	// TargetConfig is the config of the volume created on the target backend by the mirror and copy methods
+	TargetConfig *VolumeConfig `json:"targetConfig,omitempty"`
+}
+
+// VolumeRelocationRequest asks that a volume be relocated to a pool that satisfies its storage class.
+// Either field may be left empty, in which case any backend or pool may be chosen.
+type VolumeRelocationRequest struct {
+	Backend string `json:"backend,omitempty"`
+	Pool    string `json:"pool,omitempty"`
+}
+
+// VolumeMoveStatus describes the progress of a volume move started by a VolumeMover.
+type VolumeMoveStatus struct {
+	State           string `json:"state"`
+	PercentComplete int    `json:"percentComplete"`
+	Message         string `json:"message,omitempty"`
+}
+
+// VolumeMover is implemented by drivers that can move a volume between the physical pools of one
+// backend while the volume remains accessible.
+type VolumeMover interface {
+	// MoveVolume starts moving the named volume to the named pool.  Starting a move to the pool on
+	// which the volume already resides succeeds without doing anything.
+	MoveVolume(ctx context.Context, name, pool string) error
+	// GetVolumeMoveStatus returns the status of a move of the named volume to the named pool.
+	GetVolumeMoveStatus(ctx context.Context, name, pool string) (*VolumeMoveStatus, error)
+}
+
+// getVolumeMover returns the backend's driver as a VolumeMover, after ensuring that the volume is
+// managed and the backend is online or in maintenance.
+func (b *Backend) getVolumeMover(ctx context.Context, volConfig *VolumeConfig) (VolumeMover, error) {
+
+	mover, ok := b.Driver.(VolumeMover)
+	if !ok {
+		return nil, utils.UnsupportedError(fmt.Sprintf("moving volumes is not supported by backend type %s",
+			b.GetDriverName()))
+	}
+
+	// Ensure volume is managed
+	if volConfig.ImportNotManaged {
+		return nil, &NotManagedError{volConfig.InternalName}
+	}
+
+	// Ensure backend is ready
+	if err := b.ensureOnlineOrMaintenance(ctx); err != nil {
+		return nil, err
+	}
+
+	return mover, nil
+}
+
+// CanMoveVolume returns true if the backend's driver supports moving volumes between its pools.
+func (b *Backend) CanMoveVolume() bool {
+	_, ok := b.Driver.(VolumeMover)
+	return ok
+}
+
+func (b *Backend) MoveVolume(ctx context.Context, volConfig *VolumeConfig, pool string) error {
+
+	mover, err := b.getVolumeMover(ctx, volConfig)
+	if err != nil {
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend": b.Name,
+		"volume":  volConfig.InternalName,
+		"pool":    pool,
+	}).Debug("Attempting to move volume.")
+	return mover.MoveVolume(ctx, volConfig.InternalName, pool)
+}
+
+func (b *Backend) GetVolumeMoveStatus(
+	ctx context.Context, volConfig *VolumeConfig, pool string,
+) (*VolumeMoveStatus, error) {
+
+	mover, err := b.getVolumeMover(ctx, volConfig)
+	if err != nil {
+		return nil, err
+	}
+	return mover.GetVolumeMoveStatus(ctx, volConfig.InternalName, pool)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"sort"
 
 	drivers "github.com/netapp/trident/storage_drivers"
@@ -20,6 +21,9 @@ import (
 // TODO: Try moving all ProvisioningLabelTag related code here
 const ProvisioningLabelTag = "provisioning"
 
+// PlacementPolicySpread is the storage class placement policy that prefers the least used storage pools
+const PlacementPolicySpread = "spread"
+
 type Pool struct {
 	Name string
 	// A Trident storage pool can potentially satisfy more than one storage class.
@@ -30,6 +34,52 @@ type Pool struct {
 	SupportedTopologies []map[string]string
 }
 
+// PoolCapacity describes the space available on a single physical storage resource
+// (aggregate, volume group, cluster, capacity pool, etc.) that backs a storage pool.
+type PoolCapacity struct {
+	TotalBytes     uint64 `json:"totalBytes"`
+	AvailableBytes uint64 `json:"availableBytes"`
+}
+
+// OrderPhysicalPoolsForCreate orders the physical pools that could hold a new volume, most desirable
+// first.  The pools are shuffled so that equally suitable pools are chosen randomly.  If the volume's
+// placement policy spreads volumes across pools, the pools with the most available space are tried
+// first, using the capacities returned by the supplied function.  Any other placement policy has
+// already ordered the storage pools offered to the backend, so their physical pools are left in
+// random order.
+func OrderPhysicalPoolsForCreate(
+	ctx context.Context, volConfig *VolumeConfig, pools []*Pool,
+	getCapacities func() (map[string]*PoolCapacity, error),
+) []*Pool {
+
+	rand.Shuffle(len(pools), func(i, j int) {
+		pools[i], pools[j] = pools[j], pools[i]
+	})
+
+	if len(pools) < 2 || volConfig.PlacementPolicy != PlacementPolicySpread {
+		return pools
+	}
+
+	capacities, err := getCapacities()
+	if err != nil {
+		Logc(ctx).WithError(err).Warning("Could not read pool capacities, using random pool order.")
+		return pools
+	}
+
+	available := func(pool *Pool) uint64 {
+		if capacity, ok := capacities[pool.Name]; ok {
+			return capacity.AvailableBytes
+		}
+		return 0
+	}
+
+	sort.SliceStable(pools, func(i, j int) bool {
+		return available(pools[i]) > available(pools[j])
+	})
+
+	return pools
+}
+
 func NewStoragePool(backend *Backend, name string) *Pool {
 	return &Pool{
 		Name:               name,
@@ -4,6 +4,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -220,3 +221,54 @@ func TestDeleteProvisioningLabelsNotFoundSuccess(t *testing.T) {
 
 	assert.Equal(t, []string{"foo", "bar"}, newLabels, "Label is not left as is")
 }
+
+func TestOrderPhysicalPoolsForCreate(t *testing.T) {
+
+	capacities := map[string]*PoolCapacity{
+		"small":  {TotalBytes: 100, AvailableBytes: 10},
+		"medium": {TotalBytes: 100, AvailableBytes: 50},
+		"large":  {TotalBytes: 100, AvailableBytes: 90},
+	}
+
+	tests := []struct {
+		name            string
+		placementPolicy string
+		expectSorted    bool
+	}{
+		{"default", "", false},
+		{"random", "random", false},
+		{"pack", "pack", false},
+		{"weighted labels", "weightedLabels", false},
+		{"spread", PlacementPolicySpread, true},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		pools := []*Pool{{Name: "small"}, {Name: "medium"}, {Name: "large"}}
+		volConfig := &VolumeConfig{PlacementPolicy: test.placementPolicy}
+		capacitiesRead := false
+
+		ordered := OrderPhysicalPoolsForCreate(context.Background(), volConfig, pools,
+			func() (map[string]*PoolCapacity, error) {
+				capacitiesRead = true
+				return capacities, nil
+			})
+
+		assert.Len(t, ordered, 3, test.name)
+		assert.Equal(t, test.expectSorted, capacitiesRead, test.name)
+		if test.expectSorted {
+			assert.Equal(t, []string{"large", "medium", "small"},
+				[]string{ordered[0].Name, ordered[1].Name, ordered[2].Name}, test.name)
+		}
+	}
+
+	// Pools are left in random order if their capacities cannot be read
+	pools := []*Pool{{Name: "small"}, {Name: "large"}}
+	ordered := OrderPhysicalPoolsForCreate(context.Background(),
+		&VolumeConfig{PlacementPolicy: PlacementPolicySpread}, pools,
+		func() (map[string]*PoolCapacity, error) {
+			return nil, errors.New("capacity unavailable")
+		})
+	assert.Len(t, ordered, 2)
+}
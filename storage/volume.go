@@ -5,6 +5,8 @@ package storage
 import (
 	"encoding/base64"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -40,6 +42,7 @@ type VolumeConfig struct {
 	SplitOnClone              string                 `json:"splitOnClone"`
 	QosPolicy                 string                 `json:"qosPolicy,omitempty"`
 	AdaptiveQosPolicy         string                 `json:"adaptiveQosPolicy,omitempty"`
+	TieringPolicy             string                 `json:"tieringPolicy,omitempty"`
 	Qos                       string                 `json:"qos,omitempty"`
 	QosType                   string                 `json:"type,omitempty"`
 	ServiceLevel              string                 `json:"serviceLevel,omitempty"`
@@ -49,10 +52,15 @@ type VolumeConfig struct {
 	ImportOriginalName        string                 `json:"importOriginalName,omitempty"`
 	ImportBackendUUID         string                 `json:"importBackendUUID,omitempty"`
 	ImportNotManaged          bool                   `json:"importNotManaged,omitempty"`
+	IsMirrorDestination       bool                   `json:"isMirrorDestination,omitempty"`
 	MountOptions              string                 `json:"mountOptions,omitempty"`
+	LUKSEncryption            string                 `json:"luksEncryption,omitempty"`
 	RequisiteTopologies       []map[string]string    `json:"requisiteTopologies,omitempty"`
 	PreferredTopologies       []map[string]string    `json:"preferredTopologies,omitempty"`
 	AllowedTopologies         []map[string]string    `json:"allowedTopologies,omitempty"`
+	// PlacementPolicy is the storage class placement policy in effect when the volume is created.  It
+	// only orders the pools tried for that creation, so it is neither persisted nor copied into clones.
+	PlacementPolicy string `json:"-"`
 }
 
 type VolumeCreatingConfig struct {
@@ -86,6 +94,7 @@ func (c *VolumeConfig) ConstructClone() *VolumeConfig {
 	if !ok {
 		return &VolumeConfig{}
 	}
+	volConfig.PlacementPolicy = ""
 
 	return &volConfig
 }
@@ -93,9 +102,11 @@ func (c *VolumeConfig) ConstructClone() *VolumeConfig {
 type Volume struct {
 	Config      *VolumeConfig
 	BackendUUID string // UUID of the storage backend
-	Pool        string // Name of the pool on which this volume was first provisioned
+	Pool        string // Name of the pool on which this volume resides
 	Orphaned    bool   // An Orphaned volume isn't currently tracked by the storage backend
 	State       VolumeState
+	// PublishedNodes lists the nodes to which the volume is currently published
+	PublishedNodes []string
 }
 
 type VolumeState string
@@ -106,12 +117,13 @@ const (
 	VolumeStateDeleting       = VolumeState("deleting")
 	VolumeStateUpgrading      = VolumeState("upgrading")
 	VolumeStateMissingBackend = VolumeState("missing_backend")
+	VolumeStateRelocating     = VolumeState("relocating")
 	// TODO should Orphaned be moved to a VolumeState?
 )
 
 func (s VolumeState) String() string {
 	switch s {
-	case VolumeStateUnknown, VolumeStateOnline, VolumeStateDeleting:
+	case VolumeStateUnknown, VolumeStateOnline, VolumeStateDeleting, VolumeStateRelocating:
 		return string(s)
 	default:
 		return "unknown"
@@ -120,7 +132,7 @@ func (s VolumeState) String() string {
 
 func (s VolumeState) IsUnknown() bool {
 	switch s {
-	case VolumeStateOnline, VolumeStateDeleting:
+	case VolumeStateOnline, VolumeStateDeleting, VolumeStateRelocating:
 		return false
 	case VolumeStateUnknown:
 		return true
@@ -141,6 +153,10 @@ func (s VolumeState) IsMissingBackend() bool {
 	return s == VolumeStateMissingBackend
 }
 
+func (s VolumeState) IsRelocating() bool {
+	return s == VolumeStateRelocating
+}
+
 func NewVolume(conf *VolumeConfig, backendUUID string, pool string, orphaned bool) *Volume {
 	return &Volume{
 		Config:      conf,
@@ -158,6 +174,8 @@ type VolumeExternal struct {
 	Pool        string      `json:"pool"`
 	Orphaned    bool        `json:"orphaned"`
 	State       VolumeState `json:"state"`
+	// PublishedNodes lists the nodes to which the volume is currently published
+	PublishedNodes []string `json:"publishedNodes,omitempty"`
 }
 
 func (v *VolumeExternal) GetCHAPSecretName() string {
@@ -175,6 +193,8 @@ func (v *Volume) ConstructExternal() *VolumeExternal {
 		Pool:        v.Pool,
 		Orphaned:    v.Orphaned,
 		State:       v.State,
+		// The list is always replaced rather than modified in place, so it may be shared
+		PublishedNodes: v.PublishedNodes,
 	}
 }
 
@@ -216,6 +236,83 @@ func (r *UpgradeVolumeRequest) Validate() error {
 	return nil
 }
 
+// VolumeModification describes changes to the attributes of an existing volume.  Any field left
+// empty is not changed.
+type VolumeModification struct {
+	SnapshotPolicy    string `json:"snapshotPolicy,omitempty"`
+	SnapshotReserve   string `json:"snapshotReserve,omitempty"`
+	ExportPolicy      string `json:"exportPolicy,omitempty"`
+	QosPolicy         string `json:"qosPolicy,omitempty"`
+	AdaptiveQosPolicy string `json:"adaptiveQosPolicy,omitempty"`
+	TieringPolicy     string `json:"tieringPolicy,omitempty"`
+	UnixPermissions   string `json:"unixPermissions,omitempty"`
+}
+
+func (m *VolumeModification) Validate() error {
+	if *m == (VolumeModification{}) {
+		return fmt.Errorf("at least one of the following fields must be specified: snapshotPolicy, " +
+			"snapshotReserve, exportPolicy, qosPolicy, adaptiveQosPolicy, tieringPolicy, unixPermissions")
+	}
+	if m.QosPolicy != "" && m.AdaptiveQosPolicy != "" {
+		return fmt.Errorf("only one of qosPolicy or adaptiveQosPolicy may be specified")
+	}
+	if m.SnapshotReserve != "" {
+		snapshotReserve, err := strconv.Atoi(m.SnapshotReserve)
+		if err != nil || snapshotReserve < 0 || snapshotReserve > 100 {
+			return fmt.Errorf("invalid snapshotReserve %s; must be an integer between 0 and 100", m.SnapshotReserve)
+		}
+	}
+	return nil
+}
+
+// Attributes returns the names of the attributes changed by the modification.
+func (m *VolumeModification) Attributes() []string {
+	attributes := make([]string, 0)
+	for name, value := range map[string]string{
+		"snapshotPolicy":    m.SnapshotPolicy,
+		"snapshotReserve":   m.SnapshotReserve,
+		"exportPolicy":      m.ExportPolicy,
+		"qosPolicy":         m.QosPolicy,
+		"adaptiveQosPolicy": m.AdaptiveQosPolicy,
+		"tieringPolicy":     m.TieringPolicy,
+		"unixPermissions":   m.UnixPermissions,
+	} {
+		if value != "" {
+			attributes = append(attributes, name)
+		}
+	}
+	sort.Strings(attributes)
+	return attributes
+}
+
+// Apply updates a volume config with the attributes specified in the modification.
+func (m *VolumeModification) Apply(c *VolumeConfig) {
+	if m.SnapshotPolicy != "" {
+		c.SnapshotPolicy = m.SnapshotPolicy
+	}
+	if m.SnapshotReserve != "" {
+		c.SnapshotReserve = m.SnapshotReserve
+	}
+	if m.ExportPolicy != "" {
+		c.ExportPolicy = m.ExportPolicy
+	}
+	// A volume may have only one kind of QoS policy
+	if m.QosPolicy != "" {
+		c.QosPolicy = m.QosPolicy
+		c.AdaptiveQosPolicy = ""
+	}
+	if m.AdaptiveQosPolicy != "" {
+		c.AdaptiveQosPolicy = m.AdaptiveQosPolicy
+		c.QosPolicy = ""
+	}
+	if m.TieringPolicy != "" {
+		c.TieringPolicy = m.TieringPolicy
+	}
+	if m.UnixPermissions != "" {
+		c.UnixPermissions = m.UnixPermissions
+	}
+}
+
 type ByVolumeExternalName []*VolumeExternal
 
 func (a ByVolumeExternalName) Len() int           { return len(a) }
@@ -3,6 +3,7 @@
 package storage
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,3 +52,76 @@ func TestVolumeState(t *testing.T) {
 		assert.True(t, test.predicate(test.input), "Predicate failed")
 	}
 }
+
+func TestVolumeModificationValidate(t *testing.T) {
+
+	tests := map[string]struct {
+		modification VolumeModification
+		valid        bool
+	}{
+		"Empty":                 {VolumeModification{}, false},
+		"Snapshot policy":       {VolumeModification{SnapshotPolicy: "default"}, true},
+		"Snapshot reserve":      {VolumeModification{SnapshotReserve: "10"}, true},
+		"Invalid reserve":       {VolumeModification{SnapshotReserve: "ten"}, false},
+		"Out of range reserve":  {VolumeModification{SnapshotReserve: "101"}, false},
+		"QoS policy":            {VolumeModification{QosPolicy: "gold"}, true},
+		"Both QoS policy kinds": {VolumeModification{QosPolicy: "gold", AdaptiveQosPolicy: "silver"}, false},
+		"Multiple attributes":   {VolumeModification{ExportPolicy: "default", UnixPermissions: "0755"}, true},
+		"Tiering policy":        {VolumeModification{TieringPolicy: "auto"}, true},
+		"Adaptive QoS policy":   {VolumeModification{AdaptiveQosPolicy: "extreme"}, true},
+		"Negative snap reserve": {VolumeModification{SnapshotReserve: "-1"}, false},
+		"Zero snapshot reserve": {VolumeModification{SnapshotReserve: "0"}, true},
+	}
+	for testName, test := range tests {
+		t.Logf("Running test case '%s'", testName)
+
+		err := test.modification.Validate()
+		if test.valid {
+			assert.NoError(t, err, "Expected valid modification")
+		} else {
+			assert.Error(t, err, "Expected invalid modification")
+		}
+	}
+}
+
+func TestVolumeModificationApply(t *testing.T) {
+
+	volConfig := &VolumeConfig{
+		Name:            "vol1",
+		SnapshotPolicy:  "none",
+		SnapshotReserve: "0",
+		ExportPolicy:    "default",
+		QosPolicy:       "gold",
+		UnixPermissions: "0777",
+	}
+
+	modification := &VolumeModification{
+		SnapshotPolicy:    "default",
+		AdaptiveQosPolicy: "extreme",
+		TieringPolicy:     "auto",
+	}
+	assert.Equal(t, []string{"adaptiveQosPolicy", "snapshotPolicy", "tieringPolicy"}, modification.Attributes())
+
+	modification.Apply(volConfig)
+
+	assert.Equal(t, "default", volConfig.SnapshotPolicy)
+	assert.Equal(t, "0", volConfig.SnapshotReserve)
+	assert.Equal(t, "default", volConfig.ExportPolicy)
+	assert.Equal(t, "", volConfig.QosPolicy, "Expected QoS policy to be replaced by adaptive QoS policy")
+	assert.Equal(t, "extreme", volConfig.AdaptiveQosPolicy)
+	assert.Equal(t, "auto", volConfig.TieringPolicy)
+	assert.Equal(t, "0777", volConfig.UnixPermissions)
+}
+
+func TestVolumeConfigPlacementPolicyNotPersisted(t *testing.T) {
+
+	volConfig := &VolumeConfig{Name: "vol1", Size: "1Gi", PlacementPolicy: "spread"}
+
+	clone := volConfig.ConstructClone()
+	assert.Equal(t, "vol1", clone.Name)
+	assert.Equal(t, "", clone.PlacementPolicy, "Expected clone to have no placement policy")
+
+	configJSON, err := json.Marshal(volConfig)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(configJSON), "spread")
+}
@@ -11,6 +11,7 @@ type VolumeOperation string
 const (
 	// Transactions for synchronous operations
 	AddVolume      VolumeOperation = "addVolume"
+	CloneVolume    VolumeOperation = "cloneVolume"
 	DeleteVolume   VolumeOperation = "deleteVolume"
 	ImportVolume   VolumeOperation = "importVolume"
 	ResizeVolume   VolumeOperation = "resizeVolume"
@@ -20,14 +21,18 @@ const (
 
 	// Transactions for long-running operations
 	VolumeCreating VolumeOperation = "volumeCreating"
+	RelocateVolume VolumeOperation = "relocateVolume"
 )
 
 type VolumeTransaction struct {
-	Config               *VolumeConfig
-	VolumeCreatingConfig *VolumeCreatingConfig
-	SnapshotConfig       *SnapshotConfig
-	PVUpgradeConfig      *PVUpgradeConfig
-	Op                   VolumeOperation
+	Config                 *VolumeConfig
+	VolumeCreatingConfig   *VolumeCreatingConfig
+	VolumeRelocationConfig *VolumeRelocationConfig
+	SnapshotConfig         *SnapshotConfig
+	PVUpgradeConfig        *PVUpgradeConfig
+	Op                     VolumeOperation
+	// Operation journals the progress of operations that are resumed rather than rolled back
+	Operation *OperationRecord
 }
 
 type PVUpgradeConfig struct {
@@ -41,6 +41,11 @@ const (
 	StoragePools           = "storagePools"
 	AdditionalStoragePools = "additionalStoragePools"
 	ExcludeStoragePools    = "excludeStoragePools"
+
+	// Storage class placement policy parameters
+	PlacementPolicy        = "placementPolicy"
+	PlacementPackThreshold = "placementPackThreshold"
+	PlacementLabelWeights  = "placementLabelWeights"
 )
 
 var attrTypes = map[string]Type{
@@ -11,13 +11,14 @@ import (
 // UnmarshalJSON parses a JSON-formatted byte array into a storage class config struct.
 func (c *Config) UnmarshalJSON(data []byte) error {
 	var tmp struct {
-		Version         string              `json:"version"`
-		Name            string              `json:"name"`
-		Attributes      json.RawMessage     `json:"attributes,omitempty"`
-		Pools           map[string][]string `json:"storagePools,omitempty"`
-		RequiredStorage map[string][]string `json:"requiredStorage,omitempty"`
-		AdditionalPools map[string][]string `json:"additionalStoragePools,omitempty"`
-		ExcludePools    map[string][]string `json:"excludeStoragePools,omitempty"`
+		Version         string                 `json:"version"`
+		Name            string                 `json:"name"`
+		Attributes      json.RawMessage        `json:"attributes,omitempty"`
+		Pools           map[string][]string    `json:"storagePools,omitempty"`
+		RequiredStorage map[string][]string    `json:"requiredStorage,omitempty"`
+		AdditionalPools map[string][]string    `json:"additionalStoragePools,omitempty"`
+		ExcludePools    map[string][]string    `json:"excludeStoragePools,omitempty"`
+		PlacementPolicy *PlacementPolicyConfig `json:"placementPolicy,omitempty"`
 	}
 	err := json.Unmarshal(data, &tmp)
 	if err != nil {
@@ -36,6 +37,7 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	}
 
 	c.ExcludePools = tmp.ExcludePools
+	c.PlacementPolicy = tmp.PlacementPolicy
 
 	return err
 }
@@ -43,18 +45,20 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 // MarshalJSON emits a storage class config struct as a JSON-formatted byte array.
 func (c *Config) MarshalJSON() ([]byte, error) {
 	var tmp struct {
-		Version         string              `json:"version"`
-		Name            string              `json:"name"`
-		Attributes      json.RawMessage     `json:"attributes,omitempty"`
-		Pools           map[string][]string `json:"storagePools,omitempty"`
-		AdditionalPools map[string][]string `json:"additionalStoragePools,omitempty"`
-		ExcludePools    map[string][]string `json:"excludeStoragePools,omitempty"`
+		Version         string                 `json:"version"`
+		Name            string                 `json:"name"`
+		Attributes      json.RawMessage        `json:"attributes,omitempty"`
+		Pools           map[string][]string    `json:"storagePools,omitempty"`
+		AdditionalPools map[string][]string    `json:"additionalStoragePools,omitempty"`
+		ExcludePools    map[string][]string    `json:"excludeStoragePools,omitempty"`
+		PlacementPolicy *PlacementPolicyConfig `json:"placementPolicy,omitempty"`
 	}
 	tmp.Version = c.Version
 	tmp.Name = c.Name
 	tmp.Pools = c.Pools
 	tmp.AdditionalPools = c.AdditionalPools
 	tmp.ExcludePools = c.ExcludePools
+	tmp.PlacementPolicy = c.PlacementPolicy
 	attrs, err := storageattribute.MarshalRequestMap(c.Attributes)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,295 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package storageclass
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	storageattribute "github.com/netapp/trident/storage_attribute"
+)
+
+const (
+	// PlacementPolicyRandom orders equally preferred pools randomly, which is the default behavior
+	PlacementPolicyRandom = "random"
+	// PlacementPolicySpread prefers the least used pools
+	PlacementPolicySpread = storage.PlacementPolicySpread
+	// PlacementPolicyPack prefers the most used pools that are below a usage threshold
+	PlacementPolicyPack = "pack"
+	// PlacementPolicyWeightedLabels prefers pools whose labels carry the highest weights
+	PlacementPolicyWeightedLabels = "weightedLabels"
+
+	DefaultPackThreshold = 80
+)
+
+// PlacementPolicyConfig describes how Trident orders the candidate storage pools for a new volume.
+type PlacementPolicyConfig struct {
+	Type string `json:"type"`
+	// PackThreshold is the usage percentage above which the pack policy stops preferring a pool; 0
+	// selects DefaultPackThreshold
+	PackThreshold int `json:"packThreshold,omitempty"`
+	// LabelWeights maps pool labels, in the form "key=value", to a weight used by the weightedLabels policy
+	LabelWeights map[string]int `json:"labelWeights,omitempty"`
+}
+
+// PoolUsage contains live utilization data for a storage pool, as gathered from its backend.
+type PoolUsage struct {
+	TotalBytes     uint64
+	AvailableBytes uint64
+	VolumeCount    int
+}
+
+// UsedFraction returns the fraction of the pool's space that is in use.
+func (u *PoolUsage) UsedFraction() float64 {
+	if u.TotalBytes == 0 {
+		return 1
+	}
+	if u.AvailableBytes >= u.TotalBytes {
+		return 0
+	}
+	return float64(u.TotalBytes-u.AvailableBytes) / float64(u.TotalBytes)
+}
+
+// PoolUsageFunc returns the usage of a storage pool, or nil if the usage could not be determined.
+type PoolUsageFunc func(ctx context.Context, pool *storage.Pool) *PoolUsage
+
+// PlacementPolicy orders a set of storage pools that are equally acceptable for a new volume,
+// most desirable first.
+type PlacementPolicy interface {
+	Name() string
+	Order(ctx context.Context, pools []*storage.Pool, usage PoolUsageFunc) []*storage.Pool
+}
+
+// NewPlacementPolicy returns the placement policy described by the supplied config.  A nil
+// config yields the default random policy.
+func NewPlacementPolicy(c *PlacementPolicyConfig) (PlacementPolicy, error) {
+
+	if c == nil {
+		return &randomPlacementPolicy{}, nil
+	}
+
+	switch c.Type {
+	case "", PlacementPolicyRandom:
+		return &randomPlacementPolicy{}, nil
+	case PlacementPolicySpread:
+		return &spreadPlacementPolicy{}, nil
+	case PlacementPolicyPack:
+		threshold := c.PackThreshold
+		if threshold < 0 || threshold > 100 {
+			return nil, fmt.Errorf("invalid pack threshold %d; must be 0 (for the default of %d) or between "+
+				"1 and 100", threshold, DefaultPackThreshold)
+		}
+		if threshold == 0 {
+			threshold = DefaultPackThreshold
+		}
+		return &packPlacementPolicy{threshold: float64(threshold) / 100.0}, nil
+	case PlacementPolicyWeightedLabels:
+		if len(c.LabelWeights) == 0 {
+			return nil, fmt.Errorf("the %s placement policy requires label weights", PlacementPolicyWeightedLabels)
+		}
+		return &weightedLabelsPlacementPolicy{weights: c.LabelWeights}, nil
+	default:
+		return nil, fmt.Errorf("unknown placement policy %s", c.Type)
+	}
+}
+
+// CreatePlacementPolicyConfigFromParameters builds a placement policy config from storage class
+// parameters, returning nil if no placement policy parameters were specified.
+//
+//	placementPolicy: "spread"
+//	placementPackThreshold: "85"
+//	placementLabelWeights: "performance=gold:10,performance=silver:5"
+func CreatePlacementPolicyConfigFromParameters(parameters map[string]string) (*PlacementPolicyConfig, error) {
+
+	policyType, ok := parameters[storageattribute.PlacementPolicy]
+	if !ok {
+		return nil, nil
+	}
+
+	c := &PlacementPolicyConfig{Type: policyType}
+
+	if value, ok := parameters[storageattribute.PlacementPackThreshold]; ok {
+		threshold, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", storageattribute.PlacementPackThreshold, err)
+		}
+		c.PackThreshold = threshold
+	}
+
+	if value, ok := parameters[storageattribute.PlacementLabelWeights]; ok {
+		c.LabelWeights = make(map[string]int)
+		for _, entry := range strings.Split(value, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			separator := strings.LastIndex(entry, ":")
+			if separator < 1 || !strings.Contains(entry[:separator], "=") {
+				return nil, fmt.Errorf("invalid label weight %s; expected key=value:weight", entry)
+			}
+			weight, err := strconv.Atoi(entry[separator+1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid label weight %s: %v", entry, err)
+			}
+			c.LabelWeights[entry[:separator]] = weight
+		}
+	}
+
+	if _, err := NewPlacementPolicy(c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// shufflePools returns the pools in random order.
+func shufflePools(pools []*storage.Pool) []*storage.Pool {
+	rand.Shuffle(len(pools), func(i, j int) {
+		pools[i], pools[j] = pools[j], pools[i]
+	})
+	return pools
+}
+
+// getPoolUsages calls the usage function once for each pool.
+func getPoolUsages(ctx context.Context, pools []*storage.Pool, usage PoolUsageFunc) map[*storage.Pool]*PoolUsage {
+	usages := make(map[*storage.Pool]*PoolUsage, len(pools))
+	for _, pool := range pools {
+		if usage != nil {
+			usages[pool] = usage(ctx, pool)
+		}
+	}
+	return usages
+}
+
+// orderByUsage shuffles the pools, so that ties are broken randomly, and then stably sorts them
+// using the supplied comparison.  Pools with unknown usage always sort last.
+func orderByUsage(
+	ctx context.Context, pools []*storage.Pool, usage PoolUsageFunc, less func(a, b *PoolUsage) bool,
+) []*storage.Pool {
+
+	pools = shufflePools(pools)
+	usages := getPoolUsages(ctx, pools, usage)
+
+	sort.SliceStable(pools, func(i, j int) bool {
+		a, b := usages[pools[i]], usages[pools[j]]
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return less(a, b)
+	})
+
+	return pools
+}
+
+// lessUsed returns true if pool usage a is lower than pool usage b, considering
+// used space first and then volume count.
+func lessUsed(a, b *PoolUsage) bool {
+	if a.UsedFraction() != b.UsedFraction() {
+		return a.UsedFraction() < b.UsedFraction()
+	}
+	return a.VolumeCount < b.VolumeCount
+}
+
+type randomPlacementPolicy struct{}
+
+func (p *randomPlacementPolicy) Name() string {
+	return PlacementPolicyRandom
+}
+
+func (p *randomPlacementPolicy) Order(_ context.Context, pools []*storage.Pool, _ PoolUsageFunc) []*storage.Pool {
+	return shufflePools(pools)
+}
+
+type spreadPlacementPolicy struct{}
+
+func (p *spreadPlacementPolicy) Name() string {
+	return PlacementPolicySpread
+}
+
+// Order places the least used pools first.
+func (p *spreadPlacementPolicy) Order(
+	ctx context.Context, pools []*storage.Pool, usage PoolUsageFunc,
+) []*storage.Pool {
+	return orderByUsage(ctx, pools, usage, lessUsed)
+}
+
+type packPlacementPolicy struct {
+	threshold float64
+}
+
+func (p *packPlacementPolicy) Name() string {
+	return PlacementPolicyPack
+}
+
+// Order places the most used pools that are below the usage threshold first, followed by any
+// pools at or above the threshold in order of increasing usage.
+func (p *packPlacementPolicy) Order(
+	ctx context.Context, pools []*storage.Pool, usage PoolUsageFunc,
+) []*storage.Pool {
+	return orderByUsage(ctx, pools, usage, func(a, b *PoolUsage) bool {
+		aBelow, bBelow := a.UsedFraction() < p.threshold, b.UsedFraction() < p.threshold
+		if aBelow != bBelow {
+			return aBelow
+		}
+		if !aBelow {
+			return lessUsed(a, b)
+		}
+		return lessUsed(b, a)
+	})
+}
+
+type weightedLabelsPlacementPolicy struct {
+	weights map[string]int
+}
+
+func (p *weightedLabelsPlacementPolicy) Name() string {
+	return PlacementPolicyWeightedLabels
+}
+
+// weight returns the sum of the weights of all labels on a pool.
+func (p *weightedLabelsPlacementPolicy) weight(pool *storage.Pool) int {
+
+	labelOffer, ok := pool.Attributes[storageattribute.Labels].(storageattribute.LabelOffer)
+	if !ok {
+		return 0
+	}
+
+	weight := 0
+	for key, value := range labelOffer.Labels() {
+		weight += p.weights[key+"="+value]
+	}
+	return weight
+}
+
+// Order places the pools with the highest label weights first, using the spread policy to
+// order pools with equal weights.
+func (p *weightedLabelsPlacementPolicy) Order(
+	ctx context.Context, pools []*storage.Pool, usage PoolUsageFunc,
+) []*storage.Pool {
+
+	pools = orderByUsage(ctx, pools, usage, lessUsed)
+
+	weights := make(map[*storage.Pool]int, len(pools))
+	for _, pool := range pools {
+		weights[pool] = p.weight(pool)
+	}
+
+	sort.SliceStable(pools, func(i, j int) bool {
+		return weights[pools[i]] > weights[pools[j]]
+	})
+
+	Logc(ctx).WithFields(log.Fields{
+		"policy":  p.Name(),
+		"weights": weights,
+	}).Trace("Ordered pools by label weight.")
+
+	return pools
+}
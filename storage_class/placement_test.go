@@ -0,0 +1,201 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package storageclass
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	storageattribute "github.com/netapp/trident/storage_attribute"
+)
+
+func getPlacementTestPools() ([]*storage.Pool, PoolUsageFunc) {
+
+	pools := []*storage.Pool{
+		{Name: "empty", Attributes: map[string]storageattribute.Offer{
+			storageattribute.Labels: storageattribute.NewLabelOffer(map[string]string{"tier": "bronze"}),
+		}},
+		{Name: "half", Attributes: map[string]storageattribute.Offer{
+			storageattribute.Labels: storageattribute.NewLabelOffer(map[string]string{"tier": "gold"}),
+		}},
+		{Name: "mostlyFull", Attributes: map[string]storageattribute.Offer{
+			storageattribute.Labels: storageattribute.NewLabelOffer(map[string]string{"tier": "silver"}),
+		}},
+		{Name: "unknown", Attributes: map[string]storageattribute.Offer{}},
+	}
+
+	usages := map[string]*PoolUsage{
+		"empty":      {TotalBytes: 100, AvailableBytes: 100, VolumeCount: 0},
+		"half":       {TotalBytes: 100, AvailableBytes: 50, VolumeCount: 5},
+		"mostlyFull": {TotalBytes: 100, AvailableBytes: 10, VolumeCount: 9},
+	}
+
+	usage := func(_ context.Context, pool *storage.Pool) *PoolUsage {
+		return usages[pool.Name]
+	}
+
+	return pools, usage
+}
+
+func getPoolNames(pools []*storage.Pool) []string {
+	names := make([]string, 0, len(pools))
+	for _, pool := range pools {
+		names = append(names, pool.Name)
+	}
+	return names
+}
+
+func TestPlacementPolicies(t *testing.T) {
+	log.Debug("Running TestPlacementPolicies...")
+
+	tests := []struct {
+		name     string
+		config   *PlacementPolicyConfig
+		expected []string
+	}{
+		{
+			name:     "spread",
+			config:   &PlacementPolicyConfig{Type: PlacementPolicySpread},
+			expected: []string{"empty", "half", "mostlyFull", "unknown"},
+		},
+		{
+			name:     "packDefaultThreshold",
+			config:   &PlacementPolicyConfig{Type: PlacementPolicyPack},
+			expected: []string{"half", "empty", "mostlyFull", "unknown"},
+		},
+		{
+			name:     "packHighThreshold",
+			config:   &PlacementPolicyConfig{Type: PlacementPolicyPack, PackThreshold: 95},
+			expected: []string{"mostlyFull", "half", "empty", "unknown"},
+		},
+		{
+			name: "weightedLabels",
+			config: &PlacementPolicyConfig{
+				Type:         PlacementPolicyWeightedLabels,
+				LabelWeights: map[string]int{"tier=gold": 10, "tier=silver": 5},
+			},
+			expected: []string{"half", "mostlyFull", "empty", "unknown"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pools, usage := getPlacementTestPools()
+
+			policy, err := NewPlacementPolicy(test.config)
+			assert.NoError(t, err)
+			assert.Equal(t, test.config.Type, policy.Name())
+
+			ordered := policy.Order(context.Background(), pools, usage)
+			assert.Equal(t, test.expected, getPoolNames(ordered))
+		})
+	}
+}
+
+func TestRandomPlacementPolicy(t *testing.T) {
+	log.Debug("Running TestRandomPlacementPolicy...")
+
+	pools, _ := getPlacementTestPools()
+
+	policy, err := NewPlacementPolicy(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, PlacementPolicyRandom, policy.Name())
+
+	called := false
+	usage := func(_ context.Context, _ *storage.Pool) *PoolUsage {
+		called = true
+		return nil
+	}
+
+	ordered := policy.Order(context.Background(), pools, usage)
+	assert.ElementsMatch(t, []string{"empty", "half", "mostlyFull", "unknown"}, getPoolNames(ordered))
+	assert.False(t, called, "random placement should not gather pool usage")
+}
+
+func TestNewPlacementPolicyErrors(t *testing.T) {
+	log.Debug("Running TestNewPlacementPolicyErrors...")
+
+	configs := []*PlacementPolicyConfig{
+		{Type: "roundRobin"},
+		{Type: PlacementPolicyPack, PackThreshold: 101},
+		{Type: PlacementPolicyPack, PackThreshold: -1},
+		{Type: PlacementPolicyWeightedLabels},
+	}
+
+	for _, policyConfig := range configs {
+		_, err := NewPlacementPolicy(policyConfig)
+		assert.Error(t, err, "expected an error for policy %v", policyConfig)
+	}
+}
+
+func TestCreatePlacementPolicyConfigFromParameters(t *testing.T) {
+	log.Debug("Running TestCreatePlacementPolicyConfigFromParameters...")
+
+	policyConfig, err := CreatePlacementPolicyConfigFromParameters(map[string]string{"media": "ssd"})
+	assert.NoError(t, err)
+	assert.Nil(t, policyConfig)
+
+	policyConfig, err = CreatePlacementPolicyConfigFromParameters(map[string]string{
+		storageattribute.PlacementPolicy:        PlacementPolicyPack,
+		storageattribute.PlacementPackThreshold: "85%",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &PlacementPolicyConfig{Type: PlacementPolicyPack, PackThreshold: 85}, policyConfig)
+
+	policyConfig, err = CreatePlacementPolicyConfigFromParameters(map[string]string{
+		storageattribute.PlacementPolicy:       PlacementPolicyWeightedLabels,
+		storageattribute.PlacementLabelWeights: "tier=gold:10, tier=silver:5",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"tier=gold": 10, "tier=silver": 5}, policyConfig.LabelWeights)
+
+	invalidParameters := []map[string]string{
+		{storageattribute.PlacementPolicy: "roundRobin"},
+		{storageattribute.PlacementPolicy: PlacementPolicyPack, storageattribute.PlacementPackThreshold: "most"},
+		{storageattribute.PlacementPolicy: PlacementPolicyWeightedLabels, storageattribute.PlacementLabelWeights: "gold:10"},
+		{storageattribute.PlacementPolicy: PlacementPolicyWeightedLabels, storageattribute.PlacementLabelWeights: "tier=gold"},
+	}
+	for _, parameters := range invalidParameters {
+		_, err = CreatePlacementPolicyConfigFromParameters(parameters)
+		assert.Error(t, err, "expected an error for parameters %v", parameters)
+	}
+}
+
+func TestGetStoragePoolsForProtocolByBackendWithPolicy(t *testing.T) {
+	log.Debug("Running TestGetStoragePoolsForProtocolByBackendWithPolicy...")
+
+	pools, usage := getPlacementTestPools()
+	backend := &storage.Backend{Name: "fake", State: storage.Online}
+	for _, pool := range pools {
+		pool.Backend = backend
+	}
+
+	sc := New(&Config{Name: "spread", PlacementPolicy: &PlacementPolicyConfig{Type: PlacementPolicySpread}})
+	sc.pools = pools
+
+	ordered := sc.GetStoragePoolsForProtocolByBackend(context.Background(), config.ProtocolAny, nil, nil, usage)
+	assert.Equal(t, []string{"empty", "half", "mostlyFull", "unknown"}, getPoolNames(ordered))
+
+	// An invalid policy falls back to random placement
+	sc = New(&Config{Name: "invalid", PlacementPolicy: &PlacementPolicyConfig{Type: "roundRobin"}})
+	sc.pools = pools
+
+	ordered = sc.GetStoragePoolsForProtocolByBackend(context.Background(), config.ProtocolAny, nil, nil, usage)
+	assert.ElementsMatch(t, []string{"empty", "half", "mostlyFull", "unknown"}, getPoolNames(ordered))
+
+	// Pools of a backend in maintenance are not offered for placement
+	maintenanceBackend := &storage.Backend{Name: "maintenance", State: storage.Maintenance}
+	pools[0].Backend = maintenanceBackend
+	sc = New(&Config{Name: "spread", PlacementPolicy: &PlacementPolicyConfig{Type: PlacementPolicySpread}})
+	sc.pools = pools
+
+	ordered = sc.GetStoragePoolsForProtocolByBackend(context.Background(), config.ProtocolAny, nil, nil, usage)
+	assert.NotContains(t, getPoolNames(ordered), pools[0].Name)
+	assert.Len(t, ordered, len(pools)-1)
+	assert.Len(t, sc.GetStoragePoolsForProtocol(context.Background(), config.ProtocolAny), len(pools))
+}
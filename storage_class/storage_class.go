@@ -6,7 +6,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"regexp"
 	"sort"
 	"strings"
@@ -232,11 +231,14 @@ func (s *StorageClass) CheckAndAddBackend(ctx context.Context, b *storage.Backen
 		"storageClass": s.GetName(),
 	}).Debug("Checking backend for storage class")
 
-	if !b.State.IsOnline() {
+	if !b.State.IsOnline() && !b.State.IsMaintenance() {
 		Logc(ctx).WithField("backend", b.Name).Warn("Backend not online.")
 		return 0
 	}
 
+	s.poolsMutex.Lock()
+	defer s.poolsMutex.Unlock()
+
 	added := 0
 	for _, storagePool := range b.Storage {
 		if s.Matches(ctx, storagePool) {
@@ -266,6 +268,8 @@ func (s *StorageClass) IsAddedToBackend(backend *storage.Backend, storageClassNa
 }
 
 func (s *StorageClass) RemovePoolsForBackend(backend *storage.Backend) {
+	s.poolsMutex.Lock()
+	defer s.poolsMutex.Unlock()
 	newStoragePools := make([]*storage.Pool, 0)
 	for _, storagePool := range s.pools {
 		if storagePool.Backend != backend {
@@ -292,6 +296,8 @@ func (s *StorageClass) GetAdditionalStoragePools() map[string][]string {
 }
 
 func (s *StorageClass) GetStoragePoolsForProtocol(ctx context.Context, p config.Protocol) []*storage.Pool {
+	s.poolsMutex.RLock()
+	defer s.poolsMutex.RUnlock()
 	ret := make([]*storage.Pool, 0, len(s.pools))
 	// TODO:  Change this to work with indices of backends?
 	for _, storagePool := range s.pools {
@@ -346,11 +352,46 @@ func FilterPoolsOnTopology(
 	return filteredPools
 }
 
+// FilterPoolsOnAttributes returns the pools that can satisfy a volume with the specified attributes.  Drivers
+// use it to find the physical pools behind a virtual pool, so any selector, which only applies to the pools
+// that a backend reports, is ignored.
+func FilterPoolsOnAttributes(
+	ctx context.Context, pools map[string]*storage.Pool, volAttributes map[string]storageattribute.Request,
+) []*storage.Pool {
+
+	// Make a storage class from the volume attributes to simplify pool matching
+	attributesCopy := make(map[string]storageattribute.Request)
+	for k, v := range volAttributes {
+		attributesCopy[k] = v
+	}
+	delete(attributesCopy, storageattribute.Selector)
+	storageClass := NewFromAttributes(attributesCopy)
+
+	filteredPools := make([]*storage.Pool, 0)
+	for _, pool := range pools {
+		if storageClass.Matches(ctx, pool) {
+			filteredPools = append(filteredPools, pool)
+		}
+	}
+
+	return filteredPools
+}
+
 // SortPoolsByPreferredTopologies returns a list of pools ordered by the pools supportedTopologies field against
 // the provided list of preferredTopologies. If 2 or more pools can support a given preferredTopology, they are shuffled
 // randomly within that segment of the list, in order to prevent hotspots.
 func SortPoolsByPreferredTopologies(
 	ctx context.Context, pools []*storage.Pool, preferredTopologies []map[string]string,
+) []*storage.Pool {
+	return sortPoolsByPreferredTopologies(ctx, pools, preferredTopologies, shufflePools)
+}
+
+// sortPoolsByPreferredTopologies returns a list of pools ordered by the pools supportedTopologies field against
+// the provided list of preferredTopologies. If 2 or more pools can support a given preferredTopology, they are
+// ordered within that segment of the list by the supplied function.
+func sortPoolsByPreferredTopologies(
+	ctx context.Context, pools []*storage.Pool, preferredTopologies []map[string]string,
+	order func([]*storage.Pool) []*storage.Pool,
 ) []*storage.Pool {
 	remainingPools := make([]*storage.Pool, len(pools))
 	copy(remainingPools, pools)
@@ -375,26 +416,36 @@ func SortPoolsByPreferredTopologies(
 		remainingPools = make([]*storage.Pool, len(newRemainingPools))
 		copy(remainingPools, newRemainingPools)
 
-		// shuffle bucket
-		rand.Shuffle(len(poolBucket), func(i, j int) {
-			poolBucket[i], poolBucket[j] = poolBucket[j], poolBucket[i]
-		})
-
-		// add all in bucket to final list
-		orderedPools = append(orderedPools, poolBucket...)
+		// order bucket and add all in bucket to final list
+		orderedPools = append(orderedPools, order(poolBucket)...)
 	}
 
-	// shuffle and add leftover pools the did not match any preference
-	rand.Shuffle(len(remainingPools), func(i, j int) {
-		remainingPools[i], remainingPools[j] = remainingPools[j], remainingPools[i]
-	})
-	return append(orderedPools, remainingPools...)
+	// order and add leftover pools the did not match any preference
+	return append(orderedPools, order(remainingPools)...)
+}
+
+// GetPlacementPolicy returns the placement policy configured for this storage class.  If the
+// configured policy is invalid, the default random policy is returned.
+func (s *StorageClass) GetPlacementPolicy(ctx context.Context) PlacementPolicy {
+
+	policy, err := NewPlacementPolicy(s.config.PlacementPolicy)
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"storageClass": s.GetName(),
+			"error":        err,
+		}).Warning("Invalid placement policy, using random placement.")
+		policy, _ = NewPlacementPolicy(nil)
+	}
+	return policy
 }
 
 // GetStoragePoolsForProtocolByBackend returns an ordered list of pools, where
-// each pool matches the supplied protocol.
+// each pool matches the supplied protocol and its backend is online.  Pools that
+// are equally preferred by topology are ordered using the storage class's placement
+// policy, which may consult the supplied usage function for live pool utilization.
 func (s *StorageClass) GetStoragePoolsForProtocolByBackend(
 	ctx context.Context, p config.Protocol, requisiteTopologies, preferredTopologies []map[string]string,
+	usage PoolUsageFunc,
 ) []*storage.Pool {
 
 	// Get all matching pools
@@ -404,17 +455,43 @@ func (s *StorageClass) GetStoragePoolsForProtocolByBackend(
 	if len(poolsForProtocol) == 0 {
 		Logc(ctx).Info("no backend pools support the requisite protocol")
 	}
-	pools = FilterPoolsOnTopology(ctx, poolsForProtocol, requisiteTopologies)
+
+	// Backends in maintenance remain members of the storage class but take no new volumes
+	poolsOnline := make([]*storage.Pool, 0, len(poolsForProtocol))
+	for _, pool := range poolsForProtocol {
+		if pool.Backend.State.IsOnline() {
+			poolsOnline = append(poolsOnline, pool)
+		}
+	}
+	if len(poolsOnline) < len(poolsForProtocol) {
+		Logc(ctx).WithField("excluded", len(poolsForProtocol)-len(poolsOnline)).Debug(
+			"Excluded pools whose backends are not online.")
+	}
+
+	pools = FilterPoolsOnTopology(ctx, poolsOnline, requisiteTopologies)
 	if len(pools) == 0 {
 		Logc(ctx).Info("no backend pools support any requisite topologies")
 	}
-	pools = SortPoolsByPreferredTopologies(ctx, pools, preferredTopologies)
+
+	policy := s.GetPlacementPolicy(ctx)
+	pools = sortPoolsByPreferredTopologies(ctx, pools, preferredTopologies, func(p []*storage.Pool) []*storage.Pool {
+		return policy.Order(ctx, p, usage)
+	})
+
+	Logc(ctx).WithFields(log.Fields{
+		"storageClass":    s.GetName(),
+		"placementPolicy": policy.Name(),
+		"pools":           len(pools),
+	}).Debug("Ordered storage pools for placement.")
 
 	return pools
 }
 
+// Pools returns a copy of the list of storage pools that satisfy this storage class.
 func (s *StorageClass) Pools() []*storage.Pool {
-	return s.pools
+	s.poolsMutex.RLock()
+	defer s.poolsMutex.RUnlock()
+	return append([]*storage.Pool(nil), s.pools...)
 }
 
 func (s *StorageClass) ConstructExternal(ctx context.Context) *External {
@@ -423,7 +500,7 @@ func (s *StorageClass) ConstructExternal(ctx context.Context) *External {
 		Config:       s.config,
 		StoragePools: make(map[string][]string),
 	}
-	for _, storagePool := range s.pools {
+	for _, storagePool := range s.Pools() {
 		backendName := storagePool.Backend.Name
 		if storagePoolList, ok := ret.StoragePools[backendName]; ok {
 			Logc(ctx).WithFields(log.Fields{
@@ -3,6 +3,8 @@
 package storageclass
 
 import (
+	"sync"
+
 	"github.com/netapp/trident/storage"
 	storageattribute "github.com/netapp/trident/storage_attribute"
 )
@@ -10,6 +12,9 @@ import (
 type StorageClass struct {
 	config *Config
 	pools  []*storage.Pool
+
+	// poolsMutex protects pools, which may be read while volumes are being placed
+	poolsMutex sync.RWMutex
 }
 
 type Config struct {
@@ -21,6 +26,7 @@ type Config struct {
 	Pools           map[string][]string                 `json:"storagePools,omitempty"`
 	AdditionalPools map[string][]string                 `json:"additionalStoragePools,omitempty"`
 	ExcludePools    map[string][]string                 `json:"excludeStoragePools,omitempty"`
+	PlacementPolicy *PlacementPolicyConfig              `json:"placementPolicy,omitempty" hash:"ignore"`
 }
 
 type External struct {
@@ -33,6 +33,8 @@ const (
 	MinimumAPIVersion         = "1.1.8"
 	MinimumSDEVersion         = "2020.4.0"
 
+	MaximumCVSVolumeSizeBytes = 109951162777600 // 100 TiB
+
 	defaultServiceLevel    = api.ServiceLevelStandard
 	defaultNfsMountOptions = "-o nfsvers=3"
 	defaultSecurityStyle   = "unix"
@@ -1135,6 +1137,26 @@ func (d *NFSStorageDriver) Publish(
 	return nil
 }
 
+// Unpublish the volume from the host specified in publishInfo.  NFS volumes are exported to all
+// nodes, so there is nothing to remove.
+func (d *NFSStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "NFSStorageDriver",
+			"name":   volConfig.InternalName,
+			"node":   publishInfo.HostName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	return nil
+}
+
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
 func (d *NFSStorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig) error {
 	return nil
@@ -1446,6 +1468,41 @@ func (d *NFSStorageDriver) Get(ctx context.Context, name string) error {
 	return err
 }
 
+// GetVolumeCondition returns the condition of a volume, which is abnormal if the volume does not exist,
+// is not available, or is nearly full.
+func (d *NFSStorageDriver) GetVolumeCondition(
+	ctx context.Context, volConfig *storage.VolumeConfig,
+) (*storage.VolumeCondition, error) {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "GetVolumeCondition", "Type": "NFSStorageDriver", "name": name}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetVolumeCondition")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetVolumeCondition")
+	}
+
+	volumeExists, volume, err := d.API.VolumeExistsByCreationToken(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for existing volume %s: %v", name, err)
+	}
+	if !volumeExists {
+		return storage.NewAbnormalVolumeCondition("volume %s does not exist", name), nil
+	}
+
+	if volume.LifeCycleState != api.StateAvailable {
+		return storage.NewAbnormalVolumeCondition("volume %s is %s", name, volume.LifeCycleState), nil
+	}
+
+	if volume.QuotaInBytes > 0 {
+		percentUsed := int64(volume.UsedBytes) * 100 / volume.QuotaInBytes
+		if percentUsed >= storage.VolumeFullThresholdPercent {
+			return storage.NewAbnormalVolumeCondition("volume %s is %d%% full", name, percentUsed), nil
+		}
+	}
+
+	return storage.NewNormalVolumeCondition(), nil
+}
+
 // Resize increases a volume's quota
 func (d *NFSStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
 
@@ -1507,6 +1564,24 @@ func (d *NFSStorageDriver) Resize(ctx context.Context, volConfig *storage.Volume
 	return nil
 }
 
+// Modify changes the attributes of an existing volume, which is not supported by this driver.
+func (d *NFSStorageDriver) Modify(
+	ctx context.Context, volConfig *storage.VolumeConfig, modification *storage.VolumeModification,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Modify",
+			"Type":   "NFSStorageDriver",
+			"name":   volConfig.InternalName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Modify")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Modify")
+	}
+
+	return utils.UnsupportedError(fmt.Sprintf("volume modification is not supported by backend type %s", d.Name()))
+}
+
 // Retrieve storage capabilities and register pools with specified backend.
 func (d *NFSStorageDriver) GetStorageBackendSpecs(_ context.Context, backend *storage.Backend) error {
 
@@ -1529,6 +1604,34 @@ func (d *NFSStorageDriver) GetStorageBackendPhysicalPoolNames(context.Context) [
 	return []string{}
 }
 
+// GetCapacity returns the capacity available to a volume in the specified pool.  CVS has no fixed-size
+// pools, so the reported capacity is the largest volume that may be created in the configured region.
+func (d *NFSStorageDriver) GetCapacity(
+	ctx context.Context, storagePool *storage.Pool, _ map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+
+	if _, ok := d.pools[storagePool.Name]; !ok {
+		return nil, fmt.Errorf("could not find pool %s", storagePool.Name)
+	}
+
+	capacityBytes := uint64(MaximumCVSVolumeSizeBytes)
+
+	limited, limitBytes, err := drivers.CheckVolumeSizeLimits(ctx, 0, d.Config.CommonStorageDriverConfig)
+	if err != nil {
+		return nil, err
+	}
+	if limited && limitBytes < capacityBytes {
+		capacityBytes = limitBytes
+	}
+
+	return map[string]*storage.PoolCapacity{
+		d.Config.APIRegion: {
+			TotalBytes:     capacityBytes,
+			AvailableBytes: capacityBytes,
+		},
+	}, nil
+}
+
 func (d *NFSStorageDriver) GetInternalVolumeName(ctx context.Context, name string) string {
 
 	if tridentconfig.UsingPassthroughStore {
@@ -1089,6 +1089,26 @@ func (d *NFSStorageDriver) Publish(
 	return nil
 }
 
+// Unpublish the volume from the host specified in publishInfo.  NFS volumes are exported to all
+// nodes, so there is nothing to remove.
+func (d *NFSStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "NFSStorageDriver",
+			"name":   volConfig.InternalName,
+			"node":   publishInfo.HostName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	return nil
+}
+
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
 func (d *NFSStorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig) error {
 	return nil
@@ -1400,6 +1420,35 @@ func (d *NFSStorageDriver) Get(ctx context.Context, name string) error {
 	return err
 }
 
+// GetVolumeCondition returns the condition of a volume, which is abnormal if the volume does not exist,
+// is not available.
+func (d *NFSStorageDriver) GetVolumeCondition(
+	ctx context.Context, volConfig *storage.VolumeConfig,
+) (*storage.VolumeCondition, error) {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "GetVolumeCondition", "Type": "NFSStorageDriver", "name": name}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetVolumeCondition")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetVolumeCondition")
+	}
+
+	volumeExists, volume, err := d.SDK.VolumeExistsByCreationToken(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for existing volume %s: %v", name, err)
+	}
+	if !volumeExists {
+		return storage.NewAbnormalVolumeCondition("volume %s does not exist", name), nil
+	}
+
+	// The service does not report the space used by a volume
+	if volume.ProvisioningState != sdk.StateAvailable {
+		return storage.NewAbnormalVolumeCondition("volume %s is %s", name, volume.ProvisioningState), nil
+	}
+
+	return storage.NewNormalVolumeCondition(), nil
+}
+
 // Resize increases a volume's quota
 func (d *NFSStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
 
@@ -1461,6 +1510,24 @@ func (d *NFSStorageDriver) Resize(ctx context.Context, volConfig *storage.Volume
 	return nil
 }
 
+// Modify changes the attributes of an existing volume, which is not supported by this driver.
+func (d *NFSStorageDriver) Modify(
+	ctx context.Context, volConfig *storage.VolumeConfig, modification *storage.VolumeModification,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Modify",
+			"Type":   "NFSStorageDriver",
+			"name":   volConfig.InternalName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Modify")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Modify")
+	}
+
+	return utils.UnsupportedError(fmt.Sprintf("volume modification is not supported by backend type %s", d.Name()))
+}
+
 // Retrieve storage capabilities and register pools with specified backend.
 func (d *NFSStorageDriver) GetStorageBackendSpecs(_ context.Context, backend *storage.Backend) error {
 
@@ -1483,6 +1550,42 @@ func (d *NFSStorageDriver) GetStorageBackendPhysicalPoolNames(context.Context) [
 	return []string{}
 }
 
+// GetCapacity returns the capacity of the ANF capacity pools that could host a volume in the specified pool
+func (d *NFSStorageDriver) GetCapacity(
+	ctx context.Context, storagePool *storage.Pool, _ map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+
+	if _, ok := d.pools[storagePool.Name]; !ok {
+		return nil, fmt.Errorf("could not find pool %s", storagePool.Name)
+	}
+
+	cpools, err := d.SDK.GetCapacityPoolsForStoragePool(storagePool.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	capacity := make(map[string]*storage.PoolCapacity)
+	for i := range cpools {
+		cpool := &cpools[i]
+
+		usedBytes, err := d.SDK.GetCapacityPoolUsedBytes(ctx, cpool)
+		if err != nil {
+			return nil, fmt.Errorf("could not get volumes in capacity pool %s; %v", cpool.Name, err)
+		}
+
+		poolCapacity := &storage.PoolCapacity{}
+		if cpool.Size > 0 {
+			poolCapacity.TotalBytes = uint64(cpool.Size)
+		}
+		if cpool.Size > usedBytes {
+			poolCapacity.AvailableBytes = uint64(cpool.Size - usedBytes)
+		}
+		capacity[cpool.Name] = poolCapacity
+	}
+
+	return capacity, nil
+}
+
 func (d *NFSStorageDriver) GetInternalVolumeName(_ context.Context, name string) string {
 
 	if tridentconfig.UsingPassthroughStore {
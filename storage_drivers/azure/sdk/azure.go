@@ -386,6 +386,24 @@ func (d *Client) getVolumesFromPool(
 	return &fses, nil
 }
 
+// GetCapacityPoolUsedBytes returns the sum of the quotas of all volumes in a capacity pool
+func (d *Client) GetCapacityPoolUsedBytes(ctx context.Context, cpool *CapacityPool) (int64, error) {
+
+	cookie := createCookie(cpool.ResourceGroup, cpool.NetAppAccount, poolShortname(cpool.Name), DoNotUseSPoolName)
+
+	filesystems, err := d.getVolumesFromPool(ctx, cookie, "")
+	if err != nil {
+		return 0, err
+	}
+
+	usedBytes := int64(0)
+	for _, fs := range *filesystems {
+		usedBytes += fs.QuotaInBytes
+	}
+
+	return usedBytes, nil
+}
+
 // GetVolumes returns a list of ALL volumes
 func (d *Client) GetVolumes(ctx context.Context) (*[]FileSystem, error) {
 
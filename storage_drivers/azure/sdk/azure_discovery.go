@@ -147,6 +147,34 @@ func (d *Client) GetCookieByStoragePoolName(spoolname string) (*AzureCapacityPoo
 	return createCookie(cpool.ResourceGroup, cpool.NetAppAccount, cpool.Name, spoolname), nil
 }
 
+// GetCapacityPoolsForStoragePool returns all capacity pools that could host a volume in the named storage pool
+func (d *Client) GetCapacityPoolsForStoragePool(spoolname string) ([]CapacityPool, error) {
+	spool := d.SDKClient.AzureResources.StoragePoolMap[spoolname]
+	if spool == nil {
+		return nil, fmt.Errorf("no pool '%s' registered", spoolname)
+	}
+
+	// Don't allow queries during a rebuild
+	d.SDKClient.AzureResources.m.Lock()
+	defer d.SDKClient.AzureResources.m.Unlock()
+
+	cpools, err := d.capacityPoolsWithStoragePoolAttributes(
+		spool.InternalAttributes[PLocation],
+		spool.InternalAttributes[PServiceLevel],
+		spool.InternalAttributes[PSubnet])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cpools == nil {
+		return []CapacityPool{}, nil
+	}
+
+	// Return a copy so the caller isn't affected by a rebuild
+	return append([]CapacityPool{}, *cpools...), nil
+}
+
 /////////////////////////////////////////////////////////////////////////////////
 // Internal functions to do discovery
 /////////////////////////////////////////////////////////////////////////////////
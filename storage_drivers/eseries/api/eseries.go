@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -33,6 +34,12 @@ const hostMappingType = "host"
 const hostGroupMappingType = "cluster"
 const defaultPoolSearchPattern = ".+"
 
+// Snapshot groups are created with a repository sized as a percentage of the base volume,
+// and they warn when the repository is mostly full.
+const snapshotRepositoryPercentage = 20
+const snapshotRepositoryWarningThreshold = 75
+const snapshotGroupImageLimit = 32
+
 // ClientConfig holds configuration data for the API driver object.
 type ClientConfig struct {
 	// Web Proxy Services Info
@@ -1121,6 +1128,603 @@ func (d Client) DeleteVolume(ctx context.Context, volume VolumeEx) error {
 	return nil
 }
 
+// RenameVolume changes the name (label) of a volume on the array.
+func (d Client) RenameVolume(ctx context.Context, volume VolumeEx, newName string) (VolumeEx, error) {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":  "RenameVolume",
+			"Type":    "Client",
+			"name":    volume.Label,
+			"newName": newName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> RenameVolume")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< RenameVolume")
+	}
+
+	// Ensure that we do not exceed the maximum allowed volume length
+	if len(newName) > maxNameLength {
+		return VolumeEx{}, fmt.Errorf("the volume name %v exceeds the maximum length of %d characters", newName,
+			maxNameLength)
+	}
+
+	request := VolumeUpdateRequest{
+		Name: newName,
+	}
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return VolumeEx{}, fmt.Errorf("could not marshal JSON request: %v; %v", request, err)
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, jsonRequest, "POST", "/volumes/"+volume.VolumeRef)
+	if err != nil {
+		return VolumeEx{}, fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		apiError := d.getErrorFromHTTPResponse(response, responseBody)
+		apiError.Message = fmt.Sprintf("could not rename volume %s; %s", volume.Label, apiError.Message)
+		return VolumeEx{}, apiError
+	}
+
+	vol := VolumeEx{}
+	if err := json.Unmarshal(responseBody, &vol); err != nil {
+		return VolumeEx{}, fmt.Errorf("could not parse API response: %s; %v", string(responseBody), err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"OldName":   volume.Label,
+		"Name":      vol.Label,
+		"VolumeRef": vol.VolumeRef,
+	}).Debug("Renamed volume.")
+
+	return vol, nil
+}
+
+// GetSnapshotGroups returns an array containing all the snapshot groups on the array.
+func (d Client) GetSnapshotGroups(ctx context.Context) ([]SnapshotGroup, error) {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetSnapshotGroups",
+			"Type":   "Client",
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetSnapshotGroups")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetSnapshotGroups")
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, nil, "GET", "/snapshot-groups")
+	if err != nil {
+		return nil, fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, Error{
+			Code:    response.StatusCode,
+			Message: "failed to read snapshot groups",
+		}
+	}
+
+	groups := make([]SnapshotGroup, 0)
+	if err := json.Unmarshal(responseBody, &groups); err != nil {
+		return nil, fmt.Errorf("could not parse snapshot group data: %s. %v", string(responseBody), err)
+	}
+
+	Logc(ctx).WithField("Count", len(groups)).Debug("Read snapshot groups.")
+
+	return groups, nil
+}
+
+// GetSnapshotGroup returns the snapshot group whose label matches the specified name. Like GetVolume, this
+// method returns an empty structure if no such group exists, so callers should check the returned ref.
+func (d Client) GetSnapshotGroup(ctx context.Context, name string) (SnapshotGroup, error) {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetSnapshotGroup",
+			"Type":   "Client",
+			"name":   name,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetSnapshotGroup")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetSnapshotGroup")
+	}
+
+	groups, err := d.GetSnapshotGroups(ctx)
+	if err != nil {
+		return SnapshotGroup{}, err
+	}
+
+	for _, group := range groups {
+		if group.Label == name {
+			return group, nil
+		}
+	}
+
+	return SnapshotGroup{}, nil
+}
+
+// GetSnapshotGroupsForVolume returns the snapshot groups whose base is the specified volume.
+func (d Client) GetSnapshotGroupsForVolume(ctx context.Context, volume VolumeEx) ([]SnapshotGroup, error) {
+
+	groups, err := d.GetSnapshotGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeGroups := make([]SnapshotGroup, 0)
+	for _, group := range groups {
+		if group.BaseVolumeRef == volume.VolumeRef {
+			volumeGroups = append(volumeGroups, group)
+		}
+	}
+
+	return volumeGroups, nil
+}
+
+// CreateSnapshotGroup creates a snapshot group for the specified volume, with a repository allocated
+// from the volume's storage pool.
+func (d Client) CreateSnapshotGroup(ctx context.Context, name string, volume VolumeEx) (SnapshotGroup, error) {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "CreateSnapshotGroup",
+			"Type":   "Client",
+			"name":   name,
+			"volume": volume.Label,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> CreateSnapshotGroup")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< CreateSnapshotGroup")
+	}
+
+	if len(name) > maxNameLength {
+		return SnapshotGroup{}, fmt.Errorf("the snapshot group name %v exceeds the maximum length of %d characters",
+			name, maxNameLength)
+	}
+
+	request := SnapshotGroupCreateRequest{
+		BaseMappableObjectID: volume.VolumeRef,
+		Name:                 name,
+		RepositoryPercentage: snapshotRepositoryPercentage,
+		WarningThreshold:     snapshotRepositoryWarningThreshold,
+		AutoDeleteLimit:      snapshotGroupImageLimit,
+		FullPolicy:           "failbasewrites",
+		StoragePoolID:        volume.VolumeGroupRef,
+	}
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return SnapshotGroup{}, fmt.Errorf("could not marshal JSON request: %v; %v", request, err)
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, jsonRequest, "POST", "/snapshot-groups")
+	if err != nil {
+		return SnapshotGroup{}, fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		apiError := d.getErrorFromHTTPResponse(response, responseBody)
+		apiError.Message = fmt.Sprintf("could not create snapshot group %s; %s", name, apiError.Message)
+		return SnapshotGroup{}, apiError
+	}
+
+	group := SnapshotGroup{}
+	if err := json.Unmarshal(responseBody, &group); err != nil {
+		return SnapshotGroup{}, fmt.Errorf("could not parse API response: %s; %v", string(responseBody), err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"Name":             group.Label,
+		"SnapshotGroupRef": group.SnapshotGroupRef,
+		"VolumeRef":        group.BaseVolumeRef,
+	}).Debug("Created snapshot group.")
+
+	return group, nil
+}
+
+// DeleteSnapshotGroup deletes a snapshot group, including all of its images and its repository, from the array.
+func (d Client) DeleteSnapshotGroup(ctx context.Context, group SnapshotGroup) error {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "DeleteSnapshotGroup",
+			"Type":   "Client",
+			"name":   group.Label,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> DeleteSnapshotGroup")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< DeleteSnapshotGroup")
+	}
+
+	resourcePath := "/snapshot-groups/" + group.SnapshotGroupRef
+	response, responseBody, err := d.InvokeAPI(ctx, nil, "DELETE", resourcePath)
+	if err != nil {
+		return fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound, http.StatusGone:
+		break
+	default:
+		apiError := d.getErrorFromHTTPResponse(response, responseBody)
+		apiError.Message = fmt.Sprintf("could not delete snapshot group %s; %s", group.Label, apiError.Message)
+		return apiError
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"Name":             group.Label,
+		"SnapshotGroupRef": group.SnapshotGroupRef,
+	}).Debug("Deleted snapshot group.")
+
+	return nil
+}
+
+// GetSnapshotImages returns the point-in-time images in the specified snapshot group, oldest first.
+func (d Client) GetSnapshotImages(ctx context.Context, group SnapshotGroup) ([]SnapshotImage, error) {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetSnapshotImages",
+			"Type":   "Client",
+			"group":  group.Label,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetSnapshotImages")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetSnapshotImages")
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, nil, "GET", "/snapshot-images")
+	if err != nil {
+		return nil, fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, Error{
+			Code:    response.StatusCode,
+			Message: "failed to read snapshot images",
+		}
+	}
+
+	images := make([]SnapshotImage, 0)
+	if err := json.Unmarshal(responseBody, &images); err != nil {
+		return nil, fmt.Errorf("could not parse snapshot image data: %s. %v", string(responseBody), err)
+	}
+
+	groupImages := make([]SnapshotImage, 0)
+	for _, image := range images {
+		if image.SnapshotGroupRef == group.SnapshotGroupRef {
+			groupImages = append(groupImages, image)
+		}
+	}
+
+	sort.SliceStable(groupImages, func(i, j int) bool {
+		iSequence, _ := strconv.ParseUint(groupImages[i].SequenceNumber, 10, 64)
+		jSequence, _ := strconv.ParseUint(groupImages[j].SequenceNumber, 10, 64)
+		return iSequence < jSequence
+	})
+
+	return groupImages, nil
+}
+
+// CreateSnapshotImage creates a new point-in-time image in the specified snapshot group.
+func (d Client) CreateSnapshotImage(ctx context.Context, group SnapshotGroup) (SnapshotImage, error) {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "CreateSnapshotImage",
+			"Type":   "Client",
+			"group":  group.Label,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> CreateSnapshotImage")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< CreateSnapshotImage")
+	}
+
+	request := SnapshotImageCreateRequest{
+		SnapshotGroupID: group.SnapshotGroupRef,
+	}
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return SnapshotImage{}, fmt.Errorf("could not marshal JSON request: %v; %v", request, err)
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, jsonRequest, "POST", "/snapshot-images")
+	if err != nil {
+		return SnapshotImage{}, fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		apiError := d.getErrorFromHTTPResponse(response, responseBody)
+		apiError.Message = fmt.Sprintf("could not create snapshot image in group %s; %s", group.Label,
+			apiError.Message)
+		return SnapshotImage{}, apiError
+	}
+
+	image := SnapshotImage{}
+	if err := json.Unmarshal(responseBody, &image); err != nil {
+		return SnapshotImage{}, fmt.Errorf("could not parse API response: %s; %v", string(responseBody), err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"SnapshotGroup":    group.Label,
+		"SnapshotImageRef": image.SnapshotImageRef,
+		"Timestamp":        image.Timestamp,
+	}).Debug("Created snapshot image.")
+
+	return image, nil
+}
+
+// GetSnapshotVolume returns the snapshot volume whose label matches the specified name, or an empty
+// structure if no such snapshot volume exists.
+func (d Client) GetSnapshotVolume(ctx context.Context, name string) (SnapshotVolume, error) {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetSnapshotVolume",
+			"Type":   "Client",
+			"name":   name,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetSnapshotVolume")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetSnapshotVolume")
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, nil, "GET", "/snapshot-volumes")
+	if err != nil {
+		return SnapshotVolume{}, fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return SnapshotVolume{}, Error{
+			Code:    response.StatusCode,
+			Message: "failed to read snapshot volumes",
+		}
+	}
+
+	snapshotVolumes := make([]SnapshotVolume, 0)
+	if err := json.Unmarshal(responseBody, &snapshotVolumes); err != nil {
+		return SnapshotVolume{}, fmt.Errorf("could not parse snapshot volume data: %s. %v", string(responseBody),
+			err)
+	}
+
+	for _, snapshotVolume := range snapshotVolumes {
+		if snapshotVolume.Label == name {
+			return snapshotVolume, nil
+		}
+	}
+
+	return SnapshotVolume{}, nil
+}
+
+// CreateSnapshotVolume creates a read-only snapshot volume that exposes the contents of a snapshot image.
+func (d Client) CreateSnapshotVolume(ctx context.Context, name string, image SnapshotImage) (SnapshotVolume, error) {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "CreateSnapshotVolume",
+			"Type":   "Client",
+			"name":   name,
+			"image":  image.SnapshotImageRef,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> CreateSnapshotVolume")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< CreateSnapshotVolume")
+	}
+
+	if len(name) > maxNameLength {
+		return SnapshotVolume{}, fmt.Errorf("the snapshot volume name %v exceeds the maximum length of %d characters",
+			name, maxNameLength)
+	}
+
+	request := SnapshotVolumeCreateRequest{
+		SnapshotImageID: image.SnapshotImageRef,
+		Name:            name,
+		ViewMode:        "readOnly",
+	}
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return SnapshotVolume{}, fmt.Errorf("could not marshal JSON request: %v; %v", request, err)
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, jsonRequest, "POST", "/snapshot-volumes")
+	if err != nil {
+		return SnapshotVolume{}, fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		apiError := d.getErrorFromHTTPResponse(response, responseBody)
+		apiError.Message = fmt.Sprintf("could not create snapshot volume %s; %s", name, apiError.Message)
+		return SnapshotVolume{}, apiError
+	}
+
+	snapshotVolume := SnapshotVolume{}
+	if err := json.Unmarshal(responseBody, &snapshotVolume); err != nil {
+		return SnapshotVolume{}, fmt.Errorf("could not parse API response: %s; %v", string(responseBody), err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"Name":              snapshotVolume.Label,
+		"SnapshotVolumeRef": snapshotVolume.SnapshotVolumeRef,
+		"SnapshotImageRef":  snapshotVolume.SnapshotImageRef,
+	}).Debug("Created snapshot volume.")
+
+	return snapshotVolume, nil
+}
+
+// DeleteSnapshotVolume deletes a snapshot volume from the array.
+func (d Client) DeleteSnapshotVolume(ctx context.Context, snapshotVolume SnapshotVolume) error {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "DeleteSnapshotVolume",
+			"Type":   "Client",
+			"name":   snapshotVolume.Label,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> DeleteSnapshotVolume")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< DeleteSnapshotVolume")
+	}
+
+	resourcePath := "/snapshot-volumes/" + snapshotVolume.SnapshotVolumeRef
+	response, responseBody, err := d.InvokeAPI(ctx, nil, "DELETE", resourcePath)
+	if err != nil {
+		return fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound, http.StatusGone:
+		break
+	default:
+		apiError := d.getErrorFromHTTPResponse(response, responseBody)
+		apiError.Message = fmt.Sprintf("could not delete snapshot volume %s; %s", snapshotVolume.Label,
+			apiError.Message)
+		return apiError
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"Name":              snapshotVolume.Label,
+		"SnapshotVolumeRef": snapshotVolume.SnapshotVolumeRef,
+	}).Debug("Deleted snapshot volume.")
+
+	return nil
+}
+
+// GetVolumeCopyJobForTarget returns the volume copy job whose target is the specified volume, or an
+// empty structure if no such job exists.
+func (d Client) GetVolumeCopyJobForTarget(ctx context.Context, target VolumeEx) (VolumeCopyJob, error) {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetVolumeCopyJobForTarget",
+			"Type":   "Client",
+			"target": target.Label,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetVolumeCopyJobForTarget")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetVolumeCopyJobForTarget")
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, nil, "GET", "/volume-copy-jobs")
+	if err != nil {
+		return VolumeCopyJob{}, fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return VolumeCopyJob{}, Error{
+			Code:    response.StatusCode,
+			Message: "failed to read volume copy jobs",
+		}
+	}
+
+	jobs := make([]VolumeCopyJob, 0)
+	if err := json.Unmarshal(responseBody, &jobs); err != nil {
+		return VolumeCopyJob{}, fmt.Errorf("could not parse volume copy job data: %s. %v", string(responseBody), err)
+	}
+
+	for _, job := range jobs {
+		if job.TargetVolumeRef == target.VolumeRef {
+			return job, nil
+		}
+	}
+
+	return VolumeCopyJob{}, nil
+}
+
+// CreateVolumeCopyJob creates and starts a job that copies the contents of the source object, which may be
+// a volume or a snapshot volume, to the target volume.
+func (d Client) CreateVolumeCopyJob(ctx context.Context, sourceRef string, target VolumeEx) (VolumeCopyJob, error) {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":    "CreateVolumeCopyJob",
+			"Type":      "Client",
+			"sourceRef": sourceRef,
+			"target":    target.Label,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> CreateVolumeCopyJob")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< CreateVolumeCopyJob")
+	}
+
+	request := VolumeCopyJobCreateRequest{
+		SourceID:             sourceRef,
+		TargetID:             target.VolumeRef,
+		CopyPriority:         "priority2",
+		TargetWriteProtected: false,
+		OnlineCopy:           false,
+	}
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return VolumeCopyJob{}, fmt.Errorf("could not marshal JSON request: %v; %v", request, err)
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, jsonRequest, "POST", "/volume-copy-jobs")
+	if err != nil {
+		return VolumeCopyJob{}, fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		apiError := d.getErrorFromHTTPResponse(response, responseBody)
+		apiError.Message = fmt.Sprintf("could not create copy job for volume %s; %s", target.Label, apiError.Message)
+		return VolumeCopyJob{}, apiError
+	}
+
+	job := VolumeCopyJob{}
+	if err := json.Unmarshal(responseBody, &job); err != nil {
+		return VolumeCopyJob{}, fmt.Errorf("could not parse API response: %s; %v", string(responseBody), err)
+	}
+
+	// Copy jobs are created in a stopped state
+	resourcePath := "/volume-copy-jobs-control/" + job.VolumeCopyRef + "?control=start"
+	response, responseBody, err = d.InvokeAPI(ctx, nil, "POST", resourcePath)
+	if err != nil {
+		return job, fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		break
+	default:
+		apiError := d.getErrorFromHTTPResponse(response, responseBody)
+		apiError.Message = fmt.Sprintf("could not start copy job for volume %s; %s", target.Label, apiError.Message)
+		return job, apiError
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"VolumeCopyRef": job.VolumeCopyRef,
+		"SourceRef":     sourceRef,
+		"Target":        target.Label,
+	}).Debug("Started volume copy job.")
+
+	return job, nil
+}
+
+// DeleteVolumeCopyJob removes a volume copy job from the array. This does not affect the source or target volumes.
+func (d Client) DeleteVolumeCopyJob(ctx context.Context, job VolumeCopyJob) error {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":        "DeleteVolumeCopyJob",
+			"Type":          "Client",
+			"volumeCopyRef": job.VolumeCopyRef,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> DeleteVolumeCopyJob")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< DeleteVolumeCopyJob")
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, nil, "DELETE", "/volume-copy-jobs/"+job.VolumeCopyRef)
+	if err != nil {
+		return fmt.Errorf("API invocation failed. %v", err)
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound, http.StatusGone:
+		break
+	default:
+		apiError := d.getErrorFromHTTPResponse(response, responseBody)
+		apiError.Message = fmt.Sprintf("could not delete volume copy job %s; %s", job.VolumeCopyRef,
+			apiError.Message)
+		return apiError
+	}
+
+	return nil
+}
+
 // EnsureHostForIQN handles automatic E-series Host and Host Group creation. Given the IQN of a host, this method
 // verifies whether a Host is already configured on the array. If so, the Host info is returned and no further action is
 // taken. If not, this method chooses a unique name for the Host and creates it on the array. Once the Host is created,
@@ -1767,6 +2371,28 @@ func (d Client) volumeIsMappedToHost(ctx context.Context, volume VolumeEx, host
 	return false, LUNMapping{}
 }
 
+// UnmapVolumeFromHost removes the mapping of a volume to the specified host (or containing host group). If the volume
+// is not mapped, or is mapped to a different host or group, no action is taken.
+func (d Client) UnmapVolumeFromHost(ctx context.Context, volume VolumeEx, host HostEx) error {
+
+	if d.config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":     "UnmapVolumeFromHost",
+			"Type":       "Client",
+			"volumeName": volume.Label,
+			"hostName":   host.Label,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> UnmapVolumeFromHost")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< UnmapVolumeFromHost")
+	}
+
+	if mappedToHost, _ := d.volumeIsMappedToHost(ctx, volume, host); !mappedToHost {
+		return nil
+	}
+
+	return d.UnmapVolume(ctx, volume)
+}
+
 // UnmapVolume removes a mapping from the specified volume. If no map exists, no action is taken.
 func (d Client) UnmapVolume(ctx context.Context, volume VolumeEx) error {
 
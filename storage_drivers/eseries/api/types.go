@@ -132,12 +132,13 @@ type StorageSystem struct {
 }
 
 type VolumeGroupEx struct {
-	IsOffline      bool   `json:"offline"`
-	WorldWideName  string `json:"worldWideName"`
-	VolumeGroupRef string `json:"volumeGroupRef"`
-	Label          string `json:"label"`
-	FreeSpace      string `json:"freeSpace"`      // Documentation says this is an int but really it is a string!
-	DriveMediaType string `json:"driveMediaType"` // 'hdd', 'ssd'
+	IsOffline        bool   `json:"offline"`
+	WorldWideName    string `json:"worldWideName"`
+	VolumeGroupRef   string `json:"volumeGroupRef"`
+	Label            string `json:"label"`
+	FreeSpace        string `json:"freeSpace"`        // Documentation says this is an int but really it is a string!
+	TotalRaidedSpace string `json:"totalRaidedSpace"` // Also a string
+	DriveMediaType   string `json:"driveMediaType"`   // 'hdd', 'ssd'
 }
 
 // Functions to allow sorting storage pools by free space
@@ -305,3 +306,74 @@ type IscsiTargetSettings struct {
 		TCPListenPort int `json:"tcpListenPort"`
 	} `json:"portals"`
 }
+
+// SnapshotGroup is a sequence of point-in-time images of a single base volume that share a repository
+type SnapshotGroup struct {
+	SnapshotGroupRef   string `json:"pitGroupRef"`
+	Label              string `json:"label"`
+	BaseVolumeRef      string `json:"baseVolume"`
+	RepositoryRef      string `json:"repositoryVolume"`
+	RepositoryCapacity string `json:"repositoryCapacity"`
+	Status             string `json:"status"` // 'optimal', 'failed', 'overThreshold', ...
+	SnapshotCount      int    `json:"snapshotCount"`
+}
+
+type SnapshotGroupCreateRequest struct {
+	BaseMappableObjectID string `json:"baseMappableObjectId"`
+	Name                 string `json:"name"`
+	RepositoryPercentage int    `json:"repositoryPercentage"`
+	WarningThreshold     int    `json:"warningThreshold"`
+	AutoDeleteLimit      int    `json:"autoDeleteLimit"`
+	FullPolicy           string `json:"fullPolicy"` // 'failbasewrites', 'purgepit'
+	StoragePoolID        string `json:"storagePoolId,omitempty"`
+}
+
+// SnapshotImage is a single point-in-time image within a snapshot group
+type SnapshotImage struct {
+	SnapshotImageRef string `json:"pitRef"`
+	SnapshotGroupRef string `json:"pitGroupRef"`
+	BaseVolumeRef    string `json:"baseVol"`
+	Timestamp        string `json:"pitTimestamp"` // Seconds since the epoch, as a string
+	SequenceNumber   string `json:"pitSequenceNumber"`
+	Status           string `json:"status"` // 'optimal', 'failed', ...
+}
+
+type SnapshotImageCreateRequest struct {
+	SnapshotGroupID string `json:"groupId"`
+}
+
+// SnapshotVolume is a host-accessible view of a snapshot image
+type SnapshotVolume struct {
+	SnapshotVolumeRef string `json:"id"`
+	Label             string `json:"label"`
+	BaseVolumeRef     string `json:"baseVol"`
+	SnapshotImageRef  string `json:"basePIT"`
+	AccessMode        string `json:"accessMode"` // 'readOnly', 'readWrite'
+	Status            string `json:"status"`
+}
+
+type SnapshotVolumeCreateRequest struct {
+	SnapshotImageID      string `json:"snapshotImageId"`
+	Name                 string `json:"name"`
+	ViewMode             string `json:"viewMode"` // 'readOnly', 'readWrite'
+	FullThreshold        int    `json:"fullThreshold,omitempty"`
+	RepositoryPercentage int    `json:"repositoryPercentage,omitempty"`
+	RepositoryPoolID     string `json:"repositoryPoolId,omitempty"`
+}
+
+// VolumeCopyJob copies the contents of a source volume to a target volume in the background
+type VolumeCopyJob struct {
+	VolumeCopyRef   string `json:"volcopyRef"`
+	SourceVolumeRef string `json:"sourceVolume"`
+	TargetVolumeRef string `json:"targetVolume"`
+	Status          string `json:"status"` // 'inProgress', 'complete', 'halted', 'failed', 'pending', ...
+	PercentComplete int    `json:"percentComplete"`
+}
+
+type VolumeCopyJobCreateRequest struct {
+	SourceID             string `json:"sourceId"`
+	TargetID             string `json:"targetId"`
+	CopyPriority         string `json:"copyPriority"` // 'priority0' (lowest) through 'priority4' (highest)
+	TargetWriteProtected bool   `json:"targetWriteProtected"`
+	OnlineCopy           bool   `json:"onlineCopy"`
+}
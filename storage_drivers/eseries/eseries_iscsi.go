@@ -9,13 +9,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/RoaringBitmap/roaring"
+	"github.com/cenkalti/backoff/v4"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 
@@ -40,6 +41,23 @@ const (
 	Media  = "media"
 )
 
+const (
+	maxNameLength = 30
+
+	// Names of the E-series objects that hold snapshots and temporary clone artifacts
+	csiSnapshotPrefix   = "snapshot-"
+	snapshotGroupPrefix = "snap_"
+	cloneGroupPrefix    = "clone_g_"
+	cloneViewPrefix     = "clone_v_"
+
+	// States of volume copy jobs
+	volumeCopyStatusComplete = "complete"
+	volumeCopyStatusFailed   = "failed"
+	volumeCopyStatusHalted   = "halted"
+
+	cloneCopyWaitTime = 30 * time.Second
+)
+
 // SANStorageDriver is for storage provisioning via the Web Services Proxy RESTful interface that communicates
 // with E-Series controllers via the SYMbol API.
 type SANStorageDriver struct {
@@ -326,8 +344,8 @@ func (d *SANStorageDriver) initializeStoragePools(ctx context.Context) error {
 			mediaOffers = append(mediaOffers, mediaOffer)
 		}
 
-		pool.Attributes[sa.Snapshots] = sa.NewBoolOffer(false)
-		pool.Attributes[sa.Clones] = sa.NewBoolOffer(false)
+		pool.Attributes[sa.Snapshots] = sa.NewBoolOffer(true)
+		pool.Attributes[sa.Clones] = sa.NewBoolOffer(true)
 		pool.Attributes[sa.Encryption] = sa.NewBoolOffer(false)
 		pool.Attributes[sa.ProvisioningType] = sa.NewStringOffer(sa.Thick)
 		pool.Attributes[sa.Labels] = sa.NewLabelOffer(d.Config.Labels)
@@ -367,8 +385,8 @@ func (d *SANStorageDriver) initializeStoragePools(ctx context.Context) error {
 		pool := storage.NewStoragePool(nil, d.poolName(fmt.Sprintf("pool_%d", index)))
 
 		pool.Attributes[sa.BackendType] = sa.NewStringOffer(d.Name())
-		pool.Attributes[sa.Snapshots] = sa.NewBoolOffer(false)
-		pool.Attributes[sa.Clones] = sa.NewBoolOffer(false)
+		pool.Attributes[sa.Snapshots] = sa.NewBoolOffer(true)
+		pool.Attributes[sa.Clones] = sa.NewBoolOffer(true)
 		pool.Attributes[sa.Encryption] = sa.NewBoolOffer(false)
 		pool.Attributes[sa.ProvisioningType] = sa.NewStringOffer(sa.Thick)
 		pool.Attributes[sa.Labels] = sa.NewLabelOffer(d.Config.Labels, vpool.Labels)
@@ -586,34 +604,18 @@ func (d *SANStorageDriver) getPoolsForCreate(
 		return nil, fmt.Errorf("could not find pool %s", storagePool.Name)
 	}
 
-	// Make a storage class from the volume attributes to simplify pool matching
-	attributesCopy := make(map[string]sa.Request)
-	for k, v := range volAttributes {
-		attributesCopy[k] = v
-	}
-	delete(attributesCopy, sa.Selector)
-	storageClass := sc.NewFromAttributes(attributesCopy)
-
 	// Find matching pools
-	candidatePools := make([]*storage.Pool, 0)
-
-	for _, pool := range d.physicalPools {
-		if storageClass.Matches(ctx, pool) {
-			candidatePools = append(candidatePools, pool)
-		}
-	}
+	candidatePools := sc.FilterPoolsOnAttributes(ctx, d.physicalPools, volAttributes)
 
 	if len(candidatePools) == 0 {
 		err := errors.New("backend has no physical pools that can satisfy request")
 		return nil, drivers.NewBackendIneligibleError(volConfig.InternalName, []error{err}, []string{})
 	}
 
-	// Shuffle physical pools
-	rand.Shuffle(len(candidatePools), func(i, j int) {
-		candidatePools[i], candidatePools[j] = candidatePools[j], candidatePools[i]
-	})
-
-	return candidatePools, nil
+	return storage.OrderPhysicalPoolsForCreate(ctx, volConfig, candidatePools,
+		func() (map[string]*storage.PoolCapacity, error) {
+			return d.GetCapacity(ctx, storagePool, volAttributes)
+		}), nil
 }
 
 // Destroy is called by Docker to delete a container volume.
@@ -782,14 +784,28 @@ func (d *SANStorageDriver) Publish(
 			return fmt.Errorf("found for IQN %s, but it is in host group %s: %v", iqn, d.Config.AccessGroup, err)
 		}
 
-		// Map the volume directly to the Host Group
-		mapHost := api.HostEx{
-			HostRef:    api.NullRef,
-			ClusterRef: hostGroup.ClusterRef,
+		// E-series only supports a single mapping per volume, so volumes that may be attached to
+		// multiple nodes are mapped to the Host Group.  All others are mapped directly to the host,
+		// unless already mapped to its Host Group by an earlier version of Trident.
+		mapHost := host
+		switch volConfig.AccessMode {
+		case tridentconfig.ReadWriteMany, tridentconfig.ReadOnlyMany:
+			mapHost = api.HostEx{
+				HostRef:    api.NullRef,
+				ClusterRef: hostGroup.ClusterRef,
+			}
+		default:
+			if !vol.IsMapped {
+				mapHost = api.HostEx{
+					HostRef:    host.HostRef,
+					ClusterRef: api.NullRef,
+					Label:      host.Label,
+				}
+			}
 		}
 		mapping, err = d.API.MapVolume(ctx, vol, mapHost)
 		if err != nil {
-			return fmt.Errorf("could not map volume %s to Host Group %s: %v", name, hostGroup.Label, err)
+			return fmt.Errorf("could not map volume %s to host %s: %v", name, host.Label, err)
 		}
 	}
 
@@ -804,6 +820,68 @@ func (d *SANStorageDriver) Publish(
 	return nil
 }
 
+// Unpublish the volume from the host specified in publishInfo.  This method may or may not be running on the host
+// where the volume was mounted, so it should limit itself to updating access rules, initiator groups, etc.
+// that require some host identity (but not locality) as well as storage controller API access.
+func (d *SANStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	name := volConfig.InternalName
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+			"node":   publishInfo.HostName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	if d.Config.DriverContext != tridentconfig.ContextCSI {
+		return nil
+	}
+
+	// Get the volume
+	vol, err := d.API.GetVolume(ctx, name)
+	if err != nil {
+		return fmt.Errorf("could not find volume %s: %v", name, err)
+	}
+	if !d.API.IsRefValid(vol.VolumeRef) {
+		Logc(ctx).WithField("volume", name).Warn("Volume not found, nothing to unpublish.")
+		return nil
+	}
+
+	if len(publishInfo.HostIQN) == 0 || publishInfo.HostIQN[0] == "" {
+		Logc(ctx).WithField("node", publishInfo.HostName).Warn("Host initiator IQN unknown, nothing to unpublish.")
+		return nil
+	}
+	iqn := publishInfo.HostIQN[0]
+
+	host, err := d.API.GetHostForIQN(ctx, iqn)
+	if err != nil {
+		return fmt.Errorf("could not get host for IQN %s: %v", iqn, err)
+	}
+	if host.HostRef == "" {
+		Logc(ctx).WithField("IQN", iqn).Debug("No host found for IQN, nothing to unpublish.")
+		return nil
+	}
+
+	// Volumes that may be attached to multiple nodes stay mapped to the Host Group
+	switch volConfig.AccessMode {
+	case tridentconfig.ReadWriteMany, tridentconfig.ReadOnlyMany:
+		host.ClusterRef = api.NullRef
+	}
+
+	if err = d.API.UnmapVolumeFromHost(ctx, vol, host); err != nil {
+		return fmt.Errorf("could not unmap volume %s from host %s: %v", name, host.Label, err)
+	}
+
+	return nil
+}
+
 func (d *SANStorageDriver) getISCSITargetInfo(
 	ctx context.Context,
 ) (iSCSINodeName string, iSCSIInterfaces []string, returnError error) {
@@ -882,7 +960,87 @@ func (d *SANStorageDriver) MapVolumeToLocalHost(ctx context.Context, volume api.
 
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
 func (d *SANStorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig) error {
-	return utils.UnsupportedError(fmt.Sprintf("snapshots are not supported by backend type %s", d.Name()))
+	return nil
+}
+
+// getSnapshotGroupName returns the name of the snapshot group that holds the named snapshot. Each Trident
+// snapshot is stored as the only image in its own snapshot group, since E-series snapshot images cannot be
+// named. Snapshot names from the CSI frontend are too long for the array, so they are shortened by converting
+// their UUIDs to Base64, the same way volume names are shortened.
+func (d *SANStorageDriver) getSnapshotGroupName(snapName string) (string, error) {
+
+	if strings.HasPrefix(snapName, csiSnapshotPrefix) {
+		if snapUUID, err := uuid.Parse(strings.TrimPrefix(snapName, csiSnapshotPrefix)); err == nil {
+			if b64string, err := d.uuidToBase64(snapUUID.String()); err == nil {
+				return snapshotGroupPrefix + b64string, nil
+			}
+		}
+	}
+
+	if len(snapName) > maxNameLength {
+		return "", fmt.Errorf("the snapshot name %s exceeds the maximum length of %d characters", snapName,
+			maxNameLength)
+	}
+
+	return snapName, nil
+}
+
+// getSnapshotNameFromGroupName reverses the name mapping done by getSnapshotGroupName.
+func (d *SANStorageDriver) getSnapshotNameFromGroupName(groupName string) string {
+
+	b64string := strings.TrimPrefix(groupName, snapshotGroupPrefix)
+	if b64string != groupName && len(b64string) == base64.RawURLEncoding.EncodedLen(16) {
+		if snapUUID, err := d.base64ToUUID(b64string); err == nil {
+			return csiSnapshotPrefix + snapUUID
+		}
+	}
+
+	return groupName
+}
+
+// getCloneObjectName returns the name of a temporary object used while cloning the named volume.
+func (d *SANStorageDriver) getCloneObjectName(prefix, name string) string {
+	cloneObjectName := prefix + name
+	if len(cloneObjectName) > maxNameLength {
+		return cloneObjectName[0:maxNameLength]
+	}
+	return cloneObjectName
+}
+
+// getSnapshotImage returns the newest image in a snapshot group, or nil if the group has no images.
+func (d *SANStorageDriver) getSnapshotImage(
+	ctx context.Context, group api.SnapshotGroup,
+) (*api.SnapshotImage, error) {
+
+	images, err := d.API.GetSnapshotImages(ctx, group)
+	if err != nil {
+		return nil, fmt.Errorf("could not read images in snapshot group %s; %v", group.Label, err)
+	}
+	if len(images) == 0 {
+		return nil, nil
+	}
+
+	return &images[len(images)-1], nil
+}
+
+// makeSnapshot returns a Trident snapshot representing a snapshot image of the supplied volume.
+func (d *SANStorageDriver) makeSnapshot(
+	snapConfig *storage.SnapshotConfig, image *api.SnapshotImage, volume api.VolumeEx,
+) *storage.Snapshot {
+
+	created := ""
+	if timestamp, err := strconv.ParseInt(image.Timestamp, 10, 64); err == nil {
+		created = time.Unix(timestamp, 0).UTC().Format(storage.SnapshotTimestampFormat)
+	}
+
+	sizeBytes, _ := strconv.ParseInt(volume.VolumeSize, 10, 64)
+
+	return &storage.Snapshot{
+		Config:    snapConfig,
+		Created:   created,
+		SizeBytes: sizeBytes,
+		State:     storage.SnapshotStateOnline,
+	}
 }
 
 // GetSnapshot returns a snapshot of a volume, or an error if it does not exist.
@@ -901,11 +1059,41 @@ func (d *SANStorageDriver) GetSnapshot(ctx context.Context, snapConfig *storage.
 		defer Logc(ctx).WithFields(fields).Debug("<<<< GetSnapshot")
 	}
 
-	return nil, utils.UnsupportedError(fmt.Sprintf("snapshots are not supported by backend type %s", d.Name()))
+	groupName, err := d.getSnapshotGroupName(snapConfig.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := d.API.GetSnapshotGroup(ctx, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("could not check for snapshot %s; %v", snapConfig.Name, err)
+	}
+	if !d.API.IsRefValid(group.SnapshotGroupRef) {
+		Logc(ctx).WithField("snapshotName", snapConfig.Name).Debug("Snapshot group not found.")
+		return nil, nil
+	}
+
+	// A group without an image is left over from an interrupted snapshot create
+	image, err := d.getSnapshotImage(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	if image == nil {
+		Logc(ctx).WithField("snapshotName", snapConfig.Name).Debug("Snapshot group has no images.")
+		return nil, nil
+	}
+
+	volume, err := d.API.GetVolumeByRef(ctx, group.BaseVolumeRef)
+	if err != nil {
+		return nil, fmt.Errorf("could not read volume for snapshot %s; %v", snapConfig.Name, err)
+	}
+
+	snapConfig.InternalName = groupName
+
+	return d.makeSnapshot(snapConfig, image, volume), nil
 }
 
-// SnapshotList returns the list of snapshots associated with the specified volume. The E-series volume
-// plugin does not support snapshots, so this method always returns an empty array.
+// GetSnapshots returns the list of snapshots associated with the specified volume.
 func (d *SANStorageDriver) GetSnapshots(ctx context.Context, volConfig *storage.VolumeConfig) (
 	[]*storage.Snapshot, error,
 ) {
@@ -920,11 +1108,49 @@ func (d *SANStorageDriver) GetSnapshots(ctx context.Context, volConfig *storage.
 		defer Logc(ctx).WithFields(fields).Debug("<<<< GetSnapshots")
 	}
 
-	return make([]*storage.Snapshot, 0), nil
+	volume, err := d.getVolume(ctx, volConfig.InternalName)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := d.API.GetSnapshotGroupsForVolume(ctx, volume)
+	if err != nil {
+		return nil, fmt.Errorf("could not read snapshot groups for volume %s; %v", volConfig.InternalName, err)
+	}
+
+	snapshots := make([]*storage.Snapshot, 0)
+
+	for _, group := range groups {
+
+		// Skip the groups created temporarily while cloning
+		if strings.HasPrefix(group.Label, cloneGroupPrefix) {
+			continue
+		}
+
+		image, err := d.getSnapshotImage(ctx, group)
+		if err != nil {
+			return nil, err
+		}
+		if image == nil {
+			continue
+		}
+
+		snapConfig := &storage.SnapshotConfig{
+			Version:            tridentconfig.OrchestratorAPIVersion,
+			Name:               d.getSnapshotNameFromGroupName(group.Label),
+			InternalName:       group.Label,
+			VolumeName:         volConfig.Name,
+			VolumeInternalName: volConfig.InternalName,
+		}
+
+		snapshots = append(snapshots, d.makeSnapshot(snapConfig, image, volume))
+	}
+
+	return snapshots, nil
 }
 
-// CreateSnapshot creates a snapshot for the given volume. The E-series volume plugin
-// does not support cloning or snapshots, so this method always returns an error.
+// CreateSnapshot creates a snapshot for the given volume. Each snapshot is an image in a dedicated
+// snapshot group, whose repository is allocated from the storage pool of the volume.
 func (d *SANStorageDriver) CreateSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) (
 	*storage.Snapshot, error,
 ) {
@@ -940,7 +1166,43 @@ func (d *SANStorageDriver) CreateSnapshot(ctx context.Context, snapConfig *stora
 		defer Logc(ctx).WithFields(fields).Debug("<<<< CreateSnapshot")
 	}
 
-	return nil, utils.UnsupportedError(fmt.Sprintf("snapshots are not supported by backend type %s", d.Name()))
+	groupName, err := d.getSnapshotGroupName(snapConfig.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	volume, err := d.getVolume(ctx, snapConfig.VolumeInternalName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reuse a group left over from an interrupted snapshot create
+	group, err := d.API.GetSnapshotGroup(ctx, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("could not check for snapshot group %s; %v", groupName, err)
+	}
+	if !d.API.IsRefValid(group.SnapshotGroupRef) {
+		if group, err = d.API.CreateSnapshotGroup(ctx, groupName, volume); err != nil {
+			return nil, fmt.Errorf("could not create snapshot group for volume %s; %v", volume.Label, err)
+		}
+	} else if group.BaseVolumeRef != volume.VolumeRef {
+		return nil, fmt.Errorf("snapshot group %s belongs to another volume", groupName)
+	}
+
+	image, err := d.API.CreateSnapshotImage(ctx, group)
+	if err != nil {
+		return nil, fmt.Errorf("could not create snapshot of volume %s; %v", volume.Label, err)
+	}
+
+	snapConfig.InternalName = groupName
+
+	Logc(ctx).WithFields(log.Fields{
+		"snapshotName":  snapConfig.Name,
+		"snapshotGroup": groupName,
+		"volumeName":    volume.Label,
+	}).Info("Snapshot created.")
+
+	return d.makeSnapshot(snapConfig, &image, volume), nil
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
@@ -957,7 +1219,7 @@ func (d *SANStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig *stor
 		defer Logc(ctx).WithFields(fields).Debug("<<<< RestoreSnapshot")
 	}
 
-	return utils.UnsupportedError(fmt.Sprintf("snapshots are not supported by backend type %s", d.Name()))
+	return utils.UnsupportedError(fmt.Sprintf("snapshot restore is not supported by backend type %s", d.Name()))
 }
 
 // DeleteSnapshot deletes a volume snapshot.
@@ -974,11 +1236,33 @@ func (d *SANStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *stora
 		defer Logc(ctx).WithFields(fields).Debug("<<<< DeleteSnapshot")
 	}
 
-	return utils.UnsupportedError(fmt.Sprintf("snapshots are not supported by backend type %s", d.Name()))
+	groupName, err := d.getSnapshotGroupName(snapConfig.Name)
+	if err != nil {
+		return err
+	}
+
+	group, err := d.API.GetSnapshotGroup(ctx, groupName)
+	if err != nil {
+		return fmt.Errorf("could not check for snapshot %s; %v", snapConfig.Name, err)
+	}
+	if !d.API.IsRefValid(group.SnapshotGroupRef) {
+		// If the snapshot was deleted on this storage for any reason, don't fail it here.
+		Logc(ctx).WithField("snapshotName", snapConfig.Name).Warn(
+			"Could not find snapshot on array. Allowing deletion to proceed.")
+		return nil
+	}
+
+	// Deleting the group also deletes its image and repository
+	if err = d.API.DeleteSnapshotGroup(ctx, group); err != nil {
+		return fmt.Errorf("could not delete snapshot %s; %v", snapConfig.Name, err)
+	}
+
+	return nil
 }
 
-// CreateClone creates a new volume from the named volume, either by direct clone or from the named snapshot. The E-series volume plugin
-// does not support cloning or snapshots, so this method always returns an error.
+// CreateClone creates a new volume from the named volume, either from the named snapshot or from a new snapshot
+// taken for the purpose. E-series cannot create linked clones, so the snapshot image is exposed as a read-only
+// snapshot volume and copied to a new volume by a volume copy job, which may take some time to complete.
 func (d *SANStorageDriver) CreateClone(
 	ctx context.Context, volConfig *storage.VolumeConfig, _ *storage.Pool,
 ) error {
@@ -999,15 +1283,296 @@ func (d *SANStorageDriver) CreateClone(
 		defer Logc(ctx).WithFields(fields).Debug("<<<< CreateClone")
 	}
 
-	return fmt.Errorf("cloning is not supported by backend type %s", d.Name())
+	// If the clone already exists, this is a retry, so check on the copy
+	extantVolume, err := d.API.GetVolume(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing volume: %v", err)
+	}
+	if d.API.IsRefValid(extantVolume.VolumeRef) {
+		return d.waitForCloneCopy(ctx, extantVolume)
+	}
+
+	sourceVolume, err := d.getVolume(ctx, source)
+	if err != nil {
+		return fmt.Errorf("could not find source volume; %v", err)
+	}
+
+	// A volume copy needs a target at least as large as its source, so a clone may be larger than
+	// its source volume but not smaller.
+	sourceSizeBytes, err := strconv.ParseUint(sourceVolume.VolumeSize, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%v is an invalid volume size: %v", sourceVolume.VolumeSize, err)
+	}
+	sizeBytes := sourceSizeBytes
+	if volConfig.Size != "" {
+		requestedSize, err := utils.ConvertSizeToBytes(volConfig.Size)
+		if err != nil {
+			return fmt.Errorf("could not convert volume size %s: %v", volConfig.Size, err)
+		}
+		if sizeBytes, err = strconv.ParseUint(requestedSize, 10, 64); err != nil {
+			return fmt.Errorf("%v is an invalid volume size: %v", volConfig.Size, err)
+		}
+		if sizeBytes == 0 {
+			sizeBytes = sourceSizeBytes
+		}
+	}
+	if sizeBytes < sourceSizeBytes {
+		return fmt.Errorf("requested clone size (%d bytes) is smaller than the size of source volume %s "+
+			"(%d bytes)", sizeBytes, source, sourceSizeBytes)
+	}
+	if _, _, checkVolumeSizeLimitsError := drivers.CheckVolumeSizeLimits(
+		ctx, sizeBytes, d.Config.CommonStorageDriverConfig); checkVolumeSizeLimitsError != nil {
+		return checkVolumeSizeLimitsError
+	}
+
+	// Find the snapshot image to copy
+	var image *api.SnapshotImage
+	if snapshot != "" {
+		groupName, err := d.getSnapshotGroupName(snapshot)
+		if err != nil {
+			return err
+		}
+		group, err := d.API.GetSnapshotGroup(ctx, groupName)
+		if err != nil {
+			return fmt.Errorf("could not check for snapshot %s; %v", snapshot, err)
+		}
+		if !d.API.IsRefValid(group.SnapshotGroupRef) || group.BaseVolumeRef != sourceVolume.VolumeRef {
+			return utils.NotFoundError(fmt.Sprintf("snapshot %s of volume %s not found", snapshot, source))
+		}
+		if image, err = d.getSnapshotImage(ctx, group); err != nil {
+			return err
+		} else if image == nil {
+			return utils.NotFoundError(fmt.Sprintf("snapshot %s of volume %s not found", snapshot, source))
+		}
+	} else {
+		if image, err = d.createCloneSnapshotImage(ctx, name, sourceVolume); err != nil {
+			return err
+		}
+	}
+
+	// Expose the snapshot image as a snapshot volume so it may be the source of a copy
+	viewName := d.getCloneObjectName(cloneViewPrefix, name)
+	view, err := d.API.GetSnapshotVolume(ctx, viewName)
+	if err != nil {
+		return fmt.Errorf("could not check for snapshot volume %s; %v", viewName, err)
+	}
+	if !d.API.IsRefValid(view.SnapshotVolumeRef) {
+		if view, err = d.API.CreateSnapshotVolume(ctx, viewName, *image); err != nil {
+			return fmt.Errorf("could not create snapshot volume for clone %s; %v", name, err)
+		}
+	}
+
+	// Create the clone in the same storage pool as the source volume
+	fstype := volConfig.FileSystem
+	if fstype == "" {
+		fstype = drivers.DefaultFileSystemType
+	}
+
+	vol, err := d.API.CreateVolume(ctx, name, sourceVolume.VolumeGroupRef, sizeBytes, "", fstype)
+	if err != nil {
+		return fmt.Errorf("could not create clone %s; %v", name, err)
+	}
+
+	volConfig.Size = strconv.FormatUint(sizeBytes, 10)
+
+	if _, err = d.API.CreateVolumeCopyJob(ctx, view.SnapshotVolumeRef, vol); err != nil {
+		return fmt.Errorf("could not copy volume %s to clone %s; %v", source, name, err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"Name":      name,
+		"Source":    source,
+		"Snapshot":  snapshot,
+		"VolumeRef": vol.VolumeRef,
+	}).Debug("Clone created, waiting for copy to complete.")
+
+	return d.waitForCloneCopy(ctx, vol)
+}
+
+// createCloneSnapshotImage creates a temporary snapshot of a volume that is being cloned without
+// specifying a snapshot.
+func (d *SANStorageDriver) createCloneSnapshotImage(
+	ctx context.Context, name string, sourceVolume api.VolumeEx,
+) (*api.SnapshotImage, error) {
+
+	groupName := d.getCloneObjectName(cloneGroupPrefix, name)
+
+	group, err := d.API.GetSnapshotGroup(ctx, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("could not check for snapshot group %s; %v", groupName, err)
+	}
+	if !d.API.IsRefValid(group.SnapshotGroupRef) {
+		if group, err = d.API.CreateSnapshotGroup(ctx, groupName, sourceVolume); err != nil {
+			return nil, fmt.Errorf("could not create snapshot group for clone %s; %v", name, err)
+		}
+	}
+
+	image, err := d.getSnapshotImage(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	if image == nil {
+		newImage, err := d.API.CreateSnapshotImage(ctx, group)
+		if err != nil {
+			return nil, fmt.Errorf("could not create snapshot for clone %s; %v", name, err)
+		}
+		image = &newImage
+	}
+
+	return image, nil
+}
+
+// waitForCloneCopy waits briefly for the copy job that populates a clone to finish, returning a
+// VolumeCreatingError if it is still running so that the clone operation is retried later. Once the
+// copy completes, the temporary objects used for cloning are removed.
+func (d *SANStorageDriver) waitForCloneCopy(ctx context.Context, volume api.VolumeEx) error {
+
+	name := volume.Label
+	viewName := d.getCloneObjectName(cloneViewPrefix, name)
+
+	var job api.VolumeCopyJob
+
+	checkCopyComplete := func() error {
+		var err error
+		if job, err = d.API.GetVolumeCopyJobForTarget(ctx, volume); err != nil {
+			return backoff.Permanent(err)
+		}
+		if !d.API.IsRefValid(job.VolumeCopyRef) {
+			return nil
+		}
+		switch job.Status {
+		case volumeCopyStatusComplete:
+			return nil
+		case volumeCopyStatusFailed, volumeCopyStatusHalted:
+			return backoff.Permanent(fmt.Errorf("copy to clone %s is %s", name, job.Status))
+		default:
+			return utils.VolumeCreatingError(fmt.Sprintf("copy to clone %s is %d%% complete", name,
+				job.PercentComplete))
+		}
+	}
+	copyNotify := func(err error, duration time.Duration) {
+		Logc(ctx).WithField("increment", duration).Debugf("%v, waiting.", err)
+	}
+	copyBackoff := backoff.NewExponentialBackOff()
+	copyBackoff.InitialInterval = 1 * time.Second
+	copyBackoff.Multiplier = 2
+	copyBackoff.RandomizationFactor = 0.1
+	copyBackoff.MaxElapsedTime = cloneCopyWaitTime
+
+	if err := backoff.RetryNotify(checkCopyComplete, copyBackoff, copyNotify); err != nil {
+		return err
+	}
+
+	view, err := d.API.GetSnapshotVolume(ctx, viewName)
+	if err != nil {
+		return fmt.Errorf("could not check for snapshot volume %s; %v", viewName, err)
+	}
+
+	if !d.API.IsRefValid(job.VolumeCopyRef) {
+		if d.API.IsRefValid(view.SnapshotVolumeRef) {
+			// The clone was created, but the copy was never started
+			if _, err = d.API.CreateVolumeCopyJob(ctx, view.SnapshotVolumeRef, volume); err != nil {
+				return fmt.Errorf("could not copy snapshot volume %s to clone %s; %v", viewName, name, err)
+			}
+			return utils.VolumeCreatingError(fmt.Sprintf("copy to clone %s started", name))
+		}
+
+		// Nothing left to do, so return VolumeExistsError to trigger the idempotency in Backend
+		return drivers.NewVolumeExistsError(name)
+	}
+
+	// The copy is done, so clean up everything but the clone
+	if err = d.API.DeleteVolumeCopyJob(ctx, job); err != nil {
+		Logc(ctx).WithField("clone", name).WithError(err).Warning("Could not delete volume copy job.")
+	}
+	if d.API.IsRefValid(view.SnapshotVolumeRef) {
+		if err = d.API.DeleteSnapshotVolume(ctx, view); err != nil {
+			Logc(ctx).WithField("clone", name).WithError(err).Warning("Could not delete snapshot volume.")
+		}
+	}
+	groupName := d.getCloneObjectName(cloneGroupPrefix, name)
+	if group, err := d.API.GetSnapshotGroup(ctx, groupName); err != nil {
+		Logc(ctx).WithField("clone", name).WithError(err).Warning("Could not check for snapshot group.")
+	} else if d.API.IsRefValid(group.SnapshotGroupRef) {
+		if err = d.API.DeleteSnapshotGroup(ctx, group); err != nil {
+			Logc(ctx).WithField("clone", name).WithError(err).Warning("Could not delete snapshot group.")
+		}
+	}
+
+	Logc(ctx).WithField("clone", name).Debug("Clone copy complete.")
+
+	return nil
 }
 
-func (d *SANStorageDriver) Import(context.Context, *storage.VolumeConfig, string) error {
-	return errors.New("import is not implemented")
+// Import brings an existing volume under Trident's control, renaming it unless it is not to be managed.
+func (d *SANStorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Import",
+			"Type":         "SANStorageDriver",
+			"originalName": originalName,
+			"newName":      volConfig.InternalName,
+			"notManaged":   volConfig.ImportNotManaged,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Import")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Import")
+	}
+
+	volume, err := d.getVolume(ctx, originalName)
+	if err != nil {
+		return err
+	}
+
+	if volume.IsOffline {
+		return fmt.Errorf("volume %s is offline", originalName)
+	}
+	if len(volume.Mappings) > 0 {
+		return fmt.Errorf("volume %s is mapped to a host and cannot be imported", originalName)
+	}
+
+	// Use the volume size
+	if _, err = strconv.ParseUint(volume.VolumeSize, 10, 64); err != nil {
+		return fmt.Errorf("volume %s size %s is invalid; %v", originalName, volume.VolumeSize, err)
+	}
+	volConfig.Size = volume.VolumeSize
+
+	// Rename the volume if Trident will manage its lifecycle
+	if !volConfig.ImportNotManaged && volume.Label != volConfig.InternalName {
+		if _, err = d.API.RenameVolume(ctx, volume, volConfig.InternalName); err != nil {
+			Logc(ctx).WithField("originalName", originalName).Errorf(
+				"Could not import volume, rename volume failed: %v", err)
+			return fmt.Errorf("volume %s rename failed: %v", originalName, err)
+		}
+	}
+
+	return nil
 }
 
-func (d *SANStorageDriver) Rename(context.Context, string, string) error {
-	return errors.New("rename is not implemented")
+// Rename changes the name of a volume.
+func (d *SANStorageDriver) Rename(ctx context.Context, name string, newName string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":  "Rename",
+			"Type":    "SANStorageDriver",
+			"name":    name,
+			"newName": newName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Rename")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Rename")
+	}
+
+	volume, err := d.getVolume(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if _, err = d.API.RenameVolume(ctx, volume, newName); err != nil {
+		return fmt.Errorf("could not rename volume %s to %s; %v", name, newName, err)
+	}
+
+	return nil
 }
 
 // Get test for the existence of a volume
@@ -1080,6 +1645,53 @@ func (d *SANStorageDriver) GetStorageBackendPhysicalPoolNames(context.Context) [
 	return physicalPoolNames
 }
 
+// GetCapacity returns the capacity of the volume groups and DDP pools that could host a volume with the
+// specified attributes in the specified pool
+func (d *SANStorageDriver) GetCapacity(
+	ctx context.Context, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+
+	poolName := ""
+	candidatePools := d.physicalPools
+	if _, ok := d.physicalPools[storagePool.Name]; ok {
+		poolName = storagePool.Name
+	} else if _, ok := d.virtualPools[storagePool.Name]; ok {
+		// A virtual pool may place a volume on any pool that satisfies the volume's attributes
+		candidatePools = make(map[string]*storage.Pool)
+		for _, pool := range sc.FilterPoolsOnAttributes(ctx, d.physicalPools, volAttributes) {
+			candidatePools[pool.Name] = pool
+		}
+	} else {
+		return nil, fmt.Errorf("could not find pool %s", storagePool.Name)
+	}
+
+	volumeGroups, err := d.API.GetVolumePools(ctx, "", 0, poolName)
+	if err != nil {
+		return nil, fmt.Errorf("could not get storage pools from array; %v", err)
+	}
+
+	capacity := make(map[string]*storage.PoolCapacity)
+	for _, volumeGroup := range volumeGroups {
+		if _, ok := candidatePools[volumeGroup.Label]; !ok {
+			continue
+		}
+		freeSpace, err := strconv.ParseUint(volumeGroup.FreeSpace, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse free space of pool %s; %v", volumeGroup.Label, err)
+		}
+		totalSpace, err := strconv.ParseUint(volumeGroup.TotalRaidedSpace, 10, 64)
+		if err != nil {
+			totalSpace = freeSpace
+		}
+		capacity[volumeGroup.Label] = &storage.PoolCapacity{
+			TotalBytes:     totalSpace,
+			AvailableBytes: freeSpace,
+		}
+	}
+
+	return capacity, nil
+}
+
 func (d *SANStorageDriver) GetInternalVolumeName(ctx context.Context, name string) string {
 
 	if tridentconfig.UsingPassthroughStore {
@@ -1096,8 +1708,8 @@ func (d *SANStorageDriver) GetInternalVolumeName(ctx context.Context, name strin
 		b64string, err := d.uuidToBase64(uuid4string)
 		if err != nil {
 			// This is unlikely, but if the UUID encoding fails, just return the original string (capped to 30 chars)
-			if len(name) > 30 {
-				return name[0:30]
+			if len(name) > maxNameLength {
+				return name[0:maxNameLength]
 			}
 			return name
 		}
@@ -1197,33 +1809,16 @@ func (d *SANStorageDriver) CreateFollowup(ctx context.Context, volConfig *storag
 		return fmt.Errorf("could not get target IQN from array: %v", err)
 	}
 
-	// Get the Trident Host Group
-	hostGroup, err := d.API.GetHostGroup(ctx, d.Config.AccessGroup)
-	if err != nil {
-		return fmt.Errorf("could not get Host Group %s from array: %v", d.Config.AccessGroup, err)
-	}
-
-	// Map the volume directly to the Host Group
-	host := api.HostEx{
-		HostRef:    api.NullRef,
-		ClusterRef: hostGroup.ClusterRef,
-	}
-	mapping, err := d.API.MapVolume(ctx, volume, host)
-	if err != nil {
-		return fmt.Errorf("could not map volume %s to Host Group %s: %v", name, hostGroup.Label, err)
-	}
-
+	// The volume is mapped to each node's host as it is published
 	volConfig.AccessInfo.IscsiTargetPortal = d.Config.HostDataIP
 	volConfig.AccessInfo.IscsiTargetIQN = targetIQN
-	volConfig.AccessInfo.IscsiLunNumber = int32(mapping.LunNumber)
+	volConfig.AccessInfo.IscsiLunNumber = 0
 
 	Logc(ctx).WithFields(log.Fields{
 		"volume":          volConfig.Name,
 		"volume_internal": volConfig.InternalName,
 		"targetIQN":       volConfig.AccessInfo.IscsiTargetIQN,
-		"lunNumber":       volConfig.AccessInfo.IscsiLunNumber,
-		"hostGroup":       hostGroup.Label,
-	}).Debug("Mapped E-series LUN.")
+	}).Debug("Populated E-series LUN access info.")
 
 	return nil
 }
@@ -1491,6 +2086,24 @@ func (d *SANStorageDriver) Resize(ctx context.Context, volConfig *storage.Volume
 	return nil
 }
 
+// Modify changes the attributes of an existing volume, which is not supported by this driver.
+func (d *SANStorageDriver) Modify(
+	ctx context.Context, volConfig *storage.VolumeConfig, modification *storage.VolumeModification,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Modify",
+			"Type":   "SANStorageDriver",
+			"name":   volConfig.InternalName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Modify")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Modify")
+	}
+
+	return utils.UnsupportedError(fmt.Sprintf("volume modification is not supported by backend type %s", d.Name()))
+}
+
 func (d *SANStorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node, _ string) error {
 
 	nodeNames := make([]string, 0)
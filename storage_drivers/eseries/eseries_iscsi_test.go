@@ -7,15 +7,21 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/netapp/trident/storage"
 	drivers "github.com/netapp/trident/storage_drivers"
 	"github.com/netapp/trident/storage_drivers/eseries/api"
 )
@@ -265,3 +271,410 @@ func TestValidateStoragePrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSnapshotGroupName(t *testing.T) {
+	d := newTestEseriesSANDriver(nil)
+
+	tests := []struct {
+		Name      string
+		GroupName string
+	}{
+		{"snapshot-a2b24c5f-6e4d-4e2c-9a3b-0f5a2d8c7b61", "snap_orJMX25NTiyaOw9aLYx7YQ"},
+		{"docker_snap", "docker_snap"},
+		{"snapshot-notauuid", "snapshot-notauuid"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			groupName, err := d.getSnapshotGroupName(test.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, test.GroupName, groupName)
+			assert.LessOrEqual(t, len(groupName), maxNameLength)
+			assert.Equal(t, test.Name, d.getSnapshotNameFromGroupName(groupName))
+		})
+	}
+
+	_, err := d.getSnapshotGroupName("a_very_long_snapshot_name_for_docker")
+	assert.Error(t, err, "expected an error for a snapshot name that is too long")
+}
+
+func TestGetCloneObjectName(t *testing.T) {
+	d := newTestEseriesSANDriver(nil)
+
+	assert.Equal(t, "clone_g_orJMX25NTiyaOw9aLYx7YQ", d.getCloneObjectName(cloneGroupPrefix, "orJMX25NTiyaOw9aLYx7YQ"))
+	assert.Equal(t, "clone_v_test_a_very_long_volum", d.getCloneObjectName(cloneViewPrefix,
+		"test_a_very_long_volume_name"))
+}
+
+// fakeESeriesArray is a minimal in-memory Web Services Proxy that supports the volume, snapshot group,
+// snapshot image, snapshot volume and volume copy job resources.  Volume copies complete immediately.
+type fakeESeriesArray struct {
+	mutex           sync.Mutex
+	lastRef         int
+	volumes         []api.VolumeEx
+	groups          []api.SnapshotGroup
+	images          []api.SnapshotImage
+	snapshotVolumes []api.SnapshotVolume
+	copyJobs        []api.VolumeCopyJob
+}
+
+func (a *fakeESeriesArray) newRef() string {
+	a.lastRef++
+	return fmt.Sprintf("%040d", a.lastRef)
+}
+
+func (a *fakeESeriesArray) addVolume(name string, sizeBytes uint64) api.VolumeEx {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	volume := api.VolumeEx{
+		Label:          name,
+		VolumeSize:     strconv.FormatUint(sizeBytes, 10),
+		VolumeRef:      a.newRef(),
+		VolumeGroupRef: "pool1",
+	}
+	a.volumes = append(a.volumes, volume)
+	return volume
+}
+
+func (a *fakeESeriesArray) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/devmgr/v2/storage-systems/"), "/")
+	resource := strings.Split(path, "/")
+	ref := ""
+	if len(resource) > 1 {
+		ref = resource[1]
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+
+	var response interface{}
+
+	switch r.Method + " " + resource[0] {
+
+	case "GET volumes":
+		response = a.volumes
+		for _, volume := range a.volumes {
+			if volume.VolumeRef == ref {
+				response = volume
+			}
+		}
+	case "POST volumes":
+		if ref != "" {
+			var request api.VolumeUpdateRequest
+			_ = json.Unmarshal(body, &request)
+			for i := range a.volumes {
+				if a.volumes[i].VolumeRef == ref {
+					a.volumes[i].Label = request.Name
+					response = a.volumes[i]
+				}
+			}
+			break
+		}
+		var request api.VolumeCreateRequest
+		_ = json.Unmarshal(body, &request)
+		volume := api.VolumeEx{
+			Label:          request.Name,
+			VolumeSize:     strconv.Itoa(request.Size * 1024),
+			VolumeRef:      a.newRef(),
+			VolumeGroupRef: request.VolumeGroupRef,
+			VolumeTags:     request.VolumeTags,
+		}
+		a.volumes = append(a.volumes, volume)
+		response = volume
+
+	case "GET snapshot-groups":
+		response = a.groups
+	case "POST snapshot-groups":
+		var request api.SnapshotGroupCreateRequest
+		_ = json.Unmarshal(body, &request)
+		group := api.SnapshotGroup{
+			SnapshotGroupRef: a.newRef(),
+			Label:            request.Name,
+			BaseVolumeRef:    request.BaseMappableObjectID,
+			Status:           "optimal",
+		}
+		a.groups = append(a.groups, group)
+		response = group
+	case "DELETE snapshot-groups":
+		groups := make([]api.SnapshotGroup, 0)
+		for _, group := range a.groups {
+			if group.SnapshotGroupRef != ref {
+				groups = append(groups, group)
+			}
+		}
+		images := make([]api.SnapshotImage, 0)
+		for _, image := range a.images {
+			if image.SnapshotGroupRef != ref {
+				images = append(images, image)
+			}
+		}
+		a.groups, a.images = groups, images
+		w.WriteHeader(http.StatusNoContent)
+		return
+
+	case "GET snapshot-images":
+		response = a.images
+	case "POST snapshot-images":
+		var request api.SnapshotImageCreateRequest
+		_ = json.Unmarshal(body, &request)
+		image := api.SnapshotImage{
+			SnapshotImageRef: a.newRef(),
+			SnapshotGroupRef: request.SnapshotGroupID,
+			Timestamp:        "1620000000",
+			SequenceNumber:   strconv.Itoa(a.lastRef),
+			Status:           "optimal",
+		}
+		for _, group := range a.groups {
+			if group.SnapshotGroupRef == request.SnapshotGroupID {
+				image.BaseVolumeRef = group.BaseVolumeRef
+			}
+		}
+		a.images = append(a.images, image)
+		response = image
+
+	case "GET snapshot-volumes":
+		response = a.snapshotVolumes
+	case "POST snapshot-volumes":
+		var request api.SnapshotVolumeCreateRequest
+		_ = json.Unmarshal(body, &request)
+		snapshotVolume := api.SnapshotVolume{
+			SnapshotVolumeRef: a.newRef(),
+			Label:             request.Name,
+			SnapshotImageRef:  request.SnapshotImageID,
+			AccessMode:        request.ViewMode,
+			Status:            "optimal",
+		}
+		a.snapshotVolumes = append(a.snapshotVolumes, snapshotVolume)
+		response = snapshotVolume
+	case "DELETE snapshot-volumes":
+		snapshotVolumes := make([]api.SnapshotVolume, 0)
+		for _, snapshotVolume := range a.snapshotVolumes {
+			if snapshotVolume.SnapshotVolumeRef != ref {
+				snapshotVolumes = append(snapshotVolumes, snapshotVolume)
+			}
+		}
+		a.snapshotVolumes = snapshotVolumes
+		w.WriteHeader(http.StatusNoContent)
+		return
+
+	case "GET volume-copy-jobs":
+		response = a.copyJobs
+	case "POST volume-copy-jobs":
+		var request api.VolumeCopyJobCreateRequest
+		_ = json.Unmarshal(body, &request)
+		job := api.VolumeCopyJob{
+			VolumeCopyRef:   a.newRef(),
+			SourceVolumeRef: request.SourceID,
+			TargetVolumeRef: request.TargetID,
+			Status:          "complete",
+			PercentComplete: 100,
+		}
+		a.copyJobs = append(a.copyJobs, job)
+		response = job
+	case "POST volume-copy-jobs-control":
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case "DELETE volume-copy-jobs":
+		copyJobs := make([]api.VolumeCopyJob, 0)
+		for _, job := range a.copyJobs {
+			if job.VolumeCopyRef != ref {
+				copyJobs = append(copyJobs, job)
+			}
+		}
+		a.copyJobs = copyJobs
+		w.WriteHeader(http.StatusNoContent)
+		return
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	responseBody, _ := json.Marshal(response)
+	_, _ = w.Write(responseBody)
+}
+
+// newTestEseriesSANDriverWithArray returns a driver connected to a fake array.
+func newTestEseriesSANDriverWithArray(t *testing.T) (*SANStorageDriver, *fakeESeriesArray) {
+
+	array := &fakeESeriesArray{}
+
+	server := httptest.NewServer(array)
+	t.Cleanup(server.Close)
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := newTestEseriesSANDriver(map[string]bool{})
+	d.Config.WebProxyPort = port
+	d.API = newTestEseriesSANDriverAPI(&d.Config)
+
+	return d, array
+}
+
+func TestEseriesSANStorageDriverSnapshots(t *testing.T) {
+
+	ctx := context.Background()
+	d, array := newTestEseriesSANDriverWithArray(t)
+
+	volume := array.addVolume("test_vol", 1073741824)
+
+	snapConfig := &storage.SnapshotConfig{
+		Name:               "snapshot-a2b24c5f-6e4d-4e2c-9a3b-0f5a2d8c7b61",
+		VolumeName:         "vol",
+		VolumeInternalName: "test_vol",
+	}
+
+	snapshot, err := d.GetSnapshot(ctx, snapConfig)
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot, "snapshot should not exist yet")
+
+	snapshot, err = d.CreateSnapshot(ctx, snapConfig)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "snap_orJMX25NTiyaOw9aLYx7YQ", snapshot.Config.InternalName)
+		assert.Equal(t, int64(1073741824), snapshot.SizeBytes)
+		assert.Equal(t, storage.SnapshotStateOnline, snapshot.State)
+	}
+	if assert.Len(t, array.groups, 1) {
+		assert.Equal(t, volume.VolumeRef, array.groups[0].BaseVolumeRef)
+	}
+
+	snapshot, err = d.GetSnapshot(ctx, snapConfig)
+	if assert.NoError(t, err) && assert.NotNil(t, snapshot) {
+		assert.Equal(t, snapConfig.Name, snapshot.Config.Name)
+	}
+
+	// Groups used temporarily for cloning are not reported as snapshots
+	array.mutex.Lock()
+	array.groups = append(array.groups, api.SnapshotGroup{
+		SnapshotGroupRef: array.newRef(),
+		Label:            cloneGroupPrefix + "test_clone",
+		BaseVolumeRef:    volume.VolumeRef,
+	})
+	array.images = append(array.images, api.SnapshotImage{
+		SnapshotImageRef: array.newRef(),
+		SnapshotGroupRef: array.groups[1].SnapshotGroupRef,
+	})
+	array.mutex.Unlock()
+
+	snapshots, err := d.GetSnapshots(ctx, &storage.VolumeConfig{Name: "vol", InternalName: "test_vol"})
+	if assert.NoError(t, err) && assert.Len(t, snapshots, 1) {
+		assert.Equal(t, snapConfig.Name, snapshots[0].Config.Name)
+		assert.Equal(t, "snap_orJMX25NTiyaOw9aLYx7YQ", snapshots[0].Config.InternalName)
+	}
+
+	assert.NoError(t, d.DeleteSnapshot(ctx, snapConfig))
+	snapshot, err = d.GetSnapshot(ctx, snapConfig)
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot, "snapshot should have been deleted")
+
+	// Deleting a snapshot that no longer exists succeeds
+	assert.NoError(t, d.DeleteSnapshot(ctx, snapConfig))
+}
+
+func TestEseriesSANStorageDriverCreateClone(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		snapshot     string
+		size         string
+		expectedSize string
+		valid        bool
+	}{
+		{"clone of volume", "", "", "1073741824", true},
+		{"clone of snapshot", "snapshot-a2b24c5f-6e4d-4e2c-9a3b-0f5a2d8c7b61", "", "1073741824", true},
+		{"larger clone", "", "2147483648", "2147483648", true},
+		{"larger clone with units", "", "2Gi", "2147483648", true},
+		{"smaller clone", "", "536870912", "", false},
+		{"missing snapshot", "snapshot-00000000-0000-0000-0000-000000000000", "", "", false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		ctx := context.Background()
+		d, array := newTestEseriesSANDriverWithArray(t)
+
+		array.addVolume("test_vol", 1073741824)
+		if test.valid && test.snapshot != "" {
+			_, err := d.CreateSnapshot(ctx, &storage.SnapshotConfig{Name: test.snapshot, VolumeInternalName: "test_vol"})
+			assert.NoError(t, err, test.name)
+		}
+
+		volConfig := &storage.VolumeConfig{
+			InternalName:              "test_clone",
+			Size:                      test.size,
+			CloneSourceVolumeInternal: "test_vol",
+			CloneSourceSnapshot:       test.snapshot,
+		}
+
+		err := d.CreateClone(ctx, volConfig, nil)
+		if !test.valid {
+			assert.Error(t, err, test.name)
+			assert.Len(t, array.volumes, 1, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+
+		clone, err := d.API.GetVolume(ctx, "test_clone")
+		if assert.NoError(t, err, test.name) {
+			assert.Equal(t, test.expectedSize, clone.VolumeSize, test.name)
+		}
+		assert.Equal(t, test.expectedSize, volConfig.Size, test.name)
+
+		// Only the requested snapshot remains once the copy is done
+		assert.Empty(t, array.snapshotVolumes, test.name)
+		assert.Empty(t, array.copyJobs, test.name)
+		if test.snapshot == "" {
+			assert.Empty(t, array.groups, test.name)
+		} else {
+			assert.Len(t, array.groups, 1, test.name)
+		}
+	}
+}
+
+func TestEseriesSANStorageDriverImport(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		notManaged   bool
+		mapped       bool
+		expectedName string
+		valid        bool
+	}{
+		{"managed", false, false, "test_imported", true},
+		{"not managed", true, false, "legacy", true},
+		{"mapped", false, true, "legacy", false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		ctx := context.Background()
+		d, array := newTestEseriesSANDriverWithArray(t)
+
+		array.addVolume("legacy", 2147483648)
+		if test.mapped {
+			array.volumes[0].Mappings = []api.LUNMapping{{LunMappingRef: "mapping1"}}
+		}
+
+		volConfig := &storage.VolumeConfig{InternalName: "test_imported", ImportNotManaged: test.notManaged}
+
+		err := d.Import(ctx, volConfig, "legacy")
+		if test.valid {
+			assert.NoError(t, err, test.name)
+			assert.Equal(t, "2147483648", volConfig.Size, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+		assert.Equal(t, test.expectedName, array.volumes[0].Label, test.name)
+	}
+
+	d, _ := newTestEseriesSANDriverWithArray(t)
+	err := d.Import(context.Background(), &storage.VolumeConfig{InternalName: "test_imported"}, "missing")
+	assert.Error(t, err, "expected an error importing a missing volume")
+}
@@ -9,10 +9,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/RoaringBitmap/roaring"
@@ -72,16 +72,32 @@ type StorageDriver struct {
 	// state.
 	DestroyedSnapshots map[string]bool
 
+	// Mirrors saves the status of mirror relationships to volumes on this driver, keyed by the
+	// name of the local volume
+	Mirrors map[string]*storage.MirrorStatus
+
+	// SplitClones records the clones split from their source volumes, keyed by internal name, so
+	// that tests can check whether a split was started
+	SplitClones map[string]bool
+
+	// Unpublished saves the publish info of the latest unpublish from each node, keyed by node name,
+	// so that tests can check how the orchestrator identified the node
+	Unpublished map[string]*utils.VolumePublishInfo
+
 	Secret string
+
+	// mutex serializes access to the in-memory state above, since the orchestrator may
+	// invoke this driver from multiple goroutines
+	mutex sync.Mutex
 }
 
 // Implement Stringer interface for the FakeStorageDriver driver
-func (d StorageDriver) String() string {
-	return drivers.ToString(&d, []string{"Secret"}, nil)
+func (d *StorageDriver) String() string {
+	return drivers.ToString(d, []string{"Secret"}, nil)
 }
 
 // Implement GoStringer interface for the FakeStorageDriver driver
-func (d StorageDriver) GoString() string {
+func (d *StorageDriver) GoString() string {
 	return d.String()
 }
 
@@ -114,6 +130,8 @@ func NewFakeStorageDriver(ctx context.Context, config drivers.FakeStorageDriverC
 		DestroyedVolumes:   make(map[string]bool),
 		Snapshots:          make(map[string]map[string]*storage.Snapshot),
 		DestroyedSnapshots: make(map[string]bool),
+		Mirrors:            make(map[string]*storage.MirrorStatus),
+		SplitClones:        make(map[string]bool),
 		Secret:             "secret",
 	}
 	_ = driver.populateConfigurationDefaults(ctx, &config)
@@ -143,6 +161,8 @@ func NewFakeStorageDriverWithPools(ctx context.Context, pools map[string]*fake.S
 		DestroyedVolumes:   make(map[string]bool),
 		Snapshots:          make(map[string]map[string]*storage.Snapshot),
 		DestroyedSnapshots: make(map[string]bool),
+		Mirrors:            make(map[string]*storage.MirrorStatus),
+		SplitClones:        make(map[string]bool),
 		Secret:             "fake-secret",
 	}
 
@@ -172,6 +192,8 @@ func NewFakeStorageDriverWithDebugTraceFlags(debugTraceFlags map[string]bool) *S
 		DestroyedVolumes:   make(map[string]bool),
 		Snapshots:          make(map[string]map[string]*storage.Snapshot),
 		DestroyedSnapshots: make(map[string]bool),
+		Mirrors:            make(map[string]*storage.MirrorStatus),
+		SplitClones:        make(map[string]bool),
 		Secret:             "fake-secret",
 	}
 
@@ -305,6 +327,8 @@ func (d *StorageDriver) Initialize(
 	d.Config.SerialNumbers = []string{d.Config.InstanceName + "_SN"}
 	d.Snapshots = make(map[string]map[string]*storage.Snapshot)
 	d.DestroyedSnapshots = make(map[string]bool)
+	d.Mirrors = make(map[string]*storage.MirrorStatus)
+	d.SplitClones = make(map[string]bool)
 
 	s, _ := json.Marshal(d.Config)
 	Logc(ctx).Debugf("FakeStorageDriverConfig: %s", string(s))
@@ -539,6 +563,9 @@ func (d *StorageDriver) Create(
 	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool, volAttributes map[string]sa.Request,
 ) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	name := volConfig.InternalName
 	if _, ok := d.Volumes[name]; ok {
 		return drivers.NewVolumeExistsError(name)
@@ -640,34 +667,18 @@ func (d *StorageDriver) getPoolsForCreate(
 		return nil, fmt.Errorf("could not find pool %s", storagePool.Name)
 	}
 
-	// Make a storage class from the volume attributes to simplify pool matching
-	attributesCopy := make(map[string]sa.Request)
-	for k, v := range volAttributes {
-		attributesCopy[k] = v
-	}
-	delete(attributesCopy, sa.Selector)
-	storageClass := sc.NewFromAttributes(attributesCopy)
-
 	// Find matching pools
-	candidatePools := make([]*storage.Pool, 0)
-
-	for _, pool := range d.physicalPools {
-		if storageClass.Matches(ctx, pool) {
-			candidatePools = append(candidatePools, pool)
-		}
-	}
+	candidatePools := sc.FilterPoolsOnAttributes(ctx, d.physicalPools, volAttributes)
 
 	if len(candidatePools) == 0 {
 		err := errors.New("backend has no physical pools that can satisfy request")
 		return nil, drivers.NewBackendIneligibleError(volConfig.InternalName, []error{err}, []string{})
 	}
 
-	// Shuffle physical pools
-	rand.Shuffle(len(candidatePools), func(i, j int) {
-		candidatePools[i], candidatePools[j] = candidatePools[j], candidatePools[i]
-	})
-
-	return candidatePools, nil
+	return storage.OrderPhysicalPoolsForCreate(ctx, volConfig, candidatePools,
+		func() (map[string]*storage.PoolCapacity, error) {
+			return d.getCapacity(ctx, storagePool, volAttributes)
+		}), nil
 }
 
 func (d *StorageDriver) BootstrapVolume(ctx context.Context, volume *storage.Volume) {
@@ -710,6 +721,9 @@ func (d *StorageDriver) CreateClone(
 	ctx context.Context, volConfig *storage.VolumeConfig, _ *storage.Pool,
 ) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	name := volConfig.InternalName
 	source := volConfig.CloneSourceVolumeInternal
 	snapshot := volConfig.CloneSourceSnapshot
@@ -753,6 +767,10 @@ func (d *StorageDriver) CreateClone(
 	d.DestroyedVolumes[name] = false
 	fakePool.Bytes -= sizeBytes
 
+	if split, _ := strconv.ParseBool(volConfig.SplitOnClone); split {
+		d.SplitClones[name] = true
+	}
+
 	Logc(ctx).WithFields(log.Fields{
 		"backend":       d.Config.InstanceName,
 		"Name":          name,
@@ -766,8 +784,30 @@ func (d *StorageDriver) CreateClone(
 	return nil
 }
 
+// SplitClone records the split of a clone from its source volume if the clone's config calls for it.
+func (d *StorageDriver) SplitClone(ctx context.Context, volConfig *storage.VolumeConfig, _ *storage.Pool) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	name := volConfig.InternalName
+	if _, ok := d.Volumes[name]; !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", name))
+	}
+
+	if split, _ := strconv.ParseBool(volConfig.SplitOnClone); split {
+		d.SplitClones[name] = true
+		Logc(ctx).WithField("Name", name).Debug("Split fake clone.")
+	}
+
+	return nil
+}
+
 func (d *StorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	Logc(ctx).WithFields(log.Fields{
 		"volumeConfig": volConfig,
 		"originalName": originalName,
@@ -790,6 +830,9 @@ func (d *StorageDriver) Import(ctx context.Context, volConfig *storage.VolumeCon
 
 func (d *StorageDriver) Rename(ctx context.Context, name, newName string) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	Logc(ctx).WithFields(log.Fields{
 		"name":    name,
 		"newName": newName,
@@ -808,6 +851,9 @@ func (d *StorageDriver) Rename(ctx context.Context, name, newName string) error
 
 func (d *StorageDriver) Destroy(ctx context.Context, name string) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	d.DestroyedVolumes[name] = true
 
 	volume, ok := d.Volumes[name]
@@ -837,7 +883,22 @@ func (d *StorageDriver) Destroy(ctx context.Context, name string) error {
 }
 
 func (d *StorageDriver) Publish(context.Context, *storage.VolumeConfig, *utils.VolumePublishInfo) error {
-	return errors.New("fake driver does not support Publish")
+	return nil
+}
+
+func (d *StorageDriver) Unpublish(
+	_ context.Context, _ *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.Unpublished == nil {
+		d.Unpublished = make(map[string]*utils.VolumePublishInfo)
+	}
+	d.Unpublished[publishInfo.HostName] = publishInfo
+
+	return nil
 }
 
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
@@ -851,6 +912,9 @@ func (d *StorageDriver) GetSnapshot(_ context.Context, snapConfig *storage.Snaps
 	*storage.Snapshot, error,
 ) {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
 
@@ -875,6 +939,9 @@ func (d *StorageDriver) GetSnapshots(_ context.Context, volConfig *storage.Volum
 	[]*storage.Snapshot, error,
 ) {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	internalVolName := volConfig.InternalName
 
 	snapshots := make([]*storage.Snapshot, 0)
@@ -897,6 +964,9 @@ func (d *StorageDriver) CreateSnapshot(ctx context.Context, snapConfig *storage.
 	*storage.Snapshot, error,
 ) {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
 
@@ -964,6 +1034,9 @@ func (d *StorageDriver) BootstrapSnapshot(ctx context.Context, snapshot *storage
 // RestoreSnapshot restores a volume (in place) from a snapshot.
 func (d *StorageDriver) RestoreSnapshot(_ context.Context, snapConfig *storage.SnapshotConfig) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
 
@@ -986,6 +1059,9 @@ func (d *StorageDriver) RestoreSnapshot(_ context.Context, snapConfig *storage.S
 // DeleteSnapshot creates a snapshot of a volume.
 func (d *StorageDriver) DeleteSnapshot(_ context.Context, snapConfig *storage.SnapshotConfig) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
 
@@ -1003,6 +1079,9 @@ func (d *StorageDriver) DeleteSnapshot(_ context.Context, snapConfig *storage.Sn
 
 func (d *StorageDriver) Get(_ context.Context, name string) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	_, ok := d.Volumes[name]
 	if !ok {
 		return fmt.Errorf("could not find volume %s", name)
@@ -1014,6 +1093,9 @@ func (d *StorageDriver) Get(_ context.Context, name string) error {
 // Resize expands the volume size.
 func (d *StorageDriver) Resize(_ context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	name := volConfig.InternalName
 	vol := d.Volumes[name]
 
@@ -1032,6 +1114,160 @@ func (d *StorageDriver) Resize(_ context.Context, volConfig *storage.VolumeConfi
 	return nil
 }
 
+func (d *StorageDriver) Modify(
+	_ context.Context, volConfig *storage.VolumeConfig, _ *storage.VolumeModification,
+) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.Volumes[volConfig.InternalName]; !ok {
+		return fmt.Errorf("volume %s not found", volConfig.InternalName)
+	}
+
+	return nil
+}
+
+// GetMirrorVolumeHandle returns the handle by which a peer backend may refer to a volume.
+func (d *StorageDriver) GetMirrorVolumeHandle(_ context.Context, name string) (string, error) {
+	return d.Config.InstanceName + ":" + name, nil
+}
+
+// setMirrorState records the state of the mirror relationship to a volume.
+func (d *StorageDriver) setMirrorState(name, state string) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.Volumes[name]; !ok {
+		return fmt.Errorf("volume %s not found", name)
+	}
+
+	d.Mirrors[name] = &storage.MirrorStatus{State: state, Healthy: true}
+	return nil
+}
+
+func (d *StorageDriver) EstablishMirror(_ context.Context, name, _, _, _ string) error {
+
+	d.mutex.Lock()
+	mirror, ok := d.Mirrors[name]
+	d.mutex.Unlock()
+
+	if ok && mirror.State != storage.MirrorStateUninitialized {
+		return nil
+	}
+	return d.setMirrorState(name, storage.MirrorStateMirrored)
+}
+
+func (d *StorageDriver) ReestablishMirror(_ context.Context, name, _, _, _ string) error {
+	return d.setMirrorState(name, storage.MirrorStateMirrored)
+}
+
+func (d *StorageDriver) PromoteMirror(_ context.Context, name, _ string) error {
+
+	d.mutex.Lock()
+	_, ok := d.Mirrors[name]
+	d.mutex.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return d.setMirrorState(name, storage.MirrorStateBroken)
+}
+
+func (d *StorageDriver) GetMirrorStatus(_ context.Context, name, _ string) (*storage.MirrorStatus, error) {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	mirror, ok := d.Mirrors[name]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("mirror relationship for volume %s not found", name))
+	}
+
+	status := *mirror
+	return &status, nil
+}
+
+func (d *StorageDriver) DeleteMirror(_ context.Context, name, _ string) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	delete(d.Mirrors, name)
+	return nil
+}
+
+func (d *StorageDriver) ReleaseMirror(_ context.Context, name, _ string) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.Volumes[name]; !ok {
+		return fmt.Errorf("volume %s not found", name)
+	}
+	return nil
+}
+
+// MoveVolume moves a volume to another physical pool, which the fake driver does instantly.
+func (d *StorageDriver) MoveVolume(ctx context.Context, name, pool string) error {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	volume, ok := d.Volumes[name]
+	if !ok {
+		return fmt.Errorf("volume %s not found", name)
+	}
+	if volume.PhysicalPool == pool {
+		return nil
+	}
+
+	targetPool, ok := d.fakePools[pool]
+	if !ok {
+		return fmt.Errorf("could not find pool %s", pool)
+	}
+	if volume.SizeBytes > targetPool.Bytes {
+		return fmt.Errorf("requested volume is too large, requested %d bytes, have %d available in pool %s",
+			volume.SizeBytes, targetPool.Bytes, pool)
+	}
+	if sourcePool, ok := d.fakePools[volume.PhysicalPool]; ok {
+		sourcePool.Bytes += volume.SizeBytes
+	}
+	targetPool.Bytes -= volume.SizeBytes
+
+	volume.RequestedPool = pool
+	volume.PhysicalPool = pool
+	d.Volumes[name] = volume
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":      d.Config.InstanceName,
+		"name":         name,
+		"physicalPool": pool,
+	}).Debug("Moved fake volume.")
+
+	return nil
+}
+
+func (d *StorageDriver) GetVolumeMoveStatus(_ context.Context, name, pool string) (*storage.VolumeMoveStatus, error) {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	volume, ok := d.Volumes[name]
+	if !ok {
+		return nil, fmt.Errorf("volume %s not found", name)
+	}
+	if volume.PhysicalPool != pool {
+		return &storage.VolumeMoveStatus{
+			State:   storage.VolumeMoveStateFailed,
+			Message: fmt.Sprintf("volume %s is in pool %s", name, volume.PhysicalPool),
+		}, nil
+	}
+
+	return &storage.VolumeMoveStatus{State: storage.VolumeMoveStateComplete, PercentComplete: 100}, nil
+}
+
 func (d *StorageDriver) GetStorageBackendSpecs(_ context.Context, backend *storage.Backend) error {
 
 	if d.Config.BackendName == "" {
@@ -1069,6 +1305,54 @@ func (d *StorageDriver) GetStorageBackendPhysicalPoolNames(context.Context) []st
 	return physicalPoolNames
 }
 
+// GetCapacity returns the capacity of the fake pools that could host a volume with the specified attributes
+// in the specified pool
+func (d *StorageDriver) GetCapacity(
+	ctx context.Context, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.getCapacity(ctx, storagePool, volAttributes)
+}
+
+// getCapacity returns the pool capacities.  It assumes the driver mutex is already held.
+func (d *StorageDriver) getCapacity(
+	ctx context.Context, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+
+	fakePoolNames := make([]string, 0)
+
+	if _, ok := d.physicalPools[storagePool.Name]; ok {
+		fakePoolNames = append(fakePoolNames, storagePool.Name)
+	} else if _, ok := d.virtualPools[storagePool.Name]; ok {
+		for _, pool := range sc.FilterPoolsOnAttributes(ctx, d.physicalPools, volAttributes) {
+			fakePoolNames = append(fakePoolNames, pool.Name)
+		}
+	} else {
+		return nil, fmt.Errorf("could not find pool %s", storagePool.Name)
+	}
+
+	capacity := make(map[string]*storage.PoolCapacity)
+	for _, name := range fakePoolNames {
+		fakePool, ok := d.fakePools[name]
+		if !ok {
+			return nil, fmt.Errorf("fake pool %s not found", name)
+		}
+		totalBytes := fakePool.Bytes
+		if configPool, ok := d.Config.Pools[name]; ok {
+			totalBytes = configPool.Bytes
+		}
+		capacity[name] = &storage.PoolCapacity{
+			TotalBytes:     totalBytes,
+			AvailableBytes: fakePool.Bytes,
+		}
+	}
+
+	return capacity, nil
+}
+
 func (d *StorageDriver) GetInternalVolumeName(_ context.Context, name string) string {
 	if tridentconfig.UsingPassthroughStore {
 		// With a passthrough store, the name mapping must remain reversible
@@ -1144,6 +1428,9 @@ func (d *StorageDriver) GetExternalConfig(context.Context) interface{} {
 
 func (d *StorageDriver) GetVolumeExternal(_ context.Context, name string) (*storage.VolumeExternal, error) {
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	volume, ok := d.Volumes[name]
 	if !ok {
 		return nil, fmt.Errorf("fake volume %s not found", name)
@@ -1157,9 +1444,17 @@ func (d *StorageDriver) GetVolumeExternalWrappers(_ context.Context, channel cha
 	// Let the caller know we're done by closing the channel
 	defer close(channel)
 
-	// Convert all volumes to VolumeExternal and write them to the channel
+	// Convert all volumes to VolumeExternal without blocking on the channel while locked
+	d.mutex.Lock()
+	volumes := make([]*storage.VolumeExternal, 0, len(d.Volumes))
 	for _, volume := range d.Volumes {
-		channel <- &storage.VolumeExternalWrapper{Volume: d.getVolumeExternal(volume), Error: nil}
+		volumes = append(volumes, d.getVolumeExternal(volume))
+	}
+	d.mutex.Unlock()
+
+	// Write the volumes to the channel
+	for _, volume := range volumes {
+		channel <- &storage.VolumeExternalWrapper{Volume: volume, Error: nil}
 	}
 }
 
@@ -1208,7 +1503,11 @@ func (d *StorageDriver) GetUpdateType(_ context.Context, driverOrig storage.Driv
 }
 
 // CopyVolumes copies Volumes into this instance; there is no "storage system of truth" to use
-func (d StorageDriver) CopyVolumes(volumes map[string]fake.Volume) {
+func (d *StorageDriver) CopyVolumes(volumes map[string]fake.Volume) {
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	for name, vol := range volumes {
 		d.Volumes[name] = vol
 	}
@@ -1240,7 +1539,7 @@ func (d *StorageDriver) handleVolumeCreatingTransaction(ctx context.Context, vol
 	return nil
 }
 
-func (d StorageDriver) generateCreatingVolumes() map[string]fake.CreatingVolume {
+func (d *StorageDriver) generateCreatingVolumes() map[string]fake.CreatingVolume {
 	creatingVolumes := make(map[string]fake.CreatingVolume)
 	transaction01 := fake.CreatingVolume{
 		Name:           PVC_creating_01,
@@ -1288,6 +1587,6 @@ func (d *StorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*utils.
 }
 
 // GetCommonConfig returns driver's CommonConfig
-func (d StorageDriver) GetCommonConfig(context.Context) *drivers.CommonStorageDriverConfig {
+func (d *StorageDriver) GetCommonConfig(context.Context) *drivers.CommonStorageDriverConfig {
 	return d.Config.CommonStorageDriverConfig
 }
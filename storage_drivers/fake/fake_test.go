@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/storage/fake"
 	sa "github.com/netapp/trident/storage_attribute"
 	drivers "github.com/netapp/trident/storage_drivers"
@@ -28,9 +29,9 @@ func TestNewConfig(t *testing.T) {
 
 func TestStorageDriverString(t *testing.T) {
 
-	var fakeStorageDrivers = []StorageDriver{
-		*NewFakeStorageDriverWithDebugTraceFlags(map[string]bool{"method": true}),
-		*NewFakeStorageDriverWithDebugTraceFlags(nil),
+	var fakeStorageDrivers = []*StorageDriver{
+		NewFakeStorageDriverWithDebugTraceFlags(map[string]bool{"method": true}),
+		NewFakeStorageDriverWithDebugTraceFlags(nil),
 	}
 
 	// key: string to include in debug logs when the sensitive flag is set to true
@@ -136,3 +137,46 @@ func TestInitializeStoragePoolsLabels(t *testing.T) {
 		assert.Equal(t, c.virtualExpected, label, c.virtualErrorMessage)
 	}
 }
+
+func TestGetCapacity(t *testing.T) {
+
+	ctx := context.Background()
+
+	physicalPools := map[string]*fake.StoragePool{
+		"pool_0": {Bytes: 50 * 1024 * 1024 * 1024, Attrs: map[string]sa.Offer{sa.Media: sa.NewStringOffer(sa.HDD)}},
+		"pool_1": {Bytes: 20 * 1024 * 1024 * 1024, Attrs: map[string]sa.Offer{sa.Media: sa.NewStringOffer(sa.SSD)}},
+	}
+	virtualPools := []drivers.FakeStorageDriverPool{{Region: "us_east_1"}}
+
+	d, err := NewFakeStorageDriverWithPools(ctx, physicalPools, drivers.FakeStorageDriverPool{Region: "us_east_1"},
+		virtualPools)
+	assert.Nil(t, err, "Error is not nil")
+	_, err = storage.NewStorageBackend(ctx, d)
+	assert.Nil(t, err, "Error is not nil")
+
+	// A physical pool reports only itself
+	capacity, err := d.GetCapacity(ctx, d.physicalPools["pool_0"], nil)
+	assert.Nil(t, err, "Error is not nil")
+	assert.Equal(t, 1, len(capacity), "Wrong number of capacities")
+	assert.Equal(t, uint64(50*1024*1024*1024), capacity["pool_0"].TotalBytes, "Wrong total bytes")
+	assert.Equal(t, uint64(50*1024*1024*1024), capacity["pool_0"].AvailableBytes, "Wrong available bytes")
+
+	// A virtual pool reports all physical pools
+	d.fakePools["pool_1"].Bytes -= 5 * 1024 * 1024 * 1024
+	capacity, err = d.GetCapacity(ctx, d.virtualPools["fake_us_east_1_pool_0"], nil)
+	assert.Nil(t, err, "Error is not nil")
+	assert.Equal(t, 2, len(capacity), "Wrong number of capacities")
+	assert.Equal(t, uint64(20*1024*1024*1024), capacity["pool_1"].TotalBytes, "Wrong total bytes")
+	assert.Equal(t, uint64(15*1024*1024*1024), capacity["pool_1"].AvailableBytes, "Wrong available bytes")
+
+	// A virtual pool reports only the physical pools that match the volume attributes
+	capacity, err = d.GetCapacity(ctx, d.virtualPools["fake_us_east_1_pool_0"],
+		map[string]sa.Request{sa.Media: sa.NewStringRequest(sa.SSD)})
+	assert.Nil(t, err, "Error is not nil")
+	assert.Equal(t, 1, len(capacity), "Wrong number of capacities")
+	assert.NotNil(t, capacity["pool_1"], "Matching pool not reported")
+
+	// An unknown pool is an error
+	_, err = d.GetCapacity(ctx, storage.NewStoragePool(nil, "unknown"), nil)
+	assert.NotNil(t, err, "Error is nil")
+}
@@ -0,0 +1,214 @@
+package azgo
+
+import (
+	"encoding/xml"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// VolumeMoveInfoType is a structure to represent a volume-move-info ZAPI object
+type VolumeMoveInfoType struct {
+	XMLName                      xml.Name `xml:"volume-move-info"`
+	ActualCompletionTimestampPtr *int     `xml:"actual-completion-timestamp"`
+	BytesRemainingPtr            *int     `xml:"bytes-remaining"`
+	BytesSentPtr                 *int     `xml:"bytes-sent"`
+	CutoverActionPtr             *string  `xml:"cutover-action"`
+	DestinationAggregatePtr      *string  `xml:"destination-aggregate"`
+	DetailsPtr                   *string  `xml:"details"`
+	EstimatedCompletionTimePtr   *int     `xml:"estimated-completion-time"`
+	PercentCompletePtr           *int     `xml:"percent-complete"`
+	PhasePtr                     *string  `xml:"phase"`
+	SourceAggregatePtr           *string  `xml:"source-aggregate"`
+	StartTimestampPtr            *int     `xml:"start-timestamp"`
+	StatePtr                     *string  `xml:"state"`
+	VolumePtr                    *string  `xml:"volume"`
+	VserverPtr                   *string  `xml:"vserver"`
+}
+
+// NewVolumeMoveInfoType is a factory method for creating new instances of VolumeMoveInfoType objects
+func NewVolumeMoveInfoType() *VolumeMoveInfoType {
+	return &VolumeMoveInfoType{}
+}
+
+// ToXML converts this object into an xml string representation
+func (o *VolumeMoveInfoType) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o VolumeMoveInfoType) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ActualCompletionTimestamp is a 'getter' method
+func (o *VolumeMoveInfoType) ActualCompletionTimestamp() int {
+	r := *o.ActualCompletionTimestampPtr
+	return r
+}
+
+// SetActualCompletionTimestamp is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetActualCompletionTimestamp(newValue int) *VolumeMoveInfoType {
+	o.ActualCompletionTimestampPtr = &newValue
+	return o
+}
+
+// BytesRemaining is a 'getter' method
+func (o *VolumeMoveInfoType) BytesRemaining() int {
+	r := *o.BytesRemainingPtr
+	return r
+}
+
+// SetBytesRemaining is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetBytesRemaining(newValue int) *VolumeMoveInfoType {
+	o.BytesRemainingPtr = &newValue
+	return o
+}
+
+// BytesSent is a 'getter' method
+func (o *VolumeMoveInfoType) BytesSent() int {
+	r := *o.BytesSentPtr
+	return r
+}
+
+// SetBytesSent is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetBytesSent(newValue int) *VolumeMoveInfoType {
+	o.BytesSentPtr = &newValue
+	return o
+}
+
+// CutoverAction is a 'getter' method
+func (o *VolumeMoveInfoType) CutoverAction() string {
+	r := *o.CutoverActionPtr
+	return r
+}
+
+// SetCutoverAction is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetCutoverAction(newValue string) *VolumeMoveInfoType {
+	o.CutoverActionPtr = &newValue
+	return o
+}
+
+// DestinationAggregate is a 'getter' method
+func (o *VolumeMoveInfoType) DestinationAggregate() string {
+	r := *o.DestinationAggregatePtr
+	return r
+}
+
+// SetDestinationAggregate is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetDestinationAggregate(newValue string) *VolumeMoveInfoType {
+	o.DestinationAggregatePtr = &newValue
+	return o
+}
+
+// Details is a 'getter' method
+func (o *VolumeMoveInfoType) Details() string {
+	r := *o.DetailsPtr
+	return r
+}
+
+// SetDetails is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetDetails(newValue string) *VolumeMoveInfoType {
+	o.DetailsPtr = &newValue
+	return o
+}
+
+// EstimatedCompletionTime is a 'getter' method
+func (o *VolumeMoveInfoType) EstimatedCompletionTime() int {
+	r := *o.EstimatedCompletionTimePtr
+	return r
+}
+
+// SetEstimatedCompletionTime is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetEstimatedCompletionTime(newValue int) *VolumeMoveInfoType {
+	o.EstimatedCompletionTimePtr = &newValue
+	return o
+}
+
+// PercentComplete is a 'getter' method
+func (o *VolumeMoveInfoType) PercentComplete() int {
+	r := *o.PercentCompletePtr
+	return r
+}
+
+// SetPercentComplete is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetPercentComplete(newValue int) *VolumeMoveInfoType {
+	o.PercentCompletePtr = &newValue
+	return o
+}
+
+// Phase is a 'getter' method
+func (o *VolumeMoveInfoType) Phase() string {
+	r := *o.PhasePtr
+	return r
+}
+
+// SetPhase is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetPhase(newValue string) *VolumeMoveInfoType {
+	o.PhasePtr = &newValue
+	return o
+}
+
+// SourceAggregate is a 'getter' method
+func (o *VolumeMoveInfoType) SourceAggregate() string {
+	r := *o.SourceAggregatePtr
+	return r
+}
+
+// SetSourceAggregate is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetSourceAggregate(newValue string) *VolumeMoveInfoType {
+	o.SourceAggregatePtr = &newValue
+	return o
+}
+
+// StartTimestamp is a 'getter' method
+func (o *VolumeMoveInfoType) StartTimestamp() int {
+	r := *o.StartTimestampPtr
+	return r
+}
+
+// SetStartTimestamp is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetStartTimestamp(newValue int) *VolumeMoveInfoType {
+	o.StartTimestampPtr = &newValue
+	return o
+}
+
+// State is a 'getter' method
+func (o *VolumeMoveInfoType) State() string {
+	r := *o.StatePtr
+	return r
+}
+
+// SetState is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetState(newValue string) *VolumeMoveInfoType {
+	o.StatePtr = &newValue
+	return o
+}
+
+// Volume is a 'getter' method
+func (o *VolumeMoveInfoType) Volume() string {
+	r := *o.VolumePtr
+	return r
+}
+
+// SetVolume is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetVolume(newValue string) *VolumeMoveInfoType {
+	o.VolumePtr = &newValue
+	return o
+}
+
+// Vserver is a 'getter' method
+func (o *VolumeMoveInfoType) Vserver() string {
+	r := *o.VserverPtr
+	return r
+}
+
+// SetVserver is a fluent style 'setter' method that can be chained
+func (o *VolumeMoveInfoType) SetVserver(newValue string) *VolumeMoveInfoType {
+	o.VserverPtr = &newValue
+	return o
+}
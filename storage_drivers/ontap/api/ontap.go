@@ -90,6 +90,11 @@ func NewClient(config ClientConfig) *Client {
 	return d
 }
 
+// GetSVMUUID returns the UUID of the SVM managed by this client.
+func (d Client) GetSVMUUID() string {
+	return d.SVMUUID
+}
+
 // GetClonedZapiRunner returns a clone of the ZapiRunner configured on this driver.
 func (d Client) GetClonedZapiRunner() *azgo.ZapiRunner {
 	clone := new(azgo.ZapiRunner)
@@ -371,6 +376,12 @@ func (d Client) SupportsFeature(ctx context.Context, feature feature) bool {
 		return false
 	}
 
+	return ontapiSupportsFeature(ontapiVersion, feature)
+}
+
+// ontapiSupportsFeature returns true if the specified Ontapi version supports the supplied feature
+func ontapiSupportsFeature(ontapiVersion string, feature feature) bool {
+
 	ontapiSemVer, err := utils.ParseSemantic(fmt.Sprintf("%s.0", ontapiVersion))
 	if err != nil {
 		return false
@@ -577,9 +588,15 @@ func (d Client) LunMapAutoID(initiatorGroupName, lunPath string) (*azgo.LunMapRe
 func (d Client) LunMapIfNotMapped(
 	ctx context.Context, initiatorGroupName, lunPath string, importNotManaged bool,
 ) (int, error) {
+	return lunMapIfNotMapped(ctx, d, initiatorGroupName, lunPath, importNotManaged)
+}
+
+func lunMapIfNotMapped(
+	ctx context.Context, client OntapAPI, initiatorGroupName, lunPath string, importNotManaged bool,
+) (int, error) {
 
 	// Read LUN maps to see if the LUN is already mapped to the igroup
-	lunMapListResponse, err := d.LunMapListInfo(lunPath)
+	lunMapListResponse, err := client.LunMapListInfo(lunPath)
 	if err != nil {
 		return -1, fmt.Errorf("problem reading maps for LUN %s: %v", lunPath, err)
 	} else if lunMapListResponse.Result.ResultStatusAttr != "passed" {
@@ -592,7 +609,7 @@ func (d Client) LunMapIfNotMapped(
 		for _, igroup := range lunMapListResponse.Result.InitiatorGroupsPtr.InitiatorGroupInfoPtr {
 			if igroup.InitiatorGroupName() != initiatorGroupName && !importNotManaged {
 				Logc(ctx).Debugf("deleting existing LUN mapping")
-				lunUnmapResponse, err := d.LunUnmap(igroup.InitiatorGroupName(), lunPath)
+				lunUnmapResponse, err := client.LunUnmap(igroup.InitiatorGroupName(), lunPath)
 				if err != nil {
 					return -1, fmt.Errorf("problem deleting map for LUN %s: %+v", lunPath, lunUnmapResponse.Result)
 				}
@@ -615,7 +632,7 @@ func (d Client) LunMapIfNotMapped(
 
 	// Map IFF not already mapped
 	if !alreadyMapped {
-		lunMapResponse, err := d.LunMapAutoID(initiatorGroupName, lunPath)
+		lunMapResponse, err := client.LunMapAutoID(initiatorGroupName, lunPath)
 		if err != nil {
 			return -1, fmt.Errorf("problem mapping LUN %s: %v", lunPath, err)
 		} else if lunMapResponse.Result.ResultStatusAttr != "passed" {
@@ -634,6 +651,108 @@ func (d Client) LunMapIfNotMapped(
 	return lunID, nil
 }
 
+// LunMapToIgroupIfNotMapped maps a LUN to the specified igroup, unless it is already mapped to that igroup,
+// and returns the LUN ID.  Unlike LunMapIfNotMapped, any maps to other igroups are left in place.
+func (d Client) LunMapToIgroupIfNotMapped(ctx context.Context, initiatorGroupName, lunPath string) (int, error) {
+	return lunMapToIgroupIfNotMapped(ctx, d, initiatorGroupName, lunPath)
+}
+
+func lunMapToIgroupIfNotMapped(
+	ctx context.Context, client OntapAPI, initiatorGroupName, lunPath string,
+) (int, error) {
+
+	mappedIgroups, err := client.LunListIgroupsMapped(lunPath)
+	if err != nil {
+		return -1, err
+	}
+
+	if lunID, ok := mappedIgroups[initiatorGroupName]; ok {
+		Logc(ctx).WithFields(log.Fields{
+			"lun":    lunPath,
+			"igroup": initiatorGroupName,
+			"id":     lunID,
+		}).Debug("LUN already mapped.")
+		return lunID, nil
+	}
+
+	lunMapResponse, err := client.LunMapAutoID(initiatorGroupName, lunPath)
+	if err != nil {
+		return -1, fmt.Errorf("problem mapping LUN %s: %v", lunPath, err)
+	} else if lunMapResponse.Result.ResultStatusAttr != "passed" {
+		return -1, fmt.Errorf("problem mapping LUN %s: %+v", lunPath, lunMapResponse.Result)
+	}
+
+	lunID := lunMapResponse.Result.LunIdAssigned()
+
+	Logc(ctx).WithFields(log.Fields{
+		"lun":    lunPath,
+		"igroup": initiatorGroupName,
+		"id":     lunID,
+	}).Debug("LUN mapped.")
+
+	return lunID, nil
+}
+
+// LunUnmapIfMapped deletes the map between a LUN and the specified igroup, if one exists.
+func (d Client) LunUnmapIfMapped(ctx context.Context, initiatorGroupName, lunPath string) error {
+	return lunUnmapIfMapped(ctx, d, initiatorGroupName, lunPath)
+}
+
+func lunUnmapIfMapped(ctx context.Context, client OntapAPI, initiatorGroupName, lunPath string) error {
+
+	mappedIgroups, err := client.LunListIgroupsMapped(lunPath)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := mappedIgroups[initiatorGroupName]; !ok {
+		Logc(ctx).WithFields(log.Fields{
+			"lun":    lunPath,
+			"igroup": initiatorGroupName,
+		}).Debug("LUN not mapped.")
+		return nil
+	}
+
+	lunUnmapResponse, err := client.LunUnmap(initiatorGroupName, lunPath)
+	if err != nil {
+		return fmt.Errorf("problem deleting map for LUN %s: %v", lunPath, err)
+	} else if lunUnmapResponse.Result.ResultStatusAttr != "passed" {
+		return fmt.Errorf("problem deleting map for LUN %s: %+v", lunPath, lunUnmapResponse.Result)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"lun":    lunPath,
+		"igroup": initiatorGroupName,
+	}).Debug("LUN unmapped.")
+
+	return nil
+}
+
+// LunListIgroupsMapped returns the names of the igroups to which a LUN is mapped, along with the LUN ID
+// assigned in each igroup.
+func (d Client) LunListIgroupsMapped(lunPath string) (map[string]int, error) {
+	return lunListIgroupsMapped(d, lunPath)
+}
+
+func lunListIgroupsMapped(client OntapAPI, lunPath string) (map[string]int, error) {
+
+	lunMapListResponse, err := client.LunMapListInfo(lunPath)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading maps for LUN %s: %v", lunPath, err)
+	} else if lunMapListResponse.Result.ResultStatusAttr != "passed" {
+		return nil, fmt.Errorf("problem reading maps for LUN %s: %+v", lunPath, lunMapListResponse.Result)
+	}
+
+	mappedIgroups := make(map[string]int)
+	if lunMapListResponse.Result.InitiatorGroupsPtr != nil {
+		for _, igroup := range lunMapListResponse.Result.InitiatorGroupsPtr.InitiatorGroupInfoPtr {
+			mappedIgroups[igroup.InitiatorGroupName()] = igroup.LunId()
+		}
+	}
+
+	return mappedIgroups, nil
+}
+
 // LunMapListInfo returns lun mapping information for the specified lun
 // equivalent to filer::> lun mapped show -vserver iscsi_vs -path /vol/v/lun0
 func (d Client) LunMapListInfo(lunPath string) (*azgo.LunMapListInfoResponse, error) {
@@ -1102,6 +1221,90 @@ func (d Client) FlexGroupModifyUnixPermissions(
 	return response, err
 }
 
+// flexGroupModifyAttributes applies the supplied volume attributes to a FlexGroup and waits for
+// the asynchronous modify operation to complete.
+func (d Client) flexGroupModifyAttributes(
+	ctx context.Context, volumeName string, volAttrs *azgo.VolumeAttributesType,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	volAttr := &azgo.VolumeModifyIterAsyncRequestAttributes{}
+	volAttr.SetVolumeAttributes(*volAttrs)
+
+	queryAttr := &azgo.VolumeModifyIterAsyncRequestQuery{}
+	volIDAttr := azgo.NewVolumeIdAttributesType().SetName(volumeName)
+	volIDAttrs := azgo.NewVolumeAttributesType().SetVolumeIdAttributes(*volIDAttr)
+	queryAttr.SetVolumeAttributes(*volIDAttrs)
+
+	response, err := azgo.NewVolumeModifyIterAsyncRequest().
+		SetQuery(*queryAttr).
+		SetAttributes(*volAttr).
+		ExecuteUsing(d.zr)
+
+	if zerr := GetError(ctx, response, err); zerr != nil {
+		return response, zerr
+	}
+
+	err = d.WaitForAsyncResponse(ctx, *response, maxFlexGroupWait)
+	if err != nil {
+		return response, fmt.Errorf("error waiting for response: %v", err)
+	}
+
+	return response, err
+}
+
+// FlexGroupModifyExportPolicy sets a FlexGroup's export policy
+func (d Client) FlexGroupModifyExportPolicy(
+	ctx context.Context, volumeName, exportPolicyName string,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+	exportAttributes := azgo.NewVolumeExportAttributesType().SetPolicy(exportPolicyName)
+	return d.flexGroupModifyAttributes(ctx, volumeName,
+		azgo.NewVolumeAttributesType().SetVolumeExportAttributes(*exportAttributes))
+}
+
+// FlexGroupModifySnapshotPolicy sets a FlexGroup's snapshot policy
+func (d Client) FlexGroupModifySnapshotPolicy(
+	ctx context.Context, volumeName, snapshotPolicy string,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+	snapshotAttributes := azgo.NewVolumeSnapshotAttributesType().SetSnapshotPolicy(snapshotPolicy)
+	return d.flexGroupModifyAttributes(ctx, volumeName,
+		azgo.NewVolumeAttributesType().SetVolumeSnapshotAttributes(*snapshotAttributes))
+}
+
+// FlexGroupModifySnapshotReserve sets the percentage of a FlexGroup's space reserved for snapshots
+func (d Client) FlexGroupModifySnapshotReserve(
+	ctx context.Context, volumeName string, snapshotReserve int,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+	spaceAttributes := azgo.NewVolumeSpaceAttributesType().SetPercentageSnapshotReserve(snapshotReserve)
+	return d.flexGroupModifyAttributes(ctx, volumeName,
+		azgo.NewVolumeAttributesType().SetVolumeSpaceAttributes(*spaceAttributes))
+}
+
+// FlexGroupModifyTieringPolicy sets a FlexGroup's tiering policy
+func (d Client) FlexGroupModifyTieringPolicy(
+	ctx context.Context, volumeName, tieringPolicy string,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+	compAggrAttributes := azgo.NewVolumeCompAggrAttributesType().SetTieringPolicy(tieringPolicy)
+	return d.flexGroupModifyAttributes(ctx, volumeName,
+		azgo.NewVolumeAttributesType().SetVolumeCompAggrAttributes(*compAggrAttributes))
+}
+
+// FlexGroupSetQosPolicyGroupName sets a FlexGroup's QoS policy group
+func (d Client) FlexGroupSetQosPolicyGroupName(
+	ctx context.Context, volumeName string, qosPolicyGroup QosPolicyGroup,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	volQosAttr := azgo.NewVolumeQosAttributesType()
+	switch qosPolicyGroup.Kind {
+	case QosPolicyGroupKind:
+		volQosAttr.SetPolicyGroupName(qosPolicyGroup.Name)
+	case QosAdaptivePolicyGroupKind:
+		volQosAttr.SetAdaptivePolicyGroupName(qosPolicyGroup.Name)
+	}
+
+	return d.flexGroupModifyAttributes(ctx, volumeName,
+		azgo.NewVolumeAttributesType().SetVolumeQosAttributes(*volQosAttr))
+}
+
 // FlexGroupSetComment sets a flexgroup's comment to the supplied value
 func (d Client) FlexGroupSetComment(ctx context.Context, volumeName, newVolumeComment string) (
 	*azgo.VolumeModifyIterAsyncResponse, error) {
@@ -1254,7 +1457,7 @@ func (d Client) JobGetIterStatus(jobId int) (*azgo.JobGetIterResponse, error) {
 func (d Client) VolumeCreate(
 	ctx context.Context, name, aggregateName, size, spaceReserve, snapshotPolicy, unixPermissions,
 	exportPolicy, securityStyle, tieringPolicy, comment string, qosPolicyGroup QosPolicyGroup, encrypt bool,
-	snapshotReserve int,
+	snapshotReserve int, dpVolume bool,
 ) (*azgo.VolumeCreateResponse, error) {
 	request := azgo.NewVolumeCreateRequest().
 		SetVolume(name).
@@ -1272,6 +1475,11 @@ func (d Client) VolumeCreate(
 		request.SetPercentageSnapshotReserve(snapshotReserve)
 	}
 
+	// A data protection volume may only be the destination of a SnapMirror relationship
+	if dpVolume {
+		request.SetVolumeType("dp")
+	}
+
 	// Allowed ONTAP tiering Policy values
 	//
 	// =================================================================================
@@ -1347,6 +1555,47 @@ func (d Client) VolumeModifyUnixPermissions(volumeName, unixPermissions string)
 	return response, err
 }
 
+// volumeModifyAttributes applies the supplied volume attributes to a Flexvol
+func (d Client) volumeModifyAttributes(
+	volumeName string, volAttrs *azgo.VolumeAttributesType,
+) (*azgo.VolumeModifyIterResponse, error) {
+	volAttr := &azgo.VolumeModifyIterRequestAttributes{}
+	volAttr.SetVolumeAttributes(*volAttrs)
+
+	queryAttr := &azgo.VolumeModifyIterRequestQuery{}
+	volIDAttr := azgo.NewVolumeIdAttributesType().SetName(azgo.VolumeNameType(volumeName))
+	volIDAttrs := azgo.NewVolumeAttributesType().SetVolumeIdAttributes(*volIDAttr)
+	queryAttr.SetVolumeAttributes(*volIDAttrs)
+
+	response, err := azgo.NewVolumeModifyIterRequest().
+		SetQuery(*queryAttr).
+		SetAttributes(*volAttr).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// VolumeModifySnapshotPolicy sets a Flexvol's snapshot policy
+func (d Client) VolumeModifySnapshotPolicy(volumeName, snapshotPolicy string) (*azgo.VolumeModifyIterResponse, error) {
+	snapshotAttributes := azgo.NewVolumeSnapshotAttributesType().SetSnapshotPolicy(snapshotPolicy)
+	return d.volumeModifyAttributes(volumeName,
+		azgo.NewVolumeAttributesType().SetVolumeSnapshotAttributes(*snapshotAttributes))
+}
+
+// VolumeModifySnapshotReserve sets the percentage of a Flexvol's space reserved for snapshots
+func (d Client) VolumeModifySnapshotReserve(volumeName string, snapshotReserve int) (
+	*azgo.VolumeModifyIterResponse, error) {
+	spaceAttributes := azgo.NewVolumeSpaceAttributesType().SetPercentageSnapshotReserve(snapshotReserve)
+	return d.volumeModifyAttributes(volumeName,
+		azgo.NewVolumeAttributesType().SetVolumeSpaceAttributes(*spaceAttributes))
+}
+
+// VolumeModifyTieringPolicy sets a Flexvol's tiering policy
+func (d Client) VolumeModifyTieringPolicy(volumeName, tieringPolicy string) (*azgo.VolumeModifyIterResponse, error) {
+	compAggrAttributes := azgo.NewVolumeCompAggrAttributesType().SetTieringPolicy(tieringPolicy)
+	return d.volumeModifyAttributes(volumeName,
+		azgo.NewVolumeAttributesType().SetVolumeCompAggrAttributes(*compAggrAttributes))
+}
+
 // VolumeCloneCreate clones a volume from a snapshot
 func (d Client) VolumeCloneCreate(name, source, snapshot string) (*azgo.VolumeCloneCreateResponse, error) {
 	response, err := azgo.NewVolumeCloneCreateRequest().
@@ -1745,6 +1994,52 @@ func (d Client) VolumeSetComment(ctx context.Context, volumeName, newVolumeComme
 	return response, err
 }
 
+const (
+	// Volume move phases
+	VolumeMovePhaseCompleted = "completed"
+	VolumeMovePhaseFailed    = "failed"
+	VolumeMovePhaseAborted   = "aborted"
+	VolumeMovePhaseCutover   = "cutover"
+)
+
+// VolumeMoveStart starts moving a Flexvol to another aggregate in the same cluster.  The volume remains
+// online while ONTAP copies it and then cuts over to the destination aggregate automatically.
+// equivalent to filer::> volume move start -vserver vs -volume v -destination-aggregate aggr
+func (d Client) VolumeMoveStart(volumeName, destinationAggregate string) (*azgo.VolumeMoveStartResponse, error) {
+	response, err := azgo.NewVolumeMoveStartRequest().
+		SetVserver(d.config.SVM).
+		SetSourceVolume(volumeName).
+		SetDestAggr(destinationAggregate).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// VolumeMoveGet returns the status of the most recent move of a Flexvol.
+// equivalent to filer::> volume move show -vserver vs -volume v
+func (d Client) VolumeMoveGet(volumeName string) (*azgo.VolumeMoveInfoType, error) {
+
+	query := &azgo.VolumeMoveGetIterRequestQuery{}
+	info := azgo.NewVolumeMoveInfoType().
+		SetVserver(d.config.SVM).
+		SetVolume(volumeName)
+	query.SetVolumeMoveInfo(*info)
+
+	response, err := azgo.NewVolumeMoveGetIterRequest().
+		SetQuery(*query).
+		ExecuteUsing(d.zr)
+	if err != nil {
+		return nil, err
+	}
+	if zerr := NewZapiError(response.Result); !zerr.IsPassed() {
+		return nil, zerr
+	}
+	if response.Result.AttributesListPtr == nil || len(response.Result.AttributesListPtr.VolumeMoveInfoPtr) == 0 {
+		return nil, utils.NotFoundError(fmt.Sprintf("no move of volume %s found", volumeName))
+	}
+
+	return &response.Result.AttributesListPtr.VolumeMoveInfoPtr[0], nil
+}
+
 // VOLUME operations END
 /////////////////////////////////////////////////////////////////////////////
 
@@ -1949,6 +2244,16 @@ func (d Client) QtreeModifyExportPolicy(name, volumeName, exportPolicy string) (
 		ExecuteUsing(d.zr)
 }
 
+func (d Client) QtreeModifyUnixPermissions(name, volumeName, unixPermissions string) (
+	*azgo.QtreeModifyResponse, error) {
+
+	return azgo.NewQtreeModifyRequest().
+		SetQtree(name).
+		SetVolume(volumeName).
+		SetMode(unixPermissions).
+		ExecuteUsing(d.zr)
+}
+
 // QuotaOn enables quotas on a Flexvol
 // equivalent to filer::> volume quota on
 func (d Client) QuotaOn(volume string) (*azgo.QuotaOnResponse, error) {
@@ -2483,6 +2788,20 @@ func (d Client) AggregateCommitment(ctx context.Context, aggregate string) (*Agg
 /////////////////////////////////////////////////////////////////////////////
 // SNAPMIRROR operations BEGIN
 
+const (
+	SnapmirrorRelationshipTypeXDP = "extended_data_protection"
+
+	// Snapmirror mirror states
+	SnapmirrorStateUninitialized = "uninitialized"
+	SnapmirrorStateSnapmirrored  = "snapmirrored"
+	SnapmirrorStateBrokenOff     = "broken-off"
+
+	// Snapmirror relationship statuses
+	SnapmirrorStatusIdle         = "idle"
+	SnapmirrorStatusQuiesced     = "quiesced"
+	SnapmirrorStatusTransferring = "transferring"
+)
+
 // SnapmirrorGetIterRequest returns the snapmirror operations on the destination cluster
 // equivalent to filer::> snapmirror show
 func (d Client) SnapmirrorGetIterRequest(relGroupType string) (*azgo.SnapmirrorGetIterResponse, error) {
@@ -2515,12 +2834,161 @@ func (d Client) SnapmirrorGetDestinationIterRequest(relGroupType string) (*azgo.
 	return response, err
 }
 
+// SnapmirrorCreate creates a snapmirror relationship from a source volume to a destination volume.
+// This must be called on the destination cluster.
+// equivalent to filer::> snapmirror create
+func (d Client) SnapmirrorCreate(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM, replicationPolicy, replicationSchedule string,
+) (*azgo.SnapmirrorCreateResponse, error) {
+
+	request := azgo.NewSnapmirrorCreateRequest().
+		SetDestinationVolume(destinationVolume).
+		SetDestinationVserver(destinationSVM).
+		SetSourceVolume(sourceVolume).
+		SetSourceVserver(sourceSVM).
+		SetRelationshipType(SnapmirrorRelationshipTypeXDP)
+
+	if replicationPolicy != "" {
+		request.SetPolicy(replicationPolicy)
+	}
+	if replicationSchedule != "" {
+		request.SetSchedule(replicationSchedule)
+	}
+
+	response, err := request.ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorGet returns the snapmirror relationship from a source volume to a destination volume.
+// This must be called on the destination cluster.
+// equivalent to filer::> snapmirror show
+func (d Client) SnapmirrorGet(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+) (*azgo.SnapmirrorInfoType, error) {
+
+	query := &azgo.SnapmirrorGetIterRequestQuery{}
+	info := azgo.NewSnapmirrorInfoType().
+		SetDestinationVolume(destinationVolume).
+		SetDestinationVserver(destinationSVM).
+		SetSourceVolume(sourceVolume).
+		SetSourceVserver(sourceSVM)
+	query.SetSnapmirrorInfo(*info)
+
+	response, err := azgo.NewSnapmirrorGetIterRequest().
+		SetQuery(*query).
+		ExecuteUsing(d.zr)
+	if err != nil {
+		return nil, err
+	}
+	if zerr := NewZapiError(response.Result); !zerr.IsPassed() {
+		return nil, zerr
+	}
+	if response.Result.AttributesListPtr == nil || len(response.Result.AttributesListPtr.SnapmirrorInfoPtr) == 0 {
+		return nil, utils.NotFoundError(fmt.Sprintf("snapmirror relationship from %s:%s to %s:%s not found",
+			sourceSVM, sourceVolume, destinationSVM, destinationVolume))
+	}
+
+	return &response.Result.AttributesListPtr.SnapmirrorInfoPtr[0], nil
+}
+
+// SnapmirrorInitialize starts the initial transfer of a snapmirror relationship.
+// equivalent to filer::> snapmirror initialize
+func (d Client) SnapmirrorInitialize(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+) (*azgo.SnapmirrorInitializeResponse, error) {
+	response, err := azgo.NewSnapmirrorInitializeRequest().
+		SetDestinationVolume(destinationVolume).
+		SetDestinationVserver(destinationSVM).
+		SetSourceVolume(sourceVolume).
+		SetSourceVserver(sourceSVM).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorResync reestablishes a broken snapmirror relationship, discarding any changes made to the
+// destination volume since the latest common snapshot.
+// equivalent to filer::> snapmirror resync
+func (d Client) SnapmirrorResync(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+) (*azgo.SnapmirrorResyncResponse, error) {
+	response, err := azgo.NewSnapmirrorResyncRequest().
+		SetDestinationVolume(destinationVolume).
+		SetDestinationVserver(destinationSVM).
+		SetSourceVolume(sourceVolume).
+		SetSourceVserver(sourceSVM).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorUpdate starts an incremental transfer of a snapmirror relationship.
+// equivalent to filer::> snapmirror update
+func (d Client) SnapmirrorUpdate(destinationVolume, destinationSVM string) (*azgo.SnapmirrorUpdateResponse, error) {
+	response, err := azgo.NewSnapmirrorUpdateRequest().
+		SetDestinationVolume(destinationVolume).
+		SetDestinationVserver(destinationSVM).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorQuiesce disables future transfers of a snapmirror relationship.
+// equivalent to filer::> snapmirror quiesce
+func (d Client) SnapmirrorQuiesce(destinationVolume, destinationSVM string) (*azgo.SnapmirrorQuiesceResponse, error) {
+	response, err := azgo.NewSnapmirrorQuiesceRequest().
+		SetDestinationVolume(destinationVolume).
+		SetDestinationVserver(destinationSVM).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorBreak breaks a quiesced snapmirror relationship, making the destination volume writable.
+// equivalent to filer::> snapmirror break
+func (d Client) SnapmirrorBreak(destinationVolume, destinationSVM string) (*azgo.SnapmirrorBreakResponse, error) {
+	response, err := azgo.NewSnapmirrorBreakRequest().
+		SetDestinationVolume(destinationVolume).
+		SetDestinationVserver(destinationSVM).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorDestroy deletes a snapmirror relationship.  This must be called on the destination cluster.
+// equivalent to filer::> snapmirror delete
+func (d Client) SnapmirrorDestroy(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+) (*azgo.SnapmirrorDestroyResponse, error) {
+	response, err := azgo.NewSnapmirrorDestroyRequest().
+		SetDestinationVolume(destinationVolume).
+		SetDestinationVserver(destinationSVM).
+		SetSourceVolume(sourceVolume).
+		SetSourceVserver(sourceSVM).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
+// SnapmirrorRelease removes the source information of a deleted snapmirror relationship.  This must be
+// called on the source cluster.
+// equivalent to filer::> snapmirror release
+func (d Client) SnapmirrorRelease(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+) (*azgo.SnapmirrorReleaseResponse, error) {
+	response, err := azgo.NewSnapmirrorReleaseRequest().
+		SetDestinationVolume(destinationVolume).
+		SetDestinationVserver(destinationSVM).
+		SetSourceVolume(sourceVolume).
+		SetSourceVserver(sourceSVM).
+		ExecuteUsing(d.zr)
+	return response, err
+}
+
 // IsVserverDRDestination identifies if the Vserver is a destination vserver of Snapmirror relationship (SVM-DR) or not
 func (d Client) IsVserverDRDestination(ctx context.Context) (bool, error) {
+	return isVserverDRDestination(ctx, d)
+}
+
+func isVserverDRDestination(ctx context.Context, client OntapAPI) (bool, error) {
 
 	// first, get the snapmirror destination info using relationship-group-type=vserver in a snapmirror relationship
 	relationshipGroupType := "vserver"
-	response, err := d.SnapmirrorGetIterRequest(relationshipGroupType)
+	response, err := client.SnapmirrorGetIterRequest(relationshipGroupType)
 	isSVMDRDestination := false
 
 	if err != nil {
@@ -2546,10 +3014,14 @@ func (d Client) IsVserverDRDestination(ctx context.Context) (bool, error) {
 
 // IsVserverDRSource identifies if the Vserver is a source vserver of Snapmirror relationship (SVM-DR) or not
 func (d Client) IsVserverDRSource(ctx context.Context) (bool, error) {
+	return isVserverDRSource(ctx, d)
+}
+
+func isVserverDRSource(ctx context.Context, client OntapAPI) (bool, error) {
 
 	// first, get the snapmirror destination info using relationship-group-type=vserver in a snapmirror relationship
 	relationshipGroupType := "vserver"
-	response, err := d.SnapmirrorGetDestinationIterRequest(relationshipGroupType)
+	response, err := client.SnapmirrorGetDestinationIterRequest(relationshipGroupType)
 	isSVMDRSource := false
 
 	if err != nil {
@@ -2574,9 +3046,9 @@ func (d Client) IsVserverDRSource(ctx context.Context) (bool, error) {
 }
 
 // isVserverInSVMDR identifies if the Vserver is in Snapmirror relationship (SVM-DR) or not
-func (d Client) isVserverInSVMDR(ctx context.Context) bool {
-	isSVMDRSource, _ := d.IsVserverDRSource(ctx)
-	isSVMDRDestination, _ := d.IsVserverDRDestination(ctx)
+func isVserverInSVMDR(ctx context.Context, client OntapAPI) bool {
+	isSVMDRSource, _ := client.IsVserverDRSource(ctx)
+	isSVMDRDestination, _ := client.IsVserverDRDestination(ctx)
 
 	return isSVMDRSource || isSVMDRDestination
 }
@@ -2604,7 +3076,11 @@ func (d Client) NetInterfaceGet() (*azgo.NetInterfaceGetIterResponse, error) {
 }
 
 func (d Client) NetInterfaceGetDataLIFsNode(ctx context.Context, ip string) (string, error) {
-	lifResponse, err := d.NetInterfaceGet()
+	return netInterfaceGetDataLIFsNode(ctx, d, ip)
+}
+
+func netInterfaceGetDataLIFsNode(ctx context.Context, client OntapAPI, ip string) (string, error) {
+	lifResponse, err := client.NetInterfaceGet()
 	if err = GetError(ctx, lifResponse, err); err != nil {
 		return "", fmt.Errorf("error checking network interfaces: %v", err)
 	}
@@ -2628,8 +3104,12 @@ func (d Client) NetInterfaceGetDataLIFsNode(ctx context.Context, ip string) (str
 }
 
 func (d Client) NetInterfaceGetDataLIFs(ctx context.Context, protocol string) ([]string, error) {
+	return netInterfaceGetDataLIFs(ctx, d, protocol)
+}
 
-	lifResponse, err := d.NetInterfaceGet()
+func netInterfaceGetDataLIFs(ctx context.Context, client OntapAPI, protocol string) ([]string, error) {
+
+	lifResponse, err := client.NetInterfaceGet()
 	if err = GetError(ctx, lifResponse, err); err != nil {
 		return nil, fmt.Errorf("error checking network interfaces: %v", err)
 	}
@@ -2779,10 +3259,14 @@ func (d Client) EmsAutosupportLog(
 //
 
 func (d Client) TieringPolicyValue(ctx context.Context) string {
+	return tieringPolicyValue(ctx, d)
+}
+
+func tieringPolicyValue(ctx context.Context, client OntapAPI) string {
 	tieringPolicy := "none"
 	// If ONTAP version < 9.5
-	if !d.SupportsFeature(ctx, FabricPoolForSVMDR) {
-		if d.isVserverInSVMDR(ctx) {
+	if !client.SupportsFeature(ctx, FabricPoolForSVMDR) {
+		if isVserverInSVMDR(ctx, client) {
 			tieringPolicy = "snapshot-only"
 		}
 	}
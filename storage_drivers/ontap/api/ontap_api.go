@@ -0,0 +1,246 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+)
+
+// OntapAPI is the set of operations the ONTAP storage drivers invoke on an ONTAP controller.  It is
+// implemented by Client, which uses ZAPI, and by RestClient, which uses the ONTAP REST API.
+type OntapAPI interface {
+	// GetSVMUUID returns the UUID of the SVM managed by this client
+	GetSVMUUID() string
+
+	// API feature operations
+	SupportsFeature(ctx context.Context, feature feature) bool
+
+	// IGROUP operations
+	IgroupCreate(initiatorGroupName, initiatorGroupType, osType string) (*azgo.IgroupCreateResponse, error)
+	IgroupAdd(initiatorGroupName, initiator string) (*azgo.IgroupAddResponse, error)
+	IgroupRemove(initiatorGroupName, initiator string, force bool) (*azgo.IgroupRemoveResponse, error)
+	IgroupDestroy(initiatorGroupName string) (*azgo.IgroupDestroyResponse, error)
+	IgroupList() (*azgo.IgroupGetIterResponse, error)
+	IgroupGet(initiatorGroupName string) (*azgo.InitiatorGroupInfoType, error)
+
+	// LUN operations
+	LunCreate(
+		lunPath string, sizeInBytes int, osType string, qosPolicyGroup QosPolicyGroup, spaceReserved bool,
+		spaceAllocated bool,
+	) (*azgo.LunCreateBySizeResponse, error)
+	LunCloneCreate(
+		volumeName, sourceLun, destinationLun string, qosPolicyGroup QosPolicyGroup,
+	) (*azgo.CloneCreateResponse, error)
+	LunSetQosPolicyGroup(lunPath string, qosPolicyGroup QosPolicyGroup) (*azgo.LunSetQosPolicyGroupResponse, error)
+	LunGetSerialNumber(lunPath string) (*azgo.LunGetSerialNumberResponse, error)
+	LunMapGet(initiatorGroupName, lunPath string) (*azgo.LunMapGetIterResponse, error)
+	LunMap(initiatorGroupName, lunPath string, lunID int) (*azgo.LunMapResponse, error)
+	LunMapAutoID(initiatorGroupName, lunPath string) (*azgo.LunMapResponse, error)
+	LunMapIfNotMapped(ctx context.Context, initiatorGroupName, lunPath string, importNotManaged bool) (int, error)
+	LunMapToIgroupIfNotMapped(ctx context.Context, initiatorGroupName, lunPath string) (int, error)
+	LunUnmapIfMapped(ctx context.Context, initiatorGroupName, lunPath string) error
+	LunListIgroupsMapped(lunPath string) (map[string]int, error)
+	LunMapListInfo(lunPath string) (*azgo.LunMapListInfoResponse, error)
+	LunOffline(lunPath string) (*azgo.LunOfflineResponse, error)
+	LunOnline(lunPath string) (*azgo.LunOnlineResponse, error)
+	LunDestroy(lunPath string) (*azgo.LunDestroyResponse, error)
+	LunSetAttribute(lunPath, name, value string) (*azgo.LunSetAttributeResponse, error)
+	LunGetAttribute(lunPath, name string) (*azgo.LunGetAttributeResponse, error)
+	LunGet(path string) (*azgo.LunInfoType, error)
+	LunGetGeometry(path string) (*azgo.LunGetGeometryResponse, error)
+	LunResize(path string, sizeBytes int) (uint64, error)
+	LunGetAll(pathPattern string) (*azgo.LunGetIterResponse, error)
+	LunGetAllForVolume(volumeName string) (*azgo.LunGetIterResponse, error)
+	LunGetAllForVserver(vserverName string) (*azgo.LunGetIterResponse, error)
+	LunCount(ctx context.Context, volume string) (int, error)
+	LunRename(path, newPath string) (*azgo.LunMoveResponse, error)
+	LunUnmap(initiatorGroupName, lunPath string) (*azgo.LunUnmapResponse, error)
+
+	// FlexGroup operations
+	FlexGroupCreate(
+		ctx context.Context, name string, size int, aggrs []azgo.AggrNameType, spaceReserve, snapshotPolicy,
+		unixPermissions, exportPolicy, securityStyle, tieringPolicy, comment string, qosPolicyGroup QosPolicyGroup,
+		encrypt bool, snapshotReserve int,
+	) (*azgo.VolumeCreateAsyncResponse, error)
+	FlexGroupDestroy(ctx context.Context, name string, force bool) (*azgo.VolumeDestroyAsyncResponse, error)
+	FlexGroupExists(ctx context.Context, name string) (bool, error)
+	FlexGroupSize(name string) (int, error)
+	FlexGroupSetSize(ctx context.Context, name, newSize string) (*azgo.VolumeSizeAsyncResponse, error)
+	FlexGroupVolumeDisableSnapshotDirectoryAccess(
+		ctx context.Context, name string,
+	) (*azgo.VolumeModifyIterAsyncResponse, error)
+	FlexGroupModifyUnixPermissions(
+		ctx context.Context, volumeName, unixPermissions string,
+	) (*azgo.VolumeModifyIterAsyncResponse, error)
+	FlexGroupModifyExportPolicy(
+		ctx context.Context, volumeName, exportPolicyName string,
+	) (*azgo.VolumeModifyIterAsyncResponse, error)
+	FlexGroupModifySnapshotPolicy(
+		ctx context.Context, volumeName, snapshotPolicy string,
+	) (*azgo.VolumeModifyIterAsyncResponse, error)
+	FlexGroupModifySnapshotReserve(
+		ctx context.Context, volumeName string, snapshotReserve int,
+	) (*azgo.VolumeModifyIterAsyncResponse, error)
+	FlexGroupModifyTieringPolicy(
+		ctx context.Context, volumeName, tieringPolicy string,
+	) (*azgo.VolumeModifyIterAsyncResponse, error)
+	FlexGroupSetQosPolicyGroupName(
+		ctx context.Context, volumeName string, qosPolicyGroup QosPolicyGroup,
+	) (*azgo.VolumeModifyIterAsyncResponse, error)
+	FlexGroupSetComment(
+		ctx context.Context, volumeName, newVolumeComment string,
+	) (*azgo.VolumeModifyIterAsyncResponse, error)
+	FlexGroupGet(name string) (*azgo.VolumeAttributesType, error)
+	FlexGroupGetAll(prefix string) (*azgo.VolumeGetIterResponse, error)
+	WaitForAsyncResponse(ctx context.Context, zapiResult interface{}, maxWaitTime time.Duration) error
+
+	// VOLUME operations
+	VolumeCreate(
+		ctx context.Context, name, aggregateName, size, spaceReserve, snapshotPolicy, unixPermissions, exportPolicy,
+		securityStyle, tieringPolicy, comment string, qosPolicyGroup QosPolicyGroup, encrypt bool, snapshotReserve int,
+		dpVolume bool,
+	) (*azgo.VolumeCreateResponse, error)
+	VolumeModifyExportPolicy(volumeName, exportPolicyName string) (*azgo.VolumeModifyIterResponse, error)
+	VolumeModifyUnixPermissions(volumeName, unixPermissions string) (*azgo.VolumeModifyIterResponse, error)
+	VolumeModifySnapshotPolicy(volumeName, snapshotPolicy string) (*azgo.VolumeModifyIterResponse, error)
+	VolumeModifySnapshotReserve(volumeName string, snapshotReserve int) (*azgo.VolumeModifyIterResponse, error)
+	VolumeModifyTieringPolicy(volumeName, tieringPolicy string) (*azgo.VolumeModifyIterResponse, error)
+	VolumeCloneCreate(name, source, snapshot string) (*azgo.VolumeCloneCreateResponse, error)
+	VolumeCloneCreateAsync(name, source, snapshot string) (*azgo.VolumeCloneCreateAsyncResponse, error)
+	VolumeCloneSplitStart(name string) (*azgo.VolumeCloneSplitStartResponse, error)
+	VolumeDisableSnapshotDirectoryAccess(name string) (*azgo.VolumeModifyIterResponse, error)
+	VolumeSetQosPolicyGroupName(name string, qosPolicyGroup QosPolicyGroup) (*azgo.VolumeModifyIterResponse, error)
+	VolumeExists(ctx context.Context, name string) (bool, error)
+	VolumeSize(name string) (int, error)
+	VolumeSetSize(name, newSize string) (*azgo.VolumeSizeResponse, error)
+	VolumeMount(name, junctionPath string) (*azgo.VolumeMountResponse, error)
+	VolumeUnmount(name string, force bool) (*azgo.VolumeUnmountResponse, error)
+	VolumeOffline(name string) (*azgo.VolumeOfflineResponse, error)
+	VolumeDestroy(name string, force bool) (*azgo.VolumeDestroyResponse, error)
+	VolumeGet(name string) (*azgo.VolumeAttributesType, error)
+	VolumeGetAll(prefix string) (*azgo.VolumeGetIterResponse, error)
+	VolumeList(prefix string) (*azgo.VolumeGetIterResponse, error)
+	VolumeListByAttrs(
+		prefix, aggregate, spaceReserve, snapshotPolicy, tieringPolicy string, snapshotDir bool, encrypt bool,
+	) (*azgo.VolumeGetIterResponse, error)
+	VolumeListAllBackedBySnapshot(ctx context.Context, volumeName, snapshotName string) ([]string, error)
+	VolumeRename(volumeName, newVolumeName string) (*azgo.VolumeRenameResponse, error)
+	VolumeSetComment(ctx context.Context, volumeName, newVolumeComment string) (*azgo.VolumeModifyIterResponse, error)
+	VolumeMoveStart(volumeName, destinationAggregate string) (*azgo.VolumeMoveStartResponse, error)
+	VolumeMoveGet(volumeName string) (*azgo.VolumeMoveInfoType, error)
+
+	// QTREE operations
+	QtreeCreate(
+		name, volumeName, unixPermissions, exportPolicy, securityStyle, qosPolicy string,
+	) (*azgo.QtreeCreateResponse, error)
+	QtreeRename(path, newPath string) (*azgo.QtreeRenameResponse, error)
+	QtreeDestroyAsync(path string, force bool) (*azgo.QtreeDeleteAsyncResponse, error)
+	QtreeList(prefix, volumePrefix string) (*azgo.QtreeListIterResponse, error)
+	QtreeCount(ctx context.Context, volume string) (int, error)
+	QtreeExists(ctx context.Context, name, volumePrefix string) (bool, string, error)
+	QtreeGet(name, volumePrefix string) (*azgo.QtreeInfoType, error)
+	QtreeGetAll(volumePrefix string) (*azgo.QtreeListIterResponse, error)
+	QtreeModifyExportPolicy(name, volumeName, exportPolicy string) (*azgo.QtreeModifyResponse, error)
+	QtreeModifyUnixPermissions(name, volumeName, unixPermissions string) (*azgo.QtreeModifyResponse, error)
+	QuotaOn(volume string) (*azgo.QuotaOnResponse, error)
+	QuotaOff(volume string) (*azgo.QuotaOffResponse, error)
+	QuotaResize(volume string) (*azgo.QuotaResizeResponse, error)
+	QuotaStatus(volume string) (*azgo.QuotaStatusResponse, error)
+	QuotaSetEntry(qtreeName, volumeName, quotaTarget, quotaType, diskLimit string) (*azgo.QuotaSetEntryResponse, error)
+	QuotaGetEntry(target string) (*azgo.QuotaEntryType, error)
+	QuotaEntryList(volume string) (*azgo.QuotaListEntriesIterResponse, error)
+
+	// EXPORT POLICY operations
+	ExportPolicyCreate(policy string) (*azgo.ExportPolicyCreateResponse, error)
+	ExportPolicyGet(policy string) (*azgo.ExportPolicyGetResponse, error)
+	ExportPolicyDestroy(policy string) (*azgo.ExportPolicyDestroyResponse, error)
+	ExportRuleCreate(
+		policy, clientMatch string, protocols, roSecFlavors, rwSecFlavors, suSecFlavors []string,
+	) (*azgo.ExportRuleCreateResponse, error)
+	ExportRuleGetIterRequest(policy string) (*azgo.ExportRuleGetIterResponse, error)
+	ExportRuleDestroy(policy string, ruleIndex int) (*azgo.ExportRuleDestroyResponse, error)
+
+	// SNAPSHOT operations
+	SnapshotCreate(snapshotName, volumeName string) (*azgo.SnapshotCreateResponse, error)
+	SnapshotList(volumeName string) (*azgo.SnapshotGetIterResponse, error)
+	SnapshotRestoreVolume(snapshotName, volumeName string) (*azgo.SnapshotRestoreVolumeResponse, error)
+	SnapshotDelete(snapshotName, volumeName string) (*azgo.SnapshotDeleteResponse, error)
+
+	// ISCSI operations
+	IscsiServiceGetIterRequest() (*azgo.IscsiServiceGetIterResponse, error)
+	IscsiNodeGetNameRequest() (*azgo.IscsiNodeGetNameResponse, error)
+	IscsiInterfaceGetIterRequest() (*azgo.IscsiInterfaceGetIterResponse, error)
+
+	// VSERVER operations
+	VserverGetIterRequest() (*azgo.VserverGetIterResponse, error)
+	VserverGetIterAdminRequest() (*azgo.VserverGetIterResponse, error)
+	VserverGetRequest() (*azgo.VserverGetResponse, error)
+	VserverGetAggregateNames() ([]string, error)
+	VserverShowAggrGetIterRequest() (*azgo.VserverShowAggrGetIterResponse, error)
+
+	// AGGREGATE operations
+	AggrSpaceGetIterRequest(aggregateName string) (*azgo.AggrSpaceGetIterResponse, error)
+	AggregateCommitment(ctx context.Context, aggregate string) (*AggregateCommitment, error)
+
+	// SNAPMIRROR operations
+	SnapmirrorGetIterRequest(relGroupType string) (*azgo.SnapmirrorGetIterResponse, error)
+	SnapmirrorGetDestinationIterRequest(relGroupType string) (*azgo.SnapmirrorGetDestinationIterResponse, error)
+	SnapmirrorCreate(
+		destinationVolume, destinationSVM, sourceVolume, sourceSVM, replicationPolicy, replicationSchedule string,
+	) (*azgo.SnapmirrorCreateResponse, error)
+	SnapmirrorGet(destinationVolume, destinationSVM, sourceVolume, sourceSVM string) (*azgo.SnapmirrorInfoType, error)
+	SnapmirrorInitialize(
+		destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+	) (*azgo.SnapmirrorInitializeResponse, error)
+	SnapmirrorResync(
+		destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+	) (*azgo.SnapmirrorResyncResponse, error)
+	SnapmirrorUpdate(destinationVolume, destinationSVM string) (*azgo.SnapmirrorUpdateResponse, error)
+	SnapmirrorQuiesce(destinationVolume, destinationSVM string) (*azgo.SnapmirrorQuiesceResponse, error)
+	SnapmirrorBreak(destinationVolume, destinationSVM string) (*azgo.SnapmirrorBreakResponse, error)
+	SnapmirrorDestroy(
+		destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+	) (*azgo.SnapmirrorDestroyResponse, error)
+	SnapmirrorRelease(
+		destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+	) (*azgo.SnapmirrorReleaseResponse, error)
+	IsVserverDRDestination(ctx context.Context) (bool, error)
+	IsVserverDRSource(ctx context.Context) (bool, error)
+
+	// MISC operations
+	NetInterfaceGet() (*azgo.NetInterfaceGetIterResponse, error)
+	NetInterfaceGetDataLIFsNode(ctx context.Context, ip string) (string, error)
+	NetInterfaceGetDataLIFs(ctx context.Context, protocol string) ([]string, error)
+	SystemGetVersion() (*azgo.SystemGetVersionResponse, error)
+	SystemGetOntapiVersion(ctx context.Context) (string, error)
+	NodeListSerialNumbers(ctx context.Context) ([]string, error)
+	EmsAutosupportLog(
+		appVersion string, autoSupport bool, category string, computerName string, eventDescription string, eventID int,
+		eventSource string, logLevel int,
+	) (*azgo.EmsAutosupportLogResponse, error)
+	TieringPolicyValue(ctx context.Context) string
+
+	// iSCSI initiator operations
+	IscsiInitiatorAddAuth(
+		initiator, authType, userName, passphrase, outboundUserName, outboundPassphrase string,
+	) (*azgo.IscsiInitiatorAddAuthResponse, error)
+	IscsiInitiatorAuthGetIter() ([]azgo.IscsiSecurityEntryInfoType, error)
+	IscsiInitiatorDeleteAuth(initiator string) (*azgo.IscsiInitiatorDeleteAuthResponse, error)
+	IscsiInitiatorGetAuth(initiator string) (*azgo.IscsiInitiatorGetAuthResponse, error)
+	IscsiInitiatorGetDefaultAuth() (*azgo.IscsiInitiatorGetDefaultAuthResponse, error)
+	IscsiInitiatorGetIter() ([]azgo.IscsiInitiatorListEntryInfoType, error)
+	IscsiInitiatorModifyCHAPParams(
+		initiator, userName, passphrase, outboundUserName, outboundPassphrase string,
+	) (*azgo.IscsiInitiatorModifyChapParamsResponse, error)
+	IscsiInitiatorSetDefaultAuth(
+		authType, userName, passphrase, outboundUserName, outboundPassphrase string,
+	) (*azgo.IscsiInitiatorSetDefaultAuthResponse, error)
+}
+
+var (
+	_ OntapAPI = &Client{}
+	_ OntapAPI = &RestClient{}
+)
@@ -0,0 +1,4277 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+
+	tridentconfig "github.com/netapp/trident/config"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	maxRestJobWait = 120 * time.Second
+
+	restVolumeFields = "uuid,name,style,type,state,comment,size,aggregates.name,guarantee.type,nas.path," +
+		"nas.export_policy.name,nas.security_style,nas.unix_permissions,snapshot_policy.name," +
+		"snapshot_directory_access_enabled,space.used,space.available,space.snapshot.reserve_percent," +
+		"space.snapshot.used,tiering.policy,qos.policy.name,encryption.enabled,svm.name,clone.is_flexclone," +
+		"clone.parent_volume.name,clone.parent_snapshot.name,clone.split_initiated"
+	restLunFields = "uuid,name,location.volume.name,location.qtree.name,os_type,space.size,serial_number," +
+		"status.state,status.mapped,create_time,qos_policy.name,svm.name"
+	restIgroupFields = "uuid,name,protocol,os_type,initiators.name,lun_maps.logical_unit_number," +
+		"lun_maps.lun.name,svm.name"
+	restQtreeFields      = "id,name,volume.name,volume.uuid,security_style,unix_permissions,export_policy.name,svm.name"
+	restQuotaRuleFields  = "uuid,volume.name,qtree.name,type,space.hard_limit,svm.name"
+	restExportRuleFields = "index,clients.match,protocols,ro_rule,rw_rule,superuser"
+	restSVMFields        = "uuid,name,state,aggregates.name"
+	restInterfaceFields  = "name,ip.address,location.node.name,location.port.name,enabled,state,services,svm.name"
+	restSnapmirrorFields = "uuid,source.path,source.svm.name,destination.path,destination.svm.name,policy.name," +
+		"transfer_schedule.name,state,healthy,unhealthy_reason.message,transfer.state"
+	restIscsiCredentialsFields = "svm.name,initiator,authentication_type,chap.inbound.user,chap.outbound.user"
+
+	// iSCSI targets listen on the well-known port, which the REST API doesn't report
+	iscsiTargetPort = 3260
+)
+
+// RestClient is the object to use for interacting with ONTAP controllers via the ONTAP REST API.  Every
+// operation is sent over REST, so the client may be used with clusters on which ZAPI is disabled.  Responses
+// are returned as the same AZGO objects that Client returns, so that the drivers may use either one.
+type RestClient struct {
+	config        ClientConfig
+	httpClient    *http.Client
+	baseURL       string
+	jobs          map[int]string
+	jobsLock      *sync.Mutex
+	lastJobID     int
+	ontapiVersion string
+	versionLock   *sync.Mutex
+	SVMUUID       string
+}
+
+// NewRestClient is a factory method for creating a new REST client
+func NewRestClient(config ClientConfig) (*RestClient, error) {
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true, MinVersion: tridentconfig.MinTLSVersion}
+
+	// Check to use cert/key and load the cert pair
+	if config.ClientCertificate != "" && config.ClientPrivateKey != "" {
+		certDecode, err := base64.StdEncoding.DecodeString(config.ClientCertificate)
+		if err != nil {
+			return nil, errors.New("failed to decode client certificate from base64")
+		}
+		keyDecode, err := base64.StdEncoding.DecodeString(config.ClientPrivateKey)
+		if err != nil {
+			return nil, errors.New("failed to decode private key from base64")
+		}
+		cert, err := tls.X509KeyPair(certDecode, keyDecode)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load certificate and key; %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// Check to use trustedCACertificate to use InsecureSkipVerify or not
+	if config.TrustedCACertificate != "" {
+		trustedCACert, err := base64.StdEncoding.DecodeString(config.TrustedCACertificate)
+		if err != nil {
+			return nil, errors.New("failed to decode trusted CA certificate from base64")
+		}
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(trustedCACert)
+		tlsConfig.RootCAs = caCertPool
+		tlsConfig.InsecureSkipVerify = false
+	}
+
+	return &RestClient{
+		config: config,
+		httpClient: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+			Timeout:   time.Duration(tridentconfig.StorageAPITimeoutSeconds * time.Second),
+		},
+		baseURL:     "https://" + config.ManagementLIF,
+		jobs:        make(map[int]string),
+		jobsLock:    &sync.Mutex{},
+		versionLock: &sync.Mutex{},
+	}, nil
+}
+
+// RestError describes an unsuccessful ONTAP REST API request.  StatusCode is the HTTP status of the
+// response, or zero if the error was reported by a job.
+type RestError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e RestError) Error() string {
+	return fmt.Sprintf("API status: %d, Reason: %s, Code: %s", e.StatusCode, e.Message, e.Code)
+}
+
+func restNotFoundError(objectType, name string) RestError {
+	return RestError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("%s %s not found", objectType, name),
+	}
+}
+
+// restReference is the representation of a named REST object embedded in another object.
+type restReference struct {
+	Name string `json:"name,omitempty"`
+	UUID string `json:"uuid,omitempty"`
+}
+
+type restCollection struct {
+	Records    []json.RawMessage `json:"records"`
+	NumRecords int               `json:"num_records"`
+	Links      *struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"_links"`
+}
+
+type restJobReference struct {
+	Job *restReference `json:"job"`
+}
+
+type restJob struct {
+	UUID    string `json:"uuid"`
+	State   string `json:"state"`
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+type restErrorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
+
+// sendRequest sends one request to the REST API and decodes the response body, if any, into result.
+func (c *RestClient) sendRequest(method, path string, query url.Values, body, result interface{}) error {
+
+	requestURL, err := url.Parse(c.baseURL + path)
+	if err != nil {
+		return err
+	}
+	if len(query) > 0 {
+		values := requestURL.Query()
+		for key, value := range query {
+			values[key] = value
+		}
+		requestURL.RawQuery = values.Encode()
+	}
+
+	var requestBody io.Reader
+	var requestBytes []byte
+	if body != nil {
+		if requestBytes, err = json.Marshal(body); err != nil {
+			return err
+		}
+		requestBody = bytes.NewReader(requestBytes)
+	}
+
+	request, err := http.NewRequest(method, requestURL.String(), requestBody)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if c.config.ClientCertificate == "" || c.config.ClientPrivateKey == "" {
+		request.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	if c.config.DebugTraceFlags["api"] {
+		log.Debugf("sending %s to '%s': %s", method, requestURL.String(), string(requestBytes))
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	responseBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if c.config.DebugTraceFlags["api"] {
+		log.Debugf("response status: %s, body: %s", response.Status, string(responseBytes))
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		restErr := RestError{StatusCode: response.StatusCode, Message: response.Status}
+		var errorResponse restErrorResponse
+		if json.Unmarshal(responseBytes, &errorResponse) == nil && errorResponse.Error.Message != "" {
+			restErr.Code = errorResponse.Error.Code
+			restErr.Message = errorResponse.Error.Message
+		}
+		return restErr
+	}
+
+	if result != nil && len(responseBytes) > 0 {
+		return json.Unmarshal(responseBytes, result)
+	}
+	return nil
+}
+
+// getRecords reads all records of a REST collection, following the links to any subsequent pages, and
+// decodes them into records, which must be a pointer to a slice.
+func (c *RestClient) getRecords(path string, query url.Values, records interface{}) error {
+
+	allRecords := make([]json.RawMessage, 0)
+
+	for path != "" {
+		var page restCollection
+		if err := c.sendRequest(http.MethodGet, path, query, nil, &page); err != nil {
+			return err
+		}
+		allRecords = append(allRecords, page.Records...)
+
+		// The link to the next page already includes the query
+		path, query = "", nil
+		if page.Links != nil && page.Links.Next != nil {
+			path = page.Links.Next.Href
+		}
+	}
+
+	recordBytes, err := json.Marshal(allRecords)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(recordBytes, records)
+}
+
+// startJob sends a request that may be completed by an ONTAP job, and returns the job's UUID if there is one.
+func (c *RestClient) startJob(method, path string, query url.Values, body interface{}) (string, error) {
+
+	var response restJobReference
+	if err := c.sendRequest(method, path, query, body, &response); err != nil {
+		return "", err
+	}
+	if response.Job == nil {
+		return "", nil
+	}
+	return response.Job.UUID, nil
+}
+
+// invoke sends a request that may be completed by an ONTAP job, and waits for any such job to finish.
+func (c *RestClient) invoke(method, path string, query url.Values, body interface{}) error {
+
+	jobUUID, err := c.startJob(method, path, query, body)
+	if err != nil || jobUUID == "" {
+		return err
+	}
+	return c.waitForJob(jobUUID, maxRestJobWait)
+}
+
+// waitForJob polls for the completion of an ONTAP job with backoff retry logic
+func (c *RestClient) waitForJob(jobUUID string, maxWaitTime time.Duration) error {
+
+	checkJobFinished := func() error {
+		var job restJob
+		if err := c.sendRequest(http.MethodGet, "/api/cluster/jobs/"+jobUUID, nil, nil, &job); err != nil {
+			return fmt.Errorf("error getting status of job %s: %v", jobUUID, err)
+		}
+		switch job.State {
+		case "success":
+			return nil
+		case "failure":
+			// Return a permanent error to halt backoff
+			return backoff.Permanent(RestError{Code: strconv.Itoa(job.Code), Message: job.Message})
+		default:
+			return fmt.Errorf("job %s is not yet completed; state: %s", jobUUID, job.State)
+		}
+	}
+
+	jobNotify := func(err error, duration time.Duration) {
+		log.WithField("duration", duration).Debug("Job not yet completed, waiting.")
+	}
+
+	return backoff.RetryNotify(checkJobFinished, asyncResponseBackoff(maxWaitTime), jobNotify)
+}
+
+// registerJob assigns a numeric ID to an ONTAP job, so that it may be reported in a ZAPI async response.
+func (c *RestClient) registerJob(jobUUID string) int {
+
+	c.jobsLock.Lock()
+	defer c.jobsLock.Unlock()
+
+	c.lastJobID++
+	c.jobs[c.lastJobID] = jobUUID
+	return c.lastJobID
+}
+
+// WaitForAsyncResponse handles waiting for an AsyncResponse to return successfully or return an error.
+func (c *RestClient) WaitForAsyncResponse(
+	ctx context.Context, zapiResult interface{}, maxWaitTime time.Duration,
+) error {
+
+	asyncResult, err := NewZapiAsyncResult(ctx, zapiResult)
+	if err != nil {
+		return err
+	}
+
+	switch asyncResult.status {
+	case "in_progress":
+		c.jobsLock.Lock()
+		jobUUID, ok := c.jobs[asyncResult.jobId]
+		delete(c.jobs, asyncResult.jobId)
+		c.jobsLock.Unlock()
+
+		if !ok {
+			return fmt.Errorf("job ID %d not found", asyncResult.jobId)
+		}
+		if err = c.waitForJob(jobUUID, maxWaitTime); err != nil {
+			Logc(ctx).WithField("job", jobUUID).Warnf("Job not completed; %v", err)
+			return fmt.Errorf("job %s failed to complete successfully", jobUUID)
+		}
+		Logc(ctx).WithField("job", jobUUID).Debug("Job completed successfully.")
+	case "failed":
+		return fmt.Errorf("result status is failed with errorCode %d", asyncResult.errorCode)
+	}
+
+	return nil
+}
+
+// setResult records the outcome of a REST request in the status, reason and error number attributes of a
+// ZAPI response's Result, so that callers may check it exactly as they would check a ZAPI response.  A REST
+// API error is reported as a failed result, using the specified ZAPI error code if the object was not found.
+// Any other error is returned as is.
+func setResult(err error, notFoundErrno string, status, reason, errno *string) error {
+
+	if err == nil {
+		*status = "passed"
+		return nil
+	}
+
+	restErr, ok := err.(RestError)
+	if !ok {
+		return err
+	}
+
+	*errno = restErr.Code
+	switch restErr.StatusCode {
+	case http.StatusNotFound:
+		*errno = notFoundErrno
+	case http.StatusConflict:
+		*errno = azgo.EDUPLICATEENTRY
+	}
+	if *errno == "" {
+		*errno = azgo.EAPIERROR
+	}
+
+	*status = "failed"
+	*reason = restErr.Message
+	return nil
+}
+
+// setAsyncResult is like setResult, but for ZAPI async responses.  REST requests are complete by the time their
+// outcome is recorded, so the result of a successful request reports that the operation has succeeded.
+func setAsyncResult(err error, notFoundErrno string, status, reason, errno *string, resultStatus **string) error {
+
+	if err = setResult(err, notFoundErrno, status, reason, errno); err != nil {
+		return err
+	}
+
+	if *status == "passed" {
+		succeeded := "succeeded"
+		*resultStatus = &succeeded
+	}
+	return nil
+}
+
+// query returns the query parameters for a request in the client's SVM that returns the specified fields and
+// matches the specified name/value pairs.
+func (c *RestClient) query(fields string, filters ...string) url.Values {
+
+	query := url.Values{}
+	query.Set("svm.name", c.config.SVM)
+	if fields != "" {
+		query.Set("fields", fields)
+	}
+	for i := 0; i+1 < len(filters); i += 2 {
+		query.Set(filters[i], filters[i+1])
+	}
+	return query
+}
+
+func (c *RestClient) svm() *restReference {
+	return &restReference{Name: c.config.SVM}
+}
+
+// restUnixPermissions converts UNIX permissions, expressed either as an octal string such as "0755" or as a
+// symbolic string such as "---rwxr-xr-x", to the form used by the REST API, which is an integer whose decimal
+// digits are the octal digits of the permissions.
+func restUnixPermissions(permissions string) (int, error) {
+
+	var mode uint64
+	var err error
+
+	if strings.Trim(permissions, "-rwxstST") == "" && len(permissions) >= 9 {
+		for _, char := range permissions {
+			mode <<= 1
+			if char != '-' {
+				mode |= 1
+			}
+		}
+	} else if mode, err = strconv.ParseUint(permissions, 8, 32); err != nil {
+		return 0, fmt.Errorf("invalid UNIX permissions '%s'", permissions)
+	}
+
+	return strconv.Atoi(strconv.FormatUint(mode, 8))
+}
+
+// zapiUnixPermissions converts UNIX permissions from the form used by the REST API to an octal string.
+func zapiUnixPermissions(permissions int) string {
+	return fmt.Sprintf("%04d", permissions)
+}
+
+// restTieringPolicy converts a tiering policy name such as "snapshot-only" to the form used by the REST API.
+func restTieringPolicy(tieringPolicy string) string {
+	return strings.ReplaceAll(tieringPolicy, "-", "_")
+}
+
+func zapiTieringPolicy(tieringPolicy string) string {
+	return strings.ReplaceAll(tieringPolicy, "_", "-")
+}
+
+// restSize converts a size, which may include a units suffix, to a number of bytes.
+func restSize(size string) (int, error) {
+
+	sizeBytes, err := utils.ConvertSizeToBytes(size)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(sizeBytes)
+}
+
+// restTimestamp converts a REST API timestamp to seconds since the epoch.
+func restTimestamp(timestamp string) int {
+
+	if t, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		return int(t.Unix())
+	}
+	return 0
+}
+
+// splitQtreePath returns the volume and qtree names from a qtree path of the form /vol/<volume>/<qtree>.
+func splitQtreePath(path string) (string, string, error) {
+
+	elements := strings.Split(strings.TrimPrefix(path, "/vol/"), "/")
+	if len(elements) != 2 || elements[0] == "" {
+		return "", "", fmt.Errorf("invalid qtree path %s", path)
+	}
+	return elements[0], elements[1], nil
+}
+
+/////////////////////////////////////////////////////////////////////////////
+// IGROUP operations BEGIN
+
+type restIgroup struct {
+	UUID       string             `json:"uuid,omitempty"`
+	Name       string             `json:"name,omitempty"`
+	SVM        *restReference     `json:"svm,omitempty"`
+	Protocol   string             `json:"protocol,omitempty"`
+	OsType     string             `json:"os_type,omitempty"`
+	Initiators []restReference    `json:"initiators,omitempty"`
+	LunMaps    []restIgroupLunMap `json:"lun_maps,omitempty"`
+}
+
+type restIgroupLunMap struct {
+	LogicalUnitNumber int           `json:"logical_unit_number"`
+	Lun               restReference `json:"lun"`
+}
+
+func (ig *restIgroup) hasInitiator(initiator string) bool {
+	for _, i := range ig.Initiators {
+		if i.Name == initiator {
+			return true
+		}
+	}
+	return false
+}
+
+func (ig *restIgroup) toInitiatorGroupInfo() azgo.InitiatorGroupInfoType {
+
+	initiators := make([]azgo.InitiatorInfoType, 0)
+	for _, initiator := range ig.Initiators {
+		initiators = append(initiators, *azgo.NewInitiatorInfoType().SetInitiatorName(initiator.Name))
+	}
+
+	info := azgo.NewInitiatorGroupInfoType().
+		SetInitiatorGroupName(ig.Name).
+		SetInitiatorGroupUuid(ig.UUID).
+		SetInitiatorGroupType(ig.Protocol).
+		SetInitiatorGroupOsType(ig.OsType)
+	info.InitiatorsPtr = &azgo.InitiatorGroupInfoTypeInitiators{InitiatorInfoPtr: initiators}
+	if ig.SVM != nil {
+		info.SetVserver(ig.SVM.Name)
+	}
+	return *info
+}
+
+// igroupGet returns the named igroup, or a not found error if it doesn't exist
+func (c *RestClient) igroupGet(initiatorGroupName string) (*restIgroup, error) {
+
+	var igroups []restIgroup
+	err := c.getRecords("/api/protocols/san/igroups", c.query(restIgroupFields, "name", initiatorGroupName), &igroups)
+	if err != nil {
+		return nil, err
+	} else if len(igroups) == 0 {
+		return nil, restNotFoundError("igroup", initiatorGroupName)
+	}
+	return &igroups[0], nil
+}
+
+func (c *RestClient) IgroupCreate(
+	initiatorGroupName, initiatorGroupType, osType string,
+) (*azgo.IgroupCreateResponse, error) {
+
+	response := &azgo.IgroupCreateResponse{}
+
+	_, err := c.igroupGet(initiatorGroupName)
+	if err == nil {
+		err = RestError{Code: azgo.EVDISK_ERROR_INITGROUP_EXISTS,
+			Message: fmt.Sprintf("igroup %s already exists", initiatorGroupName)}
+	} else if restErr, ok := err.(RestError); ok && restErr.StatusCode == http.StatusNotFound {
+		err = c.invoke(http.MethodPost, "/api/protocols/san/igroups", nil, &restIgroup{
+			Name:     initiatorGroupName,
+			SVM:      c.svm(),
+			Protocol: initiatorGroupType,
+			OsType:   osType,
+		})
+	}
+
+	return response, setResult(err, azgo.EVDISK_ERROR_NO_SUCH_INITGROUP,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IgroupAdd adds an initiator to an initiator group
+func (c *RestClient) IgroupAdd(initiatorGroupName, initiator string) (*azgo.IgroupAddResponse, error) {
+
+	response := &azgo.IgroupAddResponse{}
+
+	igroup, err := c.igroupGet(initiatorGroupName)
+	if err == nil {
+		if igroup.hasInitiator(initiator) {
+			err = RestError{Code: azgo.EVDISK_ERROR_INITGROUP_HAS_NODE,
+				Message: fmt.Sprintf("initiator %s is already in igroup %s", initiator, initiatorGroupName)}
+		} else {
+			err = c.invoke(http.MethodPost, "/api/protocols/san/igroups/"+igroup.UUID+"/initiators", nil,
+				&restReference{Name: initiator})
+		}
+	}
+
+	return response, setResult(err, azgo.EVDISK_ERROR_NO_SUCH_INITGROUP,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IgroupRemove removes an initiator from an initiator group
+func (c *RestClient) IgroupRemove(
+	initiatorGroupName, initiator string, force bool,
+) (*azgo.IgroupRemoveResponse, error) {
+
+	response := &azgo.IgroupRemoveResponse{}
+
+	igroup, err := c.igroupGet(initiatorGroupName)
+	if err == nil {
+		if !igroup.hasInitiator(initiator) {
+			err = RestError{Code: azgo.EVDISK_ERROR_NODE_NOT_IN_INITGROUP,
+				Message: fmt.Sprintf("initiator %s is not in igroup %s", initiator, initiatorGroupName)}
+		} else {
+			query := url.Values{"allow_delete_while_mapped": {strconv.FormatBool(force)}}
+			err = c.invoke(http.MethodDelete,
+				"/api/protocols/san/igroups/"+igroup.UUID+"/initiators/"+url.PathEscape(initiator), query, nil)
+		}
+	}
+
+	return response, setResult(err, azgo.EVDISK_ERROR_NO_SUCH_INITGROUP,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IgroupDestroy destroys an initiator group
+func (c *RestClient) IgroupDestroy(initiatorGroupName string) (*azgo.IgroupDestroyResponse, error) {
+
+	response := &azgo.IgroupDestroyResponse{}
+
+	igroup, err := c.igroupGet(initiatorGroupName)
+	if err == nil {
+		if len(igroup.LunMaps) > 0 {
+			err = RestError{Code: azgo.EVDISK_ERROR_INITGROUP_MAPS_EXIST,
+				Message: fmt.Sprintf("igroup %s has LUN maps", initiatorGroupName)}
+		} else {
+			err = c.invoke(http.MethodDelete, "/api/protocols/san/igroups/"+igroup.UUID, nil, nil)
+		}
+	}
+
+	return response, setResult(err, azgo.EVDISK_ERROR_NO_SUCH_INITGROUP,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IgroupList lists initiator groups
+func (c *RestClient) IgroupList() (*azgo.IgroupGetIterResponse, error) {
+
+	response := &azgo.IgroupGetIterResponse{}
+
+	var igroups []restIgroup
+	err := c.getRecords("/api/protocols/san/igroups", c.query(restIgroupFields), &igroups)
+	if err == nil {
+		infos := make([]azgo.InitiatorGroupInfoType, 0)
+		for _, igroup := range igroups {
+			infos = append(infos, igroup.toInitiatorGroupInfo())
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = &azgo.IgroupGetIterResponseResultAttributesList{
+			InitiatorGroupInfoPtr: infos,
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IgroupGet gets a specified initiator group
+func (c *RestClient) IgroupGet(initiatorGroupName string) (*azgo.InitiatorGroupInfoType, error) {
+
+	igroup, err := c.igroupGet(initiatorGroupName)
+	if err != nil {
+		return &azgo.InitiatorGroupInfoType{}, err
+	}
+	info := igroup.toInitiatorGroupInfo()
+	return &info, nil
+}
+
+// IGROUP operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// LUN operations BEGIN
+
+type restLun struct {
+	UUID         string           `json:"uuid,omitempty"`
+	Name         string           `json:"name,omitempty"`
+	SVM          *restReference   `json:"svm,omitempty"`
+	Location     *restLunLocation `json:"location,omitempty"`
+	OsType       string           `json:"os_type,omitempty"`
+	Space        *restLunSpace    `json:"space,omitempty"`
+	SerialNumber string           `json:"serial_number,omitempty"`
+	Enabled      *bool            `json:"enabled,omitempty"`
+	Status       *restLunStatus   `json:"status,omitempty"`
+	CreateTime   string           `json:"create_time,omitempty"`
+	QosPolicy    *restReference   `json:"qos_policy,omitempty"`
+	Clone        *restLunClone    `json:"clone,omitempty"`
+}
+
+type restLunLocation struct {
+	Volume *restReference `json:"volume,omitempty"`
+	Qtree  *restReference `json:"qtree,omitempty"`
+}
+
+type restLunSpace struct {
+	Size                               int               `json:"size,omitempty"`
+	Guarantee                          *restLunGuarantee `json:"guarantee,omitempty"`
+	ScsiThinProvisioningSupportEnabled *bool             `json:"scsi_thin_provisioning_support_enabled,omitempty"`
+}
+
+type restLunGuarantee struct {
+	Requested bool `json:"requested"`
+}
+
+type restLunStatus struct {
+	State  string `json:"state,omitempty"`
+	Mapped bool   `json:"mapped"`
+}
+
+type restLunClone struct {
+	Source *restReference `json:"source,omitempty"`
+}
+
+type restLunAttribute struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+}
+
+type restLunMap struct {
+	SVM               *restReference  `json:"svm,omitempty"`
+	Igroup            *restReference  `json:"igroup,omitempty"`
+	Lun               *restReference  `json:"lun,omitempty"`
+	LogicalUnitNumber *int            `json:"logical_unit_number,omitempty"`
+	ReportingNodes    []restReference `json:"reporting_nodes,omitempty"`
+}
+
+func (l *restLun) toLunInfo() azgo.LunInfoType {
+
+	info := azgo.NewLunInfoType().
+		SetPath(l.Name).
+		SetUuid(l.UUID).
+		SetSerialNumber(l.SerialNumber).
+		SetMultiprotocolType(l.OsType).
+		SetCreationTimestamp(restTimestamp(l.CreateTime)).
+		SetVolume("").
+		SetQtree("").
+		SetSize(0).
+		SetOnline(false).
+		SetMapped(false)
+
+	if l.SVM != nil {
+		info.SetVserver(l.SVM.Name)
+	}
+	if l.Location != nil {
+		if l.Location.Volume != nil {
+			info.SetVolume(l.Location.Volume.Name)
+		}
+		if l.Location.Qtree != nil {
+			info.SetQtree(l.Location.Qtree.Name)
+		}
+	}
+	if l.Space != nil {
+		info.SetSize(l.Space.Size)
+	}
+	if l.Status != nil {
+		info.SetOnline(l.Status.State == "online").SetMapped(l.Status.Mapped)
+	}
+	if l.QosPolicy != nil {
+		info.SetQosPolicyGroup(l.QosPolicy.Name)
+	}
+	return *info
+}
+
+func (c *RestClient) lunList(query url.Values) ([]restLun, error) {
+	var luns []restLun
+	err := c.getRecords("/api/storage/luns", query, &luns)
+	return luns, err
+}
+
+// lunGet returns the LUN with the specified path, or a not found error if it doesn't exist
+func (c *RestClient) lunGet(lunPath, fields string) (*restLun, error) {
+
+	luns, err := c.lunList(c.query(fields, "name", lunPath))
+	if err != nil {
+		return nil, err
+	} else if len(luns) == 0 {
+		return nil, restNotFoundError("LUN", lunPath)
+	}
+	return &luns[0], nil
+}
+
+// lunModify applies the specified changes to the LUN with the specified path
+func (c *RestClient) lunModify(lunPath string, lun *restLun) error {
+
+	existingLun, err := c.lunGet(lunPath, "uuid")
+	if err != nil {
+		return err
+	}
+	return c.invoke(http.MethodPatch, "/api/storage/luns/"+existingLun.UUID, nil, lun)
+}
+
+func restQosPolicy(qosPolicyGroup QosPolicyGroup) *restReference {
+
+	// The REST API doesn't distinguish adaptive QoS policy groups from the others
+	switch qosPolicyGroup.Kind {
+	case QosPolicyGroupKind, QosAdaptivePolicyGroupKind:
+		return &restReference{Name: qosPolicyGroup.Name}
+	}
+	return nil
+}
+
+// LunCreate creates a lun with the specified attributes
+func (c *RestClient) LunCreate(
+	lunPath string, sizeInBytes int, osType string, qosPolicyGroup QosPolicyGroup, spaceReserved bool,
+	spaceAllocated bool,
+) (*azgo.LunCreateBySizeResponse, error) {
+
+	if strings.Contains(lunPath, failureLUNCreate) {
+		return nil, errors.New("injected error")
+	}
+
+	response := &azgo.LunCreateBySizeResponse{}
+
+	err := c.invoke(http.MethodPost, "/api/storage/luns", nil, &restLun{
+		Name:   lunPath,
+		SVM:    c.svm(),
+		OsType: osType,
+		Space: &restLunSpace{
+			Size:                               sizeInBytes,
+			Guarantee:                          &restLunGuarantee{Requested: spaceReserved},
+			ScsiThinProvisioningSupportEnabled: &spaceAllocated,
+		},
+		QosPolicy: restQosPolicy(qosPolicyGroup),
+	})
+
+	return response, setResult(err, azgo.EVDISK_ERROR_NO_SUCH_VOLUME,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunCloneCreate clones a LUN from a snapshot
+func (c *RestClient) LunCloneCreate(
+	volumeName, sourceLun, destinationLun string, qosPolicyGroup QosPolicyGroup,
+) (*azgo.CloneCreateResponse, error) {
+
+	response := &azgo.CloneCreateResponse{}
+
+	err := c.invoke(http.MethodPost, "/api/storage/luns", nil, &restLun{
+		Name: fmt.Sprintf("/vol/%s/%s", volumeName, destinationLun),
+		SVM:  c.svm(),
+		Clone: &restLunClone{
+			Source: &restReference{Name: fmt.Sprintf("/vol/%s/%s", volumeName, sourceLun)},
+		},
+		QosPolicy: restQosPolicy(qosPolicyGroup),
+	})
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunSetQosPolicyGroup sets the qos policy group or adaptive qos policy group on a lun; does not unset policy groups
+func (c *RestClient) LunSetQosPolicyGroup(
+	lunPath string, qosPolicyGroup QosPolicyGroup,
+) (*azgo.LunSetQosPolicyGroupResponse, error) {
+
+	response := &azgo.LunSetQosPolicyGroupResponse{}
+
+	var err error
+	if qosPolicy := restQosPolicy(qosPolicyGroup); qosPolicy != nil {
+		err = c.lunModify(lunPath, &restLun{QosPolicy: qosPolicy})
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunGetSerialNumber returns the serial# for a lun
+func (c *RestClient) LunGetSerialNumber(lunPath string) (*azgo.LunGetSerialNumberResponse, error) {
+
+	response := &azgo.LunGetSerialNumberResponse{}
+
+	lun, err := c.lunGet(lunPath, "serial_number")
+	if err == nil {
+		response.Result.SetSerialNumber(lun.SerialNumber)
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+func (c *RestClient) lunMapList(query url.Values) ([]restLunMap, error) {
+	var lunMaps []restLunMap
+	err := c.getRecords("/api/protocols/san/lun-maps", query, &lunMaps)
+	return lunMaps, err
+}
+
+// LunMapGet returns a list of LUN map details for a given igroup and LUN path
+func (c *RestClient) LunMapGet(initiatorGroupName, lunPath string) (*azgo.LunMapGetIterResponse, error) {
+
+	response := &azgo.LunMapGetIterResponse{}
+
+	lunMaps, err := c.lunMapList(c.query("igroup.name,lun.name,logical_unit_number,reporting_nodes.name,svm.name",
+		"igroup.name", initiatorGroupName, "lun.name", lunPath))
+	if err == nil {
+		infos := make([]azgo.LunMapInfoType, 0)
+		for _, lunMap := range lunMaps {
+			reportingNodes := make([]azgo.NodeNameType, 0)
+			for _, node := range lunMap.ReportingNodes {
+				reportingNodes = append(reportingNodes, node.Name)
+			}
+			info := azgo.NewLunMapInfoType().
+				SetInitiatorGroup(lunMap.Igroup.Name).
+				SetPath(lunMap.Lun.Name).
+				SetReportingNodes(reportingNodes).
+				SetVserver(lunMap.SVM.Name)
+			if lunMap.LogicalUnitNumber != nil {
+				info.SetLunId(*lunMap.LogicalUnitNumber)
+			}
+			infos = append(infos, *info)
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = infos
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// lunMapCreate maps a LUN to an igroup, using the specified LUN ID if it isn't nil, and returns the LUN ID
+func (c *RestClient) lunMapCreate(initiatorGroupName, lunPath string, lunID *int) (int, error) {
+
+	err := c.invoke(http.MethodPost, "/api/protocols/san/lun-maps", nil, &restLunMap{
+		SVM:               c.svm(),
+		Igroup:            &restReference{Name: initiatorGroupName},
+		Lun:               &restReference{Name: lunPath},
+		LogicalUnitNumber: lunID,
+	})
+	if err != nil {
+		return -1, err
+	}
+
+	// Read back the map to learn the LUN ID assigned by ONTAP
+	lunMaps, err := c.lunMapList(c.query("logical_unit_number",
+		"igroup.name", initiatorGroupName, "lun.name", lunPath))
+	if err != nil {
+		return -1, err
+	} else if len(lunMaps) == 0 || lunMaps[0].LogicalUnitNumber == nil {
+		return -1, restNotFoundError("LUN map for", lunPath)
+	}
+	return *lunMaps[0].LogicalUnitNumber, nil
+}
+
+// LunMap maps a lun to an id in an initiator group
+func (c *RestClient) LunMap(initiatorGroupName, lunPath string, lunID int) (*azgo.LunMapResponse, error) {
+
+	response := &azgo.LunMapResponse{}
+
+	assignedLunID, err := c.lunMapCreate(initiatorGroupName, lunPath, &lunID)
+	if err == nil {
+		response.Result.LunIdAssignedPtr = &assignedLunID
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunMapAutoID maps a LUN in an initiator group, allowing ONTAP to choose an available LUN ID
+func (c *RestClient) LunMapAutoID(initiatorGroupName, lunPath string) (*azgo.LunMapResponse, error) {
+
+	response := &azgo.LunMapResponse{}
+
+	assignedLunID, err := c.lunMapCreate(initiatorGroupName, lunPath, nil)
+	if err == nil {
+		response.Result.LunIdAssignedPtr = &assignedLunID
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+func (c *RestClient) LunMapIfNotMapped(
+	ctx context.Context, initiatorGroupName, lunPath string, importNotManaged bool,
+) (int, error) {
+	return lunMapIfNotMapped(ctx, c, initiatorGroupName, lunPath, importNotManaged)
+}
+
+// LunMapToIgroupIfNotMapped maps a LUN to the specified igroup, unless it is already mapped to that igroup,
+// and returns the LUN ID.  Unlike LunMapIfNotMapped, any maps to other igroups are left in place.
+func (c *RestClient) LunMapToIgroupIfNotMapped(ctx context.Context, initiatorGroupName, lunPath string) (int, error) {
+	return lunMapToIgroupIfNotMapped(ctx, c, initiatorGroupName, lunPath)
+}
+
+// LunUnmapIfMapped deletes the map between a LUN and the specified igroup, if one exists.
+func (c *RestClient) LunUnmapIfMapped(ctx context.Context, initiatorGroupName, lunPath string) error {
+	return lunUnmapIfMapped(ctx, c, initiatorGroupName, lunPath)
+}
+
+// LunListIgroupsMapped returns the names of the igroups to which a LUN is mapped, along with the LUN ID
+// assigned in each igroup.
+func (c *RestClient) LunListIgroupsMapped(lunPath string) (map[string]int, error) {
+	return lunListIgroupsMapped(c, lunPath)
+}
+
+// LunMapListInfo returns lun mapping information for the specified lun
+func (c *RestClient) LunMapListInfo(lunPath string) (*azgo.LunMapListInfoResponse, error) {
+
+	response := &azgo.LunMapListInfoResponse{}
+
+	lunMaps, err := c.lunMapList(c.query("igroup.name,igroup.uuid,logical_unit_number", "lun.name", lunPath))
+	if err == nil {
+		infos := make([]azgo.InitiatorGroupInfoType, 0)
+		for _, lunMap := range lunMaps {
+			info := azgo.NewInitiatorGroupInfoType().
+				SetInitiatorGroupName(lunMap.Igroup.Name).
+				SetInitiatorGroupUuid(lunMap.Igroup.UUID)
+			if lunMap.LogicalUnitNumber != nil {
+				info.SetLunId(*lunMap.LogicalUnitNumber)
+			}
+			infos = append(infos, *info)
+		}
+		response.Result.InitiatorGroupsPtr = &azgo.LunMapListInfoResponseResultInitiatorGroups{
+			InitiatorGroupInfoPtr: infos,
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunOffline offlines a LUN
+func (c *RestClient) LunOffline(lunPath string) (*azgo.LunOfflineResponse, error) {
+
+	response := &azgo.LunOfflineResponse{}
+	enabled := false
+	err := c.lunModify(lunPath, &restLun{Enabled: &enabled})
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunOnline onlines a LUN
+func (c *RestClient) LunOnline(lunPath string) (*azgo.LunOnlineResponse, error) {
+
+	response := &azgo.LunOnlineResponse{}
+	enabled := true
+	err := c.lunModify(lunPath, &restLun{Enabled: &enabled})
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunDestroy destroys a LUN
+func (c *RestClient) LunDestroy(lunPath string) (*azgo.LunDestroyResponse, error) {
+
+	response := &azgo.LunDestroyResponse{}
+
+	lun, err := c.lunGet(lunPath, "uuid")
+	if err == nil {
+		err = c.invoke(http.MethodDelete, "/api/storage/luns/"+lun.UUID, nil, nil)
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunSetAttribute sets a named attribute for a given LUN.
+func (c *RestClient) LunSetAttribute(lunPath, name, value string) (*azgo.LunSetAttributeResponse, error) {
+
+	if strings.Contains(lunPath, failureLUNSetAttr) {
+		return nil, errors.New("injected error")
+	}
+
+	response := &azgo.LunSetAttributeResponse{}
+
+	lun, err := c.lunGet(lunPath, "uuid")
+	if err == nil {
+		attributesPath := "/api/storage/luns/" + lun.UUID + "/attributes"
+		var attributes []restLunAttribute
+		if err = c.getRecords(attributesPath, url.Values{"name": {name}}, &attributes); err == nil {
+			if len(attributes) == 0 {
+				err = c.invoke(http.MethodPost, attributesPath, nil, &restLunAttribute{Name: name, Value: value})
+			} else {
+				err = c.invoke(http.MethodPatch, attributesPath+"/"+url.PathEscape(name), nil,
+					&restLunAttribute{Value: value})
+			}
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunGetAttribute gets a named attribute for a given LUN.
+func (c *RestClient) LunGetAttribute(lunPath, name string) (*azgo.LunGetAttributeResponse, error) {
+
+	response := &azgo.LunGetAttributeResponse{}
+
+	lun, err := c.lunGet(lunPath, "uuid")
+	if err == nil {
+		var attributes []restLunAttribute
+		err = c.getRecords("/api/storage/luns/"+lun.UUID+"/attributes", url.Values{"name": {name}}, &attributes)
+		if err == nil {
+			if len(attributes) == 0 {
+				err = RestError{Code: azgo.EVDISK_ERROR_NO_SUCH_ATTRIBUTE,
+					Message: fmt.Sprintf("LUN %s has no attribute %s", lunPath, name)}
+			} else {
+				response.Result.SetValue(attributes[0].Value)
+			}
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunGet returns all relevant details for a single LUN
+func (c *RestClient) LunGet(path string) (*azgo.LunInfoType, error) {
+
+	lun, err := c.lunGet(path, restLunFields)
+	if err != nil {
+		return &azgo.LunInfoType{}, err
+	}
+	info := lun.toLunInfo()
+	return &info, nil
+}
+
+// LunGetGeometry returns the size and maximum resize size of a LUN.  The public REST API does not report the
+// maximum size, so it is read through the CLI passthrough, which is also served by the REST API.
+func (c *RestClient) LunGetGeometry(path string) (*azgo.LunGetGeometryResponse, error) {
+
+	response := &azgo.LunGetGeometryResponse{}
+
+	var luns []struct {
+		Size          int `json:"size"`
+		MaxResizeSize int `json:"max_resize_size"`
+	}
+	query := url.Values{"vserver": {c.config.SVM}, "path": {path}, "fields": {"size,max_resize_size"}}
+	err := c.getRecords("/api/private/cli/lun", query, &luns)
+	if err == nil {
+		if len(luns) == 0 {
+			err = restNotFoundError("LUN", path)
+		} else {
+			response.Result.SetSize(luns[0].Size).SetMaxResizeSize(luns[0].MaxResizeSize)
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunResize resizes a LUN and returns its new size
+func (c *RestClient) LunResize(path string, sizeBytes int) (uint64, error) {
+
+	lun, err := c.lunGet(path, "uuid")
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.invoke(http.MethodPatch, "/api/storage/luns/"+lun.UUID, nil, &restLun{
+		Space: &restLunSpace{Size: sizeBytes},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// ONTAP may round the requested size, so read back the actual size
+	if lun, err = c.lunGet(path, "space.size"); err != nil {
+		return 0, err
+	} else if lun.Space == nil || lun.Space.Size < 0 {
+		return 0, fmt.Errorf("lun resize operation return an invalid size")
+	}
+	return uint64(lun.Space.Size), nil
+}
+
+// lunGetIter returns the LUNs matching a query as a ZAPI LUN iterator response
+func (c *RestClient) lunGetIter(query url.Values) (*azgo.LunGetIterResponse, error) {
+
+	response := &azgo.LunGetIterResponse{}
+
+	luns, err := c.lunList(query)
+	if err == nil {
+		infos := make([]azgo.LunInfoType, 0)
+		for _, lun := range luns {
+			infos = append(infos, lun.toLunInfo())
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = &azgo.LunGetIterResponseResultAttributesList{LunInfoPtr: infos}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunGetAll returns all LUNs matching a path pattern, such as "/vol/trident_*/*"
+func (c *RestClient) LunGetAll(pathPattern string) (*azgo.LunGetIterResponse, error) {
+	return c.lunGetIter(c.query(restLunFields, "name", pathPattern))
+}
+
+// LunGetAllForVolume returns all LUNs in a volume
+func (c *RestClient) LunGetAllForVolume(volumeName string) (*azgo.LunGetIterResponse, error) {
+	return c.lunGetIter(c.query(restLunFields, "location.volume.name", volumeName))
+}
+
+// LunGetAllForVserver returns all LUNs in an SVM
+func (c *RestClient) LunGetAllForVserver(vserverName string) (*azgo.LunGetIterResponse, error) {
+	query := c.query(restLunFields)
+	query.Set("svm.name", vserverName)
+	return c.lunGetIter(query)
+}
+
+// LunCount returns the number of LUNs that exist in a given volume
+func (c *RestClient) LunCount(_ context.Context, volume string) (int, error) {
+
+	luns, err := c.lunList(c.query("name", "location.volume.name", volume))
+	if err != nil {
+		return 0, err
+	}
+	return len(luns), nil
+}
+
+// LunRename changes the name of a LUN
+func (c *RestClient) LunRename(path, newPath string) (*azgo.LunMoveResponse, error) {
+
+	response := &azgo.LunMoveResponse{}
+	err := c.lunModify(path, &restLun{Name: newPath})
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LunUnmap deletes the lun mapping for the given LUN path and igroup
+func (c *RestClient) LunUnmap(initiatorGroupName, lunPath string) (*azgo.LunUnmapResponse, error) {
+
+	response := &azgo.LunUnmapResponse{}
+
+	lunMaps, err := c.lunMapList(c.query("igroup.uuid,lun.uuid", "igroup.name", initiatorGroupName,
+		"lun.name", lunPath))
+	if err == nil {
+		if len(lunMaps) == 0 {
+			err = restNotFoundError("LUN map for", lunPath)
+		} else {
+			err = c.invoke(http.MethodDelete,
+				"/api/protocols/san/lun-maps/"+lunMaps[0].Lun.UUID+"/"+lunMaps[0].Igroup.UUID, nil, nil)
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// LUN operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// VOLUME operations BEGIN
+
+const (
+	restStyleFlexVol   = "flexvol"
+	restStyleFlexGroup = "flexgroup"
+)
+
+type restVolume struct {
+	UUID                           string                `json:"uuid,omitempty"`
+	Name                           string                `json:"name,omitempty"`
+	SVM                            *restReference        `json:"svm,omitempty"`
+	Style                          string                `json:"style,omitempty"`
+	Type                           string                `json:"type,omitempty"`
+	State                          string                `json:"state,omitempty"`
+	Comment                        *string               `json:"comment,omitempty"`
+	Size                           int                   `json:"size,omitempty"`
+	Aggregates                     []restReference       `json:"aggregates,omitempty"`
+	Guarantee                      *restVolumeGuarantee  `json:"guarantee,omitempty"`
+	NAS                            *restVolumeNAS        `json:"nas,omitempty"`
+	SnapshotPolicy                 *restReference        `json:"snapshot_policy,omitempty"`
+	SnapshotDirectoryAccessEnabled *bool                 `json:"snapshot_directory_access_enabled,omitempty"`
+	Space                          *restVolumeSpace      `json:"space,omitempty"`
+	Tiering                        *restVolumeTiering    `json:"tiering,omitempty"`
+	QoS                            *restVolumeQoS        `json:"qos,omitempty"`
+	Encryption                     *restVolumeEncryption `json:"encryption,omitempty"`
+	Clone                          *restVolumeClone      `json:"clone,omitempty"`
+	Quota                          *restVolumeQuota      `json:"quota,omitempty"`
+	RestoreTo                      *restVolumeRestoreTo  `json:"restore_to,omitempty"`
+	Movement                       *restVolumeMovement   `json:"movement,omitempty"`
+}
+
+type restVolumeGuarantee struct {
+	Type string `json:"type,omitempty"`
+}
+
+type restVolumeNAS struct {
+	Path            *string        `json:"path,omitempty"`
+	ExportPolicy    *restReference `json:"export_policy,omitempty"`
+	SecurityStyle   string         `json:"security_style,omitempty"`
+	UnixPermissions *int           `json:"unix_permissions,omitempty"`
+}
+
+type restVolumeSpace struct {
+	Used      int                      `json:"used,omitempty"`
+	Available int                      `json:"available,omitempty"`
+	Snapshot  *restVolumeSnapshotSpace `json:"snapshot,omitempty"`
+}
+
+type restVolumeSnapshotSpace struct {
+	ReservePercent *int `json:"reserve_percent,omitempty"`
+	Used           int  `json:"used,omitempty"`
+}
+
+type restVolumeTiering struct {
+	Policy string `json:"policy,omitempty"`
+}
+
+type restVolumeQoS struct {
+	Policy *restReference `json:"policy,omitempty"`
+}
+
+type restVolumeEncryption struct {
+	Enabled bool `json:"enabled"`
+}
+
+type restVolumeClone struct {
+	IsFlexclone    bool           `json:"is_flexclone,omitempty"`
+	ParentVolume   *restReference `json:"parent_volume,omitempty"`
+	ParentSnapshot *restReference `json:"parent_snapshot,omitempty"`
+	SplitInitiated bool           `json:"split_initiated,omitempty"`
+}
+
+type restVolumeQuota struct {
+	Enabled *bool  `json:"enabled,omitempty"`
+	State   string `json:"state,omitempty"`
+}
+
+type restVolumeRestoreTo struct {
+	Snapshot *restReference `json:"snapshot,omitempty"`
+}
+
+type restVolumeMovement struct {
+	DestinationAggregate *restReference `json:"destination_aggregate,omitempty"`
+	State                string         `json:"state,omitempty"`
+	PercentComplete      *int           `json:"percent_complete,omitempty"`
+}
+
+func (v *restVolume) toVolumeAttributes() azgo.VolumeAttributesType {
+
+	idAttrs := azgo.NewVolumeIdAttributesType().
+		SetName(v.Name).
+		SetUuid(v.UUID).
+		SetStyleExtended(v.Style).
+		SetType(v.Type).
+		SetComment("").
+		SetContainingAggregateName("").
+		SetJunctionPath("")
+	if v.SVM != nil {
+		idAttrs.SetOwningVserverName(v.SVM.Name)
+	}
+	if v.Comment != nil {
+		idAttrs.SetComment(*v.Comment)
+	}
+	if len(v.Aggregates) == 1 {
+		idAttrs.SetContainingAggregateName(v.Aggregates[0].Name)
+	}
+
+	spaceAttrs := azgo.NewVolumeSpaceAttributesType().
+		SetSize(v.Size).
+		SetSpaceGuarantee("").
+		SetPercentageSnapshotReserve(0).
+		SetSizeUsedBySnapshots(0).
+		SetSizeUsed(0).
+		SetSizeAvailable(0).
+		SetPercentageSizeUsed(0)
+	if v.Guarantee != nil {
+		spaceAttrs.SetSpaceGuarantee(v.Guarantee.Type)
+	}
+	if v.Space != nil {
+		spaceAttrs.SetSizeUsed(v.Space.Used)
+		spaceAttrs.SetSizeAvailable(v.Space.Available)
+		// ZAPI reports used space as a percentage of the space usable by the active filesystem
+		if v.Space.Used+v.Space.Available > 0 {
+			spaceAttrs.SetPercentageSizeUsed(v.Space.Used * 100 / (v.Space.Used + v.Space.Available))
+		}
+	}
+	if v.Space != nil && v.Space.Snapshot != nil {
+		if v.Space.Snapshot.ReservePercent != nil {
+			spaceAttrs.SetPercentageSnapshotReserve(*v.Space.Snapshot.ReservePercent)
+		}
+		spaceAttrs.SetSizeUsedBySnapshots(v.Space.Snapshot.Used)
+	}
+
+	exportAttrs := azgo.NewVolumeExportAttributesType().SetPolicy("")
+	unixAttrs := azgo.NewVolumeSecurityUnixAttributesType().SetPermissions("")
+	securityAttrs := azgo.NewVolumeSecurityAttributesType().SetStyle("")
+	if v.NAS != nil {
+		if v.NAS.Path != nil {
+			idAttrs.SetJunctionPath(*v.NAS.Path)
+		}
+		if v.NAS.ExportPolicy != nil {
+			exportAttrs.SetPolicy(v.NAS.ExportPolicy.Name)
+		}
+		if v.NAS.UnixPermissions != nil {
+			unixAttrs.SetPermissions(zapiUnixPermissions(*v.NAS.UnixPermissions))
+		}
+		securityAttrs.SetStyle(v.NAS.SecurityStyle)
+	}
+	securityAttrs.SetVolumeSecurityUnixAttributes(*unixAttrs)
+
+	snapshotAttrs := azgo.NewVolumeSnapshotAttributesType().
+		SetSnapshotPolicy("").
+		SetSnapdirAccessEnabled(false)
+	if v.SnapshotPolicy != nil {
+		snapshotAttrs.SetSnapshotPolicy(v.SnapshotPolicy.Name)
+	}
+	if v.SnapshotDirectoryAccessEnabled != nil {
+		snapshotAttrs.SetSnapdirAccessEnabled(*v.SnapshotDirectoryAccessEnabled)
+	}
+
+	compAggrAttrs := azgo.NewVolumeCompAggrAttributesType().SetTieringPolicy("")
+	if v.Tiering != nil {
+		compAggrAttrs.SetTieringPolicy(zapiTieringPolicy(v.Tiering.Policy))
+	}
+
+	qosAttrs := azgo.NewVolumeQosAttributesType().SetPolicyGroupName("")
+	if v.QoS != nil && v.QoS.Policy != nil {
+		qosAttrs.SetPolicyGroupName(v.QoS.Policy.Name)
+	}
+
+	stateAttrs := azgo.NewVolumeStateAttributesType().SetState(v.State)
+
+	attrs := azgo.NewVolumeAttributesType().
+		SetVolumeIdAttributes(*idAttrs).
+		SetVolumeSpaceAttributes(*spaceAttrs).
+		SetVolumeExportAttributes(*exportAttrs).
+		SetVolumeSecurityAttributes(*securityAttrs).
+		SetVolumeSnapshotAttributes(*snapshotAttrs).
+		SetVolumeCompAggrAttributes(*compAggrAttrs).
+		SetVolumeQosAttributes(*qosAttrs).
+		SetVolumeStateAttributes(*stateAttrs).
+		SetEncrypt(v.Encryption != nil && v.Encryption.Enabled)
+
+	// ZAPI reports a clone's parent until the clone is split, while REST reports the split starting
+	if v.Clone != nil && v.Clone.IsFlexclone && !v.Clone.SplitInitiated && v.Clone.ParentVolume != nil {
+		parentAttrs := azgo.NewVolumeCloneParentAttributesType().SetName(v.Clone.ParentVolume.Name)
+		if v.Clone.ParentSnapshot != nil {
+			parentAttrs.SetSnapshotName(v.Clone.ParentSnapshot.Name)
+		}
+		attrs.SetVolumeCloneAttributes(*azgo.NewVolumeCloneAttributesType().
+			SetVolumeCloneParentAttributes(*parentAttrs))
+	}
+	return *attrs
+}
+
+func (c *RestClient) volumeList(query url.Values) ([]restVolume, error) {
+	var volumes []restVolume
+	err := c.getRecords("/api/storage/volumes", query, &volumes)
+	return volumes, err
+}
+
+// volumeUUID returns the UUID of the named volume, or a not found error if it doesn't exist
+func (c *RestClient) volumeUUID(name string) (string, error) {
+
+	volumes, err := c.volumeList(c.query("uuid", "name", name))
+	if err != nil {
+		return "", err
+	} else if len(volumes) == 0 {
+		return "", restNotFoundError("volume", name)
+	}
+	return volumes[0].UUID, nil
+}
+
+// volumeModify applies the specified changes to the named volume
+func (c *RestClient) volumeModify(name string, volume *restVolume) error {
+
+	uuid, err := c.volumeUUID(name)
+	if err != nil {
+		return err
+	}
+	return c.invoke(http.MethodPatch, "/api/storage/volumes/"+uuid, nil, volume)
+}
+
+// volumeGet returns the online volume of the specified style with the specified name
+func (c *RestClient) volumeGet(name, style string) (*azgo.VolumeAttributesType, error) {
+
+	volumes, err := c.volumeList(c.query(restVolumeFields, "name", name, "style", style, "state", "online"))
+	if err != nil {
+		return &azgo.VolumeAttributesType{}, err
+	} else if len(volumes) == 0 {
+		return &azgo.VolumeAttributesType{}, fmt.Errorf("%s %s not found", style, name)
+	} else if len(volumes) > 1 {
+		return &azgo.VolumeAttributesType{}, fmt.Errorf("more than one %s %s found", style, name)
+	}
+	attrs := volumes[0].toVolumeAttributes()
+	return &attrs, nil
+}
+
+// volumeGetIter returns the volumes matching a query as a ZAPI volume iterator response
+func (c *RestClient) volumeGetIter(query url.Values) (*azgo.VolumeGetIterResponse, error) {
+
+	response := &azgo.VolumeGetIterResponse{}
+
+	volumes, err := c.volumeList(query)
+	if err == nil {
+		attrs := make([]azgo.VolumeAttributesType, 0)
+		for _, volume := range volumes {
+			attrs = append(attrs, volume.toVolumeAttributes())
+		}
+		response.Result.SetNumRecords(len(attrs))
+		response.Result.AttributesListPtr = &azgo.VolumeGetIterResponseResultAttributesList{
+			VolumeAttributesPtr: attrs,
+		}
+	}
+
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// newRestVolume returns the REST representation of a new volume with the specified attributes
+func (c *RestClient) newRestVolume(
+	ctx context.Context, name, size, spaceReserve, snapshotPolicy, unixPermissions, exportPolicy, securityStyle,
+	comment string, qosPolicyGroup QosPolicyGroup, encrypt bool, snapshotReserve int,
+) (*restVolume, error) {
+
+	sizeBytes, err := restSize(size)
+	if err != nil {
+		return nil, err
+	}
+
+	volume := &restVolume{
+		Name:       name,
+		SVM:        c.svm(),
+		Size:       sizeBytes,
+		Comment:    &comment,
+		Guarantee:  &restVolumeGuarantee{Type: spaceReserve},
+		NAS:        &restVolumeNAS{SecurityStyle: securityStyle},
+		QoS:        &restVolumeQoS{Policy: restQosPolicy(qosPolicyGroup)},
+		Encryption: &restVolumeEncryption{Enabled: encrypt},
+	}
+
+	if snapshotPolicy != "" {
+		volume.SnapshotPolicy = &restReference{Name: snapshotPolicy}
+	}
+	if exportPolicy != "" {
+		volume.NAS.ExportPolicy = &restReference{Name: exportPolicy}
+	}
+	if unixPermissions != "" {
+		permissions, err := restUnixPermissions(unixPermissions)
+		if err != nil {
+			return nil, err
+		}
+		volume.NAS.UnixPermissions = &permissions
+	}
+	if snapshotReserve != NumericalValueNotSet {
+		volume.Space = &restVolumeSpace{Snapshot: &restVolumeSnapshotSpace{ReservePercent: &snapshotReserve}}
+	}
+
+	Logc(ctx).WithFields(log.Fields{"name": name, "size": sizeBytes}).Debug("Creating volume via REST.")
+
+	return volume, nil
+}
+
+// VolumeCreate creates a volume with the specified options
+func (c *RestClient) VolumeCreate(
+	ctx context.Context, name, aggregateName, size, spaceReserve, snapshotPolicy, unixPermissions,
+	exportPolicy, securityStyle, tieringPolicy, comment string, qosPolicyGroup QosPolicyGroup, encrypt bool,
+	snapshotReserve int, dpVolume bool,
+) (*azgo.VolumeCreateResponse, error) {
+
+	response := &azgo.VolumeCreateResponse{}
+
+	volume, err := c.newRestVolume(ctx, name, size, spaceReserve, snapshotPolicy, unixPermissions, exportPolicy,
+		securityStyle, comment, qosPolicyGroup, encrypt, snapshotReserve)
+	if err != nil {
+		return response, err
+	}
+
+	volume.Aggregates = []restReference{{Name: aggregateName}}
+
+	// A data protection volume may only be the destination of a SnapMirror relationship
+	if dpVolume {
+		volume.Type = "dp"
+	}
+
+	if tieringPolicy != "" && c.SupportsFeature(ctx, NetAppFabricPoolFlexVol) {
+		volume.Tiering = &restVolumeTiering{Policy: restTieringPolicy(tieringPolicy)}
+	}
+
+	err = c.invoke(http.MethodPost, "/api/storage/volumes", nil, volume)
+	return response, setResult(err, azgo.EAGGRDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeModifyExportPolicy updates the export policy for a volume
+func (c *RestClient) VolumeModifyExportPolicy(
+	volumeName, exportPolicyName string,
+) (*azgo.VolumeModifyIterResponse, error) {
+
+	response := &azgo.VolumeModifyIterResponse{}
+	err := c.volumeModify(volumeName, &restVolume{
+		NAS: &restVolumeNAS{ExportPolicy: &restReference{Name: exportPolicyName}},
+	})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeModifyUnixPermissions updates the unix permissions for a volume
+func (c *RestClient) VolumeModifyUnixPermissions(
+	volumeName, unixPermissions string,
+) (*azgo.VolumeModifyIterResponse, error) {
+
+	response := &azgo.VolumeModifyIterResponse{}
+
+	permissions, err := restUnixPermissions(unixPermissions)
+	if err != nil {
+		return response, err
+	}
+
+	err = c.volumeModify(volumeName, &restVolume{NAS: &restVolumeNAS{UnixPermissions: &permissions}})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeModifySnapshotPolicy updates the snapshot policy for a volume
+func (c *RestClient) VolumeModifySnapshotPolicy(
+	volumeName, snapshotPolicy string,
+) (*azgo.VolumeModifyIterResponse, error) {
+
+	response := &azgo.VolumeModifyIterResponse{}
+	err := c.volumeModify(volumeName, &restVolume{SnapshotPolicy: &restReference{Name: snapshotPolicy}})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeModifySnapshotReserve updates the snapshot reserve for a volume
+func (c *RestClient) VolumeModifySnapshotReserve(
+	volumeName string, snapshotReserve int,
+) (*azgo.VolumeModifyIterResponse, error) {
+
+	response := &azgo.VolumeModifyIterResponse{}
+	err := c.volumeModify(volumeName, &restVolume{
+		Space: &restVolumeSpace{Snapshot: &restVolumeSnapshotSpace{ReservePercent: &snapshotReserve}},
+	})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeModifyTieringPolicy updates the tiering policy for a volume
+func (c *RestClient) VolumeModifyTieringPolicy(
+	volumeName, tieringPolicy string,
+) (*azgo.VolumeModifyIterResponse, error) {
+
+	response := &azgo.VolumeModifyIterResponse{}
+	err := c.volumeModify(volumeName, &restVolume{
+		Tiering: &restVolumeTiering{Policy: restTieringPolicy(tieringPolicy)},
+	})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// newRestClone returns the REST representation of a new FlexClone of a volume
+func (c *RestClient) newRestClone(name, source, snapshot string) *restVolume {
+	return &restVolume{
+		Name: name,
+		SVM:  c.svm(),
+		Clone: &restVolumeClone{
+			IsFlexclone:    true,
+			ParentVolume:   &restReference{Name: source},
+			ParentSnapshot: &restReference{Name: snapshot},
+		},
+	}
+}
+
+// VolumeCloneCreate clones a volume from a snapshot
+func (c *RestClient) VolumeCloneCreate(name, source, snapshot string) (*azgo.VolumeCloneCreateResponse, error) {
+
+	response := &azgo.VolumeCloneCreateResponse{}
+	err := c.invoke(http.MethodPost, "/api/storage/volumes", nil, c.newRestClone(name, source, snapshot))
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeCloneCreateAsync clones a volume from a snapshot without waiting for the clone to complete
+func (c *RestClient) VolumeCloneCreateAsync(
+	name, source, snapshot string,
+) (*azgo.VolumeCloneCreateAsyncResponse, error) {
+
+	response := &azgo.VolumeCloneCreateAsyncResponse{}
+
+	jobUUID, err := c.startJob(http.MethodPost, "/api/storage/volumes", nil, c.newRestClone(name, source, snapshot))
+	err = setAsyncResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr,
+		&response.Result.ResultStatusPtr)
+	if err != nil {
+		return response, err
+	}
+
+	if jobUUID != "" && response.Result.ResultStatusAttr == "passed" {
+		jobID := c.registerJob(jobUUID)
+		inProgress := "in_progress"
+		response.Result.ResultJobidPtr = &jobID
+		response.Result.ResultStatusPtr = &inProgress
+	}
+
+	return response, nil
+}
+
+// VolumeCloneSplitStart splits a cloned volume from its parent
+func (c *RestClient) VolumeCloneSplitStart(name string) (*azgo.VolumeCloneSplitStartResponse, error) {
+
+	response := &azgo.VolumeCloneSplitStartResponse{}
+
+	uuid, err := c.volumeUUID(name)
+	if err == nil {
+		// The split continues in the background, so don't wait for it to finish
+		_, err = c.startJob(http.MethodPatch, "/api/storage/volumes/"+uuid, nil, &restVolume{
+			Clone: &restVolumeClone{SplitInitiated: true},
+		})
+	}
+
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeDisableSnapshotDirectoryAccess disables access to the ".snapshot" directory
+// Disable '.snapshot' to allow official mysql container's chmod-in-init to work
+func (c *RestClient) VolumeDisableSnapshotDirectoryAccess(name string) (*azgo.VolumeModifyIterResponse, error) {
+
+	response := &azgo.VolumeModifyIterResponse{}
+	enabled := false
+	err := c.volumeModify(name, &restVolume{SnapshotDirectoryAccessEnabled: &enabled})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeSetQosPolicyGroupName sets the QoS Policy Group for volume clones since
+// we can't set adaptive policy groups directly during volume clone creation.
+func (c *RestClient) VolumeSetQosPolicyGroupName(
+	name string, qosPolicyGroup QosPolicyGroup,
+) (*azgo.VolumeModifyIterResponse, error) {
+
+	response := &azgo.VolumeModifyIterResponse{}
+
+	var err error
+	if qosPolicy := restQosPolicy(qosPolicyGroup); qosPolicy != nil {
+		err = c.volumeModify(name, &restVolume{QoS: &restVolumeQoS{Policy: qosPolicy}})
+	}
+
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeExists tests for the existence of a Flexvol
+func (c *RestClient) VolumeExists(_ context.Context, name string) (bool, error) {
+
+	volumes, err := c.volumeList(c.query("uuid", "name", name))
+	if err != nil {
+		return false, err
+	}
+	return len(volumes) > 0, nil
+}
+
+// VolumeSize retrieves the size of the specified volume
+func (c *RestClient) VolumeSize(name string) (int, error) {
+
+	volAttrs, err := c.VolumeGet(name)
+	if err != nil {
+		return 0, err
+	}
+	volSpaceAttrs := volAttrs.VolumeSpaceAttributes()
+
+	return volSpaceAttrs.Size(), nil
+}
+
+// volumeSetSize sets the size of the named volume, which may be specified relative to its current size
+// by prefixing it with "+" or "-".
+func (c *RestClient) volumeSetSize(name, newSize string) error {
+
+	volumes, err := c.volumeList(c.query("uuid,size", "name", name))
+	if err != nil {
+		return err
+	} else if len(volumes) == 0 {
+		return restNotFoundError("volume", name)
+	}
+
+	var sizeBytes int
+	switch {
+	case strings.HasPrefix(newSize, "+"):
+		if sizeBytes, err = restSize(newSize[1:]); err == nil {
+			sizeBytes = volumes[0].Size + sizeBytes
+		}
+	case strings.HasPrefix(newSize, "-"):
+		if sizeBytes, err = restSize(newSize[1:]); err == nil {
+			sizeBytes = volumes[0].Size - sizeBytes
+		}
+	default:
+		sizeBytes, err = restSize(newSize)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.invoke(http.MethodPatch, "/api/storage/volumes/"+volumes[0].UUID, nil, &restVolume{Size: sizeBytes})
+}
+
+// VolumeSetSize sets the size of the specified volume
+func (c *RestClient) VolumeSetSize(name, newSize string) (*azgo.VolumeSizeResponse, error) {
+
+	response := &azgo.VolumeSizeResponse{}
+	err := c.volumeSetSize(name, newSize)
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeMount mounts a volume at the specified junction
+func (c *RestClient) VolumeMount(name, junctionPath string) (*azgo.VolumeMountResponse, error) {
+
+	response := &azgo.VolumeMountResponse{}
+	err := c.volumeModify(name, &restVolume{NAS: &restVolumeNAS{Path: &junctionPath}})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeUnmount unmounts a volume from the specified junction
+func (c *RestClient) VolumeUnmount(name string, _ bool) (*azgo.VolumeUnmountResponse, error) {
+
+	response := &azgo.VolumeUnmountResponse{}
+	junctionPath := ""
+	err := c.volumeModify(name, &restVolume{NAS: &restVolumeNAS{Path: &junctionPath}})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeOffline offlines a volume
+func (c *RestClient) VolumeOffline(name string) (*azgo.VolumeOfflineResponse, error) {
+
+	response := &azgo.VolumeOfflineResponse{}
+	err := c.volumeModify(name, &restVolume{State: "offline"})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// volumeDestroy destroys the named volume, unmounting and offlining it first if force is true
+func (c *RestClient) volumeDestroy(name string, force bool) error {
+
+	uuid, err := c.volumeUUID(name)
+	if err != nil {
+		return err
+	}
+
+	if force {
+		junctionPath := ""
+		if err = c.invoke(http.MethodPatch, "/api/storage/volumes/"+uuid, nil, &restVolume{
+			NAS: &restVolumeNAS{Path: &junctionPath},
+		}); err != nil {
+			log.WithField("volume", name).Debugf("Could not unmount volume; %v", err)
+		}
+		if err = c.invoke(http.MethodPatch, "/api/storage/volumes/"+uuid, nil, &restVolume{
+			State: "offline",
+		}); err != nil {
+			log.WithField("volume", name).Debugf("Could not offline volume; %v", err)
+		}
+	}
+
+	return c.invoke(http.MethodDelete, "/api/storage/volumes/"+uuid, nil, nil)
+}
+
+// VolumeDestroy destroys a volume
+func (c *RestClient) VolumeDestroy(name string, force bool) (*azgo.VolumeDestroyResponse, error) {
+
+	response := &azgo.VolumeDestroyResponse{}
+	err := c.volumeDestroy(name, force)
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeGet gets a specific Flexvol
+func (c *RestClient) VolumeGet(name string) (*azgo.VolumeAttributesType, error) {
+	return c.volumeGet(name, restStyleFlexVol)
+}
+
+// VolumeGetAll returns all relevant details for all FlexVols whose names match the supplied prefix
+func (c *RestClient) VolumeGetAll(prefix string) (*azgo.VolumeGetIterResponse, error) {
+	return c.volumeGetIter(c.query(restVolumeFields, "name", prefix+"*", "style", restStyleFlexVol,
+		"state", "online"))
+}
+
+// VolumeList returns the names of all Flexvols whose names match the supplied prefix
+func (c *RestClient) VolumeList(prefix string) (*azgo.VolumeGetIterResponse, error) {
+	return c.volumeGetIter(c.query("name", "name", prefix+"*", "style", restStyleFlexVol, "state", "online"))
+}
+
+// VolumeListByAttrs returns the names of all Flexvols matching the specified attributes
+func (c *RestClient) VolumeListByAttrs(
+	prefix, aggregate, spaceReserve, snapshotPolicy, tieringPolicy string, snapshotDir bool, encrypt bool,
+) (*azgo.VolumeGetIterResponse, error) {
+
+	query := c.query("name",
+		"name", prefix+"*",
+		"style", restStyleFlexVol,
+		"state", "online",
+		"snapshot_directory_access_enabled", strconv.FormatBool(snapshotDir),
+		"encryption.enabled", strconv.FormatBool(encrypt))
+
+	if aggregate != "" {
+		query.Set("aggregates.name", aggregate)
+	}
+	if spaceReserve != "" {
+		query.Set("guarantee.type", spaceReserve)
+	}
+	if snapshotPolicy != "" {
+		query.Set("snapshot_policy.name", snapshotPolicy)
+	}
+	if tieringPolicy != "" {
+		query.Set("tiering.policy", restTieringPolicy(tieringPolicy))
+	}
+
+	return c.volumeGetIter(query)
+}
+
+// VolumeListAllBackedBySnapshot returns the names of all FlexVols backed by the specified snapshot
+func (c *RestClient) VolumeListAllBackedBySnapshot(_ context.Context, volumeName, snapshotName string) ([]string,
+	error) {
+
+	volumes, err := c.volumeList(c.query("name",
+		"clone.parent_volume.name", volumeName, "clone.parent_snapshot.name", snapshotName))
+	if err != nil {
+		return nil, fmt.Errorf("error enumerating volumes backed by snapshot: %v", err)
+	}
+
+	volumeNames := make([]string, 0)
+	for _, volume := range volumes {
+		volumeNames = append(volumeNames, volume.Name)
+	}
+	return volumeNames, nil
+}
+
+// VolumeRename changes the name of a FlexVol (but not a FlexGroup!)
+func (c *RestClient) VolumeRename(volumeName, newVolumeName string) (*azgo.VolumeRenameResponse, error) {
+
+	response := &azgo.VolumeRenameResponse{}
+	err := c.volumeModify(volumeName, &restVolume{Name: newVolumeName})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeMoveStart starts moving a Flexvol to another aggregate in the same cluster
+func (c *RestClient) VolumeMoveStart(volumeName, destinationAggregate string) (*azgo.VolumeMoveStartResponse, error) {
+
+	response := &azgo.VolumeMoveStartResponse{}
+	uuid, err := c.volumeUUID(volumeName)
+	if err == nil {
+		// The move continues long after the PATCH job completes, so don't wait for the job
+		_, err = c.startJob(http.MethodPatch, "/api/storage/volumes/"+uuid, nil, &restVolume{
+			Movement: &restVolumeMovement{DestinationAggregate: &restReference{Name: destinationAggregate}},
+		})
+	}
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VolumeMoveGet returns the status of the most recent move of a Flexvol.  The REST move states are
+// reported as the equivalent ZAPI move phases.
+func (c *RestClient) VolumeMoveGet(volumeName string) (*azgo.VolumeMoveInfoType, error) {
+
+	volumes, err := c.volumeList(c.query("name,aggregates.name,movement", "name", volumeName))
+	if err != nil {
+		return nil, err
+	} else if len(volumes) == 0 {
+		return nil, restNotFoundError("volume", volumeName)
+	}
+
+	volume := volumes[0]
+	if volume.Movement == nil || volume.Movement.State == "" {
+		return nil, utils.NotFoundError(fmt.Sprintf("no move of volume %s found", volumeName))
+	}
+
+	phase := volume.Movement.State
+	switch phase {
+	case "success":
+		phase = VolumeMovePhaseCompleted
+	case "cutover_wait", "cutover_pending":
+		phase = VolumeMovePhaseCutover
+	}
+
+	info := azgo.NewVolumeMoveInfoType().
+		SetVserver(c.config.SVM).
+		SetVolume(volume.Name).
+		SetPhase(phase).
+		SetState(volume.Movement.State)
+	if volume.Movement.DestinationAggregate != nil {
+		info.SetDestinationAggregate(volume.Movement.DestinationAggregate.Name)
+	}
+	if len(volume.Aggregates) == 1 {
+		info.SetSourceAggregate(volume.Aggregates[0].Name)
+	}
+	if volume.Movement.PercentComplete != nil {
+		info.SetPercentComplete(*volume.Movement.PercentComplete)
+	}
+	return info, nil
+}
+
+func (c *RestClient) VolumeSetComment(
+	_ context.Context, volumeName, newVolumeComment string,
+) (*azgo.VolumeModifyIterResponse, error) {
+
+	response := &azgo.VolumeModifyIterResponse{}
+	err := c.volumeModify(volumeName, &restVolume{Comment: &newVolumeComment})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VOLUME operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// FlexGroup operations BEGIN
+
+// FlexGroupCreate creates a FlexGroup with the specified options
+func (c *RestClient) FlexGroupCreate(
+	ctx context.Context, name string, size int, aggrs []azgo.AggrNameType, spaceReserve, snapshotPolicy,
+	unixPermissions, exportPolicy, securityStyle, tieringPolicy, comment string, qosPolicyGroup QosPolicyGroup,
+	encrypt bool, snapshotReserve int,
+) (*azgo.VolumeCreateAsyncResponse, error) {
+
+	response := &azgo.VolumeCreateAsyncResponse{}
+
+	volume, err := c.newRestVolume(ctx, name, strconv.Itoa(size), spaceReserve, snapshotPolicy, unixPermissions,
+		exportPolicy, securityStyle, comment, qosPolicyGroup, encrypt, snapshotReserve)
+	if err != nil {
+		return response, err
+	}
+
+	junctionPath := fmt.Sprintf("/%s", name)
+	volume.Style = restStyleFlexGroup
+	volume.NAS.Path = &junctionPath
+	for _, aggr := range aggrs {
+		volume.Aggregates = append(volume.Aggregates, restReference{Name: aggr})
+	}
+
+	if tieringPolicy != "" && c.SupportsFeature(ctx, NetAppFabricPoolFlexGroup) {
+		volume.Tiering = &restVolumeTiering{Policy: restTieringPolicy(tieringPolicy)}
+	}
+
+	err = c.invoke(http.MethodPost, "/api/storage/volumes", nil, volume)
+	err = setAsyncResult(err, azgo.EAGGRDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr,
+		&response.Result.ResultStatusPtr)
+	if err != nil {
+		return response, err
+	}
+	return response, GetError(ctx, response, nil)
+}
+
+// FlexGroupDestroy destroys a FlexGroup
+func (c *RestClient) FlexGroupDestroy(
+	ctx context.Context, name string, force bool,
+) (*azgo.VolumeDestroyAsyncResponse, error) {
+
+	response := &azgo.VolumeDestroyAsyncResponse{}
+
+	err := c.volumeDestroy(name, force)
+	err = setAsyncResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr,
+		&response.Result.ResultStatusPtr)
+	if err != nil {
+		return response, err
+	}
+
+	// It's not an error if the volume no longer exists
+	if zerr := NewZapiError(response); !zerr.IsPassed() && zerr.Code() == azgo.EVOLUMEDOESNOTEXIST {
+		Logc(ctx).WithField("volume", name).Warn("FlexGroup already deleted.")
+		return response, nil
+	}
+
+	return response, GetError(ctx, response, nil)
+}
+
+// FlexGroupExists tests for the existence of a FlexGroup
+func (c *RestClient) FlexGroupExists(_ context.Context, name string) (bool, error) {
+
+	volumes, err := c.volumeList(c.query("uuid", "name", name, "style", restStyleFlexGroup))
+	if err != nil {
+		return false, err
+	}
+	return len(volumes) > 0, nil
+}
+
+// FlexGroupSize retrieves the size of the specified FlexGroup
+func (c *RestClient) FlexGroupSize(name string) (int, error) {
+
+	volAttrs, err := c.FlexGroupGet(name)
+	if err != nil {
+		return 0, err
+	}
+	volSpaceAttrs := volAttrs.VolumeSpaceAttributes()
+
+	return volSpaceAttrs.Size(), nil
+}
+
+// FlexGroupSetSize sets the size of the specified FlexGroup
+func (c *RestClient) FlexGroupSetSize(ctx context.Context, name, newSize string) (*azgo.VolumeSizeAsyncResponse,
+	error) {
+
+	response := &azgo.VolumeSizeAsyncResponse{}
+
+	err := c.volumeSetSize(name, newSize)
+	err = setAsyncResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr,
+		&response.Result.ResultStatusPtr)
+	if err != nil {
+		return response, err
+	}
+	return response, GetError(ctx, response, nil)
+}
+
+// flexGroupModify applies the specified changes to the named FlexGroup
+func (c *RestClient) flexGroupModify(
+	ctx context.Context, volumeName string, volume *restVolume,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	response := &azgo.VolumeModifyIterAsyncResponse{}
+
+	err := c.volumeModify(volumeName, volume)
+	err = setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+	if err != nil {
+		return response, err
+	}
+	return response, GetError(ctx, response, nil)
+}
+
+// FlexGroupVolumeDisableSnapshotDirectoryAccess disables access to the ".snapshot" directory
+// Disable '.snapshot' to allow official mysql container's chmod-in-init to work
+func (c *RestClient) FlexGroupVolumeDisableSnapshotDirectoryAccess(
+	ctx context.Context, name string,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	enabled := false
+	return c.flexGroupModify(ctx, name, &restVolume{SnapshotDirectoryAccessEnabled: &enabled})
+}
+
+func (c *RestClient) FlexGroupModifyUnixPermissions(
+	ctx context.Context, volumeName, unixPermissions string,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	permissions, err := restUnixPermissions(unixPermissions)
+	if err != nil {
+		return &azgo.VolumeModifyIterAsyncResponse{}, err
+	}
+	return c.flexGroupModify(ctx, volumeName, &restVolume{NAS: &restVolumeNAS{UnixPermissions: &permissions}})
+}
+
+// FlexGroupModifyExportPolicy updates the export policy for a FlexGroup
+func (c *RestClient) FlexGroupModifyExportPolicy(
+	ctx context.Context, volumeName, exportPolicyName string,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	return c.flexGroupModify(ctx, volumeName, &restVolume{
+		NAS: &restVolumeNAS{ExportPolicy: &restReference{Name: exportPolicyName}},
+	})
+}
+
+// FlexGroupModifySnapshotPolicy updates the snapshot policy for a FlexGroup
+func (c *RestClient) FlexGroupModifySnapshotPolicy(
+	ctx context.Context, volumeName, snapshotPolicy string,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	return c.flexGroupModify(ctx, volumeName, &restVolume{SnapshotPolicy: &restReference{Name: snapshotPolicy}})
+}
+
+// FlexGroupModifySnapshotReserve updates the snapshot reserve for a FlexGroup
+func (c *RestClient) FlexGroupModifySnapshotReserve(
+	ctx context.Context, volumeName string, snapshotReserve int,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	return c.flexGroupModify(ctx, volumeName, &restVolume{
+		Space: &restVolumeSpace{Snapshot: &restVolumeSnapshotSpace{ReservePercent: &snapshotReserve}},
+	})
+}
+
+// FlexGroupModifyTieringPolicy updates the tiering policy for a FlexGroup
+func (c *RestClient) FlexGroupModifyTieringPolicy(
+	ctx context.Context, volumeName, tieringPolicy string,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	return c.flexGroupModify(ctx, volumeName, &restVolume{
+		Tiering: &restVolumeTiering{Policy: restTieringPolicy(tieringPolicy)},
+	})
+}
+
+// FlexGroupSetQosPolicyGroupName note: we can't set adaptive policy groups directly during volume clone creation.
+func (c *RestClient) FlexGroupSetQosPolicyGroupName(
+	ctx context.Context, volumeName string, qosPolicyGroup QosPolicyGroup,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	qosPolicy := restQosPolicy(qosPolicyGroup)
+	if qosPolicy == nil {
+		response := &azgo.VolumeModifyIterAsyncResponse{}
+		response.Result.ResultStatusAttr = "passed"
+		return response, nil
+	}
+	return c.flexGroupModify(ctx, volumeName, &restVolume{QoS: &restVolumeQoS{Policy: qosPolicy}})
+}
+
+func (c *RestClient) FlexGroupSetComment(
+	ctx context.Context, volumeName, newVolumeComment string,
+) (*azgo.VolumeModifyIterAsyncResponse, error) {
+
+	return c.flexGroupModify(ctx, volumeName, &restVolume{Comment: &newVolumeComment})
+}
+
+// FlexGroupGet returns all relevant details for a single FlexGroup
+func (c *RestClient) FlexGroupGet(name string) (*azgo.VolumeAttributesType, error) {
+	return c.volumeGet(name, restStyleFlexGroup)
+}
+
+// FlexGroupGetAll returns all relevant details for all FlexGroups whose names match the supplied prefix
+func (c *RestClient) FlexGroupGetAll(prefix string) (*azgo.VolumeGetIterResponse, error) {
+	return c.volumeGetIter(c.query(restVolumeFields, "name", prefix+"*", "style", restStyleFlexGroup,
+		"state", "online"))
+}
+
+// FlexGroup operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// QTREE operations BEGIN
+
+type restQtree struct {
+	ID              int            `json:"id,omitempty"`
+	Name            string         `json:"name,omitempty"`
+	SVM             *restReference `json:"svm,omitempty"`
+	Volume          *restReference `json:"volume,omitempty"`
+	SecurityStyle   string         `json:"security_style,omitempty"`
+	UnixPermissions *int           `json:"unix_permissions,omitempty"`
+	ExportPolicy    *restReference `json:"export_policy,omitempty"`
+	QosPolicy       *restReference `json:"qos_policy,omitempty"`
+}
+
+type restQuotaRule struct {
+	UUID   string          `json:"uuid,omitempty"`
+	SVM    *restReference  `json:"svm,omitempty"`
+	Volume *restReference  `json:"volume,omitempty"`
+	Qtree  *restQuotaQtree `json:"qtree,omitempty"`
+	Type   string          `json:"type,omitempty"`
+	Space  *restQuotaSpace `json:"space,omitempty"`
+}
+
+// restQuotaQtree identifies the qtree of a quota rule, where an empty name denotes a default rule
+type restQuotaQtree struct {
+	Name string `json:"name"`
+}
+
+type restQuotaSpace struct {
+	HardLimit *int `json:"hard_limit"`
+}
+
+func (q *restQtree) toQtreeInfo() azgo.QtreeInfoType {
+
+	info := azgo.NewQtreeInfoType().
+		SetId(q.ID).
+		SetQtree(q.Name).
+		SetSecurityStyle(q.SecurityStyle).
+		SetVolume("").
+		SetMode("").
+		SetExportPolicy("")
+
+	if q.SVM != nil {
+		info.SetVserver(q.SVM.Name)
+	}
+	if q.Volume != nil {
+		info.SetVolume(q.Volume.Name)
+	}
+	if q.UnixPermissions != nil {
+		info.SetMode(zapiUnixPermissions(*q.UnixPermissions))
+	}
+	if q.ExportPolicy != nil {
+		info.SetExportPolicy(q.ExportPolicy.Name)
+	}
+	return *info
+}
+
+func (r *restQuotaRule) toQuotaEntry() azgo.QuotaEntryType {
+
+	entry := azgo.NewQuotaEntryType().
+		SetQuotaType(r.Type).
+		SetQuotaTarget("").
+		SetQtree("").
+		SetDiskLimit("-")
+
+	if r.SVM != nil {
+		entry.SetVserver(r.SVM.Name)
+	}
+	if r.Volume != nil {
+		entry.SetVolume(r.Volume.Name)
+		if r.Qtree != nil && r.Qtree.Name != "" {
+			entry.SetQtree(r.Qtree.Name).SetQuotaTarget(fmt.Sprintf("/vol/%s/%s", r.Volume.Name, r.Qtree.Name))
+		}
+	}
+	if r.Space != nil && r.Space.HardLimit != nil {
+		entry.SetDiskLimit(strconv.Itoa(*r.Space.HardLimit / 1024))
+	}
+	return *entry
+}
+
+func (c *RestClient) qtreeList(query url.Values) ([]restQtree, error) {
+	var qtrees []restQtree
+	err := c.getRecords("/api/storage/qtrees", query, &qtrees)
+	return qtrees, err
+}
+
+// qtreeGet returns the qtree with the specified path, or a not found error if it doesn't exist
+func (c *RestClient) qtreeGet(path string) (*restQtree, error) {
+
+	volumeName, qtreeName, err := splitQtreePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	qtrees, err := c.qtreeList(c.query("id,volume.uuid", "name", qtreeName, "volume.name", volumeName))
+	if err != nil {
+		return nil, err
+	} else if len(qtrees) == 0 {
+		return nil, restNotFoundError("qtree", path)
+	}
+	return &qtrees[0], nil
+}
+
+// qtreeModify applies the specified changes to the qtree with the specified path
+func (c *RestClient) qtreeModify(path string, qtree *restQtree) error {
+
+	existingQtree, err := c.qtreeGet(path)
+	if err != nil {
+		return err
+	}
+	return c.invoke(http.MethodPatch,
+		fmt.Sprintf("/api/storage/qtrees/%s/%d", existingQtree.Volume.UUID, existingQtree.ID), nil, qtree)
+}
+
+// qtreeGetIter returns the qtrees matching a query as a ZAPI qtree iterator response
+func (c *RestClient) qtreeGetIter(query url.Values) (*azgo.QtreeListIterResponse, error) {
+
+	response := &azgo.QtreeListIterResponse{}
+
+	qtrees, err := c.qtreeList(query)
+	if err == nil {
+		infos := make([]azgo.QtreeInfoType, 0)
+		for _, qtree := range qtrees {
+			infos = append(infos, qtree.toQtreeInfo())
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = &azgo.QtreeListIterResponseResultAttributesList{QtreeInfoPtr: infos}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// QtreeCreate creates a qtree with the specified options
+func (c *RestClient) QtreeCreate(
+	name, volumeName, unixPermissions, exportPolicy, securityStyle, qosPolicy string,
+) (*azgo.QtreeCreateResponse, error) {
+
+	response := &azgo.QtreeCreateResponse{}
+
+	qtree := &restQtree{
+		Name:          name,
+		SVM:           c.svm(),
+		Volume:        &restReference{Name: volumeName},
+		SecurityStyle: securityStyle,
+		ExportPolicy:  &restReference{Name: exportPolicy},
+	}
+	if unixPermissions != "" {
+		permissions, err := restUnixPermissions(unixPermissions)
+		if err != nil {
+			return response, err
+		}
+		qtree.UnixPermissions = &permissions
+	}
+	if qosPolicy != "" {
+		qtree.QosPolicy = &restReference{Name: qosPolicy}
+	}
+
+	err := c.invoke(http.MethodPost, "/api/storage/qtrees", nil, qtree)
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// QtreeRename renames a qtree
+func (c *RestClient) QtreeRename(path, newPath string) (*azgo.QtreeRenameResponse, error) {
+
+	response := &azgo.QtreeRenameResponse{}
+
+	_, newName, err := splitQtreePath(newPath)
+	if err == nil {
+		err = c.qtreeModify(path, &restQtree{Name: newName})
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// QtreeDestroyAsync destroys a qtree in the background
+func (c *RestClient) QtreeDestroyAsync(path string, _ bool) (*azgo.QtreeDeleteAsyncResponse, error) {
+
+	response := &azgo.QtreeDeleteAsyncResponse{}
+
+	qtree, err := c.qtreeGet(path)
+	if err == nil {
+		_, err = c.startJob(http.MethodDelete,
+			fmt.Sprintf("/api/storage/qtrees/%s/%d", qtree.Volume.UUID, qtree.ID), nil, nil)
+	}
+
+	err = setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+	if err != nil {
+		return response, err
+	}
+	if response.Result.ResultStatusAttr == "passed" {
+		inProgress := "in_progress"
+		response.Result.ResultStatusPtr = &inProgress
+	}
+	return response, nil
+}
+
+// QtreeList returns the names of all Qtrees whose names match the supplied prefix
+func (c *RestClient) QtreeList(prefix, volumePrefix string) (*azgo.QtreeListIterResponse, error) {
+	return c.qtreeGetIter(c.query("name,volume.name", "name", prefix+"*", "volume.name", volumePrefix+"*"))
+}
+
+// QtreeCount returns the number of Qtrees in the specified Flexvol, not including the Flexvol itself
+func (c *RestClient) QtreeCount(_ context.Context, volume string) (int, error) {
+
+	qtrees, err := c.qtreeList(c.query("name", "volume.name", volume))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, qtree := range qtrees {
+		if qtree.Name != "" {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// QtreeExists returns true if the named Qtree exists (and is unique in the matching Flexvols)
+func (c *RestClient) QtreeExists(_ context.Context, name, volumePrefix string) (bool, string, error) {
+
+	qtrees, err := c.qtreeList(c.query("name,volume.name", "name", name, "volume.name", volumePrefix+"*"))
+	if err != nil {
+		return false, "", err
+	}
+
+	// Ensure qtree is unique
+	if len(qtrees) != 1 || qtrees[0].Volume == nil {
+		return false, "", nil
+	}
+
+	// Get containing Flexvol
+	return true, qtrees[0].Volume.Name, nil
+}
+
+// QtreeGet returns all relevant details for a single qtree
+func (c *RestClient) QtreeGet(name, volumePrefix string) (*azgo.QtreeInfoType, error) {
+
+	qtrees, err := c.qtreeList(c.query(restQtreeFields, "name", name, "volume.name", volumePrefix+"*"))
+	if err != nil {
+		return &azgo.QtreeInfoType{}, err
+	} else if len(qtrees) == 0 {
+		return &azgo.QtreeInfoType{}, fmt.Errorf("qtree %s not found", name)
+	} else if len(qtrees) > 1 {
+		return &azgo.QtreeInfoType{}, fmt.Errorf("more than one qtree %s found", name)
+	}
+	info := qtrees[0].toQtreeInfo()
+	return &info, nil
+}
+
+// QtreeGetAll returns all relevant details for all qtrees whose Flexvol names match the supplied prefix
+func (c *RestClient) QtreeGetAll(volumePrefix string) (*azgo.QtreeListIterResponse, error) {
+	return c.qtreeGetIter(c.query(restQtreeFields, "volume.name", volumePrefix+"*"))
+}
+
+func (c *RestClient) QtreeModifyExportPolicy(name, volumeName, exportPolicy string) (*azgo.QtreeModifyResponse,
+	error) {
+
+	response := &azgo.QtreeModifyResponse{}
+	err := c.qtreeModify(fmt.Sprintf("/vol/%s/%s", volumeName, name), &restQtree{
+		ExportPolicy: &restReference{Name: exportPolicy},
+	})
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+func (c *RestClient) QtreeModifyUnixPermissions(name, volumeName, unixPermissions string) (
+	*azgo.QtreeModifyResponse, error) {
+
+	response := &azgo.QtreeModifyResponse{}
+
+	permissions, err := restUnixPermissions(unixPermissions)
+	if err != nil {
+		return response, err
+	}
+
+	err = c.qtreeModify(fmt.Sprintf("/vol/%s/%s", volumeName, name), &restQtree{UnixPermissions: &permissions})
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// quotaSetEnabled enables or disables quotas on a Flexvol.  Like the ZAPI equivalents, this doesn't wait
+// for quotas to be initialized or shut down.
+func (c *RestClient) quotaSetEnabled(volume string, enabled bool) error {
+
+	uuid, err := c.volumeUUID(volume)
+	if err != nil {
+		return err
+	}
+	_, err = c.startJob(http.MethodPatch, "/api/storage/volumes/"+uuid, nil, &restVolume{
+		Quota: &restVolumeQuota{Enabled: &enabled},
+	})
+	return err
+}
+
+// QuotaOn enables quotas on a Flexvol
+func (c *RestClient) QuotaOn(volume string) (*azgo.QuotaOnResponse, error) {
+
+	response := &azgo.QuotaOnResponse{}
+	err := c.quotaSetEnabled(volume, true)
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// QuotaOff disables quotas on a Flexvol
+func (c *RestClient) QuotaOff(volume string) (*azgo.QuotaOffResponse, error) {
+
+	response := &azgo.QuotaOffResponse{}
+	err := c.quotaSetEnabled(volume, false)
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// QuotaResize resizes quotas on a Flexvol.  ONTAP resizes quotas automatically when quota rules are changed
+// via the REST API, so this only ensures that the Flexvol exists.
+func (c *RestClient) QuotaResize(volume string) (*azgo.QuotaResizeResponse, error) {
+
+	response := &azgo.QuotaResizeResponse{}
+	_, err := c.volumeUUID(volume)
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// QuotaStatus returns the quota status for a Flexvol.
+func (c *RestClient) QuotaStatus(volume string) (*azgo.QuotaStatusResponse, error) {
+
+	response := &azgo.QuotaStatusResponse{}
+
+	volumes, err := c.volumeList(c.query("quota.state", "name", volume))
+	if err == nil {
+		if len(volumes) == 0 {
+			err = restNotFoundError("volume", volume)
+		} else if volumes[0].Quota != nil {
+			response.Result.SetStatus(volumes[0].Quota.State)
+		}
+	}
+
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// quotaRuleList returns the tree quota rules matching the specified name/value pairs
+func (c *RestClient) quotaRuleList(filters ...string) ([]restQuotaRule, error) {
+	var rules []restQuotaRule
+	err := c.getRecords("/api/storage/quota/rules", c.query(restQuotaRuleFields,
+		append([]string{"type", "tree"}, filters...)...), &rules)
+	return rules, err
+}
+
+// QuotaSetEntry creates a new quota rule with an optional hard disk limit
+func (c *RestClient) QuotaSetEntry(qtreeName, volumeName, quotaTarget, quotaType, diskLimit string) (
+	*azgo.QuotaSetEntryResponse, error) {
+
+	response := &azgo.QuotaSetEntryResponse{}
+
+	// A tree quota targets a qtree path; an empty target denotes a default quota rule
+	if quotaTarget != "" {
+		_, targetQtree, err := splitQtreePath(quotaTarget)
+		if err != nil {
+			return response, err
+		}
+		qtreeName = targetQtree
+	}
+
+	// To create a default quota rule, pass an empty disk limit
+	space := &restQuotaSpace{}
+	if diskLimit != "" && diskLimit != "-" {
+		diskLimitKB, err := strconv.Atoi(diskLimit)
+		if err != nil {
+			return response, fmt.Errorf("invalid disk limit %s; %v", diskLimit, err)
+		}
+		hardLimit := diskLimitKB * 1024
+		space.HardLimit = &hardLimit
+	}
+
+	rules, err := c.quotaRuleList("volume.name", volumeName)
+	if err == nil {
+		var existingRule *restQuotaRule
+		for i := range rules {
+			if rules[i].Qtree != nil && rules[i].Qtree.Name == qtreeName {
+				existingRule = &rules[i]
+				break
+			}
+		}
+
+		if existingRule != nil {
+			err = c.invoke(http.MethodPatch, "/api/storage/quota/rules/"+existingRule.UUID, nil,
+				&restQuotaRule{Space: space})
+		} else {
+			err = c.invoke(http.MethodPost, "/api/storage/quota/rules", nil, &restQuotaRule{
+				SVM:    c.svm(),
+				Volume: &restReference{Name: volumeName},
+				Qtree:  &restQuotaQtree{Name: qtreeName},
+				Type:   quotaType,
+				Space:  space,
+			})
+		}
+	}
+
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// QuotaGetEntry returns the disk limit for a single qtree
+func (c *RestClient) QuotaGetEntry(target string) (*azgo.QuotaEntryType, error) {
+
+	volumeName, qtreeName, err := splitQtreePath(target)
+	if err != nil {
+		return &azgo.QuotaEntryType{}, err
+	}
+
+	rules, err := c.quotaRuleList("volume.name", volumeName, "qtree.name", qtreeName)
+	if err != nil {
+		return &azgo.QuotaEntryType{}, err
+	} else if len(rules) == 0 {
+		return &azgo.QuotaEntryType{}, fmt.Errorf("tree quota for %s not found", target)
+	} else if len(rules) > 1 {
+		return &azgo.QuotaEntryType{}, fmt.Errorf("more than one tree quota for %s found", target)
+	}
+	entry := rules[0].toQuotaEntry()
+	return &entry, nil
+}
+
+// QuotaEntryList returns the disk limit quotas for a Flexvol
+func (c *RestClient) QuotaEntryList(volume string) (*azgo.QuotaListEntriesIterResponse, error) {
+
+	response := &azgo.QuotaListEntriesIterResponse{}
+
+	rules, err := c.quotaRuleList("volume.name", volume)
+	if err == nil {
+		entries := make([]azgo.QuotaEntryType, 0)
+		for _, rule := range rules {
+			entries = append(entries, rule.toQuotaEntry())
+		}
+		response.Result.SetNumRecords(len(entries))
+		response.Result.AttributesListPtr = &azgo.QuotaListEntriesIterResponseResultAttributesList{
+			QuotaEntryPtr: entries,
+		}
+	}
+
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// QTREE operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// EXPORT POLICY operations BEGIN
+
+type restExportPolicy struct {
+	ID   int            `json:"id,omitempty"`
+	Name string         `json:"name,omitempty"`
+	SVM  *restReference `json:"svm,omitempty"`
+}
+
+type restExportRule struct {
+	Index     int                `json:"index,omitempty"`
+	Clients   []restExportClient `json:"clients,omitempty"`
+	Protocols []string           `json:"protocols,omitempty"`
+	RoRule    []string           `json:"ro_rule,omitempty"`
+	RwRule    []string           `json:"rw_rule,omitempty"`
+	Superuser []string           `json:"superuser,omitempty"`
+}
+
+type restExportClient struct {
+	Match string `json:"match"`
+}
+
+// exportPolicyGet returns the named export policy, or a not found error if it doesn't exist
+func (c *RestClient) exportPolicyGet(policy string) (*restExportPolicy, error) {
+
+	var policies []restExportPolicy
+	err := c.getRecords("/api/protocols/nfs/export-policies", c.query("id,name,svm.name", "name", policy), &policies)
+	if err != nil {
+		return nil, err
+	} else if len(policies) == 0 {
+		return nil, restNotFoundError("export policy", policy)
+	}
+	return &policies[0], nil
+}
+
+func (c *RestClient) ExportPolicyCreate(policy string) (*azgo.ExportPolicyCreateResponse, error) {
+
+	response := &azgo.ExportPolicyCreateResponse{}
+
+	_, err := c.exportPolicyGet(policy)
+	if err == nil {
+		err = RestError{Code: azgo.EDUPLICATEENTRY, Message: fmt.Sprintf("export policy %s already exists", policy)}
+	} else if restErr, ok := err.(RestError); ok && restErr.StatusCode == http.StatusNotFound {
+		err = c.invoke(http.MethodPost, "/api/protocols/nfs/export-policies", nil, &restExportPolicy{
+			Name: policy,
+			SVM:  c.svm(),
+		})
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+func (c *RestClient) ExportPolicyGet(policy string) (*azgo.ExportPolicyGetResponse, error) {
+
+	response := &azgo.ExportPolicyGetResponse{}
+
+	exportPolicy, err := c.exportPolicyGet(policy)
+	if err == nil {
+		info := azgo.NewExportPolicyInfoType().
+			SetPolicyId(exportPolicy.ID).
+			SetPolicyName(exportPolicy.Name)
+		if exportPolicy.SVM != nil {
+			info.SetVserver(exportPolicy.SVM.Name)
+		}
+		response.Result.AttributesPtr = &azgo.ExportPolicyGetResponseResultAttributes{ExportPolicyInfoPtr: info}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+func (c *RestClient) ExportPolicyDestroy(policy string) (*azgo.ExportPolicyDestroyResponse, error) {
+
+	response := &azgo.ExportPolicyDestroyResponse{}
+
+	exportPolicy, err := c.exportPolicyGet(policy)
+	if err == nil {
+		err = c.invoke(http.MethodDelete, fmt.Sprintf("/api/protocols/nfs/export-policies/%d", exportPolicy.ID),
+			nil, nil)
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+func (c *RestClient) ExportRuleCreate(
+	policy, clientMatch string,
+	protocols, roSecFlavors, rwSecFlavors, suSecFlavors []string,
+) (*azgo.ExportRuleCreateResponse, error) {
+
+	response := &azgo.ExportRuleCreateResponse{}
+
+	clients := make([]restExportClient, 0)
+	for _, match := range strings.Split(clientMatch, ",") {
+		clients = append(clients, restExportClient{Match: strings.TrimSpace(match)})
+	}
+
+	exportPolicy, err := c.exportPolicyGet(policy)
+	if err == nil {
+		err = c.invoke(http.MethodPost, fmt.Sprintf("/api/protocols/nfs/export-policies/%d/rules", exportPolicy.ID),
+			nil, &restExportRule{
+				Clients:   clients,
+				Protocols: protocols,
+				RoRule:    roSecFlavors,
+				RwRule:    rwSecFlavors,
+				Superuser: suSecFlavors,
+			})
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+func (c *RestClient) ExportRuleGetIterRequest(policy string) (*azgo.ExportRuleGetIterResponse, error) {
+
+	response := &azgo.ExportRuleGetIterResponse{}
+
+	exportPolicy, err := c.exportPolicyGet(policy)
+	if err == nil {
+		var rules []restExportRule
+		err = c.getRecords(fmt.Sprintf("/api/protocols/nfs/export-policies/%d/rules", exportPolicy.ID),
+			url.Values{"fields": {restExportRuleFields}}, &rules)
+		if err == nil {
+			infos := make([]azgo.ExportRuleInfoType, 0)
+			for _, rule := range rules {
+				matches := make([]string, 0)
+				for _, client := range rule.Clients {
+					matches = append(matches, client.Match)
+				}
+				info := azgo.NewExportRuleInfoType().
+					SetPolicyName(policy).
+					SetRuleIndex(rule.Index).
+					SetClientMatch(strings.Join(matches, ",")).
+					SetProtocol(azgo.ExportRuleInfoTypeProtocol{AccessProtocolPtr: rule.Protocols}).
+					SetRoRule(azgo.ExportRuleInfoTypeRoRule{SecurityFlavorPtr: rule.RoRule}).
+					SetRwRule(azgo.ExportRuleInfoTypeRwRule{SecurityFlavorPtr: rule.RwRule}).
+					SetSuperUserSecurity(azgo.ExportRuleInfoTypeSuperUserSecurity{SecurityFlavorPtr: rule.Superuser})
+				infos = append(infos, *info)
+			}
+			response.Result.SetNumRecords(len(infos))
+			response.Result.AttributesListPtr = &azgo.ExportRuleGetIterResponseResultAttributesList{
+				ExportRuleInfoPtr: infos,
+			}
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+func (c *RestClient) ExportRuleDestroy(policy string, ruleIndex int) (*azgo.ExportRuleDestroyResponse, error) {
+
+	response := &azgo.ExportRuleDestroyResponse{}
+
+	exportPolicy, err := c.exportPolicyGet(policy)
+	if err == nil {
+		err = c.invoke(http.MethodDelete,
+			fmt.Sprintf("/api/protocols/nfs/export-policies/%d/rules/%d", exportPolicy.ID, ruleIndex), nil, nil)
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// EXPORT POLICY operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// SNAPSHOT operations BEGIN
+
+type restSnapshot struct {
+	UUID       string         `json:"uuid,omitempty"`
+	Name       string         `json:"name,omitempty"`
+	CreateTime string         `json:"create_time,omitempty"`
+	Volume     *restReference `json:"volume,omitempty"`
+	SVM        *restReference `json:"svm,omitempty"`
+}
+
+// SnapshotCreate creates a snapshot of a volume
+func (c *RestClient) SnapshotCreate(snapshotName, volumeName string) (*azgo.SnapshotCreateResponse, error) {
+
+	response := &azgo.SnapshotCreateResponse{}
+
+	uuid, err := c.volumeUUID(volumeName)
+	if err == nil {
+		err = c.invoke(http.MethodPost, "/api/storage/volumes/"+uuid+"/snapshots", nil,
+			&restSnapshot{Name: snapshotName})
+	}
+
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapshotList returns the list of snapshots associated with a volume
+func (c *RestClient) SnapshotList(volumeName string) (*azgo.SnapshotGetIterResponse, error) {
+
+	response := &azgo.SnapshotGetIterResponse{}
+
+	uuid, err := c.volumeUUID(volumeName)
+	if err == nil {
+		var snapshots []restSnapshot
+		err = c.getRecords("/api/storage/volumes/"+uuid+"/snapshots",
+			url.Values{"fields": {"uuid,name,create_time,volume.name,svm.name"}}, &snapshots)
+		if err == nil {
+			infos := make([]azgo.SnapshotInfoType, 0)
+			for _, snapshot := range snapshots {
+				info := azgo.NewSnapshotInfoType().
+					SetName(snapshot.Name).
+					SetAccessTime(restTimestamp(snapshot.CreateTime)).
+					SetVolume(volumeName).
+					SetVserver(c.config.SVM)
+				infos = append(infos, *info)
+			}
+			response.Result.SetNumRecords(len(infos))
+			response.Result.AttributesListPtr = &azgo.SnapshotGetIterResponseResultAttributesList{
+				SnapshotInfoPtr: infos,
+			}
+		}
+	}
+
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapshotRestoreVolume restores a volume to a snapshot as a non-blocking operation
+func (c *RestClient) SnapshotRestoreVolume(
+	snapshotName, volumeName string,
+) (*azgo.SnapshotRestoreVolumeResponse, error) {
+
+	response := &azgo.SnapshotRestoreVolumeResponse{}
+	err := c.volumeModify(volumeName, &restVolume{
+		RestoreTo: &restVolumeRestoreTo{Snapshot: &restReference{Name: snapshotName}},
+	})
+	return response, setResult(err, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapshotDelete deletes a snapshot of a volume
+func (c *RestClient) SnapshotDelete(snapshotName, volumeName string) (*azgo.SnapshotDeleteResponse, error) {
+
+	response := &azgo.SnapshotDeleteResponse{}
+
+	uuid, err := c.volumeUUID(volumeName)
+	if err == nil {
+		var snapshots []restSnapshot
+		snapshotsPath := "/api/storage/volumes/" + uuid + "/snapshots"
+		if err = c.getRecords(snapshotsPath, url.Values{"name": {snapshotName}}, &snapshots); err == nil {
+			if len(snapshots) == 0 {
+				err = restNotFoundError("snapshot", snapshotName)
+			} else {
+				err = c.invoke(http.MethodDelete, snapshotsPath+"/"+snapshots[0].UUID, nil, nil)
+			}
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SNAPSHOT operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// API feature operations BEGIN
+
+type restCluster struct {
+	Name    string              `json:"name"`
+	UUID    string              `json:"uuid"`
+	Version *restClusterVersion `json:"version"`
+}
+
+type restClusterVersion struct {
+	Full       string `json:"full"`
+	Generation int    `json:"generation"`
+	Major      int    `json:"major"`
+	Minor      int    `json:"minor"`
+}
+
+func (c *RestClient) clusterGet(fields string) (*restCluster, error) {
+	cluster := &restCluster{}
+	err := c.sendRequest(http.MethodGet, "/api/cluster", url.Values{"fields": {fields}}, nil, cluster)
+	return cluster, err
+}
+
+// GetSVMUUID returns the UUID of the SVM managed by this client
+func (c *RestClient) GetSVMUUID() string {
+	return c.SVMUUID
+}
+
+// SupportsFeature returns true if the Ontapi version supports the supplied feature
+func (c *RestClient) SupportsFeature(ctx context.Context, feature feature) bool {
+
+	ontapiVersion, err := c.SystemGetOntapiVersion(ctx)
+	if err != nil {
+		return false
+	}
+	return ontapiSupportsFeature(ontapiVersion, feature)
+}
+
+// API feature operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// ISCSI operations BEGIN
+
+type restIscsiService struct {
+	SVM     *restReference   `json:"svm,omitempty"`
+	Enabled bool             `json:"enabled"`
+	Target  *restIscsiTarget `json:"target,omitempty"`
+}
+
+type restIscsiTarget struct {
+	Name  string `json:"name,omitempty"`
+	Alias string `json:"alias,omitempty"`
+}
+
+func (c *RestClient) iscsiServiceList() ([]restIscsiService, error) {
+	var services []restIscsiService
+	err := c.getRecords("/api/protocols/san/iscsi/services",
+		c.query("svm.name,enabled,target.name,target.alias"), &services)
+	return services, err
+}
+
+// IscsiServiceGetIterRequest returns information about an iSCSI target
+func (c *RestClient) IscsiServiceGetIterRequest() (*azgo.IscsiServiceGetIterResponse, error) {
+
+	response := &azgo.IscsiServiceGetIterResponse{}
+
+	services, err := c.iscsiServiceList()
+	if err == nil {
+		infos := make([]azgo.IscsiServiceInfoType, 0)
+		for _, service := range services {
+			info := azgo.NewIscsiServiceInfoType().SetIsAvailable(service.Enabled)
+			if service.SVM != nil {
+				info.SetVserver(service.SVM.Name)
+			}
+			if service.Target != nil {
+				info.SetNodeName(service.Target.Name).SetAliasName(service.Target.Alias)
+			}
+			infos = append(infos, *info)
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = &azgo.IscsiServiceGetIterResponseResultAttributesList{
+			IscsiServiceInfoPtr: infos,
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IscsiNodeGetNameRequest gets the IQN of the vserver
+func (c *RestClient) IscsiNodeGetNameRequest() (*azgo.IscsiNodeGetNameResponse, error) {
+
+	response := &azgo.IscsiNodeGetNameResponse{}
+
+	services, err := c.iscsiServiceList()
+	if err == nil {
+		if len(services) == 0 || services[0].Target == nil {
+			err = restNotFoundError("iSCSI service in SVM", c.config.SVM)
+		} else {
+			response.Result.SetNodeName(services[0].Target.Name)
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IscsiInterfaceGetIterRequest returns information about the vserver's iSCSI interfaces
+func (c *RestClient) IscsiInterfaceGetIterRequest() (*azgo.IscsiInterfaceGetIterResponse, error) {
+
+	response := &azgo.IscsiInterfaceGetIterResponse{}
+
+	interfaces, err := c.ipInterfaceList("services", "data_iscsi")
+	if err == nil {
+		infos := make([]azgo.IscsiInterfaceListEntryInfoType, 0)
+		for _, ipInterface := range interfaces {
+			info := azgo.NewIscsiInterfaceListEntryInfoType().
+				SetInterfaceName(ipInterface.Name).
+				SetIpAddress(ipInterface.address()).
+				SetIpPort(iscsiTargetPort).
+				SetIsInterfaceEnabled(ipInterface.Enabled).
+				SetCurrentNode(ipInterface.node()).
+				SetCurrentPort(ipInterface.port()).
+				SetVserver(c.config.SVM)
+			infos = append(infos, *info)
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = &azgo.IscsiInterfaceGetIterResponseResultAttributesList{
+			IscsiInterfaceListEntryInfoPtr: infos,
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// ISCSI operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// VSERVER operations BEGIN
+
+type restSVM struct {
+	UUID       string          `json:"uuid,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	State      string          `json:"state,omitempty"`
+	Aggregates []restReference `json:"aggregates,omitempty"`
+}
+
+func (s *restSVM) toVserverInfo() azgo.VserverInfoType {
+
+	aggrInfos := make([]azgo.VserverAggrInfoType, 0)
+	for _, aggregate := range s.Aggregates {
+		aggrInfos = append(aggrInfos, *azgo.NewVserverAggrInfoType().SetAggrName(aggregate.Name))
+	}
+
+	info := azgo.NewVserverInfoType().
+		SetVserverName(s.Name).
+		SetUuid(s.UUID).
+		SetState(s.State).
+		SetVserverType("data").
+		SetVserverAggrInfoList(azgo.VserverInfoTypeVserverAggrInfoList{VserverAggrInfoPtr: aggrInfos})
+	return *info
+}
+
+// svmList returns the SVMs visible to the client's credentials, or only the named one if a name is specified.
+// SVMs are not owned by an SVM, so the query doesn't filter on the client's SVM.
+func (c *RestClient) svmList(name string) ([]restSVM, error) {
+
+	query := url.Values{"fields": {restSVMFields}}
+	if name != "" {
+		query.Set("name", name)
+	}
+
+	var svms []restSVM
+	err := c.getRecords("/api/svm/svms", query, &svms)
+	return svms, err
+}
+
+// svmGet returns the client's SVM, or a not found error if it doesn't exist
+func (c *RestClient) svmGet() (*restSVM, error) {
+
+	svms, err := c.svmList(c.config.SVM)
+	if err != nil {
+		return nil, err
+	}
+	if len(svms) != 1 {
+		return nil, restNotFoundError("SVM", c.config.SVM)
+	}
+	return &svms[0], nil
+}
+
+// VserverGetIterRequest returns the vservers on the system
+func (c *RestClient) VserverGetIterRequest() (*azgo.VserverGetIterResponse, error) {
+
+	response := &azgo.VserverGetIterResponse{}
+
+	svms, err := c.svmList(c.config.SVM)
+	if err == nil {
+		infos := make([]azgo.VserverInfoType, 0)
+		for _, svm := range svms {
+			infos = append(infos, svm.toVserverInfo())
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = &azgo.VserverGetIterResponseResultAttributesList{VserverInfoPtr: infos}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VserverGetIterAdminRequest returns the admin vserver, which the REST API represents as the cluster
+func (c *RestClient) VserverGetIterAdminRequest() (*azgo.VserverGetIterResponse, error) {
+
+	response := &azgo.VserverGetIterResponse{}
+
+	cluster, err := c.clusterGet("name,uuid")
+	if err == nil {
+		info := azgo.NewVserverInfoType().SetVserverName(cluster.Name).SetUuid(cluster.UUID).SetVserverType("admin")
+		response.Result.SetNumRecords(1)
+		response.Result.AttributesListPtr = &azgo.VserverGetIterResponseResultAttributesList{
+			VserverInfoPtr: []azgo.VserverInfoType{*info},
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VserverGetRequest returns vserver to which it is sent
+func (c *RestClient) VserverGetRequest() (*azgo.VserverGetResponse, error) {
+
+	response := &azgo.VserverGetResponse{}
+
+	svm, err := c.svmGet()
+	if err == nil {
+		info := svm.toVserverInfo()
+		response.Result.AttributesPtr = &azgo.VserverGetResponseResultAttributes{VserverInfoPtr: &info}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VserverGetAggregateNames returns an array of names of the aggregates assigned to the configured vserver
+func (c *RestClient) VserverGetAggregateNames() ([]string, error) {
+
+	svms, err := c.svmList(c.config.SVM)
+	if err != nil {
+		return nil, err
+	}
+	if len(svms) != 1 {
+		return nil, fmt.Errorf("could not find SVM %s", c.config.SVM)
+	}
+
+	aggrNames := make([]string, 0, len(svms[0].Aggregates))
+	for _, aggregate := range svms[0].Aggregates {
+		aggrNames = append(aggrNames, aggregate.Name)
+	}
+	return aggrNames, nil
+}
+
+// VserverShowAggrGetIterRequest returns the aggregates on the vserver, with their types and free space.  Only
+// credentials with cluster scope may read aggregate details, so the aggregates are otherwise reported by name only.
+func (c *RestClient) VserverShowAggrGetIterRequest() (*azgo.VserverShowAggrGetIterResponse, error) {
+
+	response := &azgo.VserverShowAggrGetIterResponse{}
+
+	svm, err := c.svmGet()
+	if err == nil && len(svm.Aggregates) > 0 {
+
+		aggrNames := make([]string, 0, len(svm.Aggregates))
+		for _, aggregate := range svm.Aggregates {
+			aggrNames = append(aggrNames, aggregate.Name)
+		}
+
+		var aggregates []restAggregate
+		aggregates, err = c.aggregateList(aggrNames, "name,space.block_storage.available,"+
+			"block_storage.primary.disk_class,block_storage.hybrid_cache.enabled")
+		if restErr, ok := err.(RestError); ok && restErr.StatusCode == http.StatusForbidden {
+			aggregates, err = make([]restAggregate, 0, len(aggrNames)), nil
+			for _, aggrName := range aggrNames {
+				aggregates = append(aggregates, restAggregate{Name: aggrName})
+			}
+		}
+
+		if err == nil {
+			infos := make([]azgo.ShowAggregatesType, 0)
+			for _, aggregate := range aggregates {
+				info := azgo.NewShowAggregatesType().SetAggregateName(aggregate.Name).SetVserverName(svm.Name)
+				if aggrType := aggregate.aggregateType(); aggrType != "" {
+					info.SetAggregateType(aggrType)
+				}
+				if aggregate.Space != nil && aggregate.Space.BlockStorage != nil {
+					info.SetAvailableSize(aggregate.Space.BlockStorage.Available)
+				}
+				infos = append(infos, *info)
+			}
+			response.Result.SetNumRecords(len(infos))
+			response.Result.AttributesListPtr = &azgo.VserverShowAggrGetIterResponseResultAttributesList{
+				ShowAggregatesPtr: infos,
+			}
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// VSERVER operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// AGGREGATE operations BEGIN
+
+type restAggregate struct {
+	Name         string                     `json:"name,omitempty"`
+	Space        *restAggregateSpace        `json:"space,omitempty"`
+	BlockStorage *restAggregateBlockStorage `json:"block_storage,omitempty"`
+}
+
+type restAggregateSpace struct {
+	BlockStorage *restAggregateBlockStorageSpace `json:"block_storage,omitempty"`
+}
+
+type restAggregateBlockStorageSpace struct {
+	Size      int `json:"size,omitempty"`
+	Available int `json:"available,omitempty"`
+	Used      int `json:"used,omitempty"`
+}
+
+type restAggregateBlockStorage struct {
+	Primary     *restAggregatePrimary     `json:"primary,omitempty"`
+	HybridCache *restAggregateHybridCache `json:"hybrid_cache,omitempty"`
+}
+
+type restAggregatePrimary struct {
+	DiskClass string `json:"disk_class,omitempty"`
+}
+
+type restAggregateHybridCache struct {
+	Enabled bool `json:"enabled"`
+}
+
+// aggregateType returns the ZAPI aggregate type corresponding to the class of the aggregate's disks, or an
+// empty string if it isn't known.
+func (a *restAggregate) aggregateType() string {
+
+	if a.BlockStorage == nil {
+		return ""
+	}
+	if a.BlockStorage.HybridCache != nil && a.BlockStorage.HybridCache.Enabled {
+		return "hybrid"
+	}
+	if a.BlockStorage.Primary == nil {
+		return ""
+	}
+
+	switch a.BlockStorage.Primary.DiskClass {
+	case "":
+		return ""
+	case "solid_state", "capacity_flash":
+		return "ssd"
+	case "array":
+		return "lun"
+	case "virtual", "data_center":
+		return "vmdisk"
+	default:
+		return "hdd"
+	}
+}
+
+// aggregateList returns the named aggregates, or all aggregates if no names are specified.  Aggregates are not
+// owned by an SVM, so the query doesn't filter on the client's SVM.
+func (c *RestClient) aggregateList(names []string, fields string) ([]restAggregate, error) {
+
+	query := url.Values{"fields": {fields}}
+	if len(names) > 0 {
+		query.Set("name", strings.Join(names, "|"))
+	}
+
+	var aggregates []restAggregate
+	err := c.getRecords("/api/storage/aggregates", query, &aggregates)
+	return aggregates, err
+}
+
+// AggrSpaceGetIterRequest returns the aggregates on the system
+func (c *RestClient) AggrSpaceGetIterRequest(aggregateName string) (*azgo.AggrSpaceGetIterResponse, error) {
+
+	response := &azgo.AggrSpaceGetIterResponse{}
+
+	var aggrNames []string
+	if aggregateName != "" {
+		aggrNames = []string{aggregateName}
+	}
+
+	aggregates, err := c.aggregateList(aggrNames, "name,space.block_storage.size,space.block_storage.used")
+	if err == nil {
+		infos := make([]azgo.SpaceInformationType, 0)
+		for _, aggregate := range aggregates {
+			info := azgo.NewSpaceInformationType().SetAggregate(aggregate.Name)
+			if aggregate.Space != nil && aggregate.Space.BlockStorage != nil {
+				size, used := aggregate.Space.BlockStorage.Size, aggregate.Space.BlockStorage.Used
+				info.SetAggregateSize(size).SetUsedIncludingSnapshotReserve(used)
+				if size > 0 {
+					info.SetUsedIncludingSnapshotReservePercent(used * 100 / size)
+				}
+			}
+			infos = append(infos, *info)
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = &azgo.AggrSpaceGetIterResponseResultAttributesList{
+			SpaceInformationPtr: infos,
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// AggregateCommitment returns the allocated capacity percentage for an aggregate
+func (c *RestClient) AggregateCommitment(ctx context.Context, aggregate string) (*AggregateCommitment, error) {
+
+	// first, get the aggregate's size
+	aggregates, err := c.aggregateList([]string{aggregate}, "name,space.block_storage.size")
+	if err != nil {
+		return nil, fmt.Errorf("error getting size for aggregate %v: %v", aggregate, err)
+	}
+	if len(aggregates) == 0 || aggregates[0].Space == nil || aggregates[0].Space.BlockStorage == nil {
+		return nil, fmt.Errorf("error getting size for aggregate %v", aggregate)
+	}
+	aggregateSize := aggregates[0].Space.BlockStorage.Size
+
+	// now, get all of the aggregate's volumes
+	var volumes []restVolume
+	query := url.Values{"aggregates.name": {aggregate}, "fields": {"uuid,name,size"}}
+	if err = c.getRecords("/api/storage/volumes", query, &volumes); err != nil {
+		return nil, fmt.Errorf("error enumerating Flexvols: %v", err)
+	}
+
+	totalAllocated := 0.0
+
+	// for each of the aggregate's volumes, compute its potential storage usage
+	for _, volume := range volumes {
+		volAllocated := float64(volume.Size)
+
+		luns, err := c.lunList(url.Values{"location.volume.uuid": {volume.UUID}, "fields": {"name,space.size"}})
+		if err != nil {
+			return nil, fmt.Errorf("error enumerating LUNs for volume %v: %v", volume.Name, err)
+		}
+
+		lunAllocated := 0.0
+		for _, lun := range luns {
+			if lun.Space != nil {
+				lunAllocated += float64(lun.Space.Size)
+			}
+		}
+
+		Logc(ctx).WithFields(log.Fields{
+			"volName":      volume.Name,
+			"volAllocated": volAllocated,
+			"lunAllocated": lunAllocated,
+		}).Debug("Dumping volume")
+
+		if lunAllocated > volAllocated {
+			totalAllocated += lunAllocated
+		} else {
+			totalAllocated += volAllocated
+		}
+	}
+
+	return &AggregateCommitment{
+		TotalAllocated: totalAllocated,
+		AggregateSize:  float64(aggregateSize),
+	}, nil
+}
+
+// AGGREGATE operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// SNAPMIRROR operations BEGIN
+
+type restSnapmirrorRelationship struct {
+	UUID             string                  `json:"uuid,omitempty"`
+	Source           *restSnapmirrorEndpoint `json:"source,omitempty"`
+	Destination      *restSnapmirrorEndpoint `json:"destination,omitempty"`
+	Policy           *restReference          `json:"policy,omitempty"`
+	TransferSchedule *restReference          `json:"transfer_schedule,omitempty"`
+	State            string                  `json:"state,omitempty"`
+	Healthy          *bool                   `json:"healthy,omitempty"`
+	UnhealthyReason  []restSnapmirrorMessage `json:"unhealthy_reason,omitempty"`
+	Transfer         *restSnapmirrorTransfer `json:"transfer,omitempty"`
+}
+
+type restSnapmirrorEndpoint struct {
+	Path string         `json:"path,omitempty"`
+	SVM  *restReference `json:"svm,omitempty"`
+}
+
+type restSnapmirrorMessage struct {
+	Message string `json:"message,omitempty"`
+}
+
+type restSnapmirrorTransfer struct {
+	State string `json:"state,omitempty"`
+}
+
+// snapmirrorPath returns the REST path of a volume in a snapmirror relationship
+func snapmirrorPath(svm, volume string) string {
+	return svm + ":" + volume
+}
+
+// splitSnapmirrorPath returns the SVM and volume names in a snapmirror path.  The volume name is empty in the
+// paths of SVM-DR relationships, which are of the form "<svm>:".
+func splitSnapmirrorPath(path string) (string, string) {
+	elements := strings.SplitN(path, ":", 2)
+	if len(elements) < 2 {
+		return elements[0], ""
+	}
+	return elements[0], elements[1]
+}
+
+func (r *restSnapmirrorRelationship) sourcePath() string {
+	if r.Source == nil {
+		return ""
+	}
+	return r.Source.Path
+}
+
+func (r *restSnapmirrorRelationship) destinationPath() string {
+	if r.Destination == nil {
+		return ""
+	}
+	return r.Destination.Path
+}
+
+// groupType returns the ZAPI relationship group type, which is "vserver" for SVM-DR relationships
+func (r *restSnapmirrorRelationship) groupType() string {
+	if strings.HasSuffix(r.destinationPath(), ":") {
+		return "vserver"
+	}
+	return "none"
+}
+
+// mirrorStatus returns the ZAPI mirror state and relationship status corresponding to the relationship's state
+func (r *restSnapmirrorRelationship) mirrorStatus() (string, string) {
+
+	status := SnapmirrorStatusIdle
+	if r.Transfer != nil && r.Transfer.State == "transferring" {
+		status = SnapmirrorStatusTransferring
+	}
+
+	switch r.State {
+	case "broken_off":
+		return SnapmirrorStateBrokenOff, status
+	case "in_sync":
+		return SnapmirrorStateSnapmirrored, status
+	case "paused":
+		// A relationship is paused as soon as it is quiesced, but isn't quiesced until any transfer completes
+		if status == SnapmirrorStatusTransferring {
+			return SnapmirrorStateSnapmirrored, "quiescing"
+		}
+		return SnapmirrorStateSnapmirrored, SnapmirrorStatusQuiesced
+	default:
+		return strings.ReplaceAll(r.State, "_", "-"), status
+	}
+}
+
+func (r *restSnapmirrorRelationship) toSnapmirrorInfo() azgo.SnapmirrorInfoType {
+
+	mirrorState, status := r.mirrorStatus()
+	sourceSVM, sourceVolume := splitSnapmirrorPath(r.sourcePath())
+	destinationSVM, destinationVolume := splitSnapmirrorPath(r.destinationPath())
+
+	info := azgo.NewSnapmirrorInfoType().
+		SetSourceLocation(r.sourcePath()).
+		SetSourceVserver(sourceSVM).
+		SetSourceVolume(sourceVolume).
+		SetDestinationLocation(r.destinationPath()).
+		SetDestinationVserver(destinationSVM).
+		SetDestinationVolume(destinationVolume).
+		SetRelationshipId(r.UUID).
+		SetRelationshipType(SnapmirrorRelationshipTypeXDP).
+		SetRelationshipGroupType(r.groupType()).
+		SetMirrorState(mirrorState).
+		SetRelationshipStatus(status)
+
+	if r.Policy != nil {
+		info.SetPolicy(r.Policy.Name)
+	}
+	if r.TransferSchedule != nil {
+		info.SetSchedule(r.TransferSchedule.Name)
+	}
+	if r.Healthy != nil {
+		info.SetIsHealthy(*r.Healthy)
+	}
+	if len(r.UnhealthyReason) > 0 {
+		reasons := make([]string, 0, len(r.UnhealthyReason))
+		for _, reason := range r.UnhealthyReason {
+			reasons = append(reasons, reason.Message)
+		}
+		info.SetUnhealthyReason(strings.Join(reasons, "; "))
+	}
+	return *info
+}
+
+func (r *restSnapmirrorRelationship) toSnapmirrorDestinationInfo() azgo.SnapmirrorDestinationInfoType {
+
+	_, status := r.mirrorStatus()
+	sourceSVM, sourceVolume := splitSnapmirrorPath(r.sourcePath())
+	destinationSVM, destinationVolume := splitSnapmirrorPath(r.destinationPath())
+
+	info := azgo.NewSnapmirrorDestinationInfoType().
+		SetSourceLocation(r.sourcePath()).
+		SetSourceVserver(sourceSVM).
+		SetSourceVolume(sourceVolume).
+		SetDestinationLocation(r.destinationPath()).
+		SetDestinationVserver(destinationSVM).
+		SetDestinationVolume(destinationVolume).
+		SetRelationshipId(r.UUID).
+		SetRelationshipType(SnapmirrorRelationshipTypeXDP).
+		SetRelationshipGroupType(r.groupType()).
+		SetRelationshipStatus(status)
+	return *info
+}
+
+func (c *RestClient) snapmirrorList(query url.Values) ([]restSnapmirrorRelationship, error) {
+	var relationships []restSnapmirrorRelationship
+	err := c.getRecords("/api/snapmirror/relationships", query, &relationships)
+	return relationships, err
+}
+
+// snapmirrorGet returns the relationship to the destination volume, from the source volume if one is specified,
+// or a not found error if there is none.  Relationships are read from the destination cluster, unless
+// listDestinations is set, in which case they are read from the source cluster.
+func (c *RestClient) snapmirrorGet(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string, listDestinations bool,
+) (*restSnapmirrorRelationship, error) {
+
+	query := url.Values{
+		"fields":           {restSnapmirrorFields},
+		"destination.path": {snapmirrorPath(destinationSVM, destinationVolume)},
+	}
+	if sourceVolume != "" {
+		query.Set("source.path", snapmirrorPath(sourceSVM, sourceVolume))
+	}
+	if listDestinations {
+		query.Set("list_destinations_only", "true")
+	}
+
+	relationships, err := c.snapmirrorList(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(relationships) == 0 {
+		return nil, restNotFoundError("snapmirror relationship to", snapmirrorPath(destinationSVM, destinationVolume))
+	}
+	return &relationships[0], nil
+}
+
+// snapmirrorSetState changes the state of the relationship to the destination volume
+func (c *RestClient) snapmirrorSetState(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM, state string,
+) error {
+
+	relationship, err := c.snapmirrorGet(destinationVolume, destinationSVM, sourceVolume, sourceSVM, false)
+	if err != nil {
+		return err
+	}
+	return c.invoke(http.MethodPatch, "/api/snapmirror/relationships/"+relationship.UUID, nil,
+		&restSnapmirrorRelationship{State: state})
+}
+
+// SnapmirrorGetIterRequest returns the snapmirror operations on the destination cluster
+func (c *RestClient) SnapmirrorGetIterRequest(relGroupType string) (*azgo.SnapmirrorGetIterResponse, error) {
+
+	response := &azgo.SnapmirrorGetIterResponse{}
+
+	query := url.Values{"fields": {restSnapmirrorFields}, "destination.svm.name": {c.config.SVM}}
+	relationships, err := c.snapmirrorList(query)
+	if err == nil {
+		infos := make([]azgo.SnapmirrorInfoType, 0)
+		for _, relationship := range relationships {
+			if relGroupType == "" || relationship.groupType() == relGroupType {
+				infos = append(infos, relationship.toSnapmirrorInfo())
+			}
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = &azgo.SnapmirrorGetIterResponseResultAttributesList{
+			SnapmirrorInfoPtr: infos,
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapmirrorGetDestinationIterRequest returns the snapmirror operations on the source cluster
+func (c *RestClient) SnapmirrorGetDestinationIterRequest(
+	relGroupType string,
+) (*azgo.SnapmirrorGetDestinationIterResponse, error) {
+
+	response := &azgo.SnapmirrorGetDestinationIterResponse{}
+
+	query := url.Values{
+		"fields":                 {restSnapmirrorFields},
+		"source.svm.name":        {c.config.SVM},
+		"list_destinations_only": {"true"},
+	}
+	relationships, err := c.snapmirrorList(query)
+	if err == nil {
+		infos := make([]azgo.SnapmirrorDestinationInfoType, 0)
+		for _, relationship := range relationships {
+			if relGroupType == "" || relationship.groupType() == relGroupType {
+				infos = append(infos, relationship.toSnapmirrorDestinationInfo())
+			}
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = &azgo.SnapmirrorGetDestinationIterResponseResultAttributesList{
+			SnapmirrorDestinationInfoPtr: infos,
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapmirrorCreate creates a snapmirror relationship between a source and destination volume
+func (c *RestClient) SnapmirrorCreate(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM, replicationPolicy, replicationSchedule string,
+) (*azgo.SnapmirrorCreateResponse, error) {
+
+	response := &azgo.SnapmirrorCreateResponse{}
+
+	relationship := &restSnapmirrorRelationship{
+		Source:      &restSnapmirrorEndpoint{Path: snapmirrorPath(sourceSVM, sourceVolume)},
+		Destination: &restSnapmirrorEndpoint{Path: snapmirrorPath(destinationSVM, destinationVolume)},
+	}
+	if replicationPolicy != "" {
+		relationship.Policy = &restReference{Name: replicationPolicy}
+	}
+	if replicationSchedule != "" {
+		relationship.TransferSchedule = &restReference{Name: replicationSchedule}
+	}
+
+	err := c.invoke(http.MethodPost, "/api/snapmirror/relationships", nil, relationship)
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapmirrorGet returns the snapmirror relationship from a source volume to a destination volume.
+// This must be called on the destination cluster.
+func (c *RestClient) SnapmirrorGet(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+) (*azgo.SnapmirrorInfoType, error) {
+
+	relationship, err := c.snapmirrorGet(destinationVolume, destinationSVM, sourceVolume, sourceSVM, false)
+	if restErr, ok := err.(RestError); ok && restErr.StatusCode == http.StatusNotFound {
+		return nil, utils.NotFoundError(fmt.Sprintf("snapmirror relationship from %s:%s to %s:%s not found",
+			sourceSVM, sourceVolume, destinationSVM, destinationVolume))
+	} else if err != nil {
+		return nil, err
+	}
+
+	info := relationship.toSnapmirrorInfo()
+	return &info, nil
+}
+
+// SnapmirrorInitialize initializes a snapmirror relationship
+func (c *RestClient) SnapmirrorInitialize(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+) (*azgo.SnapmirrorInitializeResponse, error) {
+
+	response := &azgo.SnapmirrorInitializeResponse{}
+
+	err := c.snapmirrorSetState(destinationVolume, destinationSVM, sourceVolume, sourceSVM, "snapmirrored")
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapmirrorResync resyncs a snapmirror relationship
+func (c *RestClient) SnapmirrorResync(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+) (*azgo.SnapmirrorResyncResponse, error) {
+
+	response := &azgo.SnapmirrorResyncResponse{}
+
+	err := c.snapmirrorSetState(destinationVolume, destinationSVM, sourceVolume, sourceSVM, "snapmirrored")
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapmirrorUpdate starts a transfer on a snapmirror relationship
+func (c *RestClient) SnapmirrorUpdate(
+	destinationVolume, destinationSVM string,
+) (*azgo.SnapmirrorUpdateResponse, error) {
+
+	response := &azgo.SnapmirrorUpdateResponse{}
+
+	relationship, err := c.snapmirrorGet(destinationVolume, destinationSVM, "", "", false)
+	if err == nil {
+		err = c.invoke(http.MethodPost, "/api/snapmirror/relationships/"+relationship.UUID+"/transfers", nil,
+			&struct{}{})
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapmirrorQuiesce quiesces a snapmirror relationship
+func (c *RestClient) SnapmirrorQuiesce(
+	destinationVolume, destinationSVM string,
+) (*azgo.SnapmirrorQuiesceResponse, error) {
+
+	response := &azgo.SnapmirrorQuiesceResponse{}
+
+	err := c.snapmirrorSetState(destinationVolume, destinationSVM, "", "", "paused")
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapmirrorBreak breaks a snapmirror relationship
+func (c *RestClient) SnapmirrorBreak(destinationVolume, destinationSVM string) (*azgo.SnapmirrorBreakResponse, error) {
+
+	response := &azgo.SnapmirrorBreakResponse{}
+
+	err := c.snapmirrorSetState(destinationVolume, destinationSVM, "", "", "broken_off")
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapmirrorDestroy removes a snapmirror relationship on the destination cluster
+func (c *RestClient) SnapmirrorDestroy(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+) (*azgo.SnapmirrorDestroyResponse, error) {
+
+	response := &azgo.SnapmirrorDestroyResponse{}
+
+	relationship, err := c.snapmirrorGet(destinationVolume, destinationSVM, sourceVolume, sourceSVM, false)
+	if err == nil {
+		err = c.invoke(http.MethodDelete, "/api/snapmirror/relationships/"+relationship.UUID,
+			url.Values{"destination_only": {"true"}}, nil)
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SnapmirrorRelease removes a snapmirror relationship on the source cluster
+func (c *RestClient) SnapmirrorRelease(
+	destinationVolume, destinationSVM, sourceVolume, sourceSVM string,
+) (*azgo.SnapmirrorReleaseResponse, error) {
+
+	response := &azgo.SnapmirrorReleaseResponse{}
+
+	relationship, err := c.snapmirrorGet(destinationVolume, destinationSVM, sourceVolume, sourceSVM, true)
+	if err == nil {
+		err = c.invoke(http.MethodDelete, "/api/snapmirror/relationships/"+relationship.UUID,
+			url.Values{"source_only": {"true"}}, nil)
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IsVserverDRDestination identifies if the Vserver is a destination vserver of Snapmirror relationship (SVM-DR) or not
+func (c *RestClient) IsVserverDRDestination(ctx context.Context) (bool, error) {
+	return isVserverDRDestination(ctx, c)
+}
+
+// IsVserverDRSource identifies if the Vserver is a source vserver of Snapmirror relationship (SVM-DR) or not
+func (c *RestClient) IsVserverDRSource(ctx context.Context) (bool, error) {
+	return isVserverDRSource(ctx, c)
+}
+
+// SNAPMIRROR operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// MISC operations BEGIN
+
+type restIPInterface struct {
+	Name     string                   `json:"name,omitempty"`
+	IP       *restIPInterfaceIP       `json:"ip,omitempty"`
+	Location *restIPInterfaceLocation `json:"location,omitempty"`
+	Enabled  bool                     `json:"enabled"`
+	State    string                   `json:"state,omitempty"`
+	Services []string                 `json:"services,omitempty"`
+	SVM      *restReference           `json:"svm,omitempty"`
+}
+
+type restIPInterfaceIP struct {
+	Address string `json:"address,omitempty"`
+}
+
+type restIPInterfaceLocation struct {
+	Node *restReference `json:"node,omitempty"`
+	Port *restReference `json:"port,omitempty"`
+}
+
+// restDataProtocols maps the data services of IP interfaces to the corresponding ZAPI data protocols
+var restDataProtocols = map[string]string{
+	"data_nfs":      "nfs",
+	"data_cifs":     "cifs",
+	"data_iscsi":    "iscsi",
+	"data_nvme_tcp": "nvme-tcp",
+}
+
+func (i *restIPInterface) address() string {
+	if i.IP == nil {
+		return ""
+	}
+	return i.IP.Address
+}
+
+func (i *restIPInterface) node() string {
+	if i.Location == nil || i.Location.Node == nil {
+		return ""
+	}
+	return i.Location.Node.Name
+}
+
+func (i *restIPInterface) port() string {
+	if i.Location == nil || i.Location.Port == nil {
+		return ""
+	}
+	return i.Location.Port.Name
+}
+
+func (i *restIPInterface) toNetInterfaceInfo() azgo.NetInterfaceInfoType {
+
+	dataProtocols := make([]string, 0)
+	for _, service := range i.Services {
+		if protocol, ok := restDataProtocols[service]; ok {
+			dataProtocols = append(dataProtocols, protocol)
+		}
+	}
+
+	info := azgo.NewNetInterfaceInfoType().
+		SetInterfaceName(i.Name).
+		SetAddress(i.address()).
+		SetCurrentNode(i.node()).
+		SetCurrentPort(i.port()).
+		SetOperationalStatus(i.State).
+		SetDataProtocols(azgo.NetInterfaceInfoTypeDataProtocols{DataProtocolPtr: dataProtocols})
+	if i.SVM != nil {
+		info.SetVserver(i.SVM.Name)
+	}
+	return *info
+}
+
+func (c *RestClient) ipInterfaceList(filters ...string) ([]restIPInterface, error) {
+	var interfaces []restIPInterface
+	err := c.getRecords("/api/network/ip/interfaces", c.query(restInterfaceFields, filters...), &interfaces)
+	return interfaces, err
+}
+
+// NetInterfaceGet returns the list of network interfaces with associated metadata, but only those LIFs that
+// are operational
+func (c *RestClient) NetInterfaceGet() (*azgo.NetInterfaceGetIterResponse, error) {
+
+	response := &azgo.NetInterfaceGetIterResponse{}
+
+	interfaces, err := c.ipInterfaceList("state", LifOperationalStatusUp)
+	if err == nil {
+		infos := make([]azgo.NetInterfaceInfoType, 0)
+		for _, ipInterface := range interfaces {
+			infos = append(infos, ipInterface.toNetInterfaceInfo())
+		}
+		response.Result.SetNumRecords(len(infos))
+		response.Result.AttributesListPtr = &azgo.NetInterfaceGetIterResponseResultAttributesList{
+			NetInterfaceInfoPtr: infos,
+		}
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+func (c *RestClient) NetInterfaceGetDataLIFsNode(ctx context.Context, ip string) (string, error) {
+	return netInterfaceGetDataLIFsNode(ctx, c, ip)
+}
+
+func (c *RestClient) NetInterfaceGetDataLIFs(ctx context.Context, protocol string) ([]string, error) {
+	return netInterfaceGetDataLIFs(ctx, c, protocol)
+}
+
+// SystemGetVersion returns the system version
+func (c *RestClient) SystemGetVersion() (*azgo.SystemGetVersionResponse, error) {
+
+	response := &azgo.SystemGetVersionResponse{}
+
+	cluster, err := c.clusterGet("version")
+	if err == nil && cluster.Version != nil {
+		response.Result.SetVersion(cluster.Version.Full).SetIsClustered(true)
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// SystemGetOntapiVersion returns the ONTAPI version corresponding to the cluster's ONTAP version, and caches
+// the result.  Since ONTAP 9.0, whose ONTAPI version is 1.100, each release has added 10 to the minor version.
+func (c *RestClient) SystemGetOntapiVersion(ctx context.Context) (string, error) {
+
+	c.versionLock.Lock()
+	defer c.versionLock.Unlock()
+
+	if c.ontapiVersion == "" {
+		cluster, err := c.clusterGet("version")
+		if err != nil {
+			return "", fmt.Errorf("could not read ONTAP version: %v", err)
+		}
+		if cluster.Version == nil {
+			return "", errors.New("could not read ONTAP version")
+		}
+		version := cluster.Version
+		c.ontapiVersion = fmt.Sprintf("1.%d", (version.Generation-8)*100+version.Major*10)
+	}
+
+	return c.ontapiVersion, nil
+}
+
+func (c *RestClient) NodeListSerialNumbers(ctx context.Context) ([]string, error) {
+
+	serialNumbers := make([]string, 0)
+
+	var nodes []struct {
+		SerialNumber string `json:"serial_number"`
+	}
+	query := url.Values{"fields": {"serial_number"}}
+	if err := c.getRecords("/api/cluster/nodes", query, &nodes); err != nil {
+		return serialNumbers, err
+	}
+
+	if len(nodes) == 0 {
+		return serialNumbers, errors.New("could not get node info")
+	}
+
+	for _, node := range nodes {
+		if node.SerialNumber != "" {
+			serialNumbers = append(serialNumbers, node.SerialNumber)
+		}
+	}
+
+	if len(serialNumbers) == 0 {
+		return serialNumbers, errors.New("could not get node serial numbers")
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"Count":         len(serialNumbers),
+		"SerialNumbers": strings.Join(serialNumbers, ","),
+	}).Debug("Read serial numbers.")
+
+	return serialNumbers, nil
+}
+
+type restEmsApplicationLog struct {
+	ComputerName        string `json:"computer_name"`
+	EventID             int    `json:"event_id"`
+	EventSource         string `json:"event_source"`
+	AppVersion          string `json:"app_version"`
+	Category            string `json:"category"`
+	EventDescription    string `json:"event_description"`
+	Severity            string `json:"severity"`
+	AutosupportRequired bool   `json:"autosupport_required"`
+}
+
+// restEmsSeverities maps the ZAPI EMS log levels to the severities of the REST API, which has fewer of them
+var restEmsSeverities = []string{
+	"emergency", "alert", "error", "error", "error", "notice", "informational", "debug",
+}
+
+// EmsAutosupportLog generates an auto support message with the supplied parameters.  Requires ONTAP 9.11.1 or
+// later, which is the first release whose REST API accepts application logs.
+func (c *RestClient) EmsAutosupportLog(
+	appVersion string, autoSupport bool, category string, computerName string,
+	eventDescription string, eventID int, eventSource string, logLevel int,
+) (*azgo.EmsAutosupportLogResponse, error) {
+
+	response := &azgo.EmsAutosupportLogResponse{}
+
+	severity := "informational"
+	if logLevel >= 0 && logLevel < len(restEmsSeverities) {
+		severity = restEmsSeverities[logLevel]
+	}
+
+	err := c.invoke(http.MethodPost, "/api/support/ems/application-logs", nil, &restEmsApplicationLog{
+		ComputerName:        computerName,
+		EventID:             eventID,
+		EventSource:         eventSource,
+		AppVersion:          appVersion,
+		Category:            category,
+		EventDescription:    eventDescription,
+		Severity:            severity,
+		AutosupportRequired: autoSupport,
+	})
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// TieringPolicyValue returns the default tiering policy, as described for Client.TieringPolicyValue
+func (c *RestClient) TieringPolicyValue(ctx context.Context) string {
+	return tieringPolicyValue(ctx, c)
+}
+
+// MISC operations END
+/////////////////////////////////////////////////////////////////////////////
+
+/////////////////////////////////////////////////////////////////////////////
+// iSCSI initiator operations BEGIN
+
+const restIscsiDefaultInitiator = "default"
+
+type restIscsiCredentials struct {
+	SVM                *restReference `json:"svm,omitempty"`
+	Initiator          string         `json:"initiator,omitempty"`
+	AuthenticationType string         `json:"authentication_type,omitempty"`
+	Chap               *restIscsiChap `json:"chap,omitempty"`
+}
+
+type restIscsiChap struct {
+	Inbound  *restIscsiChapUser `json:"inbound,omitempty"`
+	Outbound *restIscsiChapUser `json:"outbound,omitempty"`
+}
+
+type restIscsiChapUser struct {
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+type restIscsiSession struct {
+	SVM                  *restReference             `json:"svm,omitempty"`
+	Initiator            *restIscsiSessionInitiator `json:"initiator,omitempty"`
+	Isid                 string                     `json:"isid,omitempty"`
+	Tsih                 int                        `json:"tsih,omitempty"`
+	TargetPortalGroup    string                     `json:"target_portal_group,omitempty"`
+	TargetPortalGroupTag int                        `json:"target_portal_group_tag,omitempty"`
+}
+
+type restIscsiSessionInitiator struct {
+	Name  string `json:"name,omitempty"`
+	Alias string `json:"alias,omitempty"`
+}
+
+// restAuthType converts a ZAPI iSCSI authentication type to the form used by the REST API
+func restAuthType(authType string) string {
+	return strings.ToLower(authType)
+}
+
+// zapiAuthType converts a REST iSCSI authentication type to the form used by ZAPI
+func zapiAuthType(authType string) string {
+	if authType == "chap" {
+		return "CHAP"
+	}
+	return authType
+}
+
+// newRestIscsiCredentials returns the REST representation of the CHAP credentials of an initiator.  Outbound
+// credentials are only included if both the user name and passphrase are specified.
+func newRestIscsiCredentials(userName, passphrase, outboundUserName, outboundPassphrase string) *restIscsiChap {
+	chap := &restIscsiChap{Inbound: &restIscsiChapUser{User: userName, Password: passphrase}}
+	if outboundUserName != "" && outboundPassphrase != "" {
+		chap.Outbound = &restIscsiChapUser{User: outboundUserName, Password: outboundPassphrase}
+	}
+	return chap
+}
+
+func (r *restIscsiCredentials) users() (string, string) {
+	var inboundUser, outboundUser string
+	if r.Chap != nil && r.Chap.Inbound != nil {
+		inboundUser = r.Chap.Inbound.User
+	}
+	if r.Chap != nil && r.Chap.Outbound != nil {
+		outboundUser = r.Chap.Outbound.User
+	}
+	return inboundUser, outboundUser
+}
+
+func (c *RestClient) iscsiCredentialsPath(initiator string) string {
+	return "/api/protocols/san/iscsi/credentials/" + c.SVMUUID + "/" + url.PathEscape(initiator)
+}
+
+func (c *RestClient) iscsiCredentialsGet(initiator string) (*restIscsiCredentials, error) {
+	credentials := &restIscsiCredentials{}
+	err := c.sendRequest(http.MethodGet, c.iscsiCredentialsPath(initiator),
+		url.Values{"fields": {restIscsiCredentialsFields}}, nil, credentials)
+	return credentials, err
+}
+
+// IscsiInitiatorAddAuth creates and sets the authorization details for a single initiator
+func (c *RestClient) IscsiInitiatorAddAuth(
+	initiator, authType, userName, passphrase, outboundUserName, outboundPassphrase string,
+) (*azgo.IscsiInitiatorAddAuthResponse, error) {
+
+	response := &azgo.IscsiInitiatorAddAuthResponse{}
+
+	err := c.invoke(http.MethodPost, "/api/protocols/san/iscsi/credentials", nil, &restIscsiCredentials{
+		SVM:                c.svm(),
+		Initiator:          initiator,
+		AuthenticationType: restAuthType(authType),
+		Chap:               newRestIscsiCredentials(userName, passphrase, outboundUserName, outboundPassphrase),
+	})
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IscsiInitiatorAuthGetIter returns the authorization details for all non-default initiators for the client's SVM
+func (c *RestClient) IscsiInitiatorAuthGetIter() ([]azgo.IscsiSecurityEntryInfoType, error) {
+
+	var credentials []restIscsiCredentials
+	err := c.getRecords("/api/protocols/san/iscsi/credentials", c.query(restIscsiCredentialsFields), &credentials)
+	if err != nil {
+		return []azgo.IscsiSecurityEntryInfoType{}, err
+	}
+
+	infos := make([]azgo.IscsiSecurityEntryInfoType, 0)
+	for _, credential := range credentials {
+		if credential.Initiator == restIscsiDefaultInitiator {
+			continue
+		}
+		userName, outboundUserName := credential.users()
+		info := azgo.NewIscsiSecurityEntryInfoType().
+			SetInitiator(credential.Initiator).
+			SetAuthType(zapiAuthType(credential.AuthenticationType)).
+			SetUserName(userName).
+			SetOutboundUserName(outboundUserName).
+			SetVserver(c.config.SVM)
+		infos = append(infos, *info)
+	}
+
+	if len(infos) == 0 {
+		return []azgo.IscsiSecurityEntryInfoType{}, fmt.Errorf("no iscsi security entries found")
+	}
+	return infos, nil
+}
+
+// IscsiInitiatorDeleteAuth deletes the authorization details for a single initiator
+func (c *RestClient) IscsiInitiatorDeleteAuth(initiator string) (*azgo.IscsiInitiatorDeleteAuthResponse, error) {
+
+	response := &azgo.IscsiInitiatorDeleteAuthResponse{}
+
+	err := c.invoke(http.MethodDelete, c.iscsiCredentialsPath(initiator), nil, nil)
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IscsiInitiatorGetAuth returns the authorization details for a single initiator
+func (c *RestClient) IscsiInitiatorGetAuth(initiator string) (*azgo.IscsiInitiatorGetAuthResponse, error) {
+
+	response := &azgo.IscsiInitiatorGetAuthResponse{}
+
+	credentials, err := c.iscsiCredentialsGet(initiator)
+	if err == nil {
+		userName, outboundUserName := credentials.users()
+		response.Result.
+			SetAuthType(zapiAuthType(credentials.AuthenticationType)).
+			SetUserName(userName).
+			SetOutboundUserName(outboundUserName)
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IscsiInitiatorGetDefaultAuth returns the authorization details for the default initiator
+func (c *RestClient) IscsiInitiatorGetDefaultAuth() (*azgo.IscsiInitiatorGetDefaultAuthResponse, error) {
+
+	response := &azgo.IscsiInitiatorGetDefaultAuthResponse{}
+
+	credentials, err := c.iscsiCredentialsGet(restIscsiDefaultInitiator)
+	if err == nil {
+		userName, outboundUserName := credentials.users()
+		response.Result.
+			SetAuthType(zapiAuthType(credentials.AuthenticationType)).
+			SetUserName(userName).
+			SetOutboundUserName(outboundUserName)
+	}
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IscsiInitiatorGetIter returns the initiator details for all initiators logged in to the client's SVM
+func (c *RestClient) IscsiInitiatorGetIter() ([]azgo.IscsiInitiatorListEntryInfoType, error) {
+
+	var sessions []restIscsiSession
+	query := c.query("svm.name,initiator.name,initiator.alias,isid,tsih,target_portal_group,target_portal_group_tag")
+	if err := c.getRecords("/api/protocols/san/iscsi/sessions", query, &sessions); err != nil {
+		return []azgo.IscsiInitiatorListEntryInfoType{}, err
+	}
+
+	infos := make([]azgo.IscsiInitiatorListEntryInfoType, 0)
+	for _, session := range sessions {
+		info := azgo.NewIscsiInitiatorListEntryInfoType().
+			SetIsid(session.Isid).
+			SetTargetSessionId(session.Tsih).
+			SetTpgroupName(session.TargetPortalGroup).
+			SetTpgroupTag(session.TargetPortalGroupTag).
+			SetVserver(c.config.SVM)
+		if session.Initiator != nil {
+			info.SetInitiatorNodename(session.Initiator.Name).SetInitiatorAliasname(session.Initiator.Alias)
+		}
+		infos = append(infos, *info)
+	}
+
+	if len(infos) == 0 {
+		return []azgo.IscsiInitiatorListEntryInfoType{}, fmt.Errorf("no iscsi initiator entries found")
+	}
+	return infos, nil
+}
+
+// IscsiInitiatorModifyCHAPParams modifies the authorization details for a single initiator
+func (c *RestClient) IscsiInitiatorModifyCHAPParams(
+	initiator, userName, passphrase, outboundUserName, outboundPassphrase string,
+) (*azgo.IscsiInitiatorModifyChapParamsResponse, error) {
+
+	response := &azgo.IscsiInitiatorModifyChapParamsResponse{}
+
+	err := c.invoke(http.MethodPatch, c.iscsiCredentialsPath(initiator), nil, &restIscsiCredentials{
+		Chap: newRestIscsiCredentials(userName, passphrase, outboundUserName, outboundPassphrase),
+	})
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// IscsiInitiatorSetDefaultAuth sets the authorization details for the default initiator
+func (c *RestClient) IscsiInitiatorSetDefaultAuth(
+	authType, userName, passphrase, outboundUserName, outboundPassphrase string,
+) (*azgo.IscsiInitiatorSetDefaultAuthResponse, error) {
+
+	response := &azgo.IscsiInitiatorSetDefaultAuthResponse{}
+
+	credentials := &restIscsiCredentials{AuthenticationType: restAuthType(authType)}
+	if credentials.AuthenticationType == "chap" {
+		credentials.Chap = newRestIscsiCredentials(userName, passphrase, outboundUserName, outboundPassphrase)
+	}
+	err := c.invoke(http.MethodPatch, c.iscsiCredentialsPath(restIscsiDefaultInitiator), nil, credentials)
+
+	return response, setResult(err, azgo.EOBJECTNOTFOUND,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr)
+}
+
+// iSCSI initiator operations END
+/////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,441 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	restNamespaceFields = "uuid,name,location.volume.name,os_type,space.size,comment,status.state,status.mapped," +
+		"subsystem_map.subsystem.name,svm.name"
+	restSubsystemFields = "uuid,name,target_nqn,hosts.nqn,svm.name"
+)
+
+// NVMeNamespace describes an ONTAP NVMe namespace
+type NVMeNamespace struct {
+	UUID      string
+	Name      string
+	Volume    string
+	OsType    string
+	Size      int
+	Comment   string
+	State     string
+	Mapped    bool
+	Subsystem string
+}
+
+// NVMeSubsystem describes an ONTAP NVMe subsystem and the host NQNs allowed to access it
+type NVMeSubsystem struct {
+	UUID  string
+	Name  string
+	NQN   string
+	Hosts []string
+}
+
+// NVMeAPI defines the NVMe namespace and subsystem operations, which are only available via the REST API.
+// Lookups of objects that don't exist return a not found error, so callers may check for them with
+// utils.IsNotFoundError.
+type NVMeAPI interface {
+	NVMeNamespaceCreate(ctx context.Context, name string, sizeBytes int, osType, comment string) error
+	NVMeNamespaceGet(ctx context.Context, name string) (*NVMeNamespace, error)
+	NVMeNamespaceGetAll(ctx context.Context, pathPattern string) ([]NVMeNamespace, error)
+	NVMeNamespaceSetSize(ctx context.Context, name string, sizeBytes int) (int, error)
+	NVMeNamespaceSetComment(ctx context.Context, name, comment string) error
+	NVMeNamespaceDelete(ctx context.Context, name string) error
+	NVMeSubsystemCreate(ctx context.Context, name string) (*NVMeSubsystem, error)
+	NVMeSubsystemGet(ctx context.Context, name string) (*NVMeSubsystem, error)
+	NVMeSubsystemDelete(ctx context.Context, name string) error
+	NVMeSubsystemAddHost(ctx context.Context, subsystemName, hostNQN string) error
+	NVMeSubsystemRemoveHost(ctx context.Context, subsystemName, hostNQN string) error
+	NVMeNamespaceMap(ctx context.Context, subsystemName, namespaceName string) error
+	NVMeNamespaceUnmap(ctx context.Context, subsystemName, namespaceName string) error
+	NVMeDataLIFs(ctx context.Context) ([]string, error)
+}
+
+var _ NVMeAPI = &RestClient{}
+
+/////////////////////////////////////////////////////////////////////////////
+// NVMe operations BEGIN
+
+type restNamespace struct {
+	UUID         string                     `json:"uuid,omitempty"`
+	Name         string                     `json:"name,omitempty"`
+	SVM          *restReference             `json:"svm,omitempty"`
+	Location     *restLunLocation           `json:"location,omitempty"`
+	OsType       string                     `json:"os_type,omitempty"`
+	Space        *restNamespaceSpace        `json:"space,omitempty"`
+	Comment      *string                    `json:"comment,omitempty"`
+	Status       *restLunStatus             `json:"status,omitempty"`
+	SubsystemMap *restNamespaceSubsystemMap `json:"subsystem_map,omitempty"`
+}
+
+type restNamespaceSpace struct {
+	Size int `json:"size,omitempty"`
+}
+
+type restNamespaceSubsystemMap struct {
+	Subsystem *restReference `json:"subsystem,omitempty"`
+}
+
+type restSubsystem struct {
+	UUID      string              `json:"uuid,omitempty"`
+	Name      string              `json:"name,omitempty"`
+	SVM       *restReference      `json:"svm,omitempty"`
+	OsType    string              `json:"os_type,omitempty"`
+	TargetNQN string              `json:"target_nqn,omitempty"`
+	Hosts     []restSubsystemHost `json:"hosts,omitempty"`
+}
+
+type restSubsystemHost struct {
+	NQN string `json:"nqn"`
+}
+
+type restSubsystemMap struct {
+	SVM       *restReference `json:"svm,omitempty"`
+	Subsystem *restReference `json:"subsystem,omitempty"`
+	Namespace *restReference `json:"namespace,omitempty"`
+}
+
+func (n *restNamespace) toNVMeNamespace() NVMeNamespace {
+
+	namespace := NVMeNamespace{
+		UUID:   n.UUID,
+		Name:   n.Name,
+		OsType: n.OsType,
+	}
+	if n.Location != nil && n.Location.Volume != nil {
+		namespace.Volume = n.Location.Volume.Name
+	}
+	if n.Space != nil {
+		namespace.Size = n.Space.Size
+	}
+	if n.Comment != nil {
+		namespace.Comment = *n.Comment
+	}
+	if n.Status != nil {
+		namespace.State = n.Status.State
+		namespace.Mapped = n.Status.Mapped
+	}
+	if n.SubsystemMap != nil && n.SubsystemMap.Subsystem != nil {
+		namespace.Subsystem = n.SubsystemMap.Subsystem.Name
+	}
+	return namespace
+}
+
+func (s *restSubsystem) toNVMeSubsystem() NVMeSubsystem {
+
+	subsystem := NVMeSubsystem{
+		UUID:  s.UUID,
+		Name:  s.Name,
+		NQN:   s.TargetNQN,
+		Hosts: make([]string, 0, len(s.Hosts)),
+	}
+	for _, host := range s.Hosts {
+		subsystem.Hosts = append(subsystem.Hosts, host.NQN)
+	}
+	return subsystem
+}
+
+// notFoundToUtilsError converts a REST not found error to the equivalent utils error, so that callers of the
+// NVMe operations need not know about REST errors.
+func notFoundToUtilsError(err error) error {
+	if restErr, ok := err.(RestError); ok && restErr.StatusCode == http.StatusNotFound {
+		return utils.NotFoundError(restErr.Message)
+	}
+	return err
+}
+
+func (c *RestClient) namespaceList(query url.Values) ([]restNamespace, error) {
+	var namespaces []restNamespace
+	err := c.getRecords("/api/storage/namespaces", query, &namespaces)
+	return namespaces, err
+}
+
+// namespaceGet returns the namespace with the specified path, or a not found error if it doesn't exist
+func (c *RestClient) namespaceGet(name, fields string) (*restNamespace, error) {
+
+	namespaces, err := c.namespaceList(c.query(fields, "name", name))
+	if err != nil {
+		return nil, err
+	} else if len(namespaces) == 0 {
+		return nil, restNotFoundError("namespace", name)
+	}
+	return &namespaces[0], nil
+}
+
+// subsystemGet returns the named subsystem, or a not found error if it doesn't exist
+func (c *RestClient) subsystemGet(name string) (*restSubsystem, error) {
+
+	var subsystems []restSubsystem
+	err := c.getRecords("/api/protocols/nvme/subsystems", c.query(restSubsystemFields, "name", name), &subsystems)
+	if err != nil {
+		return nil, err
+	} else if len(subsystems) == 0 {
+		return nil, restNotFoundError("subsystem", name)
+	}
+	return &subsystems[0], nil
+}
+
+// NVMeNamespaceCreate creates a namespace with the specified path, such as /vol/myVolume/namespace0
+func (c *RestClient) NVMeNamespaceCreate(
+	ctx context.Context, name string, sizeBytes int, osType, comment string,
+) error {
+
+	Logc(ctx).WithFields(log.Fields{
+		"name":      name,
+		"sizeBytes": sizeBytes,
+	}).Debug("Creating namespace.")
+
+	return c.invoke(http.MethodPost, "/api/storage/namespaces", nil, &restNamespace{
+		Name:    name,
+		SVM:     c.svm(),
+		OsType:  osType,
+		Space:   &restNamespaceSpace{Size: sizeBytes},
+		Comment: &comment,
+	})
+}
+
+// NVMeNamespaceGet returns the namespace with the specified path
+func (c *RestClient) NVMeNamespaceGet(_ context.Context, name string) (*NVMeNamespace, error) {
+
+	namespace, err := c.namespaceGet(name, restNamespaceFields)
+	if err != nil {
+		return nil, notFoundToUtilsError(err)
+	}
+	result := namespace.toNVMeNamespace()
+	return &result, nil
+}
+
+// NVMeNamespaceGetAll returns all namespaces whose paths match a pattern, such as /vol/trident_*/namespace0
+func (c *RestClient) NVMeNamespaceGetAll(_ context.Context, pathPattern string) ([]NVMeNamespace, error) {
+
+	namespaces, err := c.namespaceList(c.query(restNamespaceFields, "name", pathPattern))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]NVMeNamespace, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		result = append(result, namespace.toNVMeNamespace())
+	}
+	return result, nil
+}
+
+// NVMeNamespaceSetSize resizes a namespace and returns its new size, which ONTAP may round up
+func (c *RestClient) NVMeNamespaceSetSize(ctx context.Context, name string, sizeBytes int) (int, error) {
+
+	namespace, err := c.namespaceGet(name, "uuid")
+	if err != nil {
+		return 0, notFoundToUtilsError(err)
+	}
+
+	err = c.invoke(http.MethodPatch, "/api/storage/namespaces/"+namespace.UUID, nil, &restNamespace{
+		Space: &restNamespaceSpace{Size: sizeBytes},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resized, err := c.NVMeNamespaceGet(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	return resized.Size, nil
+}
+
+// NVMeNamespaceSetComment replaces the comment of a namespace
+func (c *RestClient) NVMeNamespaceSetComment(_ context.Context, name, comment string) error {
+
+	namespace, err := c.namespaceGet(name, "uuid")
+	if err != nil {
+		return notFoundToUtilsError(err)
+	}
+	return c.invoke(http.MethodPatch, "/api/storage/namespaces/"+namespace.UUID, nil,
+		&restNamespace{Comment: &comment})
+}
+
+// NVMeNamespaceDelete deletes a namespace, if it exists
+func (c *RestClient) NVMeNamespaceDelete(ctx context.Context, name string) error {
+
+	namespace, err := c.namespaceGet(name, "uuid")
+	if restErr, ok := err.(RestError); ok && restErr.StatusCode == http.StatusNotFound {
+		Logc(ctx).WithField("namespace", name).Debug("Namespace already deleted.")
+		return nil
+	} else if err != nil {
+		return err
+	}
+	return c.invoke(http.MethodDelete, "/api/storage/namespaces/"+namespace.UUID, nil, nil)
+}
+
+// NVMeSubsystemCreate creates the named subsystem, or returns it if it already exists
+func (c *RestClient) NVMeSubsystemCreate(ctx context.Context, name string) (*NVMeSubsystem, error) {
+
+	_, err := c.subsystemGet(name)
+	if restErr, ok := err.(RestError); ok && restErr.StatusCode == http.StatusNotFound {
+		Logc(ctx).WithField("subsystem", name).Debug("Creating subsystem.")
+		err = c.invoke(http.MethodPost, "/api/protocols/nvme/subsystems", nil, &restSubsystem{
+			Name:   name,
+			SVM:    c.svm(),
+			OsType: "linux",
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the subsystem back to learn the NQN that ONTAP assigned to it
+	return c.NVMeSubsystemGet(ctx, name)
+}
+
+// NVMeSubsystemGet returns the named subsystem
+func (c *RestClient) NVMeSubsystemGet(_ context.Context, name string) (*NVMeSubsystem, error) {
+
+	subsystem, err := c.subsystemGet(name)
+	if err != nil {
+		return nil, notFoundToUtilsError(err)
+	}
+	result := subsystem.toNVMeSubsystem()
+	return &result, nil
+}
+
+// NVMeSubsystemDelete deletes the named subsystem, if it exists, along with any remaining hosts
+func (c *RestClient) NVMeSubsystemDelete(ctx context.Context, name string) error {
+
+	subsystem, err := c.subsystemGet(name)
+	if restErr, ok := err.(RestError); ok && restErr.StatusCode == http.StatusNotFound {
+		Logc(ctx).WithField("subsystem", name).Debug("Subsystem already deleted.")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	query := url.Values{"allow_delete_with_hosts": {"true"}}
+	return c.invoke(http.MethodDelete, "/api/protocols/nvme/subsystems/"+subsystem.UUID, query, nil)
+}
+
+// NVMeSubsystemAddHost allows a host NQN to access a subsystem; adding a host that is already present succeeds
+func (c *RestClient) NVMeSubsystemAddHost(ctx context.Context, subsystemName, hostNQN string) error {
+
+	subsystem, err := c.subsystemGet(subsystemName)
+	if err != nil {
+		return notFoundToUtilsError(err)
+	}
+
+	for _, host := range subsystem.Hosts {
+		if host.NQN == hostNQN {
+			Logc(ctx).WithFields(log.Fields{
+				"subsystem": subsystemName,
+				"host":      hostNQN,
+			}).Debug("Host already added to subsystem.")
+			return nil
+		}
+	}
+
+	return c.invoke(http.MethodPost, "/api/protocols/nvme/subsystems/"+subsystem.UUID+"/hosts", nil,
+		&restSubsystemHost{NQN: hostNQN})
+}
+
+// NVMeSubsystemRemoveHost revokes a host NQN's access to a subsystem; removing an absent host succeeds
+func (c *RestClient) NVMeSubsystemRemoveHost(ctx context.Context, subsystemName, hostNQN string) error {
+
+	subsystem, err := c.subsystemGet(subsystemName)
+	if err != nil {
+		return notFoundToUtilsError(err)
+	}
+
+	for _, host := range subsystem.Hosts {
+		if host.NQN == hostNQN {
+			return c.invoke(http.MethodDelete,
+				"/api/protocols/nvme/subsystems/"+subsystem.UUID+"/hosts/"+url.PathEscape(hostNQN), nil, nil)
+		}
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"subsystem": subsystemName,
+		"host":      hostNQN,
+	}).Debug("Host not found in subsystem.")
+	return nil
+}
+
+// NVMeNamespaceMap maps a namespace to a subsystem, unless it is already mapped to that subsystem.  A namespace
+// may only be mapped to one subsystem at a time.
+func (c *RestClient) NVMeNamespaceMap(ctx context.Context, subsystemName, namespaceName string) error {
+
+	namespace, err := c.namespaceGet(namespaceName, "uuid,subsystem_map.subsystem.name")
+	if err != nil {
+		return notFoundToUtilsError(err)
+	}
+
+	if namespace.SubsystemMap != nil && namespace.SubsystemMap.Subsystem != nil {
+		if namespace.SubsystemMap.Subsystem.Name == subsystemName {
+			Logc(ctx).WithFields(log.Fields{
+				"subsystem": subsystemName,
+				"namespace": namespaceName,
+			}).Debug("Namespace already mapped to subsystem.")
+			return nil
+		}
+		return fmt.Errorf("namespace %s is already mapped to subsystem %s", namespaceName,
+			namespace.SubsystemMap.Subsystem.Name)
+	}
+
+	return c.invoke(http.MethodPost, "/api/protocols/nvme/subsystem-maps", nil, &restSubsystemMap{
+		SVM:       c.svm(),
+		Subsystem: &restReference{Name: subsystemName},
+		Namespace: &restReference{Name: namespaceName},
+	})
+}
+
+// NVMeNamespaceUnmap removes the map between a namespace and a subsystem, if there is one
+func (c *RestClient) NVMeNamespaceUnmap(ctx context.Context, subsystemName, namespaceName string) error {
+
+	namespace, err := c.namespaceGet(namespaceName, "uuid,subsystem_map.subsystem.name")
+	if err != nil {
+		return notFoundToUtilsError(err)
+	}
+
+	if namespace.SubsystemMap == nil || namespace.SubsystemMap.Subsystem == nil ||
+		namespace.SubsystemMap.Subsystem.Name != subsystemName {
+		Logc(ctx).WithFields(log.Fields{
+			"subsystem": subsystemName,
+			"namespace": namespaceName,
+		}).Debug("Namespace not mapped to subsystem.")
+		return nil
+	}
+
+	subsystem, err := c.subsystemGet(subsystemName)
+	if err != nil {
+		return notFoundToUtilsError(err)
+	}
+
+	return c.invoke(http.MethodDelete,
+		"/api/protocols/nvme/subsystem-maps/"+namespace.UUID+"/"+subsystem.UUID, nil, nil)
+}
+
+// NVMeDataLIFs returns the addresses of the SVM's network interfaces that serve NVMe/TCP
+func (c *RestClient) NVMeDataLIFs(_ context.Context) ([]string, error) {
+
+	interfaces, err := c.ipInterfaceList("services", "data_nvme_tcp", "state", LifOperationalStatusUp)
+	if err != nil {
+		return nil, err
+	}
+
+	dataLIFs := make([]string, 0, len(interfaces))
+	for _, iface := range interfaces {
+		if iface.address() != "" {
+			dataLIFs = append(dataLIFs, iface.address())
+		}
+	}
+	return dataLIFs, nil
+}
+
+// NVMe operations END
+/////////////////////////////////////////////////////////////////////////////
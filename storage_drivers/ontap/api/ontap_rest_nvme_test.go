@@ -0,0 +1,133 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/utils"
+)
+
+func TestRestClient_NVMeNamespaces(t *testing.T) {
+
+	ctx := context.Background()
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	_, err := client.NVMeNamespaceGet(ctx, "/vol/vol1/namespace0")
+	assert.True(t, utils.IsNotFoundError(err), "expected not found error")
+
+	assert.NoError(t, client.NVMeNamespaceDelete(ctx, "/vol/vol1/namespace0"))
+
+	server.handle(http.MethodPost, "/api/storage/namespaces", http.StatusCreated, `{}`)
+
+	assert.NoError(t, client.NVMeNamespaceCreate(ctx, "/vol/vol1/namespace0", 1073741824, "linux", "{}"))
+
+	requests := server.requestsFor(http.MethodPost, "/api/storage/namespaces")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "/vol/vol1/namespace0", requests[0].body["name"])
+	assert.Equal(t, "{}", requests[0].body["comment"])
+	assert.Equal(t, float64(1073741824), requests[0].body["space"].(map[string]interface{})["size"])
+
+	server.handle(http.MethodGet, "/api/storage/namespaces", http.StatusOK, `{
+		"records": [{
+			"uuid": "ns-uuid", "name": "/vol/vol1/namespace0", "os_type": "linux", "comment": "{}",
+			"location": {"volume": {"name": "vol1"}}, "space": {"size": 2147483648},
+			"status": {"state": "online", "mapped": true}, "subsystem_map": {"subsystem": {"name": "vol1"}}
+		}],
+		"num_records": 1
+	}`)
+
+	namespace, err := client.NVMeNamespaceGet(ctx, "/vol/vol1/namespace0")
+	assert.NoError(t, err)
+	assert.Equal(t, NVMeNamespace{
+		UUID:      "ns-uuid",
+		Name:      "/vol/vol1/namespace0",
+		Volume:    "vol1",
+		OsType:    "linux",
+		Size:      2147483648,
+		Comment:   "{}",
+		State:     "online",
+		Mapped:    true,
+		Subsystem: "vol1",
+	}, *namespace)
+
+	server.handle(http.MethodPatch, "/api/storage/namespaces/ns-uuid", http.StatusOK, `{}`)
+
+	size, err := client.NVMeNamespaceSetSize(ctx, "/vol/vol1/namespace0", 2147483000)
+	assert.NoError(t, err)
+	assert.Equal(t, 2147483648, size)
+
+	// The namespace is already mapped to this subsystem, so mapping it again does nothing
+	assert.NoError(t, client.NVMeNamespaceMap(ctx, "vol1", "/vol/vol1/namespace0"))
+	assert.Error(t, client.NVMeNamespaceMap(ctx, "vol2", "/vol/vol1/namespace0"))
+	assert.Empty(t, server.requestsFor(http.MethodPost, "/api/protocols/nvme/subsystem-maps"))
+}
+
+func TestRestClient_NVMeSubsystems(t *testing.T) {
+
+	ctx := context.Background()
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.handle(http.MethodGet, "/api/protocols/nvme/subsystems", http.StatusOK, `{
+		"records": [{
+			"uuid": "ss-uuid", "name": "vol1", "target_nqn": "nqn.1992-08.com.netapp:sn.1:subsystem.vol1",
+			"hosts": [{"nqn": "nqn.2014-08.org.nvmexpress:uuid:node1"}]
+		}],
+		"num_records": 1
+	}`)
+
+	subsystem, err := client.NVMeSubsystemCreate(ctx, "vol1")
+	assert.NoError(t, err)
+	assert.Equal(t, "nqn.1992-08.com.netapp:sn.1:subsystem.vol1", subsystem.NQN)
+	assert.Equal(t, []string{"nqn.2014-08.org.nvmexpress:uuid:node1"}, subsystem.Hosts)
+	assert.Empty(t, server.requestsFor(http.MethodPost, "/api/protocols/nvme/subsystems"))
+
+	server.handle(http.MethodPost, "/api/protocols/nvme/subsystems/ss-uuid/hosts", http.StatusCreated, `{}`)
+	server.handle(http.MethodDelete,
+		"/api/protocols/nvme/subsystems/ss-uuid/hosts/nqn.2014-08.org.nvmexpress:uuid:node1", http.StatusOK, `{}`)
+
+	assert.NoError(t, client.NVMeSubsystemAddHost(ctx, "vol1", "nqn.2014-08.org.nvmexpress:uuid:node1"))
+	assert.NoError(t, client.NVMeSubsystemAddHost(ctx, "vol1", "nqn.2014-08.org.nvmexpress:uuid:node2"))
+	assert.NoError(t, client.NVMeSubsystemRemoveHost(ctx, "vol1", "nqn.2014-08.org.nvmexpress:uuid:node1"))
+	assert.NoError(t, client.NVMeSubsystemRemoveHost(ctx, "vol1", "nqn.2014-08.org.nvmexpress:uuid:node3"))
+
+	requests := server.requestsFor(http.MethodPost, "/api/protocols/nvme/subsystems/ss-uuid/hosts")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "nqn.2014-08.org.nvmexpress:uuid:node2", requests[0].body["nqn"])
+	assert.Len(t, server.requestsFor(http.MethodDelete,
+		"/api/protocols/nvme/subsystems/ss-uuid/hosts/nqn.2014-08.org.nvmexpress:uuid:node1"), 1)
+
+	server.handle(http.MethodDelete, "/api/protocols/nvme/subsystems/ss-uuid", http.StatusOK, `{}`)
+
+	assert.NoError(t, client.NVMeSubsystemDelete(ctx, "vol1"))
+	requests = server.requestsFor(http.MethodDelete, "/api/protocols/nvme/subsystems/ss-uuid")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "allow_delete_with_hosts=true", requests[0].query)
+}
+
+func TestRestClient_NVMeDataLIFs(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.handle(http.MethodGet, "/api/network/ip/interfaces", http.StatusOK, `{
+		"records": [
+			{"name": "lif1", "ip": {"address": "10.0.0.1"}},
+			{"name": "lif2", "ip": {"address": "10.0.0.2"}}
+		],
+		"num_records": 2
+	}`)
+
+	dataLIFs, err := client.NVMeDataLIFs(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, dataLIFs)
+}
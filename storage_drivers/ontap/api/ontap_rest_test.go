@@ -0,0 +1,800 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+)
+
+// fakeRestRequest records a request received by the fake ONTAP REST server
+type fakeRestRequest struct {
+	method string
+	path   string
+	query  string
+	body   map[string]interface{}
+}
+
+// fakeRestServer is a local HTTPS server that answers ONTAP REST API requests with canned responses
+type fakeRestServer struct {
+	*httptest.Server
+	t        *testing.T
+	routes   map[string]http.HandlerFunc
+	requests []fakeRestRequest
+}
+
+func newFakeRestServer(t *testing.T) *fakeRestServer {
+
+	s := &fakeRestServer{t: t, routes: make(map[string]http.HandlerFunc)}
+
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok, "expected basic auth")
+		assert.Equal(t, "admin", username)
+		assert.Equal(t, "password", password)
+
+		request := fakeRestRequest{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery}
+		if bodyBytes, _ := ioutil.ReadAll(r.Body); len(bodyBytes) > 0 {
+			assert.NoError(t, json.Unmarshal(bodyBytes, &request.body))
+		}
+		s.requests = append(s.requests, request)
+
+		if handler, ok := s.routes[r.Method+" "+r.URL.Path]; ok {
+			handler(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"error": {"message": "entry doesn't exist", "code": "4"}}`)
+	}))
+
+	return s
+}
+
+// handle registers a handler that always returns the specified status and JSON body
+func (s *fakeRestServer) handle(method, path string, status int, body string) {
+	s.routes[method+" "+path] = func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}
+}
+
+// handleJob registers a request that starts a job, along with the job, which finishes in the specified state
+func (s *fakeRestServer) handleJob(method, path, jobUUID, state string) {
+	s.handle(method, path, http.StatusAccepted, fmt.Sprintf(`{"job": {"uuid": "%s"}}`, jobUUID))
+	s.handle(http.MethodGet, "/api/cluster/jobs/"+jobUUID, http.StatusOK,
+		fmt.Sprintf(`{"uuid": "%s", "state": "%s", "message": "job message", "code": 917927}`, jobUUID, state))
+}
+
+func (s *fakeRestServer) requestsFor(method, path string) []fakeRestRequest {
+	requests := make([]fakeRestRequest, 0)
+	for _, request := range s.requests {
+		if request.method == method && request.path == path {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+func newTestRestClient(t *testing.T, server *fakeRestServer) *RestClient {
+
+	restClient, err := NewRestClient(ClientConfig{
+		ManagementLIF:   strings.TrimPrefix(server.URL, "https://"),
+		SVM:             "svm0",
+		Username:        "admin",
+		Password:        "password",
+		DebugTraceFlags: map[string]bool{"api": true},
+	})
+	assert.NoError(t, err)
+	restClient.SVMUUID = "svm-uuid"
+	return restClient
+}
+
+func TestRestUnixPermissions(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		permissions string
+		expected    int
+		expectError bool
+	}{
+		{"octal", "0755", 755, false},
+		{"octalNoLeadingZero", "777", 777, false},
+		{"symbolic", "---rwxr-xr-x", 755, false},
+		{"symbolicShort", "rwxrwx---", 770, false},
+		{"invalid", "rwz", 0, true},
+		{"notOctal", "0789", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		permissions, err := restUnixPermissions(test.permissions)
+		if test.expectError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, permissions)
+	}
+
+	assert.Equal(t, "0755", zapiUnixPermissions(755))
+}
+
+func TestSetResult(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus string
+		expectedErrno  string
+	}{
+		{"success", nil, "passed", ""},
+		{"notFound", RestError{StatusCode: http.StatusNotFound}, "failed", azgo.EVOLUMEDOESNOTEXIST},
+		{"conflict", RestError{StatusCode: http.StatusConflict}, "failed", azgo.EDUPLICATEENTRY},
+		{"jobFailure", RestError{Code: "917927"}, "failed", "917927"},
+		{"noCode", RestError{StatusCode: http.StatusInternalServerError}, "failed", azgo.EAPIERROR},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		response := &azgo.VolumeDestroyResponse{}
+		assert.NoError(t, setResult(test.err, azgo.EVOLUMEDOESNOTEXIST, &response.Result.ResultStatusAttr,
+			&response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr))
+		assert.Equal(t, test.expectedStatus, response.Result.ResultStatusAttr)
+		assert.Equal(t, test.expectedErrno, response.Result.ResultErrnoAttr)
+	}
+
+	// Errors other than those from the REST API are returned as is
+	response := &azgo.VolumeDestroyResponse{}
+	assert.Error(t, setResult(fmt.Errorf("connection refused"), azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr))
+}
+
+func TestSetAsyncResult(t *testing.T) {
+
+	response := &azgo.VolumeDestroyAsyncResponse{}
+	assert.NoError(t, setAsyncResult(nil, azgo.EVOLUMEDOESNOTEXIST, &response.Result.ResultStatusAttr,
+		&response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr, &response.Result.ResultStatusPtr))
+	assert.Equal(t, "passed", response.Result.ResultStatusAttr)
+	assert.Equal(t, "succeeded", response.Result.ResultStatus())
+
+	response = &azgo.VolumeDestroyAsyncResponse{}
+	assert.NoError(t, setAsyncResult(RestError{StatusCode: http.StatusNotFound}, azgo.EVOLUMEDOESNOTEXIST,
+		&response.Result.ResultStatusAttr, &response.Result.ResultReasonAttr, &response.Result.ResultErrnoAttr,
+		&response.Result.ResultStatusPtr))
+	assert.Equal(t, "failed", response.Result.ResultStatusAttr)
+	assert.Equal(t, azgo.EVOLUMEDOESNOTEXIST, response.Result.ResultErrnoAttr)
+	assert.Nil(t, response.Result.ResultStatusPtr)
+}
+
+func TestRestClient_VolumeGet(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.handle(http.MethodGet, "/api/storage/volumes", http.StatusOK, `{
+		"records": [{
+			"uuid": "vol-uuid", "name": "trident_pvc_1", "style": "flexvol", "type": "rw", "state": "online",
+			"comment": "a comment", "size": 1073741824, "aggregates": [{"name": "aggr1"}],
+			"guarantee": {"type": "none"},
+			"nas": {"path": "/trident_pvc_1", "export_policy": {"name": "default"}, "security_style": "unix",
+				"unix_permissions": 755},
+			"snapshot_policy": {"name": "none"}, "snapshot_directory_access_enabled": false,
+			"space": {"used": 768000000, "available": 252000000, "snapshot": {"reserve_percent": 5, "used": 4096}},
+			"tiering": {"policy": "snapshot_only"}, "svm": {"name": "svm0"}
+		}],
+		"num_records": 1
+	}`)
+
+	volume, err := client.VolumeGet("trident_pvc_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "trident_pvc_1", volume.VolumeIdAttributesPtr.Name())
+	assert.Equal(t, "aggr1", volume.VolumeIdAttributesPtr.ContainingAggregateName())
+	assert.Equal(t, "/trident_pvc_1", volume.VolumeIdAttributesPtr.JunctionPath())
+	assert.Equal(t, "a comment", volume.VolumeIdAttributesPtr.Comment())
+	assert.Equal(t, 1073741824, volume.VolumeSpaceAttributesPtr.Size())
+	assert.Equal(t, "none", volume.VolumeSpaceAttributesPtr.SpaceGuarantee())
+	assert.Equal(t, 5, volume.VolumeSpaceAttributesPtr.PercentageSnapshotReserve())
+	assert.Equal(t, 768000000, volume.VolumeSpaceAttributesPtr.SizeUsed())
+	assert.Equal(t, 252000000, volume.VolumeSpaceAttributesPtr.SizeAvailable())
+	assert.Equal(t, 75, volume.VolumeSpaceAttributesPtr.PercentageSizeUsed())
+	assert.Equal(t, "default", volume.VolumeExportAttributesPtr.Policy())
+	assert.Equal(t, "0755", volume.VolumeSecurityAttributesPtr.VolumeSecurityUnixAttributesPtr.Permissions())
+	assert.Equal(t, "snapshot-only", volume.VolumeCompAggrAttributesPtr.TieringPolicy())
+	assert.Nil(t, volume.VolumeCloneAttributesPtr)
+
+	requests := server.requestsFor(http.MethodGet, "/api/storage/volumes")
+	assert.Len(t, requests, 1)
+	assert.Contains(t, requests[0].query, "svm.name=svm0")
+	assert.Contains(t, requests[0].query, "style=flexvol")
+	assert.Contains(t, requests[0].query, "state=online")
+
+	// A clone reports its parent until its split starts
+	server.handle(http.MethodGet, "/api/storage/volumes", http.StatusOK, `{
+		"records": [{
+			"uuid": "clone-uuid", "name": "trident_pvc_3", "style": "flexvol", "state": "online",
+			"clone": {"is_flexclone": true, "parent_volume": {"name": "trident_pvc_1"},
+				"parent_snapshot": {"name": "snap1"}, "split_initiated": false}
+		}],
+		"num_records": 1
+	}`)
+
+	volume, err = client.VolumeGet("trident_pvc_3")
+	if assert.NoError(t, err) && assert.NotNil(t, volume.VolumeCloneAttributesPtr) {
+		parentAttrs := volume.VolumeCloneAttributesPtr.VolumeCloneParentAttributesPtr
+		if assert.NotNil(t, parentAttrs) {
+			assert.Equal(t, "trident_pvc_1", parentAttrs.Name())
+			assert.Equal(t, "snap1", parentAttrs.SnapshotName())
+		}
+	}
+
+	server.handle(http.MethodGet, "/api/storage/volumes", http.StatusOK, `{
+		"records": [{
+			"uuid": "clone-uuid", "name": "trident_pvc_3", "style": "flexvol", "state": "online",
+			"clone": {"is_flexclone": true, "parent_volume": {"name": "trident_pvc_1"}, "split_initiated": true}
+		}],
+		"num_records": 1
+	}`)
+
+	volume, err = client.VolumeGet("trident_pvc_3")
+	if assert.NoError(t, err) {
+		assert.Nil(t, volume.VolumeCloneAttributesPtr)
+	}
+
+	server.handle(http.MethodGet, "/api/storage/volumes", http.StatusOK, `{"records": [], "num_records": 0}`)
+
+	_, err = client.VolumeGet("trident_pvc_2")
+	assert.Error(t, err)
+
+	exists, err := client.VolumeExists(context.Background(), "trident_pvc_2")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRestClient_VolumeGetAllPaged(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.routes["GET /api/storage/volumes"] = func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") == "" {
+			fmt.Fprint(w, `{"records": [{"name": "vol1"}], "num_records": 1,
+				"_links": {"next": {"href": "/api/storage/volumes?start=vol1&svm.name=svm0"}}}`)
+		} else {
+			fmt.Fprint(w, `{"records": [{"name": "vol2"}], "num_records": 1}`)
+		}
+	}
+
+	response, err := client.VolumeGetAll("vol")
+	assert.NoError(t, GetError(context.Background(), response, err))
+	assert.Equal(t, 2, response.Result.NumRecords())
+
+	names := make([]string, 0)
+	for _, volume := range response.Result.AttributesListPtr.VolumeAttributesPtr {
+		names = append(names, volume.VolumeIdAttributesPtr.Name())
+	}
+	assert.Equal(t, []string{"vol1", "vol2"}, names)
+}
+
+func TestRestClient_VolumeCreate(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.handleJob(http.MethodPost, "/api/storage/volumes", "job-1", "success")
+
+	response, err := client.VolumeCreate(context.Background(), "vol1", "aggr1", "1g", "none", "default", "0755",
+		"default", "unix", "", "", QosPolicyGroup{}, false, 10, false)
+	assert.NoError(t, GetError(context.Background(), response, err))
+
+	requests := server.requestsFor(http.MethodPost, "/api/storage/volumes")
+	assert.Len(t, requests, 1)
+	body := requests[0].body
+	assert.Equal(t, "vol1", body["name"])
+	assert.Equal(t, float64(1073741824), body["size"])
+	assert.Equal(t, float64(755), body["nas"].(map[string]interface{})["unix_permissions"])
+	assert.Len(t, server.requestsFor(http.MethodGet, "/api/cluster/jobs/job-1"), 1)
+
+	// A failed job is reported as a failed ZAPI result
+	server.handleJob(http.MethodPost, "/api/storage/volumes", "job-2", "failure")
+
+	response, err = client.VolumeCreate(context.Background(), "vol1", "aggr1", "1g", "none", "default", "0755",
+		"default", "unix", "", "", QosPolicyGroup{}, false, 10, false)
+	assert.NoError(t, err)
+	zerr := NewZapiError(response)
+	assert.False(t, zerr.IsPassed())
+	assert.Equal(t, "917927", zerr.Code())
+	assert.Equal(t, "job message", zerr.Reason())
+}
+
+func TestRestClient_VolumeCloneCreateAsync(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.handleJob(http.MethodPost, "/api/storage/volumes", "clone-job", "success")
+
+	response, err := client.VolumeCloneCreateAsync("clone1", "vol1", "snap1")
+	assert.NoError(t, GetError(context.Background(), response, err))
+	assert.Equal(t, "in_progress", response.Result.ResultStatus())
+	assert.Empty(t, server.requestsFor(http.MethodGet, "/api/cluster/jobs/clone-job"))
+
+	err = client.WaitForAsyncResponse(context.Background(), response, 10*time.Second)
+	assert.NoError(t, err)
+	assert.Len(t, server.requestsFor(http.MethodGet, "/api/cluster/jobs/clone-job"), 1)
+
+	// Each job may only be waited for once
+	err = client.WaitForAsyncResponse(context.Background(), response, 10*time.Second)
+	assert.Error(t, err)
+}
+
+func TestRestClient_VolumeSetSize(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.handle(http.MethodGet, "/api/storage/volumes", http.StatusOK,
+		`{"records": [{"uuid": "vol-uuid", "name": "vol1", "size": 1073741824}], "num_records": 1}`)
+	server.handle(http.MethodPatch, "/api/storage/volumes/vol-uuid", http.StatusOK, `{}`)
+
+	tests := []struct {
+		name     string
+		newSize  string
+		expected float64
+	}{
+		{"absolute", "2147483648", 2147483648},
+		{"relative", "+1g", 2147483648},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		server.requests = nil
+		response, err := client.VolumeSetSize("vol1", test.newSize)
+		assert.NoError(t, GetError(context.Background(), response, err))
+
+		requests := server.requestsFor(http.MethodPatch, "/api/storage/volumes/vol-uuid")
+		assert.Len(t, requests, 1)
+		assert.Equal(t, test.expected, requests[0].body["size"])
+	}
+}
+
+func TestRestClient_Igroups(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.handle(http.MethodGet, "/api/protocols/san/igroups", http.StatusOK, `{
+		"records": [{
+			"uuid": "ig-uuid", "name": "trident", "protocol": "iscsi", "os_type": "linux",
+			"initiators": [{"name": "iqn.1993-08.org.debian:01:node1"}],
+			"lun_maps": [{"logical_unit_number": 0, "lun": {"name": "/vol/vol1/lun0"}}]
+		}],
+		"num_records": 1
+	}`)
+
+	igroup, err := client.IgroupGet("trident")
+	assert.NoError(t, err)
+	assert.Equal(t, "trident", igroup.InitiatorGroupName())
+	assert.Len(t, igroup.InitiatorsPtr.InitiatorInfoPtr, 1)
+
+	createResponse, err := client.IgroupCreate("trident", "iscsi", "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, azgo.EVDISK_ERROR_INITGROUP_EXISTS, NewZapiError(createResponse).Code())
+
+	addResponse, err := client.IgroupAdd("trident", "iqn.1993-08.org.debian:01:node1")
+	assert.NoError(t, err)
+	assert.Equal(t, azgo.EVDISK_ERROR_INITGROUP_HAS_NODE, NewZapiError(addResponse).Code())
+
+	removeResponse, err := client.IgroupRemove("trident", "iqn.1993-08.org.debian:01:node2", true)
+	assert.NoError(t, err)
+	assert.Equal(t, azgo.EVDISK_ERROR_NODE_NOT_IN_INITGROUP, NewZapiError(removeResponse).Code())
+
+	destroyResponse, err := client.IgroupDestroy("trident")
+	assert.NoError(t, err)
+	assert.Equal(t, azgo.EVDISK_ERROR_INITGROUP_MAPS_EXIST, NewZapiError(destroyResponse).Code())
+
+	server.handle(http.MethodGet, "/api/protocols/san/igroups", http.StatusOK, `{"records": [], "num_records": 0}`)
+
+	destroyResponse, err = client.IgroupDestroy("trident")
+	assert.NoError(t, err)
+	assert.Equal(t, azgo.EVDISK_ERROR_NO_SUCH_INITGROUP, NewZapiError(destroyResponse).Code())
+
+	server.handle(http.MethodPost, "/api/protocols/san/igroups", http.StatusCreated, `{}`)
+
+	createResponse, err = client.IgroupCreate("trident", "iscsi", "linux")
+	assert.NoError(t, GetError(context.Background(), createResponse, err))
+
+	requests := server.requestsFor(http.MethodPost, "/api/protocols/san/igroups")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, "trident", requests[0].body["name"])
+	assert.Equal(t, "svm0", requests[0].body["svm"].(map[string]interface{})["name"])
+}
+
+func TestRestClient_LunMap(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.handle(http.MethodPost, "/api/protocols/san/lun-maps", http.StatusCreated, `{}`)
+	server.handle(http.MethodGet, "/api/protocols/san/lun-maps", http.StatusOK,
+		`{"records": [{"logical_unit_number": 3}], "num_records": 1}`)
+
+	response, err := client.LunMapAutoID("trident", "/vol/vol1/lun0")
+	assert.NoError(t, GetError(context.Background(), response, err))
+	assert.Equal(t, 3, response.Result.LunIdAssigned())
+
+	requests := server.requestsFor(http.MethodPost, "/api/protocols/san/lun-maps")
+	assert.Len(t, requests, 1)
+	assert.NotContains(t, requests[0].body, "logical_unit_number")
+}
+
+func TestRestClient_LunGetGeometry(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	response, err := client.LunGetGeometry("/vol/vol1/lun0")
+	assert.NoError(t, err)
+	assert.Equal(t, azgo.EOBJECTNOTFOUND, response.Result.ResultErrnoAttr)
+
+	server.handle(http.MethodGet, "/api/private/cli/lun", http.StatusOK,
+		`{"records": [{"size": 1073741824, "max_resize_size": 68719476736}], "num_records": 1}`)
+
+	response, err = client.LunGetGeometry("/vol/vol1/lun0")
+	assert.NoError(t, GetError(context.Background(), response, err))
+	assert.Equal(t, 1073741824, response.Result.Size())
+	assert.Equal(t, 68719476736, response.Result.MaxResizeSize())
+
+	requests := server.requestsFor(http.MethodGet, "/api/private/cli/lun")
+	assert.Contains(t, requests[len(requests)-1].query, "vserver=svm0")
+}
+
+func TestRestClient_ExportPolicies(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.handle(http.MethodGet, "/api/protocols/nfs/export-policies", http.StatusOK,
+		`{"records": [], "num_records": 0}`)
+
+	getResponse, err := client.ExportPolicyGet("trident-policy")
+	assert.NoError(t, err)
+	assert.Equal(t, azgo.EOBJECTNOTFOUND, NewZapiError(getResponse).Code())
+
+	server.handle(http.MethodGet, "/api/protocols/nfs/export-policies", http.StatusOK,
+		`{"records": [{"id": 42, "name": "trident-policy", "svm": {"name": "svm0"}}], "num_records": 1}`)
+	server.handle(http.MethodGet, "/api/protocols/nfs/export-policies/42/rules", http.StatusOK, `{
+		"records": [{"index": 1, "clients": [{"match": "10.0.0.1"}], "protocols": ["nfs"],
+			"ro_rule": ["any"], "rw_rule": ["any"], "superuser": ["any"]}],
+		"num_records": 1
+	}`)
+	server.handle(http.MethodPost, "/api/protocols/nfs/export-policies/42/rules", http.StatusCreated, `{}`)
+
+	createResponse, err := client.ExportPolicyCreate("trident-policy")
+	assert.NoError(t, err)
+	assert.Equal(t, azgo.EDUPLICATEENTRY, NewZapiError(createResponse).Code())
+
+	rulesResponse, err := client.ExportRuleGetIterRequest("trident-policy")
+	assert.NoError(t, GetError(context.Background(), rulesResponse, err))
+	rules := rulesResponse.Result.AttributesListPtr.ExportRuleInfoPtr
+	assert.Len(t, rules, 1)
+	assert.Equal(t, "10.0.0.1", rules[0].ClientMatch())
+	assert.Equal(t, 1, rules[0].RuleIndex())
+
+	ruleResponse, err := client.ExportRuleCreate("trident-policy", "10.0.0.2,10.0.0.3",
+		[]string{"nfs"}, []string{"any"}, []string{"any"}, []string{"any"})
+	assert.NoError(t, GetError(context.Background(), ruleResponse, err))
+
+	requests := server.requestsFor(http.MethodPost, "/api/protocols/nfs/export-policies/42/rules")
+	assert.Len(t, requests, 1)
+	assert.Len(t, requests[0].body["clients"], 2)
+}
+
+func TestRestClient_Quotas(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	server.handle(http.MethodGet, "/api/storage/quota/rules", http.StatusOK, `{
+		"records": [{"uuid": "rule-uuid", "volume": {"name": "vol1"}, "qtree": {"name": "qtree1"},
+			"type": "tree", "space": {"hard_limit": 1048576}}],
+		"num_records": 1
+	}`)
+	server.handle(http.MethodPatch, "/api/storage/quota/rules/rule-uuid", http.StatusOK, `{}`)
+
+	entry, err := client.QuotaGetEntry("/vol/vol1/qtree1")
+	assert.NoError(t, err)
+	assert.Equal(t, "1024", entry.DiskLimit())
+	assert.Equal(t, "/vol/vol1/qtree1", entry.QuotaTarget())
+
+	response, err := client.QuotaSetEntry("", "vol1", "/vol/vol1/qtree1", "tree", "2048")
+	assert.NoError(t, GetError(context.Background(), response, err))
+
+	requests := server.requestsFor(http.MethodPatch, "/api/storage/quota/rules/rule-uuid")
+	assert.Len(t, requests, 1)
+	assert.Equal(t, float64(2097152), requests[0].body["space"].(map[string]interface{})["hard_limit"])
+
+	_, err = client.QuotaGetEntry("vol1")
+	assert.Error(t, err)
+}
+
+func TestRestClient_QtreeCount(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+
+	// The REST API reports the root qtree of each volume as a qtree with no name
+	server.handle(http.MethodGet, "/api/storage/qtrees", http.StatusOK, `{
+		"records": [{"name": "", "volume": {"name": "vol1"}}, {"name": "qtree1", "volume": {"name": "vol1"}}],
+		"num_records": 2
+	}`)
+
+	count, err := client.QtreeCount(context.Background(), "vol1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestRestClient_SystemGetOntapiVersion(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+	ctx := context.Background()
+
+	server.handle(http.MethodGet, "/api/cluster", http.StatusOK,
+		`{"name": "cluster1", "uuid": "cluster-uuid",
+		  "version": {"full": "NetApp Release 9.6P4: Fri Oct 16 2020", "generation": 9, "major": 6, "minor": 0}}`)
+
+	version, err := client.SystemGetOntapiVersion(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.160", version)
+	assert.True(t, client.SupportsFeature(ctx, LIFServices))
+	assert.False(t, client.SupportsFeature(ctx, QosPolicies))
+
+	// The version is read once
+	assert.Len(t, server.requestsFor(http.MethodGet, "/api/cluster"), 1)
+
+	response, err := client.SystemGetVersion()
+	assert.NoError(t, GetError(ctx, response, err))
+	assert.Equal(t, "NetApp Release 9.6P4: Fri Oct 16 2020", response.Result.Version())
+
+	adminResponse, err := client.VserverGetIterAdminRequest()
+	assert.NoError(t, GetError(ctx, adminResponse, err))
+	if assert.Equal(t, 1, adminResponse.Result.NumRecords()) {
+		adminInfo := adminResponse.Result.AttributesListPtr.VserverInfoPtr[0]
+		assert.Equal(t, "cluster1", adminInfo.VserverName())
+		assert.Equal(t, "admin", adminInfo.VserverType())
+	}
+}
+
+func TestRestClient_Vserver(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+	ctx := context.Background()
+
+	server.handle(http.MethodGet, "/api/svm/svms", http.StatusOK,
+		`{"records": [{"uuid": "svm-uuid", "name": "svm0", "state": "running",
+		  "aggregates": [{"name": "aggr1"}, {"name": "aggr2"}]}], "num_records": 1}`)
+
+	response, err := client.VserverGetRequest()
+	assert.NoError(t, GetError(ctx, response, err))
+	assert.Equal(t, "svm-uuid", response.Result.AttributesPtr.VserverInfoPtr.Uuid())
+
+	aggrNames, err := client.VserverGetAggregateNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aggr1", "aggr2"}, aggrNames)
+
+	// SVMs are not queried by owning SVM
+	requests := server.requestsFor(http.MethodGet, "/api/svm/svms")
+	assert.Contains(t, requests[0].query, "name=svm0")
+	assert.NotContains(t, requests[0].query, "svm.name")
+
+	// Aggregate details are reported if the credentials may read them
+	server.handle(http.MethodGet, "/api/storage/aggregates", http.StatusOK,
+		`{"records": [
+		  {"name": "aggr1", "space": {"block_storage": {"available": 1000}},
+		   "block_storage": {"primary": {"disk_class": "solid_state"}, "hybrid_cache": {"enabled": false}}},
+		  {"name": "aggr2", "space": {"block_storage": {"available": 2000}},
+		   "block_storage": {"primary": {"disk_class": "performance"}, "hybrid_cache": {"enabled": true}}}
+		], "num_records": 2}`)
+
+	aggrResponse, err := client.VserverShowAggrGetIterRequest()
+	assert.NoError(t, GetError(ctx, aggrResponse, err))
+	if assert.Equal(t, 2, aggrResponse.Result.NumRecords()) {
+		aggregates := aggrResponse.Result.AttributesListPtr.ShowAggregatesPtr
+		assert.Equal(t, "ssd", aggregates[0].AggregateType())
+		assert.Equal(t, 1000, aggregates[0].AvailableSize())
+		assert.Equal(t, "hybrid", aggregates[1].AggregateType())
+	}
+	aggrRequests := server.requestsFor(http.MethodGet, "/api/storage/aggregates")
+	assert.Contains(t, aggrRequests[0].query, "name=aggr1%7Caggr2")
+
+	// Otherwise only their names are reported
+	server.handle(http.MethodGet, "/api/storage/aggregates", http.StatusForbidden,
+		`{"error": {"message": "not authorized for that command", "code": "6"}}`)
+
+	aggrResponse, err = client.VserverShowAggrGetIterRequest()
+	assert.NoError(t, GetError(ctx, aggrResponse, err))
+	if assert.Equal(t, 2, aggrResponse.Result.NumRecords()) {
+		aggregates := aggrResponse.Result.AttributesListPtr.ShowAggregatesPtr
+		assert.Equal(t, "aggr1", aggregates[0].AggregateName())
+		assert.Nil(t, aggregates[0].AggregateTypePtr)
+		assert.Nil(t, aggregates[0].AvailableSizePtr)
+	}
+}
+
+func TestRestClient_NetInterfaces(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+	ctx := context.Background()
+
+	server.handle(http.MethodGet, "/api/network/ip/interfaces", http.StatusOK,
+		`{"records": [
+		  {"name": "lif1", "ip": {"address": "10.0.0.1"}, "location": {"node": {"name": "node1"}},
+		   "enabled": true, "state": "up", "services": ["data_core", "data_nfs"], "svm": {"name": "svm0"}},
+		  {"name": "lif2", "ip": {"address": "10.0.0.2"}, "location": {"node": {"name": "node2"}},
+		   "enabled": true, "state": "up", "services": ["data_core", "data_iscsi"], "svm": {"name": "svm0"}}
+		], "num_records": 2}`)
+
+	dataLIFs, err := client.NetInterfaceGetDataLIFs(ctx, "iscsi")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.2"}, dataLIFs)
+
+	node, err := client.NetInterfaceGetDataLIFsNode(ctx, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "node1", node)
+
+	requests := server.requestsFor(http.MethodGet, "/api/network/ip/interfaces")
+	assert.Contains(t, requests[0].query, "state=up")
+	assert.Contains(t, requests[0].query, "svm.name=svm0")
+
+	response, err := client.IscsiInterfaceGetIterRequest()
+	assert.NoError(t, GetError(ctx, response, err))
+	if assert.Equal(t, 2, response.Result.NumRecords()) {
+		info := response.Result.AttributesListPtr.IscsiInterfaceListEntryInfoPtr[0]
+		assert.Equal(t, "10.0.0.1", info.IpAddress())
+		assert.Equal(t, iscsiTargetPort, info.IpPort())
+		assert.True(t, info.IsInterfaceEnabled())
+	}
+	requests = server.requestsFor(http.MethodGet, "/api/network/ip/interfaces")
+	assert.Contains(t, requests[len(requests)-1].query, "services=data_iscsi")
+}
+
+func TestRestClient_Snapmirror(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+	ctx := context.Background()
+
+	_, err := client.SnapmirrorGet("dst", "svm0", "src", "svm1")
+	assert.Error(t, err)
+
+	server.handle(http.MethodGet, "/api/snapmirror/relationships", http.StatusOK,
+		`{"records": [{"uuid": "sm-uuid", "source": {"path": "svm1:src"}, "destination": {"path": "svm0:dst"},
+		  "policy": {"name": "MirrorAllSnapshots"}, "state": "paused", "healthy": true}], "num_records": 1}`)
+
+	info, err := client.SnapmirrorGet("dst", "svm0", "src", "svm1")
+	assert.NoError(t, err)
+	assert.Equal(t, SnapmirrorStateSnapmirrored, info.MirrorState())
+	assert.Equal(t, SnapmirrorStatusQuiesced, info.RelationshipStatus())
+	assert.Equal(t, "svm1", info.SourceVserver())
+	assert.Equal(t, "dst", info.DestinationVolume())
+	assert.Equal(t, "none", info.RelationshipGroupType())
+
+	requests := server.requestsFor(http.MethodGet, "/api/snapmirror/relationships")
+	assert.Contains(t, requests[len(requests)-1].query, "destination.path=svm0%3Adst")
+	assert.Contains(t, requests[len(requests)-1].query, "source.path=svm1%3Asrc")
+
+	server.handleJob(http.MethodPatch, "/api/snapmirror/relationships/sm-uuid", "job1", "success")
+
+	breakResponse, err := client.SnapmirrorBreak("dst", "svm0")
+	assert.NoError(t, GetError(ctx, breakResponse, err))
+	patches := server.requestsFor(http.MethodPatch, "/api/snapmirror/relationships/sm-uuid")
+	if assert.Len(t, patches, 1) {
+		assert.Equal(t, map[string]interface{}{"state": "broken_off"}, patches[0].body)
+	}
+
+	server.handleJob(http.MethodPost, "/api/snapmirror/relationships", "job2", "success")
+
+	createResponse, err := client.SnapmirrorCreate("dst", "svm0", "src", "svm1", "MirrorAllSnapshots", "")
+	assert.NoError(t, GetError(ctx, createResponse, err))
+	creates := server.requestsFor(http.MethodPost, "/api/snapmirror/relationships")
+	if assert.Len(t, creates, 1) {
+		assert.Equal(t, map[string]interface{}{"path": "svm1:src"}, creates[0].body["source"])
+		assert.NotContains(t, creates[0].body, "transfer_schedule")
+	}
+
+	// SVM-DR relationships are those whose paths name only an SVM
+	isDestination, err := client.IsVserverDRDestination(ctx)
+	assert.NoError(t, err)
+	assert.False(t, isDestination)
+
+	server.handle(http.MethodGet, "/api/snapmirror/relationships", http.StatusOK,
+		`{"records": [{"uuid": "sm-uuid", "source": {"path": "svm1:"}, "destination": {"path": "svm0:"},
+		  "state": "snapmirrored"}], "num_records": 1}`)
+
+	isDestination, err = client.IsVserverDRDestination(ctx)
+	assert.NoError(t, err)
+	assert.True(t, isDestination)
+}
+
+func TestRestClient_IscsiInitiatorAuth(t *testing.T) {
+
+	server := newFakeRestServer(t)
+	defer server.Close()
+	client := newTestRestClient(t, server)
+	ctx := context.Background()
+
+	server.handle(http.MethodGet, "/api/protocols/san/iscsi/credentials", http.StatusOK,
+		`{"records": [
+		  {"svm": {"name": "svm0"}, "initiator": "default", "authentication_type": "none"},
+		  {"svm": {"name": "svm0"}, "initiator": "iqn.1993-08.org.debian:01:1", "authentication_type": "chap",
+		   "chap": {"inbound": {"user": "in"}, "outbound": {"user": "out"}}}
+		], "num_records": 2}`)
+
+	entries, err := client.IscsiInitiatorAuthGetIter()
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "iqn.1993-08.org.debian:01:1", entries[0].Initiator())
+		assert.Equal(t, "CHAP", entries[0].AuthType())
+		assert.Equal(t, "in", entries[0].UserName())
+		assert.Equal(t, "out", entries[0].OutboundUserName())
+	}
+
+	server.handle(http.MethodGet, "/api/protocols/san/iscsi/credentials/svm-uuid/default", http.StatusOK,
+		`{"svm": {"name": "svm0"}, "initiator": "default", "authentication_type": "chap",
+		  "chap": {"inbound": {"user": "in"}}}`)
+
+	defaultResponse, err := client.IscsiInitiatorGetDefaultAuth()
+	assert.NoError(t, GetError(ctx, defaultResponse, err))
+	assert.Equal(t, "CHAP", defaultResponse.Result.AuthType())
+	assert.Equal(t, "in", defaultResponse.Result.UserName())
+
+	server.handle(http.MethodPatch, "/api/protocols/san/iscsi/credentials/svm-uuid/default", http.StatusOK, `{}`)
+
+	setResponse, err := client.IscsiInitiatorSetDefaultAuth("CHAP", "in", "secret1", "out", "")
+	assert.NoError(t, GetError(ctx, setResponse, err))
+	patches := server.requestsFor(http.MethodPatch, "/api/protocols/san/iscsi/credentials/svm-uuid/default")
+	if assert.Len(t, patches, 1) {
+		assert.Equal(t, "chap", patches[0].body["authentication_type"])
+		// Outbound credentials are only set along with a passphrase
+		assert.Equal(t, map[string]interface{}{"inbound": map[string]interface{}{"user": "in", "password": "secret1"}},
+			patches[0].body["chap"])
+	}
+
+	server.handle(http.MethodGet, "/api/protocols/san/iscsi/sessions", http.StatusOK,
+		`{"records": [], "num_records": 0}`)
+
+	_, err = client.IscsiInitiatorGetIter()
+	assert.EqualError(t, err, "no iscsi initiator entries found")
+}
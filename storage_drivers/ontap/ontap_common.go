@@ -5,11 +5,12 @@ package ontap
 import (
 	"context"
 	cryptorand "crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/rand"
 	"net"
 	"os"
 	"reflect"
@@ -69,8 +70,13 @@ const (
 	artifactPrefixDocker     = "ndvp"
 	artifactPrefixKubernetes = "trident"
 	LUNAttributeFSType       = "com.netapp.ndvp.fstype"
+	maxIgroupNameLength      = 96
 )
 
+// nodeIgroupLocks serializes changes to the per-node igroups, so that unpublishing a LUN cannot destroy
+// a node's igroup while another LUN is being published to the same node.
+var nodeIgroupLocks utils.NamedLocks
+
 type Telemetry struct {
 	tridentconfig.Telemetry
 	Plugin        string        `json:"plugin"`
@@ -84,14 +90,14 @@ type Telemetry struct {
 
 type StorageDriver interface {
 	GetConfig() *drivers.OntapStorageDriverConfig
-	GetAPI() *api.Client
+	GetAPI() api.OntapAPI
 	GetTelemetry() *Telemetry
 	Name() string
 }
 
 type NASDriver interface {
 	GetVolumeOpts(context.Context, *storage.VolumeConfig, map[string]sa.Request) (map[string]string, error)
-	GetAPI() *api.Client
+	GetAPI() api.OntapAPI
 	GetConfig() *drivers.OntapStorageDriverConfig
 }
 
@@ -133,15 +139,6 @@ func CreateCloneNAS(
 		return err
 	}
 
-	// How "splitOnClone" value gets set:
-	// In the Core we first check clone's VolumeConfig for splitOnClone value
-	// If it is not set then (again in Core) we check source PV's VolumeConfig for splitOnClone value
-	// If we still don't have splitOnClone value then HERE we check for value in the source PV's Storage/Virtual Pool
-	// If the value for "splitOnClone" is still empty then HERE we set it to backend config's SplitOnClone value
-
-	// Attempt to get splitOnClone value based on storagePool (source Volume's StoragePool)
-	var storagePoolSplitOnCloneVal string
-
 	labels := sourceLabel
 
 	if storage.IsStoragePoolUnset(storagePool) {
@@ -155,18 +152,11 @@ func CreateCloneNAS(
 		if err != nil {
 			return err
 		}
-	} else {
-		storagePoolSplitOnCloneVal = storagePool.InternalAttributes[SplitOnClone]
-	}
-
-	// If storagePoolSplitOnCloneVal is still unknown, set it to backend's default value
-	if storagePoolSplitOnCloneVal == "" {
-		storagePoolSplitOnCloneVal = d.GetConfig().SplitOnClone
 	}
 
-	split, err := strconv.ParseBool(utils.GetV(opts, "splitOnClone", storagePoolSplitOnCloneVal))
+	split, err := getCloneSplit(opts, storagePool, d.GetConfig())
 	if err != nil {
-		return fmt.Errorf("invalid boolean value for splitOnClone: %v", err)
+		return err
 	}
 
 	qosPolicy := utils.GetV(opts, "qosPolicy", "")
@@ -181,6 +171,54 @@ func CreateCloneNAS(
 		qosPolicyGroup)
 }
 
+// SplitCloneNAS starts splitting a FlexVol or FlexGroup clone from its source volume if the clone's
+// config or storage pool calls for a split.
+func SplitCloneNAS(
+	ctx context.Context, d NASDriver, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+) error {
+
+	opts, err := d.GetVolumeOpts(ctx, volConfig, make(map[string]sa.Request))
+	if err != nil {
+		return err
+	}
+
+	split, err := getCloneSplit(opts, storagePool, d.GetConfig())
+	if err != nil || !split {
+		return err
+	}
+
+	return SplitOntapClone(ctx, volConfig.InternalName, d.GetConfig(), d.GetAPI())
+}
+
+// getCloneSplit returns whether a clone should be split from its source volume.
+//
+// How "splitOnClone" value gets set:
+// In the Core we first check clone's VolumeConfig for splitOnClone value
+// If it is not set then (again in Core) we check source PV's VolumeConfig for splitOnClone value
+// If we still don't have splitOnClone value then HERE we check for value in the source PV's Storage/Virtual Pool
+// If the value for "splitOnClone" is still empty then HERE we set it to backend config's SplitOnClone value
+func getCloneSplit(
+	opts map[string]string, storagePool *storage.Pool, config *drivers.OntapStorageDriverConfig,
+) (bool, error) {
+
+	// Attempt to get splitOnClone value based on storagePool (source Volume's StoragePool)
+	var storagePoolSplitOnCloneVal string
+	if !storage.IsStoragePoolUnset(storagePool) {
+		storagePoolSplitOnCloneVal = storagePool.InternalAttributes[SplitOnClone]
+	}
+
+	// If storagePoolSplitOnCloneVal is still unknown, set it to backend's default value
+	if storagePoolSplitOnCloneVal == "" {
+		storagePoolSplitOnCloneVal = config.SplitOnClone
+	}
+
+	split, err := strconv.ParseBool(utils.GetV(opts, "splitOnClone", storagePoolSplitOnCloneVal))
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean value for splitOnClone: %v", err)
+	}
+	return split, nil
+}
+
 // InitializeOntapConfig parses the ONTAP config, mixing in the specified common config.
 func InitializeOntapConfig(
 	ctx context.Context, driverContext tridentconfig.DriverContext, configJSON string,
@@ -314,7 +352,7 @@ func (t Telemetry) GoString() string {
 	return t.String()
 }
 
-func deleteExportPolicy(ctx context.Context, policy string, clientAPI *api.Client) error {
+func deleteExportPolicy(ctx context.Context, policy string, clientAPI api.OntapAPI) error {
 	response, err := clientAPI.ExportPolicyDestroy(policy)
 	if err = api.GetError(ctx, response, err); err != nil {
 		err = fmt.Errorf("error deleting export policy: %v", err)
@@ -322,7 +360,7 @@ func deleteExportPolicy(ctx context.Context, policy string, clientAPI *api.Clien
 	return err
 }
 
-func createExportRule(ctx context.Context, desiredPolicyRule, policyName string, clientAPI *api.Client) error {
+func createExportRule(ctx context.Context, desiredPolicyRule, policyName string, clientAPI api.OntapAPI) error {
 
 	ruleResponse, err := clientAPI.ExportRuleCreate(policyName, desiredPolicyRule,
 		[]string{"nfs"}, []string{"any"}, []string{"any"}, []string{"any"})
@@ -336,7 +374,7 @@ func createExportRule(ctx context.Context, desiredPolicyRule, policyName string,
 	return err
 }
 
-func deleteExportRule(ctx context.Context, ruleIndex int, policyName string, clientAPI *api.Client) error {
+func deleteExportRule(ctx context.Context, ruleIndex int, policyName string, clientAPI api.OntapAPI) error {
 
 	ruleDestroyResponse, err := clientAPI.ExportRuleDestroy(policyName, ruleIndex)
 	if err = api.GetError(ctx, ruleDestroyResponse, err); err != nil {
@@ -350,7 +388,7 @@ func deleteExportRule(ctx context.Context, ruleIndex int, policyName string, cli
 	return err
 }
 
-func isExportPolicyExists(ctx context.Context, policyName string, clientAPI *api.Client) (bool, error) {
+func isExportPolicyExists(ctx context.Context, policyName string, clientAPI api.OntapAPI) (bool, error) {
 
 	policyGetResponse, err := clientAPI.ExportPolicyGet(policyName)
 	if err != nil {
@@ -371,7 +409,7 @@ func isExportPolicyExists(ctx context.Context, policyName string, clientAPI *api
 	return true, nil
 }
 
-func ensureExportPolicyExists(ctx context.Context, policyName string, clientAPI *api.Client) error {
+func ensureExportPolicyExists(ctx context.Context, policyName string, clientAPI api.OntapAPI) error {
 
 	policyCreateResponse, err := clientAPI.ExportPolicyCreate(policyName)
 	if err != nil {
@@ -389,7 +427,7 @@ func ensureExportPolicyExists(ctx context.Context, policyName string, clientAPI
 
 // publishFlexVolShare ensures that the volume has the correct export policy applied.
 func publishFlexVolShare(
-	ctx context.Context, clientAPI *api.Client, config *drivers.OntapStorageDriverConfig,
+	ctx context.Context, clientAPI api.OntapAPI, config *drivers.OntapStorageDriverConfig,
 	publishInfo *utils.VolumePublishInfo, volumeName string,
 ) error {
 
@@ -431,7 +469,7 @@ func getExportPolicyName(backendUUID string) string {
 // This should be used during publish to make sure access is available if the policy has somehow been deleted.
 // Otherwise we should not need to reconcile, which could be expensive.
 func ensureNodeAccess(
-	ctx context.Context, publishInfo *utils.VolumePublishInfo, clientAPI *api.Client,
+	ctx context.Context, publishInfo *utils.VolumePublishInfo, clientAPI api.OntapAPI,
 	config *drivers.OntapStorageDriverConfig,
 ) error {
 
@@ -447,7 +485,7 @@ func ensureNodeAccess(
 }
 
 func reconcileNASNodeAccess(
-	ctx context.Context, nodes []*utils.Node, config *drivers.OntapStorageDriverConfig, clientAPI *api.Client,
+	ctx context.Context, nodes []*utils.Node, config *drivers.OntapStorageDriverConfig, clientAPI api.OntapAPI,
 	policyName string,
 ) error {
 
@@ -492,20 +530,12 @@ func getDesiredExportPolicyRules(
 }
 
 func reconcileExportPolicyRules(
-	ctx context.Context, policyName string, desiredPolicyRules []string, clientAPI *api.Client,
+	ctx context.Context, policyName string, desiredPolicyRules []string, clientAPI api.OntapAPI,
 ) error {
 
-	ruleListResponse, err := clientAPI.ExportRuleGetIterRequest(policyName)
-	if err = api.GetError(ctx, ruleListResponse, err); err != nil {
-		return fmt.Errorf("error listing export policy rules: %v", err)
-	}
-	rulesToRemove := make(map[string]int)
-	if ruleListResponse.Result.NumRecords() > 0 {
-		rulesAttrList := ruleListResponse.Result.AttributesList()
-		rules := rulesAttrList.ExportRuleInfo()
-		for _, rule := range rules {
-			rulesToRemove[rule.ClientMatch()] = rule.RuleIndex()
-		}
+	rulesToRemove, err := getExportPolicyRules(ctx, policyName, clientAPI)
+	if err != nil {
+		return err
 	}
 	for _, rule := range desiredPolicyRules {
 		if _, ok := rulesToRemove[rule]; ok {
@@ -529,7 +559,162 @@ func reconcileExportPolicyRules(
 	return nil
 }
 
-func reconcileSANNodeAccess(ctx context.Context, clientAPI *api.Client, igroupName string, nodeIQNs []string) error {
+// getExportPolicyRules returns the rules in an export policy, as a map of client match to rule index.
+func getExportPolicyRules(ctx context.Context, policyName string, clientAPI api.OntapAPI) (map[string]int, error) {
+
+	ruleListResponse, err := clientAPI.ExportRuleGetIterRequest(policyName)
+	if err = api.GetError(ctx, ruleListResponse, err); err != nil {
+		return nil, fmt.Errorf("error listing export policy rules: %v", err)
+	}
+	rules := make(map[string]int)
+	if ruleListResponse.Result.NumRecords() > 0 {
+		rulesAttrList := ruleListResponse.Result.AttributesList()
+		for _, rule := range rulesAttrList.ExportRuleInfo() {
+			rules[rule.ClientMatch()] = rule.RuleIndex()
+		}
+	}
+	return rules, nil
+}
+
+// getVolumeExportPolicyName returns the name of the export policy dedicated to a single volume or qtree
+// when the backend uses per-volume export policies.
+func getVolumeExportPolicyName(volumeName string) string {
+	return volumeName
+}
+
+// createVolumeExportPolicy creates the empty export policy dedicated to a volume or qtree.  Rules are
+// added to it as the volume is published to nodes.
+func createVolumeExportPolicy(ctx context.Context, volumeName string, clientAPI api.OntapAPI) (string, error) {
+
+	policyName := getVolumeExportPolicyName(volumeName)
+	if err := ensureExportPolicyExists(ctx, policyName, clientAPI); err != nil {
+		Logc(ctx).WithField("exportPolicy", policyName).Error(err)
+		return "", err
+	}
+	return policyName, nil
+}
+
+// deleteVolumeExportPolicy deletes the export policy dedicated to a volume or qtree, if it exists.  Failures
+// are logged but not returned, since a leftover policy should not prevent a volume from being deleted.
+func deleteVolumeExportPolicy(ctx context.Context, volumeName string, clientAPI api.OntapAPI) {
+
+	policyName := getVolumeExportPolicyName(volumeName)
+	if exists, err := isExportPolicyExists(ctx, policyName, clientAPI); err != nil || !exists {
+		return
+	}
+	if err := deleteExportPolicy(ctx, policyName, clientAPI); err != nil {
+		Logc(ctx).WithField("exportPolicy", policyName).Warning(err)
+	}
+}
+
+// getNodeExportPolicyRule returns the client match for an export rule granting access to a node's IP
+// addresses, limited to those within the backend's autoExportCIDRs.
+func getNodeExportPolicyRule(
+	ctx context.Context, publishInfo *utils.VolumePublishInfo, config *drivers.OntapStorageDriverConfig,
+) (string, error) {
+
+	filteredIPs, err := utils.FilterIPs(ctx, publishInfo.HostIP, config.AutoExportCIDRs)
+	if err != nil {
+		return "", err
+	}
+	if len(filteredIPs) == 0 {
+		return "", fmt.Errorf("node %s has no IP addresses within the CIDRs %v", publishInfo.HostName,
+			config.AutoExportCIDRs)
+	}
+	return strings.Join(filteredIPs, ","), nil
+}
+
+// exportRuleMatchesNode returns true if an export rule's client match includes any of a node's IP addresses.
+func exportRuleMatchesNode(clientMatch string, nodeIPs []string) bool {
+	for _, client := range strings.Split(clientMatch, ",") {
+		for _, ip := range nodeIPs {
+			if strings.TrimSpace(client) == ip {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// publishVolumeExportPolicy grants a node access to a volume or qtree that has its own export policy.  It
+// returns false if per-volume export policies are not in use for the volume, in which case the caller should
+// fall back to the backend's export policy.
+func publishVolumeExportPolicy(
+	ctx context.Context, clientAPI api.OntapAPI, config *drivers.OntapStorageDriverConfig,
+	publishInfo *utils.VolumePublishInfo, volumeName string,
+) (bool, error) {
+
+	if !config.PerVolumeExportPolicy || publishInfo.Unmanaged {
+		return false, nil
+	}
+
+	// Volumes created before per-volume export policies were enabled continue to use the backend policy
+	policyName := getVolumeExportPolicyName(volumeName)
+	if exists, err := isExportPolicyExists(ctx, policyName, clientAPI); err != nil {
+		return false, err
+	} else if !exists {
+		Logc(ctx).WithField("exportPolicy", policyName).Debug("Per-volume export policy not found.")
+		return false, nil
+	}
+
+	desiredRule, err := getNodeExportPolicyRule(ctx, publishInfo, config)
+	if err != nil {
+		return false, err
+	}
+	rules, err := getExportPolicyRules(ctx, policyName, clientAPI)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := rules[desiredRule]; ok {
+		Logc(ctx).WithFields(log.Fields{
+			"ExportPolicy": policyName,
+			"ClientMatch":  desiredRule,
+		}).Debug("Export rule already exists.")
+		return true, nil
+	}
+	if err = createExportRule(ctx, desiredRule, policyName, clientAPI); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// unpublishVolumeExportPolicy removes a node's access to a volume or qtree that has its own export policy.
+func unpublishVolumeExportPolicy(
+	ctx context.Context, clientAPI api.OntapAPI, config *drivers.OntapStorageDriverConfig,
+	publishInfo *utils.VolumePublishInfo, volumeName string,
+) error {
+
+	if !config.PerVolumeExportPolicy || publishInfo.Unmanaged {
+		return nil
+	}
+
+	policyName := getVolumeExportPolicyName(volumeName)
+	if exists, err := isExportPolicyExists(ctx, policyName, clientAPI); err != nil {
+		return err
+	} else if !exists {
+		Logc(ctx).WithField("exportPolicy", policyName).Debug("Per-volume export policy not found.")
+		return nil
+	}
+
+	nodeIPs, err := utils.FilterIPs(ctx, publishInfo.HostIP, config.AutoExportCIDRs)
+	if err != nil {
+		return err
+	}
+	rules, err := getExportPolicyRules(ctx, policyName, clientAPI)
+	if err != nil {
+		return err
+	}
+	for clientMatch, ruleIndex := range rules {
+		if exportRuleMatchesNode(clientMatch, nodeIPs) {
+			if err = deleteExportRule(ctx, ruleIndex, policyName, clientAPI); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func reconcileSANNodeAccess(ctx context.Context, clientAPI api.OntapAPI, igroupName string, nodeIQNs []string) error {
 
 	err := ensureIGroupExists(clientAPI, igroupName)
 	if err != nil {
@@ -592,7 +777,7 @@ func reconcileSANNodeAccess(ctx context.Context, clientAPI *api.Client, igroupNa
 	return nil
 }
 
-func cleanIgroups(ctx context.Context, client *api.Client, igroupName string) {
+func cleanIgroups(ctx context.Context, client api.OntapAPI, igroupName string) {
 
 	response, err := client.IgroupDestroy(igroupName)
 	err = api.GetError(ctx, response, err)
@@ -611,7 +796,7 @@ func cleanIgroups(ctx context.Context, client *api.Client, igroupName string) {
 
 // GetISCSITargetInfo returns the iSCSI node name and iSCSI interfaces using the provided client's SVM.
 func GetISCSITargetInfo(
-	clientAPI *api.Client, config *drivers.OntapStorageDriverConfig,
+	clientAPI api.OntapAPI, config *drivers.OntapStorageDriverConfig,
 ) (iSCSINodeName string, iSCSIInterfaces []string, returnError error) {
 
 	// Get the SVM iSCSI IQN
@@ -655,7 +840,7 @@ func GetOntapDriverRedactList() []string {
 // PopulateOntapLunMapping helper function to fill in volConfig with its LUN mapping values.
 // This function assumes that the list of data LIFs has not changed since driver initialization and volume creation
 func PopulateOntapLunMapping(
-	ctx context.Context, clientAPI *api.Client, config *drivers.OntapStorageDriverConfig,
+	ctx context.Context, clientAPI api.OntapAPI, config *drivers.OntapStorageDriverConfig,
 	ips []string, volConfig *storage.VolumeConfig, lunID int, lunPath, igroupName string,
 ) error {
 
@@ -687,21 +872,25 @@ func PopulateOntapLunMapping(
 	}
 	serial := lunSerialResponse.Result.SerialNumber()
 
-	filteredIPs, err := getISCSIDataLIFsForReportingNodes(ctx, clientAPI, ips, lunPath, igroupName)
-	if err != nil {
-		return err
-	}
+	// LUNs that are mapped to individual nodes as they are published have no igroup yet
+	filteredIPs := ips
+	if igroupName != "" {
+		filteredIPs, err = getISCSIDataLIFsForReportingNodes(ctx, clientAPI, ips, lunPath, igroupName)
+		if err != nil {
+			return err
+		}
 
-	if len(filteredIPs) == 0 {
-		Logc(ctx).Warn("Unable to find reporting ONTAP nodes for discovered dataLIFs.")
-		filteredIPs = ips
+		if len(filteredIPs) == 0 {
+			Logc(ctx).Warn("Unable to find reporting ONTAP nodes for discovered dataLIFs.")
+			filteredIPs = ips
+		}
 	}
 
 	volConfig.AccessInfo.IscsiTargetPortal = filteredIPs[0]
 	volConfig.AccessInfo.IscsiPortals = filteredIPs[1:]
 	volConfig.AccessInfo.IscsiTargetIQN = targetIQN
 	volConfig.AccessInfo.IscsiLunNumber = int32(lunID)
-	volConfig.AccessInfo.IscsiIgroup = config.IgroupName
+	volConfig.AccessInfo.IscsiIgroup = igroupName
 	volConfig.AccessInfo.IscsiLunSerial = serial
 	Logc(ctx).WithFields(log.Fields{
 		"volume":          volConfig.Name,
@@ -721,7 +910,7 @@ func PopulateOntapLunMapping(
 // This function assumes that the list of data LIF IP addresses does not change between driver initialization
 // and publish
 func PublishLUN(
-	ctx context.Context, clientAPI *api.Client, config *drivers.OntapStorageDriverConfig, ips []string,
+	ctx context.Context, clientAPI api.OntapAPI, config *drivers.OntapStorageDriverConfig, ips []string,
 	publishInfo *utils.VolumePublishInfo, lunPath, igroupName string, iSCSINodeName string,
 ) error {
 
@@ -772,6 +961,7 @@ func PublishLUN(
 	}
 
 	allowEmptyIQN := false
+	perNodeIgroup := false
 	if config.DriverContext == tridentconfig.ContextCSI {
 		// Get the info about the targeted node
 		var targetNode *utils.Node
@@ -794,9 +984,35 @@ func PublishLUN(
 				allowEmptyIQN = true
 			}
 		}
+
+		// In CSI mode, each node has its own igroup, so that a LUN is only visible to the nodes it is
+		// published to.  LUNs that were mapped to the backend igroup before per-node igroups were used
+		// remain there until they are unpublished.
+		if !publishInfo.Unmanaged {
+			mappedIgroups, err := clientAPI.LunListIgroupsMapped(lunPath)
+			if err != nil {
+				return err
+			}
+			if _, ok := mappedIgroups[igroupName]; !ok {
+				igroupName = getNodeSpecificIgroupName(igroupName, publishInfo.HostName)
+				perNodeIgroup = true
+				defer nodeIgroupLocks.Lock(igroupName)()
+
+				// A node's igroup is only useful once it contains the node's IQN, and it should contain
+				// no other IQNs, such as those the node had before its initiator name was changed
+				if iqn == "" {
+					err = fmt.Errorf("unknown initiator for node %s", publishInfo.HostName)
+					Logc(ctx).Error(err)
+					return err
+				}
+				if err = reconcileSANNodeAccess(ctx, clientAPI, igroupName, []string{iqn}); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
-	if !publishInfo.Unmanaged {
+	if !publishInfo.Unmanaged && !perNodeIgroup {
 		if iqn != "" {
 			// Add IQN to igroup
 			igroupAddResponse, err := clientAPI.IgroupAdd(igroupName, iqn)
@@ -819,7 +1035,12 @@ func PublishLUN(
 	}
 
 	// Map LUN (it may already be mapped)
-	lunID, err := clientAPI.LunMapIfNotMapped(ctx, igroupName, lunPath, publishInfo.Unmanaged)
+	var lunID int
+	if perNodeIgroup {
+		lunID, err = clientAPI.LunMapToIgroupIfNotMapped(ctx, igroupName, lunPath)
+	} else {
+		lunID, err = clientAPI.LunMapIfNotMapped(ctx, igroupName, lunPath, publishInfo.Unmanaged)
+	}
 	if err != nil {
 		return err
 	}
@@ -855,9 +1076,70 @@ func PublishLUN(
 	return nil
 }
 
+// UnpublishLUN removes access to a LUN from the host specified in publishInfo, for ontap-san and
+// ontap-san-economy.  In CSI mode, the LUN is unmapped from the node's igroup, which is destroyed
+// once no LUNs remain mapped to it.  Nothing is done in Docker mode or for unmanaged volumes.
+func UnpublishLUN(
+	ctx context.Context, clientAPI api.OntapAPI, config *drivers.OntapStorageDriverConfig,
+	volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo, lunPath, igroupName string,
+) error {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":  "UnpublishLUN",
+			"Type":    "ontap_common",
+			"lunPath": lunPath,
+			"node":    publishInfo.HostName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> UnpublishLUN")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< UnpublishLUN")
+	}
+
+	if config.DriverContext != tridentconfig.ContextCSI || volConfig.ImportNotManaged {
+		return nil
+	}
+
+	nodeIgroupName := getNodeSpecificIgroupName(igroupName, publishInfo.HostName)
+	defer nodeIgroupLocks.Lock(nodeIgroupName)()
+
+	if err := clientAPI.LunUnmapIfMapped(ctx, nodeIgroupName, lunPath); err != nil {
+		return err
+	}
+
+	// A LUN still mapped to the backend igroup is visible to every node, so remove that map unless
+	// the volume may be attached to other nodes as well.
+	switch volConfig.AccessMode {
+	case tridentconfig.ReadWriteMany, tridentconfig.ReadOnlyMany:
+	default:
+		if err := clientAPI.LunUnmapIfMapped(ctx, igroupName, lunPath); err != nil {
+			return err
+		}
+	}
+
+	// Destroy the node's igroup, which only succeeds if no other LUNs are mapped to it
+	cleanIgroups(ctx, clientAPI, nodeIgroupName)
+
+	return nil
+}
+
+// getNodeSpecificIgroupName returns the name of the igroup used to publish LUNs to a single node.
+// Names that would exceed the ONTAP limit use a hash of the node name instead.
+func getNodeSpecificIgroupName(igroupName, nodeName string) string {
+
+	name := igroupName + "-" + nodeName
+	if len(name) > maxIgroupNameLength {
+		hash := sha256.Sum256([]byte(nodeName))
+		name = igroupName + "-" + hex.EncodeToString(hash[:])[:16]
+		if len(name) > maxIgroupNameLength {
+			name = name[len(name)-maxIgroupNameLength:]
+		}
+	}
+	return name
+}
+
 // getISCSIDataLIFsForReportingNodes finds the data LIFs for the reporting nodes for the LUN.
 func getISCSIDataLIFsForReportingNodes(
-	ctx context.Context, clientAPI *api.Client, ips []string, lunPath string, igroupName string,
+	ctx context.Context, clientAPI api.OntapAPI, ips []string, lunPath string, igroupName string,
 ) ([]string, error) {
 
 	lunMapGetResponse, err := clientAPI.LunMapGet(igroupName, lunPath)
@@ -1032,7 +1314,7 @@ func IsDefaultAuthTypeDeny(response *azgo.IscsiInitiatorGetDefaultAuthResponse)
 
 // InitializeSANDriver performs common ONTAP SAN driver initialization.
 func InitializeSANDriver(
-	ctx context.Context, driverContext tridentconfig.DriverContext, clientAPI *api.Client,
+	ctx context.Context, driverContext tridentconfig.DriverContext, clientAPI api.OntapAPI,
 	config *drivers.OntapStorageDriverConfig, validate func(context.Context) error, backendUUID string,
 ) error {
 
@@ -1142,7 +1424,7 @@ func getDefaultIgroupName(driverContext tridentconfig.DriverContext, backendUUID
 	}
 }
 
-func ensureIGroupExists(clientAPI *api.Client, igroupName string) error {
+func ensureIGroupExists(clientAPI api.OntapAPI, igroupName string) error {
 	igroupResponse, err := clientAPI.IgroupCreate(igroupName, "iscsi", "linux")
 	if err != nil {
 		return fmt.Errorf("error creating igroup: %v", err)
@@ -1158,7 +1440,7 @@ func ensureIGroupExists(clientAPI *api.Client, igroupName string) error {
 
 // InitializeOntapDriver sets up the API client and performs all other initialization tasks
 // that are common to all the ONTAP drivers.
-func InitializeOntapDriver(ctx context.Context, config *drivers.OntapStorageDriverConfig) (*api.Client, error) {
+func InitializeOntapDriver(ctx context.Context, config *drivers.OntapStorageDriverConfig) (api.OntapAPI, error) {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{"Method": "InitializeOntapDriver", "Type": "ontap_common"}
@@ -1217,9 +1499,9 @@ func InitializeOntapDriver(ctx context.Context, config *drivers.OntapStorageDriv
 	return client, nil
 }
 
-// InitializeOntapAPI returns an ontap.Client ZAPI client.  If the SVM isn't specified in the config
-// file, this method attempts to derive the one to use.
-func InitializeOntapAPI(ctx context.Context, config *drivers.OntapStorageDriverConfig) (*api.Client, error) {
+// InitializeOntapAPI returns an ontap.Client ZAPI client, or an ontap.RestClient REST client if the config
+// file requests one.  If the SVM isn't specified in the config file, this method attempts to derive the one to use.
+func InitializeOntapAPI(ctx context.Context, config *drivers.OntapStorageDriverConfig) (api.OntapAPI, error) {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{"Method": "InitializeOntapAPI", "Type": "ontap_common"}
@@ -1227,7 +1509,7 @@ func InitializeOntapAPI(ctx context.Context, config *drivers.OntapStorageDriverC
 		defer Logc(ctx).WithFields(fields).Debug("<<<< InitializeOntapAPI")
 	}
 
-	client := api.NewClient(api.ClientConfig{
+	clientConfig := api.ClientConfig{
 		ManagementLIF:        config.ManagementLIF,
 		SVM:                  config.SVM,
 		Username:             config.Username,
@@ -1237,7 +1519,16 @@ func InitializeOntapAPI(ctx context.Context, config *drivers.OntapStorageDriverC
 		TrustedCACertificate: config.TrustedCACertificate,
 		DriverContext:        config.DriverContext,
 		DebugTraceFlags:      config.DebugTraceFlags,
-	})
+	}
+
+	client, err := newOntapAPI(clientConfig, config.UseREST, "")
+	if err != nil {
+		return nil, err
+	}
+	if config.UseREST {
+		Logc(ctx).Debug("Using ONTAP REST API.")
+	}
+
 	if config.SVM != "" {
 
 		vserverResponse, err := client.VserverGetRequest()
@@ -1245,10 +1536,10 @@ func InitializeOntapAPI(ctx context.Context, config *drivers.OntapStorageDriverC
 			return nil, fmt.Errorf("error reading SVM details: %v", err)
 		}
 
-		client.SVMUUID = string(vserverResponse.Result.AttributesPtr.VserverInfoPtr.Uuid())
+		svmUUID := string(vserverResponse.Result.AttributesPtr.VserverInfoPtr.Uuid())
 
 		Logc(ctx).WithField("SVM", config.SVM).Debug("Using specified SVM.")
-		return client, nil
+		return newOntapAPI(clientConfig, config.UseREST, svmUUID)
 	}
 
 	// Use VserverGetIterRequest to populate config.SVM if it wasn't specified and we can derive it
@@ -1264,26 +1555,32 @@ func InitializeOntapAPI(ctx context.Context, config *drivers.OntapStorageDriverC
 	// Update everything to use our derived SVM
 	config.SVM = vserverResponse.Result.AttributesListPtr.VserverInfoPtr[0].VserverName()
 	svmUUID := string(vserverResponse.Result.AttributesListPtr.VserverInfoPtr[0].Uuid())
-
-	client = api.NewClient(api.ClientConfig{
-		ManagementLIF:        config.ManagementLIF,
-		SVM:                  config.SVM,
-		Username:             config.Username,
-		Password:             config.Password,
-		ClientCertificate:    config.ClientCertificate,
-		ClientPrivateKey:     config.ClientPrivateKey,
-		TrustedCACertificate: config.TrustedCACertificate,
-		DriverContext:        config.DriverContext,
-		DebugTraceFlags:      config.DebugTraceFlags,
-	})
-	client.SVMUUID = svmUUID
+	clientConfig.SVM = config.SVM
 
 	Logc(ctx).WithField("SVM", config.SVM).Debug("Using derived SVM.")
-	return client, nil
+	return newOntapAPI(clientConfig, config.UseREST, svmUUID)
+}
+
+// newOntapAPI returns a client for the SVM with the specified UUID, which uses the ONTAP REST API if useREST
+// is set, or ZAPI otherwise.
+func newOntapAPI(clientConfig api.ClientConfig, useREST bool, svmUUID string) (api.OntapAPI, error) {
+
+	if !useREST {
+		client := api.NewClient(clientConfig)
+		client.SVMUUID = svmUUID
+		return client, nil
+	}
+
+	restClient, err := api.NewRestClient(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not create ONTAP REST API client: %v", err)
+	}
+	restClient.SVMUUID = svmUUID
+	return restClient, nil
 }
 
 // ValidateSANDriver contains the validation logic shared between ontap-san and ontap-san-economy.
-func ValidateSANDriver(ctx context.Context, _ *api.Client, config *drivers.OntapStorageDriverConfig,
+func ValidateSANDriver(ctx context.Context, _ api.OntapAPI, config *drivers.OntapStorageDriverConfig,
 	ips []string) error {
 
 	if config.DebugTraceFlags["method"] {
@@ -1292,6 +1589,25 @@ func ValidateSANDriver(ctx context.Context, _ *api.Client, config *drivers.Ontap
 		defer Logc(ctx).WithFields(fields).Debug("<<<< ValidateSANDriver")
 	}
 
+	// NVMe namespaces are managed only by ontap-san, and only via the REST API
+	switch config.SANType {
+	case "", string(tridentconfig.ISCSI):
+	case string(tridentconfig.NVMe):
+		if config.StorageDriverName != drivers.OntapSANStorageDriverName {
+			return fmt.Errorf("SAN type %s is not supported by the %s driver", config.SANType,
+				config.StorageDriverName)
+		}
+		if !config.UseREST {
+			return fmt.Errorf("SAN type %s requires the ONTAP REST API; set useREST to true", config.SANType)
+		}
+		if config.DriverContext != tridentconfig.ContextCSI {
+			return fmt.Errorf("SAN type %s is only supported with CSI", config.SANType)
+		}
+	default:
+		return fmt.Errorf("invalid SAN type %s; must be %s or %s", config.SANType, tridentconfig.ISCSI,
+			tridentconfig.NVMe)
+	}
+
 	// If the user sets the LIF to use in the config, disable multipathing and use just the one IP address
 	if config.DataLIF != "" {
 		// Make sure it's actually a valid address
@@ -1328,7 +1644,7 @@ func ValidateSANDriver(ctx context.Context, _ *api.Client, config *drivers.Ontap
 }
 
 // ValidateNASDriver contains the validation logic shared between ontap-nas and ontap-nas-economy.
-func ValidateNASDriver(ctx context.Context, api *api.Client, config *drivers.OntapStorageDriverConfig) error {
+func ValidateNASDriver(ctx context.Context, api api.OntapAPI, config *drivers.OntapStorageDriverConfig) error {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{"Method": "ValidateNASDriver", "Type": "ontap_common"}
@@ -1498,6 +1814,10 @@ func PopulateConfigurationDefaults(ctx context.Context, config *drivers.OntapSto
 		config.AutoExportPolicy = false
 	}
 
+	if !config.AutoExportPolicy {
+		config.PerVolumeExportPolicy = false
+	}
+
 	if config.AutoExportPolicy {
 		config.ExportPolicy = "<automatic>"
 	} else if config.ExportPolicy == "" {
@@ -1550,26 +1870,32 @@ func PopulateConfigurationDefaults(ctx context.Context, config *drivers.OntapSto
 		config.AutoExportCIDRs = []string{"0.0.0.0/0", "::/0"}
 	}
 
+	if config.SANType == "" {
+		config.SANType = string(tridentconfig.ISCSI)
+	}
+
 	Logc(ctx).WithFields(log.Fields{
-		"StoragePrefix":       *config.StoragePrefix,
-		"SpaceAllocation":     config.SpaceAllocation,
-		"SpaceReserve":        config.SpaceReserve,
-		"SnapshotPolicy":      config.SnapshotPolicy,
-		"SnapshotReserve":     config.SnapshotReserve,
-		"UnixPermissions":     config.UnixPermissions,
-		"SnapshotDir":         config.SnapshotDir,
-		"ExportPolicy":        config.ExportPolicy,
-		"SecurityStyle":       config.SecurityStyle,
-		"NfsMountOptions":     config.NfsMountOptions,
-		"SplitOnClone":        config.SplitOnClone,
-		"FileSystemType":      config.FileSystemType,
-		"Encryption":          config.Encryption,
-		"LimitAggregateUsage": config.LimitAggregateUsage,
-		"LimitVolumeSize":     config.LimitVolumeSize,
-		"Size":                config.Size,
-		"TieringPolicy":       config.TieringPolicy,
-		"AutoExportPolicy":    config.AutoExportPolicy,
-		"AutoExportCIDRs":     config.AutoExportCIDRs,
+		"StoragePrefix":         *config.StoragePrefix,
+		"SpaceAllocation":       config.SpaceAllocation,
+		"SpaceReserve":          config.SpaceReserve,
+		"SnapshotPolicy":        config.SnapshotPolicy,
+		"SnapshotReserve":       config.SnapshotReserve,
+		"UnixPermissions":       config.UnixPermissions,
+		"SnapshotDir":           config.SnapshotDir,
+		"ExportPolicy":          config.ExportPolicy,
+		"SecurityStyle":         config.SecurityStyle,
+		"NfsMountOptions":       config.NfsMountOptions,
+		"SplitOnClone":          config.SplitOnClone,
+		"FileSystemType":        config.FileSystemType,
+		"Encryption":            config.Encryption,
+		"LimitAggregateUsage":   config.LimitAggregateUsage,
+		"LimitVolumeSize":       config.LimitVolumeSize,
+		"Size":                  config.Size,
+		"TieringPolicy":         config.TieringPolicy,
+		"AutoExportPolicy":      config.AutoExportPolicy,
+		"AutoExportCIDRs":       config.AutoExportCIDRs,
+		"PerVolumeExportPolicy": config.PerVolumeExportPolicy,
+		"SANType":               config.SANType,
 	}).Debugf("Configuration defaults")
 
 	return nil
@@ -1577,7 +1903,7 @@ func PopulateConfigurationDefaults(ctx context.Context, config *drivers.OntapSto
 
 func checkAggregateLimitsForFlexvol(
 	ctx context.Context, flexvol string, requestedSizeInt uint64, config drivers.OntapStorageDriverConfig,
-	client *api.Client,
+	client api.OntapAPI,
 ) error {
 
 	var aggregate, spaceReserve string
@@ -1602,7 +1928,7 @@ func checkAggregateLimitsForFlexvol(
 
 func checkAggregateLimits(
 	ctx context.Context, aggregate, spaceReserve string, requestedSizeInt uint64,
-	config drivers.OntapStorageDriverConfig, client *api.Client,
+	config drivers.OntapStorageDriverConfig, client api.OntapAPI,
 ) error {
 
 	requestedSize := float64(requestedSizeInt)
@@ -1766,7 +2092,7 @@ func EMSHeartbeat(ctx context.Context, driver StorageDriver) {
 const MSecPerHour = 1000 * 60 * 60 // millis * seconds * minutes
 
 // probeForVolume polls for the ONTAP volume to appear, with backoff retry logic
-func probeForVolume(ctx context.Context, name string, client *api.Client) error {
+func probeForVolume(ctx context.Context, name string, client api.OntapAPI) error {
 
 	checkVolumeExists := func() error {
 		volExists, err := client.VolumeExists(ctx, name)
@@ -1800,7 +2126,7 @@ func probeForVolume(ctx context.Context, name string, client *api.Client) error
 // Create a volume clone
 func CreateOntapClone(
 	ctx context.Context, name, source, snapshot, labels string, split bool, config *drivers.OntapStorageDriverConfig,
-	client *api.Client, useAsync bool, qosPolicyGroup api.QosPolicyGroup,
+	client api.OntapAPI, useAsync bool, qosPolicyGroup api.QosPolicyGroup,
 ) error {
 
 	if config.DebugTraceFlags["method"] {
@@ -1865,6 +2191,19 @@ func CreateOntapClone(
 		}
 	}
 
+	// A clone inherits its source's export policy, so give it a policy of its own if necessary
+	if config.PerVolumeExportPolicy && (config.StorageDriverName == drivers.OntapNASStorageDriverName ||
+		config.StorageDriverName == drivers.OntapNASFlexGroupStorageDriverName) {
+		policyName, err := createVolumeExportPolicy(ctx, name, client)
+		if err != nil {
+			return err
+		}
+		policyResponse, err := client.VolumeModifyExportPolicy(name, policyName)
+		if err = api.GetError(ctx, policyResponse, err); err != nil {
+			return fmt.Errorf("error updating export policy on volume %s: %v", name, err)
+		}
+	}
+
 	// Set the QoS Policy if necessary
 	if qosPolicyGroup.Kind != api.InvalidQosPolicyGroupKind {
 		qosResponse, err := client.VolumeSetQosPolicyGroupName(name, qosPolicyGroup)
@@ -1884,8 +2223,50 @@ func CreateOntapClone(
 	return nil
 }
 
+// SplitOntapClone starts splitting a clone from its source volume.  A volume that is no longer a
+// clone, such as one whose split has finished, is left as it is.
+func SplitOntapClone(
+	ctx context.Context, name string, config *drivers.OntapStorageDriverConfig, client api.OntapAPI,
+) error {
+
+	if config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "SplitOntapClone",
+			"Type":   "ontap_common",
+			"name":   name,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> SplitOntapClone")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< SplitOntapClone")
+	}
+
+	var volume *azgo.VolumeAttributesType
+	var err error
+	if config.StorageDriverName == drivers.OntapNASFlexGroupStorageDriverName {
+		volume, err = client.FlexGroupGet(name)
+	} else {
+		volume, err = client.VolumeGet(name)
+	}
+	if err != nil {
+		return fmt.Errorf("error getting clone %s: %v", name, err)
+	} else if volume == nil {
+		return utils.NotFoundError(fmt.Sprintf("clone %s not found", name))
+	}
+
+	if volume.VolumeCloneAttributesPtr == nil || volume.VolumeCloneAttributesPtr.VolumeCloneParentAttributesPtr == nil {
+		Logc(ctx).WithField("name", name).Debug("Volume is not a clone, nothing to split.")
+		return nil
+	}
+
+	splitResponse, err := client.VolumeCloneSplitStart(name)
+	if err = api.GetError(ctx, splitResponse, err); err != nil {
+		return fmt.Errorf("error splitting clone: %v", err)
+	}
+
+	return nil
+}
+
 func handleCreateOntapCloneErr(
-	ctx context.Context, zerr api.ZapiError, client *api.Client, snapshot, source, name string,
+	ctx context.Context, zerr api.ZapiError, client api.OntapAPI, snapshot, source, name string,
 ) error {
 
 	if zerr.Code() == azgo.EOBJECTNOTFOUND {
@@ -1911,7 +2292,7 @@ func handleCreateOntapCloneErr(
 // and a non-existent snapshot, this method may return (nil, nil).
 func GetSnapshot(
 	ctx context.Context, snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig,
-	client *api.Client, sizeGetter func(string) (int, error),
+	client api.OntapAPI, sizeGetter func(string) (int, error),
 ) (*storage.Snapshot, error) {
 
 	internalSnapName := snapConfig.InternalName
@@ -1968,7 +2349,7 @@ func GetSnapshot(
 
 // GetSnapshots returns the list of snapshots associated with the named volume.
 func GetSnapshots(
-	ctx context.Context, volConfig *storage.VolumeConfig, config *drivers.OntapStorageDriverConfig, client *api.Client,
+	ctx context.Context, volConfig *storage.VolumeConfig, config *drivers.OntapStorageDriverConfig, client api.OntapAPI,
 	sizeGetter func(string) (int, error),
 ) ([]*storage.Snapshot, error) {
 
@@ -2028,7 +2409,7 @@ func GetSnapshots(
 // CreateSnapshot creates a snapshot for the given volume.
 func CreateSnapshot(
 	ctx context.Context, snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig,
-	client *api.Client, sizeGetter func(string) (int, error),
+	client api.OntapAPI, sizeGetter func(string) (int, error),
 ) (*storage.Snapshot, error) {
 
 	internalSnapName := snapConfig.InternalName
@@ -2087,7 +2468,7 @@ func CreateSnapshot(
 // Restore a volume (in place) from a snapshot.
 func RestoreSnapshot(
 	ctx context.Context, snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig,
-	client *api.Client,
+	client api.OntapAPI,
 ) error {
 
 	internalSnapName := snapConfig.InternalName
@@ -2121,7 +2502,7 @@ func RestoreSnapshot(
 // DeleteSnapshot deletes a single snapshot.
 func DeleteSnapshot(
 	ctx context.Context, snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig,
-	client *api.Client,
+	client api.OntapAPI,
 ) error {
 
 	internalSnapName := snapConfig.InternalName
@@ -2159,7 +2540,7 @@ func DeleteSnapshot(
 // a split operation on the first one (sorted by volume name).
 func SplitVolumeFromBusySnapshot(
 	ctx context.Context, snapConfig *storage.SnapshotConfig, config *drivers.OntapStorageDriverConfig,
-	client *api.Client,
+	client api.OntapAPI,
 ) error {
 
 	internalSnapName := snapConfig.InternalName
@@ -2214,7 +2595,7 @@ func SplitVolumeFromBusySnapshot(
 
 // GetVolume checks for the existence of a volume.  It returns nil if the volume
 // exists and an error if it does not (or the API call fails).
-func GetVolume(ctx context.Context, name string, client *api.Client, config *drivers.OntapStorageDriverConfig) error {
+func GetVolume(ctx context.Context, name string, client api.OntapAPI, config *drivers.OntapStorageDriverConfig) error {
 
 	if config.DebugTraceFlags["method"] {
 		fields := log.Fields{"Method": "GetVolume", "Type": "ontap_common"}
@@ -2234,13 +2615,71 @@ func GetVolume(ctx context.Context, name string, client *api.Client, config *dri
 	return nil
 }
 
-type ontapPerformanceClass string
+// getFlexvolCondition returns the condition of a Flexvol, which is abnormal if the Flexvol does not exist
+// or is not online.  If checkFull is true, a Flexvol whose used space has reached the full threshold is
+// also abnormal; this is not meaningful for Flexvols sized to fit their LUNs.
+func getFlexvolCondition(
+	ctx context.Context, name string, checkFull bool, client api.OntapAPI,
+) (*storage.VolumeCondition, error) {
 
-const (
-	ontapHDD    ontapPerformanceClass = "hdd"
-	ontapHybrid ontapPerformanceClass = "hybrid"
-	ontapSSD    ontapPerformanceClass = "ssd"
-)
+	volExists, err := client.VolumeExists(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for existing volume %s: %v", name, err)
+	}
+	if !volExists {
+		return storage.NewAbnormalVolumeCondition("volume %s does not exist", name), nil
+	}
+
+	// Only online volumes are returned
+	volAttrs, err := client.VolumeGet(name)
+	if err != nil {
+		Logc(ctx).WithField("flexvol", name).WithError(err).Debug("Could not get Flexvol.")
+		return storage.NewAbnormalVolumeCondition("volume %s is not online", name), nil
+	}
+
+	return getVolumeAttributesCondition(name, volAttrs, checkFull), nil
+}
+
+// getVolumeAttributesCondition returns the condition of an online Flexvol or FlexGroup from its attributes.
+func getVolumeAttributesCondition(
+	name string, volAttrs *azgo.VolumeAttributesType, checkFull bool,
+) *storage.VolumeCondition {
+
+	if checkFull && volAttrs.VolumeSpaceAttributesPtr != nil &&
+		volAttrs.VolumeSpaceAttributesPtr.PercentageSizeUsedPtr != nil {
+
+		percentUsed := volAttrs.VolumeSpaceAttributesPtr.PercentageSizeUsed()
+		if percentUsed >= storage.VolumeFullThresholdPercent {
+			return storage.NewAbnormalVolumeCondition("volume %s is %d%% full", name, percentUsed)
+		}
+	}
+
+	return storage.NewNormalVolumeCondition()
+}
+
+// getLUNCondition returns the condition of a LUN, which is abnormal if the LUN cannot be found or is
+// not online.
+func getLUNCondition(ctx context.Context, lunPath string, client api.OntapAPI) *storage.VolumeCondition {
+
+	lunInfo, err := client.LunGet(lunPath)
+	if err != nil {
+		Logc(ctx).WithField("LUN", lunPath).WithError(err).Debug("Could not get LUN.")
+		return storage.NewAbnormalVolumeCondition("LUN %s not found", lunPath)
+	}
+	if lunInfo.OnlinePtr != nil && !lunInfo.Online() {
+		return storage.NewAbnormalVolumeCondition("LUN %s is not online", lunPath)
+	}
+
+	return storage.NewNormalVolumeCondition()
+}
+
+type ontapPerformanceClass string
+
+const (
+	ontapHDD    ontapPerformanceClass = "hdd"
+	ontapHybrid ontapPerformanceClass = "hybrid"
+	ontapSSD    ontapPerformanceClass = "ssd"
+)
 
 var ontapPerformanceClasses = map[ontapPerformanceClass]map[string]sa.Offer{
 	ontapHDD:    {sa.Media: sa.NewStringOffer(sa.HDD)},
@@ -2365,6 +2804,125 @@ func getVserverAggrAttributes(
 	return
 }
 
+// getAggregateCapacities returns the capacity of the specified aggregates.  The space available to the SVM
+// is read using vserver-show-aggr-get-iter, which will only succeed on Data ONTAP 9 and later.  Aggregate
+// sizes are read using aggr-space-get-iter if the backend credentials permit it, in which case the available
+// space is also limited by limitAggregateUsage.
+func getAggregateCapacities(
+	ctx context.Context, d StorageDriver, aggrNames []string,
+) (capacities map[string]*storage.PoolCapacity, err error) {
+
+	// Handle panics from the API layer
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unable to inspect ONTAP backend: %v\nStack trace:\n%s", r, debug.Stack())
+		}
+	}()
+
+	client := d.GetAPI()
+
+	result, err := client.VserverShowAggrGetIterRequest()
+	if err != nil {
+		return nil, err
+	}
+	if zerr := api.NewZapiError(result.Result); !zerr.IsPassed() {
+		return nil, zerr
+	}
+
+	wanted := make(map[string]bool)
+	for _, aggrName := range aggrNames {
+		wanted[aggrName] = true
+	}
+
+	capacities = make(map[string]*storage.PoolCapacity)
+
+	if result.Result.AttributesListPtr != nil {
+		for _, aggr := range result.Result.AttributesListPtr.ShowAggregatesPtr {
+			if aggr.AggregateNamePtr == nil || aggr.AvailableSizePtr == nil {
+				continue
+			}
+			aggrName := string(aggr.AggregateName())
+			if !wanted[aggrName] {
+				continue
+			}
+			available := uint64(0)
+			if aggr.AvailableSize() > 0 {
+				available = uint64(aggr.AvailableSize())
+			}
+			capacities[aggrName] = &storage.PoolCapacity{
+				TotalBytes:     available,
+				AvailableBytes: available,
+			}
+		}
+	}
+
+	limitAggregateUsage := strings.Replace(d.GetConfig().LimitAggregateUsage, "%", "", -1)
+
+	for aggrName, capacity := range capacities {
+
+		aggrSpaceResponse, aggrSpaceErr := client.AggrSpaceGetIterRequest(aggrName)
+		if aggrSpaceErr = api.GetError(ctx, aggrSpaceResponse, aggrSpaceErr); aggrSpaceErr != nil {
+			Logc(ctx).WithField("aggregate", aggrName).WithError(aggrSpaceErr).Debug(
+				"Could not read aggregate space, reporting SVM available size only.")
+			continue
+		}
+		if aggrSpaceResponse.Result.AttributesListPtr == nil {
+			continue
+		}
+
+		for _, aggrSpace := range aggrSpaceResponse.Result.AttributesListPtr.SpaceInformationPtr {
+			if aggrSpace.AggregatePtr == nil || aggrSpace.Aggregate() != aggrName || aggrSpace.AggregateSizePtr == nil {
+				continue
+			}
+			aggregateSize := float64(aggrSpace.AggregateSize())
+			capacity.TotalBytes = uint64(aggregateSize)
+
+			if limitAggregateUsage == "" || aggrSpace.UsedIncludingSnapshotReservePtr == nil {
+				break
+			}
+			percentLimit, parseErr := strconv.ParseFloat(limitAggregateUsage, 64)
+			if parseErr != nil {
+				return nil, parseErr
+			}
+
+			// Don't report space beyond the configured aggregate usage limit
+			usableBytes := aggregateSize*percentLimit/100.0 - float64(aggrSpace.UsedIncludingSnapshotReserve())
+			if usableBytes < 0 {
+				usableBytes = 0
+			}
+			if uint64(usableBytes) < capacity.AvailableBytes {
+				capacity.AvailableBytes = uint64(usableBytes)
+			}
+			break
+		}
+	}
+
+	return capacities, nil
+}
+
+// getPoolCapacityCommon returns the capacity of the aggregates that could host a volume with the specified
+// attributes in the specified pool
+func getPoolCapacityCommon(
+	ctx context.Context, d StorageDriver, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+	physicalPools, virtualPools map[string]*storage.Pool,
+) (map[string]*storage.PoolCapacity, error) {
+
+	aggrNames := make([]string, 0)
+
+	if _, ok := physicalPools[storagePool.Name]; ok {
+		aggrNames = append(aggrNames, storagePool.Name)
+	} else if _, ok := virtualPools[storagePool.Name]; ok {
+		// A virtual pool may place a volume on any aggregate that satisfies the volume's attributes
+		for _, pool := range sc.FilterPoolsOnAttributes(ctx, physicalPools, volAttributes) {
+			aggrNames = append(aggrNames, pool.Name)
+		}
+	} else {
+		return nil, fmt.Errorf("could not find pool %s", storagePool.Name)
+	}
+
+	return getAggregateCapacities(ctx, d, aggrNames)
+}
+
 // poolName constructs the name of the pool reported by this driver instance
 func poolName(name, backendName string) string {
 
@@ -2892,13 +3450,433 @@ func getVolumeOptsCommon(
 	if volConfig.AdaptiveQosPolicy != "" {
 		opts["adaptiveQosPolicy"] = volConfig.AdaptiveQosPolicy
 	}
+	if volConfig.TieringPolicy != "" {
+		opts["tieringPolicy"] = volConfig.TieringPolicy
+	}
 
 	return opts
 }
 
+// validateVolumeModification ensures a volume modification only changes attributes the driver supports,
+// returning an UnsupportedError otherwise.  It also validates the values of the requested attributes.
+func validateVolumeModification(
+	ctx context.Context, d StorageDriver, modification *storage.VolumeModification, supportedAttributes ...string,
+) error {
+
+	for _, attribute := range modification.Attributes() {
+		if !utils.SliceContainsString(supportedAttributes, attribute) {
+			return utils.UnsupportedError(fmt.Sprintf("modifying %s is not supported by backend type %s",
+				attribute, d.Name()))
+		}
+	}
+
+	if modification.QosPolicy != "" || modification.AdaptiveQosPolicy != "" {
+		if !d.GetAPI().SupportsFeature(ctx, api.QosPolicies) {
+			return fmt.Errorf("trident does not support QoS policies for ONTAP version")
+		}
+	}
+
+	switch modification.TieringPolicy {
+	case "snapshot-only", "auto", "none", "backup", "all", "":
+		break
+	default:
+		return fmt.Errorf("invalid tieringPolicy %s", modification.TieringPolicy)
+	}
+
+	return nil
+}
+
+// modifyFlexvolCommon applies the Flexvol attributes of a volume modification, namely the snapshot
+// policy, snapshot reserve, and tiering policy, to a Flexvol.
+func modifyFlexvolCommon(
+	ctx context.Context, name string, modification *storage.VolumeModification, client api.OntapAPI,
+) error {
+
+	if modification.SnapshotPolicy != "" {
+		response, err := client.VolumeModifySnapshotPolicy(name, modification.SnapshotPolicy)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error modifying snapshot policy of volume %s: %v", name, err)
+		}
+	}
+
+	if modification.SnapshotReserve != "" {
+		snapshotReserve, err := strconv.Atoi(modification.SnapshotReserve)
+		if err != nil {
+			return fmt.Errorf("invalid value for snapshotReserve: %v", err)
+		}
+		response, err := client.VolumeModifySnapshotReserve(name, snapshotReserve)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error modifying snapshot reserve of volume %s: %v", name, err)
+		}
+	}
+
+	if modification.TieringPolicy != "" {
+		response, err := client.VolumeModifyTieringPolicy(name, modification.TieringPolicy)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error modifying tiering policy of volume %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// getMirrorVolumeHandleCommon returns the handle by which a peer backend refers to a volume, which is
+// of the form "svm:volume".
+func getMirrorVolumeHandleCommon(svm, name string) string {
+	return svm + ":" + name
+}
+
+// parseMirrorVolumeHandle splits a handle returned by getMirrorVolumeHandleCommon into its SVM and
+// volume names.
+func parseMirrorVolumeHandle(handle string) (string, string, error) {
+	parts := strings.Split(handle, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid mirror volume handle %s; expected svm:volume", handle)
+	}
+	return parts[0], parts[1], nil
+}
+
+// getMirrorRelationship returns the snapmirror relationship from a remote volume to a local volume,
+// or a NotFoundError if there is none.
+func getMirrorRelationship(
+	svm, name, remoteVolumeHandle string, client api.OntapAPI,
+) (*azgo.SnapmirrorInfoType, error) {
+
+	remoteSVM, remoteName, err := parseMirrorVolumeHandle(remoteVolumeHandle)
+	if err != nil {
+		return nil, err
+	}
+	return client.SnapmirrorGet(name, svm, remoteName, remoteSVM)
+}
+
+// createMirrorRelationship creates a snapmirror relationship from a remote volume to a local volume
+// if one does not already exist, and returns the relationship.
+func createMirrorRelationship(
+	ctx context.Context, svm, name, remoteVolumeHandle, replicationPolicy, replicationSchedule string,
+	client api.OntapAPI,
+) (*azgo.SnapmirrorInfoType, error) {
+
+	relationship, err := getMirrorRelationship(svm, name, remoteVolumeHandle, client)
+	if err == nil {
+		return relationship, nil
+	} else if !utils.IsNotFoundError(err) {
+		return nil, fmt.Errorf("error getting mirror relationship for volume %s: %v", name, err)
+	}
+
+	remoteSVM, remoteName, err := parseMirrorVolumeHandle(remoteVolumeHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":              name,
+		"remoteVolume":        remoteVolumeHandle,
+		"replicationPolicy":   replicationPolicy,
+		"replicationSchedule": replicationSchedule,
+	}).Debug("Creating mirror relationship.")
+
+	response, err := client.SnapmirrorCreate(name, svm, remoteName, remoteSVM, replicationPolicy, replicationSchedule)
+	if err = api.GetError(ctx, response, err); err != nil {
+		return nil, fmt.Errorf("error creating mirror relationship for volume %s: %v", name, err)
+	}
+
+	return client.SnapmirrorGet(name, svm, remoteName, remoteSVM)
+}
+
+// establishMirrorCommon creates a snapmirror relationship from a remote volume to a local volume,
+// if needed, and starts its baseline transfer.
+func establishMirrorCommon(
+	ctx context.Context, svm, name, remoteVolumeHandle, replicationPolicy, replicationSchedule string,
+	client api.OntapAPI,
+) error {
+
+	relationship, err := createMirrorRelationship(ctx, svm, name, remoteVolumeHandle, replicationPolicy,
+		replicationSchedule, client)
+	if err != nil {
+		return err
+	}
+
+	if relationship.MirrorStatePtr == nil || relationship.MirrorState() != api.SnapmirrorStateUninitialized {
+		return nil
+	}
+	if relationship.RelationshipStatusPtr != nil && relationship.RelationshipStatus() != api.SnapmirrorStatusIdle {
+		return nil
+	}
+
+	remoteSVM, remoteName, _ := parseMirrorVolumeHandle(remoteVolumeHandle)
+	response, err := client.SnapmirrorInitialize(name, svm, remoteName, remoteSVM)
+	if err = api.GetError(ctx, response, err); err != nil {
+		return fmt.Errorf("error initializing mirror relationship for volume %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// reestablishMirrorCommon creates a snapmirror relationship from a remote volume to a local volume,
+// if needed, and resynchronizes it unless it is already replicating.
+func reestablishMirrorCommon(
+	ctx context.Context, svm, name, remoteVolumeHandle, replicationPolicy, replicationSchedule string,
+	client api.OntapAPI,
+) error {
+
+	relationship, err := createMirrorRelationship(ctx, svm, name, remoteVolumeHandle, replicationPolicy,
+		replicationSchedule, client)
+	if err != nil {
+		return err
+	}
+
+	if relationship.MirrorStatePtr != nil && relationship.MirrorState() == api.SnapmirrorStateSnapmirrored &&
+		relationship.RelationshipStatusPtr != nil && relationship.RelationshipStatus() != api.SnapmirrorStatusQuiesced {
+		return nil
+	}
+
+	remoteSVM, remoteName, _ := parseMirrorVolumeHandle(remoteVolumeHandle)
+	response, err := client.SnapmirrorResync(name, svm, remoteName, remoteSVM)
+	if err = api.GetError(ctx, response, err); err != nil {
+		return fmt.Errorf("error resynchronizing mirror relationship for volume %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// promoteMirrorCommon quiesces and breaks the snapmirror relationship to a local volume, which makes
+// the volume writable.
+func promoteMirrorCommon(ctx context.Context, svm, name, remoteVolumeHandle string, client api.OntapAPI) error {
+
+	relationship, err := getMirrorRelationship(svm, name, remoteVolumeHandle, client)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			Logc(ctx).WithField("volume", name).Debug("No mirror relationship found, nothing to promote.")
+			return nil
+		}
+		return fmt.Errorf("error getting mirror relationship for volume %s: %v", name, err)
+	}
+
+	if relationship.MirrorStatePtr != nil && relationship.MirrorState() == api.SnapmirrorStateBrokenOff {
+		return nil
+	}
+
+	if relationship.RelationshipStatusPtr == nil || relationship.RelationshipStatus() != api.SnapmirrorStatusQuiesced {
+		response, err := client.SnapmirrorQuiesce(name, svm)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error quiescing mirror relationship for volume %s: %v", name, err)
+		}
+
+		checkQuiesced := func() error {
+			relationship, err := getMirrorRelationship(svm, name, remoteVolumeHandle, client)
+			if err != nil {
+				return backoff.Permanent(err)
+			}
+			if relationship.RelationshipStatusPtr == nil ||
+				relationship.RelationshipStatus() != api.SnapmirrorStatusQuiesced {
+				return fmt.Errorf("mirror relationship for volume %s is not yet quiesced", name)
+			}
+			return nil
+		}
+		quiescedNotify := func(err error, duration time.Duration) {
+			Logc(ctx).WithField("increment", duration).Debug("Mirror relationship not yet quiesced, waiting.")
+		}
+		quiesceBackoff := backoff.NewExponentialBackOff()
+		quiesceBackoff.InitialInterval = 1 * time.Second
+		quiesceBackoff.Multiplier = 2
+		quiesceBackoff.RandomizationFactor = 0.1
+		quiesceBackoff.MaxElapsedTime = 30 * time.Second
+
+		// A transfer in progress must finish before the relationship is quiesced, so the caller may
+		// need to try again later
+		if err := backoff.RetryNotify(checkQuiesced, quiesceBackoff, quiescedNotify); err != nil {
+			return err
+		}
+	}
+
+	response, err := client.SnapmirrorBreak(name, svm)
+	if err = api.GetError(ctx, response, err); err != nil {
+		return fmt.Errorf("error breaking mirror relationship for volume %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// getMirrorStatusCommon returns the status of the snapmirror relationship to a local volume.
+func getMirrorStatusCommon(
+	svm, name, remoteVolumeHandle string, client api.OntapAPI,
+) (*storage.MirrorStatus, error) {
+
+	relationship, err := getMirrorRelationship(svm, name, remoteVolumeHandle, client)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &storage.MirrorStatus{}
+
+	if relationship.MirrorStatePtr != nil {
+		switch relationship.MirrorState() {
+		case api.SnapmirrorStateUninitialized:
+			status.State = storage.MirrorStateUninitialized
+		case api.SnapmirrorStateSnapmirrored:
+			status.State = storage.MirrorStateMirrored
+		case api.SnapmirrorStateBrokenOff:
+			status.State = storage.MirrorStateBroken
+		default:
+			status.State = relationship.MirrorState()
+		}
+	}
+	if relationship.RelationshipStatusPtr != nil {
+		status.Transferring = relationship.RelationshipStatus() == api.SnapmirrorStatusTransferring
+	}
+	if relationship.IsHealthyPtr != nil {
+		status.Healthy = relationship.IsHealthy()
+	}
+	if relationship.UnhealthyReasonPtr != nil {
+		status.Message = relationship.UnhealthyReason()
+	} else if relationship.LastTransferErrorPtr != nil {
+		status.Message = relationship.LastTransferError()
+	}
+
+	return status, nil
+}
+
+// deleteMirrorCommon deletes the snapmirror relationship to a local volume, if it exists.
+func deleteMirrorCommon(ctx context.Context, svm, name, remoteVolumeHandle string, client api.OntapAPI) error {
+
+	remoteSVM, remoteName, err := parseMirrorVolumeHandle(remoteVolumeHandle)
+	if err != nil {
+		return err
+	}
+
+	if _, err = client.SnapmirrorGet(name, svm, remoteName, remoteSVM); err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting mirror relationship for volume %s: %v", name, err)
+	}
+
+	response, err := client.SnapmirrorDestroy(name, svm, remoteName, remoteSVM)
+	if err = api.GetError(ctx, response, err); err != nil {
+		return fmt.Errorf("error deleting mirror relationship for volume %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// releaseMirrorCommon removes the information about a deleted snapmirror relationship from its local
+// source volume.
+func releaseMirrorCommon(ctx context.Context, svm, name, remoteVolumeHandle string, client api.OntapAPI) error {
+
+	remoteSVM, remoteName, err := parseMirrorVolumeHandle(remoteVolumeHandle)
+	if err != nil {
+		return err
+	}
+
+	response, err := client.SnapmirrorRelease(remoteName, remoteSVM, name, svm)
+	if err != nil {
+		return fmt.Errorf("error releasing mirror relationship for volume %s: %v", name, err)
+	}
+	if zerr := api.NewZapiError(response); !zerr.IsPassed() {
+		if zerr.Code() == azgo.EOBJECTNOTFOUND {
+			Logc(ctx).WithField("volume", name).Debug("Mirror relationship already released.")
+			return nil
+		}
+		return fmt.Errorf("error releasing mirror relationship for volume %s: %v", name, zerr)
+	}
+
+	return nil
+}
+
+// moveVolumeCommon starts moving a Flexvol to the aggregate that backs a physical pool, unless the
+// Flexvol already resides on that aggregate or is already being moved there.
+func moveVolumeCommon(
+	ctx context.Context, name, pool string, physicalPools map[string]*storage.Pool, client api.OntapAPI,
+) error {
+
+	if _, ok := physicalPools[pool]; !ok {
+		return utils.UnsupportedError(fmt.Sprintf("pool %s is not an aggregate of this backend", pool))
+	}
+
+	flexvol, err := client.VolumeGet(name)
+	if err != nil {
+		return fmt.Errorf("error getting volume %s: %v", name, err)
+	}
+	if flexvol.VolumeIdAttributesPtr != nil && flexvol.VolumeIdAttributesPtr.ContainingAggregateNamePtr != nil &&
+		flexvol.VolumeIdAttributesPtr.ContainingAggregateName() == pool {
+		return nil
+	}
+
+	if move, err := client.VolumeMoveGet(name); err == nil && move.DestinationAggregatePtr != nil &&
+		move.DestinationAggregate() == pool && move.PhasePtr != nil && !isVolumeMoveFinished(move.Phase()) {
+		Logc(ctx).WithFields(log.Fields{
+			"volume":    name,
+			"aggregate": pool,
+		}).Debug("Volume move already in progress.")
+		return nil
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":    name,
+		"aggregate": pool,
+	}).Debug("Starting volume move.")
+
+	response, err := client.VolumeMoveStart(name, pool)
+	if err = api.GetError(ctx, response, err); err != nil {
+		return fmt.Errorf("error starting move of volume %s to aggregate %s: %v", name, pool, err)
+	}
+
+	return nil
+}
+
+// isVolumeMoveFinished returns true if a volume move in the specified phase will make no further progress.
+func isVolumeMoveFinished(phase string) bool {
+	switch phase {
+	case api.VolumeMovePhaseCompleted, api.VolumeMovePhaseFailed, api.VolumeMovePhaseAborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// getVolumeMoveStatusCommon returns the status of a move of a Flexvol to the aggregate that backs a
+// physical pool.  The move is complete once the Flexvol resides on that aggregate.
+func getVolumeMoveStatusCommon(name, pool string, client api.OntapAPI) (*storage.VolumeMoveStatus, error) {
+
+	flexvol, err := client.VolumeGet(name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting volume %s: %v", name, err)
+	}
+	if flexvol.VolumeIdAttributesPtr != nil && flexvol.VolumeIdAttributesPtr.ContainingAggregateNamePtr != nil &&
+		flexvol.VolumeIdAttributesPtr.ContainingAggregateName() == pool {
+		return &storage.VolumeMoveStatus{State: storage.VolumeMoveStateComplete, PercentComplete: 100}, nil
+	}
+
+	move, err := client.VolumeMoveGet(name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting status of move of volume %s: %v", name, err)
+	}
+	if move.DestinationAggregatePtr != nil && move.DestinationAggregate() != pool {
+		return nil, fmt.Errorf("volume %s is being moved to aggregate %s instead of %s", name,
+			move.DestinationAggregate(), pool)
+	}
+
+	status := &storage.VolumeMoveStatus{State: storage.VolumeMoveStateInProgress}
+	if move.PercentCompletePtr != nil {
+		status.PercentComplete = move.PercentComplete()
+	}
+	if move.DetailsPtr != nil {
+		status.Message = move.Details()
+	}
+	if move.PhasePtr != nil && isVolumeMoveFinished(move.Phase()) {
+		// The move is over, but the volume is not on the destination aggregate
+		status.State = storage.VolumeMoveStateFailed
+		if status.Message == "" {
+			status.Message = fmt.Sprintf("volume move %s", move.Phase())
+		}
+	}
+
+	return status, nil
+}
+
 // getPoolsForCreate returns candidate storage pools for creating volumes
 func getPoolsForCreate(
-	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+	ctx context.Context, d StorageDriver, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
 	volAttributes map[string]sa.Request, physicalPools map[string]*storage.Pool, virtualPools map[string]*storage.Pool,
 ) ([]*storage.Pool, error) {
 
@@ -2912,34 +3890,22 @@ func getPoolsForCreate(
 		return nil, fmt.Errorf("could not find pool %s", storagePool.Name)
 	}
 
-	// Make a storage class from the volume attributes to simplify pool matching
-	attributesCopy := make(map[string]sa.Request)
-	for k, v := range volAttributes {
-		attributesCopy[k] = v
-	}
-	delete(attributesCopy, sa.Selector)
-	storageClass := sc.NewFromAttributes(attributesCopy)
-
 	// Find matching pools
-	candidatePools := make([]*storage.Pool, 0)
-
-	for _, pool := range physicalPools {
-		if storageClass.Matches(ctx, pool) {
-			candidatePools = append(candidatePools, pool)
-		}
-	}
+	candidatePools := sc.FilterPoolsOnAttributes(ctx, physicalPools, volAttributes)
 
 	if len(candidatePools) == 0 {
 		err := fmt.Errorf("backend has no physical pools that can satisfy request")
 		return nil, drivers.NewBackendIneligibleError(volConfig.InternalName, []error{err}, []string{})
 	}
 
-	// Shuffle physical pools
-	rand.Shuffle(len(candidatePools), func(i, j int) {
-		candidatePools[i], candidatePools[j] = candidatePools[j], candidatePools[i]
-	})
-
-	return candidatePools, nil
+	return storage.OrderPhysicalPoolsForCreate(ctx, volConfig, candidatePools,
+		func() (map[string]*storage.PoolCapacity, error) {
+			aggrNames := make([]string, 0, len(candidatePools))
+			for _, pool := range candidatePools {
+				aggrNames = append(aggrNames, pool.Name)
+			}
+			return getAggregateCapacities(ctx, d, aggrNames)
+		}), nil
 }
 
 func getInternalVolumeNameCommon(commonConfig *drivers.CommonStorageDriverConfig, name string) string {
@@ -3013,7 +3979,7 @@ func resizeValidation(
 }
 
 // Unmount a volume and then take it offline. This may need to be done before deleting certain types of volumes.
-func UnmountAndOfflineVolume(ctx context.Context, API *api.Client, name string) (bool, error) {
+func UnmountAndOfflineVolume(ctx context.Context, API api.OntapAPI, name string) (bool, error) {
 
 	// This call is sync and idempotent
 	umountResp, err := API.VolumeUnmount(name, true)
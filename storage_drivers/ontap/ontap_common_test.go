@@ -6,11 +6,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"math/rand"
 	"net"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
@@ -18,8 +22,11 @@ import (
 
 	tridentconfig "github.com/netapp/trident/config"
 	"github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
 	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap/api"
 	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+	"github.com/netapp/trident/utils"
 )
 
 const (
@@ -687,23 +694,351 @@ func TestOntapSanGetDefaultIgroupName(t *testing.T) {
 
 }
 
+func TestGetNodeSpecificIgroupName(t *testing.T) {
+
+	igroupName := "trident-4f8c9c44-b33e-4b71-a1c5-7e3b9a0d2c11"
+
+	name := getNodeSpecificIgroupName(igroupName, "node1")
+	assert.Equal(t, igroupName+"-node1", name, "Unexpected igroup name")
+
+	longNodeName := strings.Repeat("n", 100)
+	name = getNodeSpecificIgroupName(igroupName, longNodeName)
+	assert.LessOrEqual(t, len(name), maxIgroupNameLength, "igroup name too long")
+	assert.True(t, strings.HasPrefix(name, igroupName+"-"), "igroup name lacks backend igroup prefix")
+	assert.Equal(t, name, getNodeSpecificIgroupName(igroupName, longNodeName), "igroup name not deterministic")
+	assert.NotEqual(t, name, getNodeSpecificIgroupName(igroupName, longNodeName+"2"), "igroup names collide")
+}
+
+// newTestClientWithVserver returns a ZAPI client connected to a fake vserver that sends the responses
+// supplied by the responder, along with the vserver's management LIF.
+func newTestClientWithVserver(t *testing.T, responder testZAPIResponder) (*api.Client, string) {
+
+	vserverAdminHost := ONTAPTEST_LOCALHOST
+	vserverAdminPort := strconv.Itoa(rand.Intn(ONTAPTEST_SERVER_MAX_PORT-ONTAPTEST_SERVER_MIN_PORT) +
+		ONTAPTEST_SERVER_MIN_PORT)
+
+	server := newUnstartedVserverWithResponder(context.Background(), vserverAdminHost, vserverAdminPort,
+		ONTAPTEST_VSERVER_AGGR_NAME, responder)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	managementLIF := vserverAdminHost + ":" + vserverAdminPort
+	client := api.NewClient(api.ClientConfig{
+		ManagementLIF:           managementLIF,
+		SVM:                     "SVM1",
+		Username:                "client_username",
+		Password:                "client_password",
+		DriverContext:           tridentconfig.DriverContext("driverContext"),
+		ContextBasedZapiRecords: 100,
+	})
+
+	return client, managementLIF
+}
+
+// testIgroupResponder returns a responder for the igroup and LUN map requests of a fake vserver with a
+// single LUN, which maintains the initiators of each igroup and the igroups the LUN is mapped to.
+// Requests to destroy an igroup to which the LUN is mapped fail, as on ONTAP.
+func testIgroupResponder(mutex *sync.Mutex, igroups map[string][]string, lunMaps map[string]int) testZAPIResponder {
+
+	return func(zapiRequestXMLTagName, zapiRequest string) string {
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		switch zapiRequestXMLTagName {
+		case "lun-get-attribute":
+			return `<results status="passed"><value>ext4</value></results>`
+		case "lun-map-list-info":
+			groups := ""
+			for igroup, lunID := range lunMaps {
+				groups += fmt.Sprintf("<initiator-group-info><initiator-group-name>%s</initiator-group-name>"+
+					"<lun-id>%d</lun-id></initiator-group-info>", igroup, lunID)
+			}
+			return fmt.Sprintf(`<results status="passed"><initiator-groups>%s</initiator-groups></results>`, groups)
+		case "lun-map":
+			var request azgo.LunMapRequest
+			if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+				return `<results status="failed" reason="Invalid Input" errno="13115"/>`
+			}
+			lunMaps[request.InitiatorGroup()] = len(lunMaps)
+			return fmt.Sprintf(`<results status="passed"><lun-id-assigned>%d</lun-id-assigned></results>`,
+				lunMaps[request.InitiatorGroup()])
+		case "lun-unmap":
+			var request azgo.LunUnmapRequest
+			if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+				return `<results status="failed" reason="Invalid Input" errno="13115"/>`
+			}
+			delete(lunMaps, request.InitiatorGroup())
+			return `<results status="passed"/>`
+		case "igroup-create":
+			var request azgo.IgroupCreateRequest
+			if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+				return `<results status="failed" reason="Invalid Input" errno="13115"/>`
+			}
+			if _, ok := igroups[request.InitiatorGroupName()]; ok {
+				return fmt.Sprintf(`<results status="failed" reason="Initiator group exists" errno="%s"/>`,
+					azgo.EVDISK_ERROR_INITGROUP_EXISTS)
+			}
+			igroups[request.InitiatorGroupName()] = []string{}
+			return `<results status="passed"/>`
+		case "igroup-destroy":
+			var request azgo.IgroupDestroyRequest
+			if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+				return `<results status="failed" reason="Invalid Input" errno="13115"/>`
+			}
+			if _, ok := lunMaps[request.InitiatorGroupName()]; ok {
+				return fmt.Sprintf(`<results status="failed" reason="Initiator group has maps" errno="%s"/>`,
+					azgo.EVDISK_ERROR_INITGROUP_MAPS_EXIST)
+			}
+			delete(igroups, request.InitiatorGroupName())
+			return `<results status="passed"/>`
+		case "igroup-get-iter":
+			var request azgo.IgroupGetIterRequest
+			if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+				return `<results status="failed" reason="Invalid Input" errno="13115"/>`
+			}
+			name := request.Query().InitiatorGroupInfoPtr.InitiatorGroupName()
+			initiators, ok := igroups[name]
+			if !ok {
+				return `<results status="passed"><num-records>0</num-records></results>`
+			}
+			initiatorInfos := ""
+			for _, initiator := range initiators {
+				initiatorInfos += fmt.Sprintf("<initiator-info><initiator-name>%s</initiator-name></initiator-info>",
+					initiator)
+			}
+			return fmt.Sprintf(`<results status="passed"><attributes-list><initiator-group-info>`+
+				`<initiator-group-name>%s</initiator-group-name><initiators>%s</initiators>`+
+				`</initiator-group-info></attributes-list><num-records>1</num-records></results>`,
+				name, initiatorInfos)
+		case "igroup-add":
+			var request azgo.IgroupAddRequest
+			if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+				return `<results status="failed" reason="Invalid Input" errno="13115"/>`
+			}
+			igroups[request.InitiatorGroupName()] = append(igroups[request.InitiatorGroupName()],
+				request.Initiator())
+			return `<results status="passed"/>`
+		case "igroup-remove":
+			var request azgo.IgroupRemoveRequest
+			if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+				return `<results status="failed" reason="Invalid Input" errno="13115"/>`
+			}
+			var initiators []string
+			for _, initiator := range igroups[request.InitiatorGroupName()] {
+				if initiator != request.Initiator() {
+					initiators = append(initiators, initiator)
+				}
+			}
+			igroups[request.InitiatorGroupName()] = initiators
+			return `<results status="passed"/>`
+		}
+
+		return ""
+	}
+}
+
+// newTestSANPublishInfo returns the publish info of a volume being published to a registered node.
+func newTestSANPublishInfo(nodeName, iqn string) *utils.VolumePublishInfo {
+	return &utils.VolumePublishInfo{
+		HostName: nodeName,
+		HostIQN:  []string{iqn},
+		Nodes:    []*utils.Node{{Name: nodeName, IQN: iqn, NodePrep: &utils.NodePrep{}}},
+	}
+}
+
+func TestPublishLUNToNodeIgroup(t *testing.T) {
+
+	ctx := context.Background()
+	lunPath := "/vol/trident_pvc_1/lun0"
+	igroupName := "trident-4f8c9c44-b33e-4b71-a1c5-7e3b9a0d2c11"
+	nodeIgroupName := getNodeSpecificIgroupName(igroupName, "node1")
+
+	// The node's igroup still contains the IQN the node had before its initiator name was changed
+	var mutex sync.Mutex
+	igroups := map[string][]string{nodeIgroupName: {"iqn.1993-08.org.debian:01:old"}}
+	lunMaps := make(map[string]int)
+	client, _ := newTestClientWithVserver(t, testIgroupResponder(&mutex, igroups, lunMaps))
+
+	config := newTestOntapSANConfig()
+	config.DriverContext = tridentconfig.ContextCSI
+	publishInfo := newTestSANPublishInfo("node1", "iqn.1993-08.org.debian:01:new")
+
+	err := PublishLUN(ctx, client, config, []string{"10.0.0.1"}, publishInfo, lunPath, igroupName, "iqn.svm")
+	assert.NoError(t, err)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, []string{"iqn.1993-08.org.debian:01:new"}, igroups[nodeIgroupName],
+		"node igroup should contain only the node's IQN")
+	assert.Contains(t, lunMaps, nodeIgroupName, "LUN not mapped to node igroup")
+	assert.NotContains(t, igroups, igroupName, "backend igroup should not be used")
+	assert.Equal(t, nodeIgroupName, publishInfo.IscsiIgroup)
+}
+
+func TestUnpublishLUNWaitsForNodeIgroup(t *testing.T) {
+
+	ctx := context.Background()
+	lunPath := "/vol/trident_pvc_1/lun0"
+	igroupName := "trident-4f8c9c44-b33e-4b71-a1c5-7e3b9a0d2c11"
+	nodeIgroupName := getNodeSpecificIgroupName(igroupName, "node1")
+
+	var mutex sync.Mutex
+	igroups := map[string][]string{nodeIgroupName: {"iqn.1993-08.org.debian:01:node1"}}
+	lunMaps := map[string]int{nodeIgroupName: 0}
+	client, _ := newTestClientWithVserver(t, testIgroupResponder(&mutex, igroups, lunMaps))
+
+	config := newTestOntapSANConfig()
+	config.DriverContext = tridentconfig.ContextCSI
+	volConfig := &storage.VolumeConfig{AccessMode: tridentconfig.ReadWriteOnce}
+	publishInfo := newTestSANPublishInfo("node1", "iqn.1993-08.org.debian:01:node1")
+
+	// While another LUN is being published to the node, its igroup must not be destroyed
+	unlock := nodeIgroupLocks.Lock(nodeIgroupName)
+
+	done := make(chan error)
+	go func() {
+		done <- UnpublishLUN(ctx, client, config, volConfig, publishInfo, lunPath, igroupName)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("UnpublishLUN did not wait for the node igroup")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	mutex.Lock()
+	assert.Contains(t, igroups, nodeIgroupName, "node igroup destroyed while in use")
+	assert.Contains(t, lunMaps, nodeIgroupName, "LUN unmapped while node igroup in use")
+	mutex.Unlock()
+
+	unlock()
+	assert.NoError(t, <-done)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.NotContains(t, lunMaps, nodeIgroupName, "LUN not unmapped from node igroup")
+	assert.NotContains(t, igroups, nodeIgroupName, "unused node igroup not destroyed")
+}
+
+func TestGetNodeExportPolicyRule(t *testing.T) {
+
+	ctx := context.Background()
+	config := &drivers.OntapStorageDriverConfig{AutoExportCIDRs: []string{"10.0.0.0/8"}}
+
+	publishInfo := &utils.VolumePublishInfo{
+		HostName: "node1",
+		HostIP:   []string{"10.1.1.1", "192.168.1.1", "10.2.2.2"},
+	}
+	rule, err := getNodeExportPolicyRule(ctx, publishInfo, config)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.1.1.1,10.2.2.2", rule, "Unexpected export rule")
+
+	publishInfo.HostIP = []string{"192.168.1.1"}
+	_, err = getNodeExportPolicyRule(ctx, publishInfo, config)
+	assert.Error(t, err, "Expected an error for a node without matching IPs")
+}
+
+func TestExportRuleMatchesNode(t *testing.T) {
+
+	nodeIPs := []string{"10.1.1.1", "10.2.2.2"}
+
+	assert.True(t, exportRuleMatchesNode("10.1.1.1,10.2.2.2", nodeIPs))
+	assert.True(t, exportRuleMatchesNode("10.2.2.2", nodeIPs))
+	assert.True(t, exportRuleMatchesNode("10.3.3.3, 10.1.1.1", nodeIPs))
+	assert.False(t, exportRuleMatchesNode("10.1.1.10", nodeIPs))
+	assert.False(t, exportRuleMatchesNode("10.3.3.3,10.4.4.4", nodeIPs))
+	assert.False(t, exportRuleMatchesNode("10.1.1.1", nil))
+}
+
+// fakeExportPolicyAPI implements the export policy calls of api.OntapAPI using in-memory policies, which
+// map each rule index to the rule's client match.
+type fakeExportPolicyAPI struct {
+	api.OntapAPI
+	policies map[string]map[int]string
+}
+
+func (f *fakeExportPolicyAPI) ExportPolicyGet(policy string) (*azgo.ExportPolicyGetResponse, error) {
+	response := azgo.NewExportPolicyGetResponse()
+	if _, ok := f.policies[policy]; ok {
+		response.Result.ResultStatusAttr = "passed"
+	} else {
+		response.Result.ResultStatusAttr = "failed"
+		response.Result.ResultErrnoAttr = azgo.EOBJECTNOTFOUND
+	}
+	return response, nil
+}
+
+func (f *fakeExportPolicyAPI) ExportRuleGetIterRequest(policy string) (*azgo.ExportRuleGetIterResponse, error) {
+	rules := make([]azgo.ExportRuleInfoType, 0)
+	for ruleIndex, clientMatch := range f.policies[policy] {
+		rule := azgo.NewExportRuleInfoType().SetRuleIndex(ruleIndex).SetClientMatch(clientMatch)
+		rules = append(rules, *rule)
+	}
+	response := azgo.NewExportRuleGetIterResponse()
+	response.Result.ResultStatusAttr = "passed"
+	response.Result.SetNumRecords(len(rules))
+	attributesList := azgo.ExportRuleGetIterResponseResultAttributesList{}
+	response.Result.SetAttributesList(*attributesList.SetExportRuleInfo(rules))
+	return response, nil
+}
+
+func (f *fakeExportPolicyAPI) ExportRuleDestroy(policy string, ruleIndex int) (*azgo.ExportRuleDestroyResponse, error) {
+	delete(f.policies[policy], ruleIndex)
+	response := azgo.NewExportRuleDestroyResponse()
+	response.Result.ResultStatusAttr = "passed"
+	return response, nil
+}
+
+func TestUnpublishVolumeExportPolicy(t *testing.T) {
+
+	ctx := context.Background()
+	config := &drivers.OntapStorageDriverConfig{
+		PerVolumeExportPolicy: true,
+		AutoExportCIDRs:       []string{"10.0.0.0/8"},
+	}
+	policyName := getVolumeExportPolicyName("trident_pvc_1")
+
+	clientAPI := &fakeExportPolicyAPI{policies: map[string]map[int]string{
+		policyName: {1: "10.1.1.1,10.2.2.2", 2: "10.3.3.3"},
+	}}
+
+	// A node that was deleted while the volume was published to it is still identified by its IPs
+	publishInfo := &utils.VolumePublishInfo{
+		HostName: "deletedNode",
+		HostIP:   []string{"10.1.1.1", "10.2.2.2"},
+	}
+	assert.NoError(t, unpublishVolumeExportPolicy(ctx, clientAPI, config, publishInfo, "trident_pvc_1"))
+	assert.Equal(t, map[string]map[int]string{policyName: {2: "10.3.3.3"}}, clientAPI.policies,
+		"Expected only the node's export rule to be removed")
+
+	// Without its IPs, no rules match the node
+	publishInfo = &utils.VolumePublishInfo{HostName: "unknownNode"}
+	assert.NoError(t, unpublishVolumeExportPolicy(ctx, clientAPI, config, publishInfo, "trident_pvc_1"))
+	assert.Equal(t, map[string]map[int]string{policyName: {2: "10.3.3.3"}}, clientAPI.policies,
+		"Expected no export rules to be removed")
+
+	// Volumes without their own export policy are left alone
+	assert.NoError(t, unpublishVolumeExportPolicy(ctx, clientAPI, config, publishInfo, "trident_pvc_2"))
+}
+
 func TestGetExternalConfigRedactSecrets(t *testing.T) {
 
 	commonConfig := &drivers.CommonStorageDriverConfig{
-		Credentials: map[string]string{"name": "secretname", "type": "secret"},
-		StoragePrefixRaw:  json.RawMessage("\"\""),
-		StoragePrefix:     nil,
+		Credentials:      map[string]string{"name": "secretname", "type": "secret"},
+		StoragePrefixRaw: json.RawMessage("\"\""),
+		StoragePrefix:    nil,
 	}
 
 	commonConfigNoCredentials := &drivers.CommonStorageDriverConfig{
-		StoragePrefixRaw:  json.RawMessage("\"\""),
-		StoragePrefix:     nil,
+		StoragePrefixRaw: json.RawMessage("\"\""),
+		StoragePrefix:    nil,
 	}
 
 	expectedCommonConfig := &drivers.CommonStorageDriverConfig{
-		Credentials: map[string]string{drivers.KeyName: drivers.REDACTED, drivers.KeyType: drivers.REDACTED},
-		StoragePrefixRaw:  json.RawMessage("\"\""),
-		StoragePrefix:     nil,
+		Credentials:      map[string]string{drivers.KeyName: drivers.REDACTED, drivers.KeyType: drivers.REDACTED},
+		StoragePrefixRaw: json.RawMessage("\"\""),
+		StoragePrefix:    nil,
 	}
 
 	var cases = []struct {
@@ -773,3 +1108,76 @@ func TestGetExternalConfigRedactSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSANDriverSANType(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		driverName    string
+		sanType       string
+		useREST       bool
+		driverContext tridentconfig.DriverContext
+		expectError   bool
+	}{
+		{"default", drivers.OntapSANStorageDriverName, "", false, tridentconfig.ContextCSI, false},
+		{"iscsi", drivers.OntapSANStorageDriverName, "iscsi", false, tridentconfig.ContextCSI, false},
+		{"nvme", drivers.OntapSANStorageDriverName, "nvme", true, tridentconfig.ContextCSI, false},
+		{"nvmeWithoutREST", drivers.OntapSANStorageDriverName, "nvme", false, tridentconfig.ContextCSI, true},
+		{"nvmeDocker", drivers.OntapSANStorageDriverName, "nvme", true, tridentconfig.ContextDocker, true},
+		{"nvmeEconomy", drivers.OntapSANEconomyStorageDriverName, "nvme", true, tridentconfig.ContextCSI, true},
+		{"invalid", drivers.OntapSANStorageDriverName, "fc", false, tridentconfig.ContextCSI, true},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		config := &drivers.OntapStorageDriverConfig{
+			CommonStorageDriverConfig: &drivers.CommonStorageDriverConfig{
+				StorageDriverName: test.driverName,
+				DriverContext:     test.driverContext,
+			},
+			SANType: test.sanType,
+			UseREST: test.useREST,
+		}
+
+		err := ValidateSANDriver(context.Background(), nil, config, []string{"10.0.0.1"})
+		if test.expectError {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestGetVolumeAttributesCondition(t *testing.T) {
+
+	newVolumeAttributes := func(percentUsed *int) *azgo.VolumeAttributesType {
+		spaceAttrs := azgo.NewVolumeSpaceAttributesType()
+		if percentUsed != nil {
+			spaceAttrs.SetPercentageSizeUsed(*percentUsed)
+		}
+		return azgo.NewVolumeAttributesType().SetVolumeSpaceAttributes(*spaceAttrs)
+	}
+	percent := func(value int) *int { return &value }
+
+	tests := []struct {
+		name        string
+		percentUsed *int
+		checkFull   bool
+		abnormal    bool
+	}{
+		{"empty", percent(0), true, false},
+		{"belowThreshold", percent(94), true, false},
+		{"atThreshold", percent(95), true, true},
+		{"full", percent(100), true, true},
+		{"fullNotChecked", percent(100), false, false},
+		{"unknownUsage", nil, true, false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		condition := getVolumeAttributesCondition("vol1", newVolumeAttributes(test.percentUsed), test.checkFull)
+		assert.Equal(t, test.abnormal, condition.Abnormal)
+	}
+}
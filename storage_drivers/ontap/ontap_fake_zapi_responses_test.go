@@ -27,6 +27,10 @@ var (
 	igroups map[string]map[string]struct{}
 )
 
+// testZAPIResponder returns the results element of the response to a ZAPI request, or an empty string
+// if the default response should be sent instead.
+type testZAPIResponder func(zapiRequestXMLTagName, zapiRequest string) string
+
 type TestZAPIResponseResult struct {
 	XMLName          xml.Name `xml:"results"`
 	ResultStatusAttr string   `xml:"status,attr"`
@@ -376,6 +380,29 @@ func testResponseObjectFactoryMethod(zapiRequestXMLTagName, vserverAdminHost, vs
 	return output, nil
 }
 
+// newTestZAPIResponse wraps the results element of a ZAPI response in its envelope.
+func newTestZAPIResponse(vserverAdminHost, vserverAdminPort, results string) []byte {
+
+	vserverAdminUrl := "https://" + vserverAdminHost + ":" + vserverAdminPort + "/filer/admin"
+
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+	<!DOCTYPE netapp SYSTEM "file:/etc/netapp_gx.dtd">
+	<netapp xmlns="%s" version="%s">
+	%s
+	</netapp>`,
+		vserverAdminUrl, "1.21", results))
+}
+
+// testUnmarshalZAPIRequest unmarshals the API element of a ZAPI request into the specified request type.
+func testUnmarshalZAPIRequest(zapiRequest string, request interface{}) error {
+
+	zapiRequest = strings.Split(zapiRequest, "<netapp")[1]
+	zapiRequest = strings.SplitN(zapiRequest, ">", 2)[1]
+	zapiRequest = strings.Split(zapiRequest, "</netapp>")[0]
+
+	return xml.Unmarshal([]byte(zapiRequest), request)
+}
+
 func findNextXMLStartElement(newReader io.Reader) (string, error) {
 	d := xml.NewDecoder(newReader)
 
@@ -393,7 +420,7 @@ func findNextXMLStartElement(newReader io.Reader) (string, error) {
 }
 
 func getTestResponse(ctx context.Context, requestBody io.Reader, vserverAdminHost,
-	vserverAdminPort, vserverAggrName string) ([]byte, error) {
+	vserverAdminPort, vserverAggrName string, responder testZAPIResponder) ([]byte, error) {
 
 	requestBodyString, err := ioutil.ReadAll(requestBody)
 	if err != nil {
@@ -413,6 +440,12 @@ func getTestResponse(ctx context.Context, requestBody io.Reader, vserverAdminHos
 		return nil, err
 	}
 
+	if responder != nil {
+		if results := responder(startElement, string(requestBodyString)); results != "" {
+			return newTestZAPIResponse(vserverAdminHost, vserverAdminPort, results), nil
+		}
+	}
+
 	responseBytes, err := testResponseObjectFactoryMethod(startElement, vserverAdminHost, vserverAdminPort,
 		vserverAggrName, string(requestBodyString))
 
@@ -420,11 +453,20 @@ func getTestResponse(ctx context.Context, requestBody io.Reader, vserverAdminHos
 }
 
 func newUnstartedVserver(ctx context.Context, vserverAdminHost, vserverAdminPort, vserverAggrName string) *httptest.Server {
+	return newUnstartedVserverWithResponder(ctx, vserverAdminHost, vserverAdminPort, vserverAggrName, nil)
+}
+
+// newUnstartedVserverWithResponder returns a fake vserver that sends the responses supplied by the
+// responder, falling back to the default responses for any requests the responder does not handle.
+func newUnstartedVserverWithResponder(
+	ctx context.Context, vserverAdminHost, vserverAdminPort, vserverAggrName string, responder testZAPIResponder,
+) *httptest.Server {
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/servlets/", func(w http.ResponseWriter, r *http.Request) {
 
-		response, err := getTestResponse(ctx, r.Body, vserverAdminHost, vserverAdminPort, vserverAggrName)
+		response, err := getTestResponse(ctx, r.Body, vserverAdminHost, vserverAdminPort, vserverAggrName,
+			responder)
 		if err != nil {
 			w.Write([]byte(err.Error()))
 		}
@@ -26,7 +26,7 @@ import (
 type NASStorageDriver struct {
 	initialized bool
 	Config      drivers.OntapStorageDriverConfig
-	API         *api.Client
+	API         api.OntapAPI
 	Telemetry   *Telemetry
 
 	physicalPools map[string]*storage.Pool
@@ -37,7 +37,7 @@ func (d *NASStorageDriver) GetConfig() *drivers.OntapStorageDriverConfig {
 	return &d.Config
 }
 
-func (d *NASStorageDriver) GetAPI() *api.Client {
+func (d *NASStorageDriver) GetAPI() api.OntapAPI {
 	return d.API
 }
 
@@ -180,7 +180,7 @@ func (d *NASStorageDriver) Create(
 	}
 
 	// Get candidate physical pools
-	physicalPools, err := getPoolsForCreate(ctx, volConfig, storagePool, volAttributes, d.physicalPools, d.virtualPools)
+	physicalPools, err := getPoolsForCreate(ctx, d, volConfig, storagePool, volAttributes, d.physicalPools, d.virtualPools)
 	if err != nil {
 		return err
 	}
@@ -244,7 +244,9 @@ func (d *NASStorageDriver) Create(
 		tieringPolicy = d.API.TieringPolicyValue(ctx)
 	}
 
-	if d.Config.AutoExportPolicy {
+	if d.Config.PerVolumeExportPolicy {
+		exportPolicy = getVolumeExportPolicyName(name)
+	} else if d.Config.AutoExportPolicy {
 		exportPolicy = getExportPolicyName(storagePool.Backend.BackendUUID)
 	}
 
@@ -271,6 +273,13 @@ func (d *NASStorageDriver) Create(
 		"adaptiveQosPolicy": adaptiveQosPolicy,
 	}).Debug("Creating Flexvol.")
 
+	// A per-volume export policy starts without rules, which are added as the volume is published to nodes
+	if d.Config.PerVolumeExportPolicy {
+		if _, err = createVolumeExportPolicy(ctx, name, d.API); err != nil {
+			return err
+		}
+	}
+
 	createErrors := make([]error, 0)
 	physicalPoolNames := make([]string, 0)
 
@@ -294,7 +303,7 @@ func (d *NASStorageDriver) Create(
 		// Create the volume
 		volCreateResponse, err := d.API.VolumeCreate(
 			ctx, name, aggregate, size, spaceReserve, snapshotPolicy, unixPermissions, exportPolicy, securityStyle,
-			tieringPolicy, labels, qosPolicyGroup, enableEncryption, snapshotReserveInt)
+			tieringPolicy, labels, qosPolicyGroup, enableEncryption, snapshotReserveInt, volConfig.IsMirrorDestination)
 
 		if err = api.GetError(ctx, volCreateResponse, err); err != nil {
 			if zerr, ok := err.(api.ZapiError); ok {
@@ -330,6 +339,10 @@ func (d *NASStorageDriver) Create(
 		return nil
 	}
 
+	if d.Config.PerVolumeExportPolicy {
+		deleteVolumeExportPolicy(ctx, name, d.API)
+	}
+
 	// All physical pools that were eligible ultimately failed, so don't try this backend again
 	return drivers.NewBackendIneligibleError(name, createErrors, physicalPoolNames)
 }
@@ -358,6 +371,13 @@ func (d *NASStorageDriver) CreateClone(
 	return CreateCloneNAS(ctx, d, volConfig, storagePool, sourceLabel, api.MaxNASLabelLength, false)
 }
 
+// SplitClone starts splitting a clone from its source volume if called for
+func (d *NASStorageDriver) SplitClone(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+) error {
+	return SplitCloneNAS(ctx, d, volConfig, storagePool)
+}
+
 // Destroy the volume
 func (d *NASStorageDriver) Destroy(ctx context.Context, name string) error {
 
@@ -392,6 +412,10 @@ func (d *NASStorageDriver) Destroy(ctx context.Context, name string) error {
 		}
 	}
 
+	if d.Config.PerVolumeExportPolicy {
+		deleteVolumeExportPolicy(ctx, name, d.API)
+	}
+
 	return nil
 }
 
@@ -536,9 +560,36 @@ func (d *NASStorageDriver) Publish(
 	publishInfo.FilesystemType = "nfs"
 	publishInfo.MountOptions = mountOptions
 
+	// Volumes with their own export policy only need a rule for this node
+	if published, err := publishVolumeExportPolicy(ctx, d.API, &d.Config, publishInfo, name); err != nil {
+		return err
+	} else if published {
+		return nil
+	}
+
 	return publishFlexVolShare(ctx, d.API, &d.Config, publishInfo, name)
 }
 
+// Unpublish the volume from the host specified in publishInfo.  Volumes that share the backend's export
+// policy are exported to all nodes, so only volumes with their own export policy have rules to remove.
+func (d *NASStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "NASStorageDriver",
+			"name":   volConfig.InternalName,
+			"node":   publishInfo.HostName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	return unpublishVolumeExportPolicy(ctx, d.API, &d.Config, publishInfo, volConfig.InternalName)
+}
+
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
 func (d *NASStorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig) error {
 	return nil
@@ -650,6 +701,21 @@ func (d *NASStorageDriver) Get(ctx context.Context, name string) error {
 	return GetVolume(ctx, name, d.API, &d.Config)
 }
 
+// GetVolumeCondition returns the condition of a volume's Flexvol.
+func (d *NASStorageDriver) GetVolumeCondition(
+	ctx context.Context, volConfig *storage.VolumeConfig,
+) (*storage.VolumeCondition, error) {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "GetVolumeCondition", "Type": "NASStorageDriver", "name": name}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetVolumeCondition")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetVolumeCondition")
+	}
+
+	return getFlexvolCondition(ctx, name, true, d.API)
+}
+
 // Retrieve storage backend capabilities
 func (d *NASStorageDriver) GetStorageBackendSpecs(_ context.Context, backend *storage.Backend) error {
 	return getStorageBackendSpecsCommon(backend, d.physicalPools, d.virtualPools, d.BackendName())
@@ -660,6 +726,14 @@ func (d *NASStorageDriver) GetStorageBackendPhysicalPoolNames(context.Context) [
 	return getStorageBackendPhysicalPoolNamesCommon(d.physicalPools)
 }
 
+// GetCapacity returns the capacity of the aggregates that could host a volume with the specified attributes
+// in the specified pool
+func (d *NASStorageDriver) GetCapacity(
+	ctx context.Context, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+	return getPoolCapacityCommon(ctx, d, storagePool, volAttributes, d.physicalPools, d.virtualPools)
+}
+
 func (d *NASStorageDriver) getStoragePoolAttributes() map[string]sa.Offer {
 
 	return map[string]sa.Offer{
@@ -894,6 +968,226 @@ func (d *NASStorageDriver) Resize(ctx context.Context, volConfig *storage.Volume
 	return nil
 }
 
+// Modify changes the attributes of an existing volume.
+func (d *NASStorageDriver) Modify(
+	ctx context.Context, volConfig *storage.VolumeConfig, modification *storage.VolumeModification,
+) error {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Modify",
+			"Type":         "NASStorageDriver",
+			"name":         name,
+			"modification": modification,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Modify")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Modify")
+	}
+
+	if err := validateVolumeModification(ctx, d, modification, "snapshotPolicy", "snapshotReserve",
+		"exportPolicy", "qosPolicy", "adaptiveQosPolicy", "tieringPolicy", "unixPermissions"); err != nil {
+		return err
+	}
+
+	// Trident owns the export policies of volumes on backends that manage export policies automatically
+	if modification.ExportPolicy != "" && d.Config.AutoExportPolicy {
+		return utils.UnsupportedError(fmt.Sprintf(
+			"cannot modify the export policy of volume %s because the backend manages export policies", name))
+	}
+
+	qosPolicyGroup, err := api.NewQosPolicyGroup(modification.QosPolicy, modification.AdaptiveQosPolicy)
+	if err != nil {
+		return err
+	}
+
+	volExists, err := d.API.VolumeExists(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing volume: %v", err)
+	}
+	if !volExists {
+		return fmt.Errorf("volume %s does not exist", name)
+	}
+
+	if err = modifyFlexvolCommon(ctx, name, modification, d.API); err != nil {
+		return err
+	}
+
+	if modification.ExportPolicy != "" {
+		response, err := d.API.VolumeModifyExportPolicy(name, modification.ExportPolicy)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error modifying export policy of volume %s: %v", name, err)
+		}
+	}
+
+	if modification.UnixPermissions != "" {
+		response, err := d.API.VolumeModifyUnixPermissions(name, modification.UnixPermissions)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error modifying unix permissions of volume %s: %v", name, err)
+		}
+	}
+
+	if qosPolicyGroup.Kind != api.InvalidQosPolicyGroupKind {
+		response, err := d.API.VolumeSetQosPolicyGroupName(name, qosPolicyGroup)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error setting QoS policy of volume %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// GetMirrorVolumeHandle returns the handle by which a peer backend may refer to a volume
+func (d *NASStorageDriver) GetMirrorVolumeHandle(_ context.Context, name string) (string, error) {
+	return getMirrorVolumeHandleCommon(d.Config.SVM, name), nil
+}
+
+// EstablishMirror establishes a mirror relationship that replicates the remote volume to the local volume
+func (d *NASStorageDriver) EstablishMirror(
+	ctx context.Context, name, remoteVolumeHandle, replicationPolicy, replicationSchedule string,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "EstablishMirror",
+			"Type":         "NASStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> EstablishMirror")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< EstablishMirror")
+	}
+
+	return establishMirrorCommon(ctx, d.Config.SVM, name, remoteVolumeHandle, replicationPolicy, replicationSchedule,
+		d.API)
+}
+
+// ReestablishMirror resynchronizes a mirror relationship so that the local volume replicates the remote volume
+func (d *NASStorageDriver) ReestablishMirror(
+	ctx context.Context, name, remoteVolumeHandle, replicationPolicy, replicationSchedule string,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "ReestablishMirror",
+			"Type":         "NASStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> ReestablishMirror")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< ReestablishMirror")
+	}
+
+	return reestablishMirrorCommon(ctx, d.Config.SVM, name, remoteVolumeHandle, replicationPolicy,
+		replicationSchedule, d.API)
+}
+
+// PromoteMirror breaks the mirror relationship to the local volume, making it writable
+func (d *NASStorageDriver) PromoteMirror(ctx context.Context, name, remoteVolumeHandle string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "PromoteMirror",
+			"Type":         "NASStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> PromoteMirror")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< PromoteMirror")
+	}
+
+	return promoteMirrorCommon(ctx, d.Config.SVM, name, remoteVolumeHandle, d.API)
+}
+
+// GetMirrorStatus returns the status of the mirror relationship to the local volume
+func (d *NASStorageDriver) GetMirrorStatus(
+	ctx context.Context, name, remoteVolumeHandle string,
+) (*storage.MirrorStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "GetMirrorStatus",
+			"Type":         "NASStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetMirrorStatus")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetMirrorStatus")
+	}
+
+	return getMirrorStatusCommon(d.Config.SVM, name, remoteVolumeHandle, d.API)
+}
+
+// DeleteMirror deletes the mirror relationship to the local volume
+func (d *NASStorageDriver) DeleteMirror(ctx context.Context, name, remoteVolumeHandle string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "DeleteMirror",
+			"Type":         "NASStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> DeleteMirror")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< DeleteMirror")
+	}
+
+	return deleteMirrorCommon(ctx, d.Config.SVM, name, remoteVolumeHandle, d.API)
+}
+
+// ReleaseMirror removes the information about a deleted mirror relationship from its local source volume
+func (d *NASStorageDriver) ReleaseMirror(ctx context.Context, name, remoteVolumeHandle string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "ReleaseMirror",
+			"Type":         "NASStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> ReleaseMirror")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< ReleaseMirror")
+	}
+
+	return releaseMirrorCommon(ctx, d.Config.SVM, name, remoteVolumeHandle, d.API)
+}
+
+// MoveVolume starts moving a volume to another aggregate of this backend
+func (d *NASStorageDriver) MoveVolume(ctx context.Context, name, pool string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "MoveVolume",
+			"Type":   "NASStorageDriver",
+			"name":   name,
+			"pool":   pool,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> MoveVolume")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< MoveVolume")
+	}
+
+	return moveVolumeCommon(ctx, name, pool, d.physicalPools, d.API)
+}
+
+// GetVolumeMoveStatus returns the status of a move of a volume to another aggregate of this backend
+func (d *NASStorageDriver) GetVolumeMoveStatus(
+	ctx context.Context, name, pool string,
+) (*storage.VolumeMoveStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetVolumeMoveStatus",
+			"Type":   "NASStorageDriver",
+			"name":   name,
+			"pool":   pool,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetVolumeMoveStatus")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetVolumeMoveStatus")
+	}
+
+	return getVolumeMoveStatusCommon(name, pool, d.API)
+}
+
 func (d *NASStorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node, backendUUID string) error {
 
 	nodeNames := make([]string, 0)
@@ -30,7 +30,7 @@ import (
 type NASFlexGroupStorageDriver struct {
 	initialized bool
 	Config      drivers.OntapStorageDriverConfig
-	API         *api.Client
+	API         api.OntapAPI
 	Telemetry   *Telemetry
 
 	physicalPool *storage.Pool
@@ -41,7 +41,7 @@ func (d *NASFlexGroupStorageDriver) GetConfig() *drivers.OntapStorageDriverConfi
 	return &d.Config
 }
 
-func (d *NASFlexGroupStorageDriver) GetAPI() *api.Client {
+func (d *NASFlexGroupStorageDriver) GetAPI() api.OntapAPI {
 	return d.API
 }
 
@@ -525,7 +525,9 @@ func (d *NASFlexGroupStorageDriver) Create(
 		tieringPolicy = "none"
 	}
 
-	if d.Config.AutoExportPolicy {
+	if d.Config.PerVolumeExportPolicy {
+		exportPolicy = getVolumeExportPolicyName(name)
+	} else if d.Config.AutoExportPolicy {
 		exportPolicy = getExportPolicyName(storagePool.Backend.BackendUUID)
 	}
 
@@ -560,6 +562,13 @@ func (d *NASFlexGroupStorageDriver) Create(
 		return err
 	}
 
+	// A per-volume export policy starts without rules, which are added as the volume is published to nodes
+	if d.Config.PerVolumeExportPolicy {
+		if _, err = createVolumeExportPolicy(ctx, name, d.API); err != nil {
+			return err
+		}
+	}
+
 	// Create the FlexGroup
 	checkVolumeCreated := func() error {
 		_, err = d.API.FlexGroupCreate(
@@ -584,6 +593,9 @@ func (d *NASFlexGroupStorageDriver) Create(
 
 	// Run the volume check using an exponential backoff
 	if err := backoff.RetryNotify(checkVolumeCreated, volumeBackoff, volumeCreateNotify); err != nil {
+		if d.Config.PerVolumeExportPolicy {
+			deleteVolumeExportPolicy(ctx, name, d.API)
+		}
 		createErrors = append(createErrors, fmt.Errorf("ONTAP-NAS-FLEXGROUP pool %s; error creating FlexGroup %v: %v", storagePool.Name, name, err))
 		return drivers.NewBackendIneligibleError(name, createErrors, physicalPoolNames)
 	}
@@ -630,6 +642,13 @@ func (d *NASFlexGroupStorageDriver) CreateClone(
 	return CreateCloneNAS(ctx, d, volConfig, storagePool, sourceLabel, api.MaxNASLabelLength, true)
 }
 
+// SplitClone starts splitting a clone from its source volume if called for
+func (d *NASFlexGroupStorageDriver) SplitClone(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+) error {
+	return SplitCloneNAS(ctx, d, volConfig, storagePool)
+}
+
 // Import brings an existing volume under trident's control
 func (d *NASFlexGroupStorageDriver) Import(
 	ctx context.Context, volConfig *storage.VolumeConfig, originalName string,
@@ -752,6 +771,10 @@ func (d *NASFlexGroupStorageDriver) Destroy(ctx context.Context, name string) er
 		return fmt.Errorf("error destroying FlexGroup %v: %v", name, err)
 	}
 
+	if d.Config.PerVolumeExportPolicy {
+		deleteVolumeExportPolicy(ctx, name, d.API)
+	}
+
 	return nil
 }
 
@@ -786,9 +809,36 @@ func (d *NASFlexGroupStorageDriver) Publish(
 	publishInfo.FilesystemType = "nfs"
 	publishInfo.MountOptions = mountOptions
 
+	// Volumes with their own export policy only need a rule for this node
+	if published, err := publishVolumeExportPolicy(ctx, d.API, &d.Config, publishInfo, name); err != nil {
+		return err
+	} else if published {
+		return nil
+	}
+
 	return publishFlexVolShare(ctx, d.API, &d.Config, publishInfo, name)
 }
 
+// Unpublish the volume from the host specified in publishInfo.  Volumes that share the backend's export
+// policy are exported to all nodes, so only volumes with their own export policy have rules to remove.
+func (d *NASFlexGroupStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "NASFlexGroupStorageDriver",
+			"name":   volConfig.InternalName,
+			"node":   publishInfo.HostName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	return unpublishVolumeExportPolicy(ctx, d.API, &d.Config, publishInfo, volConfig.InternalName)
+}
+
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
 func (d *NASFlexGroupStorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig) error {
 	return nil
@@ -910,6 +960,37 @@ func (d *NASFlexGroupStorageDriver) Get(ctx context.Context, name string) error
 	return nil
 }
 
+// GetVolumeCondition returns the condition of a volume's FlexGroup, which is abnormal if the FlexGroup
+// does not exist, is not online, or is nearly full.
+func (d *NASFlexGroupStorageDriver) GetVolumeCondition(
+	ctx context.Context, volConfig *storage.VolumeConfig,
+) (*storage.VolumeCondition, error) {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "GetVolumeCondition", "Type": "NASFlexGroupStorageDriver", "name": name}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetVolumeCondition")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetVolumeCondition")
+	}
+
+	volExists, err := d.API.FlexGroupExists(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for existing volume %s: %v", name, err)
+	}
+	if !volExists {
+		return storage.NewAbnormalVolumeCondition("volume %s does not exist", name), nil
+	}
+
+	// Only online FlexGroups are returned
+	volAttrs, err := d.API.FlexGroupGet(name)
+	if err != nil {
+		Logc(ctx).WithField("FlexGroup", name).WithError(err).Debug("Could not get FlexGroup.")
+		return storage.NewAbnormalVolumeCondition("volume %s is not online", name), nil
+	}
+
+	return getVolumeAttributesCondition(name, volAttrs, true), nil
+}
+
 // getStorageBackendSpecsCommon updates the specified Backend object with StoragePools.
 func (d *NASFlexGroupStorageDriver) GetStorageBackendSpecs(_ context.Context, backend *storage.Backend) error {
 	backend.Name = d.BackendName()
@@ -938,6 +1019,37 @@ func (d *NASFlexGroupStorageDriver) GetStorageBackendPhysicalPoolNames(context.C
 	return physicalPoolNames
 }
 
+// GetCapacity returns the capacity of the SVM, which is the sum of the capacities of its aggregates
+// since a FlexGroup may span all of them.
+func (d *NASFlexGroupStorageDriver) GetCapacity(
+	ctx context.Context, storagePool *storage.Pool, _ map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+
+	if storagePool.Name != d.physicalPool.Name {
+		if _, ok := d.virtualPools[storagePool.Name]; !ok {
+			return nil, fmt.Errorf("could not find pool %s", storagePool.Name)
+		}
+	}
+
+	aggrNames, err := d.API.VserverGetAggregateNames()
+	if err != nil {
+		return nil, err
+	}
+
+	aggrCapacities, err := getAggregateCapacities(ctx, d, aggrNames)
+	if err != nil {
+		return nil, err
+	}
+
+	svmCapacity := &storage.PoolCapacity{}
+	for _, aggrCapacity := range aggrCapacities {
+		svmCapacity.TotalBytes += aggrCapacity.TotalBytes
+		svmCapacity.AvailableBytes += aggrCapacity.AvailableBytes
+	}
+
+	return map[string]*storage.PoolCapacity{d.physicalPool.Name: svmCapacity}, nil
+}
+
 func (d *NASFlexGroupStorageDriver) vserverAggregates(svmName string) ([]string, error) {
 	var err error
 	// Get the aggregates assigned to the SVM.  There must be at least one!
@@ -1174,6 +1286,90 @@ func (d *NASFlexGroupStorageDriver) Resize(
 	return nil
 }
 
+// Modify changes the attributes of an existing volume.
+func (d *NASFlexGroupStorageDriver) Modify(
+	ctx context.Context, volConfig *storage.VolumeConfig, modification *storage.VolumeModification,
+) error {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Modify",
+			"Type":         "NASFlexGroupStorageDriver",
+			"name":         name,
+			"modification": modification,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Modify")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Modify")
+	}
+
+	if err := validateVolumeModification(ctx, d, modification, "snapshotPolicy", "snapshotReserve",
+		"exportPolicy", "qosPolicy", "adaptiveQosPolicy", "tieringPolicy", "unixPermissions"); err != nil {
+		return err
+	}
+
+	// Trident owns the export policies of volumes on backends that manage export policies automatically
+	if modification.ExportPolicy != "" && d.Config.AutoExportPolicy {
+		return utils.UnsupportedError(fmt.Sprintf(
+			"cannot modify the export policy of volume %s because the backend manages export policies", name))
+	}
+
+	qosPolicyGroup, err := api.NewQosPolicyGroup(modification.QosPolicy, modification.AdaptiveQosPolicy)
+	if err != nil {
+		return err
+	}
+
+	volExists, err := d.API.FlexGroupExists(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing FlexGroup: %v", err)
+	}
+	if !volExists {
+		return fmt.Errorf("FlexGroup %s does not exist", name)
+	}
+
+	if modification.SnapshotPolicy != "" {
+		if _, err = d.API.FlexGroupModifySnapshotPolicy(ctx, name, modification.SnapshotPolicy); err != nil {
+			return fmt.Errorf("error modifying snapshot policy of FlexGroup %s: %v", name, err)
+		}
+	}
+
+	if modification.SnapshotReserve != "" {
+		snapshotReserve, err := strconv.Atoi(modification.SnapshotReserve)
+		if err != nil {
+			return fmt.Errorf("invalid value for snapshotReserve: %v", err)
+		}
+		if _, err = d.API.FlexGroupModifySnapshotReserve(ctx, name, snapshotReserve); err != nil {
+			return fmt.Errorf("error modifying snapshot reserve of FlexGroup %s: %v", name, err)
+		}
+	}
+
+	if modification.TieringPolicy != "" {
+		if _, err = d.API.FlexGroupModifyTieringPolicy(ctx, name, modification.TieringPolicy); err != nil {
+			return fmt.Errorf("error modifying tiering policy of FlexGroup %s: %v", name, err)
+		}
+	}
+
+	if modification.ExportPolicy != "" {
+		if _, err = d.API.FlexGroupModifyExportPolicy(ctx, name, modification.ExportPolicy); err != nil {
+			return fmt.Errorf("error modifying export policy of FlexGroup %s: %v", name, err)
+		}
+	}
+
+	if modification.UnixPermissions != "" {
+		if _, err = d.API.FlexGroupModifyUnixPermissions(ctx, name, modification.UnixPermissions); err != nil {
+			return fmt.Errorf("error modifying unix permissions of FlexGroup %s: %v", name, err)
+		}
+	}
+
+	if qosPolicyGroup.Kind != api.InvalidQosPolicyGroupKind {
+		if _, err = d.API.FlexGroupSetQosPolicyGroupName(ctx, name, qosPolicyGroup); err != nil {
+			return fmt.Errorf("error setting QoS policy of FlexGroup %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
 func (d *NASFlexGroupStorageDriver) ReconcileNodeAccess(
 	ctx context.Context, nodes []*utils.Node, backendUUID string,
 ) error {
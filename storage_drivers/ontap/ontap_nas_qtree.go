@@ -35,16 +35,27 @@ const (
 	maxQtreesPerFlexvol                         = 300
 	defaultPruneFlexvolsPeriodSecs              = uint64(600)   // default to 10 minutes
 	defaultResizeQuotasPeriodSecs               = uint64(60)    // default to 1 minute
+	defaultPruneSnapshotsPeriodSecs             = uint64(600)   // default to 10 minutes
 	defaultEmptyFlexvolDeferredDeletePeriodSecs = uint64(28800) // default to 8 hours
 	pruneTask                                   = "prune"
 	resizeTask                                  = "resize"
+	snapshotTask                                = "snapshot"
 )
 
+// qtreeSnapshotSeparator separates the qtree name from the snapshot name in the names of the
+// Flexvol snapshots that hold qtree snapshots.
+const qtreeSnapshotSeparator = "__"
+
+// qtreeCloneSnapshotPrefix identifies the temporary Flexvol snapshots taken to clone a qtree when no
+// source snapshot is specified.  Trident does not track these snapshots, so the driver deletes them
+// as soon as the clones created from them have been split.
+const qtreeCloneSnapshotPrefix = "clone-"
+
 // NASQtreeStorageDriver is for NFS storage provisioning of qtrees
 type NASQtreeStorageDriver struct {
 	initialized                      bool
 	Config                           drivers.OntapStorageDriverConfig
-	API                              *api.Client
+	API                              api.OntapAPI
 	Telemetry                        *Telemetry
 	quotaResizeMap                   map[string]bool
 	flexvolNamePrefix                string
@@ -64,7 +75,7 @@ func (d *NASQtreeStorageDriver) GetConfig() *drivers.OntapStorageDriverConfig {
 	return &d.Config
 }
 
-func (d *NASQtreeStorageDriver) GetAPI() *api.Client {
+func (d *NASQtreeStorageDriver) GetAPI() api.OntapAPI {
 	return d.API
 }
 
@@ -137,7 +148,7 @@ func (d *NASQtreeStorageDriver) Initialize(
 	} else {
 		d.flexvolExportPolicy = fmt.Sprintf("%s_qtree_pool_export_policy", artifactPrefix)
 	}
-	d.sharedLockID = d.API.SVMUUID + "-" + *d.Config.StoragePrefix
+	d.sharedLockID = d.API.GetSVMUUID() + "-" + *d.Config.StoragePrefix
 	d.emptyFlexvolMap = make(map[string]time.Time)
 
 	// Ensure the qtree cap is valid
@@ -178,11 +189,13 @@ func (d *NASQtreeStorageDriver) Initialize(
 
 	// Start periodic housekeeping tasks like cleaning up unused Flexvols
 	d.housekeepingWaitGroup = &sync.WaitGroup{}
-	d.housekeepingTasks = make(map[string]*HousekeepingTask, 2)
+	d.housekeepingTasks = make(map[string]*HousekeepingTask, 3)
 	//pruneTasks := []func(){d.pruneUnusedFlexvols, d.reapDeletedQtrees}
 	//d.housekeepingTasks[pruneTask] = NewPruneTask(d, pruneTasks)
 	resizeTasks := []func(context.Context){d.resizeQuotas}
 	d.housekeepingTasks[resizeTask] = NewResizeTask(ctx, d, resizeTasks)
+	snapshotTasks := []func(context.Context){d.pruneQtreeSnapshots}
+	d.housekeepingTasks[snapshotTask] = NewSnapshotTask(ctx, d, snapshotTasks)
 	for _, task := range d.housekeepingTasks {
 		task.Start(ctx)
 	}
@@ -289,7 +302,7 @@ func (d *NASQtreeStorageDriver) Create(
 	createError := errors.New("volume creation failed")
 
 	// Ensure volume doesn't already exist
-	exists, existsInFlexvol, err := d.API.QtreeExists(ctx, name, d.FlexvolNamePrefix())
+	exists, existsInFlexvol, err := d.qtreeExists(ctx, name)
 	if err != nil {
 		Logc(ctx).Errorf("Error checking for existing volume: %v.", err)
 		return createError
@@ -300,7 +313,7 @@ func (d *NASQtreeStorageDriver) Create(
 	}
 
 	// Get candidate physical pools
-	physicalPools, err := getPoolsForCreate(ctx, volConfig, storagePool, volAttributes, d.physicalPools, d.virtualPools)
+	physicalPools, err := getPoolsForCreate(ctx, d, volConfig, storagePool, volAttributes, d.physicalPools, d.virtualPools)
 	if err != nil {
 		return err
 	}
@@ -363,6 +376,15 @@ func (d *NASQtreeStorageDriver) Create(
 		exportPolicy = getExportPolicyName(storagePool.Backend.BackendUUID)
 	}
 
+	// A per-volume export policy applies only to the qtree, while its Flexvol keeps the backend's policy.
+	// The qtree's policy starts without rules, which are added as the qtree is published to nodes.
+	qtreeExportPolicy := exportPolicy
+	if d.Config.PerVolumeExportPolicy {
+		if qtreeExportPolicy, err = createVolumeExportPolicy(ctx, name, d.API); err != nil {
+			return err
+		}
+	}
+
 	volConfig.QosPolicy = qosPolicy
 
 	createErrors := make([]error, 0)
@@ -404,7 +426,8 @@ func (d *NASQtreeStorageDriver) Create(
 		}
 
 		// Create the qtree
-		qtreeResponse, err := d.API.QtreeCreate(name, flexvol, unixPermissions, exportPolicy, securityStyle, qosPolicy)
+		qtreeResponse, err := d.API.QtreeCreate(
+			name, flexvol, unixPermissions, qtreeExportPolicy, securityStyle, qosPolicy)
 		if err = api.GetError(ctx, qtreeResponse, err); err != nil {
 			errMessage := fmt.Sprintf("ONTAP-NAS-QTREE pool %s/%s; Qtree creation failed %s/%s: %v", storagePool.Name,
 				aggregate, flexvol, name, err)
@@ -424,11 +447,18 @@ func (d *NASQtreeStorageDriver) Create(
 		return nil
 	}
 
+	if d.Config.PerVolumeExportPolicy {
+		deleteVolumeExportPolicy(ctx, name, d.API)
+	}
+
 	// All physical pools that were eligible ultimately failed, so don't try this backend again
 	return drivers.NewBackendIneligibleError(name, createErrors, physicalPoolNames)
 }
 
-// Create a volume clone
+// Create a volume clone.  Qtrees cannot be cloned directly, so a FlexClone is made of the
+// source qtree's Flexvol, all other qtrees are removed from the FlexClone, the source qtree
+// is renamed to the clone name, and the FlexClone is split from its parent so that it becomes
+// just another Flexvol managed by this driver.
 func (d *NASQtreeStorageDriver) CreateClone(
 	ctx context.Context, volConfig *storage.VolumeConfig, _ *storage.Pool,
 ) error {
@@ -449,17 +479,377 @@ func (d *NASQtreeStorageDriver) CreateClone(
 		defer Logc(ctx).WithFields(fields).Debug("<<<< CreateClone")
 	}
 
-	return fmt.Errorf("cloning is not supported by backend type %s", d.Name())
+	// Ensure the source Flexvol won't be pruned while we clone it
+	utils.Lock(ctx, "clone", d.sharedLockID)
+	defer utils.Unlock(ctx, "clone", d.sharedLockID)
+
+	// Ensure the clone doesn't already exist
+	exists, existsInFlexvol, err := d.qtreeExists(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing qtree %s: %v", name, err)
+	}
+	if exists {
+		Logc(ctx).WithFields(log.Fields{"qtree": name, "flexvol": existsInFlexvol}).Debug("Qtree already exists.")
+		return drivers.NewVolumeExistsError(name)
+	}
+
+	// Find the Flexvol containing the source qtree
+	exists, sourceFlexvol, err := d.qtreeExists(ctx, source)
+	if err != nil {
+		return fmt.Errorf("error checking for existing qtree %s: %v", source, err)
+	}
+	if !exists {
+		return fmt.Errorf("source volume %s not found", source)
+	}
+
+	// Use the requested qtree snapshot, or create a temporary one if none was specified
+	if snapshot == "" {
+		snapshot = qtreeCloneSnapshotPrefix + time.Now().UTC().Format(storage.SnapshotNameFormat)
+		snapResponse, err := d.API.SnapshotCreate(getQtreeSnapshotName(source, snapshot), sourceFlexvol)
+		if err = api.GetError(ctx, snapResponse, err); err != nil {
+			return fmt.Errorf("error creating snapshot: %v", err)
+		}
+
+		// The snapshot stays busy until the clone split completes, so if it can't be deleted
+		// right away the housekeeping task deletes it later.
+		defer d.deleteQtreeFlexvolSnapshot(ctx, getQtreeSnapshotName(source, snapshot), sourceFlexvol)
+	}
+	flexvolSnapshot := getQtreeSnapshotName(source, snapshot)
+
+	sizeBytes, err := d.getQuotaDiskLimitSize(source, sourceFlexvol)
+	if err != nil {
+		return fmt.Errorf("error reading size of source volume %s: %v", source, err)
+	}
+
+	// Create the FlexClone that will contain the new qtree
+	flexvol := d.FlexvolNamePrefix() + utils.RandomString(10)
+
+	cloneResponse, err := d.API.VolumeCloneCreate(flexvol, sourceFlexvol, flexvolSnapshot)
+	if err != nil {
+		return fmt.Errorf("error creating clone: %v", err)
+	}
+	if zerr := api.NewZapiError(cloneResponse); !zerr.IsPassed() {
+		if err = handleCreateOntapCloneErr(ctx, zerr, d.API, flexvolSnapshot, sourceFlexvol, flexvol); err != nil {
+			return err
+		}
+	}
+
+	if err = d.prepareFlexvolCloneForQtree(ctx, flexvol, source, name, sizeBytes); err != nil {
+		if _, destroyErr := d.API.VolumeDestroy(flexvol, true); destroyErr != nil {
+			Logc(ctx).WithField("flexvol", flexvol).Error(destroyErr)
+		}
+		if d.Config.PerVolumeExportPolicy {
+			deleteVolumeExportPolicy(ctx, name, d.API)
+		}
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"qtree":         name,
+		"flexvol":       flexvol,
+		"sourceQtree":   source,
+		"sourceFlexvol": sourceFlexvol,
+		"snapshot":      flexvolSnapshot,
+	}).Debug("Created qtree clone.")
+
+	return nil
 }
 
+// prepareFlexvolCloneForQtree turns a FlexClone of a qtree Flexvol into a Flexvol containing only
+// the cloned qtree.  The FlexClone is mounted, the source qtree is renamed to the clone name, all
+// other qtrees are deleted, quotas are established, and the FlexClone is split from its parent.
+func (d *NASQtreeStorageDriver) prepareFlexvolCloneForQtree(
+	ctx context.Context, flexvol, source, name string, sizeBytes uint64,
+) error {
+
+	// Mount the FlexClone at its own junction
+	mountResponse, err := d.API.VolumeMount(flexvol, "/"+flexvol)
+	if err = api.GetError(ctx, mountResponse, err); err != nil {
+		return fmt.Errorf("error mounting Flexvol %s: %v", flexvol, err)
+	}
+
+	// Rename the source qtree in the FlexClone
+	path := fmt.Sprintf("/vol/%s/%s", flexvol, source)
+	newPath := fmt.Sprintf("/vol/%s/%s", flexvol, name)
+	renameResponse, err := d.API.QtreeRename(path, newPath)
+	if err = api.GetError(ctx, renameResponse, err); err != nil {
+		return fmt.Errorf("error renaming qtree %s in Flexvol %s: %v", source, flexvol, err)
+	}
+
+	// The cloned qtree inherits its source's export policy, so give it a policy of its own if necessary
+	if d.Config.PerVolumeExportPolicy {
+		policyName, err := createVolumeExportPolicy(ctx, name, d.API)
+		if err != nil {
+			return err
+		}
+		modifyResponse, err := d.API.QtreeModifyExportPolicy(name, flexvol, policyName)
+		if err = api.GetError(ctx, modifyResponse, err); err != nil {
+			return fmt.Errorf("error modifying export policy of qtree %s: %v", name, err)
+		}
+	}
+
+	// Remove all other qtrees from the FlexClone
+	listResponse, err := d.API.QtreeList("", flexvol)
+	if err = api.GetError(ctx, listResponse, err); err != nil {
+		return fmt.Errorf("error listing qtrees in Flexvol %s: %v", flexvol, err)
+	}
+	if listResponse.Result.AttributesListPtr != nil {
+		for _, qtree := range listResponse.Result.AttributesListPtr.QtreeInfoPtr {
+			// Skip the Flexvol's own qtree and the cloned qtree
+			if qtree.Volume() != flexvol || qtree.Qtree() == "" || qtree.Qtree() == name {
+				continue
+			}
+			qtreePath := fmt.Sprintf("/vol/%s/%s", flexvol, qtree.Qtree())
+			destroyResponse, err := d.API.QtreeDestroyAsync(qtreePath, true)
+			if err = api.GetError(ctx, destroyResponse, err); err != nil {
+				return fmt.Errorf("error deleting qtree %s from Flexvol clone: %v", qtreePath, err)
+			}
+		}
+	}
+
+	// Quota rules are not cloned, so add the default quota and the cloned qtree's quota
+	if err = d.addDefaultQuotaForFlexvol(ctx, flexvol); err != nil {
+		return fmt.Errorf("error adding default quota to Flexvol %s: %v", flexvol, err)
+	}
+	if err = d.setQuotaForQtree(ctx, name, flexvol, sizeBytes); err != nil {
+		return fmt.Errorf("error setting quota for qtree %s: %v", name, err)
+	}
+
+	// Split the FlexClone so the source snapshot is no longer busy
+	splitResponse, err := d.API.VolumeCloneSplitStart(flexvol)
+	if err = api.GetError(ctx, splitResponse, err); err != nil {
+		return fmt.Errorf("error splitting clone: %v", err)
+	}
+
+	return nil
+}
+
+// Import brings an existing qtree under Trident's control.  The qtree is specified by its path,
+// in the form <flexvol>/<qtree>, and its Flexvol may be any in the SVM that resides in one of the
+// aggregates available to this backend.
 func (d *NASQtreeStorageDriver) Import(
-	context.Context, *storage.VolumeConfig, string,
+	ctx context.Context, volConfig *storage.VolumeConfig, originalName string,
 ) error {
-	return errors.New("import is not implemented")
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Import",
+			"Type":         "NASQtreeStorageDriver",
+			"originalName": originalName,
+			"newName":      volConfig.InternalName,
+			"notManaged":   volConfig.ImportNotManaged,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Import")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Import")
+	}
+
+	// Ensure the qtree will not be renamed or removed while we import it
+	utils.Lock(ctx, "import", d.sharedLockID)
+	defer utils.Unlock(ctx, "import", d.sharedLockID)
+
+	flexvol, qtree, err := d.parseQtreePath(originalName)
+	if err != nil {
+		return err
+	}
+
+	if err = d.validateImportFlexvol(ctx, flexvol); err != nil {
+		return err
+	}
+
+	// Ensure the qtree exists in the specified Flexvol
+	exists, err := d.qtreeExistsInFlexvol(ctx, qtree, flexvol)
+	if err != nil {
+		return fmt.Errorf("error checking for existing qtree %s: %v", originalName, err)
+	}
+	if !exists {
+		return fmt.Errorf("qtree %s not found", originalName)
+	}
+
+	// An unmanaged qtree keeps its name, by which this driver finds it later, so the name must be unique
+	if volConfig.ImportNotManaged {
+		unique, uniqueInFlexvol, err := d.qtreeExists(ctx, qtree)
+		if err != nil {
+			return fmt.Errorf("error checking for existing qtree %s: %v", originalName, err)
+		}
+		if !unique || uniqueInFlexvol != flexvol {
+			return fmt.Errorf("qtree name %s is not unique in the SVM, so qtree %s may only be imported "+
+				"if Trident manages it", qtree, originalName)
+		}
+	}
+
+	// Get the qtree size from its quota, falling back to the Flexvol size
+	sizeBytes, err := d.getQuotaDiskLimitSize(qtree, flexvol)
+	if err != nil {
+		Logc(ctx).WithField("originalName", originalName).Debugf(
+			"Could not read qtree quota, using Flexvol size; %v", err)
+		flexvolSize, err := d.API.VolumeSize(flexvol)
+		if err != nil {
+			return fmt.Errorf("could not determine size of qtree %s: %v", originalName, err)
+		}
+		sizeBytes = uint64(flexvolSize)
+	}
+	volConfig.Size = strconv.FormatUint(sizeBytes, 10)
+
+	if volConfig.ImportNotManaged {
+		// The qtree keeps its name, which is all this driver needs to find it later
+		volConfig.InternalName = qtree
+		return nil
+	}
+
+	// Rename the qtree, since Trident will manage its lifecycle
+	path := fmt.Sprintf("/vol/%s/%s", flexvol, qtree)
+	newPath := fmt.Sprintf("/vol/%s/%s", flexvol, volConfig.InternalName)
+	renameResponse, err := d.API.QtreeRename(path, newPath)
+	if err = api.GetError(ctx, renameResponse, err); err != nil {
+		Logc(ctx).WithField("originalName", originalName).Errorf("Could not import volume, rename failed: %v", err)
+		return fmt.Errorf("qtree %s rename failed: %v", originalName, err)
+	}
+
+	// Ensure the renamed qtree has a quota so it may be resized later
+	if err = d.setQuotaForQtree(ctx, volConfig.InternalName, flexvol, sizeBytes); err != nil {
+		Logc(ctx).WithField("originalName", originalName).Errorf("Could not import volume, quota failed: %v", err)
+		return fmt.Errorf("qtree %s quota definition failed: %v", originalName, err)
+	}
+
+	return nil
+}
+
+// Rename changes the name of a qtree.  The new name may be a qtree name or a path in the form
+// <flexvol>/<qtree>, but the qtree may not be moved to a different Flexvol.
+func (d *NASQtreeStorageDriver) Rename(ctx context.Context, name, newName string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":  "Rename",
+			"Type":    "NASQtreeStorageDriver",
+			"name":    name,
+			"newName": newName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Rename")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Rename")
+	}
+
+	exists, flexvol, err := d.qtreeExists(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing qtree %s: %v", name, err)
+	}
+	if !exists {
+		return fmt.Errorf("qtree %s not found", name)
+	}
+
+	newQtree := newName
+	if strings.Contains(newName, "/") {
+		newFlexvol, qtree, err := d.parseQtreePath(newName)
+		if err != nil {
+			return err
+		}
+		if newFlexvol != flexvol {
+			return fmt.Errorf("qtree %s cannot be moved from Flexvol %s to %s", name, flexvol, newFlexvol)
+		}
+		newQtree = qtree
+	}
+
+	path := fmt.Sprintf("/vol/%s/%s", flexvol, name)
+	newPath := fmt.Sprintf("/vol/%s/%s", flexvol, newQtree)
+	renameResponse, err := d.API.QtreeRename(path, newPath)
+	if err = api.GetError(ctx, renameResponse, err); err != nil {
+		return fmt.Errorf("error renaming qtree %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// parseQtreePath splits a qtree path in the form <flexvol>/<qtree> into its Flexvol and qtree names.
+func (d *NASQtreeStorageDriver) parseQtreePath(path string) (flexvol, qtree string, err error) {
+
+	pathElements := strings.Split(strings.Trim(path, "/"), "/")
+	if len(pathElements) == 3 && pathElements[0] == "vol" {
+		pathElements = pathElements[1:]
+	}
+	if len(pathElements) != 2 || pathElements[0] == "" || pathElements[1] == "" {
+		return "", "", fmt.Errorf("invalid qtree path %s; expected <flexvol>/<qtree>", path)
+	}
+
+	return pathElements[0], pathElements[1], nil
+}
+
+// validateImportFlexvol ensures that qtrees may be imported from the specified Flexvol, which must
+// reside in one of the aggregates available to this backend.  The API client only sees the Flexvols
+// of the backend's SVM, so a Flexvol in any other SVM is not found.
+func (d *NASQtreeStorageDriver) validateImportFlexvol(ctx context.Context, flexvol string) error {
+
+	volAttrs, err := d.API.VolumeGet(flexvol)
+	if err != nil {
+		return fmt.Errorf("could not get Flexvol %s: %v", flexvol, err)
+	}
+	if volAttrs.VolumeIdAttributesPtr == nil || volAttrs.VolumeIdAttributesPtr.ContainingAggregateNamePtr == nil {
+		return fmt.Errorf("could not determine the aggregate of Flexvol %s", flexvol)
+	}
+
+	aggregate := volAttrs.VolumeIdAttributesPtr.ContainingAggregateName()
+	if _, ok := d.physicalPools[aggregate]; !ok {
+		return fmt.Errorf("flexvol %s is in aggregate %s, which is not available to this backend",
+			flexvol, aggregate)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"flexvol":   flexvol,
+		"aggregate": aggregate,
+	}).Debug("Flexvol may hold imported qtrees.")
+
+	return nil
+}
+
+// qtreeExists checks for the named qtree and returns the name of the Flexvol containing it.  Qtrees
+// are normally in the Flexvols created by this driver, but an imported qtree may be in any Flexvol in
+// the SVM, where it is only found if no other qtree has the same name.
+func (d *NASQtreeStorageDriver) qtreeExists(ctx context.Context, name string) (bool, string, error) {
+
+	exists, flexvol, err := d.API.QtreeExists(ctx, name, d.FlexvolNamePrefix())
+	if err != nil || exists {
+		return exists, flexvol, err
+	}
+
+	return d.API.QtreeExists(ctx, name, "")
 }
 
-func (d *NASQtreeStorageDriver) Rename(context.Context, string, string) error {
-	return errors.New("rename is not implemented")
+// findQtree checks for a qtree specified by its name or, as when validating an import, by a path in
+// the form <flexvol>/<qtree>, and returns the names of the qtree and the Flexvol containing it.
+func (d *NASQtreeStorageDriver) findQtree(
+	ctx context.Context, name string,
+) (exists bool, qtree, flexvol string, err error) {
+
+	if !strings.Contains(name, "/") {
+		exists, flexvol, err = d.qtreeExists(ctx, name)
+		return exists, name, flexvol, err
+	}
+
+	if flexvol, qtree, err = d.parseQtreePath(name); err != nil {
+		return false, "", "", err
+	}
+	exists, err = d.qtreeExistsInFlexvol(ctx, qtree, flexvol)
+	return exists, qtree, flexvol, err
+}
+
+// qtreeExistsInFlexvol checks whether the named qtree exists in the named Flexvol.
+func (d *NASQtreeStorageDriver) qtreeExistsInFlexvol(ctx context.Context, name, flexvol string) (bool, error) {
+
+	// The list matches any qtree and Flexvol whose names begin with those specified
+	listResponse, err := d.API.QtreeList(name, flexvol)
+	if err = api.GetError(ctx, listResponse, err); err != nil {
+		return false, err
+	}
+	if listResponse.Result.AttributesListPtr == nil {
+		return false, nil
+	}
+
+	for _, qtree := range listResponse.Result.AttributesListPtr.QtreeInfoPtr {
+		if qtree.Volume() == flexvol && qtree.Qtree() == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
 // Destroy the volume
@@ -482,7 +872,7 @@ func (d *NASQtreeStorageDriver) Destroy(ctx context.Context, name string) error
 	// Generic user-facing message
 	deleteError := errors.New("volume deletion failed")
 
-	exists, flexvol, err := d.API.QtreeExists(ctx, name, d.FlexvolNamePrefix())
+	exists, flexvol, err := d.qtreeExists(ctx, name)
 	if err != nil {
 		Logc(ctx).Errorf("Error checking for existing qtree. %v", err)
 		return deleteError
@@ -492,6 +882,10 @@ func (d *NASQtreeStorageDriver) Destroy(ctx context.Context, name string) error
 		return nil
 	}
 
+	if d.Config.PerVolumeExportPolicy {
+		d.deleteQtreeExportPolicy(ctx, name, flexvol)
+	}
+
 	// Rename qtree so it doesn't show up in lists while ONTAP is deleting it in the background.
 	// Ensure the deleted name doesn't exceed the qtree name length limit of 64 characters.
 	path := fmt.Sprintf("/vol/%s/%s", flexvol, name)
@@ -518,9 +912,91 @@ func (d *NASQtreeStorageDriver) Destroy(ctx context.Context, name string) error
 		return deleteError
 	}
 
+	// Snapshots of the qtree are held by its Flexvol, so they must be deleted separately.  Any that are
+	// busy are left for the housekeeping task.
+	d.deleteQtreeSnapshots(ctx, name, flexvol)
+
 	return nil
 }
 
+// deleteQtreeSnapshots deletes all Flexvol snapshots that hold snapshots of the specified qtree.
+func (d *NASQtreeStorageDriver) deleteQtreeSnapshots(ctx context.Context, qtree, flexvol string) {
+
+	snapListResponse, err := d.API.SnapshotList(flexvol)
+	if err = api.GetError(ctx, snapListResponse, err); err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"qtree":   qtree,
+			"flexvol": flexvol,
+			"error":   err,
+		}).Warning("Could not list snapshots of deleted qtree.")
+		return
+	}
+	if snapListResponse.Result.AttributesListPtr == nil {
+		return
+	}
+
+	for _, snap := range snapListResponse.Result.AttributesListPtr.SnapshotInfoPtr {
+		if snapQtree, _, ok := parseQtreeSnapshotName(snap.Name()); ok && snapQtree == qtree {
+			d.deleteQtreeFlexvolSnapshot(ctx, snap.Name(), flexvol)
+		}
+	}
+}
+
+// deleteQtreeFlexvolSnapshot deletes a Flexvol snapshot that holds a qtree snapshot.  Failures are only
+// logged, since the housekeeping task deletes any such snapshots that Trident no longer needs.
+func (d *NASQtreeStorageDriver) deleteQtreeFlexvolSnapshot(ctx context.Context, snapshot, flexvol string) {
+
+	logFields := log.Fields{"snapshot": snapshot, "flexvol": flexvol}
+
+	snapResponse, err := d.API.SnapshotDelete(snapshot, flexvol)
+	if err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Warning("Could not delete snapshot.")
+		return
+	}
+	if zerr := api.NewZapiError(snapResponse); !zerr.IsPassed() {
+		if zerr.Code() == azgo.ESNAPSHOTBUSY {
+			Logc(ctx).WithFields(logFields).Debug("Snapshot is busy, deferring deletion.")
+		} else {
+			Logc(ctx).WithFields(logFields).WithError(zerr).Warning("Could not delete snapshot.")
+		}
+		return
+	}
+
+	Logc(ctx).WithFields(logFields).Debug("Deleted snapshot.")
+}
+
+// deleteQtreeExportPolicy deletes the export policy dedicated to a qtree.  ONTAP will not delete a policy
+// that is in use, so the qtree is first switched to the export policy of its Flexvol.
+func (d *NASQtreeStorageDriver) deleteQtreeExportPolicy(ctx context.Context, qtree, flexvol string) {
+
+	policyName := getVolumeExportPolicyName(qtree)
+	if exists, err := isExportPolicyExists(ctx, policyName, d.API); err != nil || !exists {
+		return
+	}
+
+	volume, err := d.API.VolumeGet(flexvol)
+	if err != nil || volume.VolumeExportAttributesPtr == nil {
+		Logc(ctx).WithFields(log.Fields{
+			"qtree":   qtree,
+			"flexvol": flexvol,
+		}).Warningf("Could not determine Flexvol export policy; %v", err)
+		return
+	}
+
+	flexvolPolicy := volume.VolumeExportAttributesPtr.Policy()
+	modifyResponse, err := d.API.QtreeModifyExportPolicy(qtree, flexvol, flexvolPolicy)
+	if err = api.GetError(ctx, modifyResponse, err); err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"qtree":        qtree,
+			"flexvol":      flexvol,
+			"exportPolicy": flexvolPolicy,
+		}).Warningf("Could not modify qtree export policy; %v", err)
+		return
+	}
+
+	deleteVolumeExportPolicy(ctx, qtree, d.API)
+}
+
 // Publish the volume to the host specified in publishInfo.  This method may or may not be running on the host
 // where the volume will be mounted, so it should limit itself to updating access rules, initiator groups, etc.
 // that require some host identity (but not locality) as well as storage controller API access.
@@ -541,7 +1017,7 @@ func (d *NASQtreeStorageDriver) Publish(
 	}
 
 	// Check if qtree exists, and find its Flexvol so we can build the export location
-	exists, flexvol, err := d.API.QtreeExists(ctx, name, d.FlexvolNamePrefix())
+	exists, flexvol, err := d.qtreeExists(ctx, name)
 	if err != nil {
 		Logc(ctx).Errorf("Error checking for existing qtree. %v", err)
 		return errors.New("volume mount failed")
@@ -566,6 +1042,26 @@ func (d *NASQtreeStorageDriver) Publish(
 	return d.publishQtreeShare(ctx, name, flexvol, publishInfo)
 }
 
+// Unpublish the volume from the host specified in publishInfo.  Qtrees that share the backend's export
+// policy are exported to all nodes, so only qtrees with their own export policy have rules to remove.
+func (d *NASQtreeStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "NASQtreeStorageDriver",
+			"name":   volConfig.InternalName,
+			"node":   publishInfo.HostName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	return unpublishVolumeExportPolicy(ctx, d.API, &d.Config, publishInfo, volConfig.InternalName)
+}
+
 func (d *NASQtreeStorageDriver) publishQtreeShare(
 	ctx context.Context, qtree, flexvol string, publishInfo *utils.VolumePublishInfo,
 ) error {
@@ -584,30 +1080,97 @@ func (d *NASQtreeStorageDriver) publishQtreeShare(
 		return nil
 	}
 
-	if err := ensureNodeAccess(ctx, publishInfo, d.API, &d.Config); err != nil {
+	// Qtrees with their own export policy only need a rule for this node
+	published, err := publishVolumeExportPolicy(ctx, d.API, &d.Config, publishInfo, qtree)
+	if err != nil {
 		return err
 	}
 
-	// Ensure the qtree has the correct export policy applied
-	policyName := getExportPolicyName(publishInfo.BackendUUID)
-	modifyResponse, err := d.API.QtreeModifyExportPolicy(qtree, flexvol, policyName)
-	if err = api.GetError(ctx, modifyResponse, err); err != nil {
-		err = fmt.Errorf("error modifying qtree export policy; %v", err)
-		Logc(ctx).WithFields(log.Fields{
-			"Qtree":        qtree,
-			"FlexVol":      flexvol,
-			"ExportPolicy": policyName,
-		}).Error(err)
-		return err
+	if !published {
+		if err := ensureNodeAccess(ctx, publishInfo, d.API, &d.Config); err != nil {
+			return err
+		}
+
+		// Ensure the qtree has the correct export policy applied
+		policyName := getExportPolicyName(publishInfo.BackendUUID)
+		modifyResponse, err := d.API.QtreeModifyExportPolicy(qtree, flexvol, policyName)
+		if err = api.GetError(ctx, modifyResponse, err); err != nil {
+			err = fmt.Errorf("error modifying qtree export policy; %v", err)
+			Logc(ctx).WithFields(log.Fields{
+				"Qtree":        qtree,
+				"FlexVol":      flexvol,
+				"ExportPolicy": policyName,
+			}).Error(err)
+			return err
+		}
 	}
 
 	// Ensure the qtree's volume has the correct export policy applied
 	return publishFlexVolShare(ctx, d.API, &d.Config, publishInfo, flexvol)
 }
 
+// getQtreeSnapshotName returns the name of the Flexvol snapshot that holds the named snapshot
+// of a qtree.  Snapshots are taken of a qtree's containing Flexvol, so the qtree name is
+// included to keep the snapshots of each qtree separate.
+func getQtreeSnapshotName(qtree, snapshot string) string {
+	return qtree + qtreeSnapshotSeparator + snapshot
+}
+
+// parseQtreeSnapshotName returns the names of the qtree and of its snapshot that are held by the named
+// Flexvol snapshot, or false if the Flexvol snapshot does not hold a qtree snapshot.  Qtree names may
+// contain the separator, such as when the storage prefix does, but snapshot names may not, so the name
+// is split at the last separator.
+func parseQtreeSnapshotName(name string) (qtree, snapshot string, ok bool) {
+	i := strings.LastIndex(name, qtreeSnapshotSeparator)
+	if i <= 0 || i+len(qtreeSnapshotSeparator) == len(name) {
+		return "", "", false
+	}
+	return name[:i], name[i+len(qtreeSnapshotSeparator):], true
+}
+
+// getFlexvolSnapshotConfig returns a snapshot config that refers to the Flexvol snapshot holding
+// the specified qtree snapshot, so that it may be used with the common ONTAP snapshot functions.
+// A size getter that reports the qtree size is also returned.
+func (d *NASQtreeStorageDriver) getFlexvolSnapshotConfig(
+	ctx context.Context, snapConfig *storage.SnapshotConfig,
+) (*storage.SnapshotConfig, func(string) (int, error), error) {
+
+	qtree := snapConfig.VolumeInternalName
+
+	exists, flexvol, err := d.qtreeExists(ctx, qtree)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error checking for existing qtree %s: %v", qtree, err)
+	}
+	if !exists {
+		return nil, nil, fmt.Errorf("volume %s does not exist", qtree)
+	}
+
+	flexvolSnapConfig := &storage.SnapshotConfig{
+		Version:            snapConfig.Version,
+		Name:               snapConfig.Name,
+		InternalName:       getQtreeSnapshotName(qtree, snapConfig.InternalName),
+		VolumeName:         snapConfig.VolumeName,
+		VolumeInternalName: flexvol,
+	}
+
+	sizeGetter := func(string) (int, error) {
+		size, err := d.getQuotaDiskLimitSize(qtree, flexvol)
+		return int(size), err
+	}
+
+	return flexvolSnapConfig, sizeGetter, nil
+}
+
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
-func (d *NASQtreeStorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig) error {
-	return utils.UnsupportedError(fmt.Sprintf("snapshots are not supported by backend type %s", d.Name()))
+func (d *NASQtreeStorageDriver) CanSnapshot(_ context.Context, snapConfig *storage.SnapshotConfig) error {
+
+	// The separator would make the snapshot indistinguishable from those of other qtrees.  Snapshots
+	// are named on the Flexvol after their Trident names.
+	if strings.Contains(snapConfig.Name, qtreeSnapshotSeparator) {
+		return utils.InvalidInputError(fmt.Sprintf("snapshot name %s may not contain '%s'",
+			snapConfig.Name, qtreeSnapshotSeparator))
+	}
+	return nil
 }
 
 // GetSnapshot returns a snapshot of a volume, or an error if it does not exist.
@@ -626,7 +1189,16 @@ func (d *NASQtreeStorageDriver) GetSnapshot(ctx context.Context, snapConfig *sto
 		defer Logc(ctx).WithFields(fields).Debug("<<<< GetSnapshot")
 	}
 
-	return nil, utils.UnsupportedError(fmt.Sprintf("snapshots are not supported by backend type %s", d.Name()))
+	flexvolSnapConfig, sizeGetter, err := d.getFlexvolSnapshotConfig(ctx, snapConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := GetSnapshot(ctx, flexvolSnapConfig, &d.Config, d.API, sizeGetter)
+	if snapshot != nil {
+		snapshot.Config = snapConfig
+	}
+	return snapshot, err
 }
 
 // GetSnapshots returns the list of snapshots associated with the specified volume
@@ -644,8 +1216,45 @@ func (d *NASQtreeStorageDriver) GetSnapshots(ctx context.Context, volConfig *sto
 		defer Logc(ctx).WithFields(fields).Debug("<<<< GetSnapshots")
 	}
 
-	// Qtrees can't have snapshots, so return an empty list
-	return []*storage.Snapshot{}, nil
+	qtree := volConfig.InternalName
+
+	exists, flexvol, err := d.qtreeExists(ctx, qtree)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for existing qtree %s: %v", qtree, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("volume %s does not exist", qtree)
+	}
+
+	flexvolConfig := &storage.VolumeConfig{
+		Name:         volConfig.Name,
+		InternalName: flexvol,
+	}
+	sizeGetter := func(string) (int, error) {
+		size, err := d.getQuotaDiskLimitSize(qtree, flexvol)
+		return int(size), err
+	}
+
+	flexvolSnapshots, err := GetSnapshots(ctx, flexvolConfig, &d.Config, d.API, sizeGetter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only report the Flexvol snapshots that belong to this qtree
+	snapshots := make([]*storage.Snapshot, 0)
+
+	for _, snapshot := range flexvolSnapshots {
+		snapQtree, snapName, ok := parseQtreeSnapshotName(snapshot.Config.InternalName)
+		if !ok || snapQtree != qtree {
+			continue
+		}
+		snapshot.Config.Name = snapName
+		snapshot.Config.InternalName = snapName
+		snapshot.Config.VolumeInternalName = qtree
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
 }
 
 // CreateSnapshot creates a snapshot for the given volume
@@ -664,7 +1273,18 @@ func (d *NASQtreeStorageDriver) CreateSnapshot(
 		defer Logc(ctx).WithFields(fields).Debug("<<<< CreateSnapshot")
 	}
 
-	return nil, utils.UnsupportedError(fmt.Sprintf("snapshots are not supported by backend type %s", d.Name()))
+	flexvolSnapConfig, sizeGetter, err := d.getFlexvolSnapshotConfig(ctx, snapConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := CreateSnapshot(ctx, flexvolSnapConfig, &d.Config, d.API, sizeGetter)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot.Config = snapConfig
+	return snapshot, nil
 }
 
 // RestoreSnapshot restores a volume (in place) from a snapshot.
@@ -681,10 +1301,11 @@ func (d *NASQtreeStorageDriver) RestoreSnapshot(ctx context.Context, snapConfig
 		defer Logc(ctx).WithFields(fields).Debug("<<<< RestoreSnapshot")
 	}
 
-	return utils.UnsupportedError(fmt.Sprintf("snapshots are not supported by backend type %s", d.Name()))
+	// Restoring the Flexvol snapshot would also revert every other qtree in the Flexvol
+	return utils.UnsupportedError(fmt.Sprintf("snapshot restore is not supported by backend type %s", d.Name()))
 }
 
-// DeleteSnapshot creates a snapshot of a volume.
+// DeleteSnapshot deletes a snapshot of a volume.
 func (d *NASQtreeStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 
 	if d.Config.DebugTraceFlags["method"] {
@@ -698,10 +1319,16 @@ func (d *NASQtreeStorageDriver) DeleteSnapshot(ctx context.Context, snapConfig *
 		defer Logc(ctx).WithFields(fields).Debug("<<<< DeleteSnapshot")
 	}
 
-	return utils.UnsupportedError(fmt.Sprintf("snapshots are not supported by backend type %s", d.Name()))
+	flexvolSnapConfig, _, err := d.getFlexvolSnapshotConfig(ctx, snapConfig)
+	if err != nil {
+		return err
+	}
+
+	return DeleteSnapshot(ctx, flexvolSnapConfig, &d.Config, d.API)
 }
 
-// Test for the existence of a volume
+// Test for the existence of a volume.  The volume may be specified by its qtree name or, as
+// when validating an import, by a path in the form <flexvol>/<qtree>.
 func (d *NASQtreeStorageDriver) Get(ctx context.Context, name string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
@@ -713,7 +1340,7 @@ func (d *NASQtreeStorageDriver) Get(ctx context.Context, name string) error {
 	// Generic user-facing message
 	getError := fmt.Errorf("volume %s not found", name)
 
-	exists, flexvol, err := d.API.QtreeExists(ctx, name, d.FlexvolNamePrefix())
+	exists, qtree, flexvol, err := d.findQtree(ctx, name)
 	if err != nil {
 		Logc(ctx).Errorf("Error checking for existing qtree. %v", err)
 		return getError
@@ -723,11 +1350,35 @@ func (d *NASQtreeStorageDriver) Get(ctx context.Context, name string) error {
 		return getError
 	}
 
-	Logc(ctx).WithFields(log.Fields{"qtree": name, "flexvol": flexvol}).Debug("Qtree found.")
+	Logc(ctx).WithFields(log.Fields{"qtree": qtree, "flexvol": flexvol}).Debug("Qtree found.")
 
 	return nil
 }
 
+// GetVolumeCondition returns the condition of a volume's qtree and the Flexvol containing it.  Since the
+// qtrees in a Flexvol share its space, a nearly full Flexvol is reported for each of its qtrees.
+func (d *NASQtreeStorageDriver) GetVolumeCondition(
+	ctx context.Context, volConfig *storage.VolumeConfig,
+) (*storage.VolumeCondition, error) {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "GetVolumeCondition", "Type": "NASQtreeStorageDriver", "name": name}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetVolumeCondition")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetVolumeCondition")
+	}
+
+	exists, _, flexvol, err := d.findQtree(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for existing qtree %s: %v", name, err)
+	}
+	if !exists {
+		return storage.NewAbnormalVolumeCondition("qtree %s does not exist", name), nil
+	}
+
+	return getFlexvolCondition(ctx, flexvol, true, d.API)
+}
+
 // ensureFlexvolForQtree accepts a set of Flexvol characteristics and either finds one to contain a new
 // qtree or it creates a new Flexvol with the needed attributes.
 func (d *NASQtreeStorageDriver) ensureFlexvolForQtree(
@@ -805,7 +1456,7 @@ func (d *NASQtreeStorageDriver) createFlexvolForQtree(
 	createResponse, err := d.API.VolumeCreate(
 		ctx, flexvol, aggregate, size, spaceReserve, snapshotPolicy, unixPermissions,
 		exportPolicy, securityStyle, tieringPolicy, "", api.QosPolicyGroup{}, enableEncryption,
-		snapshotReserveInt)
+		snapshotReserveInt, false)
 	if err = api.GetError(ctx, createResponse, err); err != nil {
 		return "", fmt.Errorf("error creating Flexvol: %v", err)
 	}
@@ -1256,6 +1907,75 @@ func (d *NASQtreeStorageDriver) reapDeletedQtrees(ctx context.Context) {
 	}
 }
 
+// pruneQtreeSnapshots is called periodically by a background task.  Flexvol snapshots holding qtree
+// snapshots that Trident no longer needs are deleted once they are no longer busy.  These are the
+// temporary snapshots taken to clone qtrees, and the snapshots of qtrees that no longer exist in their
+// Flexvol, such as those of deleted qtrees or those a cloned Flexvol inherited from its parent.
+func (d *NASQtreeStorageDriver) pruneQtreeSnapshots(ctx context.Context) {
+
+	// Ensure we don't prune any snapshot that is involved in a qtree clone workflow
+	utils.Lock(ctx, "pruneSnapshots", d.sharedLockID)
+	defer utils.Unlock(ctx, "pruneSnapshots", d.sharedLockID)
+
+	Logc(ctx).Debug("Housekeeping, checking for unneeded qtree snapshots.")
+
+	volumeListResponse, err := d.API.VolumeList(d.FlexvolNamePrefix())
+	if err = api.GetError(ctx, volumeListResponse, err); err != nil {
+		Logc(ctx).WithField("error", err).Error("Could not list Flexvols.")
+		return
+	}
+	if volumeListResponse.Result.AttributesListPtr == nil {
+		return
+	}
+
+	for _, volAttrs := range volumeListResponse.Result.AttributesListPtr.VolumeAttributesPtr {
+		volIDAttrs := volAttrs.VolumeIdAttributes()
+		flexvol := volIDAttrs.Name()
+
+		qtreeListResponse, err := d.API.QtreeList("", flexvol)
+		if err = api.GetError(ctx, qtreeListResponse, err); err != nil {
+			Logc(ctx).WithFields(log.Fields{"flexvol": flexvol, "error": err}).Warning("Could not list qtrees.")
+			continue
+		}
+		qtrees := make(map[string]bool)
+		if qtreeListResponse.Result.AttributesListPtr != nil {
+			for _, qtree := range qtreeListResponse.Result.AttributesListPtr.QtreeInfoPtr {
+				if qtree.Volume() == flexvol {
+					qtrees[qtree.Qtree()] = true
+				}
+			}
+		}
+
+		snapListResponse, err := d.API.SnapshotList(flexvol)
+		if err = api.GetError(ctx, snapListResponse, err); err != nil {
+			Logc(ctx).WithFields(log.Fields{"flexvol": flexvol, "error": err}).Warning("Could not list snapshots.")
+			continue
+		}
+		if snapListResponse.Result.AttributesListPtr == nil {
+			continue
+		}
+
+		for _, snap := range snapListResponse.Result.AttributesListPtr.SnapshotInfoPtr {
+			qtree, snapshot, ok := parseQtreeSnapshotName(snap.Name())
+			if !ok || snap.Busy() {
+				continue
+			}
+
+			// Only remove the snapshots of missing qtrees if the qtrees were managed by Trident
+			temporary := strings.HasPrefix(snapshot, qtreeCloneSnapshotPrefix)
+			orphaned := !qtrees[qtree] && strings.HasPrefix(qtree, *d.Config.StoragePrefix)
+
+			if temporary || orphaned {
+				Logc(ctx).WithFields(log.Fields{
+					"flexvol":  flexvol,
+					"snapshot": snap.Name(),
+				}).Debug("Housekeeping, deleting unneeded qtree snapshot.")
+				d.deleteQtreeFlexvolSnapshot(ctx, snap.Name(), flexvol)
+			}
+		}
+	}
+}
+
 // ensureDefaultExportPolicy checks for an export policy with a well-known name that will be suitable
 // for setting on a Flexvol and will enable access to all qtrees therein.  If the policy exists, the
 // method assumes it created the policy itself and that all is good.  If the policy does not exist,
@@ -1317,12 +2037,20 @@ func (d *NASQtreeStorageDriver) GetStorageBackendPhysicalPoolNames(context.Conte
 	return getStorageBackendPhysicalPoolNamesCommon(d.physicalPools)
 }
 
+// GetCapacity returns the capacity of the aggregates that could host a volume with the specified attributes
+// in the specified pool
+func (d *NASQtreeStorageDriver) GetCapacity(
+	ctx context.Context, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+	return getPoolCapacityCommon(ctx, d, storagePool, volAttributes, d.physicalPools, d.virtualPools)
+}
+
 func (d *NASQtreeStorageDriver) getStoragePoolAttributes() map[string]sa.Offer {
 
 	return map[string]sa.Offer{
 		sa.BackendType:      sa.NewStringOffer(d.Name()),
-		sa.Snapshots:        sa.NewBoolOffer(false),
-		sa.Clones:           sa.NewBoolOffer(false),
+		sa.Snapshots:        sa.NewBoolOffer(true),
+		sa.Clones:           sa.NewBoolOffer(true),
 		sa.Encryption:       sa.NewBoolOffer(true),
 		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
 	}
@@ -1372,7 +2100,7 @@ func (d *NASQtreeStorageDriver) CreatePrepare(ctx context.Context, volConfig *st
 func (d *NASQtreeStorageDriver) CreateFollowup(ctx context.Context, volConfig *storage.VolumeConfig) error {
 
 	// Determine which Flexvol contains the qtree
-	exists, flexvol, err := d.API.QtreeExists(ctx, volConfig.InternalName, d.FlexvolNamePrefix())
+	exists, flexvol, err := d.qtreeExists(ctx, volConfig.InternalName)
 	if err != nil {
 		return fmt.Errorf("could not determine if qtree %s exists: %v", volConfig.InternalName, err)
 	}
@@ -1404,9 +2132,17 @@ func (d *NASQtreeStorageDriver) GetExternalConfig(ctx context.Context) interface
 // GetVolumeExternal queries the storage backend for all relevant info about
 // a single container volume managed by this driver and returns a VolumeExternal
 // representation of the volume.
-func (d *NASQtreeStorageDriver) GetVolumeExternal(_ context.Context, name string) (*storage.VolumeExternal, error) {
+func (d *NASQtreeStorageDriver) GetVolumeExternal(ctx context.Context, name string) (*storage.VolumeExternal, error) {
 
-	qtree, err := d.API.QtreeGet(name, d.FlexvolNamePrefix())
+	exists, qtreeName, flexvol, err := d.findQtree(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("qtree %s not found", name)
+	}
+
+	qtree, err := d.API.QtreeGet(qtreeName, flexvol)
 	if err != nil {
 		return nil, err
 	}
@@ -1436,6 +2172,9 @@ func (d *NASQtreeStorageDriver) GetVolumeExternalWrappers(
 	// Let the caller know we're done by closing the channel
 	defer close(channel)
 
+	// List any qtrees imported from other Flexvols once those in this driver's Flexvols are listed
+	defer d.getImportedVolumeExternals(ctx, channel)
+
 	// Get all volumes matching the storage prefix
 	volumesResponse, err := d.API.VolumeGetAll(d.FlexvolNamePrefix())
 	if err = api.GetError(ctx, volumesResponse, err); err != nil {
@@ -1522,6 +2261,43 @@ func (d *NASQtreeStorageDriver) GetVolumeExternalWrappers(
 	}
 }
 
+// getImportedVolumeExternals writes a VolumeExternal representation of each qtree that Trident manages
+// in a Flexvol not created by this driver to the supplied channel.  Such qtrees were imported, which
+// renamed them, so they are found by the storage prefix of their names.
+func (d *NASQtreeStorageDriver) getImportedVolumeExternals(
+	ctx context.Context, channel chan *storage.VolumeExternalWrapper,
+) {
+
+	// Without a storage prefix, Trident's qtrees cannot be told apart from any others
+	if *d.Config.StoragePrefix == "" {
+		return
+	}
+
+	listResponse, err := d.API.QtreeList(*d.Config.StoragePrefix, "")
+	if err = api.GetError(ctx, listResponse, err); err != nil {
+		channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
+		return
+	}
+	if listResponse.Result.AttributesListPtr == nil {
+		return
+	}
+
+	for _, qtree := range listResponse.Result.AttributesListPtr.QtreeInfoPtr {
+
+		if qtree.Qtree() == "" || strings.HasPrefix(qtree.Volume(), d.FlexvolNamePrefix()) {
+			continue
+		}
+
+		volume, err := d.GetVolumeExternal(ctx, qtree.Qtree())
+		if err != nil {
+			Logc(ctx).WithField("qtree", qtree.Qtree()).WithError(err).Warning("Could not get imported qtree.")
+			continue
+		}
+
+		channel <- &storage.VolumeExternalWrapper{Volume: volume, Error: nil}
+	}
+}
+
 // getVolumeExternal is a private method that accepts info about a volume
 // as returned by the storage backend and formats it as a VolumeExternal
 // object.
@@ -1733,6 +2509,24 @@ func NewResizeTask(ctx context.Context, d *NASQtreeStorageDriver, tasks []func(c
 	return task
 }
 
+func NewSnapshotTask(ctx context.Context, d *NASQtreeStorageDriver, tasks []func(context.Context)) *HousekeepingTask {
+
+	Logc(ctx).WithFields(log.Fields{
+		"IntervalSeconds": defaultPruneSnapshotsPeriodSecs,
+	}).Debug("Configured qtree snapshot pruning period.")
+
+	task := &HousekeepingTask{
+		Name:         snapshotTask,
+		Ticker:       time.NewTicker(time.Duration(defaultPruneSnapshotsPeriodSecs) * time.Second),
+		InitialDelay: HousekeepingStartupDelaySecs * time.Second,
+		Done:         make(chan struct{}),
+		Tasks:        tasks,
+		Driver:       d,
+	}
+
+	return task
+}
+
 // Resize expands the Flexvol containing the Qtree and updates the Qtree quota.
 func (d *NASQtreeStorageDriver) Resize(ctx context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
 
@@ -1756,7 +2550,7 @@ func (d *NASQtreeStorageDriver) Resize(ctx context.Context, volConfig *storage.V
 	resizeError := errors.New("storage driver failed to resize the volume")
 
 	// Check that volume exists
-	exists, flexvol, err := d.API.QtreeExists(ctx, name, d.FlexvolNamePrefix())
+	exists, flexvol, err := d.qtreeExists(ctx, name)
 	if err != nil {
 		Logc(ctx).WithField("error", err).Error("Error checking for existing volume.")
 		return resizeError
@@ -1811,6 +2605,59 @@ func (d *NASQtreeStorageDriver) Resize(ctx context.Context, volConfig *storage.V
 	return nil
 }
 
+// Modify changes the attributes of an existing volume.  Attributes of the Flexvol that contains the
+// qtree cannot be changed, as they are shared with other volumes.
+func (d *NASQtreeStorageDriver) Modify(
+	ctx context.Context, volConfig *storage.VolumeConfig, modification *storage.VolumeModification,
+) error {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Modify",
+			"Type":         "NASQtreeStorageDriver",
+			"name":         name,
+			"modification": modification,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Modify")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Modify")
+	}
+
+	if err := validateVolumeModification(ctx, d, modification, "exportPolicy", "unixPermissions"); err != nil {
+		return err
+	}
+
+	// Trident owns the export policies of volumes on backends that manage export policies automatically
+	if modification.ExportPolicy != "" && d.Config.AutoExportPolicy {
+		return utils.UnsupportedError(fmt.Sprintf(
+			"cannot modify the export policy of volume %s because the backend manages export policies", name))
+	}
+
+	exists, flexvol, err := d.qtreeExists(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing qtree: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("volume %s does not exist", name)
+	}
+
+	if modification.ExportPolicy != "" {
+		response, err := d.API.QtreeModifyExportPolicy(name, flexvol, modification.ExportPolicy)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error modifying export policy of qtree %s: %v", name, err)
+		}
+	}
+
+	if modification.UnixPermissions != "" {
+		response, err := d.API.QtreeModifyUnixPermissions(name, flexvol, modification.UnixPermissions)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error modifying unix permissions of qtree %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
 // resizeFlexvol grows or shrinks the Flexvol to an optimal size if possible. Otherwise
 // the Flexvol is expanded by the value of sizeBytes
 func (d *NASQtreeStorageDriver) resizeFlexvol(ctx context.Context, flexvol string, sizeBytes uint64) error {
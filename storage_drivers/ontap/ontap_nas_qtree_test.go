@@ -3,13 +3,20 @@
 package ontap
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
 	drivers "github.com/netapp/trident/storage_drivers"
 	"github.com/netapp/trident/storage_drivers/ontap/api"
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+	"github.com/netapp/trident/utils"
 )
 
 func newNASQtreeStorageDriver() *NASQtreeStorageDriver {
@@ -95,3 +102,400 @@ func TestOntapNasQtreeStorageDriverConfigString(t *testing.T) {
 		}
 	}
 }
+
+func TestOntapNasQtreeStorageDriverParseQtreePath(t *testing.T) {
+
+	driver := newNASQtreeStorageDriver()
+	driver.flexvolNamePrefix = "trident_qtree_pool_test_"
+
+	tests := []struct {
+		path    string
+		flexvol string
+		qtree   string
+		valid   bool
+	}{
+		{"trident_qtree_pool_test_ABCDEFGHIJ/myQtree", "trident_qtree_pool_test_ABCDEFGHIJ", "myQtree", true},
+		{"/vol/trident_qtree_pool_test_ABCDEFGHIJ/myQtree", "trident_qtree_pool_test_ABCDEFGHIJ", "myQtree", true},
+		{"otherVolume/myQtree", "otherVolume", "myQtree", true},
+		{"trident_qtree_pool_test_ABCDEFGHIJ", "", "", false},
+		{"trident_qtree_pool_test_ABCDEFGHIJ/", "", "", false},
+		{"trident_qtree_pool_test_ABCDEFGHIJ/myQtree/dir", "", "", false},
+	}
+
+	for _, test := range tests {
+		flexvol, qtree, err := driver.parseQtreePath(test.path)
+		if test.valid {
+			assert.NoError(t, err, "expected path %s to be valid", test.path)
+			assert.Equal(t, test.flexvol, flexvol)
+			assert.Equal(t, test.qtree, qtree)
+		} else {
+			assert.Error(t, err, "expected path %s to be invalid", test.path)
+		}
+	}
+}
+
+func TestOntapNasQtreeStorageDriverGetQtreeSnapshotName(t *testing.T) {
+
+	assert.Equal(t, "test_pvc_1234__snapshot-5678", getQtreeSnapshotName("test_pvc_1234", "snapshot-5678"))
+
+	// Snapshots of a qtree whose name is a prefix of another qtree's name must not be confused
+	assert.NotContains(t, getQtreeSnapshotName("test_pvc_12_a", "snap"), getQtreeSnapshotName("test_pvc_12", ""))
+}
+
+func TestOntapNasQtreeStorageDriverParseQtreeSnapshotName(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		qtree    string
+		snapshot string
+		ok       bool
+	}{
+		{"test_pvc_1234__snapshot-5678", "test_pvc_1234", "snapshot-5678", true},
+		{"my__prefix_pvc_1234__snapshot-5678", "my__prefix_pvc_1234", "snapshot-5678", true},
+		{"test_pvc_1234__", "", "", false},
+		{"__snapshot-5678", "", "", false},
+		{"hourly.0", "", "", false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		qtree, snapshot, ok := parseQtreeSnapshotName(test.name)
+		assert.Equal(t, test.ok, ok)
+		assert.Equal(t, test.qtree, qtree)
+		assert.Equal(t, test.snapshot, snapshot)
+	}
+
+	// Names are parsed back into the qtree and snapshot they were made from
+	qtree, snapshot, ok := parseQtreeSnapshotName(getQtreeSnapshotName("trident__pvc_1", "snap"))
+	assert.True(t, ok)
+	assert.Equal(t, "trident__pvc_1", qtree)
+	assert.Equal(t, "snap", snapshot)
+}
+
+func TestOntapNasQtreeStorageDriverCanSnapshot(t *testing.T) {
+
+	driver := newNASQtreeStorageDriver()
+
+	assert.NoError(t, driver.CanSnapshot(context.Background(), &storage.SnapshotConfig{Name: "snapshot-5678"}))
+
+	err := driver.CanSnapshot(context.Background(), &storage.SnapshotConfig{Name: "snap__1"})
+	assert.True(t, utils.IsInvalidInputError(err), "expected an invalid input error")
+}
+
+// newTestNASQtreeDriverWithVserver returns an ontap-nas-economy driver connected to a fake vserver that
+// sends the responses supplied by the responder.
+func newTestNASQtreeDriverWithVserver(t *testing.T, responder testZAPIResponder) *NASQtreeStorageDriver {
+
+	driver := newNASQtreeStorageDriver()
+	driver.API, driver.Config.ManagementLIF = newTestClientWithVserver(t, responder)
+	driver.flexvolNamePrefix = "trident_qtree_pool_test_"
+	driver.quotaResizeMap = make(map[string]bool)
+
+	return driver
+}
+
+// testSnapshotGetIterResults returns the results of a snapshot-get-iter request listing the specified
+// snapshots of a Flexvol, of which those named in busy are reported as busy.
+func testSnapshotGetIterResults(flexvol string, snapshots []string, busy ...string) string {
+
+	snapshotInfos := ""
+	for _, snapshot := range snapshots {
+		snapshotInfos += fmt.Sprintf("<snapshot-info><name>%s</name><volume>%s</volume><busy>%v</busy>"+
+			"<access-time>1600000000</access-time></snapshot-info>", snapshot, flexvol,
+			utils.StringInSlice(snapshot, busy))
+	}
+
+	return fmt.Sprintf(`<results status="passed"><attributes-list>%s</attributes-list>`+
+		`<num-records>%d</num-records></results>`, snapshotInfos, len(snapshots))
+}
+
+// testQtreeListIterResults returns the results of a qtree-list-iter request listing the specified
+// qtrees of a Flexvol.
+func testQtreeListIterResults(flexvol string, qtrees ...string) string {
+
+	qtreeInfos := ""
+	for _, qtree := range qtrees {
+		qtreeInfos += fmt.Sprintf("<qtree-info><volume>%s</volume><qtree>%s</qtree></qtree-info>", flexvol, qtree)
+	}
+
+	return fmt.Sprintf(`<results status="passed"><attributes-list>%s</attributes-list>`+
+		`<num-records>%d</num-records></results>`, qtreeInfos, len(qtrees))
+}
+
+// testSnapshotDeleteRecorder returns a responder for snapshot-delete requests that records the deleted
+// snapshots, and reports the snapshots named in busy as busy.
+func testSnapshotDeleteRecorder(mutex *sync.Mutex, deleted *[]string, busy ...string) testZAPIResponder {
+
+	return func(zapiRequestXMLTagName, zapiRequest string) string {
+
+		var request azgo.SnapshotDeleteRequest
+		if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+			return `<results status="failed" reason="Invalid Input" errno="13115"/>`
+		}
+		if utils.StringInSlice(request.Snapshot(), busy) {
+			return `<results status="failed" reason="Snapshot is busy" errno="13024"/>`
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		*deleted = append(*deleted, request.Volume()+"/"+request.Snapshot())
+
+		return `<results status="passed"/>`
+	}
+}
+
+func TestOntapNasQtreeStorageDriverCreateCloneDeletesTemporarySnapshot(t *testing.T) {
+
+	flexvol := "trident_qtree_pool_test_ABCDEFGHIJ"
+
+	tests := []struct {
+		name     string
+		snapshot string
+		busy     bool
+	}{
+		{"temporary snapshot", "", false},
+		{"busy temporary snapshot", "", true},
+		{"existing snapshot", "snapshot-1234", false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		var mutex sync.Mutex
+		var created, deleted []string
+		quotaStatus := "on"
+
+		responder := func(zapiRequestXMLTagName, zapiRequest string) string {
+			switch zapiRequestXMLTagName {
+			case "qtree-list-iter":
+				var request azgo.QtreeListIterRequest
+				if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+					return ""
+				}
+				query := request.Query()
+				info := query.QtreeInfo()
+				if info.Qtree() == "test_source" {
+					return testQtreeListIterResults(flexvol, "test_source")
+				}
+				return testQtreeListIterResults(flexvol)
+			case "snapshot-create":
+				var request azgo.SnapshotCreateRequest
+				if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+					return ""
+				}
+				mutex.Lock()
+				created = append(created, request.Volume()+"/"+request.Snapshot())
+				mutex.Unlock()
+			case "snapshot-delete":
+				var busy []string
+				mutex.Lock()
+				if test.busy {
+					for _, snapshot := range created {
+						busy = append(busy, strings.TrimPrefix(snapshot, flexvol+"/"))
+					}
+				}
+				mutex.Unlock()
+				return testSnapshotDeleteRecorder(&mutex, &deleted, busy...)(zapiRequestXMLTagName, zapiRequest)
+			case "quota-list-entries-iter":
+				return `<results status="passed"><attributes-list><quota-entry><disk-limit>1048576</disk-limit>` +
+					`</quota-entry></attributes-list><num-records>1</num-records></results>`
+			case "quota-status":
+				mutex.Lock()
+				defer mutex.Unlock()
+				return fmt.Sprintf(`<results status="passed"><status>%s</status></results>`, quotaStatus)
+			case "quota-off", "quota-on":
+				mutex.Lock()
+				defer mutex.Unlock()
+				quotaStatus = strings.TrimPrefix(zapiRequestXMLTagName, "quota-")
+			}
+			return ""
+		}
+
+		driver := newTestNASQtreeDriverWithVserver(t, responder)
+
+		volConfig := &storage.VolumeConfig{
+			InternalName:              "test_clone",
+			CloneSourceVolumeInternal: "test_source",
+			CloneSourceSnapshot:       test.snapshot,
+		}
+
+		assert.NoError(t, driver.CreateClone(context.Background(), volConfig, nil), test.name)
+
+		mutex.Lock()
+		if test.snapshot != "" {
+			assert.Empty(t, created, test.name)
+			assert.Empty(t, deleted, test.name)
+		} else if assert.Len(t, created, 1, test.name) {
+			assert.True(t, strings.HasPrefix(created[0], flexvol+"/test_source__"+qtreeCloneSnapshotPrefix), test.name)
+			if test.busy {
+				assert.Empty(t, deleted, test.name)
+			} else {
+				assert.Equal(t, created, deleted, test.name)
+			}
+		}
+		mutex.Unlock()
+	}
+}
+
+func TestOntapNasQtreeStorageDriverDestroyDeletesSnapshots(t *testing.T) {
+
+	flexvol := "trident_qtree_pool_test_ABCDEFGHIJ"
+
+	var mutex sync.Mutex
+	var deleted []string
+
+	responder := func(zapiRequestXMLTagName, zapiRequest string) string {
+		switch zapiRequestXMLTagName {
+		case "qtree-list-iter":
+			return testQtreeListIterResults(flexvol, "test_vol")
+		case "snapshot-get-iter":
+			return testSnapshotGetIterResults(flexvol, []string{
+				"test_vol__snapshot-1", "test_vol__snapshot-2", "test_vol_2__snapshot-3", "hourly.0",
+			}, "test_vol__snapshot-2")
+		case "snapshot-delete":
+			return testSnapshotDeleteRecorder(&mutex, &deleted, "test_vol__snapshot-2")(
+				zapiRequestXMLTagName, zapiRequest)
+		}
+		return ""
+	}
+
+	driver := newTestNASQtreeDriverWithVserver(t, responder)
+
+	assert.NoError(t, driver.Destroy(context.Background(), "test_vol"))
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, []string{flexvol + "/test_vol__snapshot-1"}, deleted)
+}
+
+func TestOntapNasQtreeStorageDriverPruneQtreeSnapshots(t *testing.T) {
+
+	flexvol := "trident_qtree_pool_test_ABCDEFGHIJ"
+
+	var mutex sync.Mutex
+	var deleted []string
+
+	responder := func(zapiRequestXMLTagName, zapiRequest string) string {
+		switch zapiRequestXMLTagName {
+		case "volume-get-iter":
+			return fmt.Sprintf(`<results status="passed"><attributes-list><volume-attributes>`+
+				`<volume-id-attributes><name>%s</name></volume-id-attributes></volume-attributes>`+
+				`</attributes-list><num-records>1</num-records></results>`, flexvol)
+		case "qtree-list-iter":
+			return testQtreeListIterResults(flexvol, "", "test_vol", "test__vol")
+		case "snapshot-get-iter":
+			return testSnapshotGetIterResults(flexvol, []string{
+				"test_vol__snapshot-1",
+				"test__vol__snapshot-4",
+				"test_vol__clone-20210501T101010Z",
+				"test_vol__clone-20210501T202020Z",
+				"test_gone__snapshot-2",
+				"other_vol__snapshot-3",
+				"hourly.0",
+			}, "test_vol__clone-20210501T202020Z")
+		case "snapshot-delete":
+			return testSnapshotDeleteRecorder(&mutex, &deleted)(zapiRequestXMLTagName, zapiRequest)
+		}
+		return ""
+	}
+
+	driver := newTestNASQtreeDriverWithVserver(t, responder)
+
+	driver.pruneQtreeSnapshots(context.Background())
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.ElementsMatch(t, []string{
+		flexvol + "/test_vol__clone-20210501T101010Z",
+		flexvol + "/test_gone__snapshot-2",
+	}, deleted)
+}
+
+func TestOntapNasQtreeStorageDriverImportFromUnprefixedFlexvol(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		aggregate  string
+		notManaged bool
+		valid      bool
+	}{
+		{"managed", "data", false, true},
+		{"not managed", "data", true, true},
+		{"unavailable aggregate", "other", false, false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		var mutex sync.Mutex
+		var renamed, quotas []string
+
+		responder := func(zapiRequestXMLTagName, zapiRequest string) string {
+			switch zapiRequestXMLTagName {
+			case "volume-get-iter":
+				return fmt.Sprintf(`<results status="passed"><attributes-list><volume-attributes>`+
+					`<volume-id-attributes><name>projects</name><containing-aggregate-name>%s`+
+					`</containing-aggregate-name></volume-id-attributes></volume-attributes>`+
+					`</attributes-list><num-records>1</num-records></results>`, test.aggregate)
+			case "qtree-list-iter":
+				var request azgo.QtreeListIterRequest
+				if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+					return ""
+				}
+				query := request.Query()
+				info := query.QtreeInfo()
+				if strings.HasPrefix(info.Volume(), "trident_qtree_pool_test_") {
+					return testQtreeListIterResults("")
+				}
+				return testQtreeListIterResults("projects", "myQtree")
+			case "quota-list-entries-iter":
+				return `<results status="passed"><attributes-list><quota-entry><disk-limit>1048576</disk-limit>` +
+					`</quota-entry></attributes-list><num-records>1</num-records></results>`
+			case "qtree-rename":
+				var request azgo.QtreeRenameRequest
+				if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+					return ""
+				}
+				mutex.Lock()
+				renamed = append(renamed, request.Qtree()+" -> "+request.NewQtreeName())
+				mutex.Unlock()
+				return `<results status="passed"/>`
+			case "quota-set-entry":
+				var request azgo.QuotaSetEntryRequest
+				if err := testUnmarshalZAPIRequest(zapiRequest, &request); err != nil {
+					return ""
+				}
+				mutex.Lock()
+				quotas = append(quotas, request.QuotaTarget())
+				mutex.Unlock()
+				return `<results status="passed"/>`
+			}
+			return ""
+		}
+
+		driver := newTestNASQtreeDriverWithVserver(t, responder)
+		driver.physicalPools = map[string]*storage.Pool{"data": storage.NewStoragePool(nil, "data")}
+
+		volConfig := &storage.VolumeConfig{InternalName: "test_imported", ImportNotManaged: test.notManaged}
+
+		err := driver.Import(context.Background(), volConfig, "projects/myQtree")
+		if !test.valid {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, "1073741824", volConfig.Size, test.name)
+
+		mutex.Lock()
+		if test.notManaged {
+			assert.Equal(t, "myQtree", volConfig.InternalName, test.name)
+			assert.Empty(t, renamed, test.name)
+			assert.Empty(t, quotas, test.name)
+		} else {
+			assert.Equal(t, []string{"/vol/projects/myQtree -> /vol/projects/test_imported"}, renamed, test.name)
+			assert.Equal(t, []string{"/vol/projects/test_imported"}, quotas, test.name)
+		}
+		mutex.Unlock()
+	}
+}
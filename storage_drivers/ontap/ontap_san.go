@@ -31,7 +31,7 @@ type SANStorageDriver struct {
 	initialized bool
 	Config      drivers.OntapStorageDriverConfig
 	ips         []string
-	API         *api.Client
+	API         api.OntapAPI
 	Telemetry   *Telemetry
 
 	physicalPools map[string]*storage.Pool
@@ -42,7 +42,7 @@ func (d *SANStorageDriver) GetConfig() *drivers.OntapStorageDriverConfig {
 	return &d.Config
 }
 
-func (d *SANStorageDriver) GetAPI() *api.Client {
+func (d *SANStorageDriver) GetAPI() api.OntapAPI {
 	return d.API
 }
 
@@ -91,15 +91,22 @@ func (d *SANStorageDriver) Initialize(
 	}
 	d.Config = *config
 
-	d.ips, err = d.API.NetInterfaceGetDataLIFs(ctx, "iscsi")
-	if err != nil {
-		return err
-	}
-
-	if len(d.ips) == 0 {
-		return fmt.Errorf("no iSCSI data LIFs found on SVM %s", config.SVM)
+	if d.usesNVMe() {
+		d.ips, err = d.getNVMeDataLIFs(ctx)
+		if err != nil {
+			return fmt.Errorf("error initializing %s driver: %v", d.Name(), err)
+		}
 	} else {
-		Logc(ctx).WithField("dataLIFs", d.ips).Debug("Found iSCSI LIFs.")
+		d.ips, err = d.API.NetInterfaceGetDataLIFs(ctx, "iscsi")
+		if err != nil {
+			return err
+		}
+
+		if len(d.ips) == 0 {
+			return fmt.Errorf("no iSCSI data LIFs found on SVM %s", config.SVM)
+		} else {
+			Logc(ctx).WithField("dataLIFs", d.ips).Debug("Found iSCSI LIFs.")
+		}
 	}
 
 	d.physicalPools, d.virtualPools, err = InitializeStoragePoolsCommon(ctx, d, d.getStoragePoolAttributes(),
@@ -108,14 +115,21 @@ func (d *SANStorageDriver) Initialize(
 		return fmt.Errorf("could not configure storage pools: %v", err)
 	}
 
-	err = InitializeSANDriver(ctx, driverContext, d.API, &d.Config, d.validate, backendUUID)
+	if d.usesNVMe() {
+		// Namespaces are published via per-volume subsystems, so there is no igroup to set up
+		if err = d.validate(ctx); err != nil {
+			return fmt.Errorf("error initializing %s driver: %v", d.Name(), err)
+		}
+	} else {
+		err = InitializeSANDriver(ctx, driverContext, d.API, &d.Config, d.validate, backendUUID)
 
-	// clean up igroup for failed driver
-	if err != nil {
-		if d.Config.DriverContext == tridentconfig.ContextCSI {
-			cleanIgroups(ctx, d.API, d.Config.IgroupName)
+		// clean up igroup for failed driver
+		if err != nil {
+			if d.Config.DriverContext == tridentconfig.ContextCSI {
+				cleanIgroups(ctx, d.API, d.Config.IgroupName)
+			}
+			return fmt.Errorf("error initializing %s driver: %v", d.Name(), err)
 		}
-		return fmt.Errorf("error initializing %s driver: %v", d.Name(), err)
 	}
 
 	// Set up the autosupport heartbeat
@@ -138,7 +152,7 @@ func (d *SANStorageDriver) Terminate(ctx context.Context, _ string) {
 		defer Logc(ctx).WithFields(fields).Debug("<<<< Terminate")
 	}
 
-	if d.Config.DriverContext == tridentconfig.ContextCSI {
+	if d.Config.DriverContext == tridentconfig.ContextCSI && !d.usesNVMe() {
 		// clean up igroup for terminated driver
 		cleanIgroups(ctx, d.API, d.Config.IgroupName)
 	}
@@ -204,7 +218,7 @@ func (d *SANStorageDriver) Create(
 	}
 
 	// Get candidate physical pools
-	physicalPools, err := getPoolsForCreate(ctx, volConfig, storagePool, volAttributes, d.physicalPools, d.virtualPools)
+	physicalPools, err := getPoolsForCreate(ctx, d, volConfig, storagePool, volAttributes, d.physicalPools, d.virtualPools)
 	if err != nil {
 		return err
 	}
@@ -315,10 +329,17 @@ func (d *SANStorageDriver) Create(
 			return err
 		}
 
+		// Namespaces have no QoS policy of their own, so QoS policy is set at the Flexvol layer
+		volumeQosPolicyGroup := api.QosPolicyGroup{}
+		if d.usesNVMe() {
+			volumeQosPolicyGroup = qosPolicyGroup
+		}
+
 		// Create the volume
 		volCreateResponse, err := d.API.VolumeCreate(
 			ctx, name, aggregate, size, spaceReserve, snapshotPolicy, unixPermissions, exportPolicy, securityStyle,
-			tieringPolicy, labels, api.QosPolicyGroup{}, enableEncryption, snapshotReserveInt)
+			tieringPolicy, labels, volumeQosPolicyGroup, enableEncryption, snapshotReserveInt,
+			volConfig.IsMirrorDestination)
 
 		if err = api.GetError(ctx, volCreateResponse, err); err != nil {
 			if zerr, ok := err.(api.ZapiError); ok {
@@ -339,6 +360,32 @@ func (d *SANStorageDriver) Create(
 			continue
 		}
 
+		// The LUN in a mirror destination is replicated from the source volume once the mirror is initialized
+		if volConfig.IsMirrorDestination {
+			return nil
+		}
+
+		// Create the namespace.  If this fails, clean up and move on to the next pool.
+		if d.usesNVMe() {
+			if err = d.createNVMeNamespace(ctx, name, sizeBytes, fstype); err != nil {
+				errMessage := fmt.Sprintf("ONTAP-SAN pool %s/%s; error creating namespace %s: %v",
+					storagePool.Name, aggregate, name, err)
+				Logc(ctx).Error(errMessage)
+				createErrors = append(createErrors, fmt.Errorf(errMessage))
+
+				// Don't leave the new Flexvol around
+				if _, err := d.API.VolumeDestroy(name, true); err != nil {
+					Logc(ctx).WithField("volume", name).Errorf("Could not clean up volume; %v", err)
+				} else {
+					Logc(ctx).WithField("volume", name).Debugf("Cleaned up volume after namespace create error.")
+				}
+
+				// Move on to the next pool
+				continue
+			}
+			return nil
+		}
+
 		lunPath := lunPath(name)
 		osType := "linux"
 
@@ -456,14 +503,6 @@ func (d *SANStorageDriver) CreateClone(
 		return err
 	}
 
-	// How "splitOnClone" value gets set:
-	// In the Core we first check clone's VolumeConfig for splitOnClone value
-	// If it is not set then (again in Core) we check source PV's VolumeConfig for splitOnClone value
-	// If we still don't have splitOnClone value then HERE we check for value in the source PV's Storage/Virtual Pool
-	// If the value for "splitOnClone" is still empty then HERE we set it to backend config's SplitOnClone value
-
-	// Attempt to get splitOnClone value based on storagePool (source Volume's StoragePool)
-	var storagePoolSplitOnCloneVal string
 	labels := ""
 	if storage.IsStoragePoolUnset(storagePool) {
 		// Set the base label
@@ -478,8 +517,6 @@ func (d *SANStorageDriver) CreateClone(
 		}
 
 	} else {
-		storagePoolSplitOnCloneVal = storagePool.InternalAttributes[SplitOnClone]
-
 		// Ensure the volume exists
 		flexvol, err := d.API.VolumeGet(volConfig.CloneSourceVolumeInternal)
 		if err != nil {
@@ -495,14 +532,9 @@ func (d *SANStorageDriver) CreateClone(
 		}
 	}
 
-	// If storagePoolSplitOnCloneVal is still unknown, set it to backend's default value
-	if storagePoolSplitOnCloneVal == "" {
-		storagePoolSplitOnCloneVal = d.Config.SplitOnClone
-	}
-
-	split, err := strconv.ParseBool(utils.GetV(opts, "splitOnClone", storagePoolSplitOnCloneVal))
+	split, err := getCloneSplit(opts, storagePool, &d.Config)
 	if err != nil {
-		return fmt.Errorf("invalid boolean value for splitOnClone: %v", err)
+		return err
 	}
 
 	qosPolicy := utils.GetV(opts, "qosPolicy", "")
@@ -512,6 +544,13 @@ func (d *SANStorageDriver) CreateClone(
 		return err
 	}
 
+	// The clone's namespace has no QoS policy of its own, so QoS policy is set on the clone Flexvol
+	if d.usesNVMe() {
+		Logc(ctx).WithField("splitOnClone", split).Debug("Creating volume clone.")
+		return CreateOntapClone(ctx, name, source, snapshot, labels, split, &d.Config, d.API, false,
+			qosPolicyGroup)
+	}
+
 	Logc(ctx).WithField("splitOnClone", split).Debug("Creating volume clone.")
 	if err := CreateOntapClone(ctx, name, source, snapshot, labels, split, &d.Config, d.API, false,
 		api.QosPolicyGroup{}); err != nil {
@@ -528,6 +567,35 @@ func (d *SANStorageDriver) CreateClone(
 	return nil
 }
 
+// SplitClone starts splitting a clone from its source volume if called for
+func (d *SANStorageDriver) SplitClone(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool *storage.Pool,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":      "SplitClone",
+			"Type":        "SANStorageDriver",
+			"name":        volConfig.InternalName,
+			"storagePool": storagePool,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> SplitClone")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< SplitClone")
+	}
+
+	opts, err := d.GetVolumeOpts(ctx, volConfig, make(map[string]sa.Request))
+	if err != nil {
+		return err
+	}
+
+	split, err := getCloneSplit(opts, storagePool, &d.Config)
+	if err != nil || !split {
+		return err
+	}
+
+	return SplitOntapClone(ctx, volConfig.InternalName, &d.Config, d.API)
+}
+
 func (d *SANStorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
 
 	if d.Config.DebugTraceFlags["method"] {
@@ -542,6 +610,10 @@ func (d *SANStorageDriver) Import(ctx context.Context, volConfig *storage.Volume
 		defer Logc(ctx).WithFields(fields).Debug("<<<< Import")
 	}
 
+	if d.usesNVMe() {
+		return fmt.Errorf("volume import is not supported with SAN type %s", d.Config.SANType)
+	}
+
 	// Ensure the volume exists
 	flexvol, err := d.API.VolumeGet(originalName)
 	if err != nil {
@@ -705,6 +777,13 @@ func (d *SANStorageDriver) Destroy(ctx context.Context, name string) error {
 		}
 	}
 
+	// A namespace can't be deleted while it is mapped, so remove any subsystem left over from publishing it
+	if d.usesNVMe() {
+		if err = d.destroyNVMeSubsystem(ctx, name); err != nil {
+			return err
+		}
+	}
+
 	// Delete the Flexvol & LUN
 	volDestroyResponse, err := d.API.VolumeDestroy(name, true)
 	if err != nil {
@@ -741,6 +820,13 @@ func (d *SANStorageDriver) Publish(
 		defer Logc(ctx).WithFields(fields).Debug("<<<< Publish")
 	}
 
+	if d.usesNVMe() {
+		if err := d.publishNVMeNamespace(ctx, name, publishInfo); err != nil {
+			return fmt.Errorf("error publishing %s driver: %v", d.Name(), err)
+		}
+		return nil
+	}
+
 	lunPath := lunPath(name)
 	igroupName := d.Config.IgroupName
 
@@ -758,6 +844,38 @@ func (d *SANStorageDriver) Publish(
 	return nil
 }
 
+// Unpublish the volume from the host specified in publishInfo.  This method may or may not be running on the host
+// where the volume was mounted, so it should limit itself to updating access rules, initiator groups, etc.
+// that require some host identity (but not locality) as well as storage controller API access.
+func (d *SANStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	name := volConfig.InternalName
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	var err error
+	if d.usesNVMe() {
+		err = d.unpublishNVMeNamespace(ctx, name, publishInfo)
+	} else {
+		err = UnpublishLUN(ctx, d.API, &d.Config, volConfig, publishInfo, lunPath(name), d.Config.IgroupName)
+	}
+	if err != nil {
+		return fmt.Errorf("error unpublishing %s driver: %v", d.Name(), err)
+	}
+
+	return nil
+}
+
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
 func (d *SANStorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig) error {
 	return nil
@@ -869,6 +987,43 @@ func (d *SANStorageDriver) Get(ctx context.Context, name string) error {
 	return GetVolume(ctx, name, d.API, &d.Config)
 }
 
+// GetVolumeCondition returns the condition of a volume's Flexvol and LUN or namespace.  Each Flexvol is
+// sized to fit its LUN, so a fully written LUN is not reported as a full volume.
+func (d *SANStorageDriver) GetVolumeCondition(
+	ctx context.Context, volConfig *storage.VolumeConfig,
+) (*storage.VolumeCondition, error) {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "GetVolumeCondition", "Type": "SANStorageDriver", "name": name}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetVolumeCondition")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetVolumeCondition")
+	}
+
+	condition, err := getFlexvolCondition(ctx, name, false, d.API)
+	if err != nil || condition.Abnormal {
+		return condition, err
+	}
+
+	if d.usesNVMe() {
+		nvmeAPI, err := d.nvmeAPI()
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := nvmeAPI.NVMeNamespaceGet(ctx, namespacePath(name))
+		if err != nil {
+			Logc(ctx).WithField("namespace", namespacePath(name)).WithError(err).Debug("Could not get namespace.")
+			return storage.NewAbnormalVolumeCondition("namespace %s not found", namespacePath(name)), nil
+		}
+		if namespace.State != "" && namespace.State != "online" {
+			return storage.NewAbnormalVolumeCondition("namespace %s is %s", namespace.Name, namespace.State), nil
+		}
+		return storage.NewNormalVolumeCondition(), nil
+	}
+
+	return getLUNCondition(ctx, lunPath(name), d.API), nil
+}
+
 // Retrieve storage backend capabilities
 func (d *SANStorageDriver) GetStorageBackendSpecs(_ context.Context, backend *storage.Backend) error {
 	return getStorageBackendSpecsCommon(backend, d.physicalPools, d.virtualPools, d.BackendName())
@@ -879,6 +1034,14 @@ func (d *SANStorageDriver) GetStorageBackendPhysicalPoolNames(context.Context) [
 	return getStorageBackendPhysicalPoolNamesCommon(d.physicalPools)
 }
 
+// GetCapacity returns the capacity of the aggregates that could host a volume with the specified attributes
+// in the specified pool
+func (d *SANStorageDriver) GetCapacity(
+	ctx context.Context, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+	return getPoolCapacityCommon(ctx, d, storagePool, volAttributes, d.physicalPools, d.virtualPools)
+}
+
 func (d *SANStorageDriver) getStoragePoolAttributes() map[string]sa.Offer {
 
 	return map[string]sa.Offer{
@@ -922,6 +1085,12 @@ func (d *SANStorageDriver) CreateFollowup(ctx context.Context, volConfig *storag
 		return nil
 	}
 
+	// Namespaces are mapped to their subsystems as they are published
+	if d.usesNVMe() {
+		Logc(ctx).Debug("No follow-up create actions for NVMe.")
+		return nil
+	}
+
 	return d.mapOntapSANLun(ctx, volConfig)
 }
 
@@ -929,12 +1098,21 @@ func (d *SANStorageDriver) mapOntapSANLun(ctx context.Context, volConfig *storag
 
 	// get the lunPath and lunID
 	lunPath := fmt.Sprintf("/vol/%v/lun0", volConfig.InternalName)
-	lunID, err := d.API.LunMapIfNotMapped(ctx, d.Config.IgroupName, lunPath, volConfig.ImportNotManaged)
-	if err != nil {
-		return err
+
+	// Managed LUNs are mapped to each node's igroup as they are published, so only unmanaged
+	// LUNs keep their map to the backend igroup.
+	lunID := 0
+	igroupName := ""
+	if volConfig.ImportNotManaged {
+		var err error
+		igroupName = d.Config.IgroupName
+		lunID, err = d.API.LunMapIfNotMapped(ctx, igroupName, lunPath, volConfig.ImportNotManaged)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = PopulateOntapLunMapping(ctx, d.API, &d.Config, d.ips, volConfig, lunID, lunPath, d.Config.IgroupName)
+	err := PopulateOntapLunMapping(ctx, d.API, &d.Config, d.ips, volConfig, lunID, lunPath, igroupName)
 	if err != nil {
 		return fmt.Errorf("error mapping LUN for %s driver: %v", d.Name(), err)
 	}
@@ -958,20 +1136,32 @@ func (d *SANStorageDriver) GetExternalConfig(ctx context.Context) interface{} {
 // GetVolumeExternal queries the storage backend for all relevant info about
 // a single container volume managed by this driver and returns a VolumeExternal
 // representation of the volume.
-func (d *SANStorageDriver) GetVolumeExternal(_ context.Context, name string) (*storage.VolumeExternal, error) {
+func (d *SANStorageDriver) GetVolumeExternal(ctx context.Context, name string) (*storage.VolumeExternal, error) {
 
 	volumeAttrs, err := d.API.VolumeGet(name)
 	if err != nil {
 		return nil, err
 	}
 
+	if d.usesNVMe() {
+		nvmeAPI, err := d.nvmeAPI()
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := nvmeAPI.NVMeNamespaceGet(ctx, namespacePath(name))
+		if err != nil {
+			return nil, err
+		}
+		return d.getVolumeExternal(namespace.Size, volumeAttrs), nil
+	}
+
 	lunPath := fmt.Sprintf("/vol/%v/*", name)
 	lunAttrs, err := d.API.LunGet(lunPath)
 	if err != nil {
 		return nil, err
 	}
 
-	return d.getVolumeExternal(lunAttrs, volumeAttrs), nil
+	return d.getVolumeExternal(lunAttrs.Size(), volumeAttrs), nil
 }
 
 // GetVolumeExternalWrappers queries the storage backend for all relevant info about
@@ -990,14 +1180,6 @@ func (d *SANStorageDriver) GetVolumeExternalWrappers(ctx context.Context, channe
 		return
 	}
 
-	// Get all LUNs named 'lun0' in volumes matching the storage prefix
-	lunPathPattern := fmt.Sprintf("/vol/%v/lun0", *d.Config.StoragePrefix+"*")
-	lunsResponse, err := d.API.LunGetAll(lunPathPattern)
-	if err = api.GetError(ctx, lunsResponse, err); err != nil {
-		channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
-		return
-	}
-
 	// Make a map of volumes for faster correlation with LUNs
 	volumeMap := make(map[string]azgo.VolumeAttributesType)
 	if volumesResponse.Result.AttributesListPtr != nil {
@@ -1007,6 +1189,19 @@ func (d *SANStorageDriver) GetVolumeExternalWrappers(ctx context.Context, channe
 		}
 	}
 
+	if d.usesNVMe() {
+		d.getNVMeVolumeExternalWrappers(ctx, volumeMap, channel)
+		return
+	}
+
+	// Get all LUNs named 'lun0' in volumes matching the storage prefix
+	lunPathPattern := fmt.Sprintf("/vol/%v/lun0", *d.Config.StoragePrefix+"*")
+	lunsResponse, err := d.API.LunGetAll(lunPathPattern)
+	if err = api.GetError(ctx, lunsResponse, err); err != nil {
+		channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
+		return
+	}
+
 	// Convert all LUNs to VolumeExternal and write them to the channel
 	if lunsResponse.Result.AttributesListPtr != nil {
 		for _, lun := range lunsResponse.Result.AttributesListPtr.LunInfoPtr {
@@ -1017,16 +1212,16 @@ func (d *SANStorageDriver) GetVolumeExternalWrappers(ctx context.Context, channe
 				continue
 			}
 
-			channel <- &storage.VolumeExternalWrapper{Volume: d.getVolumeExternal(&lun, &volume), Error: nil}
+			channel <- &storage.VolumeExternalWrapper{Volume: d.getVolumeExternal(lun.Size(), &volume), Error: nil}
 		}
 	}
 }
 
 // getVolumeExternal is a private method that accepts info about a volume
-// as returned by the storage backend and formats it as a VolumeExternal
-// object.
+// as returned by the storage backend, along with the size of its LUN or
+// namespace, and formats it as a VolumeExternal object.
 func (d *SANStorageDriver) getVolumeExternal(
-	lunAttrs *azgo.LunInfoType, volumeAttrs *azgo.VolumeAttributesType,
+	size int, volumeAttrs *azgo.VolumeAttributesType,
 ) *storage.VolumeExternal {
 
 	volumeIDAttrs := volumeAttrs.VolumeIdAttributesPtr
@@ -1042,7 +1237,7 @@ func (d *SANStorageDriver) getVolumeExternal(
 		Version:         tridentconfig.OrchestratorAPIVersion,
 		Name:            name,
 		InternalName:    internalName,
-		Size:            strconv.FormatInt(int64(lunAttrs.Size()), 10),
+		Size:            strconv.FormatInt(int64(size), 10),
 		Protocol:        tridentconfig.Block,
 		SnapshotPolicy:  volumeSnapshotAttrs.SnapshotPolicy(),
 		ExportPolicy:    "",
@@ -1074,6 +1269,11 @@ func (d *SANStorageDriver) GetUpdateType(_ context.Context, driverOrig storage.D
 		bitmap.Add(storage.VolumeAccessInfoChange)
 	}
 
+	// Existing volumes can't be converted between LUNs and namespaces
+	if d.Config.SANType != dOrig.Config.SANType {
+		bitmap.Add(storage.InvalidUpdate)
+	}
+
 	if d.Config.Password != dOrig.Config.Password {
 		bitmap.Add(storage.PasswordChange)
 	}
@@ -1163,6 +1363,16 @@ func (d *SANStorageDriver) Resize(ctx context.Context, volConfig *storage.Volume
 	}
 
 	// Resize operations
+	if d.usesNVMe() {
+		returnSize, err := d.resizeNVMeNamespace(ctx, name, sizeBytes)
+		if err != nil {
+			Logc(ctx).WithField("error", err).Error("Namespace resize failed.")
+			return fmt.Errorf("volume resize failed")
+		}
+		volConfig.Size = strconv.FormatUint(returnSize, 10)
+		return nil
+	}
+
 	if !d.API.SupportsFeature(ctx, api.LunGeometrySkip) {
 		// Check LUN geometry and verify LUN max size.
 		lunGeometry, err := d.API.LunGetGeometry(lunPath(name))
@@ -1226,6 +1436,216 @@ func (d *SANStorageDriver) Resize(ctx context.Context, volConfig *storage.Volume
 	return nil
 }
 
+// Modify changes the attributes of an existing volume.
+func (d *SANStorageDriver) Modify(
+	ctx context.Context, volConfig *storage.VolumeConfig, modification *storage.VolumeModification,
+) error {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Modify",
+			"Type":         "SANStorageDriver",
+			"name":         name,
+			"modification": modification,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Modify")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Modify")
+	}
+
+	if err := validateVolumeModification(ctx, d, modification, "snapshotPolicy", "snapshotReserve",
+		"qosPolicy", "adaptiveQosPolicy", "tieringPolicy"); err != nil {
+		return err
+	}
+
+	qosPolicyGroup, err := api.NewQosPolicyGroup(modification.QosPolicy, modification.AdaptiveQosPolicy)
+	if err != nil {
+		return err
+	}
+
+	volExists, err := d.API.VolumeExists(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error checking for existing volume: %v", err)
+	}
+	if !volExists {
+		return fmt.Errorf("volume %s does not exist", name)
+	}
+
+	if err = modifyFlexvolCommon(ctx, name, modification, d.API); err != nil {
+		return err
+	}
+
+	// QoS policies are applied to the Flexvol of a namespace, which has no QoS policy of its own
+	if qosPolicyGroup.Kind != api.InvalidQosPolicyGroupKind && d.usesNVMe() {
+		response, err := d.API.VolumeSetQosPolicyGroupName(name, qosPolicyGroup)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error setting QoS policy of volume %s: %v", name, err)
+		}
+		return nil
+	}
+
+	// QoS policies are applied to the LUN rather than its Flexvol
+	if qosPolicyGroup.Kind != api.InvalidQosPolicyGroupKind {
+		response, err := d.API.LunSetQosPolicyGroup(lunPath(name), qosPolicyGroup)
+		if err = api.GetError(ctx, response, err); err != nil {
+			return fmt.Errorf("error setting QoS policy of LUN %s: %v", lunPath(name), err)
+		}
+	}
+
+	return nil
+}
+
+// GetMirrorVolumeHandle returns the handle by which a peer backend may refer to a volume
+func (d *SANStorageDriver) GetMirrorVolumeHandle(_ context.Context, name string) (string, error) {
+	return getMirrorVolumeHandleCommon(d.Config.SVM, name), nil
+}
+
+// EstablishMirror establishes a mirror relationship that replicates the remote volume to the local volume
+func (d *SANStorageDriver) EstablishMirror(
+	ctx context.Context, name, remoteVolumeHandle, replicationPolicy, replicationSchedule string,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "EstablishMirror",
+			"Type":         "SANStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> EstablishMirror")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< EstablishMirror")
+	}
+
+	return establishMirrorCommon(ctx, d.Config.SVM, name, remoteVolumeHandle, replicationPolicy, replicationSchedule,
+		d.API)
+}
+
+// ReestablishMirror resynchronizes a mirror relationship so that the local volume replicates the remote volume
+func (d *SANStorageDriver) ReestablishMirror(
+	ctx context.Context, name, remoteVolumeHandle, replicationPolicy, replicationSchedule string,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "ReestablishMirror",
+			"Type":         "SANStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> ReestablishMirror")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< ReestablishMirror")
+	}
+
+	return reestablishMirrorCommon(ctx, d.Config.SVM, name, remoteVolumeHandle, replicationPolicy,
+		replicationSchedule, d.API)
+}
+
+// PromoteMirror breaks the mirror relationship to the local volume, making it writable
+func (d *SANStorageDriver) PromoteMirror(ctx context.Context, name, remoteVolumeHandle string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "PromoteMirror",
+			"Type":         "SANStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> PromoteMirror")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< PromoteMirror")
+	}
+
+	return promoteMirrorCommon(ctx, d.Config.SVM, name, remoteVolumeHandle, d.API)
+}
+
+// GetMirrorStatus returns the status of the mirror relationship to the local volume
+func (d *SANStorageDriver) GetMirrorStatus(
+	ctx context.Context, name, remoteVolumeHandle string,
+) (*storage.MirrorStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "GetMirrorStatus",
+			"Type":         "SANStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetMirrorStatus")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetMirrorStatus")
+	}
+
+	return getMirrorStatusCommon(d.Config.SVM, name, remoteVolumeHandle, d.API)
+}
+
+// DeleteMirror deletes the mirror relationship to the local volume
+func (d *SANStorageDriver) DeleteMirror(ctx context.Context, name, remoteVolumeHandle string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "DeleteMirror",
+			"Type":         "SANStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> DeleteMirror")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< DeleteMirror")
+	}
+
+	return deleteMirrorCommon(ctx, d.Config.SVM, name, remoteVolumeHandle, d.API)
+}
+
+// ReleaseMirror removes the information about a deleted mirror relationship from its local source volume
+func (d *SANStorageDriver) ReleaseMirror(ctx context.Context, name, remoteVolumeHandle string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "ReleaseMirror",
+			"Type":         "SANStorageDriver",
+			"name":         name,
+			"remoteVolume": remoteVolumeHandle,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> ReleaseMirror")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< ReleaseMirror")
+	}
+
+	return releaseMirrorCommon(ctx, d.Config.SVM, name, remoteVolumeHandle, d.API)
+}
+
+// MoveVolume starts moving a volume to another aggregate of this backend
+func (d *SANStorageDriver) MoveVolume(ctx context.Context, name, pool string) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "MoveVolume",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+			"pool":   pool,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> MoveVolume")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< MoveVolume")
+	}
+
+	return moveVolumeCommon(ctx, name, pool, d.physicalPools, d.API)
+}
+
+// GetVolumeMoveStatus returns the status of a move of a volume to another aggregate of this backend
+func (d *SANStorageDriver) GetVolumeMoveStatus(
+	ctx context.Context, name, pool string,
+) (*storage.VolumeMoveStatus, error) {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "GetVolumeMoveStatus",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+			"pool":   pool,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetVolumeMoveStatus")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetVolumeMoveStatus")
+	}
+
+	return getVolumeMoveStatusCommon(name, pool, d.API)
+}
+
 func (d *SANStorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node, _ string) error {
 
 	// Discover known nodes
@@ -1247,6 +1667,11 @@ func (d *SANStorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*uti
 		defer Logc(ctx).WithFields(fields).Debug("<<<< ReconcileNodeAccess")
 	}
 
+	// Each subsystem holds only the NQNs of the nodes its volume is published to
+	if d.usesNVMe() {
+		return nil
+	}
+
 	return reconcileSANNodeAccess(ctx, d.API, d.Config.IgroupName, nodeIQNs)
 }
 
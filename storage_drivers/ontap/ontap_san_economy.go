@@ -197,7 +197,7 @@ type SANEconomyStorageDriver struct {
 	initialized       bool
 	Config            drivers.OntapStorageDriverConfig
 	ips               []string
-	API               *api.Client
+	API               api.OntapAPI
 	Telemetry         *Telemetry
 	flexvolNamePrefix string
 	helper            *LUNHelper
@@ -211,7 +211,7 @@ func (d *SANEconomyStorageDriver) GetConfig() *drivers.OntapStorageDriverConfig
 	return &d.Config
 }
 
-func (d *SANEconomyStorageDriver) GetAPI() *api.Client {
+func (d *SANEconomyStorageDriver) GetAPI() api.OntapAPI {
 	return d.API
 }
 
@@ -413,7 +413,7 @@ func (d *SANEconomyStorageDriver) Create(
 	}
 
 	// Get candidate physical pools
-	physicalPools, err := getPoolsForCreate(ctx, volConfig, storagePool, volAttributes, d.physicalPools, d.virtualPools)
+	physicalPools, err := getPoolsForCreate(ctx, d, volConfig, storagePool, volAttributes, d.physicalPools, d.virtualPools)
 	if err != nil {
 		return err
 	}
@@ -664,7 +664,7 @@ func (d *SANEconomyStorageDriver) CreateClone(
 // Create a volume clone
 func (d *SANEconomyStorageDriver) createLUNClone(
 	ctx context.Context, lunName, source, snapshot string, config *drivers.OntapStorageDriverConfig,
-	client *api.Client, prefix string, isLunCreateFromSnapshot bool, qosPolicyGroup api.QosPolicyGroup,
+	client api.OntapAPI, prefix string, isLunCreateFromSnapshot bool, qosPolicyGroup api.QosPolicyGroup,
 ) error {
 
 	if config.DebugTraceFlags["method"] {
@@ -951,6 +951,45 @@ func (d *SANEconomyStorageDriver) Publish(
 	return nil
 }
 
+// Unpublish the volume from the host specified in publishInfo.  This method may or may not be running on the host
+// where the volume was mounted, so it should limit itself to updating access rules, initiator groups, etc.
+// that require some host identity (but not locality) as well as storage controller API access.
+func (d *SANEconomyStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	name := volConfig.InternalName
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "SANEconomyStorageDriver",
+			"name":   name,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	exists, bucketVol, err := d.LUNExists(ctx, name, d.FlexvolNamePrefix())
+	if err != nil {
+		Logc(ctx).Errorf("Error checking for existing LUN: %v", err)
+		return err
+	}
+	if !exists {
+		Logc(ctx).WithField("LUN", name).Warn("LUN not found, nothing to unpublish.")
+		return nil
+	}
+
+	lunPath := d.helper.GetLUNPath(bucketVol, name)
+
+	err = UnpublishLUN(ctx, d.API, &d.Config, volConfig, publishInfo, lunPath, d.Config.IgroupName)
+	if err != nil {
+		return fmt.Errorf("error unpublishing %s driver: %v", d.Name(), err)
+	}
+
+	return nil
+}
+
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
 func (d *SANEconomyStorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig) error {
 	return nil
@@ -1266,6 +1305,35 @@ func (d *SANEconomyStorageDriver) Get(ctx context.Context, name string) error {
 	return nil
 }
 
+// GetVolumeCondition returns the condition of a volume's LUN and the Flexvol containing it.  The Flexvols
+// are sized to fit their LUNs, so a Flexvol full of written LUNs is not reported as a full volume.
+func (d *SANEconomyStorageDriver) GetVolumeCondition(
+	ctx context.Context, volConfig *storage.VolumeConfig,
+) (*storage.VolumeCondition, error) {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{"Method": "GetVolumeCondition", "Type": "SANEconomyStorageDriver", "name": name}
+		Logc(ctx).WithFields(fields).Debug(">>>> GetVolumeCondition")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< GetVolumeCondition")
+	}
+
+	exists, bucketVol, err := d.LUNExists(ctx, name, d.FlexvolNamePrefix())
+	if err != nil {
+		return nil, fmt.Errorf("error checking for existing LUN %s: %v", name, err)
+	}
+	if !exists {
+		return storage.NewAbnormalVolumeCondition("LUN %s does not exist", name), nil
+	}
+
+	condition, err := getFlexvolCondition(ctx, bucketVol, false, d.API)
+	if err != nil || condition.Abnormal {
+		return condition, err
+	}
+
+	return getLUNCondition(ctx, d.helper.GetLUNPath(bucketVol, name), d.API), nil
+}
+
 // ensureFlexvolForLUN accepts a set of Flexvol characteristics and either finds one to contain a new
 // LUN or it creates a new Flexvol with the needed attributes.  The name of the matching volume is returned,
 // as is a boolean indicating whether the volume was newly created to satisfy this request.
@@ -1343,7 +1411,7 @@ func (d *SANEconomyStorageDriver) createFlexvolForLUN(
 	// Create the flexvol
 	volCreateResponse, err := d.API.VolumeCreate(
 		ctx, flexvol, aggregate, size, spaceReserve, snapshotPolicy, unixPermissions, exportPolicy, securityStyle,
-		tieringPolicy, "", api.QosPolicyGroup{}, encrypt, snapshotReserveInt)
+		tieringPolicy, "", api.QosPolicyGroup{}, encrypt, snapshotReserveInt, false)
 
 	if err = api.GetError(ctx, volCreateResponse, err); err != nil {
 		return "", fmt.Errorf("error creating volume: %v", err)
@@ -1497,6 +1565,14 @@ func (d *SANEconomyStorageDriver) GetStorageBackendPhysicalPoolNames(context.Con
 	return getStorageBackendPhysicalPoolNamesCommon(d.physicalPools)
 }
 
+// GetCapacity returns the capacity of the aggregates that could host a volume with the specified attributes
+// in the specified pool
+func (d *SANEconomyStorageDriver) GetCapacity(
+	ctx context.Context, storagePool *storage.Pool, volAttributes map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+	return getPoolCapacityCommon(ctx, d, storagePool, volAttributes, d.physicalPools, d.virtualPools)
+}
+
 func (d *SANEconomyStorageDriver) getStoragePoolAttributes() map[string]sa.Offer {
 
 	return map[string]sa.Offer{
@@ -1553,14 +1629,21 @@ func (d *SANEconomyStorageDriver) mapOntapSANLUN(ctx context.Context, volConfig
 	if !exists {
 		return fmt.Errorf("could not find LUN %s", volConfig.InternalName)
 	}
-	// Map LUN
 	lunPath := GetLUNPathEconomy(flexvol, volConfig.InternalName)
-	lunID, err := d.API.LunMapIfNotMapped(ctx, d.Config.IgroupName, lunPath, volConfig.ImportNotManaged)
-	if err != nil {
-		return err
+
+	// Managed LUNs are mapped to each node's igroup as they are published, so only unmanaged
+	// LUNs keep their map to the backend igroup.
+	lunID := 0
+	igroupName := ""
+	if volConfig.ImportNotManaged {
+		igroupName = d.Config.IgroupName
+		lunID, err = d.API.LunMapIfNotMapped(ctx, igroupName, lunPath, volConfig.ImportNotManaged)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = PopulateOntapLunMapping(ctx, d.API, &d.Config, d.ips, volConfig, lunID, lunPath, d.Config.IgroupName)
+	err = PopulateOntapLunMapping(ctx, d.API, &d.Config, d.ips, volConfig, lunID, lunPath, igroupName)
 	if err != nil {
 		return fmt.Errorf("error mapping LUN for %s driver: %v", d.Name(), err)
 	}
@@ -1909,6 +1992,50 @@ func (d *SANEconomyStorageDriver) Resize(ctx context.Context, volConfig *storage
 	return nil
 }
 
+// Modify changes the attributes of an existing volume.  Attributes of the Flexvol that contains the
+// LUN cannot be changed, as they are shared with other volumes.
+func (d *SANEconomyStorageDriver) Modify(
+	ctx context.Context, volConfig *storage.VolumeConfig, modification *storage.VolumeModification,
+) error {
+
+	name := volConfig.InternalName
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method":       "Modify",
+			"Type":         "SANEconomyStorageDriver",
+			"name":         name,
+			"modification": modification,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Modify")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Modify")
+	}
+
+	if err := validateVolumeModification(ctx, d, modification, "qosPolicy", "adaptiveQosPolicy"); err != nil {
+		return err
+	}
+
+	qosPolicyGroup, err := api.NewQosPolicyGroup(modification.QosPolicy, modification.AdaptiveQosPolicy)
+	if err != nil {
+		return err
+	}
+
+	exists, bucketVol, err := d.LUNExists(ctx, name, d.FlexvolNamePrefix())
+	if err != nil {
+		return fmt.Errorf("error checking for existing LUN: %v", err)
+	}
+	if !exists {
+		return fmt.Errorf("error LUN %s does not exist", name)
+	}
+
+	lunPath := d.helper.GetLUNPath(bucketVol, name)
+	response, err := d.API.LunSetQosPolicyGroup(lunPath, qosPolicyGroup)
+	if err = api.GetError(ctx, response, err); err != nil {
+		return fmt.Errorf("error setting QoS policy of LUN %s: %v", lunPath, err)
+	}
+
+	return nil
+}
+
 // resizeFlexvol grows or shrinks the Flexvol to an optimal size if possible. Otherwise
 // the Flexvol is expanded by the value of sizeBytes
 func (d *SANEconomyStorageDriver) resizeFlexvol(ctx context.Context, flexvol string, sizeBytes uint64) error {
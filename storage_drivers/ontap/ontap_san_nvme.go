@@ -0,0 +1,271 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	tridentconfig "github.com/netapp/trident/config"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap/api"
+	"github.com/netapp/trident/storage_drivers/ontap/api/azgo"
+	"github.com/netapp/trident/utils"
+)
+
+// When the SAN type is NVMe, each ontap-san volume is a Flexvol containing a single namespace, which is
+// mapped to a subsystem of the same name as the Flexvol.  The subsystem exists only while the volume is
+// published, and its hosts are the NQNs of the nodes the volume is published to.
+
+func namespacePath(name string) string {
+	return fmt.Sprintf("/vol/%v/namespace0", name)
+}
+
+// nvmeNamespaceAttributes are saved as JSON in the namespace comment, since namespaces have no equivalent
+// of LUN attributes.
+type nvmeNamespaceAttributes struct {
+	FileSystemType string `json:"fstype"`
+	DriverContext  string `json:"driverContext"`
+}
+
+// usesNVMe returns whether this driver provisions NVMe namespaces rather than iSCSI LUNs.
+func (d *SANStorageDriver) usesNVMe() bool {
+	return d.Config.SANType == string(tridentconfig.NVMe)
+}
+
+// nvmeAPI returns the driver's client as an NVMeAPI, which only the REST client implements.
+func (d *SANStorageDriver) nvmeAPI() (api.NVMeAPI, error) {
+	nvmeAPI, ok := d.API.(api.NVMeAPI)
+	if !ok {
+		return nil, fmt.Errorf("SAN type %s requires the ONTAP REST API; set useREST to true", d.Config.SANType)
+	}
+	return nvmeAPI, nil
+}
+
+// getNVMeDataLIFs returns the addresses of the SVM's NVMe/TCP data LIFs.
+func (d *SANStorageDriver) getNVMeDataLIFs(ctx context.Context) ([]string, error) {
+
+	nvmeAPI, err := d.nvmeAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := nvmeAPI.NVMeDataLIFs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no NVMe/TCP data LIFs found on SVM %s", d.Config.SVM)
+	}
+	Logc(ctx).WithField("dataLIFs", ips).Debug("Found NVMe/TCP LIFs.")
+
+	return ips, nil
+}
+
+// createNVMeNamespace creates the namespace for a new volume and records its file system type.
+func (d *SANStorageDriver) createNVMeNamespace(
+	ctx context.Context, name string, sizeBytes uint64, fstype string,
+) error {
+
+	nvmeAPI, err := d.nvmeAPI()
+	if err != nil {
+		return err
+	}
+
+	comment, err := json.Marshal(nvmeNamespaceAttributes{
+		FileSystemType: fstype,
+		DriverContext:  string(d.Config.DriverContext),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nvmeAPI.NVMeNamespaceCreate(ctx, namespacePath(name), int(sizeBytes), "linux", string(comment))
+}
+
+// getNVMeNamespaceFSType returns the file system type recorded when a namespace was created.
+func getNVMeNamespaceFSType(ctx context.Context, namespace *api.NVMeNamespace) string {
+
+	var attrs nvmeNamespaceAttributes
+	if err := json.Unmarshal([]byte(namespace.Comment), &attrs); err != nil || attrs.FileSystemType == "" {
+		Logc(ctx).WithFields(log.Fields{
+			"namespace": namespace.Name,
+			"fstype":    drivers.DefaultFileSystemType,
+		}).Warn("Namespace fstype not found, using default.")
+		return drivers.DefaultFileSystemType
+	}
+	return attrs.FileSystemType
+}
+
+// publishNVMeNamespace gives the host in publishInfo access to a volume's namespace by adding the host's
+// NQN to the volume's subsystem, creating the subsystem and mapping the namespace to it if necessary.
+func (d *SANStorageDriver) publishNVMeNamespace(
+	ctx context.Context, name string, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	nvmeAPI, err := d.nvmeAPI()
+	if err != nil {
+		return err
+	}
+
+	hostNQN := publishInfo.HostNQN
+	if publishInfo.Localhost {
+		if hostNQN, err = utils.GetHostNQN(ctx); err != nil {
+			return fmt.Errorf("error determining host NQN: %v", err)
+		}
+	}
+	if hostNQN == "" {
+		return fmt.Errorf("unknown NVMe host NQN for node %s", publishInfo.HostName)
+	}
+
+	namespace, err := nvmeAPI.NVMeNamespaceGet(ctx, namespacePath(name))
+	if err != nil {
+		return fmt.Errorf("error reading namespace for volume %s: %v", name, err)
+	}
+
+	subsystem, err := nvmeAPI.NVMeSubsystemCreate(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error creating subsystem %s: %v", name, err)
+	}
+
+	if err = nvmeAPI.NVMeSubsystemAddHost(ctx, name, hostNQN); err != nil {
+		return fmt.Errorf("error adding host %s to subsystem %s: %v", hostNQN, name, err)
+	}
+
+	if err = nvmeAPI.NVMeNamespaceMap(ctx, name, namespace.Name); err != nil {
+		return fmt.Errorf("error mapping namespace %s to subsystem %s: %v", namespace.Name, name, err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"namespace": namespace.Name,
+		"subsystem": subsystem.NQN,
+		"host":      hostNQN,
+	}).Debug("Published namespace.")
+
+	// Add fields needed by Attach
+	publishInfo.SANType = string(tridentconfig.NVMe)
+	publishInfo.NVMeSubsystemNQN = subsystem.NQN
+	publishInfo.NVMeNamespaceUUID = namespace.UUID
+	publishInfo.NVMeTargetIPs = d.ips
+	publishInfo.FilesystemType = getNVMeNamespaceFSType(ctx, namespace)
+
+	return nil
+}
+
+// unpublishNVMeNamespace removes the host in publishInfo from a volume's subsystem.  Once no hosts remain,
+// the namespace is unmapped and the subsystem is deleted.
+func (d *SANStorageDriver) unpublishNVMeNamespace(
+	ctx context.Context, name string, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	nvmeAPI, err := d.nvmeAPI()
+	if err != nil {
+		return err
+	}
+
+	if publishInfo.HostNQN != "" {
+		err = nvmeAPI.NVMeSubsystemRemoveHost(ctx, name, publishInfo.HostNQN)
+		if utils.IsNotFoundError(err) {
+			Logc(ctx).WithField("subsystem", name).Debug("Subsystem not found, nothing to unpublish.")
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error removing host %s from subsystem %s: %v", publishInfo.HostNQN, name, err)
+		}
+	}
+
+	subsystem, err := nvmeAPI.NVMeSubsystemGet(ctx, name)
+	if utils.IsNotFoundError(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error reading subsystem %s: %v", name, err)
+	}
+
+	if len(subsystem.Hosts) > 0 {
+		Logc(ctx).WithFields(log.Fields{
+			"subsystem": name,
+			"hosts":     subsystem.Hosts,
+		}).Debug("Subsystem still has hosts.")
+		return nil
+	}
+
+	return d.destroyNVMeSubsystem(ctx, name)
+}
+
+// destroyNVMeSubsystem unmaps a volume's namespace from the volume's subsystem and deletes the subsystem.
+func (d *SANStorageDriver) destroyNVMeSubsystem(ctx context.Context, name string) error {
+
+	nvmeAPI, err := d.nvmeAPI()
+	if err != nil {
+		return err
+	}
+
+	err = nvmeAPI.NVMeNamespaceUnmap(ctx, name, namespacePath(name))
+	if err != nil && !utils.IsNotFoundError(err) {
+		return fmt.Errorf("error unmapping namespace %s: %v", namespacePath(name), err)
+	}
+
+	if err = nvmeAPI.NVMeSubsystemDelete(ctx, name); err != nil {
+		return fmt.Errorf("error deleting subsystem %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// getNVMeVolumeExternalWrappers writes a VolumeExternal representation of each namespace named 'namespace0'
+// in the specified volumes to the supplied channel.
+func (d *SANStorageDriver) getNVMeVolumeExternalWrappers(
+	ctx context.Context, volumeMap map[string]azgo.VolumeAttributesType,
+	channel chan *storage.VolumeExternalWrapper,
+) {
+
+	nvmeAPI, err := d.nvmeAPI()
+	if err != nil {
+		channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
+		return
+	}
+
+	namespaces, err := nvmeAPI.NVMeNamespaceGetAll(ctx, namespacePath(*d.Config.StoragePrefix+"*"))
+	if err != nil {
+		channel <- &storage.VolumeExternalWrapper{Volume: nil, Error: err}
+		return
+	}
+
+	for _, namespace := range namespaces {
+
+		volume, ok := volumeMap[namespace.Volume]
+		if !ok {
+			Logc(ctx).WithField("path", namespace.Name).Warning("Flexvol not found for namespace.")
+			continue
+		}
+
+		channel <- &storage.VolumeExternalWrapper{Volume: d.getVolumeExternal(namespace.Size, &volume), Error: nil}
+	}
+}
+
+// resizeNVMeNamespace resizes a volume's Flexvol and namespace, and returns the namespace's new size.
+func (d *SANStorageDriver) resizeNVMeNamespace(ctx context.Context, name string, sizeBytes uint64) (uint64, error) {
+
+	nvmeAPI, err := d.nvmeAPI()
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := d.API.VolumeSetSize(name, strconv.FormatUint(sizeBytes, 10))
+	if err = api.GetError(ctx, response.Result, err); err != nil {
+		return 0, fmt.Errorf("error resizing volume %s: %v", name, err)
+	}
+
+	newSize, err := nvmeAPI.NVMeNamespaceSetSize(ctx, namespacePath(name), int(sizeBytes))
+	if err != nil {
+		return 0, fmt.Errorf("error resizing namespace %s: %v", namespacePath(name), err)
+	}
+
+	return uint64(newSize), nil
+}
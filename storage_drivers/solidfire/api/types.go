@@ -207,6 +207,12 @@ type AddVolumesToVolumeAccessGroupRequest struct {
 	Volumes             []int64 `json:"volumes"`
 }
 
+// RemoveVolumesFromVolumeAccessGroupRequest
+type RemoveVolumesFromVolumeAccessGroupRequest struct {
+	VolumeAccessGroupID int64   `json:"volumeAccessGroupID"`
+	Volumes             []int64 `json:"volumes"`
+}
+
 // CreateVolumeAccessGroupRequest
 type CreateVolumeAccessGroupRequest struct {
 	Name       string   `json:"name"`
@@ -222,6 +228,11 @@ type CreateVolumeAccessGroupResult struct {
 	} `json:"result"`
 }
 
+// DeleteVolumeAccessGroupRequest
+type DeleteVolumeAccessGroupRequest struct {
+	VAGID int64 `json:"volumeAccessGroupID"`
+}
+
 // AddInitiatorsToVolumeAccessGroupRequest
 type AddInitiatorsToVolumeAccessGroupRequest struct {
 	Initiators []string `json:"initiators"`
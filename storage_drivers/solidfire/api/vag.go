@@ -56,3 +56,14 @@ func (c *Client) AddInitiatorsToVolumeAccessGroup(
 	}
 	return nil
 }
+
+// DeleteVolumeAccessGroup tbd
+func (c *Client) DeleteVolumeAccessGroup(ctx context.Context, r *DeleteVolumeAccessGroupRequest) error {
+
+	_, err := c.Request(ctx, "DeleteVolumeAccessGroup", r, NewReqID())
+	if err != nil {
+		Logc(ctx).Errorf("Error in DeleteVolumeAccessGroup API response: %+v", err)
+		return errors.New("failed to delete VAG")
+	}
+	return nil
+}
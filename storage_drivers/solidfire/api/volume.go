@@ -196,6 +196,19 @@ func (c *Client) AddVolumesToAccessGroup(ctx context.Context, req *AddVolumesToV
 	return err
 }
 
+// RemoveVolumesFromAccessGroup tbd
+func (c *Client) RemoveVolumesFromAccessGroup(
+	ctx context.Context, req *RemoveVolumesFromVolumeAccessGroupRequest,
+) (err error) {
+
+	_, err = c.Request(ctx, "RemoveVolumesFromVolumeAccessGroup", req, NewReqID())
+	if err != nil {
+		Logc(ctx).Errorf("error response from Remove from VAG request: %+v ", err)
+		return errors.New("device API error")
+	}
+	return err
+}
+
 // DeleteVolume tbd
 func (c *Client) DeleteVolume(ctx context.Context, volumeID int64) (err error) {
 
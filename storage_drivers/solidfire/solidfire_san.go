@@ -4,6 +4,8 @@ package solidfire
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -40,10 +42,17 @@ const (
 	QoSType = "type"
 
 	MaxLabelLength = 512
+
+	// maxVAGNameLength is the longest name SolidFire accepts for a volume access group
+	maxVAGNameLength = 64
 )
 
 const MinimumVolumeSizeBytes = 1000000000 // 1 GB
 
+// nodeVAGLocks serializes changes to the volume access groups of each node, so that unpublishing a volume
+// cannot delete a node's access group while another volume is being published to the same node.
+var nodeVAGLocks utils.NamedLocks
+
 // SANStorageDriver is for iSCSI storage provisioning
 type SANStorageDriver struct {
 	initialized      bool
@@ -412,7 +421,8 @@ func (d *SANStorageDriver) populateConfigurationDefaults(
 			config.UseCHAP = true
 		}
 	case tridentconfig.ContextCSI:
-		if !config.UseCHAP {
+		// Volumes are mapped to per-node access groups if access groups are configured
+		if !config.UseCHAP && len(config.AccessGroups) == 0 {
 			Logc(ctx).Info("Enabling CHAP for CSI volumes.")
 			config.UseCHAP = true
 		}
@@ -689,21 +699,24 @@ func (d *SANStorageDriver) validate(ctx context.Context) error {
 			}
 		}
 
-		// Deal with upgrades for versions prior to handling multiple VAG ID's
-		var vIDs []int64
-		var req api.ListVolumesForAccountRequest
-		req.AccountID = d.AccountID
-		volumes, _ := d.Client.ListVolumesForAccount(ctx, &req)
-		for _, v := range volumes {
-			if v.Status != "deleted" {
-				vIDs = append(vIDs, v.VolumeID)
+		// Deal with upgrades for versions prior to handling multiple VAG ID's.  In CSI mode, volumes are
+		// added to access groups only when published to a node.
+		if d.Config.DriverContext != tridentconfig.ContextCSI {
+			var vIDs []int64
+			var req api.ListVolumesForAccountRequest
+			req.AccountID = d.AccountID
+			volumes, _ := d.Client.ListVolumesForAccount(ctx, &req)
+			for _, v := range volumes {
+				if v.Status != "deleted" {
+					vIDs = append(vIDs, v.VolumeID)
+				}
 			}
-		}
-		for _, vag := range d.Config.AccessGroups {
-			addAGErr := d.AddMissingVolumesToVag(ctx, vag, vIDs)
-			if addAGErr != nil {
-				err = fmt.Errorf("failed to update AccessGroup membership of volume %+v", addAGErr)
-				return err
+			for _, vag := range d.Config.AccessGroups {
+				addAGErr := d.AddMissingVolumesToVag(ctx, vag, vIDs)
+				if addAGErr != nil {
+					err = fmt.Errorf("failed to update AccessGroup membership of volume %+v", addAGErr)
+					return err
+				}
 			}
 		}
 	}
@@ -731,6 +744,8 @@ func (d *SANStorageDriver) validate(ctx context.Context) error {
 
 	if d.Config.UseCHAP {
 		Logc(ctx).WithFields(fields).Debug("Using CHAP, skipped Volume Access Group logic.")
+	} else if d.Config.DriverContext == tridentconfig.ContextCSI {
+		Logc(ctx).WithFields(fields).Info("Volumes will be added to the access groups of the nodes they are published to.")
 	} else {
 		Logc(ctx).WithFields(fields).Info("Please ensure all relevant hosts are added to one of the specified Volume Access Groups.")
 	}
@@ -1192,29 +1207,219 @@ func (d *SANStorageDriver) Publish(
 		fstype = str
 	}
 
-	// Get the account, which contains the iSCSI login credentials
-	var req api.GetAccountByIDRequest
-	req.AccountID = v.AccountID
-	account, err := d.Client.GetAccountByID(ctx, &req)
-	if err != nil {
-		Logc(ctx).Errorf("Failed to get account %v: %+v ", v.AccountID, err)
-		return errors.New("volume attach failure")
+	if d.Config.UseCHAP {
+		// Get the account, which contains the iSCSI login credentials
+		var req api.GetAccountByIDRequest
+		req.AccountID = v.AccountID
+		account, err := d.Client.GetAccountByID(ctx, &req)
+		if err != nil {
+			Logc(ctx).Errorf("Failed to get account %v: %+v ", v.AccountID, err)
+			return errors.New("volume attach failure")
+		}
+		publishInfo.IscsiUsername = account.Username
+		publishInfo.IscsiInitiatorSecret = account.InitiatorSecret
+	} else if d.Config.DriverContext == tridentconfig.ContextCSI && !publishInfo.Localhost {
+		// Limit access to the volume to the nodes it is published to
+		if err = d.addVolumeToNodeVAG(ctx, &v, publishInfo); err != nil {
+			return err
+		}
 	}
 
 	// Add fields needed by Attach
 	publishInfo.IscsiLunNumber = 0
 	publishInfo.IscsiTargetPortal = d.Config.SVIP
 	publishInfo.IscsiTargetIQN = v.Iqn
-	publishInfo.IscsiUsername = account.Username
-	publishInfo.IscsiInitiatorSecret = account.InitiatorSecret
 	publishInfo.IscsiInterface = d.InitiatorIFace
 	publishInfo.FilesystemType = fstype
-	publishInfo.UseCHAP = true
+	publishInfo.UseCHAP = d.Config.UseCHAP
 	publishInfo.SharedTarget = false
 
 	return nil
 }
 
+// Unpublish the volume from the host specified in publishInfo.  This method may or may not be running on the host
+// where the volume was mounted, so it should limit itself to updating access rules, initiator groups, etc.
+// that require some host identity (but not locality) as well as storage controller API access.
+// Volumes published using CHAP are accessible with the tenant's credentials from any node, so only volumes
+// published using access groups in CSI mode are removed from the node's access group.
+func (d *SANStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	name := volConfig.InternalName
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+			"node":   publishInfo.HostName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	if d.Config.DriverContext != tridentconfig.ContextCSI || d.Config.UseCHAP {
+		return nil
+	}
+
+	v, err := d.GetVolume(ctx, name)
+	if err != nil {
+		Logc(ctx).WithField("volume", name).Warn("Volume not found, nothing to unpublish.")
+		return nil
+	}
+
+	defer nodeVAGLocks.Lock(getNodeVAGName(publishInfo.HostName))()
+
+	vag, err := d.getNodeVAG(ctx, publishInfo)
+	if err != nil {
+		return err
+	}
+	if vag == nil {
+		Logc(ctx).WithField("node", publishInfo.HostName).Debug("No volume access group found for node.")
+		return nil
+	}
+
+	// Leave volumes alone in the access groups listed in the backend config, which may be shared by other nodes
+	for _, vagID := range d.Config.AccessGroups {
+		if vagID == vag.VAGID {
+			return nil
+		}
+	}
+
+	volumeInVAG := false
+	for _, volumeID := range vag.Volumes {
+		if volumeID == v.VolumeID {
+			volumeInVAG = true
+			break
+		}
+	}
+
+	if volumeInVAG {
+		req := &api.RemoveVolumesFromVolumeAccessGroupRequest{
+			VolumeAccessGroupID: vag.VAGID,
+			Volumes:             []int64{v.VolumeID},
+		}
+		if err = d.Client.RemoveVolumesFromAccessGroup(ctx, req); err != nil {
+			return fmt.Errorf("could not remove SolidFire volume %s from VAG %s: %v", name, vag.Name, err)
+		}
+		Logc(ctx).WithFields(log.Fields{
+			"volume": name,
+			"VAG":    vag.Name,
+		}).Debug("Removed volume from VAG.")
+	}
+
+	// Delete the node's access group once it no longer grants access to any volumes
+	if vag.Name == getNodeVAGName(publishInfo.HostName) &&
+		(len(vag.Volumes) == 0 || (len(vag.Volumes) == 1 && vag.Volumes[0] == v.VolumeID)) {
+		if err = d.Client.DeleteVolumeAccessGroup(ctx, &api.DeleteVolumeAccessGroupRequest{VAGID: vag.VAGID}); err != nil {
+			Logc(ctx).WithField("VAG", vag.Name).Warnf("Could not delete volume access group; %v", err)
+		}
+	}
+
+	return nil
+}
+
+// getNodeVAGName returns the name of the volume access group that Trident creates for a node.
+// Names that would exceed the SolidFire limit use a hash of the node name instead.
+func getNodeVAGName(nodeName string) string {
+
+	name := tridentconfig.DefaultSolidFireVAG + "-" + nodeName
+	if len(name) > maxVAGNameLength {
+		hash := sha256.Sum256([]byte(nodeName))
+		name = tridentconfig.DefaultSolidFireVAG + "-" + hex.EncodeToString(hash[:])[:16]
+	}
+	return name
+}
+
+// getNodeVAG returns the volume access group that contains the initiator of the node specified in
+// publishInfo, or the group Trident created for the node.  If neither exists, it returns (nil, nil).
+func (d *SANStorageDriver) getNodeVAG(
+	ctx context.Context, publishInfo *utils.VolumePublishInfo,
+) (*api.VolumeAccessGroup, error) {
+
+	vags, err := d.Client.ListVolumeAccessGroups(ctx, &api.ListVolumeAccessGroupsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("could not list VAGs for backend %s: %v", d.Config.SVIP, err)
+	}
+
+	vagName := getNodeVAGName(publishInfo.HostName)
+	var namedVAG *api.VolumeAccessGroup
+
+	for i, vag := range vags {
+		for _, initiator := range vag.Initiators {
+			for _, iqn := range publishInfo.HostIQN {
+				if iqn != "" && strings.EqualFold(initiator, iqn) {
+					return &vags[i], nil
+				}
+			}
+		}
+		if vag.Name == vagName {
+			namedVAG = &vags[i]
+		}
+	}
+
+	return namedVAG, nil
+}
+
+// addVolumeToNodeVAG adds a volume to the volume access group of the node specified in publishInfo,
+// first creating the group and adding the node's initiator to it as needed.
+func (d *SANStorageDriver) addVolumeToNodeVAG(
+	ctx context.Context, v *api.Volume, publishInfo *utils.VolumePublishInfo,
+) error {
+
+	if len(publishInfo.HostIQN) == 0 || publishInfo.HostIQN[0] == "" {
+		return fmt.Errorf("unknown initiator for node %s", publishInfo.HostName)
+	}
+	iqn := publishInfo.HostIQN[0]
+
+	vagName := getNodeVAGName(publishInfo.HostName)
+	defer nodeVAGLocks.Lock(vagName)()
+
+	vag, err := d.getNodeVAG(ctx, publishInfo)
+	if err != nil {
+		return err
+	}
+
+	if vag == nil {
+		req := &api.CreateVolumeAccessGroupRequest{
+			Name:       vagName,
+			Volumes:    []int64{v.VolumeID},
+			Initiators: []string{iqn},
+		}
+		vagID, err := d.Client.CreateVolumeAccessGroup(ctx, req)
+		if err != nil {
+			return fmt.Errorf("could not create VAG %s: %v", vagName, err)
+		}
+		Logc(ctx).WithFields(log.Fields{
+			"VAG":    vagName,
+			"VAGID":  vagID,
+			"volume": v.Name,
+		}).Debug("Created volume access group for node.")
+		return nil
+	}
+
+	if !utils.SliceContainsString(vag.Initiators, iqn) {
+		req := &api.AddInitiatorsToVolumeAccessGroupRequest{
+			Initiators: []string{iqn},
+			VAGID:      vag.VAGID,
+		}
+		if err = d.Client.AddInitiatorsToVolumeAccessGroup(ctx, req); err != nil {
+			return fmt.Errorf("could not add initiator %s to VAG %s: %v", iqn, vag.Name, err)
+		}
+	}
+
+	req := &api.AddVolumesToVolumeAccessGroupRequest{
+		VolumeAccessGroupID: vag.VAGID,
+		Volumes:             []int64{v.VolumeID},
+	}
+	if err = d.Client.AddVolumesToAccessGroup(ctx, req); err != nil {
+		return fmt.Errorf("could not map SolidFire volume %s to VAG %s: %v", v.Name, vag.Name, err)
+	}
+
+	return nil
+}
+
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
 func (d *SANStorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig) error {
 	return nil
@@ -1592,6 +1797,31 @@ func (d *SANStorageDriver) GetStorageBackendPhysicalPoolNames(context.Context) [
 	return []string{}
 }
 
+// GetCapacity returns the provisioning capacity of the SolidFire cluster, which backs every storage pool
+func (d *SANStorageDriver) GetCapacity(
+	ctx context.Context, storagePool *storage.Pool, _ map[string]sa.Request,
+) (map[string]*storage.PoolCapacity, error) {
+
+	if _, ok := d.virtualPools[storagePool.Name]; !ok {
+		return nil, fmt.Errorf("could not find pool %s", storagePool.Name)
+	}
+
+	clusterCapacity, err := d.Client.GetClusterCapacity(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get cluster capacity; %v", err)
+	}
+
+	capacity := &storage.PoolCapacity{}
+	if clusterCapacity.MaxOverProvisionableSpace > 0 {
+		capacity.TotalBytes = uint64(clusterCapacity.MaxOverProvisionableSpace)
+	}
+	if available := clusterCapacity.MaxOverProvisionableSpace - clusterCapacity.ProvisionedSpace; available > 0 {
+		capacity.AvailableBytes = uint64(available)
+	}
+
+	return map[string]*storage.PoolCapacity{d.Config.SVIP: capacity}, nil
+}
+
 func (d *SANStorageDriver) GetInternalVolumeName(ctx context.Context, name string) string {
 
 	if tridentconfig.UsingPassthroughStore {
@@ -1675,16 +1905,19 @@ func (d *SANStorageDriver) mapSolidfireLun(ctx context.Context, volConfig *stora
 		volConfig.AccessInfo.IscsiTargetSecret = a.TargetSecret
 	} else {
 
-		volumeIDList := []int64{v.VolumeID}
-		for _, vagID := range d.Config.AccessGroups {
-			req := api.AddVolumesToVolumeAccessGroupRequest{
-				VolumeAccessGroupID: vagID,
-				Volumes:             volumeIDList,
-			}
+		// In CSI mode, volumes are added to the access groups of the nodes they are published to
+		if d.Config.DriverContext != tridentconfig.ContextCSI {
+			volumeIDList := []int64{v.VolumeID}
+			for _, vagID := range d.Config.AccessGroups {
+				req := api.AddVolumesToVolumeAccessGroupRequest{
+					VolumeAccessGroupID: vagID,
+					Volumes:             volumeIDList,
+				}
 
-			err = d.Client.AddVolumesToAccessGroup(ctx, &req)
-			if err != nil {
-				return fmt.Errorf("could not map SolidFire volume %s to the VAG: %s", name, err.Error())
+				err = d.Client.AddVolumesToAccessGroup(ctx, &req)
+				if err != nil {
+					return fmt.Errorf("could not map SolidFire volume %s to the VAG: %s", name, err.Error())
+				}
 			}
 		}
 
@@ -1996,6 +2229,24 @@ func (d *SANStorageDriver) Resize(ctx context.Context, volConfig *storage.Volume
 	return nil
 }
 
+// Modify changes the attributes of an existing volume, which is not supported by this driver.
+func (d *SANStorageDriver) Modify(
+	ctx context.Context, volConfig *storage.VolumeConfig, modification *storage.VolumeModification,
+) error {
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Modify",
+			"Type":   "SANStorageDriver",
+			"name":   volConfig.InternalName,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Modify")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Modify")
+	}
+
+	return utils.UnsupportedError(fmt.Sprintf("volume modification is not supported by backend type %s", d.Name()))
+}
+
 func (d *SANStorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node, _ string) error {
 
 	nodeNames := make([]string, 0)
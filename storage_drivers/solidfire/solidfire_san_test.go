@@ -4,12 +4,20 @@ package solidfire
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
 	drivers "github.com/netapp/trident/storage_drivers"
 	"github.com/netapp/trident/storage_drivers/solidfire/api"
+	"github.com/netapp/trident/utils"
 )
 
 const (
@@ -114,6 +122,197 @@ func TestSolidfireSANStorageDriverConfigString(t *testing.T) {
 	}
 }
 
+func TestGetNodeVAGName(t *testing.T) {
+
+	assert.Equal(t, "trident-node1", getNodeVAGName("node1"), "Unexpected VAG name")
+
+	longNodeName := strings.Repeat("n", 100)
+	name := getNodeVAGName(longNodeName)
+	assert.LessOrEqual(t, len(name), maxVAGNameLength, "VAG name too long")
+	assert.True(t, strings.HasPrefix(name, "trident-"), "VAG name lacks prefix")
+	assert.NotEqual(t, name, getNodeVAGName(longNodeName+"2"), "VAG names collide")
+}
+
+// testElementCluster is a fake Element cluster that serves the JSON-RPC requests used to publish its
+// volumes through volume access groups.
+type testElementCluster struct {
+	mutex     sync.Mutex
+	volumes   []api.Volume
+	vags      []api.VolumeAccessGroup
+	nextVAGID int64
+}
+
+func (c *testElementCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var request struct {
+		Method string          `json:"method"`
+		ID     int             `json:"id"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var result interface{} = struct{}{}
+
+	switch request.Method {
+	case "ListVolumesForAccount":
+		result = map[string]interface{}{"volumes": c.volumes}
+	case "ListVolumeAccessGroups":
+		result = map[string]interface{}{"volumeAccessGroups": c.vags}
+	case "CreateVolumeAccessGroup":
+		var params api.CreateVolumeAccessGroupRequest
+		_ = json.Unmarshal(request.Params, &params)
+		c.nextVAGID++
+		c.vags = append(c.vags, api.VolumeAccessGroup{
+			Name:       params.Name,
+			VAGID:      c.nextVAGID,
+			Initiators: params.Initiators,
+			Volumes:    params.Volumes,
+		})
+		result = map[string]interface{}{"volumeAccessGroupID": c.nextVAGID}
+	case "AddInitiatorsToVolumeAccessGroup":
+		var params api.AddInitiatorsToVolumeAccessGroupRequest
+		_ = json.Unmarshal(request.Params, &params)
+		if vag := c.getVAG(params.VAGID); vag != nil {
+			vag.Initiators = append(vag.Initiators, params.Initiators...)
+		}
+	case "AddVolumesToVolumeAccessGroup":
+		var params api.AddVolumesToVolumeAccessGroupRequest
+		_ = json.Unmarshal(request.Params, &params)
+		if vag := c.getVAG(params.VolumeAccessGroupID); vag != nil {
+			vag.Volumes = append(vag.Volumes, params.Volumes...)
+		}
+	case "RemoveVolumesFromVolumeAccessGroup":
+		var params api.RemoveVolumesFromVolumeAccessGroupRequest
+		_ = json.Unmarshal(request.Params, &params)
+		if vag := c.getVAG(params.VolumeAccessGroupID); vag != nil {
+			removed := make(map[int64]bool)
+			for _, volumeID := range params.Volumes {
+				removed[volumeID] = true
+			}
+			var volumes []int64
+			for _, volumeID := range vag.Volumes {
+				if !removed[volumeID] {
+					volumes = append(volumes, volumeID)
+				}
+			}
+			vag.Volumes = volumes
+		}
+	case "DeleteVolumeAccessGroup":
+		var params api.DeleteVolumeAccessGroupRequest
+		_ = json.Unmarshal(request.Params, &params)
+		for i, vag := range c.vags {
+			if vag.VAGID == params.VAGID {
+				c.vags = append(c.vags[:i], c.vags[i+1:]...)
+				break
+			}
+		}
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": request.ID, "result": result})
+}
+
+// getVAG returns the volume access group with the specified ID.  The caller must hold c.mutex.
+func (c *testElementCluster) getVAG(vagID int64) *api.VolumeAccessGroup {
+	for i := range c.vags {
+		if c.vags[i].VAGID == vagID {
+			return &c.vags[i]
+		}
+	}
+	return nil
+}
+
+// newTestSolidfireSANDriverWithCluster returns a CSI driver that uses the specified access groups of a fake
+// Element cluster, rather than CHAP.
+func newTestSolidfireSANDriverWithCluster(
+	t *testing.T, cluster *testElementCluster, accessGroups []int64,
+) *SANStorageDriver {
+
+	server := httptest.NewTLSServer(cluster)
+	t.Cleanup(server.Close)
+
+	driver := newTestSolidfireSANDriver()
+	driver.Config.DriverContext = tridentconfig.ContextCSI
+	driver.Config.UseCHAP = false
+	driver.Config.AccessGroups = accessGroups
+	driver.Client.Endpoint = server.URL
+
+	return driver
+}
+
+func TestSolidfireSANStorageDriverPublishToNodeVAG(t *testing.T) {
+
+	ctx := context.Background()
+	cluster := &testElementCluster{
+		volumes:   []api.Volume{{VolumeID: 10, Name: "pvc-1", Status: "active", Iqn: "iqn.2010-01.com.solidfire:pvc-1"}},
+		vags:      []api.VolumeAccessGroup{{Name: "trident", VAGID: 1}},
+		nextVAGID: 1,
+	}
+	driver := newTestSolidfireSANDriverWithCluster(t, cluster, []int64{1})
+
+	volConfig := &storage.VolumeConfig{InternalName: "pvc-1"}
+	publishInfo := &utils.VolumePublishInfo{
+		HostName: "node1",
+		HostIQN:  []string{"iqn.1993-08.org.debian:01:node1"},
+	}
+
+	assert.NoError(t, driver.Publish(ctx, volConfig, publishInfo))
+	assert.False(t, publishInfo.UseCHAP, "volume published using CHAP")
+
+	cluster.mutex.Lock()
+	if assert.Len(t, cluster.vags, 2, "node VAG not created") {
+		assert.Equal(t, "trident-node1", cluster.vags[1].Name)
+		assert.Equal(t, []string{"iqn.1993-08.org.debian:01:node1"}, cluster.vags[1].Initiators)
+		assert.Equal(t, []int64{10}, cluster.vags[1].Volumes)
+	}
+	assert.Empty(t, cluster.vags[0].Volumes, "volume added to configured VAG")
+	cluster.mutex.Unlock()
+
+	assert.NoError(t, driver.Unpublish(ctx, volConfig, publishInfo))
+
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+	assert.Len(t, cluster.vags, 1, "unused node VAG not deleted")
+}
+
+func TestSolidfireSANStorageDriverPublishToConfiguredVAG(t *testing.T) {
+
+	ctx := context.Background()
+	cluster := &testElementCluster{
+		volumes: []api.Volume{{VolumeID: 10, Name: "pvc-1", Status: "active", Iqn: "iqn.2010-01.com.solidfire:pvc-1"}},
+		vags: []api.VolumeAccessGroup{{
+			Name:       "trident",
+			VAGID:      1,
+			Initiators: []string{"iqn.1993-08.org.debian:01:node1", "iqn.1993-08.org.debian:01:node2"},
+		}},
+		nextVAGID: 1,
+	}
+	driver := newTestSolidfireSANDriverWithCluster(t, cluster, []int64{1})
+
+	volConfig := &storage.VolumeConfig{InternalName: "pvc-1"}
+	publishInfo := &utils.VolumePublishInfo{
+		HostName: "node1",
+		HostIQN:  []string{"iqn.1993-08.org.debian:01:node1"},
+	}
+
+	// A node already in a configured VAG uses that group, which other nodes may share
+	assert.NoError(t, driver.Publish(ctx, volConfig, publishInfo))
+	assert.NoError(t, driver.Unpublish(ctx, volConfig, publishInfo))
+
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+	assert.Len(t, cluster.vags, 1, "node VAG created")
+	assert.Equal(t, []int64{10}, cluster.vags[0].Volumes, "volume removed from configured VAG")
+}
+
 func TestValidateStoragePrefix(t *testing.T) {
 	tests := []struct {
 		Name          string
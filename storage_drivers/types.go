@@ -241,6 +241,9 @@ type OntapStorageDriverConfig struct {
 	LimitAggregateUsage              string   `json:"limitAggregateUsage"`
 	AutoExportPolicy                 bool     `json:"autoExportPolicy"`
 	AutoExportCIDRs                  []string `json:"autoExportCIDRs"`
+	PerVolumeExportPolicy            bool     `json:"perVolumeExportPolicy"`
+	UseREST                          bool     `json:"useREST"`
+	SANType                          string   `json:"sanType"` // iscsi or nvme, default to iscsi
 	OntapStorageDriverPool
 	Storage                   []OntapStorageDriverPool `json:"storage"`
 	UseCHAP                   bool                     `json:"useCHAP"`
@@ -0,0 +1,203 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros are the shorthand schedules accepted in place of the five cron fields
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describes the values allowed in one field of a cron schedule
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names for the values starting at min, if any
+}
+
+var (
+	cronMinuteField = cronField{name: "minute", min: 0, max: 59}
+	cronHourField   = cronField{name: "hour", min: 0, max: 23}
+	cronDayField    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonthField  = cronField{name: "month", min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	// Both 0 and 7 are Sunday
+	cronWeekdayField = cronField{name: "day of week", min: 0, max: 7, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// cronSearchLimit bounds the search for the next time matching a schedule, which must be long enough
+// to include a leap day.
+const cronSearchLimit = 5
+
+// CronSchedule is a schedule in the five-field format of cron: minute, hour, day of month, month,
+// and day of week.  As in cron, a time matches if it matches either the day of month or the day of
+// week when both are restricted.
+type CronSchedule struct {
+	minutes, hours, days, months, weekdays uint64
+	daysRestricted, weekdaysRestricted     bool
+}
+
+// ParseCronSchedule parses a cron schedule, which may also be one of the macros such as @daily.
+func ParseCronSchedule(spec string) (*CronSchedule, error) {
+
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron schedule '%s' must have 5 fields", spec)
+	}
+
+	var err error
+	schedule := &CronSchedule{
+		daysRestricted:     !strings.HasPrefix(fields[2], "*"),
+		weekdaysRestricted: !strings.HasPrefix(fields[4], "*"),
+	}
+	if schedule.minutes, err = parseCronField(fields[0], cronMinuteField); err != nil {
+		return nil, err
+	}
+	if schedule.hours, err = parseCronField(fields[1], cronHourField); err != nil {
+		return nil, err
+	}
+	if schedule.days, err = parseCronField(fields[2], cronDayField); err != nil {
+		return nil, err
+	}
+	if schedule.months, err = parseCronField(fields[3], cronMonthField); err != nil {
+		return nil, err
+	}
+	if schedule.weekdays, err = parseCronField(fields[4], cronWeekdayField); err != nil {
+		return nil, err
+	}
+	if schedule.weekdays&(1<<7) != 0 {
+		schedule.weekdays |= 1
+	}
+
+	// Reject schedules such as February 30th that never match
+	if schedule.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("cron schedule '%s' never matches", spec)
+	}
+
+	return schedule, nil
+}
+
+// parseCronField parses one field of a cron schedule into a set of bits, one for each value the
+// field matches.
+func parseCronField(field string, spec cronField) (uint64, error) {
+
+	var bits uint64
+
+	for _, item := range strings.Split(field, ",") {
+
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			rangePart = item[:i]
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in %s field '%s'", spec.name, field)
+			}
+		}
+
+		var first, last int
+		switch {
+		case rangePart == "*":
+			first, last = spec.min, spec.max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if first, err = parseCronValue(bounds[0], spec); err != nil {
+				return 0, err
+			}
+			if last, err = parseCronValue(bounds[1], spec); err != nil {
+				return 0, err
+			}
+			if first > last {
+				return 0, fmt.Errorf("invalid range in %s field '%s'", spec.name, field)
+			}
+		default:
+			var err error
+			if first, err = parseCronValue(rangePart, spec); err != nil {
+				return 0, err
+			}
+			last = first
+			// A single value with a step, such as 5/15, runs to the end of the field's range
+			if step > 1 {
+				last = spec.max
+			}
+		}
+
+		for value := first; value <= last; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+// parseCronValue parses a number or name in a field of a cron schedule.
+func parseCronValue(value string, spec cronField) (int, error) {
+
+	for i, name := range spec.names {
+		if strings.EqualFold(value, name) {
+			return spec.min + i, nil
+		}
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < spec.min || number > spec.max {
+		return 0, fmt.Errorf("invalid %s '%s'", spec.name, value)
+	}
+	return number, nil
+}
+
+// Next returns the first time after t that matches the schedule, in t's location, or the zero time
+// if the schedule does not match any time in the following few years.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(cronSearchLimit, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.months&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hours&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minutes&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchesDay returns true if the day of t matches the schedule's day of month and day of week.
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+
+	dayMatches := s.days&(1<<uint(t.Day())) != 0
+	weekdayMatches := s.weekdays&(1<<uint(t.Weekday())) != 0
+
+	if s.daysRestricted && s.weekdaysRestricted {
+		return dayMatches || weekdayMatches
+	}
+	return dayMatches && weekdayMatches
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		spec  string
+		valid bool
+	}{
+		{"every minute", "* * * * *", true},
+		{"lists, ranges and steps", "0,30 8-18/2 1-15 */3 mon-fri", true},
+		{"names", "0 0 * JAN,jul SUN", true},
+		{"sunday as seven", "0 0 * * 7", true},
+		{"macro", "@daily", true},
+		{"too few fields", "0 0 * *", false},
+		{"too many fields", "0 0 * * * *", false},
+		{"minute out of range", "60 * * * *", false},
+		{"day out of range", "0 0 0 * *", false},
+		{"backwards range", "0 18-8 * * *", false},
+		{"zero step", "*/0 * * * *", false},
+		{"unknown name", "0 0 * * someday", false},
+		{"unknown macro", "@fortnightly", false},
+		{"never matches", "0 0 30 2 *", false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		_, err := ParseCronSchedule(test.spec)
+		if test.valid {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Error(t, err, test.name)
+		}
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+
+	// A Friday
+	start := time.Date(2021, 4, 30, 10, 17, 42, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		spec     string
+		expected time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2021, 4, 30, 10, 18, 0, 0, time.UTC)},
+		{"hourly", "@hourly", time.Date(2021, 4, 30, 11, 0, 0, 0, time.UTC)},
+		{"every fifteen minutes", "*/15 * * * *", time.Date(2021, 4, 30, 10, 30, 0, 0, time.UTC)},
+		{"daily", "@daily", time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"weekly", "@weekly", time.Date(2021, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{"weekdays", "30 9 * * mon-fri", time.Date(2021, 5, 3, 9, 30, 0, 0, time.UTC)},
+		{"day of month or week", "0 0 15 * fri", time.Date(2021, 5, 7, 0, 0, 0, 0, time.UTC)},
+		{"next year", "0 0 1 jan *", time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		schedule, err := ParseCronSchedule(test.spec)
+		if assert.NoError(t, err, test.name) {
+			assert.Equal(t, test.expected, schedule.Next(start), test.name)
+		}
+	}
+}
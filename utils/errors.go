@@ -108,6 +108,35 @@ func IsNotReadyError(err error) bool {
 	return ok
 }
 
+/////////////////////////////////////////////////////////////////////////////
+// notLeaderError
+/////////////////////////////////////////////////////////////////////////////
+
+type notLeaderError struct {
+	message string
+}
+
+func (e *notLeaderError) Error() string { return e.message }
+
+func NotLeaderError(leader string) error {
+	if leader == "" {
+		return &notLeaderError{
+			"Trident is running as a standby and no leader has been elected, please try again later",
+		}
+	}
+	return &notLeaderError{
+		fmt.Sprintf("Trident is running as a standby, please send requests to the leader %s", leader),
+	}
+}
+
+func IsNotLeaderError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(*notLeaderError)
+	return ok
+}
+
 /////////////////////////////////////////////////////////////////////////////
 // unsupportedError
 /////////////////////////////////////////////////////////////////////////////
@@ -174,6 +203,28 @@ func IsVolumeDeletingError(err error) bool {
 	return ok
 }
 
+/////////////////////////////////////////////////////////////////////////////
+// volumeRelocatingError
+/////////////////////////////////////////////////////////////////////////////
+
+type volumeRelocatingError struct {
+	message string
+}
+
+func (e *volumeRelocatingError) Error() string { return e.message }
+
+func VolumeRelocatingError(message string) error {
+	return &volumeRelocatingError{message}
+}
+
+func IsVolumeRelocatingError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(*volumeRelocatingError)
+	return ok
+}
+
 /////////////////////////////////////////////////////////////////////////////
 // timeoutError
 /////////////////////////////////////////////////////////////////////////////
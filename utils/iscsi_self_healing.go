@@ -0,0 +1,153 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+// ISCSISelfHealingResult describes the state of a staged iSCSI volume's sessions and paths, along with
+// any repairs made to them.
+type ISCSISelfHealingResult struct {
+	// MissingPortals are the portals to which there was no session to the volume's target
+	MissingPortals []string
+	// RestoredPortals are the missing portals to which a session was established
+	RestoredPortals []string
+	// RunningPaths is the number of paths to the LUN whose SCSI devices are running
+	RunningPaths int
+	// ExpectedPaths is the number of paths expected from the portals through which the LUN was published
+	ExpectedPaths int
+	// MultipathReloaded is true if the LUN's multipath device was reloaded to pick up restored paths
+	MultipathReloaded bool
+}
+
+// Degraded returns true if the LUN has fewer running paths than expected.
+func (r *ISCSISelfHealingResult) Degraded() bool {
+	return r.RunningPaths < r.ExpectedPaths
+}
+
+// Repaired returns true if any sessions were restored or the multipath device was reloaded.
+func (r *ISCSISelfHealingResult) Repaired() bool {
+	return len(r.RestoredPortals) > 0 || r.MultipathReloaded
+}
+
+// HealISCSIVolume ensures that a staged iSCSI volume has sessions to all of the portals through which it
+// was published, logging in to any that are missing.  If any sessions were restored, or if any of the
+// LUN's paths are missing or not running, the LUN is rescanned and its multipath device is reloaded so
+// that the restored paths are used.  This method must be able to accomplish its task using only the
+// publish info saved when the volume was staged.
+func HealISCSIVolume(ctx context.Context, publishInfo *VolumePublishInfo) (*ISCSISelfHealingResult, error) {
+
+	fields := log.Fields{"iSCSINodeName": publishInfo.IscsiTargetIQN, "lunID": publishInfo.IscsiLunNumber}
+	Logc(ctx).WithFields(fields).Debug(">>>> iscsi_self_healing.HealISCSIVolume")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< iscsi_self_healing.HealISCSIVolume")
+
+	var lunID = int(publishInfo.IscsiLunNumber)
+	var targetIQN = publishInfo.IscsiTargetIQN
+	var iscsiInterface = publishInfo.IscsiInterface
+
+	if iscsiInterface == "" {
+		iscsiInterface = "default"
+	}
+
+	var bkportal []string
+	var portalIps []string
+	bkportal = append(bkportal, ensureHostportFormatted(publishInfo.IscsiTargetPortal))
+	portalIps = append(portalIps, getHostportIP(publishInfo.IscsiTargetPortal))
+
+	for _, p := range publishInfo.IscsiPortals {
+		bkportal = append(bkportal, ensureHostportFormatted(p))
+		portalIps = append(portalIps, getHostportIP(p))
+	}
+
+	result := &ISCSISelfHealingResult{ExpectedPaths: len(bkportal)}
+
+	// Log in to any portals without a session, as is done when attaching the volume
+	if publishInfo.UseCHAP {
+		missingPortals, _, err := portalsToLogin(ctx, targetIQN, bkportal)
+		if err != nil {
+			return nil, err
+		}
+		result.MissingPortals = missingPortals
+
+		for _, portal := range missingPortals {
+			err = loginWithChap(ctx, targetIQN, portal, publishInfo.IscsiUsername, publishInfo.IscsiInitiatorSecret,
+				publishInfo.IscsiTargetUsername, publishInfo.IscsiTargetSecret, iscsiInterface)
+			if err != nil {
+				Logc(ctx).WithFields(log.Fields{
+					"err":    err,
+					"portal": portal,
+				}).Warning("Failed to restore session to portal using CHAP.")
+				continue
+			}
+			result.RestoredPortals = append(result.RestoredPortals, portal)
+		}
+	} else {
+		missingPortalIps, _, err := portalsIpsToLogin(ctx, targetIQN, portalIps)
+		if err != nil {
+			return nil, err
+		}
+		result.MissingPortals = missingPortalIps
+
+		if len(missingPortalIps) > 0 {
+			EnsureISCSISessions(ctx, targetIQN, iscsiInterface, missingPortalIps)
+
+			stillMissingPortalIps, _, err := portalsIpsToLogin(ctx, targetIQN, missingPortalIps)
+			if err != nil {
+				return nil, err
+			}
+			result.RestoredPortals = restoredPortals(missingPortalIps, stillMissingPortalIps)
+		}
+	}
+
+	hostSessionMap := GetISCSIHostSessionMapForTarget(ctx, targetIQN)
+	paths := getSysfsBlockDirsForLUN(lunID, hostSessionMap)
+	result.RunningPaths = countRunningSCSIDevices(paths)
+
+	if len(result.RestoredPortals) == 0 && len(paths) == len(hostSessionMap) && !result.Degraded() {
+		return result, nil
+	}
+
+	// Discover the LUN on any new sessions, then have multipathd add the new paths to the map
+	hosts := make([]int, 0, len(hostSessionMap))
+	for hostNumber := range hostSessionMap {
+		hosts = append(hosts, hostNumber)
+	}
+	if err := iSCSIScanTargetLUN(ctx, lunID, hosts); err != nil {
+		return result, err
+	}
+	time.Sleep(time.Second)
+
+	deviceInfo, err := getDeviceInfoForLUN(ctx, lunID, targetIQN, false)
+	if err != nil {
+		return result, err
+	}
+	if deviceInfo != nil && deviceInfo.MultipathDevice != "" {
+		if err = reloadMultipathDevice(ctx, deviceInfo.MultipathDevice); err != nil {
+			return result, err
+		}
+		result.MultipathReloaded = true
+	}
+
+	paths = getSysfsBlockDirsForLUN(lunID, GetISCSIHostSessionMapForTarget(ctx, targetIQN))
+	result.RunningPaths = countRunningSCSIDevices(paths)
+
+	return result, nil
+}
+
+// restoredPortals returns the portals that were missing a session but are no longer.
+func restoredPortals(missingPortals, stillMissingPortals []string) []string {
+
+	restored := make([]string, 0)
+	for _, portal := range missingPortals {
+		if !SliceContainsString(stillMissingPortals, portal) {
+			restored = append(restored, portal)
+		}
+	}
+	return restored
+}
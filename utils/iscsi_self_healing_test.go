@@ -0,0 +1,66 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestoredPortals(t *testing.T) {
+
+	tests := []struct {
+		name                string
+		missingPortals      []string
+		stillMissingPortals []string
+		expected            []string
+	}{
+		{"allRestored", []string{"10.0.0.1", "10.0.0.2"}, nil, []string{"10.0.0.1", "10.0.0.2"}},
+		{"someRestored", []string{"10.0.0.1", "10.0.0.2"}, []string{"10.0.0.2"}, []string{"10.0.0.1"}},
+		{"noneRestored", []string{"10.0.0.1"}, []string{"10.0.0.1"}, []string{}},
+		{"noneMissing", nil, nil, []string{}},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		assert.Equal(t, test.expected, restoredPortals(test.missingPortals, test.stillMissingPortals))
+	}
+}
+
+func TestISCSISelfHealingResult(t *testing.T) {
+
+	tests := []struct {
+		name             string
+		result           ISCSISelfHealingResult
+		expectedDegraded bool
+		expectedRepaired bool
+	}{
+		{"healthy", ISCSISelfHealingResult{RunningPaths: 2, ExpectedPaths: 2}, false, false},
+		{"degraded", ISCSISelfHealingResult{RunningPaths: 1, ExpectedPaths: 2}, true, false},
+		{
+			"restored",
+			ISCSISelfHealingResult{
+				MissingPortals:    []string{"10.0.0.2"},
+				RestoredPortals:   []string{"10.0.0.2"},
+				RunningPaths:      2,
+				ExpectedPaths:     2,
+				MultipathReloaded: true,
+			},
+			false, true,
+		},
+		{
+			"notRestored",
+			ISCSISelfHealingResult{MissingPortals: []string{"10.0.0.2"}, RunningPaths: 1, ExpectedPaths: 2},
+			true, false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		assert.Equal(t, test.expectedDegraded, test.result.Degraded())
+		assert.Equal(t, test.expectedRepaired, test.result.Repaired())
+	}
+}
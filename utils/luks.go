@@ -0,0 +1,174 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+const (
+	luksDeviceMapperDir = "/dev/mapper"
+	luksDevicePrefix    = "luks-"
+	luksFstype          = "crypto_LUKS"
+)
+
+// parseLUKSEncryption returns whether a volume's luksEncryption option is enabled.  An empty value means
+// the volume is not encrypted.
+func parseLUKSEncryption(luksEncryption string) (bool, error) {
+	if luksEncryption == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(luksEncryption)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for luksEncryption: %s", luksEncryption)
+	}
+	return enabled, nil
+}
+
+// LUKSDevicePath returns the path of the dm-crypt device through which the named volume is accessed
+// when it is encrypted with LUKS.
+func LUKSDevicePath(name string) string {
+	return path.Join(luksDeviceMapperDir, luksDevicePrefix+name)
+}
+
+// IsLUKSDevicePath returns true if the supplied path is that of a dm-crypt device opened by Trident.
+func IsLUKSDevicePath(devicePath string) bool {
+	return strings.HasPrefix(devicePath, path.Join(luksDeviceMapperDir, luksDevicePrefix))
+}
+
+// findDeviceForLUKSDevice returns the name of the device beneath a LUKS device opened by Trident, such as dm-0
+// for the dm-1 device of /dev/mapper/luks-<name>, or an empty string if the device is not such a LUKS device.
+func findDeviceForLUKSDevice(ctx context.Context, sysBlockDir, device string) string {
+
+	mapperName, err := ioutil.ReadFile(filepath.Join(sysBlockDir, device, "dm", "name"))
+	if err != nil || !strings.HasPrefix(strings.TrimSpace(string(mapperName)), luksDevicePrefix) {
+		return ""
+	}
+
+	slaves, err := ioutil.ReadDir(filepath.Join(sysBlockDir, device, "slaves"))
+	if err != nil || len(slaves) != 1 {
+		Logc(ctx).WithField("device", device).Debug("Could not find device for LUKS device.")
+		return ""
+	}
+
+	return slaves[0].Name()
+}
+
+// ensureLUKSDeviceOpen formats the supplied device with LUKS if it has not been formatted, and then opens
+// it through dm-crypt using the passphrase.  The path of the opened device is returned.  A device already
+// containing anything other than a LUKS header is never formatted, since that would destroy its data.
+func ensureLUKSDeviceOpen(
+	ctx context.Context, devicePath, existingFstype, name, passphrase string,
+) (string, error) {
+
+	luksDevicePath := LUKSDevicePath(name)
+
+	fields := log.Fields{"device": devicePath, "luksDevice": luksDevicePath}
+	Logc(ctx).WithFields(fields).Debug(">>>> luks.ensureLUKSDeviceOpen")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< luks.ensureLUKSDeviceOpen")
+
+	if PathExists(luksDevicePath) {
+		Logc(ctx).WithFields(fields).Debug("LUKS device already open.")
+		return luksDevicePath, nil
+	}
+
+	if passphrase == "" {
+		return "", fmt.Errorf("no LUKS passphrase provided for device %s", devicePath)
+	}
+
+	switch existingFstype {
+	case "":
+		Logc(ctx).WithFields(fields).Debug("Formatting device with LUKS.")
+		if err := luksFormat(ctx, devicePath, passphrase); err != nil {
+			return "", err
+		}
+	case luksFstype:
+		Logc(ctx).WithFields(fields).Debug("Device already formatted with LUKS.")
+	default:
+		return "", fmt.Errorf("device %s is already formatted with %s, cannot format it with LUKS",
+			devicePath, existingFstype)
+	}
+
+	if err := luksOpen(ctx, devicePath, luksDevicePrefix+name, passphrase); err != nil {
+		return "", err
+	}
+
+	if err := waitForDevice(ctx, luksDevicePath); err != nil {
+		return "", fmt.Errorf("could not find LUKS device %s; %v", luksDevicePath, err)
+	}
+
+	return luksDevicePath, nil
+}
+
+// luksFormat writes a LUKS header to a device.  The passphrase is passed on standard input so that it
+// never appears in the host's process table.
+func luksFormat(ctx context.Context, devicePath, passphrase string) error {
+
+	out, err := execCommandWithInput(ctx, "cryptsetup", passphrase,
+		"luksFormat", "--batch-mode", "--type", "luks2", "--key-file", "-", devicePath)
+	if err != nil {
+		return fmt.Errorf("could not format device %s with LUKS; %s; %v", devicePath,
+			strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// luksOpen opens a LUKS device through dm-crypt.  The volume key is kept in the device mapper table rather
+// than the kernel keyring, so that the device may later be resized without the passphrase.
+func luksOpen(ctx context.Context, devicePath, mapperName, passphrase string) error {
+
+	out, err := execCommandWithInput(ctx, "cryptsetup", passphrase,
+		"open", "--type", "luks", "--disable-keyring", "--key-file", "-", devicePath, mapperName)
+	if err != nil {
+		return fmt.Errorf("could not open LUKS device %s; %s; %v", devicePath,
+			strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// CloseLUKSDevice closes a dm-crypt device opened by ensureLUKSDeviceOpen.  It is not an error if the
+// device has already been closed.
+func CloseLUKSDevice(ctx context.Context, luksDevicePath string) error {
+
+	fields := log.Fields{"luksDevice": luksDevicePath}
+	Logc(ctx).WithFields(fields).Debug(">>>> luks.CloseLUKSDevice")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< luks.CloseLUKSDevice")
+
+	if !PathExists(luksDevicePath) {
+		Logc(ctx).WithFields(fields).Debug("LUKS device not open.")
+		return nil
+	}
+
+	out, err := execCommand(ctx, "cryptsetup", "close", path.Base(luksDevicePath))
+	if err != nil {
+		return fmt.Errorf("could not close LUKS device %s; %s; %v", luksDevicePath,
+			strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// ResizeLUKSDevice grows an open dm-crypt device to fill its underlying device, which must already have
+// been resized.
+func ResizeLUKSDevice(ctx context.Context, luksDevicePath string) error {
+
+	fields := log.Fields{"luksDevice": luksDevicePath}
+	Logc(ctx).WithFields(fields).Debug(">>>> luks.ResizeLUKSDevice")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< luks.ResizeLUKSDevice")
+
+	out, err := execCommand(ctx, "cryptsetup", "resize", path.Base(luksDevicePath))
+	if err != nil {
+		return fmt.Errorf("could not resize LUKS device %s; %s; %v", luksDevicePath,
+			strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLUKSEncryption(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		value         string
+		expected      bool
+		expectedError bool
+	}{
+		{"empty", "", false, false},
+		{"true", "true", true, false},
+		{"false", "false", false, false},
+		{"uppercase", "TRUE", true, false},
+		{"invalid", "yes please", false, true},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		enabled, err := parseLUKSEncryption(test.value)
+		assert.Equal(t, test.expected, enabled)
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
+func TestLUKSDevicePath(t *testing.T) {
+
+	devicePath := LUKSDevicePath("pvc-4cd7b5d9-8b0e-4f07-b7b5-7f0e3f6e1a2b")
+	assert.Equal(t, "/dev/mapper/luks-pvc-4cd7b5d9-8b0e-4f07-b7b5-7f0e3f6e1a2b", devicePath)
+	assert.True(t, IsLUKSDevicePath(devicePath))
+
+	assert.False(t, IsLUKSDevicePath("/dev/dm-3"))
+	assert.False(t, IsLUKSDevicePath("/dev/mapper/3600a098038303634722b4d59646c4436"))
+	assert.False(t, IsLUKSDevicePath(""))
+}
+
+func TestFindDeviceForLUKSDevice(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "sys-block")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// dm-1 is a LUKS device on multipath device dm-0, and dm-2 is some other device mapper device on dm-0
+	writeSysfsFile(t, filepath.Join(dir, "dm-0", "dm", "name"), "3600a098038303634722b4d59646c4436")
+	writeSysfsFile(t, filepath.Join(dir, "dm-0", "slaves", "sda"), "")
+	writeSysfsFile(t, filepath.Join(dir, "dm-0", "slaves", "sdb"), "")
+	writeSysfsFile(t, filepath.Join(dir, "dm-1", "dm", "name"), "luks-pvc-4cd7b5d9-8b0e-4f07-b7b5-7f0e3f6e1a2b")
+	writeSysfsFile(t, filepath.Join(dir, "dm-1", "slaves", "dm-0"), "")
+	writeSysfsFile(t, filepath.Join(dir, "dm-2", "dm", "name"), "vg0-lv0")
+	writeSysfsFile(t, filepath.Join(dir, "dm-2", "slaves", "dm-0"), "")
+
+	tests := []struct {
+		name     string
+		device   string
+		expected string
+	}{
+		{"luks", "dm-1", "dm-0"},
+		{"multipath", "dm-0", ""},
+		{"otherDeviceMapper", "dm-2", ""},
+		{"notDeviceMapper", "sda", ""},
+		{"missing", "dm-3", ""},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		assert.Equal(t, test.expected, findDeviceForLUKSDevice(context.Background(), dir, test.device))
+	}
+}
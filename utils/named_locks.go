@@ -0,0 +1,117 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"sync"
+)
+
+// NamedLocks is a set of read/write locks identified by name, such as one for each volume.  Unlike the
+// shared locks acquired by Lock, a named lock exists only while some caller holds it or is waiting for
+// it, so the set does not grow with every name ever locked.  As with sync.RWMutex, a caller waiting to
+// acquire a lock exclusively keeps new readers from acquiring it.  The zero value is ready to use.
+type NamedLocks struct {
+	mutex sync.Mutex
+	locks map[string]*namedLock
+}
+
+// namedLock is the state of one named lock, which is protected by the mutex of its NamedLocks.
+type namedLock struct {
+	changed        *sync.Cond // signaled whenever the lock is released
+	refs           int        // callers holding the lock or waiting for it
+	readers        int        // callers holding the lock for reading
+	writer         bool       // whether a caller holds the lock exclusively
+	writersWaiting int        // callers waiting to acquire the lock exclusively
+}
+
+// acquire returns the named lock, creating it if necessary, and adds a reference to it.  The caller
+// must hold l.mutex.
+func (l *NamedLocks) acquire(name string) *namedLock {
+
+	if l.locks == nil {
+		l.locks = make(map[string]*namedLock)
+	}
+	lock, ok := l.locks[name]
+	if !ok {
+		lock = &namedLock{changed: sync.NewCond(&l.mutex)}
+		l.locks[name] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+// release removes a reference to the named lock, discarding the lock once it is unreferenced.  The
+// caller must hold l.mutex.
+func (l *NamedLocks) release(name string, lock *namedLock) {
+	if lock.refs--; lock.refs <= 0 {
+		delete(l.locks, name)
+	}
+}
+
+// Lock waits for and exclusively acquires the named lock, and returns the function that releases it.
+func (l *NamedLocks) Lock(name string) func() {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	lock := l.acquire(name)
+	lock.writersWaiting++
+	for lock.writer || lock.readers > 0 {
+		lock.changed.Wait()
+	}
+	lock.writersWaiting--
+	lock.writer = true
+
+	return func() { l.unlock(name, lock) }
+}
+
+// TryLock exclusively acquires the named lock if no other caller holds it, and returns the function that
+// releases it.  The returned function is nil if the lock is held elsewhere.
+func (l *NamedLocks) TryLock(name string) func() {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	lock := l.acquire(name)
+	if lock.writer || lock.readers > 0 {
+		l.release(name, lock)
+		return nil
+	}
+	lock.writer = true
+
+	return func() { l.unlock(name, lock) }
+}
+
+// RLock waits for and acquires the named lock for reading, which any number of callers may do at once,
+// and returns the function that releases it.
+func (l *NamedLocks) RLock(name string) func() {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	lock := l.acquire(name)
+	for lock.writer || lock.writersWaiting > 0 {
+		lock.changed.Wait()
+	}
+	lock.readers++
+
+	return func() {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+
+		lock.readers--
+		lock.changed.Broadcast()
+		l.release(name, lock)
+	}
+}
+
+// unlock releases a named lock held exclusively.
+func (l *NamedLocks) unlock(name string, lock *namedLock) {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	lock.writer = false
+	lock.changed.Broadcast()
+	l.release(name, lock)
+}
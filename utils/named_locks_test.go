@@ -0,0 +1,119 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertNotAcquired fails the test if the acquired channel is closed within a short time.
+func assertNotAcquired(t *testing.T, acquired <-chan struct{}, message string) {
+	select {
+	case <-acquired:
+		t.Fatal(message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestNamedLocks(t *testing.T) {
+
+	var locks NamedLocks
+
+	unlock := locks.Lock("vol1")
+
+	// Other names are not affected
+	unlockOther := locks.TryLock("vol2")
+	assert.NotNil(t, unlockOther)
+	unlockOther()
+
+	// The held lock cannot be acquired without waiting
+	assert.Nil(t, locks.TryLock("vol1"))
+
+	acquired := make(chan struct{})
+	go func() {
+		defer locks.Lock("vol1")()
+		close(acquired)
+	}()
+
+	assertNotAcquired(t, acquired, "Acquired a named lock that was already held.")
+
+	unlock()
+	<-acquired
+
+	// Unreferenced locks are discarded
+	locks.mutex.Lock()
+	defer locks.mutex.Unlock()
+	assert.Empty(t, locks.locks)
+}
+
+func TestNamedLocksRLock(t *testing.T) {
+
+	var locks NamedLocks
+
+	// Any number of readers may hold a lock
+	unlockReader1 := locks.RLock("vol1")
+	unlockReader2 := locks.RLock("vol1")
+	assert.Nil(t, locks.TryLock("vol1"))
+
+	acquired := make(chan struct{})
+	go func() {
+		defer locks.Lock("vol1")()
+		close(acquired)
+	}()
+
+	// A waiting writer keeps new readers from acquiring the lock
+	assert.Eventually(t, func() bool {
+		locks.mutex.Lock()
+		defer locks.mutex.Unlock()
+		return locks.locks["vol1"].writersWaiting == 1
+	}, 5*time.Second, time.Millisecond)
+
+	readerAcquired := make(chan struct{})
+	go func() {
+		defer locks.RLock("vol1")()
+		close(readerAcquired)
+	}()
+
+	unlockReader1()
+	assertNotAcquired(t, acquired, "Acquired a named lock that was held for reading.")
+	assertNotAcquired(t, readerAcquired, "Acquired a named lock for reading while a writer was waiting.")
+
+	unlockReader2()
+	<-acquired
+	<-readerAcquired
+
+	assert.Eventually(t, func() bool {
+		locks.mutex.Lock()
+		defer locks.mutex.Unlock()
+		return len(locks.locks) == 0
+	}, 5*time.Second, time.Millisecond, "expected all locks to be discarded")
+}
+
+func TestNamedLocksConcurrent(t *testing.T) {
+
+	var locks NamedLocks
+	var wg sync.WaitGroup
+	counter := 0
+
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			defer locks.Lock("vol1")()
+			counter++
+		}()
+		go func() {
+			defer wg.Done()
+			defer locks.RLock("vol1")()
+			_ = counter
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, counter)
+	assert.Empty(t, locks.locks)
+}
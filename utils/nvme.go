@@ -0,0 +1,518 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+const (
+	nvmeTCPPort                        = "4420"
+	nvmeNamespaceDiscoveryTimeoutSecs  = 90
+	nvmeSubsystemSysfsDir              = "/sys/class/nvme-subsystem"
+	nvmeNativeMultipathSysfsParameter  = "/sys/module/nvme_core/parameters/multipath"
+	nvmeHostNQNFile                    = "/etc/nvme/hostnqn"
+	nvmeControllerStateLive            = "live"
+	nvmeControllerTransportTCP         = "tcp"
+	nvmeControllerAddressTargetAddrKey = "traddr"
+)
+
+var nvmeControllerRegex = regexp.MustCompile(`^nvme\d+$`)
+var nvmeNamespaceRegex = regexp.MustCompile(`^nvme\d+n\d+$`)
+
+// nvmeController describes one path, i.e. one NVMe/TCP connection, to an NVMe subsystem
+type nvmeController struct {
+	Name      string
+	Address   string
+	State     string
+	Transport string
+}
+
+// nvmeSubsystem describes an NVMe subsystem to which the host is connected
+type nvmeSubsystem struct {
+	Name        string
+	Path        string
+	NQN         string
+	Controllers []nvmeController
+}
+
+// NVMeSupported returns true if the NVMe CLI is installed on the host.
+func NVMeSupported(ctx context.Context) bool {
+
+	Logc(ctx).Debug(">>>> nvme.NVMeSupported")
+	defer Logc(ctx).Debug("<<<< nvme.NVMeSupported")
+
+	output, err := execCommand(ctx, "nvme", "version")
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"error":  err,
+			"output": string(output),
+		}).Debug("NVMe CLI not found.")
+		return false
+	}
+	return true
+}
+
+// GetHostNQN returns the NVMe qualified name of this host, as recorded in /etc/nvme/hostnqn.
+func GetHostNQN(ctx context.Context) (string, error) {
+
+	Logc(ctx).Debug(">>>> nvme.GetHostNQN")
+	defer Logc(ctx).Debug("<<<< nvme.GetHostNQN")
+
+	out, err := ioutil.ReadFile(chrootPathPrefix + nvmeHostNQNFile)
+	if err != nil {
+		Logc(ctx).WithField("error", err).Warn("Could not read hostnqn; perhaps NVMe is not installed?")
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// NVMeNativeMultipathEnabled returns true if the Linux kernel combines the paths to each NVMe namespace
+// into a single block device.
+func NVMeNativeMultipathEnabled(ctx context.Context) bool {
+
+	out, err := ioutil.ReadFile(chrootPathPrefix + nvmeNativeMultipathSysfsParameter)
+	if err != nil {
+		Logc(ctx).WithField("error", err).Debug("Could not read NVMe multipath parameter.")
+		return false
+	}
+	return strings.TrimSpace(string(out)) == "Y"
+}
+
+// AttachNVMeVolume attaches the volume to the local host.  This method must be able to accomplish its task
+// using only the data passed in.  It may be assumed that this method always runs on the host to which the
+// volume will be attached.
+func AttachNVMeVolume(ctx context.Context, name, mountpoint string, publishInfo *VolumePublishInfo) error {
+
+	Logc(ctx).Debug(">>>> nvme.AttachNVMeVolume")
+	defer Logc(ctx).Debug("<<<< nvme.AttachNVMeVolume")
+
+	subsystemNQN := publishInfo.NVMeSubsystemNQN
+	namespaceUUID := publishInfo.NVMeNamespaceUUID
+	fstype := publishInfo.FilesystemType
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":        name,
+		"mountpoint":    mountpoint,
+		"subsystemNQN":  subsystemNQN,
+		"namespaceUUID": namespaceUUID,
+		"targetIPs":     publishInfo.NVMeTargetIPs,
+		"fstype":        fstype,
+	}).Debug("Attaching NVMe volume.")
+
+	if !NVMeSupported(ctx) {
+		Logc(ctx).Errorf("Unable to attach volume: nvme-cli not found")
+		return errors.New("unable to attach: nvme-cli not found on host")
+	}
+
+	if err := NVMeConnect(ctx, subsystemNQN, publishInfo.NVMeTargetIPs); err != nil {
+		return err
+	}
+
+	devicePath, err := waitForNVMeNamespaceDevice(ctx, subsystemNQN, namespaceUUID)
+	if err != nil {
+		return err
+	}
+
+	// Return the device in the publish info in case the mount will be done later
+	publishInfo.DevicePath = devicePath
+
+	if fstype == fsRaw {
+		return nil
+	}
+
+	existingFstype, err := getFSType(ctx, devicePath)
+	if err != nil {
+		return fmt.Errorf("error getting file system type of namespace %s, device %s: %v", name, devicePath, err)
+	}
+
+	if existingFstype == "" {
+		Logc(ctx).WithFields(log.Fields{"volume": name, "fstype": fstype}).Debug("Formatting namespace.")
+		if err := formatVolume(ctx, devicePath, fstype); err != nil {
+			return fmt.Errorf("error formatting namespace %s, device %s: %v", name, devicePath, err)
+		}
+	} else if existingFstype != unknownFstype && existingFstype != fstype {
+		Logc(ctx).WithFields(log.Fields{
+			"volume":          name,
+			"existingFstype":  existingFstype,
+			"requestedFstype": fstype,
+		}).Error("Namespace already formatted with a different file system type.")
+		return fmt.Errorf("namespace %s, device %s already formatted with other filesystem: %s",
+			name, devicePath, existingFstype)
+	} else {
+		Logc(ctx).WithFields(log.Fields{
+			"volume": name,
+			"fstype": existingFstype,
+		}).Debug("Namespace already formatted.")
+	}
+
+	// Optionally mount the device
+	if mountpoint != "" {
+		if err := MountDevice(ctx, devicePath, mountpoint, publishInfo.MountOptions, false); err != nil {
+			return fmt.Errorf("error mounting namespace %v, device %v, mountpoint %v; %s",
+				name, devicePath, mountpoint, err)
+		}
+	}
+
+	return nil
+}
+
+// NVMeConnect ensures that the host has a live NVMe/TCP connection to a subsystem through each of the
+// specified target IP addresses.  It succeeds as long as at least one path to the subsystem is connected.
+func NVMeConnect(ctx context.Context, subsystemNQN string, targetIPs []string) error {
+
+	fields := log.Fields{"subsystemNQN": subsystemNQN, "targetIPs": targetIPs}
+	Logc(ctx).WithFields(fields).Debug(">>>> nvme.NVMeConnect")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< nvme.NVMeConnect")
+
+	connectedIPs := make(map[string]bool)
+	if subsystem, err := getNVMeSubsystem(chrootPathPrefix+nvmeSubsystemSysfsDir, subsystemNQN); err != nil {
+		return err
+	} else if subsystem != nil {
+		for _, controller := range subsystem.Controllers {
+			if controller.State == nvmeControllerStateLive && controller.Transport == nvmeControllerTransportTCP {
+				connectedIPs[controller.Address] = true
+			}
+		}
+	}
+
+	connected := len(connectedIPs) > 0
+	for _, ip := range targetIPs {
+
+		if connectedIPs[ip] {
+			Logc(ctx).WithField("targetIP", ip).Debug("NVMe path already connected.")
+			continue
+		}
+
+		_, err := execCommand(ctx, "nvme", "connect", "-t", nvmeControllerTransportTCP, "-a", ip,
+			"-s", nvmeTCPPort, "-n", subsystemNQN)
+		if err != nil {
+			Logc(ctx).WithFields(log.Fields{
+				"targetIP": ip,
+				"error":    err,
+			}).Error("Failed to connect NVMe path.")
+			continue
+		}
+
+		connected = true
+	}
+
+	if !connected {
+		return fmt.Errorf("could not connect to NVMe subsystem %s", subsystemNQN)
+	}
+	return nil
+}
+
+// NVMeDisconnect disconnects all paths from the host to an NVMe subsystem.
+func NVMeDisconnect(ctx context.Context, subsystemNQN string) error {
+
+	fields := log.Fields{"subsystemNQN": subsystemNQN}
+	Logc(ctx).WithFields(fields).Debug(">>>> nvme.NVMeDisconnect")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< nvme.NVMeDisconnect")
+
+	subsystem, err := getNVMeSubsystem(chrootPathPrefix+nvmeSubsystemSysfsDir, subsystemNQN)
+	if err != nil {
+		return err
+	} else if subsystem == nil {
+		Logc(ctx).WithFields(fields).Debug("NVMe subsystem not connected.")
+		return nil
+	}
+
+	if out, err := execCommand(ctx, "nvme", "disconnect", "-n", subsystemNQN); err != nil {
+		return fmt.Errorf("could not disconnect from NVMe subsystem %s; %s; %v", subsystemNQN,
+			strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// PrepareNVMeDeviceForRemoval flushes any outstanding I/O to an NVMe namespace device.
+func PrepareNVMeDeviceForRemoval(ctx context.Context, devicePath string, force bool) error {
+
+	fields := log.Fields{"devicePath": devicePath, "force": force}
+	Logc(ctx).WithFields(fields).Debug(">>>> nvme.PrepareNVMeDeviceForRemoval")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< nvme.PrepareNVMeDeviceForRemoval")
+
+	if devicePath == "" || !PathExists(devicePath) {
+		Logc(ctx).WithFields(fields).Debug("NVMe device not present.")
+		return nil
+	}
+
+	if err := flushOneDevice(ctx, devicePath); err != nil && !force {
+		return err
+	}
+	return nil
+}
+
+// NVMeRescanNamespace rescans the namespaces of an NVMe subsystem so that the host detects an increase in the
+// size of a namespace, and verifies that the namespace's devices are at least the specified size.
+func NVMeRescanNamespace(ctx context.Context, subsystemNQN, namespaceUUID string, minSize int64) error {
+
+	fields := log.Fields{"subsystemNQN": subsystemNQN, "namespaceUUID": namespaceUUID, "minSize": minSize}
+	Logc(ctx).WithFields(fields).Debug(">>>> nvme.NVMeRescanNamespace")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< nvme.NVMeRescanNamespace")
+
+	subsystemsDir := chrootPathPrefix + nvmeSubsystemSysfsDir
+
+	subsystem, err := getNVMeSubsystem(subsystemsDir, subsystemNQN)
+	if err != nil {
+		return fmt.Errorf("error getting NVMe subsystem %s: %v", subsystemNQN, err)
+	} else if subsystem == nil {
+		return fmt.Errorf("host is not connected to NVMe subsystem %s", subsystemNQN)
+	}
+
+	devices, err := getNVMeNamespaceDevices(subsystemsDir, subsystemNQN, namespaceUUID)
+	if err != nil {
+		return fmt.Errorf("error getting devices for NVMe namespace %s: %v", namespaceUUID, err)
+	} else if len(devices) == 0 {
+		return fmt.Errorf("no devices found for NVMe namespace %s", namespaceUUID)
+	}
+
+	largeEnough, err := nvmeDevicesLargeEnough(ctx, devices, minSize)
+	if err != nil {
+		return err
+	}
+
+	if !largeEnough {
+		for _, controllerPath := range getNVMeRescanControllerPaths(subsystem) {
+			if _, err = execCommand(ctx, "nvme", "ns-rescan", controllerPath); err != nil {
+				Logc(ctx).WithField("controller", controllerPath).Error("Failed to rescan NVMe controller.")
+				return fmt.Errorf("failed to rescan NVMe controller %s: %v", controllerPath, err)
+			}
+		}
+
+		time.Sleep(time.Second)
+		if largeEnough, err = nvmeDevicesLargeEnough(ctx, devices, minSize); err != nil {
+			return err
+		} else if !largeEnough {
+			Logc(ctx).Error("NVMe namespace not large enough after resize.")
+			return fmt.Errorf("NVMe namespace %s not large enough after resize", namespaceUUID)
+		}
+	}
+
+	// Without native multipath, the devices may be combined by device mapper multipathing
+	if len(devices) > 1 {
+		if multipathDevice := findMultipathDeviceForDevice(ctx, devices[0]); multipathDevice != "" {
+			largeEnough, err = nvmeDevicesLargeEnough(ctx, []string{multipathDevice}, minSize)
+			if err != nil {
+				return err
+			}
+			if !largeEnough {
+				Logc(ctx).WithField("multipathDevice", multipathDevice).Debug("Reloading the multipath device.")
+				if err = reloadMultipathDevice(ctx, multipathDevice); err != nil {
+					return err
+				}
+				time.Sleep(time.Second)
+				if largeEnough, err = nvmeDevicesLargeEnough(ctx, []string{multipathDevice}, minSize); err != nil {
+					return err
+				} else if !largeEnough {
+					Logc(ctx).Error("Multipath device not large enough after resize.")
+					return fmt.Errorf("multipath device %s not large enough after resize", multipathDevice)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// nvmeDevicesLargeEnough returns true if each of the specified block devices is at least the specified size.
+func nvmeDevicesLargeEnough(ctx context.Context, devices []string, minSize int64) (bool, error) {
+
+	for _, device := range devices {
+		size, err := getISCSIDiskSize(ctx, "/dev/"+device)
+		if err != nil {
+			return false, err
+		}
+		if size < minSize {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// getNVMeRescanControllerPaths returns the device paths of the live controllers of an NVMe subsystem, which
+// are the devices through which the host rescans the subsystem's namespaces.
+func getNVMeRescanControllerPaths(subsystem *nvmeSubsystem) []string {
+
+	paths := make([]string, 0)
+	for _, controller := range subsystem.Controllers {
+		if controller.State == nvmeControllerStateLive {
+			paths = append(paths, "/dev/"+controller.Name)
+		}
+	}
+	return paths
+}
+
+// waitForNVMeNamespaceDevice waits for the block device of an NVMe namespace to appear and returns its path.
+func waitForNVMeNamespaceDevice(ctx context.Context, subsystemNQN, namespaceUUID string) (string, error) {
+
+	fields := log.Fields{"subsystemNQN": subsystemNQN, "namespaceUUID": namespaceUUID}
+	Logc(ctx).WithFields(fields).Debug(">>>> nvme.waitForNVMeNamespaceDevice")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< nvme.waitForNVMeNamespaceDevice")
+
+	maxDuration := nvmeNamespaceDiscoveryTimeoutSecs * time.Second
+	devicePath := ""
+
+	findDevice := func() error {
+		devices, err := getNVMeNamespaceDevices(chrootPathPrefix+nvmeSubsystemSysfsDir, subsystemNQN, namespaceUUID)
+		if err != nil {
+			return err
+		} else if len(devices) == 0 {
+			return errors.New("namespace device not yet present")
+		}
+
+		// With native multipath, the kernel presents one device for all paths.  Otherwise, each
+		// path has its own device, which are combined by device mapper multipathing if it is in use.
+		device := devices[0]
+		if len(devices) > 1 {
+			if multipathDevice := findMultipathDeviceForDevice(ctx, device); multipathDevice != "" {
+				device = multipathDevice
+			} else {
+				Logc(ctx).WithField("devices", devices).Warn(
+					"Multiple paths found for NVMe namespace without native or device mapper multipathing.")
+			}
+		}
+
+		devicePath = "/dev/" + device
+		if !PathExists(devicePath) {
+			return fmt.Errorf("device %s not yet present", devicePath)
+		}
+		return nil
+	}
+
+	deviceNotify := func(err error, duration time.Duration) {
+		Logc(ctx).WithField("increment", duration).Debug("NVMe namespace device not yet present, waiting.")
+	}
+
+	deviceBackoff := backoff.NewExponentialBackOff()
+	deviceBackoff.InitialInterval = 1 * time.Second
+	deviceBackoff.Multiplier = 1.414 // approx sqrt(2)
+	deviceBackoff.RandomizationFactor = 0.1
+	deviceBackoff.MaxElapsedTime = maxDuration
+
+	if err := backoff.RetryNotify(findDevice, deviceBackoff, deviceNotify); err != nil {
+		return "", fmt.Errorf("could not find NVMe namespace %s after %3.2f seconds", namespaceUUID,
+			maxDuration.Seconds())
+	}
+
+	Logc(ctx).WithField("device", devicePath).Debug("NVMe namespace device found.")
+	return devicePath, nil
+}
+
+// getNVMeSubsystem returns the NVMe subsystem with the specified NQN, or nil if the host isn't connected to it.
+func getNVMeSubsystem(subsystemsDir, subsystemNQN string) (*nvmeSubsystem, error) {
+
+	dirs, err := ioutil.ReadDir(subsystemsDir)
+	if err != nil {
+		// No subsystems are connected until the nvme-core module is loaded
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		subsystemPath := filepath.Join(subsystemsDir, dir.Name())
+		if readSysfsValue(filepath.Join(subsystemPath, "subsysnqn")) != subsystemNQN {
+			continue
+		}
+
+		subsystem := &nvmeSubsystem{Name: dir.Name(), Path: subsystemPath, NQN: subsystemNQN}
+
+		entries, err := ioutil.ReadDir(subsystemPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !nvmeControllerRegex.MatchString(entry.Name()) {
+				continue
+			}
+			controllerPath := filepath.Join(subsystemPath, entry.Name())
+			subsystem.Controllers = append(subsystem.Controllers, nvmeController{
+				Name:      entry.Name(),
+				Address:   parseNVMeControllerAddress(readSysfsValue(filepath.Join(controllerPath, "address"))),
+				State:     readSysfsValue(filepath.Join(controllerPath, "state")),
+				Transport: readSysfsValue(filepath.Join(controllerPath, "transport")),
+			})
+		}
+		return subsystem, nil
+	}
+
+	return nil, nil
+}
+
+// getNVMeNamespaceDevices returns the names of the block devices for an NVMe namespace in a subsystem.
+// With native multipath, there is one device, which is found in the subsystem directory; otherwise,
+// there is one device per path, each of which is found in the directory of its controller.
+func getNVMeNamespaceDevices(subsystemsDir, subsystemNQN, namespaceUUID string) ([]string, error) {
+
+	subsystem, err := getNVMeSubsystem(subsystemsDir, subsystemNQN)
+	if err != nil || subsystem == nil {
+		return nil, err
+	}
+
+	devices := findNVMeNamespaceDevicesInDir(subsystem.Path, namespaceUUID)
+	if len(devices) > 0 {
+		return devices, nil
+	}
+
+	for _, controller := range subsystem.Controllers {
+		devices = append(devices, findNVMeNamespaceDevicesInDir(
+			filepath.Join(subsystem.Path, controller.Name), namespaceUUID)...)
+	}
+	return devices, nil
+}
+
+func findNVMeNamespaceDevicesInDir(dir, namespaceUUID string) []string {
+
+	devices := make([]string, 0)
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return devices
+	}
+	for _, entry := range entries {
+		if !nvmeNamespaceRegex.MatchString(entry.Name()) {
+			continue
+		}
+		if strings.EqualFold(readSysfsValue(filepath.Join(dir, entry.Name(), "uuid")), namespaceUUID) {
+			devices = append(devices, entry.Name())
+		}
+	}
+	return devices
+}
+
+// parseNVMeControllerAddress returns the target address from the address of an NVMe controller, which looks
+// like "traddr=10.0.0.1,trsvcid=4420".
+func parseNVMeControllerAddress(address string) string {
+
+	for _, field := range strings.Split(address, ",") {
+		keyValue := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(keyValue) == 2 && keyValue[0] == nvmeControllerAddressTargetAddrKey {
+			return keyValue[1]
+		}
+	}
+	return ""
+}
+
+func readSysfsValue(path string) string {
+
+	value, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(value))
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSubsystemNQN  = "nqn.1992-08.com.netapp:sn.1234:subsystem.vol1"
+	testNamespaceUUID = "2d2cb5a2-0d0f-4a41-9a56-08c7e1f0c6b5"
+)
+
+// writeSysfsFile creates a file, along with any missing parent directories, in a fake sysfs tree
+func writeSysfsFile(t *testing.T, path, value string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, ioutil.WriteFile(path, []byte(value+"\n"), 0644))
+}
+
+// newFakeNVMeSubsystemsDir creates a fake /sys/class/nvme-subsystem containing one subsystem with two
+// controllers.  With native multipath the namespace device is in the subsystem directory, and otherwise
+// there is one namespace device per controller.
+func newFakeNVMeSubsystemsDir(t *testing.T, nativeMultipath bool) string {
+
+	dir, err := ioutil.TempDir("", "nvme-subsystem")
+	assert.NoError(t, err)
+
+	subsystemDir := filepath.Join(dir, "nvme-subsys0")
+	writeSysfsFile(t, filepath.Join(subsystemDir, "subsysnqn"), testSubsystemNQN)
+
+	for i, address := range []string{"10.0.0.1", "10.0.0.2"} {
+		controller := filepath.Join(subsystemDir, fmt.Sprintf("nvme%d", i))
+		writeSysfsFile(t, filepath.Join(controller, "address"), "traddr="+address+",trsvcid=4420")
+		writeSysfsFile(t, filepath.Join(controller, "state"), "live")
+		writeSysfsFile(t, filepath.Join(controller, "transport"), "tcp")
+		if !nativeMultipath {
+			writeSysfsFile(t, filepath.Join(controller, fmt.Sprintf("nvme%dn1", i), "uuid"), testNamespaceUUID)
+		}
+	}
+
+	if nativeMultipath {
+		writeSysfsFile(t, filepath.Join(subsystemDir, "nvme0n1", "uuid"), testNamespaceUUID)
+	}
+
+	// A subsystem the host is connected to for some other volume
+	writeSysfsFile(t, filepath.Join(dir, "nvme-subsys1", "subsysnqn"),
+		"nqn.1992-08.com.netapp:sn.1234:subsystem.vol2")
+
+	return dir
+}
+
+func TestParseNVMeControllerAddress(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		address  string
+		expected string
+	}{
+		{"tcp", "traddr=10.0.0.1,trsvcid=4420", "10.0.0.1"},
+		{"tcpWithSource", "traddr=10.0.0.1,trsvcid=4420,src_addr=10.0.0.100", "10.0.0.1"},
+		{"ipv6", "traddr=fd20:8b1e:b255:4071::1,trsvcid=4420", "fd20:8b1e:b255:4071::1"},
+		{"spaces", "traddr=10.0.0.1, trsvcid=4420", "10.0.0.1"},
+		{"empty", "", ""},
+		{"noTargetAddress", "trsvcid=4420", ""},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		assert.Equal(t, test.expected, parseNVMeControllerAddress(test.address))
+	}
+}
+
+func TestGetNVMeSubsystem(t *testing.T) {
+
+	dir := newFakeNVMeSubsystemsDir(t, true)
+	defer os.RemoveAll(dir)
+
+	subsystem, err := getNVMeSubsystem(dir, testSubsystemNQN)
+	assert.NoError(t, err)
+	if assert.NotNil(t, subsystem) {
+		assert.Equal(t, "nvme-subsys0", subsystem.Name)
+		assert.Equal(t, []nvmeController{
+			{Name: "nvme0", Address: "10.0.0.1", State: "live", Transport: "tcp"},
+			{Name: "nvme1", Address: "10.0.0.2", State: "live", Transport: "tcp"},
+		}, subsystem.Controllers)
+	}
+
+	subsystem, err = getNVMeSubsystem(dir, "nqn.1992-08.com.netapp:sn.1234:subsystem.vol3")
+	assert.NoError(t, err)
+	assert.Nil(t, subsystem)
+
+	subsystem, err = getNVMeSubsystem(filepath.Join(dir, "missing"), testSubsystemNQN)
+	assert.NoError(t, err)
+	assert.Nil(t, subsystem)
+}
+
+func TestGetNVMeNamespaceDevices(t *testing.T) {
+
+	tests := []struct {
+		name            string
+		nativeMultipath bool
+		subsystemNQN    string
+		namespaceUUID   string
+		expected        []string
+	}{
+		{"nativeMultipath", true, testSubsystemNQN, testNamespaceUUID, []string{"nvme0n1"}},
+		{"pathPerController", false, testSubsystemNQN, testNamespaceUUID, []string{"nvme0n1", "nvme1n1"}},
+		{"unknownNamespace", true, testSubsystemNQN, "00000000-0000-0000-0000-000000000000", []string{}},
+		{"unknownSubsystem", true, "nqn.1992-08.com.netapp:sn.1234:subsystem.vol3", testNamespaceUUID, nil},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		dir := newFakeNVMeSubsystemsDir(t, test.nativeMultipath)
+
+		devices, err := getNVMeNamespaceDevices(dir, test.subsystemNQN, test.namespaceUUID)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, devices)
+
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestGetNVMeRescanControllerPaths(t *testing.T) {
+
+	dir := newFakeNVMeSubsystemsDir(t, true)
+	defer os.RemoveAll(dir)
+
+	subsystem, err := getNVMeSubsystem(dir, testSubsystemNQN)
+	if !assert.NoError(t, err) || !assert.NotNil(t, subsystem) {
+		return
+	}
+	assert.Equal(t, []string{"/dev/nvme0", "/dev/nvme1"}, getNVMeRescanControllerPaths(subsystem))
+
+	// Controllers whose paths are down are not rescanned
+	subsystem.Controllers[1].State = "connecting"
+	assert.Equal(t, []string{"/dev/nvme0"}, getNVMeRescanControllerPaths(subsystem))
+}
+
+func TestNVMeRescanNamespaceNotConnected(t *testing.T) {
+
+	// The rescan fails, rather than falling back to iSCSI, if the host is not connected to the subsystem
+	err := NVMeRescanNamespace(ctx(), "nqn.1992-08.com.netapp:sn.1234:subsystem.missing", testNamespaceUUID, 1)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not connected")
+	}
+}
@@ -79,8 +79,11 @@ func AttachNFSVolume(ctx context.Context, name, mountpoint string, publishInfo *
 // AttachISCSIVolume attaches the volume to the local host.  This method must be able to accomplish its task using only the data passed in.
 // It may be assumed that this method always runs on the host to which the volume will be attached.  If the mountpoint
 // parameter is specified, the volume will be mounted.  The device path is set on the in-out publishInfo parameter
-// so that it may be mounted later instead.
-func AttachISCSIVolume(ctx context.Context, name, mountpoint string, publishInfo *VolumePublishInfo) error {
+// so that it may be mounted later instead.  If the volume is LUKS-encrypted, the device is opened with the supplied
+// passphrase, and the device path is that of the dm-crypt device.
+func AttachISCSIVolume(
+	ctx context.Context, name, mountpoint string, publishInfo *VolumePublishInfo, luksPassphrase string,
+) error {
 
 	Logc(ctx).Debug(">>>> osutils.AttachISCSIVolume")
 	defer Logc(ctx).Debug("<<<< osutils.AttachISCSIVolume")
@@ -108,6 +111,11 @@ func AttachISCSIVolume(ctx context.Context, name, mountpoint string, publishInfo
 	var fstype = publishInfo.FilesystemType
 	var options = publishInfo.MountOptions
 
+	luksEncryption, err := parseLUKSEncryption(publishInfo.LUKSEncryption)
+	if err != nil {
+		return err
+	}
+
 	if iscsiInterface == "" {
 		iscsiInterface = "default"
 	}
@@ -120,6 +128,7 @@ func AttachISCSIVolume(ctx context.Context, name, mountpoint string, publishInfo
 		"targetIQN":      targetIQN,
 		"iscsiInterface": iscsiInterface,
 		"fstype":         fstype,
+		"luksEncryption": luksEncryption,
 	}).Debug("Attaching iSCSI volume.")
 
 	if !ISCSISupported(ctx) {
@@ -206,7 +215,8 @@ func AttachISCSIVolume(ctx context.Context, name, mountpoint string, publishInfo
 	}
 
 	// Lookup all the SCSI device information, and include filesystem type only if not raw block volume
-	needFSType := fstype != fsRaw
+	// or if a LUKS header may need to be written
+	needFSType := fstype != fsRaw || luksEncryption
 
 	deviceInfo, err := getDeviceInfoForLUN(ctx, lunID, targetIQN, needFSType)
 	if err != nil {
@@ -236,6 +246,21 @@ func AttachISCSIVolume(ctx context.Context, name, mountpoint string, publishInfo
 		return fmt.Errorf("could not find device %v; %s", devicePath, err)
 	}
 
+	existingFstype := deviceInfo.Filesystem
+
+	// Format and open the LUKS device if needed, and use it in place of the LUN from here on
+	if luksEncryption {
+		devicePath, err = ensureLUKSDeviceOpen(ctx, devicePath, existingFstype, name, luksPassphrase)
+		if err != nil {
+			return fmt.Errorf("error opening LUKS device for LUN %s; %v", name, err)
+		}
+		if fstype != fsRaw {
+			if existingFstype, err = getFSType(ctx, devicePath); err != nil {
+				return fmt.Errorf("error getting file system type of LUKS device %s; %v", devicePath, err)
+			}
+		}
+	}
+
 	// Return the device in the publish info in case the mount will be done later
 	publishInfo.DevicePath = devicePath
 
@@ -243,7 +268,6 @@ func AttachISCSIVolume(ctx context.Context, name, mountpoint string, publishInfo
 		return nil
 	}
 
-	existingFstype := deviceInfo.Filesystem
 	if existingFstype == "" {
 		Logc(ctx).WithFields(log.Fields{"volume": name, "fstype": fstype}).Debug("Formatting LUN.")
 		err := formatVolume(ctx, devicePath, fstype)
@@ -2195,6 +2219,11 @@ func GetMountedISCSIDevices(ctx context.Context) ([]*ScsiDeviceInfo, error) {
 			mountedDevice = strings.TrimPrefix(procMount.Root, "/")
 		}
 
+		// A LUKS device is layered on the iSCSI device, so look for the device beneath it instead
+		if luksDevice := findDeviceForLUKSDevice(ctx, chrootPathPrefix+"/sys/block", mountedDevice); luksDevice != "" {
+			mountedDevice = luksDevice
+		}
+
 		mountedDevices = append(mountedDevices, mountedDevice)
 	}
 
@@ -3151,6 +3180,28 @@ func execCommand(ctx context.Context, name string, args ...string) ([]byte, erro
 	return out, err
 }
 
+// execCommandWithInput invokes an external process, writing the supplied input to its standard input.
+// The input is never logged, since it may be a secret.
+func execCommandWithInput(ctx context.Context, name, input string, args ...string) ([]byte, error) {
+
+	Logc(ctx).WithFields(log.Fields{
+		"command": name,
+		"args":    args,
+	}).Debug(">>>> osutils.execCommandWithInput.")
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	out, err := cmd.CombinedOutput()
+
+	Logc(ctx).WithFields(log.Fields{
+		"command": name,
+		"output":  sanitizeString(string(out)),
+		"error":   err,
+	}).Debug("<<<< osutils.execCommandWithInput.")
+
+	return out, err
+}
+
 // execCommandResult is used to return shell command results via channels between goroutines
 type execCommandResult struct {
 	Output []byte
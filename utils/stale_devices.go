@@ -0,0 +1,233 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+// IsStagedVolumeMounted returns true if a volume staged on this host is mounted anywhere, as it is when
+// published to a pod.  A mounted volume is in use and must not be cleaned up.
+func IsStagedVolumeMounted(ctx context.Context, publishInfo *VolumePublishInfo) (bool, error) {
+
+	Logc(ctx).Debug(">>>> stale_devices.IsStagedVolumeMounted")
+	defer Logc(ctx).Debug("<<<< stale_devices.IsStagedVolumeMounted")
+
+	if publishInfo.IscsiTargetIQN != "" {
+		mountedISCSIDevices, err := GetMountedISCSIDevices(ctx)
+		if err != nil {
+			return false, err
+		}
+		lunID := strconv.Itoa(int(publishInfo.IscsiLunNumber))
+		for _, device := range mountedISCSIDevices {
+			if device.IQN == publishInfo.IscsiTargetIQN && device.LUN == lunID {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	procSelfMountinfo, err := listProcSelfMountinfo(procSelfMountinfoPath)
+	if err != nil {
+		return false, err
+	}
+
+	if publishInfo.NfsServerIP != "" {
+		exportPath := fmt.Sprintf("%s:%s", publishInfo.NfsServerIP, publishInfo.NfsPath)
+		return isNFSExportMounted(procSelfMountinfo, exportPath), nil
+	}
+
+	if publishInfo.DevicePath != "" {
+		device, err := filepath.EvalSymlinks(publishInfo.DevicePath)
+		if err != nil {
+			// The device is gone, so nothing can be using it
+			return false, nil
+		}
+		return isDeviceMounted(procSelfMountinfo, strings.TrimPrefix(device, "/dev/")), nil
+	}
+
+	return false, nil
+}
+
+// isNFSExportMounted returns true if the NFS export is mounted anywhere.
+func isNFSExportMounted(mounts []MountInfo, exportPath string) bool {
+
+	for _, mount := range mounts {
+		if strings.HasPrefix(mount.FsType, "nfs") && mount.MountSource == exportPath {
+			return true
+		}
+	}
+	return false
+}
+
+// isDeviceMounted returns true if the device, specified by its name in /dev, is mounted anywhere.  A raw
+// block device is bind-mounted from devtmpfs, so its name is found in the root of the mount instead.
+func isDeviceMounted(mounts []MountInfo, deviceName string) bool {
+
+	for _, mount := range mounts {
+
+		var mountedDevice string
+		if strings.HasPrefix(mount.MountSource, "/dev/") {
+			device, err := filepath.EvalSymlinks(mount.MountSource)
+			if err != nil {
+				continue
+			}
+			mountedDevice = strings.TrimPrefix(device, "/dev/")
+		} else {
+			mountedDevice = strings.TrimPrefix(mount.Root, "/")
+		}
+
+		if mountedDevice == deviceName {
+			return true
+		}
+	}
+	return false
+}
+
+// FindOrphanedISCSIDevices returns the devices attached from the specified targets whose LUNs are neither
+// staged on this host nor mounted, and whose serial numbers are among those of Trident's volumes.  Devices
+// from other targets, or whose serial numbers are unknown to Trident, are never returned, since they may
+// belong to something other than Trident.
+func FindOrphanedISCSIDevices(
+	ctx context.Context, targetIQNs []string, stagedVolumes []*VolumePublishInfo, tridentLUNSerials []string,
+) ([]*ScsiDeviceInfo, error) {
+
+	Logc(ctx).WithField("targetIQNs", targetIQNs).Debug(">>>> stale_devices.FindOrphanedISCSIDevices")
+	defer Logc(ctx).Debug("<<<< stale_devices.FindOrphanedISCSIDevices")
+
+	devices, err := GetISCSIDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mountedDevices, err := GetMountedISCSIDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceSerial := func(device *ScsiDeviceInfo) string {
+		for _, deviceName := range device.Devices {
+			serial, err := getSCSIDeviceSerial(chrootPathPrefix+"/sys/block", deviceName)
+			if err != nil {
+				Logc(ctx).WithField("device", deviceName).WithError(err).Debug("Could not read device serial number.")
+				continue
+			}
+			return serial
+		}
+		return ""
+	}
+
+	return findOrphanedISCSIDevices(devices, mountedDevices, targetIQNs, stagedVolumes, tridentLUNSerials,
+		deviceSerial), nil
+}
+
+// findOrphanedISCSIDevices returns the devices from the specified targets that match neither a staged
+// volume nor a mounted device, and whose serial numbers are among the specified ones.
+func findOrphanedISCSIDevices(
+	devices, mountedDevices []*ScsiDeviceInfo, targetIQNs []string, stagedVolumes []*VolumePublishInfo,
+	tridentLUNSerials []string, deviceSerial func(*ScsiDeviceInfo) string,
+) []*ScsiDeviceInfo {
+
+	inUseLUNs := make(map[string]bool)
+	for _, publishInfo := range stagedVolumes {
+		if publishInfo.IscsiTargetIQN != "" {
+			inUseLUNs[iSCSILUNKey(publishInfo.IscsiTargetIQN, strconv.Itoa(int(publishInfo.IscsiLunNumber)))] = true
+		}
+	}
+	for _, device := range mountedDevices {
+		inUseLUNs[iSCSILUNKey(device.IQN, device.LUN)] = true
+	}
+
+	orphanedDevices := make([]*ScsiDeviceInfo, 0)
+	for _, device := range devices {
+		if !SliceContainsString(targetIQNs, device.IQN) || inUseLUNs[iSCSILUNKey(device.IQN, device.LUN)] {
+			continue
+		}
+		if serial := deviceSerial(device); serial == "" || !SliceContainsString(tridentLUNSerials, serial) {
+			continue
+		}
+		orphanedDevices = append(orphanedDevices, device)
+	}
+	return orphanedDevices
+}
+
+// getSCSIDeviceSerial returns the serial number of a SCSI device, as reported in its unit serial number
+// VPD page (0x80), which begins with a four-byte header giving the length of the serial number.
+func getSCSIDeviceSerial(sysBlockPath, deviceName string) (string, error) {
+
+	vpdPage, err := ioutil.ReadFile(filepath.Join(sysBlockPath, deviceName, "device", "vpd_pg80"))
+	if err != nil {
+		return "", err
+	}
+	if len(vpdPage) < 4 || vpdPage[1] != 0x80 {
+		return "", fmt.Errorf("invalid unit serial number page for device %s", deviceName)
+	}
+
+	length := int(vpdPage[3])
+	if len(vpdPage) < 4+length {
+		return "", fmt.Errorf("truncated unit serial number page for device %s", deviceName)
+	}
+	return strings.TrimSpace(string(vpdPage[4 : 4+length])), nil
+}
+
+func iSCSILUNKey(targetIQN, lunID string) string {
+	return targetIQN + "/" + lunID
+}
+
+// RemoveOrphanedISCSIDevice flushes and removes a device that was found by FindOrphanedISCSIDevices.
+// The removal fails, rather than risk losing data, if the device cannot be flushed.
+func RemoveOrphanedISCSIDevice(ctx context.Context, deviceInfo *ScsiDeviceInfo) error {
+
+	fields := log.Fields{"iqn": deviceInfo.IQN, "lun": deviceInfo.LUN, "multipathDevice": deviceInfo.MultipathDevice}
+	Logc(ctx).WithFields(fields).Debug(">>>> stale_devices.RemoveOrphanedISCSIDevice")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< stale_devices.RemoveOrphanedISCSIDevice")
+
+	return removeSCSIDevice(ctx, deviceInfo, false)
+}
+
+// LogoutISCSITargetIfUnused logs out of all sessions to the specified target if no devices remain attached
+// from it, and returns whether it did so.
+func LogoutISCSITargetIfUnused(ctx context.Context, targetIQN string) (bool, error) {
+
+	fields := log.Fields{"targetIQN": targetIQN}
+	Logc(ctx).WithFields(fields).Debug(">>>> stale_devices.LogoutISCSITargetIfUnused")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< stale_devices.LogoutISCSITargetIfUnused")
+
+	devices, err := GetISCSIDevices(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, device := range devices {
+		if device.IQN == targetIQN {
+			return false, nil
+		}
+	}
+
+	sessionInfo, err := getISCSISessionInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	loggedOut := false
+	for _, session := range sessionInfo {
+		if session.TargetName != targetIQN {
+			continue
+		}
+		// Strip the target portal group tag from the portal
+		portal := strings.Split(session.Portal, ",")[0]
+		if err = ISCSILogout(ctx, targetIQN, portal); err != nil {
+			return loggedOut, err
+		}
+		loggedOut = true
+	}
+	return loggedOut, nil
+}
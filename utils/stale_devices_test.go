@@ -0,0 +1,124 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindOrphanedISCSIDevices(t *testing.T) {
+
+	const (
+		tridentIQN = "iqn.1992-08.com.netapp:sn.afbb1784f77411e582f8080027e22798:vs.3"
+		otherIQN   = "iqn.2003-01.org.linux-iscsi.host:sn.1234"
+	)
+
+	devices := []*ScsiDeviceInfo{
+		{IQN: tridentIQN, LUN: "0", MultipathDevice: "dm-0", Devices: []string{"sda", "sdb"}},
+		{IQN: tridentIQN, LUN: "1", MultipathDevice: "dm-1", Devices: []string{"sdc", "sdd"}},
+		{IQN: tridentIQN, LUN: "2", MultipathDevice: "dm-2", Devices: []string{"sde", "sdf"}},
+		{IQN: tridentIQN, LUN: "3", MultipathDevice: "dm-3", Devices: []string{"sdh", "sdi"}},
+		{IQN: otherIQN, LUN: "0", Devices: []string{"sdg"}},
+	}
+	mountedDevices := []*ScsiDeviceInfo{devices[1]}
+
+	// LUN 3 is not one of Trident's
+	serials := map[string]string{"sda": "serial0", "sdc": "serial1", "sde": "serial2", "sdh": "other", "sdg": "serial0"}
+	deviceSerial := func(device *ScsiDeviceInfo) string {
+		return serials[device.Devices[0]]
+	}
+	tridentLUNSerials := []string{"serial0", "serial1", "serial2"}
+
+	tests := []struct {
+		name              string
+		targetIQNs        []string
+		stagedVolumes     []*VolumePublishInfo
+		tridentLUNSerials []string
+		expected          []*ScsiDeviceInfo
+	}{
+		{
+			"stagedAndMounted",
+			[]string{tridentIQN},
+			[]*VolumePublishInfo{
+				{VolumeAccessInfo: VolumeAccessInfo{IscsiAccessInfo: IscsiAccessInfo{
+					IscsiTargetIQN: tridentIQN, IscsiLunNumber: 0}}},
+			},
+			tridentLUNSerials,
+			[]*ScsiDeviceInfo{devices[2]},
+		},
+		{"nothingStaged", []string{tridentIQN}, nil, tridentLUNSerials, []*ScsiDeviceInfo{devices[0], devices[2]}},
+		{"unknownTargets", nil, nil, tridentLUNSerials, []*ScsiDeviceInfo{}},
+		{"unknownSerials", []string{tridentIQN}, nil, nil, []*ScsiDeviceInfo{}},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		assert.Equal(t, test.expected, findOrphanedISCSIDevices(devices, mountedDevices, test.targetIQNs,
+			test.stagedVolumes, test.tridentLUNSerials, deviceSerial))
+	}
+}
+
+func TestGetSCSIDeviceSerial(t *testing.T) {
+
+	sysBlockPath := t.TempDir()
+
+	writePage := func(deviceName string, page []byte) {
+		deviceDir := filepath.Join(sysBlockPath, deviceName, "device")
+		if err := os.MkdirAll(deviceDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(deviceDir, "vpd_pg80"), page, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writePage("sda", append([]byte{0x00, 0x80, 0x00, 0x0c}, []byte("80FEa+Mab1u8")...))
+	writePage("sdb", []byte{0x00, 0x83, 0x00, 0x00})
+	writePage("sdc", append([]byte{0x00, 0x80, 0x00, 0x0c}, []byte("80FE")...))
+
+	serial, err := getSCSIDeviceSerial(sysBlockPath, "sda")
+	assert.NoError(t, err)
+	assert.Equal(t, "80FEa+Mab1u8", serial)
+
+	_, err = getSCSIDeviceSerial(sysBlockPath, "sdb")
+	assert.Error(t, err, "wrong page")
+
+	_, err = getSCSIDeviceSerial(sysBlockPath, "sdc")
+	assert.Error(t, err, "truncated page")
+
+	_, err = getSCSIDeviceSerial(sysBlockPath, "sdd")
+	assert.Error(t, err, "missing device")
+}
+
+func TestIsNFSExportMounted(t *testing.T) {
+
+	mounts := []MountInfo{
+		{MountPoint: "/var/lib/kubelet/pods/uid1/volumes/kubernetes.io~csi/pvc-1/mount", FsType: "nfs4",
+			MountSource: "10.0.0.1:/trident_pvc_1"},
+		{MountPoint: "/mnt/other", FsType: "ext4", MountSource: "10.0.0.1:/trident_pvc_2"},
+	}
+
+	assert.True(t, isNFSExportMounted(mounts, "10.0.0.1:/trident_pvc_1"))
+	assert.False(t, isNFSExportMounted(mounts, "10.0.0.1:/trident_pvc_2"))
+	assert.False(t, isNFSExportMounted(mounts, "10.0.0.1:/trident_pvc_3"))
+}
+
+func TestIsDeviceMounted(t *testing.T) {
+
+	mounts := []MountInfo{
+		{MountPoint: "/var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/pvc-1/uid1",
+			MountSource: "devtmpfs", Root: "/nvme0n1"},
+		{MountPoint: "/var/lib/kubelet/pods/uid2/volumes/kubernetes.io~csi/pvc-2/mount",
+			MountSource: "/dev/does-not-exist", Root: "/"},
+	}
+
+	assert.True(t, isDeviceMounted(mounts, "nvme0n1"))
+	assert.False(t, isDeviceMounted(mounts, "nvme1n1"))
+	assert.False(t, isDeviceMounted(mounts, "does-not-exist"))
+}
@@ -4,6 +4,7 @@ package utils
 
 type VolumeAccessInfo struct {
 	IscsiAccessInfo
+	NVMeAccessInfo
 	NfsAccessInfo
 	MountOptions string `json:"mountOptions,omitempty"`
 }
@@ -23,6 +24,12 @@ type IscsiAccessInfo struct {
 	IscsiLunSerial       string   `json:"iscsiLunSerial,omitempty"`
 }
 
+type NVMeAccessInfo struct {
+	NVMeSubsystemNQN  string   `json:"nvmeSubsystemNqn,omitempty"`
+	NVMeNamespaceUUID string   `json:"nvmeNamespaceUuid,omitempty"`
+	NVMeTargetIPs     []string `json:"nvmeTargetIps,omitempty"`
+}
+
 type NfsAccessInfo struct {
 	NfsServerIP string `json:"nfsServerIp,omitempty"`
 	NfsPath     string `json:"nfsPath,omitempty"`
@@ -31,6 +38,7 @@ type NfsAccessInfo struct {
 type VolumePublishInfo struct {
 	Localhost      bool     `json:"localhost,omitempty"`
 	HostIQN        []string `json:"hostIQN,omitempty"`
+	HostNQN        string   `json:"hostNQN,omitempty"`
 	HostIP         []string `json:"hostIP,omitempty"`
 	BackendUUID    string   `json:"backendUUID,omitempty"`
 	Nodes          []*Node  `json:"nodes,omitempty"`
@@ -40,6 +48,8 @@ type VolumePublishInfo struct {
 	SharedTarget   bool     `json:"sharedTarget,omitempty"`
 	DevicePath     string   `json:"devicePath,omitempty"`
 	Unmanaged      bool     `json:"unmanaged,omitempty"`
+	SANType        string   `json:"sanType,omitempty"`
+	LUKSEncryption string   `json:"luksEncryption,omitempty"`
 	VolumeAccessInfo
 }
 
@@ -50,10 +60,12 @@ type VolumeTrackingPublishInfo struct {
 type Node struct {
 	Name           string            `json:"name"`
 	IQN            string            `json:"iqn,omitempty"`
+	NQN            string            `json:"nqn,omitempty"`
 	IPs            []string          `json:"ips,omitempty"`
 	TopologyLabels map[string]string `json:"topologyLabels,omitempty"`
 	NodePrep       *NodePrep         `json:"nodePrep"`
 	HostInfo       *HostSystem       `json:"hostInfo,omitempty"`
+	Deleted        bool              `json:"deleted,omitempty"`
 }
 
 type NodePrep struct {
@@ -66,6 +78,13 @@ type NodePrep struct {
 
 type NodePrepStatus string
 
+// NodeEvent is an event reported by a CSI node to the controller, which records it against the node.
+type NodeEvent struct {
+	Type    string `json:"type"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
 type HostSystem struct {
 	OS SystemOS `json:"os"`
 }
@@ -0,0 +1,192 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+// CopyVolume copies the data of one volume to another on the local host, attaching both volumes as
+// described by the publish info with which they were published to the host, and detaching them again
+// when done.  If both volumes are iSCSI LUNs, the source device is copied to the target device with dd,
+// which preserves any file system or LUKS encryption.  Otherwise, both volumes are mounted in workDir
+// and the source files are copied to the target with rsync.  A copy may be repeated safely, such as
+// after it was interrupted, and volumes left mounted in workDir by an earlier attempt are unmounted.
+func CopyVolume(
+	ctx context.Context, workDir, sourceName string, source *VolumePublishInfo, targetName string,
+	target *VolumePublishInfo,
+) error {
+
+	fields := log.Fields{
+		"source":  sourceName,
+		"target":  targetName,
+		"workDir": workDir,
+	}
+	Logc(ctx).WithFields(fields).Debug(">>>> osutils.CopyVolume")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< osutils.CopyVolume")
+
+	if err := checkVolumeCopyEndpoint(sourceName, source); err != nil {
+		return err
+	}
+	if err := checkVolumeCopyEndpoint(targetName, target); err != nil {
+		return err
+	}
+
+	if isISCSIVolumeCopyEndpoint(source) && isISCSIVolumeCopyEndpoint(target) {
+		return copyISCSIVolume(ctx, sourceName, source, targetName, target)
+	}
+
+	// Volumes with no file system, or with one that is encrypted, cannot be copied file by file
+	for name, publishInfo := range map[string]*VolumePublishInfo{sourceName: source, targetName: target} {
+		if publishInfo.FilesystemType == fsRaw {
+			return UnsupportedError(fmt.Sprintf("raw block volume %s may only be copied to or from another "+
+				"iSCSI volume", name))
+		}
+		if luksEncryption, err := parseLUKSEncryption(publishInfo.LUKSEncryption); err != nil {
+			return err
+		} else if luksEncryption {
+			return UnsupportedError(fmt.Sprintf("encrypted volume %s may only be copied to or from another "+
+				"iSCSI volume", name))
+		}
+	}
+
+	if err := os.MkdirAll(workDir, 0700); err != nil {
+		return fmt.Errorf("could not create volume copy directory %s; %v", workDir, err)
+	}
+	defer os.Remove(workDir)
+
+	sourceMountpoint := filepath.Join(workDir, "source")
+	if err := attachVolumeForCopy(ctx, sourceName, sourceMountpoint, source); err != nil {
+		return err
+	}
+	defer detachVolumeAfterCopy(ctx, sourceName, sourceMountpoint, source)
+
+	targetMountpoint := filepath.Join(workDir, "target")
+	if err := attachVolumeForCopy(ctx, targetName, targetMountpoint, target); err != nil {
+		return err
+	}
+	defer detachVolumeAfterCopy(ctx, targetName, targetMountpoint, target)
+
+	// The trailing separators copy the contents of the source directory rather than the directory itself
+	out, err := execCommand(ctx, "rsync", "--archive", "--hard-links", "--numeric-ids", "--delete",
+		sourceMountpoint+"/", targetMountpoint+"/")
+	if err != nil {
+		return fmt.Errorf("could not copy files of volume %s to volume %s; %v: %s", sourceName, targetName,
+			err, string(out))
+	}
+
+	return nil
+}
+
+// checkVolumeCopyEndpoint returns an UnsupportedError if a volume cannot be attached for a copy.
+func checkVolumeCopyEndpoint(name string, publishInfo *VolumePublishInfo) error {
+	if publishInfo.FilesystemType != "nfs" && !isISCSIVolumeCopyEndpoint(publishInfo) {
+		return UnsupportedError(fmt.Sprintf("volume %s cannot be copied on a host; only NFS and iSCSI "+
+			"volumes may be copied", name))
+	}
+	return nil
+}
+
+// isISCSIVolumeCopyEndpoint returns true if a volume is published as an iSCSI LUN.
+func isISCSIVolumeCopyEndpoint(publishInfo *VolumePublishInfo) bool {
+	return publishInfo.FilesystemType != "nfs" && publishInfo.SANType != "nvme" && publishInfo.IscsiTargetIQN != ""
+}
+
+// copyISCSIVolume copies the device of one iSCSI volume to that of another with dd.
+func copyISCSIVolume(
+	ctx context.Context, sourceName string, source *VolumePublishInfo, targetName string,
+	target *VolumePublishInfo,
+) error {
+
+	// Attach the LUNs without mounting, formatting or opening them, so that their contents are copied as is
+	rawSource, rawTarget := *source, *target
+	rawSource.FilesystemType, rawTarget.FilesystemType = fsRaw, fsRaw
+	rawSource.LUKSEncryption, rawTarget.LUKSEncryption = "", ""
+
+	if err := attachVolumeForCopy(ctx, sourceName, "", &rawSource); err != nil {
+		return err
+	}
+	defer detachVolumeAfterCopy(ctx, sourceName, "", &rawSource)
+
+	if err := attachVolumeForCopy(ctx, targetName, "", &rawTarget); err != nil {
+		return err
+	}
+	defer detachVolumeAfterCopy(ctx, targetName, "", &rawTarget)
+
+	out, err := execCommand(ctx, "dd", "if="+rawSource.DevicePath, "of="+rawTarget.DevicePath, "bs=1M",
+		"conv=fsync")
+	if err != nil {
+		return fmt.Errorf("could not copy device of volume %s to volume %s; %v: %s", sourceName, targetName,
+			err, string(out))
+	}
+
+	return nil
+}
+
+// attachVolumeForCopy attaches a volume to the local host, mounting it unless no mountpoint is given.
+// A volume still mounted at the mountpoint by an earlier copy is unmounted first.
+func attachVolumeForCopy(ctx context.Context, name, mountpoint string, publishInfo *VolumePublishInfo) error {
+
+	if mountpoint != "" {
+		if mounted, _ := IsMounted(ctx, "", mountpoint); mounted {
+			if err := Umount(ctx, mountpoint); err != nil {
+				return fmt.Errorf("could not unmount volume copy mountpoint %s; %v", mountpoint, err)
+			}
+		}
+		if err := os.MkdirAll(mountpoint, 0700); err != nil {
+			return fmt.Errorf("could not create volume copy mountpoint %s; %v", mountpoint, err)
+		}
+	}
+
+	var err error
+	if publishInfo.FilesystemType == "nfs" {
+		err = AttachNFSVolume(ctx, name, mountpoint, publishInfo)
+	} else {
+		err = AttachISCSIVolume(ctx, name, mountpoint, publishInfo, "")
+	}
+	if err != nil {
+		return fmt.Errorf("could not attach volume %s for copy; %v", name, err)
+	}
+
+	return nil
+}
+
+// detachVolumeAfterCopy unmounts a volume attached for a copy, removes its mountpoint and, for an iSCSI
+// volume, removes its device from the host.  Failures are only logged, since the copy is complete and
+// the volume is unpublished from the host regardless.
+func detachVolumeAfterCopy(ctx context.Context, name, mountpoint string, publishInfo *VolumePublishInfo) {
+
+	logFields := log.Fields{"volume": name, "mountpoint": mountpoint}
+
+	if mountpoint != "" {
+		if err := Umount(ctx, mountpoint); err != nil {
+			Logc(ctx).WithFields(logFields).WithError(err).Warning("Could not unmount volume after copy.")
+			return
+		}
+		if err := os.Remove(mountpoint); err != nil {
+			Logc(ctx).WithFields(logFields).WithError(err).Warning("Could not remove volume copy mountpoint.")
+		}
+	}
+
+	if publishInfo.FilesystemType == "nfs" {
+		return
+	}
+
+	if err := PrepareDeviceForRemoval(ctx, int(publishInfo.IscsiLunNumber), publishInfo.IscsiTargetIQN,
+		false); err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Warning("Could not remove device of volume after copy.")
+		return
+	}
+	if !publishInfo.SharedTarget {
+		if err := ISCSILogout(ctx, publishInfo.IscsiTargetIQN, publishInfo.IscsiTargetPortal); err != nil {
+			Logc(ctx).WithFields(logFields).WithError(err).Warning("Could not log out of volume's iSCSI target.")
+		}
+	}
+}
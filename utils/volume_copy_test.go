@@ -0,0 +1,55 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyVolumeUnsupported(t *testing.T) {
+
+	nfs := &VolumePublishInfo{FilesystemType: "nfs"}
+	iscsi := &VolumePublishInfo{FilesystemType: "ext4"}
+	iscsi.IscsiTargetIQN = "iqn.1992-08.com.netapp:sn.1"
+	raw := &VolumePublishInfo{FilesystemType: fsRaw}
+	raw.IscsiTargetIQN = "iqn.1992-08.com.netapp:sn.1"
+	encrypted := &VolumePublishInfo{FilesystemType: "ext4", LUKSEncryption: "true"}
+	encrypted.IscsiTargetIQN = "iqn.1992-08.com.netapp:sn.1"
+	nvme := &VolumePublishInfo{FilesystemType: "ext4", SANType: "nvme"}
+
+	tests := []struct {
+		name   string
+		source *VolumePublishInfo
+		target *VolumePublishInfo
+	}{
+		{"raw to NFS", raw, nfs},
+		{"NFS to raw", nfs, raw},
+		{"encrypted to NFS", encrypted, nfs},
+		{"NVMe to iSCSI", nvme, iscsi},
+		{"iSCSI to NVMe", iscsi, nvme},
+	}
+
+	workDir, err := ioutil.TempDir("", "trident-copy-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workDir)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			copyDir := filepath.Join(workDir, "copy")
+			err := CopyVolume(context.Background(), copyDir, "source", test.source, "target", test.target)
+			assert.True(t, IsUnsupportedError(err), "expected an unsupported error, got %v", err)
+
+			// Nothing is attached when the copy is not supported
+			_, err = os.Stat(copyDir)
+			assert.True(t, os.IsNotExist(err), "volume copy directory created")
+		})
+	}
+}
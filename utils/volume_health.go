@@ -0,0 +1,102 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+const scsiDeviceStateRunning = "running"
+
+// IsStaleFileHandleError returns true if the error is ESTALE, which is returned when accessing an NFS
+// volume whose export has been deleted or replaced on the server.
+func IsStaleFileHandleError(err error) bool {
+	return errors.Is(err, syscall.ESTALE)
+}
+
+// GetISCSIPathCounts returns the number of paths to a published iSCSI LUN whose SCSI devices are running,
+// along with the number of paths expected from the portals through which the LUN was published.
+func GetISCSIPathCounts(ctx context.Context, publishInfo *VolumePublishInfo) (int, int) {
+
+	fields := log.Fields{"iSCSINodeName": publishInfo.IscsiTargetIQN, "lunID": publishInfo.IscsiLunNumber}
+	Logc(ctx).WithFields(fields).Debug(">>>> volume_health.GetISCSIPathCounts")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< volume_health.GetISCSIPathCounts")
+
+	hostSessionMap := GetISCSIHostSessionMapForTarget(ctx, publishInfo.IscsiTargetIQN)
+	paths := getSysfsBlockDirsForLUN(int(publishInfo.IscsiLunNumber), hostSessionMap)
+
+	return countRunningSCSIDevices(paths), 1 + len(publishInfo.IscsiPortals)
+}
+
+// countRunningSCSIDevices returns how many of the supplied sysfs SCSI device directories are for devices
+// in the running state.  A path whose session has failed is usually in the transport-offline state.
+func countRunningSCSIDevices(paths []string) int {
+
+	running := 0
+	for _, p := range paths {
+		if readSysfsValue(filepath.Join(p, "state")) == scsiDeviceStateRunning {
+			running++
+		}
+	}
+	return running
+}
+
+// GetNVMePathCounts returns the number of live controllers for a published NVMe subsystem, along with
+// the number of controllers expected from the target addresses to which the host was connected.
+func GetNVMePathCounts(ctx context.Context, publishInfo *VolumePublishInfo) (int, int, error) {
+
+	fields := log.Fields{"subsystemNQN": publishInfo.NVMeSubsystemNQN}
+	Logc(ctx).WithFields(fields).Debug(">>>> volume_health.GetNVMePathCounts")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< volume_health.GetNVMePathCounts")
+
+	subsystem, err := getNVMeSubsystem(chrootPathPrefix+nvmeSubsystemSysfsDir, publishInfo.NVMeSubsystemNQN)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	live := 0
+	if subsystem != nil {
+		for _, controller := range subsystem.Controllers {
+			if controller.State == nvmeControllerStateLive {
+				live++
+			}
+		}
+	}
+	return live, len(publishInfo.NVMeTargetIPs), nil
+}
+
+// IsRemountedReadOnly returns true if the file system at the mountpoint was mounted read-write but is now
+// read-only, as happens when the kernel remounts a file system after detecting errors in it.
+func IsRemountedReadOnly(ctx context.Context, mountpoint string) (bool, error) {
+
+	fields := log.Fields{"mountpoint": mountpoint}
+	Logc(ctx).WithFields(fields).Debug(">>>> volume_health.IsRemountedReadOnly")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< volume_health.IsRemountedReadOnly")
+
+	procSelfMountinfo, err := listProcSelfMountinfo(procSelfMountinfoPath)
+	if err != nil {
+		return false, err
+	}
+	return isRemountedReadOnly(procSelfMountinfo, mountpoint), nil
+}
+
+// isRemountedReadOnly checks the mount options of the topmost mount at the mountpoint, which are read-write
+// if the mount was requested read-write, against the options of its superblock, which become read-only
+// when the file system itself is remounted read-only.
+func isRemountedReadOnly(mounts []MountInfo, mountpoint string) bool {
+
+	remounted := false
+	for _, mount := range mounts {
+		if mount.MountPoint == mountpoint {
+			remounted = SliceContainsString(mount.MountOptions, "rw") && SliceContainsString(mount.SuperOptions, "ro")
+		}
+	}
+	return remounted
+}
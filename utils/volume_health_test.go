@@ -0,0 +1,85 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStaleFileHandleError(t *testing.T) {
+
+	staleErr := &os.PathError{Op: "stat", Path: "/mnt/pvc-1", Err: syscall.ESTALE}
+
+	assert.True(t, IsStaleFileHandleError(staleErr))
+	assert.True(t, IsStaleFileHandleError(fmt.Errorf("could not stat volume; %w", staleErr)))
+	assert.False(t, IsStaleFileHandleError(&os.PathError{Op: "stat", Path: "/mnt/pvc-1", Err: syscall.ENOENT}))
+	assert.False(t, IsStaleFileHandleError(nil))
+}
+
+func TestCountRunningSCSIDevices(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "scsi-device")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeSysfsFile(t, filepath.Join(dir, "3:0:0:1", "state"), "running")
+	writeSysfsFile(t, filepath.Join(dir, "4:0:0:1", "state"), "transport-offline")
+	writeSysfsFile(t, filepath.Join(dir, "5:0:0:1", "state"), "running")
+
+	tests := []struct {
+		name     string
+		paths    []string
+		expected int
+	}{
+		{"allRunning", []string{"3:0:0:1", "5:0:0:1"}, 2},
+		{"oneOffline", []string{"3:0:0:1", "4:0:0:1", "5:0:0:1"}, 2},
+		{"missingDevice", []string{"3:0:0:1", "6:0:0:1"}, 1},
+		{"none", []string{}, 0},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		paths := make([]string, 0)
+		for _, p := range test.paths {
+			paths = append(paths, filepath.Join(dir, p))
+		}
+		assert.Equal(t, test.expected, countRunningSCSIDevices(paths))
+	}
+}
+
+func TestIsRemountedReadOnly(t *testing.T) {
+
+	mounts, err := parseProcSelfMountinfo([]byte(
+		`2390 28 8:16 / /var/lib/kubelet/pods/uid1/volumes/kubernetes.io~csi/pvc-1/mount rw,relatime shared:1 - ext4 /dev/sdb ro,errors=remount-ro
+2391 28 8:32 / /var/lib/kubelet/pods/uid2/volumes/kubernetes.io~csi/pvc-2/mount rw,relatime shared:2 - ext4 /dev/sdc rw
+2392 28 8:48 / /var/lib/kubelet/pods/uid3/volumes/kubernetes.io~csi/pvc-3/mount ro,relatime shared:3 - ext4 /dev/sdd ro
+2393 28 0:52 / /var/lib/kubelet/pods/uid4/volumes/kubernetes.io~csi/pvc-4/mount rw,relatime shared:4 - nfs 10.0.0.1:/trident_pvc_4 ro,vers=4.1
+`))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		mountpoint string
+		expected   bool
+	}{
+		{"remounted", "/var/lib/kubelet/pods/uid1/volumes/kubernetes.io~csi/pvc-1/mount", true},
+		{"readWrite", "/var/lib/kubelet/pods/uid2/volumes/kubernetes.io~csi/pvc-2/mount", false},
+		{"mountedReadOnly", "/var/lib/kubelet/pods/uid3/volumes/kubernetes.io~csi/pvc-3/mount", false},
+		{"nfsRemounted", "/var/lib/kubelet/pods/uid4/volumes/kubernetes.io~csi/pvc-4/mount", true},
+		{"notMounted", "/var/lib/kubelet/pods/uid5/volumes/kubernetes.io~csi/pvc-5/mount", false},
+	}
+
+	for _, test := range tests {
+		t.Logf("Running test case '%s'", test.name)
+
+		assert.Equal(t, test.expected, isRemountedReadOnly(mounts, test.mountpoint))
+	}
+}